@@ -0,0 +1,175 @@
+package access_tokens
+
+import (
+	"cloud-app-hive/controllers/access_tokens/requests"
+	"cloud-app-hive/controllers/errors"
+	"cloud-app-hive/controllers/validators"
+	"cloud-app-hive/domain/commands"
+	"cloud-app-hive/use_cases/access_tokens"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AccessTokenController struct {
+	createAccessTokenUseCase   access_tokens.CreateAccessTokenUseCase
+	findAccessTokensUseCase    access_tokens.FindAccessTokensUseCase
+	findAccessTokenByIDUseCase access_tokens.FindAccessTokenByIDUseCase
+	revokeAccessTokenUseCase   access_tokens.RevokeAccessTokenUseCase
+}
+
+func NewAccessTokenController(
+	createAccessTokenUseCase access_tokens.CreateAccessTokenUseCase,
+	findAccessTokensUseCase access_tokens.FindAccessTokensUseCase,
+	findAccessTokenByIDUseCase access_tokens.FindAccessTokenByIDUseCase,
+	revokeAccessTokenUseCase access_tokens.RevokeAccessTokenUseCase,
+) AccessTokenController {
+	return AccessTokenController{
+		createAccessTokenUseCase:   createAccessTokenUseCase,
+		findAccessTokensUseCase:    findAccessTokensUseCase,
+		findAccessTokenByIDUseCase: findAccessTokenByIDUseCase,
+		revokeAccessTokenUseCase:   revokeAccessTokenUseCase,
+	}
+}
+
+// CreateAccessTokenController godoc
+// @Summary Mints an access token
+// @Description creates a named access token with scopes, an optional namespace restriction and an optional expiry. The secret is only returned in this response.
+// @ID create-access-token
+// @Tags AccessTokens
+// @Accept  json
+// @Produce  json
+// @Param Authorization header string true "Authorization Token"
+// @Param createAccessTokenRequest body requests.CreateAccessTokenRequest true "Create Access Token Request"
+// @Success 200 {object} domain.AccessToken
+// @Failure 400 {object} errors.ApiError
+// @Router /access-tokens [post]
+func (accessTokenController AccessTokenController) CreateAccessTokenController(c *gin.Context) {
+	userID, ok := validators.AuthenticatedUserID(c)
+	if !ok {
+		validators.Unauthorized(c)
+		return
+	}
+
+	var createAccessTokenRequest requests.CreateAccessTokenRequest
+	if err := c.ShouldBindJSON(&createAccessTokenRequest); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"validation-errors": err.Error()})
+		return
+	}
+
+	accessToken, secret, err := accessTokenController.createAccessTokenUseCase.Execute(commands.CreateAccessToken{
+		Name:        createAccessTokenRequest.Name,
+		UserID:      userID,
+		Scopes:      createAccessTokenRequest.Scopes,
+		NamespaceID: createAccessTokenRequest.NamespaceID,
+		ExpiresAt:   createAccessTokenRequest.ExpiresAt,
+	})
+	if err != nil {
+		fmt.Println("Error while creating access token: ", err)
+		if apiError, ok := errors.NewForbiddenApiError(err, c); ok {
+			c.JSON(apiError.StatusCode, apiError)
+			return
+		}
+		if _, ok := err.(*errors.InvalidAccessTokenError); ok {
+			c.JSON(http.StatusBadRequest, gin.H{"validation-errors": err.Error()})
+			return
+		}
+		if _, ok := err.(*errors.NamespaceNotFoundByIDError); ok {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"accessToken": accessToken,
+		"secret":      secret,
+	})
+}
+
+// FindAccessTokensController godoc
+// @Summary Lists the access tokens of the user
+// @Description lists the access tokens of the user with their scopes, expiry and last use, without their secrets
+// @ID find-access-tokens
+// @Tags AccessTokens
+// @Produce  json
+// @Param Authorization header string true "Authorization Token"
+// @Success 200 {array} domain.AccessToken
+// @Router /access-tokens [get]
+func (accessTokenController AccessTokenController) FindAccessTokensController(c *gin.Context) {
+	userID, ok := validators.AuthenticatedUserID(c)
+	if !ok {
+		validators.Unauthorized(c)
+		return
+	}
+
+	accessTokens, err := accessTokenController.findAccessTokensUseCase.Execute(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"accessTokens": accessTokens})
+}
+
+// FindAccessTokenByIDController godoc
+// @Summary Returns an access token of the user
+// @Description returns an access token with its last use, without its secret
+// @ID find-access-token-by-id
+// @Tags AccessTokens
+// @Produce  json
+// @Param Authorization header string true "Authorization Token"
+// @Param id path string true "Access token ID"
+// @Success 200 {object} domain.AccessToken
+// @Router /access-tokens/{id} [get]
+func (accessTokenController AccessTokenController) FindAccessTokenByIDController(c *gin.Context) {
+	userID, ok := validators.AuthenticatedUserID(c)
+	if !ok {
+		validators.Unauthorized(c)
+		return
+	}
+
+	accessToken, err := accessTokenController.findAccessTokenByIDUseCase.Execute(c.Param("id"), userID)
+	if err != nil {
+		if _, ok := err.(*errors.AccessTokenNotFoundByIDError); ok {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"accessToken": accessToken})
+}
+
+// RevokeAccessTokenController godoc
+// @Summary Revokes an access token
+// @Description revokes an access token, requests using it are rejected right away
+// @ID revoke-access-token
+// @Tags AccessTokens
+// @Produce  json
+// @Param Authorization header string true "Authorization Token"
+// @Param id path string true "Access token ID"
+// @Success 200 {object} domain.AccessToken
+// @Router /access-tokens/{id} [delete]
+func (accessTokenController AccessTokenController) RevokeAccessTokenController(c *gin.Context) {
+	userID, ok := validators.AuthenticatedUserID(c)
+	if !ok {
+		validators.Unauthorized(c)
+		return
+	}
+
+	accessToken, err := accessTokenController.revokeAccessTokenUseCase.Execute(c.Param("id"), userID)
+	if err != nil {
+		if _, ok := err.(*errors.AccessTokenNotFoundByIDError); ok {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"accessToken": accessToken})
+}
@@ -0,0 +1,30 @@
+package access_tokens
+
+import (
+	"cloud-app-hive/controllers/validators"
+	"cloud-app-hive/use_cases/access_tokens"
+
+	"github.com/gin-gonic/gin"
+)
+
+func InitAccessTokensRoutes(
+	router *gin.RouterGroup,
+	createAccessTokenUseCase access_tokens.CreateAccessTokenUseCase,
+	findAccessTokensUseCase access_tokens.FindAccessTokensUseCase,
+	findAccessTokenByIDUseCase access_tokens.FindAccessTokenByIDUseCase,
+	revokeAccessTokenUseCase access_tokens.RevokeAccessTokenUseCase,
+) {
+	accessTokenController := NewAccessTokenController(
+		createAccessTokenUseCase,
+		findAccessTokensUseCase,
+		findAccessTokenByIDUseCase,
+		revokeAccessTokenUseCase,
+	)
+
+	// Access tokens are managed by signed in users only, a token cannot mint or revoke tokens
+	accessTokens := router.Group("/access-tokens", validators.RequireUserSession)
+	accessTokens.POST("", accessTokenController.CreateAccessTokenController)
+	accessTokens.GET("", accessTokenController.FindAccessTokensController)
+	accessTokens.GET("/:id", accessTokenController.FindAccessTokenByIDController)
+	accessTokens.DELETE("/:id", accessTokenController.RevokeAccessTokenController)
+}
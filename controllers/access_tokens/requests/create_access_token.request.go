@@ -0,0 +1,15 @@
+package requests
+
+import (
+	"time"
+
+	"cloud-app-hive/domain"
+)
+
+// CreateAccessTokenRequest is a struct that represents the request body for minting an access token
+type CreateAccessTokenRequest struct {
+	Name        string                   `json:"name" binding:"required,min=3,max=100"`
+	Scopes      domain.AccessTokenScopes `json:"scopes" binding:"required,min=1,dive,oneof=namespaces:read namespaces:admin applications:read applications:deploy applications:delete"`
+	NamespaceID *string                  `json:"namespaceId" binding:"omitempty,min=1"`
+	ExpiresAt   *time.Time               `json:"expiresAt" binding:""`
+}
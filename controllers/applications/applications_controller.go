@@ -14,25 +14,33 @@ import (
 	"cloud-app-hive/controllers/errors"
 	controllerValidators "cloud-app-hive/controllers/validators"
 	"cloud-app-hive/domain/commands"
-	"cloud-app-hive/repositories"
 	"cloud-app-hive/use_cases/applications"
+	"cloud-app-hive/use_cases/operations"
 
 	"github.com/gin-gonic/gin"
 )
 
 type ApplicationController struct {
-	findApplicationsUseCase       applications.FindApplicationsUseCase
-	findApplicationByIDUseCase    applications.FindApplicationByIDUseCase
-	createApplicationUseCase      applications.CreateApplicationUseCase
-	updateApplicationUseCase      applications.UpdateApplicationUseCase
-	deleteApplicationUseCase      applications.DeleteApplicationUseCase
-	deployApplicationUseCase      applications.DeployApplicationUseCase
-	undeployApplicationUseCase    applications.UndeployApplicationUseCase
-	getApplicationLogsUseCase     applications.GetApplicationLogsUseCase
-	getApplicationMetricsUseCase  applications.GetApplicationMetricsUseCase
-	getApplicationStatusUseCase   applications.GetApplicationStatusUseCase
-	fillApplicationsStatusUseCase applications.FillApplicationStatusUseCase
-	getClusterMetricsUseCase      use_cases.GetClusterMetricsUseCase
+	findApplicationsUseCase           applications.FindApplicationsUseCase
+	findApplicationByIDUseCase        applications.FindApplicationByIDUseCase
+	createApplicationUseCase          applications.CreateApplicationUseCase
+	updateApplicationUseCase          applications.UpdateApplicationUseCase
+	deleteApplicationUseCase          applications.DeleteApplicationUseCase
+	deployApplicationUseCase          applications.DeployApplicationUseCase
+	undeployApplicationUseCase        applications.UndeployApplicationUseCase
+	getApplicationLogsUseCase         applications.GetApplicationLogsUseCase
+	getApplicationMetricsUseCase      applications.GetApplicationMetricsUseCase
+	getApplicationStatusUseCase       applications.GetApplicationStatusUseCase
+	fillApplicationsStatusUseCase     applications.FillApplicationStatusUseCase
+	getClusterMetricsUseCase          use_cases.GetClusterMetricsUseCase
+	dryRunApplicationUseCase          applications.DryRunApplicationUseCase
+	scaleApplicationUseCase           applications.ScaleApplicationUseCase
+	setApplicationSecretUseCase       applications.SetApplicationSecretUseCase
+	deleteApplicationSecretUseCase    applications.DeleteApplicationSecretUseCase
+	findApplicationImageScanUseCase   applications.FindApplicationImageScanUseCase
+	setApplicationConfigGroupsUseCase applications.SetApplicationConfigGroupsUseCase
+	startApplicationOperationUseCase  operations.StartApplicationOperationUseCase
+	getApplicationEventsUseCase       applications.GetApplicationEventsUseCase
 }
 
 func NewApplicationController(
@@ -48,20 +56,36 @@ func NewApplicationController(
 	getApplicationStatusUseCase applications.GetApplicationStatusUseCase,
 	fillApplicationsStatusUseCase applications.FillApplicationStatusUseCase,
 	getClusterMetricsUseCase use_cases.GetClusterMetricsUseCase,
+	dryRunApplicationUseCase applications.DryRunApplicationUseCase,
+	scaleApplicationUseCase applications.ScaleApplicationUseCase,
+	setApplicationSecretUseCase applications.SetApplicationSecretUseCase,
+	deleteApplicationSecretUseCase applications.DeleteApplicationSecretUseCase,
+	findApplicationImageScanUseCase applications.FindApplicationImageScanUseCase,
+	setApplicationConfigGroupsUseCase applications.SetApplicationConfigGroupsUseCase,
+	startApplicationOperationUseCase operations.StartApplicationOperationUseCase,
+	getApplicationEventsUseCase applications.GetApplicationEventsUseCase,
 ) ApplicationController {
 	return ApplicationController{
-		findApplicationsUseCase:       findApplicationsUseCase,
-		findApplicationByIDUseCase:    findApplicationByIDUseCase,
-		createApplicationUseCase:      createApplicationUseCase,
-		updateApplicationUseCase:      updateApplicationUseCase,
-		deleteApplicationUseCase:      deleteApplicationUseCase,
-		deployApplicationUseCase:      deployApplicationUseCase,
-		undeployApplicationUseCase:    undeployApplicationUseCase,
-		getApplicationLogsUseCase:     getApplicationLogsUseCase,
-		getApplicationMetricsUseCase:  getApplicationMetricsUseCase,
-		getApplicationStatusUseCase:   getApplicationStatusUseCase,
-		fillApplicationsStatusUseCase: fillApplicationsStatusUseCase,
-		getClusterMetricsUseCase:      getClusterMetricsUseCase,
+		findApplicationsUseCase:           findApplicationsUseCase,
+		findApplicationByIDUseCase:        findApplicationByIDUseCase,
+		createApplicationUseCase:          createApplicationUseCase,
+		updateApplicationUseCase:          updateApplicationUseCase,
+		deleteApplicationUseCase:          deleteApplicationUseCase,
+		deployApplicationUseCase:          deployApplicationUseCase,
+		undeployApplicationUseCase:        undeployApplicationUseCase,
+		getApplicationLogsUseCase:         getApplicationLogsUseCase,
+		getApplicationMetricsUseCase:      getApplicationMetricsUseCase,
+		getApplicationStatusUseCase:       getApplicationStatusUseCase,
+		fillApplicationsStatusUseCase:     fillApplicationsStatusUseCase,
+		getClusterMetricsUseCase:          getClusterMetricsUseCase,
+		dryRunApplicationUseCase:          dryRunApplicationUseCase,
+		scaleApplicationUseCase:           scaleApplicationUseCase,
+		setApplicationSecretUseCase:       setApplicationSecretUseCase,
+		deleteApplicationSecretUseCase:    deleteApplicationSecretUseCase,
+		findApplicationImageScanUseCase:   findApplicationImageScanUseCase,
+		setApplicationConfigGroupsUseCase: setApplicationConfigGroupsUseCase,
+		startApplicationOperationUseCase:  startApplicationOperationUseCase,
+		getApplicationEventsUseCase:       getApplicationEventsUseCase,
 	}
 }
 
@@ -74,11 +98,13 @@ func NewApplicationController(
 // @Produce  json
 // @Param Authorization header string true "Authorization Token"
 // @Param createApplicationRequest body requests.CreateApplicationRequest true "Create Application Request"
-// @Success 200 {object} responses.CreateApplicationResponse
+// @Success 202 {object} responses.CreateApplicationResponse
 // @Failure 400 {object} errors.ApiError
+// @Failure 502 {object} errors.ApiError
 // @Router /applications [post]
 func (applicationController ApplicationController) CreateAndDeployApplicationController(c *gin.Context) {
-	if !controllerValidators.ValidateAuthorizationToken(c) {
+	userID, ok := controllerValidators.AuthenticatedUserID(c)
+	if !ok {
 		controllerValidators.Unauthorized(c)
 		return
 	}
@@ -119,24 +145,151 @@ func (applicationController ApplicationController) CreateAndDeployApplicationCon
 		return
 	}
 
+	if !controllerValidators.AccessTokenAllowsNamespace(c, createApplicationRequest.NamespaceID) {
+		return
+	}
+
+	// A dry run does not deploy anything, so it does not need the cluster to have room left
+	dryRun := c.Query("dryRun") == "true"
+	if !dryRun && !applicationController.clusterCanAcceptApplications(c) {
+		return
+	}
+
+	createApplication := commands.CreateApplication{
+		Name:                      createApplicationRequest.Name,
+		Description:               createApplicationRequest.Description,
+		Image:                     createApplicationRequest.Image,
+		Registry:                  createApplicationRequest.Registry,
+		RegistryCredentialID:      createApplicationRequest.RegistryCredentialID,
+		PinImageDigest:            createApplicationRequest.PinImageDigest,
+		NamespaceID:               createApplicationRequest.NamespaceID,
+		UserID:                    userID,
+		Port:                      createApplicationRequest.Port,
+		Zone:                      createApplicationRequest.Zone,
+		ApplicationType:           createApplicationRequest.ApplicationType,
+		EnvironmentVariables:      createApplicationRequest.EnvironmentVariables,
+		Secrets:                   createApplicationRequest.Secrets,
+		ContainerSpecifications:   createApplicationRequest.ContainerSpecifications,
+		ScalabilitySpecifications: createApplicationRequest.ScalabilitySpecifications,
+		Ingress:                   createApplicationRequest.Ingress,
+		Dependencies:              domain.NewApplicationDependencies(createApplicationRequest.Dependencies),
+		ConfigFiles:               createApplicationRequest.ConfigFiles,
+		AdministratorEmail:        createApplicationRequest.AdministratorEmail,
+		DryRun:                    dryRun,
+		Actor:                     controllerValidators.AuditActor(c),
+	}
+
+	application, namespace, err := applicationController.createApplicationUseCase.Execute(createApplication)
+	if err != nil {
+		fmt.Println("Error while creating application: ", err)
+		if apiError, ok := errors.NewForbiddenApiError(err, c); ok {
+			c.JSON(apiError.StatusCode, apiError)
+			return
+		}
+		switch err.(type) {
+		case *errors.InvalidApplicationSecretsError, *errors.InvalidApplicationIngressError, *errors.InvalidApplicationDependenciesError, *errors.InvalidApplicationConfigFilesError:
+			c.JSON(http.StatusBadRequest, gin.H{"validation-errors": err.Error()})
+			return
+		}
+		if isRegistryCredentialError(err) {
+			c.JSON(http.StatusBadRequest, gin.H{"validation-errors": err.Error()})
+			return
+		}
+		if apiError, ok := errors.NewImageApiError(err, c); ok {
+			c.JSON(apiError.StatusCode, apiError)
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if dryRun {
+		applicationController.dryRunApplication(c, *application, commands.NewApplyApplication(*application, namespace.Name))
+		return
+	}
+
+	operation, ok := applicationController.startDeployOperation(c, domain.DeployOperation, *application, namespace.Name, userID)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusAccepted, responses.CreateApplicationResponse{
+		Message:     fmt.Sprintf("App %s is being deployed", application.Name),
+		Application: *application,
+		Operation:   operation,
+		Warnings:    applicationController.imageScanWarnings(*namespace, *application),
+	})
+}
+
+// startDeployOperation deploys the stored application in the background, it answers with an error when the operation cannot be recorded
+func (applicationController ApplicationController) startDeployOperation(c *gin.Context, operationType domain.OperationType, application domain.Application, namespaceName string, userID string) (*domain.Operation, bool) {
+	actor := controllerValidators.AuditActor(c)
+	return applicationController.startOperation(c, commands.StartApplicationOperation{
+		Type:          operationType,
+		Application:   application,
+		NamespaceName: namespaceName,
+		UserID:        userID,
+		Run: func(reporter domain.OperationStepReporter) error {
+			return applicationController.deployApplicationUseCase.Execute(commands.DeployApplication{
+				Application:   application,
+				NamespaceName: namespaceName,
+				Actor:         actor,
+				StepReporter:  reporter,
+			})
+		},
+	})
+}
+
+// startOperation records the operation and runs it in the background, it answers with an error when the operation cannot be recorded
+func (applicationController ApplicationController) startOperation(c *gin.Context, startApplicationOperation commands.StartApplicationOperation) (*domain.Operation, bool) {
+	operation, err := applicationController.startApplicationOperationUseCase.Execute(startApplicationOperation)
+	if err != nil {
+		fmt.Println("Error while starting operation: ", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	return operation, true
+}
+
+// restrictAccessTokenToApplicationNamespace rejects access tokens restricted to another namespace than the one of the application
+func (applicationController ApplicationController) restrictAccessTokenToApplicationNamespace(c *gin.Context) {
+	if _, ok := controllerValidators.AuthenticatedAccessToken(c); !ok {
+		c.Next()
+		return
+	}
+	userID, _ := controllerValidators.AuthenticatedUserID(c)
+	application, err := applicationController.findApplicationByIDUseCase.Execute(commands.FindApplicationByID{
+		ApplicationID: c.Param("id"),
+		QueryByUserID: userID,
+	})
+	// Missing applications and permission errors are reported by the controller
+	if err == nil && !controllerValidators.AccessTokenAllowsNamespace(c, application.NamespaceID) {
+		return
+	}
+	c.Next()
+}
+
+// clusterCanAcceptApplications checks that the cluster is not exceeding its limits before deploying a new application.
+// It writes the error response itself and returns false when the application must not be deployed.
+func (applicationController ApplicationController) clusterCanAcceptApplications(c *gin.Context) bool {
 	// Check cluster state before creating application
 	clusterState, err := applicationController.getClusterMetricsUseCase.Execute()
 	if err != nil {
 		fmt.Println("Error while getting cluster state: ", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
+		return false
 	}
 	if clusterState == nil {
 		fmt.Println("Cluster state is nil")
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Cluster state is nil"})
-		return
+		return false
 	}
 
 	stopDeployingApplicationWhenClusterNodesUsageIsAbovePercentageStr := os.Getenv("STOP_DEPLOYING_APPLICATION_WHEN_CLUSTER_NODES_USAGE_IS_ABOVE_PERCENTAGE")
 	if stopDeployingApplicationWhenClusterNodesUsageIsAbovePercentageStr == "" {
 		fmt.Println("STOP_DEPLOYING_APPLICATION_WHEN_CLUSTER_NODES_USAGE_IS_ABOVE_PERCENTAGE is not set")
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "STOP_DEPLOYING_APPLICATION_WHEN_CLUSTER_NODES_USAGE_IS_ABOVE_PERCENTAGE is not set"})
-		return
+		return false
 	}
 	stopDeployingApplicationWhenPercentageOfNodesExceededUsageStr := os.Getenv("STOP_DEPLOYING_APPLICATION_WHEN_PERCENTAGE_OF_NODES_EXCEEDED_USAGE")
 	if stopDeployingApplicationWhenPercentageOfNodesExceededUsageStr == "" {
@@ -148,8 +301,7 @@ func (applicationController ApplicationController) CreateAndDeployApplicationCon
 	if err != nil {
 		fmt.Println("Error when convert STOP_DEPLOYING_APPLICATION_WHEN_CLUSTER_NODES_USAGE_IS_ABOVE_PERCENTAGE to float64")
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error when convert STOP_DEPLOYING_APPLICATION_WHEN_CLUSTER_NODES_USAGE_IS_ABOVE_PERCENTAGE to float64"})
-		return
-
+		return false
 	}
 	stopDeployingApplicationWhenPercentageOfNodesExceededUsage, err := strconv.ParseFloat(stopDeployingApplicationWhenPercentageOfNodesExceededUsageStr, 64)
 	if err != nil {
@@ -165,58 +317,25 @@ func (applicationController ApplicationController) CreateAndDeployApplicationCon
 	) {
 		fmt.Println("Cluster is exceeding its limits")
 		c.JSON(http.StatusInsufficientStorage, gin.H{"error": "Cluster is exceeding its limits"})
-		return
+		return false
 	}
 
-	createApplication := commands.CreateApplication{
-		Name:                      createApplicationRequest.Name,
-		Description:               createApplicationRequest.Description,
-		Image:                     createApplicationRequest.Image,
-		Registry:                  createApplicationRequest.Registry,
-		NamespaceID:               createApplicationRequest.NamespaceID,
-		UserID:                    createApplicationRequest.UserID,
-		Port:                      createApplicationRequest.Port,
-		Zone:                      createApplicationRequest.Zone,
-		ApplicationType:           createApplicationRequest.ApplicationType,
-		EnvironmentVariables:      createApplicationRequest.EnvironmentVariables,
-		Secrets:                   createApplicationRequest.Secrets,
-		ContainerSpecifications:   createApplicationRequest.ContainerSpecifications,
-		ScalabilitySpecifications: createApplicationRequest.ScalabilitySpecifications,
-		AdministratorEmail:        createApplicationRequest.AdministratorEmail,
-	}
-
-	application, namespace, err := applicationController.createApplicationUseCase.Execute(createApplication)
-	if err != nil {
-		fmt.Println("Error while creating application: ", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	}
+	return true
+}
 
-	deployApplicationUseCase := applications.DeployApplicationUseCase{
-		ContainerManagerRepository: repositories.KubernetesContainerManagerRepository{},
-	}
-	applyApplication := commands.ApplyApplication{
-		Name:                      application.Name,
-		Image:                     application.Image,
-		Registry:                  application.Registry,
-		Namespace:                 namespace.Name,
-		Port:                      application.Port,
-		ApplicationType:           application.ApplicationType,
-		EnvironmentVariables:      *application.EnvironmentVariables,
-		Secrets:                   *application.Secrets,
-		ContainerSpecifications:   application.ContainerSpecifications.Data(),
-		ScalabilitySpecifications: application.ScalabilitySpecifications.Data(),
-	}
-	err = deployApplicationUseCase.Execute(applyApplication)
+// dryRunApplication renders the Kubernetes objects of an application deployment and writes them with their diff against the live objects
+func (applicationController ApplicationController) dryRunApplication(c *gin.Context, application domain.Application, applyApplication commands.ApplyApplication) {
+	objects, err := applicationController.dryRunApplicationUseCase.Execute(applyApplication)
 	if err != nil {
-		fmt.Println("Error while deploying application: ", err)
+		fmt.Println("Error while dry running application: ", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, responses.CreateApplicationResponse{
-		Message:     fmt.Sprintf("App %s deployed", application.Name),
-		Application: *application,
+	c.JSON(http.StatusOK, responses.DryRunApplicationResponse{
+		DryRun:      true,
+		Application: application,
+		Objects:     objects,
 	})
 }
 
@@ -232,7 +351,8 @@ func (applicationController ApplicationController) CreateAndDeployApplicationCon
 // @Failure 400 {object} errorsApiError
 // @Router /applications [get]
 func (applicationController ApplicationController) FindApplicationsController(c *gin.Context) {
-	if !controllerValidators.ValidateAuthorizationToken(c) {
+	userID, ok := controllerValidators.AuthenticatedUserID(c)
+	if !ok {
 		controllerValidators.Unauthorized(c)
 		return
 	}
@@ -243,12 +363,21 @@ func (applicationController ApplicationController) FindApplicationsController(c
 		return
 	}
 
+	// An access token restricted to a namespace only lists the applications of this namespace
+	if accessToken, ok := controllerValidators.AuthenticatedAccessToken(c); ok && accessToken.NamespaceID != nil {
+		if findApplicationsRequest.NamespaceID != nil && !controllerValidators.AccessTokenAllowsNamespace(c, *findApplicationsRequest.NamespaceID) {
+			return
+		}
+		findApplicationsRequest.NamespaceID = accessToken.NamespaceID
+	}
+
 	findApplicationsCommand := commands.FindApplications{
 		Name:            findApplicationsRequest.Name,
 		Image:           findApplicationsRequest.Image,
 		NamespaceID:     findApplicationsRequest.NamespaceID,
 		ApplicationType: findApplicationsRequest.ApplicationType,
 		IsAutoScaled:    findApplicationsRequest.IsAutoScaled,
+		MemberUserID:    &userID,
 		Page:            findApplicationsRequest.Page,
 		Limit:           findApplicationsRequest.Limit,
 	}
@@ -262,7 +391,8 @@ func (applicationController ApplicationController) FindApplicationsController(c
 }
 
 func (applicationController ApplicationController) FindApplicationByIDController(c *gin.Context) {
-	if !controllerValidators.ValidateAuthorizationToken(c) {
+	userID, ok := controllerValidators.AuthenticatedUserID(c)
+	if !ok {
 		controllerValidators.Unauthorized(c)
 		return
 	}
@@ -274,21 +404,14 @@ func (applicationController ApplicationController) FindApplicationByIDController
 		return
 	}
 
-	queryByUserID := c.Query("userId")
-	if queryByUserID == "" {
-		fmt.Println("userId query param is required")
-		c.JSON(http.StatusBadRequest, gin.H{"validation-errors": "userId query param is required"})
-		return
-	}
-
 	foundApplication, err := applicationController.findApplicationByIDUseCase.Execute(commands.FindApplicationByID{
 		ApplicationID: applicationID,
-		QueryByUserID: queryByUserID,
+		QueryByUserID: userID,
 	})
 	if err != nil {
 		fmt.Println("Error while finding application by ID: ", err)
-		if _, ok := err.(*errors.UnauthorizedToAccessNamespaceError); ok {
-			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		if apiError, ok := errors.NewForbiddenApiError(err, c); ok {
+			c.JSON(apiError.StatusCode, apiError)
 			return
 		}
 		fmt.Println(err)
@@ -307,8 +430,48 @@ func (applicationController ApplicationController) FindApplicationByIDController
 	c.JSON(http.StatusOK, gin.H{"application": foundApplication})
 }
 
+// GetImageScanController godoc
+// @Summary Lists the vulnerabilities of the image of an application
+// @Description lists the vulnerabilities found in the image deployed by an application, and the ones its namespace does not allow
+// @ID get-application-image-scan
+// @Tags Applications
+// @Produce  json
+// @Param Authorization header string true "Authorization Token"
+// @Param id path string true "Application ID"
+// @Success 200 {object} domain.ImageScanReport
+// @Failure 403 {object} errors.ApiError
+// @Router /applications/{id}/vulnerabilities [get]
+func (applicationController ApplicationController) GetImageScanController(c *gin.Context) {
+	userID, ok := controllerValidators.AuthenticatedUserID(c)
+	if !ok {
+		controllerValidators.Unauthorized(c)
+		return
+	}
+
+	imageScanReport, err := applicationController.findApplicationImageScanUseCase.Execute(commands.FindApplicationByID{
+		ApplicationID: c.Param("id"),
+		QueryByUserID: userID,
+	})
+	if err != nil {
+		fmt.Println("Error while finding the image scan of the application: ", err)
+		if apiError, ok := errors.NewForbiddenApiError(err, c); ok {
+			c.JSON(apiError.StatusCode, apiError)
+			return
+		}
+		if _, ok := err.(*errors.ApplicationNotFoundByIDError); ok {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"imageScan": imageScanReport})
+}
+
 func (applicationController ApplicationController) UpdateApplicationByNameAndNamespaceController(c *gin.Context) {
-	if !controllerValidators.ValidateAuthorizationToken(c) {
+	userID, ok := controllerValidators.AuthenticatedUserID(c)
+	if !ok {
 		controllerValidators.Unauthorized(c)
 		return
 	}
@@ -320,10 +483,9 @@ func (applicationController ApplicationController) UpdateApplicationByNameAndNam
 	}
 
 	applicationID := c.Param("id")
-	userID := c.Query("userId")
-	if applicationID == "" || userID == "" {
-		fmt.Println("Application ID url param and userId query param are required")
-		c.JSON(http.StatusBadRequest, gin.H{"validation-errors": "Application ID url param and userId query param are required"})
+	if applicationID == "" {
+		fmt.Println("Application ID url param is required")
+		c.JSON(http.StatusBadRequest, gin.H{"validation-errors": "Application ID url param is required"})
 		return
 	}
 
@@ -344,23 +506,44 @@ func (applicationController ApplicationController) UpdateApplicationByNameAndNam
 		})
 		return
 	}
+	dryRun := c.Query("dryRun") == "true"
 	updateApplication := commands.UpdateApplication{
 		UserID:                    userID,
 		Description:               updateApplicationRequest.Description,
 		Image:                     updateApplicationRequest.Image,
 		Registry:                  updateApplicationRequest.Registry,
+		RegistryCredentialID:      updateApplicationRequest.RegistryCredentialID,
+		PinImageDigest:            updateApplicationRequest.PinImageDigest,
 		Port:                      updateApplicationRequest.Port,
 		ApplicationType:           updateApplicationRequest.ApplicationType,
 		EnvironmentVariables:      updateApplicationRequest.EnvironmentVariables,
 		Secrets:                   updateApplicationRequest.Secrets,
 		ContainerSpecifications:   updateApplicationRequest.ContainerSpecifications,
 		ScalabilitySpecifications: updateApplicationRequest.ScalabilitySpecifications,
+		Ingress:                   updateApplicationRequest.Ingress,
+		Dependencies:              domain.NewApplicationDependencies(updateApplicationRequest.Dependencies),
+		ConfigFiles:               updateApplicationRequest.ConfigFiles,
 		AdministratorEmail:        updateApplicationRequest.AdministratorEmail,
+		DryRun:                    dryRun,
+		Actor:                     controllerValidators.AuditActor(c),
 	}
 	application, namespace, err := applicationController.updateApplicationUseCase.Execute(applicationID, updateApplication, userID)
 	if err != nil {
-		if _, ok := err.(*errors.UnauthorizedToAccessNamespaceError); ok {
-			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		if apiError, ok := errors.NewForbiddenApiError(err, c); ok {
+			c.JSON(apiError.StatusCode, apiError)
+			return
+		}
+		switch err.(type) {
+		case *errors.InvalidApplicationSecretsError, *errors.InvalidApplicationIngressError, *errors.InvalidApplicationDependenciesError, *errors.InvalidApplicationConfigFilesError:
+			c.JSON(http.StatusBadRequest, gin.H{"validation-errors": err.Error()})
+			return
+		}
+		if isRegistryCredentialError(err) {
+			c.JSON(http.StatusBadRequest, gin.H{"validation-errors": err.Error()})
+			return
+		}
+		if apiError, ok := errors.NewImageApiError(err, c); ok {
+			c.JSON(apiError.StatusCode, apiError)
 			return
 		}
 		fmt.Println("Error while updating application: ", err)
@@ -372,54 +555,48 @@ func (applicationController ApplicationController) UpdateApplicationByNameAndNam
 		return
 	}
 
-	applyApplication := commands.ApplyApplication{
-		Name:                      application.Name,
-		Image:                     application.Image,
-		Registry:                  application.Registry,
-		Namespace:                 namespace.Name,
-		Port:                      application.Port,
-		ApplicationType:           application.ApplicationType,
-		EnvironmentVariables:      *application.EnvironmentVariables,
-		Secrets:                   *application.Secrets,
-		ContainerSpecifications:   application.ContainerSpecifications.Data(),
-		ScalabilitySpecifications: application.ScalabilitySpecifications.Data(),
+	if dryRun {
+		applicationController.dryRunApplication(c, *application, commands.NewApplyApplication(*application, namespace.Name))
+		return
 	}
-	err = applicationController.deployApplicationUseCase.Execute(applyApplication)
-	if err != nil {
-		fmt.Println("Error while deploying application: ", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+
+	operation, ok := applicationController.startDeployOperation(c, domain.UpdateOperation, *application, namespace.Name, userID)
+	if !ok {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"message":     fmt.Sprintf("App %s deployed", application.Name),
+	c.JSON(http.StatusAccepted, gin.H{
+		"message":     fmt.Sprintf("App %s is being deployed", application.Name),
 		"application": application,
+		"operation":   operation,
+		"warnings":    applicationController.imageScanWarnings(*namespace, *application),
 	})
 }
 
 // DeleteApplicationByIDController deletes an application by name and namespace in query params
 func (applicationController ApplicationController) DeleteApplicationByIDController(c *gin.Context) {
-	if !controllerValidators.ValidateAuthorizationToken(c) {
+	userID, ok := controllerValidators.AuthenticatedUserID(c)
+	if !ok {
 		controllerValidators.Unauthorized(c)
 		return
 	}
 
 	applicationID := c.Param("id")
-	userID := c.Query("userId")
-	if applicationID == "" || userID == "" {
-		fmt.Println("Application ID url param and 'userId' query param must be provided")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Application ID url param and 'userId' query param must be provided"})
+	if applicationID == "" {
+		fmt.Println("Application ID url param must be provided")
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Application ID url param must be provided"})
 		return
 	}
 
 	foundApplication, err := applicationController.findApplicationByIDUseCase.Execute(commands.FindApplicationByID{
 		ApplicationID: applicationID,
 		QueryByUserID: userID,
+		Permission:    domain.PermissionDeleteApplication,
 	})
 	if err != nil {
 		fmt.Println("Error while finding application by ID: ", err)
-		if _, ok := err.(*errors.UnauthorizedToAccessNamespaceError); ok {
-			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		if apiError, ok := errors.NewForbiddenApiError(err, c); ok {
+			c.JSON(apiError.StatusCode, apiError)
 			return
 		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
@@ -429,34 +606,48 @@ func (applicationController ApplicationController) DeleteApplicationByIDControll
 	deleteApplication := commands.DeleteApplication{
 		ID:     applicationID,
 		UserID: userID,
+		Actor:  controllerValidators.AuditActor(c),
 	}
 	deletedApplication, err := applicationController.deleteApplicationUseCase.Execute(deleteApplication)
 	if err != nil {
 		fmt.Println("Error while deleting application: ", err)
+		if apiError, ok := errors.NewForbiddenApiError(err, c); ok {
+			c.JSON(apiError.StatusCode, apiError)
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	unapplyApplication := commands.UnapplyApplication{
-		Name:      foundApplication.Name,
-		Namespace: foundApplication.Namespace.Name,
-	}
-	err = applicationController.undeployApplicationUseCase.Execute(unapplyApplication)
-	if err != nil {
-		fmt.Println("Error while undeploying application: ", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	actor := controllerValidators.AuditActor(c)
+	operation, ok := applicationController.startOperation(c, commands.StartApplicationOperation{
+		Type:          domain.DeleteOperation,
+		Application:   *foundApplication,
+		NamespaceName: foundApplication.Namespace.Name,
+		UserID:        userID,
+		Run: func(reporter domain.OperationStepReporter) error {
+			return applicationController.undeployApplicationUseCase.Execute(commands.UndeployApplication{
+				Application:  *foundApplication,
+				Actor:        actor,
+				StepReporter: reporter,
+			})
+		},
+	})
+	if !ok {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"message":     fmt.Sprintf("App %s deleted in namespace %s", deletedApplication.Name, deletedApplication.Namespace.Name),
+	c.JSON(http.StatusAccepted, gin.H{
+		"message":     fmt.Sprintf("App %s deleted in namespace %s, it is being removed from the cluster", deletedApplication.Name, deletedApplication.Namespace.Name),
 		"application": deletedApplication,
+		"operation":   operation,
 	})
 }
 
 // GetMetricsByApplicationNameAndNamespaceController returns the metrics of an application by name and namespace in query params
 func (applicationController ApplicationController) GetMetricsByApplicationNameAndNamespaceController(c *gin.Context) {
-	if !controllerValidators.ValidateAuthorizationToken(c) {
+	userID, ok := controllerValidators.AuthenticatedUserID(c)
+	if !ok {
 		controllerValidators.Unauthorized(c)
 		return
 	}
@@ -466,19 +657,15 @@ func (applicationController ApplicationController) GetMetricsByApplicationNameAn
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Application ID url param must be provided"})
 		return
 	}
-	queryBy := c.Query("userId")
-	if queryBy == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "userId query param must be provided"})
-		return
-	}
 
 	application, err := applicationController.findApplicationByIDUseCase.Execute(commands.FindApplicationByID{
 		ApplicationID: applicationID,
-		QueryByUserID: queryBy,
+		QueryByUserID: userID,
+		Permission:    domain.PermissionReadApplicationMetrics,
 	})
 	if err != nil {
-		if _, ok := err.(*errors.UnauthorizedToAccessNamespaceError); ok {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		if apiError, ok := errors.NewForbiddenApiError(err, c); ok {
+			c.JSON(apiError.StatusCode, apiError)
 			return
 		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
@@ -505,7 +692,8 @@ func (applicationController ApplicationController) GetMetricsByApplicationNameAn
 
 // GetLogsByApplicationNameAndNamespaceController returns the logs of an application by name and namespace in query params
 func (applicationController ApplicationController) GetLogsByApplicationNameAndNamespaceController(c *gin.Context) {
-	if !controllerValidators.ValidateAuthorizationToken(c) {
+	userID, ok := controllerValidators.AuthenticatedUserID(c)
+	if !ok {
 		controllerValidators.Unauthorized(c)
 		return
 	}
@@ -515,19 +703,15 @@ func (applicationController ApplicationController) GetLogsByApplicationNameAndNa
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Application ID url param must be provided"})
 		return
 	}
-	queryBy := c.Query("userId")
-	if queryBy == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "userId query param must be provided"})
-		return
-	}
 
 	application, err := applicationController.findApplicationByIDUseCase.Execute(commands.FindApplicationByID{
 		ApplicationID: applicationID,
-		QueryByUserID: queryBy,
+		QueryByUserID: userID,
+		Permission:    domain.PermissionReadApplicationLogs,
 	})
 	if err != nil {
-		if _, ok := err.(*errors.UnauthorizedToAccessNamespaceError); ok {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		if apiError, ok := errors.NewForbiddenApiError(err, c); ok {
+			c.JSON(apiError.StatusCode, apiError)
 			return
 		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
@@ -559,7 +743,8 @@ func (applicationController ApplicationController) GetLogsByApplicationNameAndNa
 
 // GetStatusByApplicationNameAndNamespaceController returns the status of an application by name and namespace in query params
 func (applicationController ApplicationController) GetStatusByApplicationNameAndNamespaceController(c *gin.Context) {
-	if !controllerValidators.ValidateAuthorizationToken(c) {
+	userID, ok := controllerValidators.AuthenticatedUserID(c)
+	if !ok {
 		controllerValidators.Unauthorized(c)
 		return
 	}
@@ -569,19 +754,15 @@ func (applicationController ApplicationController) GetStatusByApplicationNameAnd
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Application ID url param must be provided"})
 		return
 	}
-	queryBy := c.Query("userId")
-	if queryBy == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "userId query param must be provided"})
-		return
-	}
 
 	application, err := applicationController.findApplicationByIDUseCase.Execute(commands.FindApplicationByID{
 		ApplicationID: applicationID,
-		QueryByUserID: queryBy,
+		QueryByUserID: userID,
+		Permission:    domain.PermissionReadApplicationStatus,
 	})
 	if err != nil {
-		if _, ok := err.(*errors.UnauthorizedToAccessNamespaceError); ok {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		if apiError, ok := errors.NewForbiddenApiError(err, c); ok {
+			c.JSON(apiError.StatusCode, apiError)
 			return
 		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
@@ -609,3 +790,380 @@ func (applicationController ApplicationController) GetStatusByApplicationNameAnd
 		"status": status,
 	})
 }
+
+// GetEventsByApplicationIDController godoc
+// @Summary Lists the Kubernetes events of an application
+// @Description lists the events of the Deployment, ReplicaSets, Pods, Service and Ingress of an application, e.g. FailedScheduling, BackOff, Unhealthy or OOMKilled, de-duplicated and from the oldest to the most recent
+// @ID get-application-events
+// @Tags Applications
+// @Produce  json
+// @Param Authorization header string true "Authorization Token"
+// @Param id path string true "Application ID"
+// @Success 200 {array} domain.ApplicationEvent
+// @Failure 403 {object} errors.ApiError
+// @Failure 404 {object} errors.ApiError
+// @Router /applications/{id}/events [get]
+func (applicationController ApplicationController) GetEventsByApplicationIDController(c *gin.Context) {
+	getApplicationEvents, ok := applicationController.applicationEventsCommand(c)
+	if !ok {
+		return
+	}
+
+	applicationEvents, err := applicationController.getApplicationEventsUseCase.Execute(getApplicationEvents)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"events": applicationEvents,
+	})
+}
+
+// StreamEventsByApplicationIDController godoc
+// @Summary Streams the Kubernetes events of an application
+// @Description sends the current events of the application as server-sent events named "events", then the events that happen or repeat until the client disconnects
+// @ID stream-application-events
+// @Tags Applications
+// @Produce  text/event-stream
+// @Param Authorization header string true "Authorization Token"
+// @Param id path string true "Application ID"
+// @Success 200 {array} domain.ApplicationEvent
+// @Failure 403 {object} errors.ApiError
+// @Failure 404 {object} errors.ApiError
+// @Failure 429 {object} errors.ApiError
+// @Router /applications/{id}/events/stream [get]
+func (applicationController ApplicationController) StreamEventsByApplicationIDController(c *gin.Context) {
+	getApplicationEvents, ok := applicationController.applicationEventsCommand(c)
+	if !ok {
+		return
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	// The ingress controller would otherwise buffer the events until the stream ends
+	c.Header("X-Accel-Buffering", "no")
+	err := applicationController.getApplicationEventsUseCase.Stream(c.Request.Context(), getApplicationEvents, func(applicationEvents []domain.ApplicationEvent) error {
+		c.SSEvent("events", applicationEvents)
+		c.Writer.Flush()
+		return nil
+	})
+	if err == nil {
+		return
+	}
+	// Nothing was streamed yet when the stream is refused or the first read of the events fails
+	if !c.Writer.Written() {
+		c.Writer.Header().Del("Content-Type")
+		if _, ok := err.(*errors.TooManyEventStreamsError); ok {
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.SSEvent("error", gin.H{"error": err.Error()})
+	c.Writer.Flush()
+}
+
+// applicationEventsCommand finds the application of the request and returns the command reading its events,
+// the response is written when it returns false
+func (applicationController ApplicationController) applicationEventsCommand(c *gin.Context) (commands.GetApplicationEvents, bool) {
+	userID, ok := controllerValidators.AuthenticatedUserID(c)
+	if !ok {
+		controllerValidators.Unauthorized(c)
+		return commands.GetApplicationEvents{}, false
+	}
+
+	application, err := applicationController.findApplicationByIDUseCase.Execute(commands.FindApplicationByID{
+		ApplicationID: c.Param("id"),
+		QueryByUserID: userID,
+		Permission:    domain.PermissionReadApplicationStatus,
+	})
+	if err != nil {
+		if apiError, ok := errors.NewForbiddenApiError(err, c); ok {
+			c.JSON(apiError.StatusCode, apiError)
+			return commands.GetApplicationEvents{}, false
+		}
+		if _, ok := err.(*errors.ApplicationNotFoundByIDError); ok {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return commands.GetApplicationEvents{}, false
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return commands.GetApplicationEvents{}, false
+	}
+
+	return commands.GetApplicationEvents{
+		Name:      application.Name,
+		Namespace: application.Namespace.Name,
+		UserID:    userID,
+	}, true
+}
+
+// ScaleApplicationController godoc
+// @Summary Scales an application
+// @Description scales an application horizontally or vertically, or renders the scaled objects when dryRun=true
+// @ID scale-application
+// @Tags Applications
+// @Accept  json
+// @Produce  json
+// @Param Authorization header string true "Authorization Token"
+// @Param id path string true "Application ID"
+// @Param dryRun query bool false "Render the Kubernetes objects without scaling"
+// @Param scaleApplicationRequest body requests.ScaleApplicationRequest true "Scale Application Request"
+// @Success 200 {object} responses.DryRunApplicationResponse
+// @Success 202 {object} domain.Operation
+// @Failure 400 {object} errors.ApiError
+// @Router /applications/{id}/scale [post]
+func (applicationController ApplicationController) ScaleApplicationController(c *gin.Context) {
+	userID, ok := controllerValidators.AuthenticatedUserID(c)
+	if !ok {
+		controllerValidators.Unauthorized(c)
+		return
+	}
+
+	applicationID := c.Param("id")
+	if applicationID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Application ID url param must be provided"})
+		return
+	}
+
+	var scaleApplicationRequest requests.ScaleApplicationRequest
+	if err := c.ShouldBindJSON(&scaleApplicationRequest); err != nil {
+		fmt.Println("Error while binding json when scaling application: ", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"validation-errors": fmt.Errorf("error while binding json: %w", err).Error(),
+		})
+		return
+	}
+
+	application, err := applicationController.findApplicationByIDUseCase.Execute(commands.FindApplicationByID{
+		ApplicationID: applicationID,
+		QueryByUserID: userID,
+		Permission:    domain.PermissionScaleApplication,
+	})
+	if err != nil {
+		if apiError, ok := errors.NewForbiddenApiError(err, c); ok {
+			c.JSON(apiError.StatusCode, apiError)
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	// The scaling is checked before answering, since it then runs in the background
+	scaledApplication, err := applicationController.scaleApplicationUseCase.DryRun(application.ID, scaleApplicationRequest.ScalingType)
+	if err != nil {
+		fmt.Println("Error while dry running application scaling: ", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if c.Query("dryRun") == "true" {
+		applicationController.dryRunApplication(c, *scaledApplication, commands.NewApplyApplication(*scaledApplication, application.Namespace.Name))
+		return
+	}
+
+	actor := controllerValidators.AuditActor(c)
+	operation, ok := applicationController.startOperation(c, commands.StartApplicationOperation{
+		Type:          domain.ScaleOperation,
+		Application:   *application,
+		NamespaceName: application.Namespace.Name,
+		UserID:        userID,
+		Run: func(reporter domain.OperationStepReporter) error {
+			_, err := applicationController.scaleApplicationUseCase.Execute(application.ID, actor, scaleApplicationRequest.ScalingType, reporter)
+			return err
+		},
+	})
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"message":     fmt.Sprintf("App %s is being scaled", application.Name),
+		"application": scaledApplication,
+		"operation":   operation,
+	})
+}
+
+// SetApplicationSecretController godoc
+// @Summary Adds or rotates a secret of an application
+// @Description sets the value of a single secret and applies the application, the value is never returned
+// @ID set-application-secret
+// @Tags Applications
+// @Accept  json
+// @Produce  json
+// @Param Authorization header string true "Authorization Token"
+// @Param id path string true "Application ID"
+// @Param name path string true "Secret name"
+// @Param restart query bool false "Roll the pods out so that they read the new value"
+// @Param setApplicationSecretRequest body requests.SetApplicationSecretRequest true "Set Application Secret Request"
+// @Success 200 {object} domain.ApplicationSecret
+// @Failure 400 {object} errors.ApiError
+// @Router /applications/{id}/secrets/{name} [put]
+func (applicationController ApplicationController) SetApplicationSecretController(c *gin.Context) {
+	userID, ok := controllerValidators.AuthenticatedUserID(c)
+	if !ok {
+		controllerValidators.Unauthorized(c)
+		return
+	}
+
+	var setApplicationSecretRequest requests.SetApplicationSecretRequest
+	if err := c.ShouldBindJSON(&setApplicationSecretRequest); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"validation-errors": fmt.Errorf("error while binding json: %w", err).Error(),
+		})
+		return
+	}
+
+	secret, err := applicationController.setApplicationSecretUseCase.Execute(commands.SetApplicationSecret{
+		ApplicationID: c.Param("id"),
+		Name:          c.Param("name"),
+		Value:         setApplicationSecretRequest.Value,
+		Reference:     setApplicationSecretRequest.Reference,
+		Restart:       c.Query("restart") == "true",
+		UserID:        userID,
+		Actor:         controllerValidators.AuditActor(c),
+	})
+	if err != nil {
+		fmt.Println("Error while setting application secret: ", err)
+		applicationSecretError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": fmt.Sprintf("Secret %s set to version %d", secret.Name, secret.Version),
+		"secret":  secret,
+	})
+}
+
+// DeleteApplicationSecretController godoc
+// @Summary Deletes a secret of an application
+// @Description removes a single secret and applies the application
+// @ID delete-application-secret
+// @Tags Applications
+// @Produce  json
+// @Param Authorization header string true "Authorization Token"
+// @Param id path string true "Application ID"
+// @Param name path string true "Secret name"
+// @Success 200 {object} domain.ApplicationSecret
+// @Failure 404 {object} errors.ApiError
+// @Router /applications/{id}/secrets/{name} [delete]
+func (applicationController ApplicationController) DeleteApplicationSecretController(c *gin.Context) {
+	userID, ok := controllerValidators.AuthenticatedUserID(c)
+	if !ok {
+		controllerValidators.Unauthorized(c)
+		return
+	}
+
+	secret, err := applicationController.deleteApplicationSecretUseCase.Execute(commands.DeleteApplicationSecret{
+		ApplicationID: c.Param("id"),
+		Name:          c.Param("name"),
+		UserID:        userID,
+		Actor:         controllerValidators.AuditActor(c),
+	})
+	if err != nil {
+		fmt.Println("Error while deleting application secret: ", err)
+		applicationSecretError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": fmt.Sprintf("Secret %s deleted", secret.Name),
+		"secret":  secret,
+	})
+}
+
+// SetApplicationConfigGroupsController godoc
+// @Summary Attaches an application to config groups of its namespace
+// @Description replaces the config groups of the application, a group takes precedence over the groups before it and the application's own environment variables and secrets take precedence over every group. The application is deployed again if it is deployed.
+// @ID set-application-config-groups
+// @Tags Applications
+// @Accept  json
+// @Produce  json
+// @Param Authorization header string true "Authorization Token"
+// @Param id path string true "Application ID"
+// @Param setApplicationConfigGroupsRequest body requests.SetApplicationConfigGroupsRequest true "Names of the config groups, in their order of precedence"
+// @Success 200 {array} domain.ConfigGroupAttachment
+// @Failure 400 {object} errors.ApiError
+// @Failure 404 {object} errors.ApiError
+// @Router /applications/{id}/config-groups [put]
+func (applicationController ApplicationController) SetApplicationConfigGroupsController(c *gin.Context) {
+	userID, ok := controllerValidators.AuthenticatedUserID(c)
+	if !ok {
+		controllerValidators.Unauthorized(c)
+		return
+	}
+
+	var setApplicationConfigGroupsRequest requests.SetApplicationConfigGroupsRequest
+	if err := c.ShouldBindJSON(&setApplicationConfigGroupsRequest); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"validation-errors": fmt.Errorf("error while binding json: %w", err).Error(),
+		})
+		return
+	}
+
+	attachments, err := applicationController.setApplicationConfigGroupsUseCase.Execute(commands.SetApplicationConfigGroups{
+		ApplicationID:    c.Param("id"),
+		ConfigGroupNames: setApplicationConfigGroupsRequest.ConfigGroups,
+		UserID:           userID,
+		Actor:            controllerValidators.AuditActor(c),
+	})
+	if err != nil {
+		fmt.Println("Error while setting application config groups: ", err)
+		if apiError, ok := errors.NewForbiddenApiError(err, c); ok {
+			c.JSON(apiError.StatusCode, apiError)
+			return
+		}
+		switch err.(type) {
+		case *errors.InvalidConfigGroupError:
+			c.JSON(http.StatusBadRequest, gin.H{"validation-errors": err.Error()})
+		case *errors.ApplicationNotFoundByIDError, *errors.ConfigGroupNotFoundError:
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"configGroupAttachments": attachments,
+	})
+}
+
+// applicationSecretError writes the response of an error returned while setting or deleting a secret
+func applicationSecretError(c *gin.Context, err error) {
+	if apiError, ok := errors.NewForbiddenApiError(err, c); ok {
+		c.JSON(apiError.StatusCode, apiError)
+		return
+	}
+	switch err.(type) {
+	case *errors.InvalidApplicationSecretsError:
+		c.JSON(http.StatusBadRequest, gin.H{"validation-errors": err.Error()})
+	case *errors.ApplicationNotFoundByIDError, *errors.ApplicationSecretNotFoundError:
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// imageScanWarnings returns the vulnerabilities of the deployed image that the namespace warns about
+func (applicationController ApplicationController) imageScanWarnings(namespace domain.Namespace, application domain.Application) []string {
+	imageScanReport, err := applicationController.findApplicationImageScanUseCase.Report(namespace, application)
+	if err != nil {
+		fmt.Println("Error while finding the image scan of the application: ", err)
+		return nil
+	}
+	if warning := imageScanReport.Warning(); warning != "" {
+		return []string{warning}
+	}
+	return nil
+}
+
+// isRegistryCredentialError returns true if the registry credential of the request cannot be used by the application
+func isRegistryCredentialError(err error) bool {
+	switch err.(type) {
+	case *errors.InvalidRegistryCredentialError, *errors.RegistryCredentialNotFoundError:
+		return true
+	}
+	return false
+}
@@ -1,8 +1,11 @@
 package applications
 
 import (
+	"cloud-app-hive/controllers/validators"
+	"cloud-app-hive/domain"
 	"cloud-app-hive/use_cases"
 	"cloud-app-hive/use_cases/applications"
+	"cloud-app-hive/use_cases/operations"
 
 	"github.com/gin-gonic/gin"
 )
@@ -21,6 +24,14 @@ func InitApplicationsRoutes(
 	getApplicationStatusUseCase applications.GetApplicationStatusUseCase,
 	fillApplicationStatusUseCase applications.FillApplicationStatusUseCase,
 	getClusterMetricsUseCase use_cases.GetClusterMetricsUseCase,
+	dryRunApplicationUseCase applications.DryRunApplicationUseCase,
+	scaleApplicationUseCase applications.ScaleApplicationUseCase,
+	setApplicationSecretUseCase applications.SetApplicationSecretUseCase,
+	deleteApplicationSecretUseCase applications.DeleteApplicationSecretUseCase,
+	findApplicationImageScanUseCase applications.FindApplicationImageScanUseCase,
+	setApplicationConfigGroupsUseCase applications.SetApplicationConfigGroupsUseCase,
+	startApplicationOperationUseCase operations.StartApplicationOperationUseCase,
+	getApplicationEventsUseCase applications.GetApplicationEventsUseCase,
 ) {
 	applicationController := NewApplicationController(
 		findApplicationsUseCase,
@@ -35,13 +46,33 @@ func InitApplicationsRoutes(
 		getApplicationStatusUseCase,
 		fillApplicationStatusUseCase,
 		getClusterMetricsUseCase,
+		dryRunApplicationUseCase,
+		scaleApplicationUseCase,
+		setApplicationSecretUseCase,
+		deleteApplicationSecretUseCase,
+		findApplicationImageScanUseCase,
+		setApplicationConfigGroupsUseCase,
+		startApplicationOperationUseCase,
+		getApplicationEventsUseCase,
 	)
-	router.GET("/applications", applicationController.FindApplicationsController)
-	router.POST("/applications", applicationController.CreateAndDeployApplicationController)
-	router.GET("/applications/:id", applicationController.FindApplicationByIDController)
-	router.PUT("/applications/:id", applicationController.UpdateApplicationByNameAndNamespaceController)
-	router.GET("/applications/:id/metrics", applicationController.GetMetricsByApplicationNameAndNamespaceController)
-	router.GET("/applications/:id/logs", applicationController.GetLogsByApplicationNameAndNamespaceController)
-	router.GET("/applications/:id/status", applicationController.GetStatusByApplicationNameAndNamespaceController)
-	router.DELETE("/applications/:id", applicationController.DeleteApplicationByIDController)
+	readScope := validators.RequireScope(domain.ScopeApplicationsRead)
+	deployScope := validators.RequireScope(domain.ScopeApplicationsDeploy)
+	deleteScope := validators.RequireScope(domain.ScopeApplicationsDelete)
+	applicationNamespace := applicationController.restrictAccessTokenToApplicationNamespace
+
+	router.GET("/applications", readScope, applicationController.FindApplicationsController)
+	router.POST("/applications", deployScope, applicationController.CreateAndDeployApplicationController)
+	router.GET("/applications/:id", readScope, applicationNamespace, applicationController.FindApplicationByIDController)
+	router.PUT("/applications/:id", deployScope, applicationNamespace, applicationController.UpdateApplicationByNameAndNamespaceController)
+	router.POST("/applications/:id/scale", deployScope, applicationNamespace, applicationController.ScaleApplicationController)
+	router.GET("/applications/:id/metrics", readScope, applicationNamespace, applicationController.GetMetricsByApplicationNameAndNamespaceController)
+	router.GET("/applications/:id/logs", readScope, applicationNamespace, applicationController.GetLogsByApplicationNameAndNamespaceController)
+	router.GET("/applications/:id/status", readScope, applicationNamespace, applicationController.GetStatusByApplicationNameAndNamespaceController)
+	router.GET("/applications/:id/events", readScope, applicationNamespace, applicationController.GetEventsByApplicationIDController)
+	router.GET("/applications/:id/events/stream", readScope, applicationNamespace, applicationController.StreamEventsByApplicationIDController)
+	router.GET("/applications/:id/vulnerabilities", readScope, applicationNamespace, applicationController.GetImageScanController)
+	router.PUT("/applications/:id/secrets/:name", deployScope, applicationNamespace, applicationController.SetApplicationSecretController)
+	router.DELETE("/applications/:id/secrets/:name", deployScope, applicationNamespace, applicationController.DeleteApplicationSecretController)
+	router.PUT("/applications/:id/config-groups", deployScope, applicationNamespace, applicationController.SetApplicationConfigGroupsController)
+	router.DELETE("/applications/:id", deleteScope, applicationNamespace, applicationController.DeleteApplicationByIDController)
 }
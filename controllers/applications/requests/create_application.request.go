@@ -13,9 +13,10 @@ type CreateApplicationRequest struct {
 	Name                      string                                      `json:"name" binding:"required,min=3,max=50" validate:"IsACustomStringForSubdomainValidation"`
 	Description               string                                      `json:"description" binding:"omitempty,min=3,max=50"`
 	Image                     string                                      `json:"image" binding:"required"`
-	Registry                  domain.ImageRegistry                        `json:"registry" binding:"required,oneof=dockerhub pcr"`
+	Registry                  domain.ImageRegistry                        `json:"registry" binding:"required,oneof=dockerhub pcr custom"`
+	RegistryCredentialID      *string                                     `json:"registryCredentialId"` // Required by the custom registry
+	PinImageDigest            bool                                        `json:"pinImageDigest"`       // Runs the verified digest of the image rather than its tag
 	NamespaceID               string                                      `json:"namespaceId" binding:"required"`
-	UserID                    string                                      `json:"userId" binding:"required"`
 	Port                      uint32                                      `json:"port" binding:"required,min=1,max=65535"`
 	Zone                      string                                      `json:"zone"`
 	ApplicationType           domain.ApplicationType                      `json:"applicationType" binding:"oneof=SINGLE_INSTANCE LOAD_BALANCED" validate:"required"`
@@ -23,6 +24,9 @@ type CreateApplicationRequest struct {
 	Secrets                   domain.ApplicationSecrets                   `json:"secrets"`
 	ContainerSpecifications   domain.ApplicationContainerSpecifications   `json:"containerSpecifications" binding:"required"`
 	ScalabilitySpecifications domain.ApplicationScalabilitySpecifications `json:"scalabilitySpecifications" binding:"required"`
+	Ingress                   domain.ApplicationIngress                   `json:"ingress"`      // Routes / of the default host to the port when empty
+	Dependencies              []string                                    `json:"dependencies"` // Names of the applications of the namespace it calls, described by HIVE_SVC_<NAME>_* environment variables
+	ConfigFiles               domain.ApplicationConfigFiles               `json:"configFiles"`  // Files mounted read-only in the container, given as text or base64
 	AdministratorEmail        string                                      `json:"administratorEmail" binding:"required,email"`
 }
 
@@ -49,5 +53,25 @@ func ValidateCreateApplicationRequest(createApplicationRequest CreateApplication
 		return err
 	}
 
+	err = createApplicationRequest.Ingress.Validate()
+	if err != nil {
+		return err
+	}
+
+	err = createApplicationRequest.Ingress.ValidatePorts(createApplicationRequest.Port)
+	if err != nil {
+		return err
+	}
+
+	err = domain.NewApplicationDependencies(createApplicationRequest.Dependencies).Validate(createApplicationRequest.Name)
+	if err != nil {
+		return err
+	}
+
+	err = createApplicationRequest.ConfigFiles.Validate()
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
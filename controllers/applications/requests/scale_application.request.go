@@ -0,0 +1,9 @@
+package requests
+
+import "cloud-app-hive/use_cases/applications"
+
+// ScaleApplicationRequest is a struct that represents the request body for scaling an application
+// swagger:model ScaleApplicationRequest
+type ScaleApplicationRequest struct {
+	ScalingType applications.ScalingType `json:"scalingType" binding:"required,oneof=HorizontalUpScaling HorizontalDownScaling VerticalUpScaling"`
+}
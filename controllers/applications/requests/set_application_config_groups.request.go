@@ -0,0 +1,8 @@
+package requests
+
+// SetApplicationConfigGroupsRequest is a struct that represents the request body for attaching an application to config groups of its namespace.
+// A group takes precedence over the groups before it, the environment variables and secrets of the application take precedence over every group.
+// swagger:model SetApplicationConfigGroupsRequest
+type SetApplicationConfigGroupsRequest struct {
+	ConfigGroups []string `json:"configGroups" binding:"required"`
+}
@@ -0,0 +1,9 @@
+package requests
+
+// SetApplicationSecretRequest is a struct that represents the request body for adding or rotating a secret,
+// given either a value or a reference to a secret provider formatted as "provider://path#key"
+// swagger:model SetApplicationSecretRequest
+type SetApplicationSecretRequest struct {
+	Value     string `json:"value" binding:"required_without=Reference"`
+	Reference string `json:"reference"`
+}
@@ -11,13 +11,18 @@ import (
 type UpdateApplicationRequest struct {
 	Description               string                                      `json:"description"`
 	Image                     string                                      `json:"image" binding:"required"`
-	Registry                  domain.ImageRegistry                        `json:"registry" binding:"required,oneof=dockerhub pcr"`
+	Registry                  domain.ImageRegistry                        `json:"registry" binding:"required,oneof=dockerhub pcr custom"`
+	RegistryCredentialID      *string                                     `json:"registryCredentialId"` // Required by the custom registry
+	PinImageDigest            bool                                        `json:"pinImageDigest"`       // Runs the verified digest of the image rather than its tag
 	Port                      uint32                                      `json:"port" binding:"required,min=1,max=65535"`
 	ApplicationType           domain.ApplicationType                      `json:"applicationType" binding:"oneof=SINGLE_INSTANCE LOAD_BALANCED"`
 	EnvironmentVariables      domain.ApplicationEnvironmentVariables      `json:"environmentVariables"`
 	Secrets                   domain.ApplicationSecrets                   `json:"secrets"`
 	ContainerSpecifications   domain.ApplicationContainerSpecifications   `json:"containerSpecifications"`
 	ScalabilitySpecifications domain.ApplicationScalabilitySpecifications `json:"scalabilitySpecifications"`
+	Ingress                   domain.ApplicationIngress                   `json:"ingress"`      // Replaces the routes and policies, a basic auth without password keeps the stored one
+	Dependencies              []string                                    `json:"dependencies"` // Replaces the names of the applications of the namespace it calls
+	ConfigFiles               *domain.ApplicationConfigFiles              `json:"configFiles"`  // Replaces the config files when given, a secret file without content keeps the stored one
 	AdministratorEmail        string                                      `json:"administratorEmail" binding:"required,email"`
 }
 
@@ -53,5 +58,28 @@ func ValidateUpdateApplicationRequest(updateApplicationRequest UpdateApplication
 		return err
 	}
 
+	err = updateApplicationRequest.Ingress.Validate()
+	if err != nil {
+		return err
+	}
+
+	err = updateApplicationRequest.Ingress.ValidatePorts(updateApplicationRequest.Port)
+	if err != nil {
+		return err
+	}
+
+	// The name of the application is not part of the request, the use case checks that it does not depend on itself
+	err = domain.NewApplicationDependencies(updateApplicationRequest.Dependencies).Validate("")
+	if err != nil {
+		return err
+	}
+
+	if updateApplicationRequest.ConfigFiles != nil {
+		err = updateApplicationRequest.ConfigFiles.Validate()
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
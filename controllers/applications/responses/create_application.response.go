@@ -7,6 +7,10 @@ import "cloud-app-hive/domain"
 type CreateApplicationResponse struct {
 	Message     string             `json:"message"`
 	Application domain.Application `json:"application"`
+	// Operation tracks the deployment of the application, which runs in the background
+	Operation *domain.Operation `json:"operation,omitempty"`
+	// Warnings are the vulnerabilities of the image that the namespace allows to deploy but warns about
+	Warnings []string `json:"warnings,omitempty"`
 }
 
 // ApplicationResponse is a struct that represents the response body for creating an application
@@ -0,0 +1,11 @@
+package responses
+
+import "cloud-app-hive/domain"
+
+// DryRunApplicationResponse is a struct that represents the response body of a create, update or scale in dry run mode
+// swagger:response DryRunApplicationResponse
+type DryRunApplicationResponse struct {
+	DryRun      bool                            `json:"dryRun"`
+	Application domain.Application              `json:"application"`
+	Objects     []domain.KubernetesObjectDryRun `json:"objects"`
+}
@@ -22,7 +22,7 @@ func NewClusterController(
 }
 
 func (clusterController ClusterController) GetClusterMetricsController(c *gin.Context) {
-	if !controllerValidators.ValidateAuthorizationToken(c) {
+	if _, ok := controllerValidators.AuthenticatedUserID(c); !ok {
 		controllerValidators.Unauthorized(c)
 		return
 	}
@@ -1,6 +1,8 @@
 package cluster
 
 import (
+	"cloud-app-hive/controllers/validators"
+	"cloud-app-hive/domain"
 	"cloud-app-hive/use_cases"
 
 	"github.com/gin-gonic/gin"
@@ -13,5 +15,5 @@ func InitClusterRoutes(
 	clusterController := NewClusterController(
 		getClusterMetricsUseCase,
 	)
-	router.GET("/cluster/metrics", clusterController.GetClusterMetricsController)
+	router.GET("/cluster/metrics", validators.RequireScope(domain.ScopeApplicationsRead), clusterController.GetClusterMetricsController)
 }
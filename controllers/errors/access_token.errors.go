@@ -0,0 +1,35 @@
+package errors
+
+import "fmt"
+
+type AccessTokenNotFoundByIDError struct {
+	AccessTokenID string
+}
+
+func (e *AccessTokenNotFoundByIDError) Error() string {
+	return fmt.Sprintf("access token with id %s not found", e.AccessTokenID)
+}
+
+func NewAccessTokenNotFoundByIDError(
+	accessTokenID string,
+) *AccessTokenNotFoundByIDError {
+	return &AccessTokenNotFoundByIDError{
+		AccessTokenID: accessTokenID,
+	}
+}
+
+type InvalidAccessTokenError struct {
+	Message string
+}
+
+func (e *InvalidAccessTokenError) Error() string {
+	return fmt.Sprintf("invalid access token: %s", e.Message)
+}
+
+func NewInvalidAccessTokenError(
+	message string,
+) *InvalidAccessTokenError {
+	return &InvalidAccessTokenError{
+		Message: message,
+	}
+}
@@ -0,0 +1,73 @@
+package errors
+
+import "fmt"
+
+type AddonNotFoundError struct {
+	AddonID string
+}
+
+func (e *AddonNotFoundError) Error() string {
+	return fmt.Sprintf("addon with id %s not found", e.AddonID)
+}
+
+func NewAddonNotFoundError(
+	addonID string,
+) *AddonNotFoundError {
+	return &AddonNotFoundError{
+		AddonID: addonID,
+	}
+}
+
+type InvalidAddonError struct {
+	Message string
+}
+
+func (e *InvalidAddonError) Error() string {
+	return fmt.Sprintf("invalid addon: %s", e.Message)
+}
+
+func NewInvalidAddonError(
+	message string,
+) *InvalidAddonError {
+	return &InvalidAddonError{
+		Message: message,
+	}
+}
+
+type AddonInUseError struct {
+	AddonID          string
+	ApplicationNames []string
+}
+
+func (e *AddonInUseError) Error() string {
+	return fmt.Sprintf("addon with id %s is bound to the applications %v", e.AddonID, e.ApplicationNames)
+}
+
+func NewAddonInUseError(
+	addonID string,
+	applicationNames []string,
+) *AddonInUseError {
+	return &AddonInUseError{
+		AddonID:          addonID,
+		ApplicationNames: applicationNames,
+	}
+}
+
+type AddonBindingNotFoundError struct {
+	AddonID       string
+	ApplicationID string
+}
+
+func (e *AddonBindingNotFoundError) Error() string {
+	return fmt.Sprintf("application with id %s is not bound to addon with id %s", e.ApplicationID, e.AddonID)
+}
+
+func NewAddonBindingNotFoundError(
+	addonID string,
+	applicationID string,
+) *AddonBindingNotFoundError {
+	return &AddonBindingNotFoundError{
+		AddonID:       addonID,
+		ApplicationID: applicationID,
+	}
+}
@@ -2,6 +2,7 @@ package errors
 
 import (
 	"fmt"
+	"net/http"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -23,7 +24,7 @@ func NewApiError(statusCode int, name string, message string, description string
 		"path":        c.Request.URL.Path,
 		"body":        c.Request.Body,
 		"params":      c.Params,
-		"headers":     c.Request.Header,
+		"headers":     redactedHeaders(c.Request.Header),
 		"query":       c.Request.URL.Query(),
 		"contentType": c.ContentType(),
 		"clientIP":    c.ClientIP(),
@@ -43,6 +44,15 @@ func NewApiError(statusCode int, name string, message string, description string
 	return err
 }
 
+// redactedHeaders hides the bearer token, the error context is returned to the client and printed in the logs
+func redactedHeaders(headers http.Header) http.Header {
+	redacted := headers.Clone()
+	if redacted.Get("Authorization") != "" {
+		redacted.Set("Authorization", "[REDACTED]")
+	}
+	return redacted
+}
+
 func (e ApiError) String() string {
 	return fmt.Sprintf("%s - %d - %s - %s - %s - %v", e.Date, e.StatusCode, e.Name, e.Message, e.Description, e.Context)
 }
@@ -0,0 +1,17 @@
+package errors
+
+type InvalidApplicationConfigFilesError struct {
+	Message string
+}
+
+func (e *InvalidApplicationConfigFilesError) Error() string {
+	return e.Message
+}
+
+func NewInvalidApplicationConfigFilesError(
+	message string,
+) *InvalidApplicationConfigFilesError {
+	return &InvalidApplicationConfigFilesError{
+		Message: message,
+	}
+}
@@ -0,0 +1,17 @@
+package errors
+
+type InvalidApplicationDependenciesError struct {
+	Message string
+}
+
+func (e *InvalidApplicationDependenciesError) Error() string {
+	return e.Message
+}
+
+func NewInvalidApplicationDependenciesError(
+	message string,
+) *InvalidApplicationDependenciesError {
+	return &InvalidApplicationDependenciesError{
+		Message: message,
+	}
+}
@@ -0,0 +1,19 @@
+package errors
+
+import "fmt"
+
+type TooManyEventStreamsError struct {
+	MaxStreams int
+}
+
+func (e *TooManyEventStreamsError) Error() string {
+	return fmt.Sprintf("no more than %d event streams can be opened at the same time", e.MaxStreams)
+}
+
+func NewTooManyEventStreamsError(
+	maxStreams int,
+) *TooManyEventStreamsError {
+	return &TooManyEventStreamsError{
+		MaxStreams: maxStreams,
+	}
+}
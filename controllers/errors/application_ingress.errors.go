@@ -0,0 +1,17 @@
+package errors
+
+type InvalidApplicationIngressError struct {
+	Message string
+}
+
+func (e *InvalidApplicationIngressError) Error() string {
+	return e.Message
+}
+
+func NewInvalidApplicationIngressError(
+	message string,
+) *InvalidApplicationIngressError {
+	return &InvalidApplicationIngressError{
+		Message: message,
+	}
+}
@@ -0,0 +1,22 @@
+package errors
+
+import "fmt"
+
+type ApplicationSecretNotFoundError struct {
+	ApplicationID string
+	SecretName    string
+}
+
+func (e *ApplicationSecretNotFoundError) Error() string {
+	return fmt.Sprintf("secret %s not found in application with id %s", e.SecretName, e.ApplicationID)
+}
+
+func NewApplicationSecretNotFoundError(
+	applicationID string,
+	secretName string,
+) *ApplicationSecretNotFoundError {
+	return &ApplicationSecretNotFoundError{
+		ApplicationID: applicationID,
+		SecretName:    secretName,
+	}
+}
@@ -0,0 +1,54 @@
+package errors
+
+import "fmt"
+
+type ConfigGroupNotFoundError struct {
+	ConfigGroup string
+}
+
+func (e *ConfigGroupNotFoundError) Error() string {
+	return fmt.Sprintf("config group %s not found", e.ConfigGroup)
+}
+
+func NewConfigGroupNotFoundError(
+	configGroup string,
+) *ConfigGroupNotFoundError {
+	return &ConfigGroupNotFoundError{
+		ConfigGroup: configGroup,
+	}
+}
+
+type InvalidConfigGroupError struct {
+	Message string
+}
+
+func (e *InvalidConfigGroupError) Error() string {
+	return fmt.Sprintf("invalid config group: %s", e.Message)
+}
+
+func NewInvalidConfigGroupError(
+	message string,
+) *InvalidConfigGroupError {
+	return &InvalidConfigGroupError{
+		Message: message,
+	}
+}
+
+type ConfigGroupInUseError struct {
+	ConfigGroupID    string
+	ApplicationNames []string
+}
+
+func (e *ConfigGroupInUseError) Error() string {
+	return fmt.Sprintf("config group with id %s is attached to the applications %v", e.ConfigGroupID, e.ApplicationNames)
+}
+
+func NewConfigGroupInUseError(
+	configGroupID string,
+	applicationNames []string,
+) *ConfigGroupInUseError {
+	return &ConfigGroupInUseError{
+		ConfigGroupID:    configGroupID,
+		ApplicationNames: applicationNames,
+	}
+}
@@ -0,0 +1,55 @@
+package errors
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// NewForbiddenApiError returns the 403 ApiError of a namespace authorization error.
+// It returns false if err is not an authorization error, so that the caller can handle it.
+func NewForbiddenApiError(err error, c *gin.Context) (ApiError, bool) {
+	switch authorizationError := err.(type) {
+	case *NamespacePermissionDeniedError:
+		return NewApiError(
+			http.StatusForbidden,
+			"namespace_permission_denied",
+			fmt.Sprintf("The user with ID %s has the role %s in the namespace %s, which does not allow %s", authorizationError.UserID, authorizationError.Role, authorizationError.NamespaceName, authorizationError.Permission),
+			"If the user should be allowed to perform this action, please ask the namespace admin(s) to give the user another role",
+			c,
+			map[string]interface{}{
+				"namespaceId": authorizationError.NamespaceID,
+				"userId":      authorizationError.UserID,
+				"role":        authorizationError.Role,
+				"permission":  authorizationError.Permission,
+			},
+		), true
+	case *UnauthorizedToAccessNamespaceError:
+		return NewApiError(
+			http.StatusForbidden,
+			"user_unauthorized_to_access_namespace",
+			fmt.Sprintf("The user with ID %s is not a member of the namespace with ID %s", authorizationError.UserID, authorizationError.NamespaceID),
+			"If user should be authorized to access the namespace, please contact the namespace admin(s) to grant access to the user",
+			c,
+			map[string]interface{}{
+				"namespaceId": authorizationError.NamespaceID,
+				"userId":      authorizationError.UserID,
+			},
+		), true
+	case *UnauthorizedToRemoveAdminFromNamespaceError:
+		return NewApiError(
+			http.StatusForbidden,
+			"unauthorized_to_remove_admin_from_namespace",
+			fmt.Sprintf("The user with ID %s is not authorized to remove the admin %s from the namespace with ID %s", authorizationError.RemovedBy, authorizationError.UserID, authorizationError.NamespaceID),
+			"Only the owner of the namespace can remove or add its admins",
+			c,
+			map[string]interface{}{
+				"namespaceId": authorizationError.NamespaceID,
+				"userId":      authorizationError.UserID,
+				"removedBy":   authorizationError.RemovedBy,
+			},
+		), true
+	}
+	return ApiError{}, false
+}
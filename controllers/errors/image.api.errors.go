@@ -0,0 +1,79 @@
+package errors
+
+import (
+	stdErrors "errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// NewImageApiError returns the ApiError of an image that failed its verification or its scan before being deployed,
+// err may wrap the image error. It returns false if err is not an image error, so that the caller can handle it.
+func NewImageApiError(err error, c *gin.Context) (ApiError, bool) {
+	for ; err != nil; err = stdErrors.Unwrap(err) {
+		switch imageError := err.(type) {
+		case *ImageNotFoundError:
+			return NewApiError(
+				http.StatusBadRequest,
+				"image_not_found",
+				fmt.Sprintf("The image %s was not found in its registry: %s", imageError.Image, imageError.Message),
+				"Please check the image name, its tag and the registry of the application",
+				c,
+				map[string]interface{}{
+					"image": imageError.Image,
+				},
+			), true
+		case *IncompatibleImageArchitectureError:
+			return NewApiError(
+				http.StatusBadRequest,
+				"image_architecture_incompatible",
+				fmt.Sprintf("The image %s is built for %s, but the cluster nodes run %s", imageError.Image, strings.Join(imageError.Architectures, ", "), strings.Join(imageError.SupportedArchitectures, ", ")),
+				"Please build the image for one of the architectures of the cluster nodes",
+				c,
+				map[string]interface{}{
+					"image":                  imageError.Image,
+					"architectures":          imageError.Architectures,
+					"supportedArchitectures": imageError.SupportedArchitectures,
+				},
+			), true
+		case *ImageVerificationUnavailableError:
+			return NewApiError(
+				http.StatusBadGateway,
+				"image_verification_unavailable",
+				fmt.Sprintf("The image %s could not be verified: %s", imageError.Image, imageError.Message),
+				"Please check that the registry is reachable with the credentials of the application and try again later",
+				c,
+				map[string]interface{}{
+					"image": imageError.Image,
+				},
+			), true
+		case *VulnerableImageError:
+			return NewApiError(
+				http.StatusBadRequest,
+				"image_vulnerabilities_not_allowed",
+				fmt.Sprintf("The image %s has %d vulnerabilities more severe than %s, which the namespace does not allow: %s", imageError.Image, imageError.Count, imageError.MaxSeverity, imageError.VulnerabilityIDs),
+				"Please deploy an image fixing these vulnerabilities, or ask the namespace admin(s) to change the image scan policy",
+				c,
+				map[string]interface{}{
+					"image":       imageError.Image,
+					"imageDigest": imageError.ImageDigest,
+					"maxSeverity": imageError.MaxSeverity,
+				},
+			), true
+		case *ImageScanUnavailableError:
+			return NewApiError(
+				http.StatusBadGateway,
+				"image_scan_unavailable",
+				fmt.Sprintf("The image %s could not be scanned: %s", imageError.Image, imageError.Message),
+				"The namespace blocks the images that are not scanned, please try again later",
+				c,
+				map[string]interface{}{
+					"image": imageError.Image,
+				},
+			), true
+		}
+	}
+	return ApiError{}, false
+}
@@ -0,0 +1,63 @@
+package errors
+
+import "fmt"
+
+type ImageNotFoundError struct {
+	Image   string
+	Message string
+}
+
+func (e *ImageNotFoundError) Error() string {
+	return fmt.Sprintf("image %s not found: %s", e.Image, e.Message)
+}
+
+func NewImageNotFoundError(
+	image string,
+	message string,
+) *ImageNotFoundError {
+	return &ImageNotFoundError{
+		Image:   image,
+		Message: message,
+	}
+}
+
+type IncompatibleImageArchitectureError struct {
+	Image                  string
+	Architectures          []string
+	SupportedArchitectures []string
+}
+
+func (e *IncompatibleImageArchitectureError) Error() string {
+	return fmt.Sprintf("image %s is built for %v but the cluster nodes run %v", e.Image, e.Architectures, e.SupportedArchitectures)
+}
+
+func NewIncompatibleImageArchitectureError(
+	image string,
+	architectures []string,
+	supportedArchitectures []string,
+) *IncompatibleImageArchitectureError {
+	return &IncompatibleImageArchitectureError{
+		Image:                  image,
+		Architectures:          architectures,
+		SupportedArchitectures: supportedArchitectures,
+	}
+}
+
+type ImageVerificationUnavailableError struct {
+	Image   string
+	Message string
+}
+
+func (e *ImageVerificationUnavailableError) Error() string {
+	return fmt.Sprintf("image %s cannot be verified: %s", e.Image, e.Message)
+}
+
+func NewImageVerificationUnavailableError(
+	image string,
+	message string,
+) *ImageVerificationUnavailableError {
+	return &ImageVerificationUnavailableError{
+		Image:   image,
+		Message: message,
+	}
+}
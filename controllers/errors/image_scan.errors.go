@@ -0,0 +1,66 @@
+package errors
+
+import "fmt"
+
+type VulnerableImageError struct {
+	Image            string
+	ImageDigest      string
+	MaxSeverity      string
+	VulnerabilityIDs string
+	Count            int
+}
+
+func (e *VulnerableImageError) Error() string {
+	return fmt.Sprintf("image %s has %d vulnerabilities more severe than %s: %s", e.Image, e.Count, e.MaxSeverity, e.VulnerabilityIDs)
+}
+
+func NewVulnerableImageError(
+	image string,
+	imageDigest string,
+	maxSeverity string,
+	vulnerabilityIDs string,
+	count int,
+) *VulnerableImageError {
+	return &VulnerableImageError{
+		Image:            image,
+		ImageDigest:      imageDigest,
+		MaxSeverity:      maxSeverity,
+		VulnerabilityIDs: vulnerabilityIDs,
+		Count:            count,
+	}
+}
+
+type ImageScanUnavailableError struct {
+	Image   string
+	Message string
+}
+
+func (e *ImageScanUnavailableError) Error() string {
+	return fmt.Sprintf("image %s cannot be scanned: %s", e.Image, e.Message)
+}
+
+func NewImageScanUnavailableError(
+	image string,
+	message string,
+) *ImageScanUnavailableError {
+	return &ImageScanUnavailableError{
+		Image:   image,
+		Message: message,
+	}
+}
+
+type InvalidImageScanPolicyError struct {
+	Message string
+}
+
+func (e *InvalidImageScanPolicyError) Error() string {
+	return fmt.Sprintf("invalid image scan policy: %s", e.Message)
+}
+
+func NewInvalidImageScanPolicyError(
+	message string,
+) *InvalidImageScanPolicyError {
+	return &InvalidImageScanPolicyError{
+		Message: message,
+	}
+}
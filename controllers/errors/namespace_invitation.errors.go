@@ -0,0 +1,77 @@
+package errors
+
+import "fmt"
+
+type NamespaceInvitationNotFoundError struct {
+	InvitationID string
+}
+
+func (e *NamespaceInvitationNotFoundError) Error() string {
+	if e.InvitationID == "" {
+		return "namespace invitation not found"
+	}
+	return fmt.Sprintf("namespace invitation with id %s not found", e.InvitationID)
+}
+
+// NewNamespaceInvitationNotFoundError returns the error of an unknown invitation, the ID is empty when it was looked up by token
+func NewNamespaceInvitationNotFoundError(
+	invitationID string,
+) *NamespaceInvitationNotFoundError {
+	return &NamespaceInvitationNotFoundError{
+		InvitationID: invitationID,
+	}
+}
+
+type NamespaceInvitationNotPendingError struct {
+	InvitationID string
+	Status       string
+}
+
+func (e *NamespaceInvitationNotPendingError) Error() string {
+	return fmt.Sprintf("namespace invitation %s is %s", e.InvitationID, e.Status)
+}
+
+func NewNamespaceInvitationNotPendingError(
+	invitationID string,
+	status string,
+) *NamespaceInvitationNotPendingError {
+	return &NamespaceInvitationNotPendingError{
+		InvitationID: invitationID,
+		Status:       status,
+	}
+}
+
+type NamespaceInvitationEmailMismatchError struct {
+	InvitationID string
+	UserID       string
+}
+
+func (e *NamespaceInvitationEmailMismatchError) Error() string {
+	return fmt.Sprintf("namespace invitation %s was not sent to the email of user %s", e.InvitationID, e.UserID)
+}
+
+func NewNamespaceInvitationEmailMismatchError(
+	invitationID string,
+	userID string,
+) *NamespaceInvitationEmailMismatchError {
+	return &NamespaceInvitationEmailMismatchError{
+		InvitationID: invitationID,
+		UserID:       userID,
+	}
+}
+
+type InvalidNamespaceInvitationError struct {
+	Message string
+}
+
+func (e *InvalidNamespaceInvitationError) Error() string {
+	return fmt.Sprintf("invalid namespace invitation: %s", e.Message)
+}
+
+func NewInvalidNamespaceInvitationError(
+	message string,
+) *InvalidNamespaceInvitationError {
+	return &InvalidNamespaceInvitationError{
+		Message: message,
+	}
+}
@@ -0,0 +1,17 @@
+package errors
+
+type InvalidNamespaceManifestError struct {
+	Message string
+}
+
+func (e *InvalidNamespaceManifestError) Error() string {
+	return e.Message
+}
+
+func NewInvalidNamespaceManifestError(
+	message string,
+) *InvalidNamespaceManifestError {
+	return &InvalidNamespaceManifestError{
+		Message: message,
+	}
+}
@@ -0,0 +1,60 @@
+package errors
+
+import "fmt"
+
+type NamespaceMembershipConflictError struct {
+	NamespaceID string
+	UserID      string
+	Message     string
+}
+
+func (e *NamespaceMembershipConflictError) Error() string {
+	return fmt.Sprintf("membership of user %s in namespace %s cannot change: %s", e.UserID, e.NamespaceID, e.Message)
+}
+
+func NewNamespaceMembershipConflictError(
+	namespaceID string,
+	userID string,
+	message string,
+) *NamespaceMembershipConflictError {
+	return &NamespaceMembershipConflictError{
+		NamespaceID: namespaceID,
+		UserID:      userID,
+		Message:     message,
+	}
+}
+
+type NamespaceOwnershipTransferNotFoundError struct {
+	NamespaceID string
+	UserID      string
+}
+
+func (e *NamespaceOwnershipTransferNotFoundError) Error() string {
+	return fmt.Sprintf("namespace %s has no pending ownership transfer to user %s", e.NamespaceID, e.UserID)
+}
+
+func NewNamespaceOwnershipTransferNotFoundError(
+	namespaceID string,
+	userID string,
+) *NamespaceOwnershipTransferNotFoundError {
+	return &NamespaceOwnershipTransferNotFoundError{
+		NamespaceID: namespaceID,
+		UserID:      userID,
+	}
+}
+
+type InvalidNamespaceOwnershipTransferError struct {
+	Message string
+}
+
+func (e *InvalidNamespaceOwnershipTransferError) Error() string {
+	return fmt.Sprintf("invalid namespace ownership transfer: %s", e.Message)
+}
+
+func NewInvalidNamespaceOwnershipTransferError(
+	message string,
+) *InvalidNamespaceOwnershipTransferError {
+	return &InvalidNamespaceOwnershipTransferError{
+		Message: message,
+	}
+}
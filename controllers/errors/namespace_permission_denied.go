@@ -0,0 +1,31 @@
+package errors
+
+import "fmt"
+
+type NamespacePermissionDeniedError struct {
+	NamespaceID   string
+	NamespaceName string
+	UserID        string
+	Role          string
+	Permission    string
+}
+
+func (e *NamespacePermissionDeniedError) Error() string {
+	return fmt.Sprintf("user '%s' with role %s is not allowed to perform '%s' in namespace '%s' (namespaceID: %s)", e.UserID, e.Role, e.Permission, e.NamespaceName, e.NamespaceID)
+}
+
+func NewNamespacePermissionDeniedError(
+	namespaceID string,
+	namespaceName string,
+	userID string,
+	role string,
+	permission string,
+) *NamespacePermissionDeniedError {
+	return &NamespacePermissionDeniedError{
+		NamespaceID:   namespaceID,
+		NamespaceName: namespaceName,
+		UserID:        userID,
+		Role:          role,
+		Permission:    permission,
+	}
+}
@@ -0,0 +1,35 @@
+package errors
+
+import "fmt"
+
+type NetworkRuleNotFoundError struct {
+	NetworkRuleID string
+}
+
+func (e *NetworkRuleNotFoundError) Error() string {
+	return fmt.Sprintf("network rule with id %s not found", e.NetworkRuleID)
+}
+
+func NewNetworkRuleNotFoundError(
+	networkRuleID string,
+) *NetworkRuleNotFoundError {
+	return &NetworkRuleNotFoundError{
+		NetworkRuleID: networkRuleID,
+	}
+}
+
+type InvalidNetworkRuleError struct {
+	Message string
+}
+
+func (e *InvalidNetworkRuleError) Error() string {
+	return fmt.Sprintf("invalid network rule: %s", e.Message)
+}
+
+func NewInvalidNetworkRuleError(
+	message string,
+) *InvalidNetworkRuleError {
+	return &InvalidNetworkRuleError{
+		Message: message,
+	}
+}
@@ -0,0 +1,19 @@
+package errors
+
+import "fmt"
+
+type OperationNotFoundByIDError struct {
+	OperationID string
+}
+
+func (e *OperationNotFoundByIDError) Error() string {
+	return fmt.Sprintf("operation with id %s not found", e.OperationID)
+}
+
+func NewOperationNotFoundByIDError(
+	operationID string,
+) *OperationNotFoundByIDError {
+	return &OperationNotFoundByIDError{
+		OperationID: operationID,
+	}
+}
@@ -0,0 +1,54 @@
+package errors
+
+import "fmt"
+
+type RegistryCredentialNotFoundError struct {
+	RegistryCredentialID string
+}
+
+func (e *RegistryCredentialNotFoundError) Error() string {
+	return fmt.Sprintf("registry credential with id %s not found", e.RegistryCredentialID)
+}
+
+func NewRegistryCredentialNotFoundError(
+	registryCredentialID string,
+) *RegistryCredentialNotFoundError {
+	return &RegistryCredentialNotFoundError{
+		RegistryCredentialID: registryCredentialID,
+	}
+}
+
+type InvalidRegistryCredentialError struct {
+	Message string
+}
+
+func (e *InvalidRegistryCredentialError) Error() string {
+	return fmt.Sprintf("invalid registry credential: %s", e.Message)
+}
+
+func NewInvalidRegistryCredentialError(
+	message string,
+) *InvalidRegistryCredentialError {
+	return &InvalidRegistryCredentialError{
+		Message: message,
+	}
+}
+
+type RegistryCredentialInUseError struct {
+	RegistryCredentialID string
+	ApplicationNames     []string
+}
+
+func (e *RegistryCredentialInUseError) Error() string {
+	return fmt.Sprintf("registry credential with id %s is used by the applications %v", e.RegistryCredentialID, e.ApplicationNames)
+}
+
+func NewRegistryCredentialInUseError(
+	registryCredentialID string,
+	applicationNames []string,
+) *RegistryCredentialInUseError {
+	return &RegistryCredentialInUseError{
+		RegistryCredentialID: registryCredentialID,
+		ApplicationNames:     applicationNames,
+	}
+}
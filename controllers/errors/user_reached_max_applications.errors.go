@@ -0,0 +1,17 @@
+package errors
+
+type UserHasReachedMaxNumberOfApplicationsError struct {
+	Message string
+}
+
+func (e *UserHasReachedMaxNumberOfApplicationsError) Error() string {
+	return e.Message
+}
+
+func NewUserHasReachedMaxNumberOfApplicationsError(
+	message string,
+) *UserHasReachedMaxNumberOfApplicationsError {
+	return &UserHasReachedMaxNumberOfApplicationsError{
+		Message: message,
+	}
+}
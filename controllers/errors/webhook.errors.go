@@ -0,0 +1,35 @@
+package errors
+
+import "fmt"
+
+type WebhookNotFoundError struct {
+	WebhookID string
+}
+
+func (e *WebhookNotFoundError) Error() string {
+	return fmt.Sprintf("webhook with id %s not found", e.WebhookID)
+}
+
+func NewWebhookNotFoundError(
+	webhookID string,
+) *WebhookNotFoundError {
+	return &WebhookNotFoundError{
+		WebhookID: webhookID,
+	}
+}
+
+type InvalidWebhookError struct {
+	Message string
+}
+
+func (e *InvalidWebhookError) Error() string {
+	return fmt.Sprintf("invalid webhook: %s", e.Message)
+}
+
+func NewInvalidWebhookError(
+	message string,
+) *InvalidWebhookError {
+	return &InvalidWebhookError{
+		Message: message,
+	}
+}
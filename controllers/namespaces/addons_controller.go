@@ -0,0 +1,267 @@
+package namespaces
+
+import (
+	"cloud-app-hive/controllers/errors"
+	"fmt"
+	"net/http"
+
+	"cloud-app-hive/controllers/namespaces/requests"
+	"cloud-app-hive/controllers/validators"
+	"cloud-app-hive/domain"
+	"cloud-app-hive/domain/commands"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CreateAddonController godoc
+// @Summary Provisions a MySQL, PostgreSQL or Redis add-on in a namespace
+// @Description the add-on runs in the namespace with a volume sized by its tier, its credentials are generated, stored encrypted and never returned
+// @ID create-addon
+// @Tags Namespaces
+// @Accept  json
+// @Produce  json
+// @Param Authorization header string true "Authorization Token"
+// @Param id path string true "Namespace ID"
+// @Param addon body requests.CreateAddonRequest true "Name, type and size of the add-on"
+// @Success 201 {object} domain.Addon
+// @Failure 400 {object} errors.ApiError
+// @Failure 403 {object} errors.ApiError
+// @Failure 404 {object} errors.ApiError
+// @Router /namespaces/{id}/addons [post]
+func (namespaceController NamespaceController) CreateAddonController(c *gin.Context) {
+	userID, ok := validators.AuthenticatedUserID(c)
+	if !ok {
+		validators.Unauthorized(c)
+		return
+	}
+
+	var createAddonRequest requests.CreateAddonRequest
+	if err := c.ShouldBindJSON(&createAddonRequest); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"validation-errors": err.Error()})
+		return
+	}
+	size := domain.AddonSize(createAddonRequest.Size)
+	if size == "" {
+		size = domain.SmallAddon
+	}
+
+	addon, err := namespaceController.createAddonUseCase.Execute(commands.CreateAddon{
+		NamespaceID: c.Param("id"),
+		Name:        createAddonRequest.Name,
+		Type:        domain.AddonType(createAddonRequest.Type),
+		Size:        size,
+		CreatedBy:   userID,
+		Actor:       validators.AuditActor(c),
+	})
+	if err != nil {
+		addonError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"addon": addon,
+	})
+}
+
+// FindAddonsController godoc
+// @Summary Finds the add-ons of a namespace with the applications bound to them, without their credentials
+// @ID find-addons
+// @Tags Namespaces
+// @Produce  json
+// @Param Authorization header string true "Authorization Token"
+// @Param id path string true "Namespace ID"
+// @Success 200 {array} domain.Addon
+// @Failure 403 {object} errors.ApiError
+// @Router /namespaces/{id}/addons [get]
+func (namespaceController NamespaceController) FindAddonsController(c *gin.Context) {
+	userID, ok := validators.AuthenticatedUserID(c)
+	if !ok {
+		validators.Unauthorized(c)
+		return
+	}
+
+	addons, err := namespaceController.findAddonsUseCase.Execute(c.Param("id"), userID)
+	if err != nil {
+		addonError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"addons": addons,
+	})
+}
+
+// DeleteAddonController godoc
+// @Summary Deletes an add-on and its data
+// @Description an add-on cannot be deleted while applications are bound to it
+// @ID delete-addon
+// @Tags Namespaces
+// @Produce  json
+// @Param Authorization header string true "Authorization Token"
+// @Param id path string true "Namespace ID"
+// @Param addonId path string true "Add-on ID"
+// @Success 200 {object} domain.Addon
+// @Failure 403 {object} errors.ApiError
+// @Failure 404 {object} errors.ApiError
+// @Failure 409 {object} errors.ApiError
+// @Router /namespaces/{id}/addons/{addonId} [delete]
+func (namespaceController NamespaceController) DeleteAddonController(c *gin.Context) {
+	userID, ok := validators.AuthenticatedUserID(c)
+	if !ok {
+		validators.Unauthorized(c)
+		return
+	}
+
+	addon, err := namespaceController.deleteAddonUseCase.Execute(commands.DeleteAddon{
+		NamespaceID: c.Param("id"),
+		AddonID:     c.Param("addonId"),
+		DeletedBy:   userID,
+		Actor:       validators.AuditActor(c),
+	})
+	if err != nil {
+		addonError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"addon": addon,
+	})
+}
+
+// BindAddonController godoc
+// @Summary Binds an add-on to an application of its namespace
+// @Description the application receives the HIVE_ADDON_<NAME>_HOST, _PORT, _USERNAME, _PASSWORD, _DATABASE and _URL secrets and is deployed again if it is deployed
+// @ID bind-addon
+// @Tags Namespaces
+// @Accept  json
+// @Produce  json
+// @Param Authorization header string true "Authorization Token"
+// @Param id path string true "Namespace ID"
+// @Param addonId path string true "Add-on ID"
+// @Param binding body requests.BindAddonRequest true "Application to bind"
+// @Success 201 {object} domain.AddonBinding
+// @Failure 400 {object} errors.ApiError
+// @Failure 403 {object} errors.ApiError
+// @Failure 404 {object} errors.ApiError
+// @Router /namespaces/{id}/addons/{addonId}/bindings [post]
+func (namespaceController NamespaceController) BindAddonController(c *gin.Context) {
+	userID, ok := validators.AuthenticatedUserID(c)
+	if !ok {
+		validators.Unauthorized(c)
+		return
+	}
+
+	var bindAddonRequest requests.BindAddonRequest
+	if err := c.ShouldBindJSON(&bindAddonRequest); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"validation-errors": err.Error()})
+		return
+	}
+
+	binding, err := namespaceController.bindAddonUseCase.Execute(commands.BindAddon{
+		NamespaceID:   c.Param("id"),
+		AddonID:       c.Param("addonId"),
+		ApplicationID: bindAddonRequest.ApplicationID,
+		BoundBy:       userID,
+		Actor:         validators.AuditActor(c),
+	})
+	if err != nil {
+		addonError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"binding": binding,
+	})
+}
+
+// UnbindAddonController godoc
+// @Summary Unbinds an add-on from an application
+// @Description the secrets of the add-on are removed from the application, which is deployed again if it is deployed. The data of the add-on is kept.
+// @ID unbind-addon
+// @Tags Namespaces
+// @Produce  json
+// @Param Authorization header string true "Authorization Token"
+// @Param id path string true "Namespace ID"
+// @Param addonId path string true "Add-on ID"
+// @Param applicationId path string true "Application ID"
+// @Success 200 {object} domain.AddonBinding
+// @Failure 403 {object} errors.ApiError
+// @Failure 404 {object} errors.ApiError
+// @Router /namespaces/{id}/addons/{addonId}/bindings/{applicationId} [delete]
+func (namespaceController NamespaceController) UnbindAddonController(c *gin.Context) {
+	userID, ok := validators.AuthenticatedUserID(c)
+	if !ok {
+		validators.Unauthorized(c)
+		return
+	}
+
+	binding, err := namespaceController.unbindAddonUseCase.Execute(commands.UnbindAddon{
+		NamespaceID:   c.Param("id"),
+		AddonID:       c.Param("addonId"),
+		ApplicationID: c.Param("applicationId"),
+		UnboundBy:     userID,
+		Actor:         validators.AuditActor(c),
+	})
+	if err != nil {
+		addonError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"binding": binding,
+	})
+}
+
+func addonError(c *gin.Context, err error) {
+	if apiError, ok := errors.NewForbiddenApiError(err, c); ok {
+		c.JSON(apiError.StatusCode, apiError)
+		return
+	}
+
+	switch addonErr := err.(type) {
+	case *errors.NamespaceNotFoundByIDError:
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case *errors.ApplicationNotFoundByIDError:
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case *errors.AddonNotFoundError:
+		c.JSON(http.StatusNotFound, errors.NewApiError(
+			http.StatusNotFound,
+			"addon_not_found",
+			"The add-on does not exist",
+			"Please check the ID of the add-on",
+			c,
+			map[string]interface{}{
+				"addonId": addonErr.AddonID,
+			},
+		))
+	case *errors.AddonBindingNotFoundError:
+		c.JSON(http.StatusNotFound, errors.NewApiError(
+			http.StatusNotFound,
+			"addon_binding_not_found",
+			"The application is not bound to the add-on",
+			"Please check the IDs of the add-on and of the application",
+			c,
+			map[string]interface{}{
+				"addonId":       addonErr.AddonID,
+				"applicationId": addonErr.ApplicationID,
+			},
+		))
+	case *errors.AddonInUseError:
+		c.JSON(http.StatusConflict, errors.NewApiError(
+			http.StatusConflict,
+			"addon_in_use",
+			"Applications of the namespace are bound to the add-on",
+			"Please unbind these applications before deleting the add-on",
+			c,
+			map[string]interface{}{
+				"addonId":      addonErr.AddonID,
+				"applications": addonErr.ApplicationNames,
+			},
+		))
+	case *errors.InvalidAddonError:
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		fmt.Printf("Error while handling addon: %s", err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,215 @@
+package namespaces
+
+import (
+	"cloud-app-hive/controllers/errors"
+	"fmt"
+	"net/http"
+
+	"cloud-app-hive/controllers/namespaces/requests"
+	"cloud-app-hive/controllers/validators"
+	"cloud-app-hive/domain/commands"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CreateConfigGroupController godoc
+// @Summary Creates a config group of environment variables and secrets shared by applications of a namespace
+// @Description the group is rendered as a ConfigMap and a Secret in the namespace, secret values are stored encrypted and never returned
+// @ID create-config-group
+// @Tags Namespaces
+// @Accept  json
+// @Produce  json
+// @Param Authorization header string true "Authorization Token"
+// @Param id path string true "Namespace ID"
+// @Param configGroup body requests.CreateConfigGroupRequest true "Name and values of the config group"
+// @Success 201 {object} domain.ConfigGroup
+// @Failure 400 {object} errors.ApiError
+// @Failure 403 {object} errors.ApiError
+// @Failure 404 {object} errors.ApiError
+// @Router /namespaces/{id}/config-groups [post]
+func (namespaceController NamespaceController) CreateConfigGroupController(c *gin.Context) {
+	userID, ok := validators.AuthenticatedUserID(c)
+	if !ok {
+		validators.Unauthorized(c)
+		return
+	}
+
+	var createConfigGroupRequest requests.CreateConfigGroupRequest
+	if err := c.ShouldBindJSON(&createConfigGroupRequest); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"validation-errors": err.Error()})
+		return
+	}
+
+	configGroup, err := namespaceController.createConfigGroupUseCase.Execute(commands.CreateConfigGroup{
+		NamespaceID:          c.Param("id"),
+		Name:                 createConfigGroupRequest.Name,
+		Description:          createConfigGroupRequest.Description,
+		EnvironmentVariables: createConfigGroupRequest.EnvironmentVariables,
+		Secrets:              createConfigGroupRequest.Secrets,
+		CreatedBy:            userID,
+		Actor:                validators.AuditActor(c),
+	})
+	if err != nil {
+		configGroupError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"configGroup": configGroup,
+	})
+}
+
+// FindConfigGroupsController godoc
+// @Summary Finds the config groups of a namespace with the applications attached to them, without their secret values
+// @ID find-config-groups
+// @Tags Namespaces
+// @Produce  json
+// @Param Authorization header string true "Authorization Token"
+// @Param id path string true "Namespace ID"
+// @Success 200 {array} domain.ConfigGroup
+// @Failure 403 {object} errors.ApiError
+// @Router /namespaces/{id}/config-groups [get]
+func (namespaceController NamespaceController) FindConfigGroupsController(c *gin.Context) {
+	userID, ok := validators.AuthenticatedUserID(c)
+	if !ok {
+		validators.Unauthorized(c)
+		return
+	}
+
+	configGroups, err := namespaceController.findConfigGroupsUseCase.Execute(c.Param("id"), userID)
+	if err != nil {
+		configGroupError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"configGroups": configGroups,
+	})
+}
+
+// UpdateConfigGroupController godoc
+// @Summary Replaces the values of a config group
+// @Description the applications attached to the group are deployed again in the background if they are deployed, so that their pods are rolled out with the new values.
+// @Description The response lists the operations deploying them, their progress is read from the operations endpoint.
+// @ID update-config-group
+// @Tags Namespaces
+// @Accept  json
+// @Produce  json
+// @Param Authorization header string true "Authorization Token"
+// @Param id path string true "Namespace ID"
+// @Param configGroupId path string true "Config group ID"
+// @Param configGroup body requests.UpdateConfigGroupRequest true "Values of the config group"
+// @Success 202 {object} domain.ConfigGroup
+// @Failure 400 {object} errors.ApiError
+// @Failure 403 {object} errors.ApiError
+// @Failure 404 {object} errors.ApiError
+// @Router /namespaces/{id}/config-groups/{configGroupId} [put]
+func (namespaceController NamespaceController) UpdateConfigGroupController(c *gin.Context) {
+	userID, ok := validators.AuthenticatedUserID(c)
+	if !ok {
+		validators.Unauthorized(c)
+		return
+	}
+
+	var updateConfigGroupRequest requests.UpdateConfigGroupRequest
+	if err := c.ShouldBindJSON(&updateConfigGroupRequest); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"validation-errors": err.Error()})
+		return
+	}
+
+	configGroup, startedOperations, err := namespaceController.updateConfigGroupUseCase.Execute(commands.UpdateConfigGroup{
+		NamespaceID:          c.Param("id"),
+		ConfigGroupID:        c.Param("configGroupId"),
+		Description:          updateConfigGroupRequest.Description,
+		EnvironmentVariables: updateConfigGroupRequest.EnvironmentVariables,
+		Secrets:              updateConfigGroupRequest.Secrets,
+		UpdatedBy:            userID,
+		Actor:                validators.AuditActor(c),
+	})
+	if err != nil {
+		configGroupError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"configGroup": configGroup,
+		"operations":  startedOperations,
+	})
+}
+
+// DeleteConfigGroupController godoc
+// @Summary Deletes a config group
+// @Description a config group cannot be deleted while applications are attached to it
+// @ID delete-config-group
+// @Tags Namespaces
+// @Produce  json
+// @Param Authorization header string true "Authorization Token"
+// @Param id path string true "Namespace ID"
+// @Param configGroupId path string true "Config group ID"
+// @Success 200 {object} domain.ConfigGroup
+// @Failure 403 {object} errors.ApiError
+// @Failure 404 {object} errors.ApiError
+// @Failure 409 {object} errors.ApiError
+// @Router /namespaces/{id}/config-groups/{configGroupId} [delete]
+func (namespaceController NamespaceController) DeleteConfigGroupController(c *gin.Context) {
+	userID, ok := validators.AuthenticatedUserID(c)
+	if !ok {
+		validators.Unauthorized(c)
+		return
+	}
+
+	configGroup, err := namespaceController.deleteConfigGroupUseCase.Execute(commands.DeleteConfigGroup{
+		NamespaceID:   c.Param("id"),
+		ConfigGroupID: c.Param("configGroupId"),
+		DeletedBy:     userID,
+		Actor:         validators.AuditActor(c),
+	})
+	if err != nil {
+		configGroupError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"configGroup": configGroup,
+	})
+}
+
+func configGroupError(c *gin.Context, err error) {
+	if apiError, ok := errors.NewForbiddenApiError(err, c); ok {
+		c.JSON(apiError.StatusCode, apiError)
+		return
+	}
+
+	switch configGroupErr := err.(type) {
+	case *errors.NamespaceNotFoundByIDError:
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case *errors.ConfigGroupNotFoundError:
+		c.JSON(http.StatusNotFound, errors.NewApiError(
+			http.StatusNotFound,
+			"config_group_not_found",
+			"The config group does not exist",
+			"Please check the ID of the config group",
+			c,
+			map[string]interface{}{
+				"configGroup": configGroupErr.ConfigGroup,
+			},
+		))
+	case *errors.ConfigGroupInUseError:
+		c.JSON(http.StatusConflict, errors.NewApiError(
+			http.StatusConflict,
+			"config_group_in_use",
+			"Applications of the namespace are attached to the config group",
+			"Please detach these applications before deleting the config group",
+			c,
+			map[string]interface{}{
+				"configGroupId": configGroupErr.ConfigGroupID,
+				"applications":  configGroupErr.ApplicationNames,
+			},
+		))
+	case *errors.InvalidConfigGroupError:
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		fmt.Printf("Error while handling config group: %s", err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
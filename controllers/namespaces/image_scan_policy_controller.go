@@ -0,0 +1,69 @@
+package namespaces
+
+import (
+	"cloud-app-hive/controllers/errors"
+	"net/http"
+
+	"cloud-app-hive/controllers/namespaces/requests"
+	"cloud-app-hive/controllers/validators"
+	"cloud-app-hive/domain/commands"
+
+	"github.com/gin-gonic/gin"
+)
+
+// UpdateImageScanPolicyController godoc
+// @Summary Changes the image scan policy of a namespace
+// @Description images deployed in the namespace are scanned for vulnerabilities, deploys of images with vulnerabilities more severe than maxSeverity are blocked or warned about
+// @ID update-image-scan-policy
+// @Tags Namespaces
+// @Accept  json
+// @Produce  json
+// @Param Authorization header string true "Authorization Token"
+// @Param id path string true "Namespace ID"
+// @Param imageScanPolicy body requests.UpdateImageScanPolicyRequest true "Policy and most severe vulnerabilities allowed"
+// @Success 200 {object} domain.Namespace
+// @Failure 400 {object} errors.ApiError
+// @Failure 403 {object} errors.ApiError
+// @Failure 404 {object} errors.ApiError
+// @Router /namespaces/{id}/image-scan-policy [put]
+func (namespaceController NamespaceController) UpdateImageScanPolicyController(c *gin.Context) {
+	userID, ok := validators.AuthenticatedUserID(c)
+	if !ok {
+		validators.Unauthorized(c)
+		return
+	}
+
+	var updateImageScanPolicyRequest requests.UpdateImageScanPolicyRequest
+	if err := c.ShouldBindJSON(&updateImageScanPolicyRequest); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"validation-errors": err.Error()})
+		return
+	}
+
+	namespace, err := namespaceController.updateImageScanPolicyUseCase.Execute(commands.UpdateImageScanPolicy{
+		NamespaceID: c.Param("id"),
+		Policy:      updateImageScanPolicyRequest.Policy,
+		MaxSeverity: updateImageScanPolicyRequest.MaxSeverity,
+		UpdatedBy:   userID,
+		Actor:       validators.AuditActor(c),
+	})
+	if err != nil {
+		if apiError, ok := errors.NewForbiddenApiError(err, c); ok {
+			c.JSON(apiError.StatusCode, apiError)
+			return
+		}
+		switch err.(type) {
+		case *errors.NamespaceNotFoundByIDError:
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case *errors.InvalidImageScanPolicyError:
+			c.JSON(http.StatusBadRequest, gin.H{"validation-errors": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"imageScanPolicy": namespace.ImageScanPolicy,
+		"maxSeverity":     namespace.MaxImageVulnerabilitySeverity,
+	})
+}
@@ -0,0 +1,247 @@
+package namespaces
+
+import (
+	"cloud-app-hive/controllers/errors"
+	"fmt"
+	"net/http"
+
+	"cloud-app-hive/controllers/namespaces/requests"
+	"cloud-app-hive/controllers/validators"
+	"cloud-app-hive/domain/commands"
+
+	"github.com/gin-gonic/gin"
+)
+
+// InviteMemberToNamespaceController godoc
+// @Summary Invites someone to a namespace by email
+// @Description stores a pending invitation and emails a link to accept it, the membership is only created once the invitation is accepted
+// @ID invite-member-to-namespace
+// @Tags Namespaces
+// @Accept  json
+// @Produce  json
+// @Param Authorization header string true "Authorization Token"
+// @Param id path string true "Namespace ID"
+// @Param invitation body requests.InviteMemberToNamespaceRequest true "Email and role of the invitee"
+// @Success 201 {object} domain.NamespaceInvitation
+// @Failure 400 {object} errors.ApiError
+// @Failure 403 {object} errors.ApiError
+// @Router /namespaces/{id}/invitations [post]
+func (namespaceController NamespaceController) InviteMemberToNamespaceController(c *gin.Context) {
+	userID, ok := validators.AuthenticatedUserID(c)
+	if !ok {
+		validators.Unauthorized(c)
+		return
+	}
+
+	var inviteMemberToNamespaceRequest requests.InviteMemberToNamespaceRequest
+	if err := c.ShouldBindJSON(&inviteMemberToNamespaceRequest); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"validation-errors": err.Error()})
+		return
+	}
+
+	namespaceInvitation, err := namespaceController.createNamespaceInvitationUseCase.Execute(commands.CreateNamespaceInvitation{
+		NamespaceID: c.Param("id"),
+		Email:       inviteMemberToNamespaceRequest.Email,
+		Role:        inviteMemberToNamespaceRequest.Role,
+		InvitedBy:   userID,
+		Actor:       validators.AuditActor(c),
+	})
+	if err != nil {
+		namespaceInvitationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"namespace_invitation": namespaceInvitation,
+	})
+}
+
+// FindNamespaceInvitationsController godoc
+// @Summary Finds the pending invitations of a namespace
+// @Description returns the invitations of a namespace that were neither accepted, declined, revoked nor expired
+// @ID find-namespace-invitations
+// @Tags Namespaces
+// @Produce  json
+// @Param Authorization header string true "Authorization Token"
+// @Param id path string true "Namespace ID"
+// @Success 200 {array} domain.NamespaceInvitation
+// @Failure 403 {object} errors.ApiError
+// @Router /namespaces/{id}/invitations [get]
+func (namespaceController NamespaceController) FindNamespaceInvitationsController(c *gin.Context) {
+	userID, ok := validators.AuthenticatedUserID(c)
+	if !ok {
+		validators.Unauthorized(c)
+		return
+	}
+
+	namespaceInvitations, err := namespaceController.findNamespaceInvitationsUseCase.Execute(c.Param("id"), userID)
+	if err != nil {
+		namespaceInvitationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"namespace_invitations": namespaceInvitations,
+	})
+}
+
+// RevokeNamespaceInvitationController godoc
+// @Summary Revokes a pending invitation
+// @ID revoke-namespace-invitation
+// @Tags Namespaces
+// @Produce  json
+// @Param Authorization header string true "Authorization Token"
+// @Param id path string true "Namespace ID"
+// @Param invitationId path string true "Invitation ID"
+// @Success 200 {object} domain.NamespaceInvitation
+// @Failure 403 {object} errors.ApiError
+// @Failure 404 {object} errors.ApiError
+// @Failure 410 {object} errors.ApiError
+// @Router /namespaces/{id}/invitations/{invitationId} [delete]
+func (namespaceController NamespaceController) RevokeNamespaceInvitationController(c *gin.Context) {
+	userID, ok := validators.AuthenticatedUserID(c)
+	if !ok {
+		validators.Unauthorized(c)
+		return
+	}
+
+	namespaceInvitation, err := namespaceController.revokeNamespaceInvitationUseCase.Execute(commands.RevokeNamespaceInvitation{
+		NamespaceID:  c.Param("id"),
+		InvitationID: c.Param("invitationId"),
+		RevokedBy:    userID,
+		Actor:        validators.AuditActor(c),
+	})
+	if err != nil {
+		namespaceInvitationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"namespace_invitation": namespaceInvitation,
+	})
+}
+
+// AcceptNamespaceInvitationController godoc
+// @Summary Accepts an invitation
+// @Description makes the signed in user a member of the namespace with the role of the invitation
+// @ID accept-namespace-invitation
+// @Tags Namespaces
+// @Produce  json
+// @Param Authorization header string true "Authorization Token"
+// @Param token path string true "Token received by email"
+// @Success 200 {object} domain.NamespaceMembership
+// @Failure 403 {object} errors.ApiError
+// @Failure 404 {object} errors.ApiError
+// @Failure 410 {object} errors.ApiError
+// @Router /invitations/{token}/accept [post]
+func (namespaceController NamespaceController) AcceptNamespaceInvitationController(c *gin.Context) {
+	respondToNamespaceInvitation, ok := newRespondToNamespaceInvitation(c)
+	if !ok {
+		return
+	}
+
+	namespaceMembership, err := namespaceController.acceptNamespaceInvitationUseCase.Execute(respondToNamespaceInvitation)
+	if err != nil {
+		namespaceInvitationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"namespace_membership": namespaceMembership,
+	})
+}
+
+// DeclineNamespaceInvitationController godoc
+// @Summary Declines an invitation
+// @ID decline-namespace-invitation
+// @Tags Namespaces
+// @Produce  json
+// @Param Authorization header string true "Authorization Token"
+// @Param token path string true "Token received by email"
+// @Success 200 {object} domain.NamespaceInvitation
+// @Failure 403 {object} errors.ApiError
+// @Failure 404 {object} errors.ApiError
+// @Failure 410 {object} errors.ApiError
+// @Router /invitations/{token}/decline [post]
+func (namespaceController NamespaceController) DeclineNamespaceInvitationController(c *gin.Context) {
+	respondToNamespaceInvitation, ok := newRespondToNamespaceInvitation(c)
+	if !ok {
+		return
+	}
+
+	namespaceInvitation, err := namespaceController.declineNamespaceInvitationUseCase.Execute(respondToNamespaceInvitation)
+	if err != nil {
+		namespaceInvitationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"namespace_invitation": namespaceInvitation,
+	})
+}
+
+func newRespondToNamespaceInvitation(c *gin.Context) (commands.RespondToNamespaceInvitation, bool) {
+	userID, ok := validators.AuthenticatedUserID(c)
+	if !ok {
+		validators.Unauthorized(c)
+		return commands.RespondToNamespaceInvitation{}, false
+	}
+	return commands.RespondToNamespaceInvitation{
+		Token:     c.Param("token"),
+		UserID:    userID,
+		UserEmail: validators.AuthenticatedUserEmail(c),
+		Actor:     validators.AuditActor(c),
+	}, true
+}
+
+func namespaceInvitationError(c *gin.Context, err error) {
+	if apiError, ok := errors.NewForbiddenApiError(err, c); ok {
+		c.JSON(apiError.StatusCode, apiError)
+		return
+	}
+
+	switch invitationError := err.(type) {
+	case *errors.NamespaceNotFoundByIDError:
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case *errors.NamespaceInvitationNotFoundError:
+		c.JSON(http.StatusNotFound, errors.NewApiError(
+			http.StatusNotFound,
+			"namespace_invitation_not_found",
+			"The invitation does not exist",
+			"Please check the link of the invitation email",
+			c,
+			map[string]interface{}{
+				"invitationId": invitationError.InvitationID,
+			},
+		))
+	case *errors.NamespaceInvitationNotPendingError:
+		c.JSON(http.StatusGone, errors.NewApiError(
+			http.StatusGone,
+			"namespace_invitation_not_pending",
+			err.Error(),
+			"Please ask the namespace admin(s) for a new invitation",
+			c,
+			map[string]interface{}{
+				"invitationId": invitationError.InvitationID,
+				"status":       invitationError.Status,
+			},
+		))
+	case *errors.NamespaceInvitationEmailMismatchError:
+		c.JSON(http.StatusForbidden, errors.NewApiError(
+			http.StatusForbidden,
+			"namespace_invitation_email_mismatch",
+			err.Error(),
+			"Please sign in with the account of the email the invitation was sent to",
+			c,
+			map[string]interface{}{
+				"invitationId": invitationError.InvitationID,
+				"userId":       invitationError.UserID,
+			},
+		))
+	case *errors.InvalidNamespaceInvitationError:
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		fmt.Printf("Error while handling namespace invitation: %s", err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,207 @@
+package namespaces
+
+import (
+	"cloud-app-hive/controllers/errors"
+	"fmt"
+	"net/http"
+
+	"cloud-app-hive/controllers/namespaces/requests"
+	"cloud-app-hive/controllers/validators"
+	"cloud-app-hive/domain/commands"
+
+	"github.com/gin-gonic/gin"
+)
+
+// UpdateMemberRoleController godoc
+// @Summary Changes the role of a namespace member
+// @Description only the owner can give or take the owner and admin roles, the namespace always keeps its owner and at least one owner or admin
+// @ID update-member-role
+// @Tags Namespaces
+// @Accept  json
+// @Produce  json
+// @Param Authorization header string true "Authorization Token"
+// @Param id path string true "Namespace ID"
+// @Param userId path string true "User ID of the member"
+// @Param role body requests.UpdateMemberRoleRequest true "New role of the member"
+// @Success 200 {object} domain.NamespaceMembership
+// @Failure 403 {object} errors.ApiError
+// @Failure 409 {object} errors.ApiError
+// @Router /namespaces/{id}/memberships/{userId} [put]
+func (namespaceController NamespaceController) UpdateMemberRoleController(c *gin.Context) {
+	updatedBy, ok := validators.AuthenticatedUserID(c)
+	if !ok {
+		validators.Unauthorized(c)
+		return
+	}
+
+	var updateMemberRoleRequest requests.UpdateMemberRoleRequest
+	if err := c.ShouldBindJSON(&updateMemberRoleRequest); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"validation-errors": err.Error()})
+		return
+	}
+
+	namespaceMembership, err := namespaceController.updateNamespaceMembershipUseCase.Execute(commands.UpdateNamespaceMembership{
+		NamespaceID: c.Param("id"),
+		UserID:      c.Param("userId"),
+		Role:        updateMemberRoleRequest.Role,
+		UpdatedBy:   updatedBy,
+		Actor:       validators.AuditActor(c),
+	})
+	if err != nil {
+		namespaceOwnershipError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"namespace_membership": namespaceMembership,
+	})
+}
+
+// TransferNamespaceOwnershipController godoc
+// @Summary Transfers a namespace to one of its members
+// @Description the member becomes the owner once the transfer is accepted, the previous owner then stays as an admin
+// @ID transfer-namespace-ownership
+// @Tags Namespaces
+// @Accept  json
+// @Produce  json
+// @Param Authorization header string true "Authorization Token"
+// @Param id path string true "Namespace ID"
+// @Param transfer body requests.TransferNamespaceOwnershipRequest true "Member the namespace is transferred to"
+// @Success 200 {object} domain.Namespace
+// @Failure 400 {object} errors.ApiError
+// @Failure 403 {object} errors.ApiError
+// @Router /namespaces/{id}/ownership-transfer [post]
+func (namespaceController NamespaceController) TransferNamespaceOwnershipController(c *gin.Context) {
+	userID, ok := validators.AuthenticatedUserID(c)
+	if !ok {
+		validators.Unauthorized(c)
+		return
+	}
+
+	var transferNamespaceOwnershipRequest requests.TransferNamespaceOwnershipRequest
+	if err := c.ShouldBindJSON(&transferNamespaceOwnershipRequest); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"validation-errors": err.Error()})
+		return
+	}
+
+	namespace, err := namespaceController.transferNamespaceOwnershipUseCase.Execute(commands.TransferNamespaceOwnership{
+		NamespaceID: c.Param("id"),
+		NewOwnerID:  transferNamespaceOwnershipRequest.UserID,
+		RequestedBy: userID,
+		Actor:       validators.AuditActor(c),
+	})
+	if err != nil {
+		namespaceOwnershipError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"namespace": namespace,
+	})
+}
+
+// AcceptNamespaceOwnershipTransferController godoc
+// @Summary Accepts the ownership of a namespace
+// @ID accept-namespace-ownership-transfer
+// @Tags Namespaces
+// @Produce  json
+// @Param Authorization header string true "Authorization Token"
+// @Param id path string true "Namespace ID"
+// @Success 200 {object} domain.Namespace
+// @Failure 404 {object} errors.ApiError
+// @Router /namespaces/{id}/ownership-transfer/accept [post]
+func (namespaceController NamespaceController) AcceptNamespaceOwnershipTransferController(c *gin.Context) {
+	userID, ok := validators.AuthenticatedUserID(c)
+	if !ok {
+		validators.Unauthorized(c)
+		return
+	}
+
+	namespace, err := namespaceController.acceptNamespaceOwnershipTransferUseCase.Execute(commands.RespondToNamespaceOwnershipTransfer{
+		NamespaceID: c.Param("id"),
+		UserID:      userID,
+		Actor:       validators.AuditActor(c),
+	})
+	if err != nil {
+		namespaceOwnershipError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"namespace": namespace,
+	})
+}
+
+// CancelNamespaceOwnershipTransferController godoc
+// @Summary Cancels the pending ownership transfer of a namespace
+// @Description the owner withdraws the transfer or the member it was offered to declines it
+// @ID cancel-namespace-ownership-transfer
+// @Tags Namespaces
+// @Produce  json
+// @Param Authorization header string true "Authorization Token"
+// @Param id path string true "Namespace ID"
+// @Success 200 {object} domain.Namespace
+// @Failure 403 {object} errors.ApiError
+// @Failure 404 {object} errors.ApiError
+// @Router /namespaces/{id}/ownership-transfer [delete]
+func (namespaceController NamespaceController) CancelNamespaceOwnershipTransferController(c *gin.Context) {
+	userID, ok := validators.AuthenticatedUserID(c)
+	if !ok {
+		validators.Unauthorized(c)
+		return
+	}
+
+	namespace, err := namespaceController.cancelNamespaceOwnershipTransferUseCase.Execute(commands.RespondToNamespaceOwnershipTransfer{
+		NamespaceID: c.Param("id"),
+		UserID:      userID,
+		Actor:       validators.AuditActor(c),
+	})
+	if err != nil {
+		namespaceOwnershipError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"namespace": namespace,
+	})
+}
+
+func namespaceOwnershipError(c *gin.Context, err error) {
+	if apiError, ok := errors.NewForbiddenApiError(err, c); ok {
+		c.JSON(apiError.StatusCode, apiError)
+		return
+	}
+	if apiError, ok := newNamespaceMembershipConflictApiError(err, c); ok {
+		c.JSON(apiError.StatusCode, apiError)
+		return
+	}
+
+	switch err.(type) {
+	case *errors.NamespaceNotFoundByIDError, *errors.NamespaceOwnershipTransferNotFoundError:
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case *errors.InvalidNamespaceOwnershipTransferError:
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		fmt.Printf("Error while changing namespace ownership or roles: %s", err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// newNamespaceMembershipConflictApiError returns the 409 ApiError of a change that would leave a namespace without owner or admin
+func newNamespaceMembershipConflictApiError(err error, c *gin.Context) (errors.ApiError, bool) {
+	conflictError, ok := err.(*errors.NamespaceMembershipConflictError)
+	if !ok {
+		return errors.ApiError{}, false
+	}
+	return errors.NewApiError(
+		http.StatusConflict,
+		"namespace_membership_conflict",
+		conflictError.Error(),
+		"Please give the owner or admin role to another member, or transfer the ownership of the namespace first",
+		c,
+		map[string]interface{}{
+			"namespaceId": conflictError.NamespaceID,
+			"userId":      conflictError.UserID,
+		},
+	), true
+}
@@ -0,0 +1,49 @@
+package namespaces
+
+import (
+	"fmt"
+	"net/http"
+
+	"cloud-app-hive/controllers/errors"
+	"cloud-app-hive/controllers/validators"
+
+	"github.com/gin-gonic/gin"
+)
+
+// FindNamespaceServicesController godoc
+// @Summary Finds the services of the applications of a namespace
+// @Description applications reach the services of the applications they depend on through the HIVE_SVC_<NAME>_HOST, HIVE_SVC_<NAME>_PORT and HIVE_SVC_<NAME>_URL environment variables
+// @ID find-namespace-services
+// @Tags Namespaces
+// @Produce  json
+// @Param Authorization header string true "Authorization Token"
+// @Param id path string true "Namespace ID"
+// @Success 200 {array} domain.ApplicationService
+// @Failure 403 {object} errors.ApiError
+// @Router /namespaces/{id}/services [get]
+func (namespaceController NamespaceController) FindNamespaceServicesController(c *gin.Context) {
+	userID, ok := validators.AuthenticatedUserID(c)
+	if !ok {
+		validators.Unauthorized(c)
+		return
+	}
+
+	services, err := namespaceController.findNamespaceServicesUseCase.Execute(c.Param("id"), userID)
+	if err != nil {
+		if apiError, ok := errors.NewForbiddenApiError(err, c); ok {
+			c.JSON(apiError.StatusCode, apiError)
+			return
+		}
+		if _, ok := err.(*errors.NamespaceNotFoundByIDError); ok {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		fmt.Println("Error while finding namespace services: ", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"services": services,
+	})
+}
@@ -4,8 +4,11 @@ import (
 	"cloud-app-hive/controllers/errors"
 	"cloud-app-hive/domain"
 	"cloud-app-hive/use_cases/applications"
+	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
+	"strings"
 
 	"cloud-app-hive/controllers/namespaces/requests"
 	"cloud-app-hive/controllers/validators"
@@ -13,18 +16,53 @@ import (
 	"cloud-app-hive/use_cases/namespaces"
 
 	"github.com/gin-gonic/gin"
+	"sigs.k8s.io/yaml"
 )
 
 type NamespaceController struct {
-	createNamespaceUseCase           namespaces.CreateNamespaceUseCase
-	findNamespacesUseCase            namespaces.FindNamespacesUseCase
-	findNamespaceByIDUseCase         namespaces.FindNamespaceByIDUseCase
-	findNamespaceByName              namespaces.FindNamespaceByNameUseCase
-	createNamespaceMembershipUseCase namespaces.CreateNamespaceMembershipUseCase
-	removeNamespaceMembershipUseCase namespaces.RemoveNamespaceMembershipUseCase
-	deleteNamespaceByIDUseCase       namespaces.DeleteNamespaceByIDUseCase
-	updateNamespaceByIDUseCase       namespaces.UpdateNamespaceByIDUseCase
-	fillApplicationsStatusUseCase    applications.FillApplicationStatusUseCase
+	createNamespaceUseCase                  namespaces.CreateNamespaceUseCase
+	findNamespacesUseCase                   namespaces.FindNamespacesUseCase
+	findNamespaceByIDUseCase                namespaces.FindNamespaceByIDUseCase
+	findNamespaceByName                     namespaces.FindNamespaceByNameUseCase
+	createNamespaceMembershipUseCase        namespaces.CreateNamespaceMembershipUseCase
+	removeNamespaceMembershipUseCase        namespaces.RemoveNamespaceMembershipUseCase
+	deleteNamespaceByIDUseCase              namespaces.DeleteNamespaceByIDUseCase
+	updateNamespaceByIDUseCase              namespaces.UpdateNamespaceByIDUseCase
+	fillApplicationsStatusUseCase           applications.FillApplicationStatusUseCase
+	exportNamespaceManifestUseCase          namespaces.ExportNamespaceManifestUseCase
+	applyNamespaceManifestUseCase           namespaces.ApplyNamespaceManifestUseCase
+	findAuditEventsUseCase                  namespaces.FindAuditEventsUseCase
+	createNamespaceInvitationUseCase        namespaces.CreateNamespaceInvitationUseCase
+	findNamespaceInvitationsUseCase         namespaces.FindNamespaceInvitationsUseCase
+	revokeNamespaceInvitationUseCase        namespaces.RevokeNamespaceInvitationUseCase
+	acceptNamespaceInvitationUseCase        namespaces.AcceptNamespaceInvitationUseCase
+	declineNamespaceInvitationUseCase       namespaces.DeclineNamespaceInvitationUseCase
+	updateNamespaceMembershipUseCase        namespaces.UpdateNamespaceMembershipUseCase
+	transferNamespaceOwnershipUseCase       namespaces.TransferNamespaceOwnershipUseCase
+	acceptNamespaceOwnershipTransferUseCase namespaces.AcceptNamespaceOwnershipTransferUseCase
+	cancelNamespaceOwnershipTransferUseCase namespaces.CancelNamespaceOwnershipTransferUseCase
+	createNetworkRuleUseCase                namespaces.CreateNetworkRuleUseCase
+	findNetworkRulesUseCase                 namespaces.FindNetworkRulesUseCase
+	deleteNetworkRuleUseCase                namespaces.DeleteNetworkRuleUseCase
+	createRegistryCredentialUseCase         namespaces.CreateRegistryCredentialUseCase
+	findRegistryCredentialsUseCase          namespaces.FindRegistryCredentialsUseCase
+	updateRegistryCredentialUseCase         namespaces.UpdateRegistryCredentialUseCase
+	deleteRegistryCredentialUseCase         namespaces.DeleteRegistryCredentialUseCase
+	updateImageScanPolicyUseCase            namespaces.UpdateImageScanPolicyUseCase
+	findNamespaceServicesUseCase            namespaces.FindNamespaceServicesUseCase
+	createAddonUseCase                      namespaces.CreateAddonUseCase
+	findAddonsUseCase                       namespaces.FindAddonsUseCase
+	deleteAddonUseCase                      namespaces.DeleteAddonUseCase
+	bindAddonUseCase                        namespaces.BindAddonUseCase
+	unbindAddonUseCase                      namespaces.UnbindAddonUseCase
+	createConfigGroupUseCase                namespaces.CreateConfigGroupUseCase
+	findConfigGroupsUseCase                 namespaces.FindConfigGroupsUseCase
+	updateConfigGroupUseCase                namespaces.UpdateConfigGroupUseCase
+	deleteConfigGroupUseCase                namespaces.DeleteConfigGroupUseCase
+	createWebhookUseCase                    namespaces.CreateWebhookUseCase
+	findWebhooksUseCase                     namespaces.FindWebhooksUseCase
+	updateWebhookUseCase                    namespaces.UpdateWebhookUseCase
+	deleteWebhookUseCase                    namespaces.DeleteWebhookUseCase
 }
 
 func NewNamespaceController(
@@ -36,25 +74,99 @@ func NewNamespaceController(
 	deleteNamespaceByIDUseCase namespaces.DeleteNamespaceByIDUseCase,
 	updateNamespaceByIDUseCase namespaces.UpdateNamespaceByIDUseCase,
 	fillApplicationsStatusUseCase applications.FillApplicationStatusUseCase,
+	exportNamespaceManifestUseCase namespaces.ExportNamespaceManifestUseCase,
+	applyNamespaceManifestUseCase namespaces.ApplyNamespaceManifestUseCase,
+	findAuditEventsUseCase namespaces.FindAuditEventsUseCase,
+	createNamespaceInvitationUseCase namespaces.CreateNamespaceInvitationUseCase,
+	findNamespaceInvitationsUseCase namespaces.FindNamespaceInvitationsUseCase,
+	revokeNamespaceInvitationUseCase namespaces.RevokeNamespaceInvitationUseCase,
+	acceptNamespaceInvitationUseCase namespaces.AcceptNamespaceInvitationUseCase,
+	declineNamespaceInvitationUseCase namespaces.DeclineNamespaceInvitationUseCase,
+	updateNamespaceMembershipUseCase namespaces.UpdateNamespaceMembershipUseCase,
+	transferNamespaceOwnershipUseCase namespaces.TransferNamespaceOwnershipUseCase,
+	acceptNamespaceOwnershipTransferUseCase namespaces.AcceptNamespaceOwnershipTransferUseCase,
+	cancelNamespaceOwnershipTransferUseCase namespaces.CancelNamespaceOwnershipTransferUseCase,
+	createNetworkRuleUseCase namespaces.CreateNetworkRuleUseCase,
+	findNetworkRulesUseCase namespaces.FindNetworkRulesUseCase,
+	deleteNetworkRuleUseCase namespaces.DeleteNetworkRuleUseCase,
+	createRegistryCredentialUseCase namespaces.CreateRegistryCredentialUseCase,
+	findRegistryCredentialsUseCase namespaces.FindRegistryCredentialsUseCase,
+	updateRegistryCredentialUseCase namespaces.UpdateRegistryCredentialUseCase,
+	deleteRegistryCredentialUseCase namespaces.DeleteRegistryCredentialUseCase,
+	updateImageScanPolicyUseCase namespaces.UpdateImageScanPolicyUseCase,
+	findNamespaceServicesUseCase namespaces.FindNamespaceServicesUseCase,
+	createAddonUseCase namespaces.CreateAddonUseCase,
+	findAddonsUseCase namespaces.FindAddonsUseCase,
+	deleteAddonUseCase namespaces.DeleteAddonUseCase,
+	bindAddonUseCase namespaces.BindAddonUseCase,
+	unbindAddonUseCase namespaces.UnbindAddonUseCase,
+	createConfigGroupUseCase namespaces.CreateConfigGroupUseCase,
+	findConfigGroupsUseCase namespaces.FindConfigGroupsUseCase,
+	updateConfigGroupUseCase namespaces.UpdateConfigGroupUseCase,
+	deleteConfigGroupUseCase namespaces.DeleteConfigGroupUseCase,
+	createWebhookUseCase namespaces.CreateWebhookUseCase,
+	findWebhooksUseCase namespaces.FindWebhooksUseCase,
+	updateWebhookUseCase namespaces.UpdateWebhookUseCase,
+	deleteWebhookUseCase namespaces.DeleteWebhookUseCase,
 ) NamespaceController {
 	return NamespaceController{
-		createNamespaceUseCase:           createNamespaceUseCase,
-		findNamespacesUseCase:            findNamespacesUseCase,
-		findNamespaceByIDUseCase:         findNamespaceByIDUseCase,
-		createNamespaceMembershipUseCase: createNamespaceMembershipUseCase,
-		removeNamespaceMembershipUseCase: removeNamespaceMembershipUseCase,
-		deleteNamespaceByIDUseCase:       deleteNamespaceByIDUseCase,
-		updateNamespaceByIDUseCase:       updateNamespaceByIDUseCase,
-		fillApplicationsStatusUseCase:    fillApplicationsStatusUseCase,
+		createNamespaceUseCase:                  createNamespaceUseCase,
+		findNamespacesUseCase:                   findNamespacesUseCase,
+		findNamespaceByIDUseCase:                findNamespaceByIDUseCase,
+		createNamespaceMembershipUseCase:        createNamespaceMembershipUseCase,
+		removeNamespaceMembershipUseCase:        removeNamespaceMembershipUseCase,
+		deleteNamespaceByIDUseCase:              deleteNamespaceByIDUseCase,
+		updateNamespaceByIDUseCase:              updateNamespaceByIDUseCase,
+		fillApplicationsStatusUseCase:           fillApplicationsStatusUseCase,
+		exportNamespaceManifestUseCase:          exportNamespaceManifestUseCase,
+		applyNamespaceManifestUseCase:           applyNamespaceManifestUseCase,
+		findAuditEventsUseCase:                  findAuditEventsUseCase,
+		createNamespaceInvitationUseCase:        createNamespaceInvitationUseCase,
+		findNamespaceInvitationsUseCase:         findNamespaceInvitationsUseCase,
+		revokeNamespaceInvitationUseCase:        revokeNamespaceInvitationUseCase,
+		acceptNamespaceInvitationUseCase:        acceptNamespaceInvitationUseCase,
+		declineNamespaceInvitationUseCase:       declineNamespaceInvitationUseCase,
+		updateNamespaceMembershipUseCase:        updateNamespaceMembershipUseCase,
+		transferNamespaceOwnershipUseCase:       transferNamespaceOwnershipUseCase,
+		acceptNamespaceOwnershipTransferUseCase: acceptNamespaceOwnershipTransferUseCase,
+		cancelNamespaceOwnershipTransferUseCase: cancelNamespaceOwnershipTransferUseCase,
+		createNetworkRuleUseCase:                createNetworkRuleUseCase,
+		findNetworkRulesUseCase:                 findNetworkRulesUseCase,
+		deleteNetworkRuleUseCase:                deleteNetworkRuleUseCase,
+		createRegistryCredentialUseCase:         createRegistryCredentialUseCase,
+		findRegistryCredentialsUseCase:          findRegistryCredentialsUseCase,
+		updateRegistryCredentialUseCase:         updateRegistryCredentialUseCase,
+		deleteRegistryCredentialUseCase:         deleteRegistryCredentialUseCase,
+		updateImageScanPolicyUseCase:            updateImageScanPolicyUseCase,
+		findNamespaceServicesUseCase:            findNamespaceServicesUseCase,
+		createAddonUseCase:                      createAddonUseCase,
+		findAddonsUseCase:                       findAddonsUseCase,
+		deleteAddonUseCase:                      deleteAddonUseCase,
+		bindAddonUseCase:                        bindAddonUseCase,
+		unbindAddonUseCase:                      unbindAddonUseCase,
+		createConfigGroupUseCase:                createConfigGroupUseCase,
+		findConfigGroupsUseCase:                 findConfigGroupsUseCase,
+		updateConfigGroupUseCase:                updateConfigGroupUseCase,
+		deleteConfigGroupUseCase:                deleteConfigGroupUseCase,
+		createWebhookUseCase:                    createWebhookUseCase,
+		findWebhooksUseCase:                     findWebhooksUseCase,
+		updateWebhookUseCase:                    updateWebhookUseCase,
+		deleteWebhookUseCase:                    deleteWebhookUseCase,
 	}
 }
 
 func (namespaceController NamespaceController) CreateNamespaceController(c *gin.Context) {
-	if !validators.ValidateAuthorizationToken(c) {
+	userID, ok := validators.AuthenticatedUserID(c)
+	if !ok {
 		validators.Unauthorized(c)
 		return
 	}
 
+	// An access token restricted to a namespace cannot create other namespaces
+	if !validators.AccessTokenAllowsNamespace(c, "") {
+		return
+	}
+
 	if !validators.ValidateBodyIsNotNullNorEmpty(c) {
 		validators.BodyIsNullOrEmptyResponse(c)
 		return
@@ -77,7 +189,8 @@ func (namespaceController NamespaceController) CreateNamespaceController(c *gin.
 	createNamespace := commands.CreateNamespace{
 		Name:        createNamespaceRequest.Name,
 		Description: createNamespaceRequest.Description,
-		UserID:      createNamespaceRequest.UserID,
+		UserID:      userID,
+		Actor:       validators.AuditActor(c),
 	}
 	namespace, err := namespaceController.createNamespaceUseCase.Execute(createNamespace)
 	if err != nil {
@@ -95,7 +208,8 @@ func (namespaceController NamespaceController) CreateNamespaceController(c *gin.
 }
 
 func (namespaceController NamespaceController) FindNamespacesController(c *gin.Context) {
-	if !validators.ValidateAuthorizationToken(c) {
+	userID, ok := validators.AuthenticatedUserID(c)
+	if !ok {
 		validators.Unauthorized(c)
 		return
 	}
@@ -117,23 +231,14 @@ func (namespaceController NamespaceController) FindNamespacesController(c *gin.C
 
 	findNamespaces := commands.FindNamespaces{
 		Name:    findNamespacesRequest.Name,
-		UserID:  findNamespacesRequest.UserID,
+		UserID:  userID,
 		Page:    findNamespacesRequest.Page,
 		PerPage: findNamespacesRequest.PerPage,
 	}
 	foundNamespaces, err := namespaceController.findNamespacesUseCase.Execute(findNamespaces)
 	if err != nil {
-		if _, ok := err.(*errors.UnauthorizedToAccessNamespaceError); ok {
-			c.JSON(http.StatusForbidden, errors.NewApiError(
-				http.StatusForbidden,
-				"user_unauthorized_to_access_namespace",
-				fmt.Sprintf("The user with ID %s is not authorized to access the namespace with name %s", findNamespaces.UserID, *findNamespaces.Name),
-				"If user should be authorized to access the namespace, please contact the namespace admin(s) to grant access to the user",
-				c,
-				map[string]interface{}{
-					"query_params": c.Request.URL.Query(),
-				},
-			))
+		if apiError, ok := errors.NewForbiddenApiError(err, c); ok {
+			c.JSON(apiError.StatusCode, apiError)
 			return
 		}
 		if _, ok := err.(*errors.NamespaceNotFoundByNameError); ok {
@@ -162,38 +267,28 @@ func (namespaceController NamespaceController) FindNamespacesController(c *gin.C
 		))
 		return
 	}
+	for _, foundNamespace := range foundNamespaces {
+		if !validators.AccessTokenAllowsNamespace(c, foundNamespace.ID) {
+			return
+		}
+	}
 	c.JSON(http.StatusOK, gin.H{
 		"namespaces": foundNamespaces,
 	})
 }
 
 func (namespaceController NamespaceController) FindNamespaceByIDController(c *gin.Context) {
-	if !validators.ValidateAuthorizationToken(c) {
+	userID, ok := validators.AuthenticatedUserID(c)
+	if !ok {
 		validators.Unauthorized(c)
 		return
 	}
 
 	namespaceID := c.Param("id")
-	userID := c.Query("userId")
-	if userID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "userId query param is required"})
-		return
-	}
 	foundNamespace, userApplications, err := namespaceController.findNamespaceByIDUseCase.Execute(namespaceID, userID)
 	if err != nil {
-		if _, ok := err.(*errors.UnauthorizedToAccessNamespaceError); ok {
-			c.JSON(http.StatusForbidden, errors.NewApiError(
-				http.StatusForbidden,
-				"user_unauthorized_to_access_namespace",
-				fmt.Sprintf("The user with ID %s is not authorized to access the namespace with ID %s", userID, namespaceID),
-				"If user should be authorized to access the namespace, please contact the namespace admin(s) to grant access to the user",
-				c,
-				map[string]interface{}{
-					"query_params": c.Request.URL.Query(),
-					"namespace_id": namespaceID,
-					"userId":       userID,
-				},
-			))
+		if apiError, ok := errors.NewForbiddenApiError(err, c); ok {
+			c.JSON(apiError.StatusCode, apiError)
 			return
 		}
 		fmt.Printf("Error while finding namespace by id: %s", err.Error())
@@ -222,22 +317,21 @@ func (namespaceController NamespaceController) FindNamespaceByIDController(c *gi
 }
 
 func (namespaceController NamespaceController) DeleteNamespaceByIDController(c *gin.Context) {
-	if !validators.ValidateAuthorizationToken(c) {
+	userID, ok := validators.AuthenticatedUserID(c)
+	if !ok {
 		validators.Unauthorized(c)
 		return
 	}
 
 	namespaceID := c.Param("id")
 
-	userID := c.Query("userId")
-	if userID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "userId query param is required"})
-		return
-	}
-
-	namespace, err := namespaceController.deleteNamespaceByIDUseCase.Execute(namespaceID, userID)
+	namespace, err := namespaceController.deleteNamespaceByIDUseCase.Execute(namespaceID, userID, validators.AuditActor(c))
 	if err != nil {
 		fmt.Println(err)
+		if apiError, ok := errors.NewForbiddenApiError(err, c); ok {
+			c.JSON(apiError.StatusCode, apiError)
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -248,7 +342,8 @@ func (namespaceController NamespaceController) DeleteNamespaceByIDController(c *
 }
 
 func (namespaceController NamespaceController) AddMemberToNamespaceController(c *gin.Context) {
-	if !validators.ValidateAuthorizationToken(c) {
+	userID, ok := validators.AuthenticatedUserID(c)
+	if !ok {
 		validators.Unauthorized(c)
 		return
 	}
@@ -264,10 +359,15 @@ func (namespaceController NamespaceController) AddMemberToNamespaceController(c
 		NamespaceID: namespaceID,
 		UserID:      addMemberToNamespaceRequest.UserID,
 		Role:        addMemberToNamespaceRequest.Role,
-		AddedBy:     addMemberToNamespaceRequest.AddedBy,
+		AddedBy:     userID,
+		Actor:       validators.AuditActor(c),
 	}
 	namespaceMembership, err := namespaceController.createNamespaceMembershipUseCase.Execute(createNamespaceMembership)
 	if err != nil {
+		if apiError, ok := errors.NewForbiddenApiError(err, c); ok {
+			c.JSON(apiError.StatusCode, apiError)
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -278,62 +378,44 @@ func (namespaceController NamespaceController) AddMemberToNamespaceController(c
 }
 
 func (namespaceController NamespaceController) RemoveMemberFromNamespaceController(c *gin.Context) {
-	if !validators.ValidateAuthorizationToken(c) {
+	removedBy, ok := validators.AuthenticatedUserID(c)
+	if !ok {
 		validators.Unauthorized(c)
 		return
 	}
 
 	namespaceID := c.Param("id")
 	userID := c.Param("userId")
-	removedBy := c.Query("removedBy")
-	if removedBy == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "removedBy query param is required"})
-		return
-	}
 
 	namespaceMembership, err := namespaceController.removeNamespaceMembershipUseCase.Execute(commands.RemoveNamespaceMembership{
 		NamespaceID: namespaceID,
 		UserID:      userID,
 		RemovedBy:   removedBy,
+		Actor:       validators.AuditActor(c),
 	})
 	if err != nil {
 		fmt.Printf("Error while removing member from namespace: %s", err.Error())
-
-		status := http.StatusInternalServerError
-		errorName := "internal_server_error"
-		errorMessage := "An internal server error occurred while trying to remove the member from the namespace"
-		description := "Please try again later or contact support"
-		contextualInformation := map[string]interface{}{
-			"queryParams":  c.Request.URL.Query(),
-			"namespace_id": namespaceID,
-			"userId":       userID,
-			"removedBy":    removedBy,
-		}
-		if _, ok := err.(*errors.UnauthorizedToAccessNamespaceError); ok {
-			status = http.StatusForbidden
-			errorName = "unauthorized_to_access_namespace"
-			errorMessage = fmt.Sprintf("The user with id %s is not authorized to access the namespace with id %s", userID, namespaceID)
-			description = "Please try again with a different namespace id"
-		}
-		if _, ok := err.(*errors.NotAdminInNamespaceError); ok {
-			status = http.StatusForbidden
-			errorName = "not_admin_in_namespace"
-			errorMessage = fmt.Sprintf("The user with id %s is not an admin in the namespace with id %s", removedBy, namespaceID)
-			description = "Maybe try again with a different namespace id or ask admin to remove this user from the namespace"
+		if apiError, ok := errors.NewForbiddenApiError(err, c); ok {
+			c.JSON(apiError.StatusCode, apiError)
+			return
 		}
-		if _, ok := err.(*errors.UnauthorizedToRemoveAdminFromNamespaceError); ok {
-			status = http.StatusForbidden
-			errorName = "unauthorized_to_remove_admin_from_namespace"
-			errorMessage = fmt.Sprintf("The user with id %s is not authorized to remove an admin from the namespace with id %s", removedBy, namespaceID)
-			description = "Please try again with a different namespace id. Because you cannot remove another admin from the namespace"
+		if apiError, ok := newNamespaceMembershipConflictApiError(err, c); ok {
+			c.JSON(apiError.StatusCode, apiError)
+			return
 		}
+
 		c.JSON(http.StatusInternalServerError, errors.NewApiError(
-			status,
-			errorName,
-			errorMessage,
-			description,
+			http.StatusInternalServerError,
+			"internal_server_error",
+			"An internal server error occurred while trying to remove the member from the namespace",
+			"Please try again later or contact support",
 			c,
-			contextualInformation,
+			map[string]interface{}{
+				"queryParams":  c.Request.URL.Query(),
+				"namespace_id": namespaceID,
+				"userId":       userID,
+				"removedBy":    removedBy,
+			},
 		))
 		return
 	}
@@ -344,13 +426,13 @@ func (namespaceController NamespaceController) RemoveMemberFromNamespaceControll
 }
 
 func (namespaceController NamespaceController) UpdateNamespaceByIDController(c *gin.Context) {
-	if !validators.ValidateAuthorizationToken(c) {
+	userID, ok := validators.AuthenticatedUserID(c)
+	if !ok {
 		validators.Unauthorized(c)
 		return
 	}
 
 	namespaceID := c.Param("id")
-	userID := c.Query("userId")
 	var updateNamespaceRequest requests.UpdateNamespaceByIDRequest
 	if err := c.ShouldBindJSON(&updateNamespaceRequest); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"validation-errors": err.Error()})
@@ -359,11 +441,16 @@ func (namespaceController NamespaceController) UpdateNamespaceByIDController(c *
 
 	updateNamespace := commands.UpdateNamespace{
 		ID:          namespaceID,
-		UserID:      updateNamespaceRequest.UserID,
+		UserID:      userID,
 		Description: updateNamespaceRequest.Description,
+		Actor:       validators.AuditActor(c),
 	}
 	namespace, err := namespaceController.updateNamespaceByIDUseCase.Execute(updateNamespace, userID)
 	if err != nil {
+		if apiError, ok := errors.NewForbiddenApiError(err, c); ok {
+			c.JSON(apiError.StatusCode, apiError)
+			return
+		}
 		if _, ok := err.(*errors.NamespaceNotFoundByIDError); ok {
 			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 			return
@@ -376,3 +463,253 @@ func (namespaceController NamespaceController) UpdateNamespaceByIDController(c *
 		"namespace": namespace,
 	})
 }
+
+// ExportNamespaceManifestController godoc
+// @Summary Exports the manifest of a namespace
+// @Description exports the applications of a namespace as a versioned manifest, in JSON or in YAML with ?format=yaml. Secret values are never exported.
+// @ID export-namespace-manifest
+// @Tags Namespaces
+// @Produce  json
+// @Produce  application/yaml
+// @Param Authorization header string true "Authorization Token"
+// @Param id path string true "Namespace ID"
+// @Param format query string false "json or yaml"
+// @Success 200 {object} domain.NamespaceManifest
+// @Failure 403 {object} errors.ApiError
+// @Router /namespaces/{id}/manifest [get]
+func (namespaceController NamespaceController) ExportNamespaceManifestController(c *gin.Context) {
+	userID, ok := validators.AuthenticatedUserID(c)
+	if !ok {
+		validators.Unauthorized(c)
+		return
+	}
+
+	namespaceID := c.Param("id")
+
+	manifest, err := namespaceController.exportNamespaceManifestUseCase.Execute(namespaceID, userID)
+	if err != nil {
+		if _, ok := err.(*errors.NamespaceNotFoundByIDError); ok {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		if apiError, ok := errors.NewForbiddenApiError(err, c); ok {
+			c.JSON(apiError.StatusCode, apiError)
+			return
+		}
+		fmt.Printf("Error while exporting namespace manifest: %s", err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if c.Query("format") == "yaml" || strings.Contains(c.GetHeader("Accept"), "yaml") {
+		manifestYAML, err := yaml.Marshal(manifest)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.Data(http.StatusOK, "application/yaml", manifestYAML)
+		return
+	}
+
+	c.JSON(http.StatusOK, manifest)
+}
+
+// ApplyNamespaceManifestController godoc
+// @Summary Applies a manifest to a namespace
+// @Description creates, updates and, with ?prune=true, deletes the applications of a namespace so that they match the manifest. The body can be YAML or JSON. The changed applications are deployed or removed by the operations of the result.
+// @ID apply-namespace-manifest
+// @Tags Namespaces
+// @Accept  json
+// @Accept  application/yaml
+// @Produce  json
+// @Param Authorization header string true "Authorization Token"
+// @Param id path string true "Namespace ID"
+// @Param prune query bool false "Delete applications missing from the manifest"
+// @Param manifest body domain.NamespaceManifest true "Namespace manifest"
+// @Success 202 {object} domain.NamespaceManifestApplyResult
+// @Failure 400 {object} errors.ApiError
+// @Failure 409 {object} errors.ApiError
+// @Router /namespaces/{id}/manifest/apply [post]
+func (namespaceController NamespaceController) ApplyNamespaceManifestController(c *gin.Context) {
+	userID, ok := validators.AuthenticatedUserID(c)
+	if !ok {
+		validators.Unauthorized(c)
+		return
+	}
+
+	namespaceID := c.Param("id")
+	prune := c.Query("prune") == "true"
+
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil || len(body) == 0 {
+		validators.BodyIsNullOrEmptyResponse(c)
+		return
+	}
+
+	// JSON is valid YAML, so the same decoder handles both formats
+	var manifest domain.NamespaceManifest
+	if err := yaml.UnmarshalStrict(body, &manifest); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"validation-errors": err.Error()})
+		return
+	}
+
+	result, err := namespaceController.applyNamespaceManifestUseCase.Execute(commands.ApplyNamespaceManifest{
+		NamespaceID: namespaceID,
+		UserID:      userID,
+		Manifest:    manifest,
+		Prune:       prune,
+		Actor:       validators.AuditActor(c),
+	})
+	if err != nil {
+		fmt.Printf("Error while applying namespace manifest: %s", err.Error())
+		switch err.(type) {
+		case *errors.InvalidNamespaceManifestError,
+			*errors.InvalidApplicationEnvironmentVariablesError,
+			*errors.InvalidApplicationSecretsError,
+			*errors.InvalidApplicationIngressError,
+			*errors.InvalidApplicationDependenciesError,
+			*errors.InvalidApplicationContainerSpecificationsError,
+			*errors.InvalidApplicationScalabilitySpecificationsError,
+			*errors.InvalidApplicationConfigFilesError,
+			*errors.InvalidRegistryCredentialError:
+			c.JSON(http.StatusBadRequest, gin.H{"validation-errors": err.Error()})
+			return
+		}
+		switch err.(type) {
+		case *errors.NamespaceHasReachedMaxNumberOfApplicationsError,
+			*errors.UserHasReachedMaxNumberOfApplicationsError:
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		if _, ok := err.(*errors.NamespaceNotFoundByIDError); ok {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		if apiError, ok := errors.NewForbiddenApiError(err, c); ok {
+			c.JSON(apiError.StatusCode, apiError)
+			return
+		}
+		if apiError, ok := errors.NewImageApiError(err, c); ok {
+			apiError.Context.(map[string]interface{})["result"] = result
+			c.JSON(apiError.StatusCode, apiError)
+			return
+		}
+		// The applications already reconciled are reported so that the manifest can be fixed and applied again
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error(), "result": result})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"result": result,
+	})
+}
+
+// FindAuditEventsController godoc
+// @Summary Finds the audit events of a namespace
+// @Description returns the actions performed on a namespace and its applications, the most recent first. With ?format=jsonl every matching event is exported as JSON lines.
+// @ID find-audit-events
+// @Tags Namespaces
+// @Produce  json
+// @Produce  application/x-ndjson
+// @Param Authorization header string true "Authorization Token"
+// @Param id path string true "Namespace ID"
+// @Param action query string false "Action of the events"
+// @Param actorId query string false "User that performed the actions"
+// @Param targetId query string false "Namespace, user or application the actions were performed on"
+// @Param outcome query string false "success, failure or denied"
+// @Param from query string false "RFC 3339 date of the oldest events"
+// @Param to query string false "RFC 3339 date after the most recent events"
+// @Param page query int false "Page, starting at 1"
+// @Param limit query int false "Number of events by page, at most 500"
+// @Param format query string false "json or jsonl"
+// @Success 200 {array} domain.AuditEvent
+// @Failure 403 {object} errors.ApiError
+// @Router /namespaces/{id}/audit [get]
+func (namespaceController NamespaceController) FindAuditEventsController(c *gin.Context) {
+	userID, ok := validators.AuthenticatedUserID(c)
+	if !ok {
+		validators.Unauthorized(c)
+		return
+	}
+
+	var findAuditEventsRequest requests.FindAuditEventsRequest
+	if err := c.ShouldBindQuery(&findAuditEventsRequest); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"validation-errors": err.Error()})
+		return
+	}
+
+	findAuditEvents := commands.FindAuditEvents{
+		NamespaceID: c.Param("id"),
+		UserID:      userID,
+		Action:      findAuditEventsRequest.Action,
+		ActorID:     findAuditEventsRequest.ActorID,
+		TargetID:    findAuditEventsRequest.TargetID,
+		Outcome:     findAuditEventsRequest.Outcome,
+		From:        findAuditEventsRequest.From,
+		To:          findAuditEventsRequest.To,
+		Page:        findAuditEventsRequest.Page,
+		Limit:       findAuditEventsRequest.Limit,
+	}
+	if findAuditEvents.Page == 0 {
+		findAuditEvents.Page = 1
+	}
+	if findAuditEvents.Limit == 0 {
+		findAuditEvents.Limit = 100
+	}
+
+	if c.Query("format") == "jsonl" || strings.Contains(c.GetHeader("Accept"), "ndjson") {
+		namespaceController.exportAuditEvents(c, findAuditEvents)
+		return
+	}
+
+	auditEvents, err := namespaceController.findAuditEventsUseCase.Execute(findAuditEvents)
+	if err != nil {
+		namespaceController.findAuditEventsError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"auditEvents": auditEvents,
+		"page":        findAuditEvents.Page,
+		"limit":       findAuditEvents.Limit,
+	})
+}
+
+// exportAuditEvents writes every event matching the filters as JSON lines, ignoring the pagination of the request
+func (namespaceController NamespaceController) exportAuditEvents(c *gin.Context, findAuditEvents commands.FindAuditEvents) {
+	encoder := json.NewEncoder(c.Writer)
+	headerWritten := false
+	err := namespaceController.findAuditEventsUseCase.Export(findAuditEvents, func(auditEvent domain.AuditEvent) error {
+		if !headerWritten {
+			c.Header("Content-Type", "application/x-ndjson")
+			c.Status(http.StatusOK)
+			headerWritten = true
+		}
+		return encoder.Encode(auditEvent)
+	})
+	if err != nil {
+		if headerWritten {
+			// The response has started, the client notices the truncated export
+			fmt.Printf("Error while exporting audit events: %s", err.Error())
+			return
+		}
+		namespaceController.findAuditEventsError(c, err)
+		return
+	}
+	if !headerWritten {
+		c.Data(http.StatusOK, "application/x-ndjson", []byte{})
+	}
+}
+
+func (namespaceController NamespaceController) findAuditEventsError(c *gin.Context, err error) {
+	if _, ok := err.(*errors.NamespaceNotFoundByIDError); ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if apiError, ok := errors.NewForbiddenApiError(err, c); ok {
+		c.JSON(apiError.StatusCode, apiError)
+		return
+	}
+	fmt.Printf("Error while finding audit events: %s", err.Error())
+	c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+}
@@ -1,6 +1,8 @@
 package namespaces
 
 import (
+	"cloud-app-hive/controllers/validators"
+	"cloud-app-hive/domain"
 	"cloud-app-hive/use_cases/applications"
 	"cloud-app-hive/use_cases/namespaces"
 	"github.com/gin-gonic/gin"
@@ -16,6 +18,40 @@ func InitNamespacesRoutes(
 	deleteNamespaceByIDUseCase namespaces.DeleteNamespaceByIDUseCase,
 	updateNamespaceByIDUseCase namespaces.UpdateNamespaceByIDUseCase,
 	fillApplicationsStatusUseCase applications.FillApplicationStatusUseCase,
+	exportNamespaceManifestUseCase namespaces.ExportNamespaceManifestUseCase,
+	applyNamespaceManifestUseCase namespaces.ApplyNamespaceManifestUseCase,
+	findAuditEventsUseCase namespaces.FindAuditEventsUseCase,
+	createNamespaceInvitationUseCase namespaces.CreateNamespaceInvitationUseCase,
+	findNamespaceInvitationsUseCase namespaces.FindNamespaceInvitationsUseCase,
+	revokeNamespaceInvitationUseCase namespaces.RevokeNamespaceInvitationUseCase,
+	acceptNamespaceInvitationUseCase namespaces.AcceptNamespaceInvitationUseCase,
+	declineNamespaceInvitationUseCase namespaces.DeclineNamespaceInvitationUseCase,
+	updateNamespaceMembershipUseCase namespaces.UpdateNamespaceMembershipUseCase,
+	transferNamespaceOwnershipUseCase namespaces.TransferNamespaceOwnershipUseCase,
+	acceptNamespaceOwnershipTransferUseCase namespaces.AcceptNamespaceOwnershipTransferUseCase,
+	cancelNamespaceOwnershipTransferUseCase namespaces.CancelNamespaceOwnershipTransferUseCase,
+	createNetworkRuleUseCase namespaces.CreateNetworkRuleUseCase,
+	findNetworkRulesUseCase namespaces.FindNetworkRulesUseCase,
+	deleteNetworkRuleUseCase namespaces.DeleteNetworkRuleUseCase,
+	createRegistryCredentialUseCase namespaces.CreateRegistryCredentialUseCase,
+	findRegistryCredentialsUseCase namespaces.FindRegistryCredentialsUseCase,
+	updateRegistryCredentialUseCase namespaces.UpdateRegistryCredentialUseCase,
+	deleteRegistryCredentialUseCase namespaces.DeleteRegistryCredentialUseCase,
+	updateImageScanPolicyUseCase namespaces.UpdateImageScanPolicyUseCase,
+	findNamespaceServicesUseCase namespaces.FindNamespaceServicesUseCase,
+	createAddonUseCase namespaces.CreateAddonUseCase,
+	findAddonsUseCase namespaces.FindAddonsUseCase,
+	deleteAddonUseCase namespaces.DeleteAddonUseCase,
+	bindAddonUseCase namespaces.BindAddonUseCase,
+	unbindAddonUseCase namespaces.UnbindAddonUseCase,
+	createConfigGroupUseCase namespaces.CreateConfigGroupUseCase,
+	findConfigGroupsUseCase namespaces.FindConfigGroupsUseCase,
+	updateConfigGroupUseCase namespaces.UpdateConfigGroupUseCase,
+	deleteConfigGroupUseCase namespaces.DeleteConfigGroupUseCase,
+	createWebhookUseCase namespaces.CreateWebhookUseCase,
+	findWebhooksUseCase namespaces.FindWebhooksUseCase,
+	updateWebhookUseCase namespaces.UpdateWebhookUseCase,
+	deleteWebhookUseCase namespaces.DeleteWebhookUseCase,
 ) {
 	namespaceController := NewNamespaceController(
 		createNamespaceUseCase,
@@ -26,14 +62,102 @@ func InitNamespacesRoutes(
 		deleteNamespaceByIDUseCase,
 		updateNamespaceByIDUseCase,
 		fillApplicationsStatusUseCase,
+		exportNamespaceManifestUseCase,
+		applyNamespaceManifestUseCase,
+		findAuditEventsUseCase,
+		createNamespaceInvitationUseCase,
+		findNamespaceInvitationsUseCase,
+		revokeNamespaceInvitationUseCase,
+		acceptNamespaceInvitationUseCase,
+		declineNamespaceInvitationUseCase,
+		updateNamespaceMembershipUseCase,
+		transferNamespaceOwnershipUseCase,
+		acceptNamespaceOwnershipTransferUseCase,
+		cancelNamespaceOwnershipTransferUseCase,
+		createNetworkRuleUseCase,
+		findNetworkRulesUseCase,
+		deleteNetworkRuleUseCase,
+		createRegistryCredentialUseCase,
+		findRegistryCredentialsUseCase,
+		updateRegistryCredentialUseCase,
+		deleteRegistryCredentialUseCase,
+		updateImageScanPolicyUseCase,
+		findNamespaceServicesUseCase,
+		createAddonUseCase,
+		findAddonsUseCase,
+		deleteAddonUseCase,
+		bindAddonUseCase,
+		unbindAddonUseCase,
+		createConfigGroupUseCase,
+		findConfigGroupsUseCase,
+		updateConfigGroupUseCase,
+		deleteConfigGroupUseCase,
+		createWebhookUseCase,
+		findWebhooksUseCase,
+		updateWebhookUseCase,
+		deleteWebhookUseCase,
 	)
 
-	router.POST("/namespaces", namespaceController.CreateNamespaceController)
-	router.GET("/namespaces", namespaceController.FindNamespacesController)
-	router.GET("/namespaces/:id", namespaceController.FindNamespaceByIDController)
-	router.DELETE("/namespaces/:id", namespaceController.DeleteNamespaceByIDController)
-	router.PUT("/namespaces/:id", namespaceController.UpdateNamespaceByIDController)
+	readScope := validators.RequireScope(domain.ScopeNamespacesRead)
+	adminScope := validators.RequireScope(domain.ScopeNamespacesAdmin)
+	// Binding an add-on changes the secrets of an application and deploys it again
+	deployScope := validators.RequireScope(domain.ScopeApplicationsDeploy)
+	namespaceParam := validators.RestrictAccessTokenToNamespaceParam("id")
 
-	router.POST("/namespaces/:id/memberships", namespaceController.AddMemberToNamespaceController)
-	router.DELETE("/namespaces/:id/memberships/:userId", namespaceController.RemoveMemberFromNamespaceController)
+	router.POST("/namespaces", adminScope, namespaceController.CreateNamespaceController)
+	router.GET("/namespaces", readScope, namespaceController.FindNamespacesController)
+	router.GET("/namespaces/:id", readScope, namespaceParam, namespaceController.FindNamespaceByIDController)
+	router.DELETE("/namespaces/:id", adminScope, namespaceParam, namespaceController.DeleteNamespaceByIDController)
+	router.PUT("/namespaces/:id", adminScope, namespaceParam, namespaceController.UpdateNamespaceByIDController)
+
+	router.POST("/namespaces/:id/memberships", adminScope, namespaceParam, namespaceController.AddMemberToNamespaceController)
+	router.PUT("/namespaces/:id/memberships/:userId", adminScope, namespaceParam, namespaceController.UpdateMemberRoleController)
+	router.DELETE("/namespaces/:id/memberships/:userId", adminScope, namespaceParam, namespaceController.RemoveMemberFromNamespaceController)
+
+	// Ownership is transferred between signed in users only, an access token cannot give a namespace away
+	router.POST("/namespaces/:id/ownership-transfer", validators.RequireUserSession, namespaceController.TransferNamespaceOwnershipController)
+	router.POST("/namespaces/:id/ownership-transfer/accept", validators.RequireUserSession, namespaceController.AcceptNamespaceOwnershipTransferController)
+	router.DELETE("/namespaces/:id/ownership-transfer", validators.RequireUserSession, namespaceController.CancelNamespaceOwnershipTransferController)
+
+	router.POST("/namespaces/:id/invitations", adminScope, namespaceParam, namespaceController.InviteMemberToNamespaceController)
+	router.GET("/namespaces/:id/invitations", adminScope, namespaceParam, namespaceController.FindNamespaceInvitationsController)
+	router.DELETE("/namespaces/:id/invitations/:invitationId", adminScope, namespaceParam, namespaceController.RevokeNamespaceInvitationController)
+	// Invitations are answered by signed in users, an access token cannot join a namespace
+	router.POST("/invitations/:token/accept", validators.RequireUserSession, namespaceController.AcceptNamespaceInvitationController)
+	router.POST("/invitations/:token/decline", validators.RequireUserSession, namespaceController.DeclineNamespaceInvitationController)
+
+	router.POST("/namespaces/:id/network-rules", adminScope, namespaceParam, namespaceController.CreateNetworkRuleController)
+	router.GET("/namespaces/:id/network-rules", readScope, namespaceParam, namespaceController.FindNetworkRulesController)
+	router.DELETE("/namespaces/:id/network-rules/:ruleId", adminScope, namespaceParam, namespaceController.DeleteNetworkRuleController)
+
+	router.POST("/namespaces/:id/registry-credentials", adminScope, namespaceParam, namespaceController.CreateRegistryCredentialController)
+	router.GET("/namespaces/:id/registry-credentials", readScope, namespaceParam, namespaceController.FindRegistryCredentialsController)
+	router.PUT("/namespaces/:id/registry-credentials/:credentialId", adminScope, namespaceParam, namespaceController.UpdateRegistryCredentialController)
+	router.DELETE("/namespaces/:id/registry-credentials/:credentialId", adminScope, namespaceParam, namespaceController.DeleteRegistryCredentialController)
+	router.PUT("/namespaces/:id/image-scan-policy", adminScope, namespaceParam, namespaceController.UpdateImageScanPolicyController)
+
+	router.GET("/namespaces/:id/services", readScope, namespaceParam, namespaceController.FindNamespaceServicesController)
+
+	router.POST("/namespaces/:id/addons", adminScope, namespaceParam, namespaceController.CreateAddonController)
+	router.GET("/namespaces/:id/addons", readScope, namespaceParam, namespaceController.FindAddonsController)
+	router.DELETE("/namespaces/:id/addons/:addonId", adminScope, namespaceParam, namespaceController.DeleteAddonController)
+	router.POST("/namespaces/:id/addons/:addonId/bindings", deployScope, namespaceParam, namespaceController.BindAddonController)
+	router.DELETE("/namespaces/:id/addons/:addonId/bindings/:applicationId", deployScope, namespaceParam, namespaceController.UnbindAddonController)
+
+	router.POST("/namespaces/:id/config-groups", adminScope, namespaceParam, namespaceController.CreateConfigGroupController)
+	router.GET("/namespaces/:id/config-groups", readScope, namespaceParam, namespaceController.FindConfigGroupsController)
+	router.PUT("/namespaces/:id/config-groups/:configGroupId", adminScope, namespaceParam, namespaceController.UpdateConfigGroupController)
+	router.DELETE("/namespaces/:id/config-groups/:configGroupId", adminScope, namespaceParam, namespaceController.DeleteConfigGroupController)
+
+	// Webhooks and their delivery log describe every change of the namespace, they are restricted to its administrators
+	router.POST("/namespaces/:id/webhooks", adminScope, namespaceParam, namespaceController.CreateWebhookController)
+	router.GET("/namespaces/:id/webhooks", adminScope, namespaceParam, namespaceController.FindWebhooksController)
+	router.PUT("/namespaces/:id/webhooks/:webhookId", adminScope, namespaceParam, namespaceController.UpdateWebhookController)
+	router.DELETE("/namespaces/:id/webhooks/:webhookId", adminScope, namespaceParam, namespaceController.DeleteWebhookController)
+	router.GET("/namespaces/:id/webhooks/:webhookId/deliveries", adminScope, namespaceParam, namespaceController.FindWebhookDeliveriesController)
+
+	router.GET("/namespaces/:id/manifest", readScope, namespaceParam, namespaceController.ExportNamespaceManifestController)
+	router.POST("/namespaces/:id/manifest/apply", adminScope, namespaceParam, namespaceController.ApplyNamespaceManifestController)
+
+	router.GET("/namespaces/:id/audit", adminScope, namespaceParam, namespaceController.FindAuditEventsController)
 }
@@ -0,0 +1,152 @@
+package namespaces
+
+import (
+	"cloud-app-hive/controllers/errors"
+	"fmt"
+	"net/http"
+
+	"cloud-app-hive/controllers/namespaces/requests"
+	"cloud-app-hive/controllers/validators"
+	"cloud-app-hive/domain/commands"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CreateNetworkRuleController godoc
+// @Summary Allows traffic to the applications of a namespace
+// @Description namespaces only accept traffic from their own applications and from the ingress controller, a rule allows another namespace or one of its applications to reach every application of the namespace or only one
+// @ID create-network-rule
+// @Tags Namespaces
+// @Accept  json
+// @Produce  json
+// @Param Authorization header string true "Authorization Token"
+// @Param id path string true "Namespace ID"
+// @Param networkRule body requests.CreateNetworkRuleRequest true "Applications and namespace of the rule"
+// @Success 201 {object} domain.NetworkRule
+// @Failure 400 {object} errors.ApiError
+// @Failure 403 {object} errors.ApiError
+// @Failure 404 {object} errors.ApiError
+// @Router /namespaces/{id}/network-rules [post]
+func (namespaceController NamespaceController) CreateNetworkRuleController(c *gin.Context) {
+	userID, ok := validators.AuthenticatedUserID(c)
+	if !ok {
+		validators.Unauthorized(c)
+		return
+	}
+
+	var createNetworkRuleRequest requests.CreateNetworkRuleRequest
+	if err := c.ShouldBindJSON(&createNetworkRuleRequest); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"validation-errors": err.Error()})
+		return
+	}
+
+	networkRule, err := namespaceController.createNetworkRuleUseCase.Execute(commands.CreateNetworkRule{
+		NamespaceID:       c.Param("id"),
+		ApplicationID:     createNetworkRuleRequest.ApplicationID,
+		FromNamespaceID:   createNetworkRuleRequest.FromNamespaceID,
+		FromApplicationID: createNetworkRuleRequest.FromApplicationID,
+		Description:       createNetworkRuleRequest.Description,
+		CreatedBy:         userID,
+		Actor:             validators.AuditActor(c),
+	})
+	if err != nil {
+		networkRuleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"network_rule": networkRule,
+	})
+}
+
+// FindNetworkRulesController godoc
+// @Summary Finds the network rules of a namespace
+// @ID find-network-rules
+// @Tags Namespaces
+// @Produce  json
+// @Param Authorization header string true "Authorization Token"
+// @Param id path string true "Namespace ID"
+// @Success 200 {array} domain.NetworkRule
+// @Failure 403 {object} errors.ApiError
+// @Router /namespaces/{id}/network-rules [get]
+func (namespaceController NamespaceController) FindNetworkRulesController(c *gin.Context) {
+	userID, ok := validators.AuthenticatedUserID(c)
+	if !ok {
+		validators.Unauthorized(c)
+		return
+	}
+
+	networkRules, err := namespaceController.findNetworkRulesUseCase.Execute(c.Param("id"), userID)
+	if err != nil {
+		networkRuleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"network_rules": networkRules,
+	})
+}
+
+// DeleteNetworkRuleController godoc
+// @Summary Deletes a network rule
+// @Description the traffic the rule allowed is rejected again once the remaining rules are applied
+// @ID delete-network-rule
+// @Tags Namespaces
+// @Produce  json
+// @Param Authorization header string true "Authorization Token"
+// @Param id path string true "Namespace ID"
+// @Param ruleId path string true "Network rule ID"
+// @Success 200 {object} domain.NetworkRule
+// @Failure 403 {object} errors.ApiError
+// @Failure 404 {object} errors.ApiError
+// @Router /namespaces/{id}/network-rules/{ruleId} [delete]
+func (namespaceController NamespaceController) DeleteNetworkRuleController(c *gin.Context) {
+	userID, ok := validators.AuthenticatedUserID(c)
+	if !ok {
+		validators.Unauthorized(c)
+		return
+	}
+
+	networkRule, err := namespaceController.deleteNetworkRuleUseCase.Execute(commands.DeleteNetworkRule{
+		NamespaceID:   c.Param("id"),
+		NetworkRuleID: c.Param("ruleId"),
+		DeletedBy:     userID,
+		Actor:         validators.AuditActor(c),
+	})
+	if err != nil {
+		networkRuleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"network_rule": networkRule,
+	})
+}
+
+func networkRuleError(c *gin.Context, err error) {
+	if apiError, ok := errors.NewForbiddenApiError(err, c); ok {
+		c.JSON(apiError.StatusCode, apiError)
+		return
+	}
+
+	switch networkRuleErr := err.(type) {
+	case *errors.NamespaceNotFoundByIDError:
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case *errors.NetworkRuleNotFoundError:
+		c.JSON(http.StatusNotFound, errors.NewApiError(
+			http.StatusNotFound,
+			"network_rule_not_found",
+			"The network rule does not exist",
+			"Please check the ID of the network rule",
+			c,
+			map[string]interface{}{
+				"networkRuleId": networkRuleErr.NetworkRuleID,
+			},
+		))
+	case *errors.InvalidNetworkRuleError:
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		fmt.Printf("Error while handling network rule: %s", err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
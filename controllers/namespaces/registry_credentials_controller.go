@@ -0,0 +1,213 @@
+package namespaces
+
+import (
+	"cloud-app-hive/controllers/errors"
+	"fmt"
+	"net/http"
+
+	"cloud-app-hive/controllers/namespaces/requests"
+	"cloud-app-hive/controllers/validators"
+	"cloud-app-hive/domain/commands"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CreateRegistryCredentialController godoc
+// @Summary Registers the account of a namespace on a Docker registry
+// @Description applications of the namespace using the custom registry pull their image with one of its registry credentials, the password is stored encrypted and never returned
+// @ID create-registry-credential
+// @Tags Namespaces
+// @Accept  json
+// @Produce  json
+// @Param Authorization header string true "Authorization Token"
+// @Param id path string true "Namespace ID"
+// @Param registryCredential body requests.CreateRegistryCredentialRequest true "Registry and account of the credential"
+// @Success 201 {object} domain.RegistryCredential
+// @Failure 400 {object} errors.ApiError
+// @Failure 403 {object} errors.ApiError
+// @Failure 404 {object} errors.ApiError
+// @Router /namespaces/{id}/registry-credentials [post]
+func (namespaceController NamespaceController) CreateRegistryCredentialController(c *gin.Context) {
+	userID, ok := validators.AuthenticatedUserID(c)
+	if !ok {
+		validators.Unauthorized(c)
+		return
+	}
+
+	var createRegistryCredentialRequest requests.CreateRegistryCredentialRequest
+	if err := c.ShouldBindJSON(&createRegistryCredentialRequest); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"validation-errors": err.Error()})
+		return
+	}
+
+	registryCredential, err := namespaceController.createRegistryCredentialUseCase.Execute(commands.CreateRegistryCredential{
+		NamespaceID: c.Param("id"),
+		Name:        createRegistryCredentialRequest.Name,
+		Server:      createRegistryCredentialRequest.Server,
+		Username:    createRegistryCredentialRequest.Username,
+		Password:    createRegistryCredentialRequest.Password,
+		CreatedBy:   userID,
+		Actor:       validators.AuditActor(c),
+	})
+	if err != nil {
+		registryCredentialError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"registry_credential": registryCredential,
+	})
+}
+
+// FindRegistryCredentialsController godoc
+// @Summary Finds the registry credentials of a namespace, without their password
+// @ID find-registry-credentials
+// @Tags Namespaces
+// @Produce  json
+// @Param Authorization header string true "Authorization Token"
+// @Param id path string true "Namespace ID"
+// @Success 200 {array} domain.RegistryCredential
+// @Failure 403 {object} errors.ApiError
+// @Router /namespaces/{id}/registry-credentials [get]
+func (namespaceController NamespaceController) FindRegistryCredentialsController(c *gin.Context) {
+	userID, ok := validators.AuthenticatedUserID(c)
+	if !ok {
+		validators.Unauthorized(c)
+		return
+	}
+
+	registryCredentials, err := namespaceController.findRegistryCredentialsUseCase.Execute(c.Param("id"), userID)
+	if err != nil {
+		registryCredentialError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"registry_credentials": registryCredentials,
+	})
+}
+
+// UpdateRegistryCredentialController godoc
+// @Summary Updates a registry credential
+// @Description the applications using the credential are applied again with the new account, the password is kept when empty
+// @ID update-registry-credential
+// @Tags Namespaces
+// @Accept  json
+// @Produce  json
+// @Param Authorization header string true "Authorization Token"
+// @Param id path string true "Namespace ID"
+// @Param credentialId path string true "Registry credential ID"
+// @Param registryCredential body requests.UpdateRegistryCredentialRequest true "Registry and account of the credential"
+// @Success 200 {object} domain.RegistryCredential
+// @Failure 400 {object} errors.ApiError
+// @Failure 403 {object} errors.ApiError
+// @Failure 404 {object} errors.ApiError
+// @Router /namespaces/{id}/registry-credentials/{credentialId} [put]
+func (namespaceController NamespaceController) UpdateRegistryCredentialController(c *gin.Context) {
+	userID, ok := validators.AuthenticatedUserID(c)
+	if !ok {
+		validators.Unauthorized(c)
+		return
+	}
+
+	var updateRegistryCredentialRequest requests.UpdateRegistryCredentialRequest
+	if err := c.ShouldBindJSON(&updateRegistryCredentialRequest); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"validation-errors": err.Error()})
+		return
+	}
+
+	registryCredential, err := namespaceController.updateRegistryCredentialUseCase.Execute(commands.UpdateRegistryCredential{
+		NamespaceID:          c.Param("id"),
+		RegistryCredentialID: c.Param("credentialId"),
+		Server:               updateRegistryCredentialRequest.Server,
+		Username:             updateRegistryCredentialRequest.Username,
+		Password:             updateRegistryCredentialRequest.Password,
+		UpdatedBy:            userID,
+		Actor:                validators.AuditActor(c),
+	})
+	if err != nil {
+		registryCredentialError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"registry_credential": registryCredential,
+	})
+}
+
+// DeleteRegistryCredentialController godoc
+// @Summary Deletes a registry credential
+// @Description a credential cannot be deleted while applications pull their image with it
+// @ID delete-registry-credential
+// @Tags Namespaces
+// @Produce  json
+// @Param Authorization header string true "Authorization Token"
+// @Param id path string true "Namespace ID"
+// @Param credentialId path string true "Registry credential ID"
+// @Success 200 {object} domain.RegistryCredential
+// @Failure 403 {object} errors.ApiError
+// @Failure 404 {object} errors.ApiError
+// @Failure 409 {object} errors.ApiError
+// @Router /namespaces/{id}/registry-credentials/{credentialId} [delete]
+func (namespaceController NamespaceController) DeleteRegistryCredentialController(c *gin.Context) {
+	userID, ok := validators.AuthenticatedUserID(c)
+	if !ok {
+		validators.Unauthorized(c)
+		return
+	}
+
+	registryCredential, err := namespaceController.deleteRegistryCredentialUseCase.Execute(commands.DeleteRegistryCredential{
+		NamespaceID:          c.Param("id"),
+		RegistryCredentialID: c.Param("credentialId"),
+		DeletedBy:            userID,
+		Actor:                validators.AuditActor(c),
+	})
+	if err != nil {
+		registryCredentialError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"registry_credential": registryCredential,
+	})
+}
+
+func registryCredentialError(c *gin.Context, err error) {
+	if apiError, ok := errors.NewForbiddenApiError(err, c); ok {
+		c.JSON(apiError.StatusCode, apiError)
+		return
+	}
+
+	switch registryCredentialErr := err.(type) {
+	case *errors.NamespaceNotFoundByIDError:
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case *errors.RegistryCredentialNotFoundError:
+		c.JSON(http.StatusNotFound, errors.NewApiError(
+			http.StatusNotFound,
+			"registry_credential_not_found",
+			"The registry credential does not exist",
+			"Please check the ID of the registry credential",
+			c,
+			map[string]interface{}{
+				"registryCredentialId": registryCredentialErr.RegistryCredentialID,
+			},
+		))
+	case *errors.RegistryCredentialInUseError:
+		c.JSON(http.StatusConflict, errors.NewApiError(
+			http.StatusConflict,
+			"registry_credential_in_use",
+			"The registry credential is used by applications of the namespace",
+			"Please make these applications use another registry before deleting the credential",
+			c,
+			map[string]interface{}{
+				"registryCredentialId": registryCredentialErr.RegistryCredentialID,
+				"applications":         registryCredentialErr.ApplicationNames,
+			},
+		))
+	case *errors.InvalidRegistryCredentialError:
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		fmt.Printf("Error while handling registry credential: %s", err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
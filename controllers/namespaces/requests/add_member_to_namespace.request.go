@@ -4,7 +4,6 @@ import "cloud-app-hive/domain"
 
 // AddMemberToNamespaceRequest is a struct that represents the request body for creating a namespace
 type AddMemberToNamespaceRequest struct {
-	UserID  string      `json:"userId" binding:"required"`
-	Role    domain.Role `json:"role" binding:"required,oneof=ADMIN MEMBER"`
-	AddedBy string      `json:"addedBy" binding:"required"`
+	UserID string      `json:"userId" binding:"required"`
+	Role   domain.Role `json:"role" binding:"required,oneof=OWNER ADMIN DEVELOPER MEMBER VIEWER"`
 }
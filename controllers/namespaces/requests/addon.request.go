@@ -0,0 +1,14 @@
+package requests
+
+// CreateAddonRequest is a struct that represents the request body for provisioning an add-on in a namespace.
+// The size defaults to small.
+type CreateAddonRequest struct {
+	Name string `json:"name" binding:"required,max=40"`
+	Type string `json:"type" binding:"required,oneof=mysql postgresql redis"`
+	Size string `json:"size" binding:"omitempty,oneof=small medium large"`
+}
+
+// BindAddonRequest is a struct that represents the request body for binding an add-on to an application of its namespace
+type BindAddonRequest struct {
+	ApplicationID string `json:"applicationId" binding:"required"`
+}
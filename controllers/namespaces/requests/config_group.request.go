@@ -0,0 +1,19 @@
+package requests
+
+import "cloud-app-hive/domain"
+
+// CreateConfigGroupRequest is a struct that represents the request body for creating a config group in a namespace
+type CreateConfigGroupRequest struct {
+	Name                 string                                 `json:"name" binding:"required,max=40"`
+	Description          string                                 `json:"description" binding:"max=255"`
+	EnvironmentVariables domain.ApplicationEnvironmentVariables `json:"environmentVariables"`
+	Secrets              domain.ApplicationSecrets              `json:"secrets"`
+}
+
+// UpdateConfigGroupRequest is a struct that represents the request body for replacing the values of a config group.
+// A secret given without value nor reference keeps its current value.
+type UpdateConfigGroupRequest struct {
+	Description          string                                 `json:"description" binding:"max=255"`
+	EnvironmentVariables domain.ApplicationEnvironmentVariables `json:"environmentVariables"`
+	Secrets              domain.ApplicationSecrets              `json:"secrets"`
+}
@@ -9,7 +9,6 @@ import (
 type CreateNamespaceRequest struct {
 	Name        string `json:"name" binding:"required,min=3,max=100" validate:"IsACustomStringForSubdomainValidation"`
 	Description string `json:"description" binding:"omitempty,min=3,max=1000"`
-	UserID      string `json:"userId" binding:"required"`
 }
 
 func ValidateCreateNamespaceRequest(createNamespaceRequest CreateNamespaceRequest) error {
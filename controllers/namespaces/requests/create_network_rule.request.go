@@ -0,0 +1,9 @@
+package requests
+
+// CreateNetworkRuleRequest is a struct that represents the request body for allowing traffic to the applications of a namespace
+type CreateNetworkRuleRequest struct {
+	ApplicationID     *string `json:"applicationId"`
+	FromNamespaceID   string  `json:"fromNamespaceId" binding:"required"`
+	FromApplicationID *string `json:"fromApplicationId"`
+	Description       string  `json:"description" binding:"max=1000"`
+}
@@ -0,0 +1,19 @@
+package requests
+
+import (
+	"time"
+
+	"cloud-app-hive/domain"
+)
+
+// FindAuditEventsRequest is a struct that represents the query parameters for finding the audit events of a namespace
+type FindAuditEventsRequest struct {
+	Action   *domain.AuditAction  `form:"action" binding:""`
+	ActorID  *string              `form:"actorId" binding:""`
+	TargetID *string              `form:"targetId" binding:""`
+	Outcome  *domain.AuditOutcome `form:"outcome" binding:"omitempty,oneof=success failure denied"`
+	From     *time.Time           `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To       *time.Time           `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+	Page     uint32               `form:"page" binding:"omitempty,min=1"`
+	Limit    uint32               `form:"limit" binding:"omitempty,max=500"`
+}
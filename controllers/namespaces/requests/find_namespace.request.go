@@ -8,7 +8,6 @@ import (
 // FindNamespacesRequest is a struct that represents the request body for finding a namespace
 type FindNamespacesRequest struct {
 	Name    *string `form:"name" validate:"omitempty,IsACustomStringForSubdomainValidation"`
-	UserID  string  `form:"userId" validate:"required"`
 	Page    int     `form:"page"`
 	PerPage int     `form:"per_page"`
 }
@@ -0,0 +1,9 @@
+package requests
+
+import "cloud-app-hive/domain"
+
+// UpdateImageScanPolicyRequest is a struct that represents the request body for changing the image scan policy of a namespace
+type UpdateImageScanPolicyRequest struct {
+	Policy      domain.ImageScanPolicy       `json:"policy" binding:"required,oneof=off warn block"`
+	MaxSeverity domain.VulnerabilitySeverity `json:"maxSeverity" binding:"required,oneof=UNKNOWN LOW MEDIUM HIGH CRITICAL"`
+}
@@ -0,0 +1,9 @@
+package requests
+
+import "cloud-app-hive/domain"
+
+// InviteMemberToNamespaceRequest is a struct that represents the request body for inviting someone to a namespace by email
+type InviteMemberToNamespaceRequest struct {
+	Email string      `json:"email" binding:"required,email"`
+	Role  domain.Role `json:"role" binding:"required,oneof=OWNER ADMIN DEVELOPER MEMBER VIEWER"`
+}
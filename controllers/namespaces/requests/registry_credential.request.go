@@ -0,0 +1,17 @@
+package requests
+
+// CreateRegistryCredentialRequest is a struct that represents the request body for registering the account of a namespace on a registry
+type CreateRegistryCredentialRequest struct {
+	Name     string `json:"name" binding:"required,max=63"`
+	Server   string `json:"server" binding:"required,max=255"`
+	Username string `json:"username" binding:"required,max=255"`
+	Password string `json:"password" binding:"required"`
+}
+
+// UpdateRegistryCredentialRequest is a struct that represents the request body for updating a registry credential.
+// The stored password is kept when the password is empty.
+type UpdateRegistryCredentialRequest struct {
+	Server   string `json:"server" binding:"required,max=255"`
+	Username string `json:"username" binding:"required,max=255"`
+	Password string `json:"password"`
+}
@@ -0,0 +1,6 @@
+package requests
+
+// TransferNamespaceOwnershipRequest is a struct that represents the request body for transferring a namespace to one of its members
+type TransferNamespaceOwnershipRequest struct {
+	UserID string `json:"userId" binding:"required"`
+}
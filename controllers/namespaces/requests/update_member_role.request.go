@@ -0,0 +1,8 @@
+package requests
+
+import "cloud-app-hive/domain"
+
+// UpdateMemberRoleRequest is a struct that represents the request body for changing the role of a namespace member
+type UpdateMemberRoleRequest struct {
+	Role domain.Role `json:"role" binding:"required,oneof=OWNER ADMIN DEVELOPER MEMBER VIEWER"`
+}
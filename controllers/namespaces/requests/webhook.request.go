@@ -0,0 +1,17 @@
+package requests
+
+import "cloud-app-hive/domain"
+
+// CreateWebhookRequest is a struct that represents the request body for subscribing an endpoint to the events of a namespace
+type CreateWebhookRequest struct {
+	URL        string                   `json:"url" binding:"required,max=2048"`
+	EventTypes domain.WebhookEventTypes `json:"eventTypes" binding:"required"`
+}
+
+// UpdateWebhookRequest is a struct that represents the request body for updating a webhook.
+// Inactive webhooks keep their configuration but no event is sent to them.
+type UpdateWebhookRequest struct {
+	URL        string                   `json:"url" binding:"required,max=2048"`
+	EventTypes domain.WebhookEventTypes `json:"eventTypes" binding:"required"`
+	Active     *bool                    `json:"active" binding:"required"`
+}
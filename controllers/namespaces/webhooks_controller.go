@@ -0,0 +1,229 @@
+package namespaces
+
+import (
+	"cloud-app-hive/controllers/errors"
+	"fmt"
+	"net/http"
+
+	"cloud-app-hive/controllers/namespaces/requests"
+	"cloud-app-hive/controllers/validators"
+	"cloud-app-hive/domain/commands"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CreateWebhookController godoc
+// @Summary Subscribes an endpoint to the events of a namespace
+// @Description the events are signed with the returned secret, it is stored encrypted and never returned again
+// @ID create-webhook
+// @Tags Namespaces
+// @Accept  json
+// @Produce  json
+// @Param Authorization header string true "Authorization Token"
+// @Param id path string true "Namespace ID"
+// @Param webhook body requests.CreateWebhookRequest true "Endpoint and events of the webhook"
+// @Success 201 {object} domain.Webhook
+// @Failure 400 {object} errors.ApiError
+// @Failure 403 {object} errors.ApiError
+// @Failure 404 {object} errors.ApiError
+// @Router /namespaces/{id}/webhooks [post]
+func (namespaceController NamespaceController) CreateWebhookController(c *gin.Context) {
+	userID, ok := validators.AuthenticatedUserID(c)
+	if !ok {
+		validators.Unauthorized(c)
+		return
+	}
+
+	var createWebhookRequest requests.CreateWebhookRequest
+	if err := c.ShouldBindJSON(&createWebhookRequest); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"validation-errors": err.Error()})
+		return
+	}
+
+	webhook, secret, err := namespaceController.createWebhookUseCase.Execute(commands.CreateWebhook{
+		NamespaceID: c.Param("id"),
+		URL:         createWebhookRequest.URL,
+		EventTypes:  createWebhookRequest.EventTypes,
+		CreatedBy:   userID,
+		Actor:       validators.AuditActor(c),
+	})
+	if err != nil {
+		webhookError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"webhook": webhook,
+		"secret":  secret,
+	})
+}
+
+// FindWebhooksController godoc
+// @Summary Finds the webhooks of a namespace, without their secret
+// @ID find-webhooks
+// @Tags Namespaces
+// @Produce  json
+// @Param Authorization header string true "Authorization Token"
+// @Param id path string true "Namespace ID"
+// @Success 200 {array} domain.Webhook
+// @Failure 403 {object} errors.ApiError
+// @Router /namespaces/{id}/webhooks [get]
+func (namespaceController NamespaceController) FindWebhooksController(c *gin.Context) {
+	userID, ok := validators.AuthenticatedUserID(c)
+	if !ok {
+		validators.Unauthorized(c)
+		return
+	}
+
+	webhooks, err := namespaceController.findWebhooksUseCase.Execute(c.Param("id"), userID)
+	if err != nil {
+		webhookError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"webhooks": webhooks,
+	})
+}
+
+// UpdateWebhookController godoc
+// @Summary Updates a webhook
+// @Description changes the endpoint, the events and the state of the webhook, its secret is kept
+// @ID update-webhook
+// @Tags Namespaces
+// @Accept  json
+// @Produce  json
+// @Param Authorization header string true "Authorization Token"
+// @Param id path string true "Namespace ID"
+// @Param webhookId path string true "Webhook ID"
+// @Param webhook body requests.UpdateWebhookRequest true "Endpoint, events and state of the webhook"
+// @Success 200 {object} domain.Webhook
+// @Failure 400 {object} errors.ApiError
+// @Failure 403 {object} errors.ApiError
+// @Failure 404 {object} errors.ApiError
+// @Router /namespaces/{id}/webhooks/{webhookId} [put]
+func (namespaceController NamespaceController) UpdateWebhookController(c *gin.Context) {
+	userID, ok := validators.AuthenticatedUserID(c)
+	if !ok {
+		validators.Unauthorized(c)
+		return
+	}
+
+	var updateWebhookRequest requests.UpdateWebhookRequest
+	if err := c.ShouldBindJSON(&updateWebhookRequest); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"validation-errors": err.Error()})
+		return
+	}
+
+	webhook, err := namespaceController.updateWebhookUseCase.Execute(commands.UpdateWebhook{
+		NamespaceID: c.Param("id"),
+		WebhookID:   c.Param("webhookId"),
+		URL:         updateWebhookRequest.URL,
+		EventTypes:  updateWebhookRequest.EventTypes,
+		Active:      *updateWebhookRequest.Active,
+		UpdatedBy:   userID,
+		Actor:       validators.AuditActor(c),
+	})
+	if err != nil {
+		webhookError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"webhook": webhook,
+	})
+}
+
+// DeleteWebhookController godoc
+// @Summary Deletes a webhook
+// @ID delete-webhook
+// @Tags Namespaces
+// @Produce  json
+// @Param Authorization header string true "Authorization Token"
+// @Param id path string true "Namespace ID"
+// @Param webhookId path string true "Webhook ID"
+// @Success 200 {object} domain.Webhook
+// @Failure 403 {object} errors.ApiError
+// @Failure 404 {object} errors.ApiError
+// @Router /namespaces/{id}/webhooks/{webhookId} [delete]
+func (namespaceController NamespaceController) DeleteWebhookController(c *gin.Context) {
+	userID, ok := validators.AuthenticatedUserID(c)
+	if !ok {
+		validators.Unauthorized(c)
+		return
+	}
+
+	webhook, err := namespaceController.deleteWebhookUseCase.Execute(commands.DeleteWebhook{
+		NamespaceID: c.Param("id"),
+		WebhookID:   c.Param("webhookId"),
+		DeletedBy:   userID,
+		Actor:       validators.AuditActor(c),
+	})
+	if err != nil {
+		webhookError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"webhook": webhook,
+	})
+}
+
+// FindWebhookDeliveriesController godoc
+// @Summary Finds the last attempts to deliver events to a webhook
+// @Description every attempt is recorded with its payload, the status answered by the endpoint and the time of the next attempt when it failed, most recent first
+// @ID find-webhook-deliveries
+// @Tags Namespaces
+// @Produce  json
+// @Param Authorization header string true "Authorization Token"
+// @Param id path string true "Namespace ID"
+// @Param webhookId path string true "Webhook ID"
+// @Success 200 {array} domain.WebhookDelivery
+// @Failure 403 {object} errors.ApiError
+// @Failure 404 {object} errors.ApiError
+// @Router /namespaces/{id}/webhooks/{webhookId}/deliveries [get]
+func (namespaceController NamespaceController) FindWebhookDeliveriesController(c *gin.Context) {
+	userID, ok := validators.AuthenticatedUserID(c)
+	if !ok {
+		validators.Unauthorized(c)
+		return
+	}
+
+	deliveries, err := namespaceController.findWebhooksUseCase.FindDeliveries(c.Param("id"), c.Param("webhookId"), userID)
+	if err != nil {
+		webhookError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"deliveries": deliveries,
+	})
+}
+
+func webhookError(c *gin.Context, err error) {
+	if apiError, ok := errors.NewForbiddenApiError(err, c); ok {
+		c.JSON(apiError.StatusCode, apiError)
+		return
+	}
+
+	switch webhookErr := err.(type) {
+	case *errors.NamespaceNotFoundByIDError:
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case *errors.WebhookNotFoundError:
+		c.JSON(http.StatusNotFound, errors.NewApiError(
+			http.StatusNotFound,
+			"webhook_not_found",
+			"The webhook does not exist",
+			"Please check the ID of the webhook",
+			c,
+			map[string]interface{}{
+				"webhookId": webhookErr.WebhookID,
+			},
+		))
+	case *errors.InvalidWebhookError:
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		fmt.Printf("Error while handling webhook: %s", err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,63 @@
+package operations
+
+import (
+	"cloud-app-hive/controllers/errors"
+	"cloud-app-hive/controllers/validators"
+	"cloud-app-hive/use_cases/operations"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type OperationController struct {
+	findOperationByIDUseCase operations.FindOperationByIDUseCase
+}
+
+func NewOperationController(
+	findOperationByIDUseCase operations.FindOperationByIDUseCase,
+) OperationController {
+	return OperationController{
+		findOperationByIDUseCase: findOperationByIDUseCase,
+	}
+}
+
+// FindOperationByIDController godoc
+// @Summary Returns an operation
+// @Description returns the progress of a deployment, an update, a scaling or a deletion of an application, with the step that failed and why when it failed
+// @ID find-operation-by-id
+// @Tags Operations
+// @Produce  json
+// @Param Authorization header string true "Authorization Token"
+// @Param id path string true "Operation ID"
+// @Success 200 {object} domain.Operation
+// @Failure 403 {object} errors.ApiError
+// @Failure 404 {object} errors.ApiError
+// @Router /operations/{id} [get]
+func (operationController OperationController) FindOperationByIDController(c *gin.Context) {
+	userID, ok := validators.AuthenticatedUserID(c)
+	if !ok {
+		validators.Unauthorized(c)
+		return
+	}
+
+	operation, err := operationController.findOperationByIDUseCase.Execute(c.Param("id"), userID)
+	if err != nil {
+		fmt.Println("Error while finding operation: ", err)
+		if apiError, ok := errors.NewForbiddenApiError(err, c); ok {
+			c.JSON(apiError.StatusCode, apiError)
+			return
+		}
+		if _, ok := err.(*errors.OperationNotFoundByIDError); ok {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if !validators.AccessTokenAllowsNamespace(c, operation.NamespaceID) {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"operation": operation})
+}
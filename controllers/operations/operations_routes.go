@@ -0,0 +1,21 @@
+package operations
+
+import (
+	"cloud-app-hive/controllers/validators"
+	"cloud-app-hive/domain"
+	"cloud-app-hive/use_cases/operations"
+
+	"github.com/gin-gonic/gin"
+)
+
+func InitOperationsRoutes(
+	router *gin.RouterGroup,
+	findOperationByIDUseCase operations.FindOperationByIDUseCase,
+) {
+	operationController := NewOperationController(
+		findOperationByIDUseCase,
+	)
+	readScope := validators.RequireScope(domain.ScopeApplicationsRead)
+
+	router.GET("/operations/:id", readScope, operationController.FindOperationByIDController)
+}
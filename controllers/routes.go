@@ -2,19 +2,26 @@ package controllers
 
 import (
 	"cloud-app-hive/use_cases"
+	accessTokensUseCases "cloud-app-hive/use_cases/access_tokens"
 	applicationsUseCases "cloud-app-hive/use_cases/applications"
 	namespaceUseCases "cloud-app-hive/use_cases/namespaces"
+	operationsUseCases "cloud-app-hive/use_cases/operations"
 	"net/http"
 
+	"cloud-app-hive/controllers/access_tokens"
 	"cloud-app-hive/controllers/applications"
 	"cloud-app-hive/controllers/cluster"
 	"cloud-app-hive/controllers/namespaces"
+	"cloud-app-hive/controllers/operations"
+	"cloud-app-hive/controllers/validators"
 
 	"github.com/gin-gonic/gin"
 )
 
 func InitRoutes(
 	router *gin.Engine,
+	tokenVerifier validators.TokenVerifier,
+	authenticateAccessTokenUseCase accessTokensUseCases.AuthenticateAccessTokenUseCase,
 	createNamespaceUseCase namespaceUseCases.CreateNamespaceUseCase,
 	findNamespaceByIDUseCase namespaceUseCases.FindNamespaceByIDUseCase,
 	findNamespacesUseCase namespaceUseCases.FindNamespacesUseCase,
@@ -34,10 +41,61 @@ func InitRoutes(
 	deleteNamespaceByIDUseCase namespaceUseCases.DeleteNamespaceByIDUseCase,
 	updateNamespaceByIDUseCase namespaceUseCases.UpdateNamespaceByIDUseCase,
 	getClusterMetricsUseCase use_cases.GetClusterMetricsUseCase,
+	exportNamespaceManifestUseCase namespaceUseCases.ExportNamespaceManifestUseCase,
+	applyNamespaceManifestUseCase namespaceUseCases.ApplyNamespaceManifestUseCase,
+	dryRunApplicationUseCase applicationsUseCases.DryRunApplicationUseCase,
+	scaleApplicationUseCase applicationsUseCases.ScaleApplicationUseCase,
+	createAccessTokenUseCase accessTokensUseCases.CreateAccessTokenUseCase,
+	findAccessTokensUseCase accessTokensUseCases.FindAccessTokensUseCase,
+	findAccessTokenByIDUseCase accessTokensUseCases.FindAccessTokenByIDUseCase,
+	revokeAccessTokenUseCase accessTokensUseCases.RevokeAccessTokenUseCase,
+	findAuditEventsUseCase namespaceUseCases.FindAuditEventsUseCase,
+	setApplicationSecretUseCase applicationsUseCases.SetApplicationSecretUseCase,
+	deleteApplicationSecretUseCase applicationsUseCases.DeleteApplicationSecretUseCase,
+	createNamespaceInvitationUseCase namespaceUseCases.CreateNamespaceInvitationUseCase,
+	findNamespaceInvitationsUseCase namespaceUseCases.FindNamespaceInvitationsUseCase,
+	revokeNamespaceInvitationUseCase namespaceUseCases.RevokeNamespaceInvitationUseCase,
+	acceptNamespaceInvitationUseCase namespaceUseCases.AcceptNamespaceInvitationUseCase,
+	declineNamespaceInvitationUseCase namespaceUseCases.DeclineNamespaceInvitationUseCase,
+	updateNamespaceMembershipUseCase namespaceUseCases.UpdateNamespaceMembershipUseCase,
+	transferNamespaceOwnershipUseCase namespaceUseCases.TransferNamespaceOwnershipUseCase,
+	acceptNamespaceOwnershipTransferUseCase namespaceUseCases.AcceptNamespaceOwnershipTransferUseCase,
+	cancelNamespaceOwnershipTransferUseCase namespaceUseCases.CancelNamespaceOwnershipTransferUseCase,
+	rateLimiter validators.RateLimiter,
+	createNetworkRuleUseCase namespaceUseCases.CreateNetworkRuleUseCase,
+	findNetworkRulesUseCase namespaceUseCases.FindNetworkRulesUseCase,
+	deleteNetworkRuleUseCase namespaceUseCases.DeleteNetworkRuleUseCase,
+	createRegistryCredentialUseCase namespaceUseCases.CreateRegistryCredentialUseCase,
+	findRegistryCredentialsUseCase namespaceUseCases.FindRegistryCredentialsUseCase,
+	updateRegistryCredentialUseCase namespaceUseCases.UpdateRegistryCredentialUseCase,
+	deleteRegistryCredentialUseCase namespaceUseCases.DeleteRegistryCredentialUseCase,
+	updateImageScanPolicyUseCase namespaceUseCases.UpdateImageScanPolicyUseCase,
+	findApplicationImageScanUseCase applicationsUseCases.FindApplicationImageScanUseCase,
+	findNamespaceServicesUseCase namespaceUseCases.FindNamespaceServicesUseCase,
+	createAddonUseCase namespaceUseCases.CreateAddonUseCase,
+	findAddonsUseCase namespaceUseCases.FindAddonsUseCase,
+	deleteAddonUseCase namespaceUseCases.DeleteAddonUseCase,
+	bindAddonUseCase namespaceUseCases.BindAddonUseCase,
+	unbindAddonUseCase namespaceUseCases.UnbindAddonUseCase,
+	createConfigGroupUseCase namespaceUseCases.CreateConfigGroupUseCase,
+	findConfigGroupsUseCase namespaceUseCases.FindConfigGroupsUseCase,
+	updateConfigGroupUseCase namespaceUseCases.UpdateConfigGroupUseCase,
+	deleteConfigGroupUseCase namespaceUseCases.DeleteConfigGroupUseCase,
+	setApplicationConfigGroupsUseCase applicationsUseCases.SetApplicationConfigGroupsUseCase,
+	startApplicationOperationUseCase operationsUseCases.StartApplicationOperationUseCase,
+	findOperationByIDUseCase operationsUseCases.FindOperationByIDUseCase,
+	createWebhookUseCase namespaceUseCases.CreateWebhookUseCase,
+	findWebhooksUseCase namespaceUseCases.FindWebhooksUseCase,
+	updateWebhookUseCase namespaceUseCases.UpdateWebhookUseCase,
+	deleteWebhookUseCase namespaceUseCases.DeleteWebhookUseCase,
+	getApplicationEventsUseCase applicationsUseCases.GetApplicationEventsUseCase,
 ) *gin.Engine {
 	api := router.Group("/api/v1")
 	{
 		api.GET("/health", HealthCheck)
+		// Routes registered after this point require a valid JWT or access token, the health check above stays public
+		api.Use(validators.Authentication(tokenVerifier, authenticateAccessTokenUseCase))
+		api.Use(validators.RateLimit(rateLimiter, api.BasePath()))
 		namespaces.InitNamespacesRoutes(
 			api,
 			createNamespaceUseCase,
@@ -48,6 +106,40 @@ func InitRoutes(
 			deleteNamespaceByIDUseCase,
 			updateNamespaceByIDUseCase,
 			fillApplicationsStatusUseCase,
+			exportNamespaceManifestUseCase,
+			applyNamespaceManifestUseCase,
+			findAuditEventsUseCase,
+			createNamespaceInvitationUseCase,
+			findNamespaceInvitationsUseCase,
+			revokeNamespaceInvitationUseCase,
+			acceptNamespaceInvitationUseCase,
+			declineNamespaceInvitationUseCase,
+			updateNamespaceMembershipUseCase,
+			transferNamespaceOwnershipUseCase,
+			acceptNamespaceOwnershipTransferUseCase,
+			cancelNamespaceOwnershipTransferUseCase,
+			createNetworkRuleUseCase,
+			findNetworkRulesUseCase,
+			deleteNetworkRuleUseCase,
+			createRegistryCredentialUseCase,
+			findRegistryCredentialsUseCase,
+			updateRegistryCredentialUseCase,
+			deleteRegistryCredentialUseCase,
+			updateImageScanPolicyUseCase,
+			findNamespaceServicesUseCase,
+			createAddonUseCase,
+			findAddonsUseCase,
+			deleteAddonUseCase,
+			bindAddonUseCase,
+			unbindAddonUseCase,
+			createConfigGroupUseCase,
+			findConfigGroupsUseCase,
+			updateConfigGroupUseCase,
+			deleteConfigGroupUseCase,
+			createWebhookUseCase,
+			findWebhooksUseCase,
+			updateWebhookUseCase,
+			deleteWebhookUseCase,
 		)
 		applications.InitApplicationsRoutes(
 			api,
@@ -63,11 +155,30 @@ func InitRoutes(
 			getApplicationStatusUseCase,
 			fillApplicationsStatusUseCase,
 			getClusterMetricsUseCase,
+			dryRunApplicationUseCase,
+			scaleApplicationUseCase,
+			setApplicationSecretUseCase,
+			deleteApplicationSecretUseCase,
+			findApplicationImageScanUseCase,
+			setApplicationConfigGroupsUseCase,
+			startApplicationOperationUseCase,
+			getApplicationEventsUseCase,
+		)
+		operations.InitOperationsRoutes(
+			api,
+			findOperationByIDUseCase,
 		)
 		cluster.InitClusterRoutes(
 			api,
 			getClusterMetricsUseCase,
 		)
+		access_tokens.InitAccessTokensRoutes(
+			api,
+			createAccessTokenUseCase,
+			findAccessTokensUseCase,
+			findAccessTokenByIDUseCase,
+			revokeAccessTokenUseCase,
+		)
 	}
 
 	return router
@@ -1,15 +1,177 @@
 package validators
 
 import (
+	"fmt"
 	"net/http"
-	"os"
+	"strings"
+
+	"cloud-app-hive/controllers/errors"
+	"cloud-app-hive/domain"
+	"cloud-app-hive/services"
 
 	"github.com/gin-gonic/gin"
 )
 
-func ValidateAuthorizationToken(c *gin.Context) bool {
-	authHeader := c.Request.Header.Get("Authorization")
-	return authHeader == "Bearer "+os.Getenv("API_KEY")
+// authenticatedUserIDKey is the key of the authenticated subject in the gin context
+const authenticatedUserIDKey = "authenticatedUserId"
+
+// authenticatedUserEmailKey is the key of the verified email claim of the JWT in the gin context, it is not set for access tokens
+const authenticatedUserEmailKey = "authenticatedUserEmail"
+
+// accessTokenKey is the key of the access token in the gin context, it is only set when the request does not use a JWT
+const accessTokenKey = "accessToken"
+
+// TokenVerifier verifies a bearer token and returns its claims
+type TokenVerifier interface {
+	Verify(token string) (*services.JWTClaims, error)
+}
+
+// AccessTokenAuthenticator returns the active access token of a secret
+type AccessTokenAuthenticator interface {
+	Execute(secret string) (*domain.AccessToken, error)
+}
+
+// Authentication is a middleware that verifies the bearer JWT or access token of the request and stores its subject in the context.
+// Requests without a valid token are rejected before reaching the controllers.
+func Authentication(tokenVerifier TokenVerifier, accessTokenAuthenticator AccessTokenAuthenticator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authHeader := c.Request.Header.Get("Authorization")
+		token := strings.TrimPrefix(authHeader, "Bearer ")
+		if token == authHeader || token == "" {
+			Unauthorized(c)
+			return
+		}
+
+		if domain.IsAnAccessTokenSecret(token) {
+			accessToken, err := accessTokenAuthenticator.Execute(token)
+			if err != nil {
+				fmt.Println("Error while authenticating access token: ", err)
+				Unauthorized(c)
+				return
+			}
+			c.Set(authenticatedUserIDKey, accessToken.UserID)
+			c.Set(accessTokenKey, accessToken)
+			c.Next()
+			return
+		}
+
+		claims, err := tokenVerifier.Verify(token)
+		if err != nil {
+			fmt.Println("Error while verifying token: ", err)
+			Unauthorized(c)
+			return
+		}
+
+		c.Set(authenticatedUserIDKey, claims.Subject)
+		// Only an email the issuer verified proves who the user is
+		if claims.EmailVerified {
+			c.Set(authenticatedUserEmailKey, claims.Email)
+		}
+		c.Next()
+	}
+}
+
+// AuthenticatedUserID returns the subject of the token verified by the Authentication middleware
+func AuthenticatedUserID(c *gin.Context) (string, bool) {
+	userID := c.GetString(authenticatedUserIDKey)
+	return userID, userID != ""
+}
+
+// AuthenticatedUserEmail returns the email claim of the verified JWT, empty when the issuer does not provide it or did not verify it
+func AuthenticatedUserEmail(c *gin.Context) string {
+	return c.GetString(authenticatedUserEmailKey)
+}
+
+// AuthenticatedAccessToken returns the access token of the request, or false if the request uses a JWT
+func AuthenticatedAccessToken(c *gin.Context) (*domain.AccessToken, bool) {
+	accessToken, ok := c.Get(accessTokenKey)
+	if !ok {
+		return nil, false
+	}
+	return accessToken.(*domain.AccessToken), true
+}
+
+// AuditActor returns the user, access token and source IP of the request, as recorded in the audit log
+func AuditActor(c *gin.Context) domain.AuditActor {
+	userID, _ := AuthenticatedUserID(c)
+	actor := domain.AuditActor{UserID: userID, SourceIP: c.ClientIP()}
+	if accessToken, ok := AuthenticatedAccessToken(c); ok {
+		actor.AccessTokenID = &accessToken.ID
+	}
+	return actor
+}
+
+// RequireScope is a middleware that rejects access tokens without the scope.
+// Requests authenticated with a JWT act for the user and are not restricted by scopes.
+func RequireScope(scope domain.AccessTokenScope) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		accessToken, ok := AuthenticatedAccessToken(c)
+		if ok && !accessToken.Scopes.Include(scope) {
+			c.AbortWithStatusJSON(http.StatusForbidden, errors.NewApiError(
+				http.StatusForbidden,
+				"access_token_scope_missing",
+				fmt.Sprintf("The access token %s does not have the scope %s", accessToken.ID, scope),
+				"Please use an access token with this scope",
+				c,
+				map[string]interface{}{
+					"accessTokenId": accessToken.ID,
+					"scopes":        accessToken.Scopes,
+					"requiredScope": scope,
+				},
+			))
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequireUserSession is a middleware that rejects access tokens, so that a leaked token cannot mint or revoke tokens
+func RequireUserSession(c *gin.Context) {
+	if accessToken, ok := AuthenticatedAccessToken(c); ok {
+		c.AbortWithStatusJSON(http.StatusForbidden, errors.NewApiError(
+			http.StatusForbidden,
+			"user_session_required",
+			"This endpoint cannot be called with an access token",
+			"Please sign in to manage access tokens",
+			c,
+			map[string]interface{}{
+				"accessTokenId": accessToken.ID,
+			},
+		))
+		return
+	}
+	c.Next()
+}
+
+// AccessTokenAllowsNamespace writes a 403 response and returns false when the request uses an access token restricted to another namespace.
+// An empty namespace ID stands for an action outside of any existing namespace.
+func AccessTokenAllowsNamespace(c *gin.Context, namespaceID string) bool {
+	accessToken, ok := AuthenticatedAccessToken(c)
+	if !ok || accessToken.AllowsNamespace(namespaceID) {
+		return true
+	}
+	c.AbortWithStatusJSON(http.StatusForbidden, errors.NewApiError(
+		http.StatusForbidden,
+		"access_token_namespace_restricted",
+		fmt.Sprintf("The access token %s is restricted to the namespace %s", accessToken.ID, *accessToken.NamespaceID),
+		"Please use an access token of this namespace",
+		c,
+		map[string]interface{}{
+			"accessTokenId": accessToken.ID,
+			"namespaceId":   namespaceID,
+		},
+	))
+	return false
+}
+
+// RestrictAccessTokenToNamespaceParam is a middleware that applies AccessTokenAllowsNamespace to the namespace ID of the route
+func RestrictAccessTokenToNamespaceParam(param string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !AccessTokenAllowsNamespace(c, c.Param(param)) {
+			return
+		}
+		c.Next()
+	}
 }
 
 func Unauthorized(c *gin.Context) {
@@ -0,0 +1,77 @@
+package validators
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"cloud-app-hive/controllers/errors"
+	"cloud-app-hive/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RateLimiter counts a request of an identity on a class of routes and tells whether it is allowed
+type RateLimiter interface {
+	Execute(identity string, rateLimitClass domain.RateLimitClass) (domain.RateLimitDecision, error)
+}
+
+// RateLimit is a middleware that rejects the requests exceeding the limits of the access token, or of the user for JWTs, on the class of the route.
+// It must be registered after the Authentication middleware. The requests are let through when the counters cannot be reached.
+func RateLimit(rateLimiter RateLimiter, basePath string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		identity := rateLimitIdentity(c)
+		if identity == "" {
+			c.Next()
+			return
+		}
+
+		rateLimitClass := domain.RateLimitClassOf(c.Request.Method, strings.TrimPrefix(c.FullPath(), basePath))
+		rateLimitDecision, err := rateLimiter.Execute(identity, rateLimitClass)
+		if err != nil {
+			fmt.Println("Error while checking rate limit: ", err)
+			c.Next()
+			return
+		}
+
+		if rateLimitDecision.Limit.Requests > 0 {
+			c.Header("X-RateLimit-Limit", strconv.FormatInt(rateLimitDecision.Limit.Requests, 10))
+			c.Header("X-RateLimit-Remaining", strconv.FormatInt(rateLimitDecision.Remaining, 10))
+			c.Header("X-RateLimit-Reset", strconv.FormatInt(rateLimitDecision.ResetAt.Unix(), 10))
+		}
+
+		if !rateLimitDecision.Allowed {
+			retryAfter := int64(math.Ceil(rateLimitDecision.RetryAfter(time.Now()).Seconds()))
+			c.Header("Retry-After", strconv.FormatInt(retryAfter, 10))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, errors.NewApiError(
+				http.StatusTooManyRequests,
+				"rate_limit_exceeded",
+				fmt.Sprintf("The limit of %s %s requests is exceeded", rateLimitDecision.Limit, rateLimitClass),
+				fmt.Sprintf("Please retry in %d seconds", retryAfter),
+				c,
+				map[string]interface{}{
+					"class":      rateLimitClass,
+					"limit":      rateLimitDecision.Limit.Requests,
+					"window":     rateLimitDecision.Limit.Window.String(),
+					"retryAfter": retryAfter,
+				},
+			))
+			return
+		}
+		c.Next()
+	}
+}
+
+// rateLimitIdentity returns the identity whose requests are counted, each access token has its own counters
+func rateLimitIdentity(c *gin.Context) string {
+	if accessToken, ok := AuthenticatedAccessToken(c); ok {
+		return "token:" + accessToken.ID
+	}
+	if userID, ok := AuthenticatedUserID(c); ok {
+		return "user:" + userID
+	}
+	return ""
+}
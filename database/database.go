@@ -34,7 +34,14 @@ func ConnectToDatabase() (*gorm.DB, error) {
 }
 
 func MigrateDatabase(db *gorm.DB) error {
-	err := db.AutoMigrate(&domain.Application{}, &domain.Namespace{}, &domain.NamespaceMembership{})
+	err := db.AutoMigrate(&domain.Application{}, &domain.Namespace{}, &domain.NamespaceMembership{}, &domain.AccessToken{}, &domain.AuditEvent{}, &domain.NamespaceDataKey{}, &domain.NamespaceInvitation{}, &domain.RateLimitCounter{}, &domain.NetworkRule{}, &domain.RegistryCredential{}, &domain.ImageScan{}, &domain.Addon{}, &domain.AddonBinding{}, &domain.ConfigGroup{}, &domain.ConfigGroupAttachment{}, &domain.Operation{}, &domain.Webhook{}, &domain.WebhookDelivery{})
+	if err != nil {
+		return ErrDatabaseMigration
+	}
+
+	// Password previews used to show the last characters of the passwords
+	maskedPassword := domain.MaskSecretValue("")
+	err = db.Model(&domain.RegistryCredential{}).Where("password_preview <> '' AND password_preview <> ?", maskedPassword).Update("password_preview", maskedPassword).Error
 	if err != nil {
 		return ErrDatabaseMigration
 	}
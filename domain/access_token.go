@@ -0,0 +1,136 @@
+package domain
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql/driver"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// AccessTokenSecretPrefix starts every access token secret, it tells access tokens apart from JWTs
+const AccessTokenSecretPrefix = "cah_"
+
+// accessTokenLastUsedPrecision avoids writing the last use of a token on every request
+const accessTokenLastUsedPrecision = time.Minute
+
+type AccessTokenScope string
+
+const (
+	ScopeNamespacesRead     AccessTokenScope = "namespaces:read"
+	ScopeNamespacesAdmin    AccessTokenScope = "namespaces:admin"
+	ScopeApplicationsRead   AccessTokenScope = "applications:read"
+	ScopeApplicationsDeploy AccessTokenScope = "applications:deploy"
+	ScopeApplicationsDelete AccessTokenScope = "applications:delete"
+)
+
+// impliedScopes are the scopes granted by a broader scope
+var impliedScopes = map[AccessTokenScope][]AccessTokenScope{
+	ScopeNamespacesAdmin:    {ScopeNamespacesRead, ScopeApplicationsRead, ScopeApplicationsDeploy, ScopeApplicationsDelete},
+	ScopeApplicationsDeploy: {ScopeApplicationsRead},
+	ScopeApplicationsDelete: {ScopeApplicationsRead},
+}
+
+// IsAValidAccessTokenScope returns true if the scope can be given to an access token
+func IsAValidAccessTokenScope(scope AccessTokenScope) bool {
+	switch scope {
+	case ScopeNamespacesRead, ScopeNamespacesAdmin, ScopeApplicationsRead, ScopeApplicationsDeploy, ScopeApplicationsDelete:
+		return true
+	}
+	return false
+}
+
+// AccessTokenScopes is a slice of AccessTokenScope stored as JSON
+// swagger:model AccessTokenScopes
+type AccessTokenScopes []AccessTokenScope
+
+func (accessTokenScopes *AccessTokenScopes) Scan(value interface{}) error {
+	bytes, ok := value.([]byte)
+	if !ok {
+		return fmt.Errorf("failed to unmarshal JSONB value: %v", value)
+	}
+
+	return json.Unmarshal(bytes, &accessTokenScopes)
+}
+
+func (accessTokenScopes AccessTokenScopes) Value() (driver.Value, error) {
+	return json.Marshal(accessTokenScopes)
+}
+
+// Include returns true if one of the scopes is or implies the scope
+func (accessTokenScopes AccessTokenScopes) Include(scope AccessTokenScope) bool {
+	for _, accessTokenScope := range accessTokenScopes {
+		if accessTokenScope == scope {
+			return true
+		}
+		for _, impliedScope := range impliedScopes[accessTokenScope] {
+			if impliedScope == scope {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// AccessToken is a struct that represents a named token that a user mints for scripts and CI pipelines.
+// Only the hash of the secret is stored, the secret itself is returned once at creation.
+type AccessToken struct {
+	ID          string            `json:"id" gorm:"primaryKey"`
+	Name        string            `json:"name" gorm:"size:100;not null"`
+	UserID      string            `json:"userId" gorm:"index:idx_access_token_user_id;not null"`
+	SecretHash  string            `json:"-" gorm:"size:64;uniqueIndex;not null"`
+	SecretStart string            `json:"secretStart" gorm:"size:20;not null"` // The first characters of the secret, to recognize the token
+	Scopes      AccessTokenScopes `json:"scopes" gorm:"type:json;not null"`
+	NamespaceID *string           `json:"namespaceId" gorm:"size:100"` // The only namespace the token can access, any namespace of the user when nil
+	ExpiresAt   *time.Time        `json:"expiresAt"`
+	LastUsedAt  *time.Time        `json:"lastUsedAt"`
+	RevokedAt   *time.Time        `json:"revokedAt"`
+	UpdatedAt   time.Time         `json:"updatedAt" gorm:"autoUpdateTime;not null"`
+	CreatedAt   time.Time         `json:"createdAt" gorm:"autoCreateTime;not null"`
+	DeletedAt   *gorm.DeletedAt   `json:"deletedAt" gorm:"index;default:null"`
+}
+
+// NewAccessTokenSecret returns a random access token secret
+func NewAccessTokenSecret() (string, error) {
+	randomBytes := make([]byte, 32)
+	if _, err := rand.Read(randomBytes); err != nil {
+		return "", fmt.Errorf("error generating access token secret: %w", err)
+	}
+	return AccessTokenSecretPrefix + base64.RawURLEncoding.EncodeToString(randomBytes), nil
+}
+
+// IsAnAccessTokenSecret returns true if the bearer token is an access token secret rather than a JWT
+func IsAnAccessTokenSecret(token string) bool {
+	return strings.HasPrefix(token, AccessTokenSecretPrefix)
+}
+
+// HashAccessTokenSecret returns the hash stored in place of the secret.
+// Secrets are random 256 bits values, so a fast hash is enough to make a leaked table useless.
+func HashAccessTokenSecret(secret string) string {
+	hash := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(hash[:])
+}
+
+// IsActive returns true if the token is neither revoked nor expired
+func (accessToken AccessToken) IsActive(now time.Time) bool {
+	if accessToken.RevokedAt != nil {
+		return false
+	}
+	return accessToken.ExpiresAt == nil || now.Before(*accessToken.ExpiresAt)
+}
+
+// AllowsNamespace returns true if the token is not restricted to another namespace
+func (accessToken AccessToken) AllowsNamespace(namespaceID string) bool {
+	return accessToken.NamespaceID == nil || *accessToken.NamespaceID == namespaceID
+}
+
+// ShouldRecordUse returns true if the last use of the token is outdated
+func (accessToken AccessToken) ShouldRecordUse(now time.Time) bool {
+	return accessToken.LastUsedAt == nil || now.Sub(*accessToken.LastUsedAt) >= accessTokenLastUsedPrecision
+}
@@ -0,0 +1,57 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAccessTokenScopes_Include(t *testing.T) {
+	scopes := AccessTokenScopes{ScopeApplicationsDeploy}
+	if !scopes.Include(ScopeApplicationsDeploy) || !scopes.Include(ScopeApplicationsRead) {
+		t.Error("expected the deploy scope to include itself and the read scope")
+	}
+	if scopes.Include(ScopeApplicationsDelete) || scopes.Include(ScopeNamespacesAdmin) {
+		t.Error("expected the deploy scope not to include delete or admin scopes")
+	}
+	if !(AccessTokenScopes{ScopeNamespacesAdmin}).Include(ScopeApplicationsDelete) {
+		t.Error("expected the admin scope to include every scope")
+	}
+}
+
+func TestAccessToken_IsActiveAndRestrictedToNamespace(t *testing.T) {
+	now := time.Now()
+	past, future := now.Add(-time.Hour), now.Add(time.Hour)
+	namespaceID := "namespace-id"
+
+	accessToken := AccessToken{ExpiresAt: &future, NamespaceID: &namespaceID}
+	if !accessToken.IsActive(now) {
+		t.Error("expected a token expiring in the future to be active")
+	}
+	if !accessToken.AllowsNamespace(namespaceID) || accessToken.AllowsNamespace("other") || accessToken.AllowsNamespace("") {
+		t.Error("expected the token to allow its namespace only")
+	}
+
+	accessToken.ExpiresAt = &past
+	if accessToken.IsActive(now) {
+		t.Error("expected an expired token to be inactive")
+	}
+	accessToken.ExpiresAt = nil
+	accessToken.RevokedAt = &past
+	if accessToken.IsActive(now) {
+		t.Error("expected a revoked token to be inactive")
+	}
+}
+
+func TestNewAccessTokenSecret(t *testing.T) {
+	secret, err := NewAccessTokenSecret()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	otherSecret, _ := NewAccessTokenSecret()
+	if !IsAnAccessTokenSecret(secret) || secret == otherSecret {
+		t.Errorf("expected random prefixed secrets, got %s and %s", secret, otherSecret)
+	}
+	if HashAccessTokenSecret(secret) != HashAccessTokenSecret(secret) || HashAccessTokenSecret(secret) == HashAccessTokenSecret(otherSecret) {
+		t.Error("expected hashes to be stable and distinct")
+	}
+}
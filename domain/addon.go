@@ -0,0 +1,260 @@
+package domain
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// AddonType is an enum that represents the kind of backing service an add-on provides
+type AddonType string
+
+const (
+	MySQLAddon      AddonType = "mysql"
+	PostgreSQLAddon AddonType = "postgresql"
+	RedisAddon      AddonType = "redis"
+)
+
+// AddonSize is an enum that represents the resources given to an add-on
+type AddonSize string
+
+const (
+	SmallAddon  AddonSize = "small"
+	MediumAddon AddonSize = "medium"
+	LargeAddon  AddonSize = "large"
+)
+
+// AddonResources are the CPU, memory and storage of an add-on, written in Kubernetes units
+type AddonResources struct {
+	CPU     string `json:"cpu"`
+	Memory  string `json:"memory"`
+	Storage string `json:"storage"`
+}
+
+var addonSizeResources = map[AddonSize]AddonResources{
+	SmallAddon:  {CPU: "250m", Memory: "256Mi", Storage: "1Gi"},
+	MediumAddon: {CPU: "500m", Memory: "1Gi", Storage: "10Gi"},
+	LargeAddon:  {CPU: "1000m", Memory: "4Gi", Storage: "50Gi"},
+}
+
+// Resources returns the resources of the size tier
+func (addonSize AddonSize) Resources() AddonResources {
+	return addonSizeResources[addonSize]
+}
+
+// addonTypeImages are the images the add-ons are deployed with
+var addonTypeImages = map[AddonType]string{
+	MySQLAddon:      "mysql:8.0",
+	PostgreSQLAddon: "postgres:16",
+	RedisAddon:      "redis:7",
+}
+
+var addonTypePorts = map[AddonType]uint32{
+	MySQLAddon:      3306,
+	PostgreSQLAddon: 5432,
+	RedisAddon:      6379,
+}
+
+// Image returns the image the add-on is deployed with
+func (addonType AddonType) Image() string {
+	return addonTypeImages[addonType]
+}
+
+// Port returns the port the add-on listens on
+func (addonType AddonType) Port() uint32 {
+	return addonTypePorts[addonType]
+}
+
+// Addon is a struct that represents a backing service (a database or a cache) provisioned in the namespace of its applications.
+// Applications bound to the add-on receive its connection settings as secrets.
+type Addon struct {
+	ID          string    `json:"id" gorm:"primaryKey"`
+	NamespaceID string    `json:"namespaceId" gorm:"size:255;index:idx_addon_namespace_id;not null"`
+	Name        string    `json:"name" gorm:"size:40;not null"`
+	Type        AddonType `json:"type" gorm:"size:20;not null"`
+	Size        AddonSize `json:"size" gorm:"size:20;not null"`
+	// Host and Port are the address of the add-on inside the cluster
+	Host     string `json:"host" gorm:"size:255;not null"`
+	Port     uint32 `json:"port" gorm:"not null"`
+	Username string `json:"username" gorm:"size:63;not null"`
+	Database string `json:"database" gorm:"size:63;not null"`
+	// Credentials are the generated password and the connection URL, encrypted with the data key of the namespace and never written in API responses
+	Credentials *ApplicationSecrets `json:"-" gorm:"type:json"`
+	Bindings    []AddonBinding      `json:"bindings" gorm:"foreignKey:AddonID;references:ID"`
+	CreatedBy   string              `json:"createdBy" gorm:"size:255;not null"`
+	UpdatedAt   time.Time           `json:"updatedAt" gorm:"autoUpdateTime"`
+	CreatedAt   time.Time           `json:"createdAt" gorm:"autoCreateTime"`
+	DeletedAt   *gorm.DeletedAt     `json:"deletedAt" gorm:"index;default:null"`
+}
+
+// AddonBinding is a struct that represents an application receiving the connection settings of an add-on
+type AddonBinding struct {
+	ID            string    `json:"id" gorm:"primaryKey"`
+	AddonID       string    `json:"addonId" gorm:"size:255;uniqueIndex:idx_addon_binding_addon_application;not null"`
+	ApplicationID string    `json:"applicationId" gorm:"size:255;uniqueIndex:idx_addon_binding_addon_application;index:idx_addon_binding_application_id;not null"`
+	Addon         *Addon    `json:"addon,omitempty" gorm:"foreignKey:AddonID;references:ID"`
+	CreatedBy     string    `json:"createdBy" gorm:"size:255;not null"`
+	CreatedAt     time.Time `json:"createdAt" gorm:"autoCreateTime"`
+}
+
+const MaxAddonsByNamespace = 5
+
+// AddonEnvironmentVariablePrefix is the prefix of the secrets describing the add-ons bound to an application
+const AddonEnvironmentVariablePrefix = "HIVE_ADDON_"
+
+// Names of the credentials of an add-on when they go through the secrets cipher
+const (
+	addonPasswordSecretName = "password"
+	addonURLSecretName      = "url"
+)
+
+var addonNameRegex = regexp.MustCompile(`^[a-z]([-a-z0-9]{0,38}[a-z0-9])?$`)
+
+// addonDatabaseName is the database created in MySQL and PostgreSQL add-ons
+const addonDatabaseName = "app"
+
+// addonUsername is the user of MySQL and PostgreSQL add-ons, Redis add-ons use its default user
+const addonUsername = "app"
+
+// ValidateAddon checks the name, type and size of an add-on
+func ValidateAddon(name string, addonType AddonType, size AddonSize) error {
+	if !addonNameRegex.MatchString(name) {
+		return fmt.Errorf("name %s must be at most 40 lowercase alphanumeric characters or '-', starting with a letter and ending with an alphanumeric character", name)
+	}
+	if addonType.Image() == "" {
+		return fmt.Errorf("type %s must be one of %s, %s or %s", addonType, MySQLAddon, PostgreSQLAddon, RedisAddon)
+	}
+	if _, ok := addonSizeResources[size]; !ok {
+		return fmt.Errorf("size %s must be one of %s, %s or %s", size, SmallAddon, MediumAddon, LargeAddon)
+	}
+	return nil
+}
+
+// NewAddon returns an add-on of a namespace with generated credentials, namespace is the name of the namespace on the cluster
+func NewAddon(namespaceID string, namespace string, name string, addonType AddonType, size AddonSize, createdBy string) (*Addon, error) {
+	if err := ValidateAddon(name, addonType, size); err != nil {
+		return nil, err
+	}
+	password, err := newAddonPassword()
+	if err != nil {
+		return nil, err
+	}
+
+	addon := Addon{
+		NamespaceID: namespaceID,
+		Name:        name,
+		Type:        addonType,
+		Size:        size,
+		Host:        AddonServiceHost(name, namespace),
+		Port:        addonType.Port(),
+		Username:    addonUsername,
+		Database:    addonDatabaseName,
+		CreatedBy:   createdBy,
+	}
+	if addonType == RedisAddon {
+		addon.Username = "default"
+		addon.Database = "0"
+	}
+
+	now := time.Now()
+	credentials := ApplicationSecrets{
+		{Name: addonPasswordSecretName, Val: password, Preview: MaskSecretValue(password), Version: 1, UpdatedAt: &now},
+		{Name: addonURLSecretName, Val: addon.connectionURL(password), Preview: MaskSecretValue(password), Version: 1, UpdatedAt: &now},
+	}
+	addon.Credentials = &credentials
+	return &addon, nil
+}
+
+// newAddonPassword returns a random password made of hexadecimal characters, so that it can be written in connection URLs as is
+func newAddonPassword() (string, error) {
+	randomBytes := make([]byte, 16)
+	if _, err := rand.Read(randomBytes); err != nil {
+		return "", fmt.Errorf("error generating add-on password: %w", err)
+	}
+	return hex.EncodeToString(randomBytes), nil
+}
+
+func (addon Addon) connectionURL(password string) string {
+	scheme := string(addon.Type)
+	return fmt.Sprintf("%s://%s:%s@%s:%d/%s", scheme, addon.Username, password, addon.Host, addon.Port, addon.Database)
+}
+
+// Password returns the password of the add-on, as stored in its credentials
+func (addon Addon) Password() ApplicationSecret {
+	return addon.credential(addonPasswordSecretName)
+}
+
+// URL returns the connection URL of the add-on, as stored in its credentials
+func (addon Addon) URL() ApplicationSecret {
+	return addon.credential(addonURLSecretName)
+}
+
+func (addon Addon) credential(name string) ApplicationSecret {
+	if addon.Credentials == nil {
+		return ApplicationSecret{Name: name}
+	}
+	credential, found := addon.Credentials.Find(name)
+	if !found {
+		return ApplicationSecret{Name: name}
+	}
+	return credential
+}
+
+// BindingSecrets returns the secrets giving an application the connection settings of the add-on,
+// the password and the URL keep the encryption of the credentials
+func (addon Addon) BindingSecrets() ApplicationSecrets {
+	prefix := AddonEnvironmentVariableNamePrefix(addon.Name)
+	plainSecret := func(suffix string, value string) ApplicationSecret {
+		return ApplicationSecret{Name: prefix + suffix, Val: value, Preview: MaskSecretValue(value), Version: 1}
+	}
+	encryptedSecret := func(suffix string, credential ApplicationSecret) ApplicationSecret {
+		credential.Name = prefix + suffix
+		return credential
+	}
+	return ApplicationSecrets{
+		plainSecret("HOST", addon.Host),
+		plainSecret("PORT", fmt.Sprintf("%d", addon.Port)),
+		plainSecret("USERNAME", addon.Username),
+		plainSecret("DATABASE", addon.Database),
+		encryptedSecret("PASSWORD", addon.Password()),
+		encryptedSecret("URL", addon.URL()),
+	}
+}
+
+// AddonEnvironmentVariableNamePrefix returns the prefix of the secrets describing an add-on,
+// e.g. HIVE_ADDON_MAIN_DB_ for the add-on main-db
+func AddonEnvironmentVariableNamePrefix(addonName string) string {
+	return AddonEnvironmentVariablePrefix + strings.ToUpper(strings.ReplaceAll(addonName, "-", "_")) + "_"
+}
+
+// AddonServiceName returns the name of the service, and of the StatefulSet, of an add-on on the cluster
+func AddonServiceName(addonName string) string {
+	return fmt.Sprintf("%s-addon", addonName)
+}
+
+// AddonServiceHost returns the host of the service of an add-on inside the cluster
+func AddonServiceHost(addonName string, namespace string) string {
+	return fmt.Sprintf("%s.%s.svc.cluster.local", AddonServiceName(addonName), namespace)
+}
+
+// AddonBindingSecrets returns the secrets of the add-ons bound to an application, without the ones whose name is already used by the given secrets
+func AddonBindingSecrets(bindings []AddonBinding, secrets ApplicationSecrets) ApplicationSecrets {
+	bindingSecrets := ApplicationSecrets{}
+	for _, binding := range bindings {
+		if binding.Addon == nil {
+			continue
+		}
+		for _, bindingSecret := range binding.Addon.BindingSecrets() {
+			if _, found := secrets.Find(bindingSecret.Name); found {
+				continue
+			}
+			bindingSecrets = append(bindingSecrets, bindingSecret)
+		}
+	}
+	return bindingSecrets
+}
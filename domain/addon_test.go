@@ -0,0 +1,71 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNewAddon_GeneratesCredentials(t *testing.T) {
+	addon, err := NewAddon("team-id", "team", "main-db", PostgreSQLAddon, SmallAddon, "admin")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if addon.Host != "main-db-addon.team.svc.cluster.local" || addon.Port != 5432 {
+		t.Errorf("expected the address of the main-db service, got %s:%d", addon.Host, addon.Port)
+	}
+	password := addon.Password().Val
+	if len(password) != 32 {
+		t.Errorf("expected a 32 characters password, got %q", password)
+	}
+	expectedURL := "postgresql://app:" + password + "@main-db-addon.team.svc.cluster.local:5432/app"
+	if addon.URL().Val != expectedURL {
+		t.Errorf("expected the URL %s, got %s", expectedURL, addon.URL().Val)
+	}
+
+	otherAddon, _ := NewAddon("team-id", "team", "main-db", PostgreSQLAddon, SmallAddon, "admin")
+	if otherAddon.Password().Val == password {
+		t.Error("expected every addon to get its own password")
+	}
+}
+
+func TestValidateAddon(t *testing.T) {
+	if err := ValidateAddon("cache", RedisAddon, LargeAddon); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	for _, invalid := range []struct {
+		name      string
+		addonType AddonType
+		size      AddonSize
+	}{
+		{"Main-DB", MySQLAddon, SmallAddon},
+		{"1db", MySQLAddon, SmallAddon},
+		{strings.Repeat("a", 41), MySQLAddon, SmallAddon},
+		{"db", "mongodb", SmallAddon},
+		{"db", MySQLAddon, "huge"},
+	} {
+		if err := ValidateAddon(invalid.name, invalid.addonType, invalid.size); err == nil {
+			t.Errorf("expected %+v to be rejected", invalid)
+		}
+	}
+}
+
+func TestAddonBindingSecrets_KeepApplicationSecrets(t *testing.T) {
+	addon, err := NewAddon("team-id", "team", "cache", RedisAddon, SmallAddon, "admin")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	applicationSecrets := ApplicationSecrets{{Name: "HIVE_ADDON_CACHE_URL", Val: "redis://external:6379"}}
+
+	bindingSecrets := AddonBindingSecrets([]AddonBinding{{Addon: addon}}, applicationSecrets)
+	if len(bindingSecrets) != 5 {
+		t.Fatalf("expected 5 secrets besides the one of the application, got %d", len(bindingSecrets))
+	}
+	host, _ := bindingSecrets.Find("HIVE_ADDON_CACHE_HOST")
+	password, _ := bindingSecrets.Find("HIVE_ADDON_CACHE_PASSWORD")
+	if host.Val != "cache-addon.team.svc.cluster.local" || password.Val != addon.Password().Val {
+		t.Errorf("expected the host and password of the addon, got %s and %s", host.Val, password.Val)
+	}
+	if _, found := bindingSecrets.Find("HIVE_ADDON_CACHE_URL"); found {
+		t.Error("expected the secret of the application to win")
+	}
+}
@@ -12,6 +12,8 @@ type ImageRegistry string
 const (
 	DockerHubRegistry ImageRegistry = "dockerhub"
 	PrivateRegistry   ImageRegistry = "pcr"
+	// CustomRegistry is any registry for which the namespace has a registry credential
+	CustomRegistry ImageRegistry = "custom"
 )
 
 // Application is a struct that represents a user's application
@@ -20,7 +22,11 @@ type Application struct {
 	Name                      string                                                    `json:"name" gorm:"size:100;not null"`
 	Description               string                                                    `json:"description" gorm:"size:1000;not null"`
 	Image                     string                                                    `json:"image" gorm:"size:1000;not null"`
-	Registry                  ImageRegistry                                             `json:"registry" gorm:"type:enum('dockerhub', 'pcr');default:'dockerhub'"`
+	ImageDigest               string                                                    `json:"imageDigest" gorm:"size:100"`         // The digest the image resolved to when it was verified
+	PinImageDigest            bool                                                      `json:"pinImageDigest" gorm:"default:false"` // Deploys the image by its digest so that every replica runs the same image
+	Registry                  ImageRegistry                                             `json:"registry" gorm:"type:enum('dockerhub', 'pcr', 'custom');default:'dockerhub'"`
+	RegistryCredentialID      *string                                                   `json:"registryCredentialId" gorm:"size:255"` // The credential pulling the image from the custom registry
+	RegistryCredential        *RegistryCredential                                       `json:"-" gorm:"foreignKey:RegistryCredentialID;references:ID"`
 	UserID                    string                                                    `json:"userId" gorm:"size:100;not null"`
 	NamespaceID               string                                                    `json:"namespaceId" gorm:"size:100;not null"`
 	Namespace                 Namespace                                                 `json:"namespace" gorm:"foreignKey:NamespaceID;references:ID;not null"`
@@ -31,6 +37,11 @@ type Application struct {
 	Secrets                   *ApplicationSecrets                                       `json:"secrets" gorm:"type:json"`
 	ContainerSpecifications   *datatypes.JSONType[ApplicationContainerSpecifications]   `json:"containerSpecifications" gorm:"type:json"`
 	ScalabilitySpecifications *datatypes.JSONType[ApplicationScalabilitySpecifications] `json:"scalabilitySpecifications" gorm:"type:json"`
+	Dependencies              *ApplicationDependencies                                  `json:"dependencies" gorm:"type:json"` // The applications of the namespace this application calls
+	Ingress                   *ApplicationIngress                                       `json:"ingress" gorm:"type:json"`      // An application without ingress is reached on / of its default host
+	ConfigFiles               *ApplicationConfigFiles                                   `json:"configFiles" gorm:"type:json"`  // Files mounted read-only in the container
+	AddonBindings             []AddonBinding                                            `json:"addonBindings" gorm:"foreignKey:ApplicationID;references:ID"`
+	ConfigGroupAttachments    []ConfigGroupAttachment                                   `json:"configGroupAttachments" gorm:"foreignKey:ApplicationID;references:ID"`
 	AdministratorEmail        string                                                    `json:"administratorEmail" gorm:"size:320;not null"`
 	Status                    *ApplicationDeploymentStatus                              `json:"status"`
 	UpdatedAt                 time.Time                                                 `json:"updatedAt" gorm:"autoUpdateTime;not null"`
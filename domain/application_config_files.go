@@ -0,0 +1,229 @@
+package domain
+
+import (
+	"cloud-app-hive/controllers/errors"
+	"crypto/sha256"
+	"database/sql/driver"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// ConfigFileEncoding is an enum that represents how the content of a config file is written in requests
+type ConfigFileEncoding string
+
+const (
+	TextConfigFile   ConfigFileEncoding = "text"
+	Base64ConfigFile ConfigFileEncoding = "base64"
+)
+
+// ApplicationConfigFile is a struct that represents a file mounted read-only in the container of an application, e.g. nginx.conf.
+// The content of a secret file is encrypted like the secrets of the application and never written in API responses.
+type ApplicationConfigFile struct {
+	Name string `json:"name"`
+	// MountPath is the absolute path of the file in the container
+	MountPath string             `json:"mountPath"`
+	Content   string             `json:"content"`
+	Encoding  ConfigFileEncoding `json:"encoding"` // text when empty
+	Secret    bool               `json:"secret"`
+	// Size is the number of bytes of the file, computed when its content is set
+	Size int `json:"size"`
+}
+
+// applicationConfigFileResponse is the JSON representation of a config file in API responses, without the content of secret files
+type applicationConfigFileResponse struct {
+	Name      string             `json:"name"`
+	MountPath string             `json:"mountPath"`
+	Content   string             `json:"content,omitempty"`
+	Encoding  ConfigFileEncoding `json:"encoding"`
+	Secret    bool               `json:"secret"`
+	Size      int                `json:"size"`
+}
+
+// storedApplicationConfigFile has the JSON tags of ApplicationConfigFile but not its MarshalJSON, so that the content is kept in the database
+type storedApplicationConfigFile ApplicationConfigFile
+
+// MarshalJSON writes the config file without its content if it is a secret file
+func (applicationConfigFile ApplicationConfigFile) MarshalJSON() ([]byte, error) {
+	response := applicationConfigFileResponse{
+		Name:      applicationConfigFile.Name,
+		MountPath: applicationConfigFile.MountPath,
+		Content:   applicationConfigFile.Content,
+		Encoding:  applicationConfigFile.Encoding,
+		Secret:    applicationConfigFile.Secret,
+		Size:      applicationConfigFile.Size,
+	}
+	if applicationConfigFile.Secret {
+		response.Content = ""
+	}
+	return json.Marshal(response)
+}
+
+// ApplicationConfigFiles is a slice of ApplicationConfigFile
+// swagger:model ApplicationConfigFiles
+type ApplicationConfigFiles []ApplicationConfigFile
+
+func (applicationConfigFiles *ApplicationConfigFiles) Scan(value interface{}) error {
+	bytes, ok := value.([]byte)
+	if !ok {
+		return fmt.Errorf("failed to unmarshal JSONB value: %v", value)
+	}
+
+	return json.Unmarshal(bytes, &applicationConfigFiles)
+}
+
+func (applicationConfigFiles *ApplicationConfigFiles) Value() (driver.Value, error) {
+	if applicationConfigFiles == nil {
+		return json.Marshal(nil)
+	}
+	storedConfigFiles := make([]storedApplicationConfigFile, 0, len(*applicationConfigFiles))
+	for _, configFile := range *applicationConfigFiles {
+		storedConfigFiles = append(storedConfigFiles, storedApplicationConfigFile(configFile))
+	}
+	return json.Marshal(storedConfigFiles)
+}
+
+const MaxConfigFilesByApplication = 10
+
+// MaxConfigFileSize is the maximum size of a config file in bytes
+const MaxConfigFileSize = 256 * 1024
+
+// MaxConfigFilesSize is the maximum size of all the config files of an application in bytes,
+// so that they fit in a ConfigMap and a Secret whatever their encoding
+const MaxConfigFilesSize = 512 * 1024
+
+// configFileNameRegex matches the names that are valid keys of ConfigMaps and valid names of annotations
+const configFileNameRegex = "^[a-zA-Z0-9]([-._a-zA-Z0-9]{0,61}[a-zA-Z0-9])?$"
+
+// Decode returns the bytes of the file
+func (applicationConfigFile ApplicationConfigFile) Decode() ([]byte, error) {
+	if applicationConfigFile.Encoding == Base64ConfigFile {
+		return base64.StdEncoding.DecodeString(applicationConfigFile.Content)
+	}
+	return []byte(applicationConfigFile.Content), nil
+}
+
+// Checksum returns the SHA-256 of the file as stored, it changes each time the file is written
+func (applicationConfigFile ApplicationConfigFile) Checksum() string {
+	checksum := sha256.Sum256([]byte(applicationConfigFile.MountPath + "\n" + string(applicationConfigFile.Encoding) + "\n" + applicationConfigFile.Content))
+	return hex.EncodeToString(checksum[:])
+}
+
+// Validate checks the names, paths, encodings and sizes of the config files.
+// The content of a secret file given without content is not checked, it keeps the stored one.
+func (applicationConfigFiles ApplicationConfigFiles) Validate() error {
+	if len(applicationConfigFiles) > MaxConfigFilesByApplication {
+		return errors.NewInvalidApplicationConfigFilesError(fmt.Sprintf("an application cannot have more than %d config files", MaxConfigFilesByApplication))
+	}
+	names := map[string]bool{}
+	mountPaths := []string{}
+	totalSize := 0
+	for _, configFile := range applicationConfigFiles {
+		if match, _ := regexp.MatchString(configFileNameRegex, configFile.Name); !match {
+			return errors.NewInvalidApplicationConfigFilesError(fmt.Sprintf("name %s must match the following regex: %s", configFile.Name, configFileNameRegex))
+		}
+		if names[configFile.Name] {
+			return errors.NewInvalidApplicationConfigFilesError(fmt.Sprintf("config file %s is defined twice", configFile.Name))
+		}
+		names[configFile.Name] = true
+
+		if !path.IsAbs(configFile.MountPath) || path.Clean(configFile.MountPath) != configFile.MountPath || configFile.MountPath == "/" {
+			return errors.NewInvalidApplicationConfigFilesError(fmt.Sprintf("mount path %s of config file %s must be a clean absolute path", configFile.MountPath, configFile.Name))
+		}
+		for _, mountPath := range mountPaths {
+			if mountPath == configFile.MountPath || strings.HasPrefix(mountPath, configFile.MountPath+"/") || strings.HasPrefix(configFile.MountPath, mountPath+"/") {
+				return errors.NewInvalidApplicationConfigFilesError(fmt.Sprintf("mount path %s of config file %s overlaps the mount path %s", configFile.MountPath, configFile.Name, mountPath))
+			}
+		}
+		mountPaths = append(mountPaths, configFile.MountPath)
+
+		if configFile.Encoding != "" && configFile.Encoding != TextConfigFile && configFile.Encoding != Base64ConfigFile {
+			return errors.NewInvalidApplicationConfigFilesError(fmt.Sprintf("encoding of config file %s must be %s or %s", configFile.Name, TextConfigFile, Base64ConfigFile))
+		}
+		if configFile.Secret && configFile.Content == "" {
+			continue
+		}
+		content, err := configFile.Decode()
+		if err != nil {
+			return errors.NewInvalidApplicationConfigFilesError(fmt.Sprintf("content of config file %s is not valid base64", configFile.Name))
+		}
+		if len(content) > MaxConfigFileSize {
+			return errors.NewInvalidApplicationConfigFilesError(fmt.Sprintf("config file %s must not be larger than %d bytes", configFile.Name, MaxConfigFileSize))
+		}
+		totalSize += len(content)
+	}
+	if totalSize > MaxConfigFilesSize {
+		return errors.NewInvalidApplicationConfigFilesError(fmt.Sprintf("the config files of an application must not be larger than %d bytes", MaxConfigFilesSize))
+	}
+	return nil
+}
+
+// Find returns the config file with the given name
+func (applicationConfigFiles ApplicationConfigFiles) Find(name string) (ApplicationConfigFile, bool) {
+	for _, configFile := range applicationConfigFiles {
+		if configFile.Name == name {
+			return configFile, true
+		}
+	}
+	return ApplicationConfigFile{}, false
+}
+
+// Merge returns the config files to store when the config files of an application are replaced by these ones.
+// A secret file without content keeps the content of the current secret file with the same name, the other files get their size computed.
+func (applicationConfigFiles ApplicationConfigFiles) Merge(currentConfigFiles *ApplicationConfigFiles) (ApplicationConfigFiles, error) {
+	current := ApplicationConfigFiles{}
+	if currentConfigFiles != nil {
+		current = *currentConfigFiles
+	}
+
+	mergedConfigFiles := ApplicationConfigFiles{}
+	for _, configFile := range applicationConfigFiles {
+		if configFile.Encoding == "" {
+			configFile.Encoding = TextConfigFile
+		}
+		if configFile.Secret && configFile.Content == "" {
+			currentConfigFile, exists := current.Find(configFile.Name)
+			if !exists || !currentConfigFile.Secret || currentConfigFile.Encoding != configFile.Encoding {
+				return nil, errors.NewInvalidApplicationConfigFilesError(fmt.Sprintf("secret config file %s has no content and no content is stored yet", configFile.Name))
+			}
+			configFile.Content = currentConfigFile.Content
+			configFile.Size = currentConfigFile.Size
+			mergedConfigFiles = append(mergedConfigFiles, configFile)
+			continue
+		}
+		content, err := configFile.Decode()
+		if err != nil {
+			return nil, errors.NewInvalidApplicationConfigFilesError(fmt.Sprintf("content of config file %s is not valid base64", configFile.Name))
+		}
+		configFile.Size = len(content)
+		mergedConfigFiles = append(mergedConfigFiles, configFile)
+	}
+	return mergedConfigFiles, nil
+}
+
+// SecretContents returns the contents of the secret files as secrets named after the files, so that they go through the secrets cipher
+func (applicationConfigFiles ApplicationConfigFiles) SecretContents() ApplicationSecrets {
+	contents := ApplicationSecrets{}
+	for _, configFile := range applicationConfigFiles {
+		if configFile.Secret {
+			contents = append(contents, ApplicationSecret{Name: configFile.Name, Val: configFile.Content})
+		}
+	}
+	return contents
+}
+
+// WithSecretContents returns the config files with the contents of the secret files replaced by the given ones
+func (applicationConfigFiles ApplicationConfigFiles) WithSecretContents(contents ApplicationSecrets) ApplicationConfigFiles {
+	configFiles := make(ApplicationConfigFiles, 0, len(applicationConfigFiles))
+	for _, configFile := range applicationConfigFiles {
+		if content, found := contents.Find(configFile.Name); found && configFile.Secret {
+			configFile.Content = content.Val
+		}
+		configFiles = append(configFiles, configFile)
+	}
+	return configFiles
+}
@@ -0,0 +1,75 @@
+package domain
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestApplicationConfigFiles_Validate(t *testing.T) {
+	configFiles := ApplicationConfigFiles{
+		{Name: "nginx.conf", MountPath: "/etc/nginx/nginx.conf", Content: "worker_processes 1;"},
+		{Name: "favicon.ico", MountPath: "/usr/share/nginx/html/favicon.ico", Content: "AAEC", Encoding: Base64ConfigFile},
+		{Name: "tls.key", MountPath: "/etc/ssl/private/tls.key", Secret: true},
+	}
+	if err := configFiles.Validate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	invalidConfigFiles := map[string]ApplicationConfigFiles{
+		"name":            {{Name: "nginx/conf", MountPath: "/etc/nginx/nginx.conf"}},
+		"relative path":   {{Name: "nginx.conf", MountPath: "etc/nginx/nginx.conf"}},
+		"unclean path":    {{Name: "nginx.conf", MountPath: "/etc/nginx/../nginx.conf"}},
+		"root path":       {{Name: "nginx.conf", MountPath: "/"}},
+		"duplicate name":  {{Name: "nginx.conf", MountPath: "/etc/nginx/nginx.conf"}, {Name: "nginx.conf", MountPath: "/etc/nginx/default.conf"}},
+		"duplicate path":  {{Name: "a.conf", MountPath: "/etc/app.conf"}, {Name: "b.conf", MountPath: "/etc/app.conf"}},
+		"nested path":     {{Name: "a.conf", MountPath: "/etc/app"}, {Name: "b.conf", MountPath: "/etc/app/b.conf"}},
+		"encoding":        {{Name: "nginx.conf", MountPath: "/etc/nginx/nginx.conf", Encoding: "gzip"}},
+		"base64 content":  {{Name: "favicon.ico", MountPath: "/favicon.ico", Content: "not base64!", Encoding: Base64ConfigFile}},
+		"size of a file":  {{Name: "large.txt", MountPath: "/large.txt", Content: strings.Repeat("a", MaxConfigFileSize+1)}},
+		"size of files":   {{Name: "a.txt", MountPath: "/a.txt", Content: strings.Repeat("a", MaxConfigFileSize)}, {Name: "b.txt", MountPath: "/b.txt", Content: strings.Repeat("b", MaxConfigFileSize)}, {Name: "c.txt", MountPath: "/c.txt", Content: "c"}},
+		"number of files": make(ApplicationConfigFiles, MaxConfigFilesByApplication+1),
+	}
+	for name, invalid := range invalidConfigFiles {
+		if err := invalid.Validate(); err == nil {
+			t.Errorf("expected an error for the invalid %s", name)
+		}
+	}
+}
+
+func TestApplicationConfigFiles_MergeKeepsSecretContents(t *testing.T) {
+	current := ApplicationConfigFiles{{Name: "tls.key", MountPath: "/etc/ssl/private/tls.key", Content: "enc:v1:key-id:c2VhbGVk", Encoding: TextConfigFile, Secret: true, Size: 6}}
+
+	merged, err := ApplicationConfigFiles{
+		{Name: "tls.key", MountPath: "/etc/ssl/tls.key", Secret: true},
+		{Name: "favicon.ico", MountPath: "/favicon.ico", Content: "AAEC", Encoding: Base64ConfigFile},
+	}.Merge(&current)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if merged[0].Content != current[0].Content || merged[0].Size != 6 || merged[0].MountPath != "/etc/ssl/tls.key" {
+		t.Errorf("expected the secret file to keep its content at its new path, got %+v", merged[0])
+	}
+	if merged[1].Size != 3 {
+		t.Errorf("expected the size of the decoded file, got %d", merged[1].Size)
+	}
+
+	if _, err := (ApplicationConfigFiles{{Name: "other.key", MountPath: "/other.key", Secret: true}}).Merge(&current); err == nil {
+		t.Errorf("expected an error for a new secret file without content")
+	}
+}
+
+func TestApplicationConfigFiles_SecretContentsAreNotWrittenInResponses(t *testing.T) {
+	configFiles := ApplicationConfigFiles{
+		{Name: "nginx.conf", MountPath: "/etc/nginx/nginx.conf", Content: "worker_processes 1;"},
+		{Name: "tls.key", MountPath: "/etc/ssl/private/tls.key", Content: "private key", Secret: true},
+	}
+	response, _ := json.Marshal(configFiles)
+	if strings.Contains(string(response), "private key") || !strings.Contains(string(response), "worker_processes 1;") {
+		t.Errorf("expected only the content of the plain file in the response, got %s", response)
+	}
+	stored, _ := configFiles.Value()
+	if !strings.Contains(string(stored.([]byte)), "private key") {
+		t.Errorf("expected the stored value to keep the content of the secret file, got %s", stored)
+	}
+}
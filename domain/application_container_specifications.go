@@ -2,6 +2,7 @@ package domain
 
 import (
 	customErrors "cloud-app-hive/controllers/errors"
+	domainErrors "cloud-app-hive/domain/errors"
 	"database/sql/driver"
 	"encoding/json"
 	"fmt"
@@ -289,3 +290,26 @@ func NextMemoryLimit(memoryLimit ContainerMemoryLimit) ApplicationMemoryLimit {
 	}
 	return ApplicationMemoryLimitChoice[len(ApplicationMemoryLimitChoice)-1]
 }
+
+// VerticallyScaledUp returns the specifications with the next cpu and memory limits,
+// it fails when the application already has the maximum cpu and memory limits
+func (applicationContainerSpecifications ApplicationContainerSpecifications) VerticallyScaledUp() (ApplicationContainerSpecifications, error) {
+	if err := applicationContainerSpecifications.Validate(); err != nil {
+		return ApplicationContainerSpecifications{}, err
+	}
+	if applicationContainerSpecifications.CPULimit == nil || applicationContainerSpecifications.MemoryLimit == nil {
+		return ApplicationContainerSpecifications{}, customErrors.NewInvalidApplicationContainerSpecificationsError("cpu and memory limits are required to scale vertically")
+	}
+
+	if IsAtMaxCPULimit(*applicationContainerSpecifications.CPULimit) && IsAtMaxMemoryLimit(*applicationContainerSpecifications.MemoryLimit) {
+		return ApplicationContainerSpecifications{}, domainErrors.NewInvalidApplicationCannotVerticallyScaleBecauseMaxSpecsError(
+			"application is already at maximum cpu and memory limits",
+		)
+	}
+	nextCPULimit := NextCPULimit(*applicationContainerSpecifications.CPULimit)
+	nextMemoryLimit := NextMemoryLimit(*applicationContainerSpecifications.MemoryLimit)
+	return ApplicationContainerSpecifications{
+		CPULimit:    &ContainerCpuLimit{Val: nextCPULimit.Value, Unit: nextCPULimit.Unit},
+		MemoryLimit: &ContainerMemoryLimit{Val: nextMemoryLimit.Value, Unit: nextMemoryLimit.Unit},
+	}, nil
+}
@@ -0,0 +1,183 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"cloud-app-hive/controllers/errors"
+)
+
+// ServiceEnvironmentVariablePrefix is the prefix of the environment variables describing the services of the dependencies of an application
+const ServiceEnvironmentVariablePrefix = "HIVE_SVC_"
+
+// ApplicationDependency is an application of the same namespace that an application calls.
+// The port of the dependency is recorded when the application is deployed, so that the application is deployed again when it changes.
+type ApplicationDependency struct {
+	Name string `json:"name"`
+	// Port is the port of the dependency the application was deployed with, 0 while no application of the namespace has this name
+	Port uint32 `json:"port"`
+}
+
+// ApplicationDependencies is a slice of ApplicationDependency
+// swagger:model ApplicationDependencies
+type ApplicationDependencies []ApplicationDependency
+
+func (applicationDependencies *ApplicationDependencies) Scan(value interface{}) error {
+	bytes, ok := value.([]byte)
+	if !ok {
+		return fmt.Errorf("failed to unmarshal JSONB value: %v", value)
+	}
+
+	return json.Unmarshal(bytes, &applicationDependencies)
+}
+
+func (applicationDependencies *ApplicationDependencies) Value() (driver.Value, error) {
+	return json.Marshal(applicationDependencies)
+}
+
+// NewApplicationDependencies returns the dependencies on the applications with the given names, their ports are set by Resolve
+func NewApplicationDependencies(names []string) ApplicationDependencies {
+	applicationDependencies := ApplicationDependencies{}
+	for _, name := range names {
+		applicationDependencies = append(applicationDependencies, ApplicationDependency{Name: name})
+	}
+	return applicationDependencies
+}
+
+// Names returns the names of the dependencies
+func (applicationDependencies ApplicationDependencies) Names() []string {
+	names := []string{}
+	for _, dependency := range applicationDependencies {
+		names = append(names, dependency.Name)
+	}
+	return names
+}
+
+// DependsOn returns true if one of the dependencies has the given name
+func (applicationDependencies ApplicationDependencies) DependsOn(name string) bool {
+	for _, dependency := range applicationDependencies {
+		if dependency.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate checks that the dependencies of the application name other applications, with distinct environment variables
+func (applicationDependencies ApplicationDependencies) Validate(applicationName string) error {
+	if len(applicationDependencies) >= MaxApplicationsByNamespace {
+		return errors.NewInvalidApplicationDependenciesError(fmt.Sprintf("an application cannot depend on more than %d applications", MaxApplicationsByNamespace-1))
+	}
+	environmentVariablePrefixes := map[string]string{}
+	for _, dependency := range applicationDependencies {
+		if match, _ := regexp.MatchString(applicationManifestNameRegex, dependency.Name); !match {
+			return errors.NewInvalidApplicationDependenciesError(fmt.Sprintf("dependency '%s' must match the following regex: %s", dependency.Name, applicationManifestNameRegex))
+		}
+		if dependency.Name == applicationName {
+			return errors.NewInvalidApplicationDependenciesError(fmt.Sprintf("application %s cannot depend on itself", applicationName))
+		}
+		prefix := ServiceEnvironmentVariableNamePrefix(dependency.Name)
+		if otherName, exists := environmentVariablePrefixes[prefix]; exists {
+			return errors.NewInvalidApplicationDependenciesError(fmt.Sprintf("dependencies %s and %s would both be described by the %s environment variables", otherName, dependency.Name, prefix))
+		}
+		environmentVariablePrefixes[prefix] = dependency.Name
+	}
+	return nil
+}
+
+// Resolve returns the dependencies with the ports of the applications of the namespace, 0 for the ones that do not exist
+func (applicationDependencies ApplicationDependencies) Resolve(namespaceApplications []Application) ApplicationDependencies {
+	ports := map[string]uint32{}
+	for _, application := range namespaceApplications {
+		ports[application.Name] = application.Port
+	}
+	resolvedDependencies := ApplicationDependencies{}
+	for _, dependency := range applicationDependencies {
+		resolvedDependencies = append(resolvedDependencies, ApplicationDependency{Name: dependency.Name, Port: ports[dependency.Name]})
+	}
+	return resolvedDependencies
+}
+
+// EnvironmentVariables returns the host, port and URL of the service of every dependency that exists,
+// namespace is the name of the namespace on the cluster
+func (applicationDependencies ApplicationDependencies) EnvironmentVariables(namespace string) ApplicationEnvironmentVariables {
+	environmentVariables := ApplicationEnvironmentVariables{}
+	for _, dependency := range applicationDependencies {
+		if dependency.Port == 0 {
+			continue
+		}
+		prefix := ServiceEnvironmentVariableNamePrefix(dependency.Name)
+		host := ApplicationServiceHost(dependency.Name, namespace)
+		environmentVariables = append(environmentVariables,
+			ApplicationEnvironmentVariable{Name: prefix + "_HOST", Val: host},
+			ApplicationEnvironmentVariable{Name: prefix + "_PORT", Val: fmt.Sprintf("%d", dependency.Port)},
+			ApplicationEnvironmentVariable{Name: prefix + "_URL", Val: ApplicationServiceURL(dependency.Name, namespace, dependency.Port)},
+		)
+	}
+	return environmentVariables
+}
+
+// ServiceEnvironmentVariableNamePrefix returns the prefix of the environment variables describing the service of an application,
+// e.g. HIVE_SVC_USERS_API for the application users-api
+func ServiceEnvironmentVariableNamePrefix(applicationName string) string {
+	return ServiceEnvironmentVariablePrefix + strings.ToUpper(strings.ReplaceAll(applicationName, "-", "_"))
+}
+
+// ApplicationServiceName returns the name of the service of an application on the cluster
+func ApplicationServiceName(applicationName string) string {
+	return fmt.Sprintf("%s-service", applicationName)
+}
+
+// ApplicationServiceHost returns the host of the service of an application inside the cluster
+func ApplicationServiceHost(applicationName string, namespace string) string {
+	return fmt.Sprintf("%s.%s.svc.cluster.local", ApplicationServiceName(applicationName), namespace)
+}
+
+// ApplicationServiceURL returns the URL of the service of an application inside the cluster
+func ApplicationServiceURL(applicationName string, namespace string, port uint32) string {
+	return fmt.Sprintf("http://%s:%d", ApplicationServiceHost(applicationName, namespace), port)
+}
+
+// ApplicationService describes how the applications of a namespace reach one of them
+type ApplicationService struct {
+	ApplicationID string `json:"applicationId"`
+	Name          string `json:"name"`
+	Host          string `json:"host"`
+	Port          uint32 `json:"port"`
+	URL           string `json:"url"`
+	// EnvironmentVariablePrefix is the prefix of the environment variables given to the applications depending on this one
+	EnvironmentVariablePrefix string   `json:"environmentVariablePrefix"`
+	Dependencies              []string `json:"dependencies"`
+	// Dependents are the applications of the namespace depending on this one
+	Dependents []string `json:"dependents"`
+}
+
+// NewApplicationServices returns the services of the applications of a namespace
+func NewApplicationServices(namespace Namespace, applications []Application) []ApplicationService {
+	applicationServices := []ApplicationService{}
+	for _, application := range applications {
+		applicationService := ApplicationService{
+			ApplicationID:             application.ID,
+			Name:                      application.Name,
+			Host:                      ApplicationServiceHost(application.Name, namespace.Name),
+			Port:                      application.Port,
+			URL:                       ApplicationServiceURL(application.Name, namespace.Name, application.Port),
+			EnvironmentVariablePrefix: ServiceEnvironmentVariableNamePrefix(application.Name),
+			Dependencies:              []string{},
+			Dependents:                []string{},
+		}
+		if application.Dependencies != nil {
+			applicationService.Dependencies = application.Dependencies.Names()
+		}
+		for _, otherApplication := range applications {
+			if otherApplication.Dependencies != nil && otherApplication.Dependencies.DependsOn(application.Name) {
+				applicationService.Dependents = append(applicationService.Dependents, otherApplication.Name)
+			}
+		}
+		applicationServices = append(applicationServices, applicationService)
+	}
+	return applicationServices
+}
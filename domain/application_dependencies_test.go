@@ -0,0 +1,58 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestApplicationDependencies_Validate(t *testing.T) {
+	if err := NewApplicationDependencies([]string{"users-api", "billing"}).Validate("frontend"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	invalidDependencies := map[string][]string{
+		"name":                 {"api.v2"},
+		"itself":               {"frontend"},
+		"environment variable": {"users-api", "Users_API"},
+	}
+	for name, names := range invalidDependencies {
+		if err := NewApplicationDependencies(names).Validate("frontend"); err == nil {
+			t.Errorf("expected an error for the invalid %s", name)
+		}
+	}
+}
+
+func TestApplicationDependencies_ResolveAndEnvironmentVariables(t *testing.T) {
+	dependencies := NewApplicationDependencies([]string{"users-api", "billing"}).Resolve([]Application{
+		{Name: "users-api", Port: 8080},
+		{Name: "frontend", Port: 80},
+	})
+	expectedDependencies := ApplicationDependencies{{Name: "users-api", Port: 8080}, {Name: "billing", Port: 0}}
+	if !reflect.DeepEqual(dependencies, expectedDependencies) {
+		t.Fatalf("expected %v, got %v", expectedDependencies, dependencies)
+	}
+
+	expectedEnvironmentVariables := ApplicationEnvironmentVariables{
+		{Name: "HIVE_SVC_USERS_API_HOST", Val: "users-api-service.team.svc.cluster.local"},
+		{Name: "HIVE_SVC_USERS_API_PORT", Val: "8080"},
+		{Name: "HIVE_SVC_USERS_API_URL", Val: "http://users-api-service.team.svc.cluster.local:8080"},
+	}
+	if environmentVariables := dependencies.EnvironmentVariables("team"); !reflect.DeepEqual(environmentVariables, expectedEnvironmentVariables) {
+		t.Errorf("expected only the existing dependency to be described, got %v", environmentVariables)
+	}
+}
+
+func TestNewApplicationServices(t *testing.T) {
+	frontendDependencies := ApplicationDependencies{{Name: "users-api", Port: 8080}}
+	services := NewApplicationServices(Namespace{Name: "team"}, []Application{
+		{ID: "frontend-id", Name: "frontend", Port: 80, Dependencies: &frontendDependencies},
+		{ID: "users-api-id", Name: "users-api", Port: 8080},
+	})
+
+	if len(services) != 2 || services[1].URL != "http://users-api-service.team.svc.cluster.local:8080" || services[1].EnvironmentVariablePrefix != "HIVE_SVC_USERS_API" {
+		t.Fatalf("expected the services of both applications, got %+v", services)
+	}
+	if !reflect.DeepEqual(services[0].Dependencies, []string{"users-api"}) || !reflect.DeepEqual(services[1].Dependents, []string{"frontend"}) {
+		t.Errorf("expected frontend to depend on users-api, got %+v", services)
+	}
+}
@@ -0,0 +1,96 @@
+package domain
+
+import "strings"
+
+// DryRunAction is an enum that represents what a deploy would do to a Kubernetes object
+type DryRunAction string
+
+const (
+	DryRunCreate    DryRunAction = "create"
+	DryRunUpdate    DryRunAction = "update"
+	DryRunUnchanged DryRunAction = "unchanged"
+)
+
+// KubernetesObjectDryRun is a struct that represents a Kubernetes object rendered for a deploy without being applied
+type KubernetesObjectDryRun struct {
+	Kind      string       `json:"kind"`
+	Name      string       `json:"name"`
+	Namespace string       `json:"namespace,omitempty"`
+	Action    DryRunAction `json:"action"`
+	// ServerSideDryRun is true when the object was validated and defaulted by the cluster rather than only rendered locally
+	ServerSideDryRun bool   `json:"serverSideDryRun"`
+	Rendered         string `json:"rendered"`
+	Live             string `json:"live,omitempty"`
+	Diff             string `json:"diff,omitempty"`
+}
+
+// NewKubernetesObjectDryRun compares the rendered YAML of an object with its live YAML (empty when the object does not exist yet)
+func NewKubernetesObjectDryRun(kind string, name string, namespace string, rendered string, live string, serverSideDryRun bool) KubernetesObjectDryRun {
+	objectDryRun := KubernetesObjectDryRun{
+		Kind:             kind,
+		Name:             name,
+		Namespace:        namespace,
+		ServerSideDryRun: serverSideDryRun,
+		Rendered:         rendered,
+		Live:             live,
+	}
+	switch {
+	case live == "":
+		objectDryRun.Action = DryRunCreate
+	case live == rendered:
+		objectDryRun.Action = DryRunUnchanged
+	default:
+		objectDryRun.Action = DryRunUpdate
+		objectDryRun.Diff = LineDiff(live, rendered)
+	}
+	return objectDryRun
+}
+
+// LineDiff returns a line by line diff between two texts.
+// Unchanged lines are prefixed with two spaces, removed lines with "- " and added lines with "+ ".
+func LineDiff(before string, after string) string {
+	beforeLines := strings.Split(strings.TrimSuffix(before, "\n"), "\n")
+	afterLines := strings.Split(strings.TrimSuffix(after, "\n"), "\n")
+
+	// Longest common subsequence table, built from the end so that the diff can be read from the start
+	lcs := make([][]int, len(beforeLines)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(afterLines)+1)
+	}
+	for i := len(beforeLines) - 1; i >= 0; i-- {
+		for j := len(afterLines) - 1; j >= 0; j-- {
+			if beforeLines[i] == afterLines[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var diff strings.Builder
+	i, j := 0, 0
+	for i < len(beforeLines) && j < len(afterLines) {
+		switch {
+		case beforeLines[i] == afterLines[j]:
+			diff.WriteString("  " + beforeLines[i] + "\n")
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			diff.WriteString("- " + beforeLines[i] + "\n")
+			i++
+		default:
+			diff.WriteString("+ " + afterLines[j] + "\n")
+			j++
+		}
+	}
+	for ; i < len(beforeLines); i++ {
+		diff.WriteString("- " + beforeLines[i] + "\n")
+	}
+	for ; j < len(afterLines); j++ {
+		diff.WriteString("+ " + afterLines[j] + "\n")
+	}
+
+	return diff.String()
+}
@@ -0,0 +1,26 @@
+package domain
+
+import "testing"
+
+func TestNewKubernetesObjectDryRun(t *testing.T) {
+	rendered := "kind: Service\nspec:\n  port: 8080\n"
+
+	created := NewKubernetesObjectDryRun("Service", "app", "ns", rendered, "", false)
+	if created.Action != DryRunCreate {
+		t.Errorf("expected action %s, got %s", DryRunCreate, created.Action)
+	}
+
+	unchanged := NewKubernetesObjectDryRun("Service", "app", "ns", rendered, rendered, true)
+	if unchanged.Action != DryRunUnchanged || unchanged.Diff != "" {
+		t.Errorf("expected unchanged object without diff, got %s with diff %q", unchanged.Action, unchanged.Diff)
+	}
+
+	updated := NewKubernetesObjectDryRun("Service", "app", "ns", rendered, "kind: Service\nspec:\n  port: 80\n", true)
+	if updated.Action != DryRunUpdate {
+		t.Errorf("expected action %s, got %s", DryRunUpdate, updated.Action)
+	}
+	expectedDiff := "  kind: Service\n  spec:\n-   port: 80\n+   port: 8080\n"
+	if updated.Diff != expectedDiff {
+		t.Errorf("expected diff %q, got %q", expectedDiff, updated.Diff)
+	}
+}
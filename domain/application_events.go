@@ -0,0 +1,104 @@
+package domain
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// ApplicationEventType is the type Kubernetes gives to an event
+type ApplicationEventType string
+
+const (
+	NormalApplicationEvent  ApplicationEventType = "Normal"
+	WarningApplicationEvent ApplicationEventType = "Warning"
+)
+
+// Kinds of the objects of an application that events are about
+const (
+	DeploymentEventObject = "Deployment"
+	ReplicaSetEventObject = "ReplicaSet"
+	PodEventObject        = "Pod"
+	ServiceEventObject    = "Service"
+	IngressEventObject    = "Ingress"
+)
+
+// OOMKilledEventReason is the reason of the events reporting a container killed for running out of memory,
+// built from the state of the container since Kubernetes records no event for it
+const OOMKilledEventReason = "OOMKilled"
+
+// combinedEventMessagePrefix starts the messages of the events Kubernetes aggregates from similar ones
+const combinedEventMessagePrefix = "(combined from similar events): "
+
+// ApplicationEvent is a struct that represents a Kubernetes event about one of the objects of an application,
+// e.g. a pod that cannot be scheduled, an image that cannot be pulled or a failing probe
+type ApplicationEvent struct {
+	Type           ApplicationEventType `json:"type"`
+	Reason         string               `json:"reason"`
+	Message        string               `json:"message"`
+	ObjectKind     string               `json:"objectKind"`
+	ObjectName     string               `json:"objectName"`
+	Count          int32                `json:"count"`
+	FirstTimestamp time.Time            `json:"firstTimestamp"`
+	LastTimestamp  time.Time            `json:"lastTimestamp"`
+}
+
+// key identifies the occurrences of the same event on the same object
+func (applicationEvent ApplicationEvent) key() string {
+	return strings.Join([]string{applicationEvent.ObjectKind, applicationEvent.ObjectName, string(applicationEvent.Type), applicationEvent.Reason, applicationEvent.Message}, "/")
+}
+
+// MergeApplicationEvents de-duplicates the events reported several times for the same object, their counts are added up,
+// and orders them from the oldest to the most recent occurrence like kubectl does, events that happened at the same time keep their order
+func MergeApplicationEvents(applicationEvents []ApplicationEvent) []ApplicationEvent {
+	mergedEvents := []ApplicationEvent{}
+	indexes := map[string]int{}
+	for _, applicationEvent := range applicationEvents {
+		applicationEvent.Message = strings.TrimPrefix(applicationEvent.Message, combinedEventMessagePrefix)
+		if applicationEvent.Count <= 0 {
+			applicationEvent.Count = 1
+		}
+		if applicationEvent.FirstTimestamp.IsZero() || (!applicationEvent.LastTimestamp.IsZero() && applicationEvent.LastTimestamp.Before(applicationEvent.FirstTimestamp)) {
+			applicationEvent.FirstTimestamp = applicationEvent.LastTimestamp
+		}
+		if applicationEvent.LastTimestamp.IsZero() {
+			applicationEvent.LastTimestamp = applicationEvent.FirstTimestamp
+		}
+
+		index, ok := indexes[applicationEvent.key()]
+		if !ok {
+			indexes[applicationEvent.key()] = len(mergedEvents)
+			mergedEvents = append(mergedEvents, applicationEvent)
+			continue
+		}
+		mergedEvent := &mergedEvents[index]
+		mergedEvent.Count += applicationEvent.Count
+		if applicationEvent.FirstTimestamp.Before(mergedEvent.FirstTimestamp) {
+			mergedEvent.FirstTimestamp = applicationEvent.FirstTimestamp
+		}
+		if applicationEvent.LastTimestamp.After(mergedEvent.LastTimestamp) {
+			mergedEvent.LastTimestamp = applicationEvent.LastTimestamp
+		}
+	}
+
+	sort.SliceStable(mergedEvents, func(i, j int) bool {
+		return mergedEvents[i].LastTimestamp.Before(mergedEvents[j].LastTimestamp)
+	})
+	return mergedEvents
+}
+
+// NewApplicationEvents returns the merged events that were not in previous or that happened again since, in the order of current
+func NewApplicationEvents(previous []ApplicationEvent, current []ApplicationEvent) []ApplicationEvent {
+	previousEvents := map[string]ApplicationEvent{}
+	for _, applicationEvent := range previous {
+		previousEvents[applicationEvent.key()] = applicationEvent
+	}
+	newEvents := []ApplicationEvent{}
+	for _, applicationEvent := range current {
+		previousEvent, ok := previousEvents[applicationEvent.key()]
+		if !ok || applicationEvent.Count != previousEvent.Count || applicationEvent.LastTimestamp.After(previousEvent.LastTimestamp) {
+			newEvents = append(newEvents, applicationEvent)
+		}
+	}
+	return newEvents
+}
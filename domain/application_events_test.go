@@ -0,0 +1,52 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMergeApplicationEvents(t *testing.T) {
+	start := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	merged := MergeApplicationEvents([]ApplicationEvent{
+		{Type: WarningApplicationEvent, Reason: "BackOff", Message: "Back-off restarting failed container api", ObjectKind: PodEventObject, ObjectName: "api-deployment-5d8f-x7k2p", Count: 3, FirstTimestamp: start.Add(time.Minute), LastTimestamp: start.Add(3 * time.Minute)},
+		{Type: NormalApplicationEvent, Reason: "Scheduled", Message: "Successfully assigned team/api-deployment-5d8f-x7k2p", ObjectKind: PodEventObject, ObjectName: "api-deployment-5d8f-x7k2p", LastTimestamp: start},
+		// The same event recorded again once Kubernetes stopped aggregating it
+		{Type: WarningApplicationEvent, Reason: "BackOff", Message: "(combined from similar events): Back-off restarting failed container api", ObjectKind: PodEventObject, ObjectName: "api-deployment-5d8f-x7k2p", Count: 2, FirstTimestamp: start.Add(4 * time.Minute), LastTimestamp: start.Add(5 * time.Minute)},
+		{Type: WarningApplicationEvent, Reason: "FailedScheduling", Message: "0/3 nodes are available: 3 Insufficient memory.", ObjectKind: PodEventObject, ObjectName: "api-deployment-5d8f-q9w4z", FirstTimestamp: start.Add(2 * time.Minute)},
+	})
+
+	if len(merged) != 3 {
+		t.Fatalf("expected the BackOff events to be merged, got %+v", merged)
+	}
+	if merged[0].Reason != "Scheduled" || merged[0].Count != 1 || !merged[0].FirstTimestamp.Equal(start) {
+		t.Errorf("expected the Scheduled event first with a count of 1, got %+v", merged[0])
+	}
+	if merged[1].Reason != "FailedScheduling" || !merged[1].LastTimestamp.Equal(start.Add(2*time.Minute)) {
+		t.Errorf("expected the FailedScheduling event second, got %+v", merged[1])
+	}
+	backOff := merged[2]
+	if backOff.Reason != "BackOff" || backOff.Count != 5 || !backOff.FirstTimestamp.Equal(start.Add(time.Minute)) || !backOff.LastTimestamp.Equal(start.Add(5*time.Minute)) {
+		t.Errorf("expected the BackOff event last, 5 times from 12:01 to 12:05, got %+v", backOff)
+	}
+}
+
+func TestNewApplicationEvents(t *testing.T) {
+	start := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	scheduled := ApplicationEvent{Reason: "Scheduled", ObjectKind: PodEventObject, ObjectName: "api-deployment-5d8f-x7k2p", Count: 1, LastTimestamp: start}
+	backOff := ApplicationEvent{Reason: "BackOff", ObjectKind: PodEventObject, ObjectName: "api-deployment-5d8f-x7k2p", Count: 1, LastTimestamp: start.Add(time.Minute)}
+	repeatedBackOff := backOff
+	repeatedBackOff.Count = 2
+	repeatedBackOff.LastTimestamp = start.Add(2 * time.Minute)
+
+	if newEvents := NewApplicationEvents([]ApplicationEvent{scheduled, backOff}, []ApplicationEvent{scheduled, backOff}); len(newEvents) != 0 {
+		t.Errorf("expected no new event, got %+v", newEvents)
+	}
+	newEvents := NewApplicationEvents([]ApplicationEvent{scheduled}, []ApplicationEvent{scheduled, backOff})
+	if len(newEvents) != 1 || newEvents[0].Reason != "BackOff" {
+		t.Errorf("expected the BackOff event to be new, got %+v", newEvents)
+	}
+	newEvents = NewApplicationEvents([]ApplicationEvent{scheduled, backOff}, []ApplicationEvent{scheduled, repeatedBackOff})
+	if len(newEvents) != 1 || newEvents[0].Count != 2 {
+		t.Errorf("expected the repeated BackOff event to be sent again, got %+v", newEvents)
+	}
+}
@@ -0,0 +1,318 @@
+package domain
+
+import (
+	"crypto/rand"
+	"crypto/sha1"
+	"database/sql/driver"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net"
+	"regexp"
+	"strings"
+
+	"cloud-app-hive/controllers/errors"
+)
+
+const (
+	// MaxApplicationRoutes is the number of routes an application can declare
+	MaxApplicationRoutes = 10
+	// MaxIngressBodySizeMegabytes is the largest request body size an application can accept
+	MaxIngressBodySizeMegabytes = 1024
+	// MaxIngressTimeoutSeconds is the longest read and send timeout of an application
+	MaxIngressTimeoutSeconds = 3600
+	// MaxIngressConnectTimeoutSeconds is the longest connect timeout nginx accepts
+	MaxIngressConnectTimeoutSeconds = 75
+	// MaxIngressResponseHeaders is the number of response headers an application can add
+	MaxIngressResponseHeaders = 20
+	// MaxIngressSourceRanges is the number of CIDRs of each source range list
+	MaxIngressSourceRanges = 50
+)
+
+const (
+	routeSubdomainRegex      = "^[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?$"
+	routePathPrefixRegex     = "^/[a-zA-Z0-9/._~-]*$"
+	responseHeaderNameRegex  = "^[a-zA-Z0-9-]{1,100}$"
+	responseHeaderValueRegex = "^[a-zA-Z0-9 !#%&'()*+,./:<=>?@\\[\\]^_`|~-]{0,1000}$"
+	basicAuthUsernameRegex   = "^[a-zA-Z0-9._-]{1,100}$"
+)
+
+// ApplicationRoute is a struct that routes the requests of a host and path prefix to a port of an application
+type ApplicationRoute struct {
+	// Subdomain is the first label of the host, which is <subdomain>.<namespace>.<domain>. It defaults to the application name.
+	Subdomain string `json:"subdomain,omitempty"`
+	// PathPrefix defaults to /
+	PathPrefix string `json:"pathPrefix,omitempty"`
+	// Port is the container port receiving the requests, it defaults to the port of the application
+	Port uint32 `json:"port,omitempty"`
+	// StripPathPrefix removes the path prefix before the request reaches the application, so that /api/users is received as /users
+	StripPathPrefix bool `json:"stripPathPrefix,omitempty"`
+}
+
+// ApplicationBasicAuth is a struct that represents the HTTP basic auth protecting an application.
+// Its password is write-only: only its hash is stored, and neither is written in API responses.
+type ApplicationBasicAuth struct {
+	Username string `json:"username"`
+	Password string `json:"password,omitempty"`
+	// PasswordHash is the htpasswd hash of the password, read by nginx
+	PasswordHash string `json:"passwordHash,omitempty"`
+}
+
+// storedApplicationBasicAuth has the JSON tags of ApplicationBasicAuth but not its MarshalJSON, so that the hash is kept in the database
+type storedApplicationBasicAuth ApplicationBasicAuth
+
+// MarshalJSON writes the basic auth without its password nor its hash
+func (applicationBasicAuth ApplicationBasicAuth) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Username string `json:"username"`
+	}{Username: applicationBasicAuth.Username})
+}
+
+// ApplicationIngress is a struct that represents how the requests reach an application and the policies applied to them.
+// An application without routes is reached on / of its default host.
+// swagger:model ApplicationIngress
+type ApplicationIngress struct {
+	Routes []ApplicationRoute `json:"routes,omitempty"`
+	// MaxBodySizeMegabytes is the largest request body accepted, 0 keeps the default of the cluster
+	MaxBodySizeMegabytes uint32 `json:"maxBodySizeMegabytes,omitempty"`
+	// The timeouts are in seconds, 0 keeps the default of the cluster
+	ConnectTimeoutSeconds uint32                `json:"connectTimeoutSeconds,omitempty"`
+	ReadTimeoutSeconds    uint32                `json:"readTimeoutSeconds,omitempty"`
+	SendTimeoutSeconds    uint32                `json:"sendTimeoutSeconds,omitempty"`
+	ResponseHeaders       map[string]string     `json:"responseHeaders,omitempty"`
+	BasicAuth             *ApplicationBasicAuth `json:"basicAuth,omitempty"`
+	// AllowedSourceRanges are the only CIDRs allowed to reach the application, when set
+	AllowedSourceRanges []string `json:"allowedSourceRanges,omitempty"`
+	// DeniedSourceRanges are the CIDRs that cannot reach the application
+	DeniedSourceRanges []string `json:"deniedSourceRanges,omitempty"`
+}
+
+// storedApplicationIngress is the ApplicationIngress stored in the database, with the hash of its basic auth password
+type storedApplicationIngress struct {
+	Routes                []ApplicationRoute          `json:"routes,omitempty"`
+	MaxBodySizeMegabytes  uint32                      `json:"maxBodySizeMegabytes,omitempty"`
+	ConnectTimeoutSeconds uint32                      `json:"connectTimeoutSeconds,omitempty"`
+	ReadTimeoutSeconds    uint32                      `json:"readTimeoutSeconds,omitempty"`
+	SendTimeoutSeconds    uint32                      `json:"sendTimeoutSeconds,omitempty"`
+	ResponseHeaders       map[string]string           `json:"responseHeaders,omitempty"`
+	BasicAuth             *storedApplicationBasicAuth `json:"basicAuth,omitempty"`
+	AllowedSourceRanges   []string                    `json:"allowedSourceRanges,omitempty"`
+	DeniedSourceRanges    []string                    `json:"deniedSourceRanges,omitempty"`
+}
+
+func (applicationIngress *ApplicationIngress) Scan(value interface{}) error {
+	bytes, ok := value.([]byte)
+	if !ok {
+		return fmt.Errorf("failed to unmarshal JSONB value: %v", value)
+	}
+
+	return json.Unmarshal(bytes, &applicationIngress)
+}
+
+func (applicationIngress *ApplicationIngress) Value() (driver.Value, error) {
+	if applicationIngress == nil {
+		return json.Marshal(nil)
+	}
+	storedIngress := storedApplicationIngress{
+		Routes:                applicationIngress.Routes,
+		MaxBodySizeMegabytes:  applicationIngress.MaxBodySizeMegabytes,
+		ConnectTimeoutSeconds: applicationIngress.ConnectTimeoutSeconds,
+		ReadTimeoutSeconds:    applicationIngress.ReadTimeoutSeconds,
+		SendTimeoutSeconds:    applicationIngress.SendTimeoutSeconds,
+		ResponseHeaders:       applicationIngress.ResponseHeaders,
+		AllowedSourceRanges:   applicationIngress.AllowedSourceRanges,
+		DeniedSourceRanges:    applicationIngress.DeniedSourceRanges,
+	}
+	if applicationIngress.BasicAuth != nil {
+		storedBasicAuth := storedApplicationBasicAuth(*applicationIngress.BasicAuth)
+		// The password itself is never stored
+		storedBasicAuth.Password = ""
+		storedIngress.BasicAuth = &storedBasicAuth
+	}
+	return json.Marshal(storedIngress)
+}
+
+// Validate checks the routes and policies of the ingress
+func (applicationIngress ApplicationIngress) Validate() error {
+	if len(applicationIngress.Routes) > MaxApplicationRoutes {
+		return errors.NewInvalidApplicationIngressError(fmt.Sprintf("an application cannot declare more than %d routes", MaxApplicationRoutes))
+	}
+	routes := map[string]bool{}
+	for _, route := range applicationIngress.Routes {
+		if route.Subdomain != "" {
+			if match, _ := regexp.MatchString(routeSubdomainRegex, route.Subdomain); !match {
+				return errors.NewInvalidApplicationIngressError(fmt.Sprintf("route subdomain '%s' must match the following regex: %s", route.Subdomain, routeSubdomainRegex))
+			}
+		}
+		if route.PathPrefix != "" {
+			if match, _ := regexp.MatchString(routePathPrefixRegex, route.PathPrefix); !match || strings.Contains(route.PathPrefix, "//") {
+				return errors.NewInvalidApplicationIngressError(fmt.Sprintf("route path prefix '%s' must match the following regex: %s", route.PathPrefix, routePathPrefixRegex))
+			}
+		}
+		if route.Port > 65535 {
+			return errors.NewInvalidApplicationIngressError("route port must be between 1 and 65535")
+		}
+		key := route.Subdomain + route.pathPrefix()
+		if routes[key] {
+			return errors.NewInvalidApplicationIngressError(fmt.Sprintf("route %s%s is declared more than once", route.Subdomain, route.pathPrefix()))
+		}
+		routes[key] = true
+	}
+
+	if applicationIngress.MaxBodySizeMegabytes > MaxIngressBodySizeMegabytes {
+		return errors.NewInvalidApplicationIngressError(fmt.Sprintf("maxBodySizeMegabytes must not exceed %d", MaxIngressBodySizeMegabytes))
+	}
+	if applicationIngress.ConnectTimeoutSeconds > MaxIngressConnectTimeoutSeconds {
+		return errors.NewInvalidApplicationIngressError(fmt.Sprintf("connectTimeoutSeconds must not exceed %d", MaxIngressConnectTimeoutSeconds))
+	}
+	if applicationIngress.ReadTimeoutSeconds > MaxIngressTimeoutSeconds || applicationIngress.SendTimeoutSeconds > MaxIngressTimeoutSeconds {
+		return errors.NewInvalidApplicationIngressError(fmt.Sprintf("readTimeoutSeconds and sendTimeoutSeconds must not exceed %d", MaxIngressTimeoutSeconds))
+	}
+
+	if len(applicationIngress.ResponseHeaders) > MaxIngressResponseHeaders {
+		return errors.NewInvalidApplicationIngressError(fmt.Sprintf("an application cannot add more than %d response headers", MaxIngressResponseHeaders))
+	}
+	// Headers are rendered in the nginx configuration, so their values cannot contain quotes, backslashes, semicolons nor variables
+	for name, value := range applicationIngress.ResponseHeaders {
+		if match, _ := regexp.MatchString(responseHeaderNameRegex, name); !match {
+			return errors.NewInvalidApplicationIngressError(fmt.Sprintf("response header name '%s' must match the following regex: %s", name, responseHeaderNameRegex))
+		}
+		if match, _ := regexp.MatchString(responseHeaderValueRegex, value); !match {
+			return errors.NewInvalidApplicationIngressError(fmt.Sprintf("the value of response header %s must match the following regex: %s", name, responseHeaderValueRegex))
+		}
+	}
+
+	if applicationIngress.BasicAuth != nil {
+		if match, _ := regexp.MatchString(basicAuthUsernameRegex, applicationIngress.BasicAuth.Username); !match {
+			return errors.NewInvalidApplicationIngressError(fmt.Sprintf("basic auth username must match the following regex: %s", basicAuthUsernameRegex))
+		}
+	}
+
+	if err := validateSourceRanges("allowedSourceRanges", applicationIngress.AllowedSourceRanges); err != nil {
+		return err
+	}
+	return validateSourceRanges("deniedSourceRanges", applicationIngress.DeniedSourceRanges)
+}
+
+// ValidatePorts checks that the routes target container ports the service of the application can expose:
+// the service exposes the port of the application on 80, so no route can target the container port 80 of an application listening on another port
+func (applicationIngress ApplicationIngress) ValidatePorts(applicationPort uint32) error {
+	for _, route := range applicationIngress.Routes {
+		if route.Port == 80 && applicationPort != 80 {
+			return errors.NewInvalidApplicationIngressError("a route cannot target the container port 80 unless it is the port of the application")
+		}
+	}
+	return nil
+}
+
+// ValidateRoutesAgainst checks that no route of the application is served by another application of the namespace.
+// Applications can share a host on different path prefixes, but a host and path prefix belong to a single application,
+// otherwise an application could take over the requests of another one.
+func (applicationIngress ApplicationIngress) ValidateRoutesAgainst(applicationName string, namespaceApplications []Application) error {
+	routeOwners := map[string]string{}
+	for _, namespaceApplication := range namespaceApplications {
+		if namespaceApplication.Name == applicationName {
+			continue
+		}
+		ingress := ApplicationIngress{}
+		if namespaceApplication.Ingress != nil {
+			ingress = *namespaceApplication.Ingress
+		}
+		for _, route := range ingress.ResolvedRoutes(namespaceApplication.Name, namespaceApplication.Port) {
+			routeOwners[route.Subdomain+route.PathPrefix] = namespaceApplication.Name
+		}
+	}
+	for _, route := range applicationIngress.ResolvedRoutes(applicationName, 0) {
+		if owner, ok := routeOwners[route.Subdomain+route.PathPrefix]; ok {
+			return errors.NewInvalidApplicationIngressError(fmt.Sprintf("route %s%s is already served by application %s", route.Subdomain, route.PathPrefix, owner))
+		}
+	}
+	return nil
+}
+
+func validateSourceRanges(field string, sourceRanges []string) error {
+	if len(sourceRanges) > MaxIngressSourceRanges {
+		return errors.NewInvalidApplicationIngressError(fmt.Sprintf("%s cannot contain more than %d CIDRs", field, MaxIngressSourceRanges))
+	}
+	for _, sourceRange := range sourceRanges {
+		if _, _, err := net.ParseCIDR(sourceRange); err != nil {
+			return errors.NewInvalidApplicationIngressError(fmt.Sprintf("%s: '%s' is not a valid CIDR", field, sourceRange))
+		}
+	}
+	return nil
+}
+
+// Merge returns the ingress to store when the ingress of an application is replaced by this one.
+// A basic auth password is replaced by its hash, and a basic auth without password keeps the hash of the current one
+// if its username did not change.
+func (applicationIngress ApplicationIngress) Merge(currentIngress *ApplicationIngress) (ApplicationIngress, error) {
+	if applicationIngress.BasicAuth == nil {
+		return applicationIngress, nil
+	}
+
+	basicAuth := *applicationIngress.BasicAuth
+	if basicAuth.Password == "" {
+		if currentIngress == nil || currentIngress.BasicAuth == nil || currentIngress.BasicAuth.Username != basicAuth.Username || currentIngress.BasicAuth.PasswordHash == "" {
+			return ApplicationIngress{}, errors.NewInvalidApplicationIngressError(fmt.Sprintf("basic auth of user %s has no password and no password is stored yet", basicAuth.Username))
+		}
+		basicAuth.PasswordHash = currentIngress.BasicAuth.PasswordHash
+	} else {
+		passwordHash, err := hashBasicAuthPassword(basicAuth.Password)
+		if err != nil {
+			return ApplicationIngress{}, err
+		}
+		basicAuth.Password = ""
+		basicAuth.PasswordHash = passwordHash
+	}
+	applicationIngress.BasicAuth = &basicAuth
+	return applicationIngress, nil
+}
+
+// basicAuthSaltLength is the length of the salt of basic auth password hashes
+const basicAuthSaltLength = 16
+
+// hashBasicAuthPassword returns the salted SHA-1 hash of the password, in the {SSHA} htpasswd format supported by nginx
+func hashBasicAuthPassword(password string) (string, error) {
+	salt := make([]byte, basicAuthSaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", fmt.Errorf("error generating basic auth salt: %w", err)
+	}
+	hash := sha1.Sum(append([]byte(password), salt...))
+	return "{SSHA}" + base64.StdEncoding.EncodeToString(append(hash[:], salt...)), nil
+}
+
+// Htpasswd returns the htpasswd file of the basic auth, read by nginx
+func (applicationBasicAuth ApplicationBasicAuth) Htpasswd() string {
+	return fmt.Sprintf("%s:%s\n", applicationBasicAuth.Username, applicationBasicAuth.PasswordHash)
+}
+
+// pathPrefix returns the path prefix of the route without trailing slash, / if it has none
+func (route ApplicationRoute) pathPrefix() string {
+	pathPrefix := strings.TrimRight(route.PathPrefix, "/")
+	if pathPrefix == "" {
+		return "/"
+	}
+	return pathPrefix
+}
+
+// ResolvedRoutes returns the routes of the application with their defaults set, or the default route if it declares none
+func (applicationIngress ApplicationIngress) ResolvedRoutes(applicationName string, applicationPort uint32) []ApplicationRoute {
+	routes := applicationIngress.Routes
+	if len(routes) == 0 {
+		routes = []ApplicationRoute{{}}
+	}
+	resolvedRoutes := []ApplicationRoute{}
+	for _, route := range routes {
+		if route.Subdomain == "" {
+			route.Subdomain = applicationName
+		}
+		route.PathPrefix = route.pathPrefix()
+		if route.Port == 0 {
+			route.Port = applicationPort
+		}
+		// Stripping / would not change the path
+		route.StripPathPrefix = route.StripPathPrefix && route.PathPrefix != "/"
+		resolvedRoutes = append(resolvedRoutes, route)
+	}
+	return resolvedRoutes
+}
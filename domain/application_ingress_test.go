@@ -0,0 +1,149 @@
+package domain
+
+import (
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestApplicationIngress_Validate(t *testing.T) {
+	validIngress := ApplicationIngress{
+		Routes: []ApplicationRoute{
+			{Subdomain: "shop", PathPrefix: "/api", Port: 8080, StripPathPrefix: true},
+			{Subdomain: "shop"},
+		},
+		MaxBodySizeMegabytes: 50,
+		ReadTimeoutSeconds:   120,
+		ResponseHeaders:      map[string]string{"X-Frame-Options": "DENY"},
+		BasicAuth:            &ApplicationBasicAuth{Username: "admin", Password: "secret"},
+		AllowedSourceRanges:  []string{"10.0.0.0/8", "2001:db8::/32"},
+	}
+	if err := validIngress.Validate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	invalidIngresses := map[string]ApplicationIngress{
+		"subdomain":       {Routes: []ApplicationRoute{{Subdomain: "Shop.example"}}},
+		"path prefix":     {Routes: []ApplicationRoute{{PathPrefix: "api"}}},
+		"duplicate route": {Routes: []ApplicationRoute{{PathPrefix: "/api"}, {PathPrefix: "/api/"}}},
+		"port":            {Routes: []ApplicationRoute{{Port: 70000}}},
+		"body size":       {MaxBodySizeMegabytes: MaxIngressBodySizeMegabytes + 1},
+		"connect timeout": {ConnectTimeoutSeconds: MaxIngressConnectTimeoutSeconds + 1},
+		"header name":     {ResponseHeaders: map[string]string{"X Header": "value"}},
+		"header value":    {ResponseHeaders: map[string]string{"X-Header": `value"; return 200; "`}},
+		"username":        {BasicAuth: &ApplicationBasicAuth{Username: "ad:min"}},
+		"source range":    {DeniedSourceRanges: []string{"10.0.0.1"}},
+	}
+	for name, ingress := range invalidIngresses {
+		if err := ingress.Validate(); err == nil {
+			t.Errorf("expected an invalid %s to be rejected", name)
+		}
+	}
+}
+
+func TestApplicationIngress_MergeHashesBasicAuthPassword(t *testing.T) {
+	ingress, err := ApplicationIngress{BasicAuth: &ApplicationBasicAuth{Username: "admin", Password: "secret"}}.Merge(nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ingress.BasicAuth.Password != "" {
+		t.Errorf("expected the password to be cleared once hashed")
+	}
+	passwordHash := ingress.BasicAuth.PasswordHash
+	if !strings.HasPrefix(passwordHash, "{SSHA}") {
+		t.Fatalf("expected an {SSHA} hash, got %s", passwordHash)
+	}
+	decodedHash, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(passwordHash, "{SSHA}"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	hash := sha1.Sum(append([]byte("secret"), decodedHash[sha1.Size:]...))
+	if string(hash[:]) != string(decodedHash[:sha1.Size]) {
+		t.Errorf("expected the hash to match the password")
+	}
+
+	// A basic auth without password keeps the stored hash of the same user
+	keptIngress, err := ApplicationIngress{BasicAuth: &ApplicationBasicAuth{Username: "admin"}}.Merge(&ingress)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if keptIngress.BasicAuth.PasswordHash != passwordHash {
+		t.Errorf("expected the stored hash to be kept, got %s", keptIngress.BasicAuth.PasswordHash)
+	}
+	if _, err := (ApplicationIngress{BasicAuth: &ApplicationBasicAuth{Username: "other"}}).Merge(&ingress); err == nil {
+		t.Errorf("expected a new user without password to be rejected")
+	}
+}
+
+func TestApplicationIngress_BasicAuthPasswordIsNotWritten(t *testing.T) {
+	ingress := &ApplicationIngress{BasicAuth: &ApplicationBasicAuth{Username: "admin", Password: "secret", PasswordHash: "{SSHA}hash"}}
+
+	response, err := json.Marshal(ingress)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Contains(string(response), "secret") || strings.Contains(string(response), "{SSHA}") {
+		t.Errorf("expected the password and its hash to be omitted from the response, got %s", response)
+	}
+
+	// Only the hash is kept in the database
+	stored, err := ingress.Value()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Contains(string(stored.([]byte)), "secret") {
+		t.Errorf("expected the password not to be stored, got %s", stored)
+	}
+	scannedIngress := ApplicationIngress{}
+	if err := scannedIngress.Scan(stored); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if scannedIngress.BasicAuth.PasswordHash != "{SSHA}hash" {
+		t.Errorf("expected the stored ingress to keep the hash, got %+v", scannedIngress.BasicAuth)
+	}
+}
+
+func TestApplicationIngress_ResolvedRoutes(t *testing.T) {
+	defaultRoutes := ApplicationIngress{}.ResolvedRoutes("api", 8080)
+	if len(defaultRoutes) != 1 || defaultRoutes[0] != (ApplicationRoute{Subdomain: "api", PathPrefix: "/", Port: 8080}) {
+		t.Errorf("expected the default route of the application, got %+v", defaultRoutes)
+	}
+
+	routes := ApplicationIngress{Routes: []ApplicationRoute{
+		{Subdomain: "shop", PathPrefix: "/api/", Port: 9000, StripPathPrefix: true},
+		{Subdomain: "shop", StripPathPrefix: true},
+	}}.ResolvedRoutes("api", 8080)
+	if routes[0] != (ApplicationRoute{Subdomain: "shop", PathPrefix: "/api", Port: 9000, StripPathPrefix: true}) {
+		t.Errorf("expected the trailing slash to be trimmed, got %+v", routes[0])
+	}
+	if routes[1].StripPathPrefix || routes[1].Port != 8080 {
+		t.Errorf("expected the root route to keep its path on the application port, got %+v", routes[1])
+	}
+}
+
+func TestApplicationIngress_ValidateRoutesAgainst(t *testing.T) {
+	namespaceApplications := []Application{
+		{Name: "frontend", Port: 80},
+		{Name: "shop", Port: 80, Ingress: &ApplicationIngress{Routes: []ApplicationRoute{{Subdomain: "shop", PathPrefix: "/api"}}}},
+	}
+
+	if err := (ApplicationIngress{Routes: []ApplicationRoute{{Subdomain: "frontend", PathPrefix: "/api"}}}).ValidateRoutesAgainst("api", namespaceApplications); err != nil {
+		t.Errorf("expected an application to share a host on another path prefix, got %v", err)
+	}
+	if err := (ApplicationIngress{}).ValidateRoutesAgainst("frontend", namespaceApplications); err != nil {
+		t.Errorf("expected an application not to collide with its own routes, got %v", err)
+	}
+	for name, ingress := range map[string]ApplicationIngress{
+		"default route of another application":  {Routes: []ApplicationRoute{{Subdomain: "frontend"}}},
+		"declared route of another application": {Routes: []ApplicationRoute{{Subdomain: "shop", PathPrefix: "/api/"}}},
+	} {
+		if err := ingress.ValidateRoutesAgainst("api", namespaceApplications); err == nil {
+			t.Errorf("expected the %s to be rejected", name)
+		}
+	}
+	if err := (ApplicationIngress{}).ValidateRoutesAgainst("shop", []Application{{Name: "other", Ingress: &ApplicationIngress{Routes: []ApplicationRoute{{Subdomain: "shop"}}}}}); err == nil {
+		t.Error("expected the default route of an application to be rejected when another application serves it")
+	}
+}
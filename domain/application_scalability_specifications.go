@@ -90,3 +90,21 @@ func (applicationScalabilitySpecifications ApplicationScalabilitySpecifications)
 }
 
 const MaxNumberOfReplicas = 3
+
+// HorizontallyScaledUp returns the specifications with one more replica, it fails when the application already runs the maximum number of replicas
+func (applicationScalabilitySpecifications ApplicationScalabilitySpecifications) HorizontallyScaledUp() (ApplicationScalabilitySpecifications, error) {
+	if applicationScalabilitySpecifications.Replicas+1 > MaxNumberOfReplicas {
+		return ApplicationScalabilitySpecifications{}, fmt.Errorf("application is already at maximum number of replicas")
+	}
+	applicationScalabilitySpecifications.Replicas++
+	return applicationScalabilitySpecifications, nil
+}
+
+// HorizontallyScaledDown returns the specifications with one less replica, it fails when the application runs a single replica
+func (applicationScalabilitySpecifications ApplicationScalabilitySpecifications) HorizontallyScaledDown() (ApplicationScalabilitySpecifications, error) {
+	if applicationScalabilitySpecifications.Replicas-1 <= 0 {
+		return ApplicationScalabilitySpecifications{}, fmt.Errorf("application is already at minimum number of replicas")
+	}
+	applicationScalabilitySpecifications.Replicas--
+	return applicationScalabilitySpecifications, nil
+}
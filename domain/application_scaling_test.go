@@ -0,0 +1,56 @@
+package domain
+
+import (
+	"testing"
+
+	domainErrors "cloud-app-hive/domain/errors"
+)
+
+func TestApplicationScalabilitySpecifications_HorizontalScaling(t *testing.T) {
+	specifications := ApplicationScalabilitySpecifications{Replicas: 2, IsAutoScaled: true, CpuUsagePercentageThreshold: 80}
+
+	scaledUp, err := specifications.HorizontallyScaledUp()
+	if err != nil || scaledUp.Replicas != 3 || !scaledUp.IsAutoScaled || scaledUp.CpuUsagePercentageThreshold != 80 {
+		t.Errorf("expected 3 replicas keeping the other specifications, got %+v, %v", scaledUp, err)
+	}
+	if _, err := scaledUp.HorizontallyScaledUp(); err == nil {
+		t.Errorf("expected an error above %d replicas", MaxNumberOfReplicas)
+	}
+
+	scaledDown, err := specifications.HorizontallyScaledDown()
+	if err != nil || scaledDown.Replicas != 1 {
+		t.Errorf("expected 1 replica, got %+v, %v", scaledDown, err)
+	}
+	if _, err := scaledDown.HorizontallyScaledDown(); err == nil {
+		t.Error("expected an error below 1 replica")
+	}
+}
+
+func TestApplicationContainerSpecifications_VerticallyScaledUp(t *testing.T) {
+	specifications := ApplicationContainerSpecifications{
+		CPULimit:    &ContainerCpuLimit{Val: 140, Unit: mCPU},
+		MemoryLimit: &ContainerMemoryLimit{Val: 256, Unit: MB},
+	}
+
+	scaledUp, err := specifications.VerticallyScaledUp()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if scaledUp.CPULimit.Val != 280 || scaledUp.MemoryLimit.Val != 512 || specifications.CPULimit.Val != 140 {
+		t.Errorf("expected the next limits without changing the current ones, got %+v and %+v", *scaledUp.CPULimit, *scaledUp.MemoryLimit)
+	}
+
+	maxCPULimit := ApplicationCPULimitChoice[len(ApplicationCPULimitChoice)-1]
+	maxMemoryLimit := ApplicationMemoryLimitChoice[len(ApplicationMemoryLimitChoice)-1]
+	_, err = ApplicationContainerSpecifications{
+		CPULimit:    &ContainerCpuLimit{Val: maxCPULimit.Value, Unit: maxCPULimit.Unit},
+		MemoryLimit: &ContainerMemoryLimit{Val: maxMemoryLimit.Value, Unit: maxMemoryLimit.Unit},
+	}.VerticallyScaledUp()
+	if _, ok := err.(*domainErrors.InvalidApplicationCannotVerticallyScaleBecauseMaxSpecsError); !ok {
+		t.Errorf("expected the max specifications error, got %v", err)
+	}
+
+	if _, err := (ApplicationContainerSpecifications{CPULimit: &ContainerCpuLimit{Val: 140, Unit: mCPU}}).VerticallyScaledUp(); err == nil {
+		t.Error("expected an error without memory limit")
+	}
+}
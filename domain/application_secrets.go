@@ -6,12 +6,44 @@ import (
 	"encoding/json"
 	"fmt"
 	"regexp"
+	"time"
 )
 
-// ApplicationSecret is a struct that represents a secret
+// ApplicationSecret is a struct that represents a secret.
+// Its value is write-only: it is read from requests and stored, but never written in API responses.
 type ApplicationSecret struct {
 	Name string `json:"name" validate:"required"`
-	Val  string `json:"value" validate:"required"`
+	Val  string `json:"value"`
+	// Reference is the location of the value in a secret provider, formatted as "provider://path#key", for secrets without value
+	Reference string `json:"reference,omitempty"`
+	// Preview is the masked value of the secret, set when the value is set
+	Preview string `json:"preview"`
+	// Version is incremented each time the value of the secret changes
+	Version   int        `json:"version"`
+	UpdatedAt *time.Time `json:"updatedAt"`
+}
+
+// applicationSecretResponse is the JSON representation of a secret in API responses, without its value
+type applicationSecretResponse struct {
+	Name      string     `json:"name"`
+	Reference string     `json:"reference,omitempty"`
+	Preview   string     `json:"preview"`
+	Version   int        `json:"version"`
+	UpdatedAt *time.Time `json:"updatedAt"`
+}
+
+// storedApplicationSecret has the JSON tags of ApplicationSecret but not its MarshalJSON, so that the value is kept in the database
+type storedApplicationSecret ApplicationSecret
+
+// MarshalJSON writes the secret without its value
+func (applicationSecret ApplicationSecret) MarshalJSON() ([]byte, error) {
+	return json.Marshal(applicationSecretResponse{
+		Name:      applicationSecret.Name,
+		Reference: applicationSecret.Reference,
+		Preview:   applicationSecret.Preview,
+		Version:   applicationSecret.Version,
+		UpdatedAt: applicationSecret.UpdatedAt,
+	})
 }
 
 // ApplicationSecrets is a slice of ApplicationSecret
@@ -24,11 +56,133 @@ func (applicationSecrets *ApplicationSecrets) Scan(value interface{}) error {
 		return fmt.Errorf("failed to unmarshal JSONB value: %v", value)
 	}
 
-	return json.Unmarshal(bytes, &applicationSecrets)
+	if err := json.Unmarshal(bytes, &applicationSecrets); err != nil {
+		return err
+	}
+	// Previews stored before they were fixed showed the last characters of the values
+	for index := range *applicationSecrets {
+		if (*applicationSecrets)[index].Preview != "" {
+			(*applicationSecrets)[index].Preview = maskedSecretValue
+		}
+	}
+	return nil
 }
 
 func (applicationSecrets *ApplicationSecrets) Value() (driver.Value, error) {
-	return json.Marshal(applicationSecrets)
+	if applicationSecrets == nil {
+		return json.Marshal(nil)
+	}
+	storedSecrets := make([]storedApplicationSecret, 0, len(*applicationSecrets))
+	for _, secret := range *applicationSecrets {
+		if secret.Preview != "" {
+			secret.Preview = maskedSecretValue
+		}
+		storedSecrets = append(storedSecrets, storedApplicationSecret(secret))
+	}
+	return json.Marshal(storedSecrets)
+}
+
+// maskedSecretValue is the preview of every secret value
+const maskedSecretValue = "****"
+
+// MaskSecretValue returns the preview of a secret value. It does not depend on the value:
+// previews are stored in plaintext next to the encrypted values, any character they showed would leak.
+func MaskSecretValue(value string) string {
+	return maskedSecretValue
+}
+
+// NamesOnly returns the secrets without their preview and version, for members who cannot read secrets
+func (applicationSecrets *ApplicationSecrets) NamesOnly() *ApplicationSecrets {
+	if applicationSecrets == nil {
+		return nil
+	}
+	secrets := ApplicationSecrets{}
+	for _, secret := range *applicationSecrets {
+		secrets = append(secrets, ApplicationSecret{Name: secret.Name})
+	}
+	return &secrets
+}
+
+// Find returns the secret with the given name and false if there is none
+func (applicationSecrets ApplicationSecrets) Find(name string) (ApplicationSecret, bool) {
+	for _, secret := range applicationSecrets {
+		if secret.Name == name {
+			return secret, true
+		}
+	}
+	return ApplicationSecret{}, false
+}
+
+// newApplicationSecretVersion returns the next version of a secret set to the value or reference of requested,
+// or the current secret if it already has this value or reference
+func newApplicationSecretVersion(current ApplicationSecret, requested ApplicationSecret, now time.Time) ApplicationSecret {
+	if current.Name == requested.Name && current.Val == requested.Val && current.Reference == requested.Reference {
+		return current
+	}
+	secret := ApplicationSecret{
+		Name:      requested.Name,
+		Val:       requested.Val,
+		Reference: requested.Reference,
+		Version:   current.Version + 1,
+		UpdatedAt: &now,
+	}
+	// The value of a reference is only known by its provider
+	if !secret.IsReference() {
+		secret.Preview = MaskSecretValue(secret.Val)
+	}
+	return secret
+}
+
+// Merge returns the secrets to store when the secrets of an application are replaced by these ones.
+// A secret without value nor reference keeps the value, version and metadata of the current secret with the same name,
+// a secret with a new value or reference gets a new version.
+func (applicationSecrets ApplicationSecrets) Merge(currentSecrets *ApplicationSecrets, now time.Time) (ApplicationSecrets, error) {
+	current := ApplicationSecrets{}
+	if currentSecrets != nil {
+		current = *currentSecrets
+	}
+
+	mergedSecrets := ApplicationSecrets{}
+	for _, secret := range applicationSecrets {
+		currentSecret, exists := current.Find(secret.Name)
+		if secret.Val == "" && !secret.IsReference() {
+			if !exists {
+				return nil, errors.NewInvalidApplicationSecretsError(fmt.Sprintf("secret %s has no value and no value is stored yet", secret.Name))
+			}
+			mergedSecrets = append(mergedSecrets, currentSecret)
+			continue
+		}
+		mergedSecrets = append(mergedSecrets, newApplicationSecretVersion(currentSecret, secret, now))
+	}
+	return mergedSecrets, nil
+}
+
+// WithSecret returns the secrets with the value or reference of one secret added or rotated
+func (applicationSecrets ApplicationSecrets) WithSecret(requested ApplicationSecret, now time.Time) ApplicationSecrets {
+	secrets := ApplicationSecrets{}
+	replaced := false
+	for _, secret := range applicationSecrets {
+		if secret.Name == requested.Name {
+			secret = newApplicationSecretVersion(secret, requested, now)
+			replaced = true
+		}
+		secrets = append(secrets, secret)
+	}
+	if !replaced {
+		secrets = append(secrets, newApplicationSecretVersion(ApplicationSecret{}, requested, now))
+	}
+	return secrets
+}
+
+// WithoutSecret returns the secrets without the one with the given name
+func (applicationSecrets ApplicationSecrets) WithoutSecret(name string) ApplicationSecrets {
+	secrets := ApplicationSecrets{}
+	for _, secret := range applicationSecrets {
+		if secret.Name != name {
+			secrets = append(secrets, secret)
+		}
+	}
+	return secrets
 }
 
 const secretNameRegex = "^[a-zA-Z_][a-zA-Z0-9_]*$"
@@ -49,6 +203,21 @@ func (applicationSecrets *ApplicationSecrets) Validate() error {
 		if !IsAValidSecretName(secret.Name) {
 			return errors.NewInvalidApplicationSecretsError("Name must not contain special characters, it must match the following regex: " + secretNameRegex)
 		}
+		if err := secret.validateReference(); err != nil {
+			return err
+		}
 	}
 	return nil
 }
+
+// validateReference returns an error if the secret has both a value and a reference, or a malformed reference
+func (applicationSecret ApplicationSecret) validateReference() error {
+	if !applicationSecret.IsReference() {
+		return nil
+	}
+	if applicationSecret.Val != "" {
+		return errors.NewInvalidApplicationSecretsError(fmt.Sprintf("secret %s must have either a value or a reference", applicationSecret.Name))
+	}
+	_, err := ParseSecretReference(applicationSecret.Reference)
+	return err
+}
@@ -0,0 +1,132 @@
+package domain
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMaskSecretValue(t *testing.T) {
+	if preview := MaskSecretValue("short"); preview != "****" {
+		t.Errorf("expected a short value to be fully masked, got %s", preview)
+	}
+	if preview := MaskSecretValue("sk_live_0123456789"); preview != "****" {
+		t.Errorf("expected a long value to be fully masked, got %s", preview)
+	}
+}
+
+func TestApplicationSecret_MarshalJSONOmitsValue(t *testing.T) {
+	updatedAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	secrets := &ApplicationSecrets{{Name: "TOKEN", Val: "sk_live_0123456789", Preview: "****", Version: 3, UpdatedAt: &updatedAt}}
+
+	response, err := json.Marshal(secrets)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Contains(string(response), "sk_live") || strings.Contains(string(response), `"value"`) {
+		t.Errorf("expected the value to be omitted from the response, got %s", response)
+	}
+	if !strings.Contains(string(response), `"preview":"****"`) || !strings.Contains(string(response), `"version":3`) {
+		t.Errorf("expected the secret metadata in the response, got %s", response)
+	}
+
+	// The value is kept in the database
+	stored, err := secrets.Value()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	scannedSecrets := ApplicationSecrets{}
+	if err := scannedSecrets.Scan(stored); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if scannedSecrets[0].Val != "sk_live_0123456789" || scannedSecrets[0].Version != 3 {
+		t.Errorf("expected the stored secret to keep its value, got %+v", scannedSecrets[0])
+	}
+}
+
+func TestApplicationSecrets_MasksPreviewsShowingTheLastCharacters(t *testing.T) {
+	scannedSecrets := ApplicationSecrets{}
+	if err := scannedSecrets.Scan([]byte(`[{"name":"TOKEN","value":"enc:v1:key:token","preview":"****6789","version":1}]`)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if scannedSecrets[0].Preview != "****" {
+		t.Errorf("expected a preview stored with the last characters of the value to be masked, got %s", scannedSecrets[0].Preview)
+	}
+
+	secrets := &ApplicationSecrets{{Name: "TOKEN", Val: "enc:v1:key:token", Preview: "****6789", Version: 1}}
+	stored, err := secrets.Value()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Contains(string(stored.([]byte)), "6789") {
+		t.Errorf("expected the preview to be stored masked, got %s", stored)
+	}
+}
+
+func TestApplicationSecrets_Merge(t *testing.T) {
+	createdAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := createdAt.Add(time.Hour)
+	currentSecrets := &ApplicationSecrets{
+		{Name: "KEPT", Val: "enc:v1:key:kept", Preview: "****", Version: 2, UpdatedAt: &createdAt},
+		{Name: "ROTATED", Val: "enc:v1:key:rotated", Preview: "****", Version: 1, UpdatedAt: &createdAt},
+	}
+
+	mergedSecrets, err := ApplicationSecrets{{Name: "KEPT"}, {Name: "ROTATED", Val: "new-value"}, {Name: "ADDED", Val: "added-value"}}.Merge(currentSecrets, now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if kept := mergedSecrets[0]; kept.Val != "enc:v1:key:kept" || kept.Version != 2 || !kept.UpdatedAt.Equal(createdAt) {
+		t.Errorf("expected the secret without value to be kept, got %+v", kept)
+	}
+	if rotated := mergedSecrets[1]; rotated.Val != "new-value" || rotated.Version != 2 || !rotated.UpdatedAt.Equal(now) {
+		t.Errorf("expected the rotated secret to get a new version, got %+v", rotated)
+	}
+	if added := mergedSecrets[2]; added.Version != 1 || added.Preview != "****" {
+		t.Errorf("expected the added secret to get its first version, got %+v", added)
+	}
+
+	if _, err := (ApplicationSecrets{{Name: "UNKNOWN"}}).Merge(currentSecrets, now); err == nil {
+		t.Error("expected an error for a secret without value nor stored value")
+	}
+}
+
+func TestApplicationSecrets_WithSecretAndWithoutSecret(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	secrets := ApplicationSecrets{}.WithSecret(ApplicationSecret{Name: "TOKEN", Val: "first"}, now).WithSecret(ApplicationSecret{Name: "TOKEN", Val: "second"}, now)
+	if len(secrets) != 1 || secrets[0].Val != "second" || secrets[0].Version != 2 {
+		t.Errorf("expected the secret to be rotated in place, got %+v", secrets)
+	}
+	if unchanged := secrets.WithSecret(ApplicationSecret{Name: "TOKEN", Val: "second"}, now.Add(time.Hour)); unchanged[0].Version != 2 {
+		t.Errorf("expected setting the same value to keep the version, got %+v", unchanged[0])
+	}
+	if referenced := secrets.WithSecret(ApplicationSecret{Name: "TOKEN", Reference: "vault://team/api#token"}, now); referenced[0].Val != "" || referenced[0].Preview != "" || referenced[0].Version != 3 {
+		t.Errorf("expected the value to be replaced by the reference, got %+v", referenced[0])
+	}
+	if remaining := secrets.WithoutSecret("TOKEN"); len(remaining) != 0 {
+		t.Errorf("expected the secret to be removed, got %+v", remaining)
+	}
+}
+
+func TestParseSecretReference(t *testing.T) {
+	reference, err := ParseSecretReference("vault://team/api/#database_password")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if reference.Provider != "vault" || reference.Path != "team/api" || reference.Key != "database_password" {
+		t.Errorf("unexpected reference %+v", reference)
+	}
+	if reference, err := ParseSecretReference("file://tokens.env"); err != nil || reference.Key != "" {
+		t.Errorf("expected a reference without key, got %+v and %v", reference, err)
+	}
+	for _, invalidReference := range []string{"team/api#key", "vault://#key", "Vault://team#key"} {
+		if _, err := ParseSecretReference(invalidReference); err == nil {
+			t.Errorf("expected %s to be invalid", invalidReference)
+		}
+	}
+
+	secrets := ApplicationSecrets{{Name: "TOKEN", Val: "value", Reference: "vault://team#token"}}
+	if err := secrets.Validate(); err == nil {
+		t.Error("expected a secret with both a value and a reference to be invalid")
+	}
+}
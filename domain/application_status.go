@@ -115,3 +115,37 @@ func (appStatus ApplicationStatus) ComputeApplicationStatus() (*ApplicationDeplo
 
 	return &computedStatus, humanizedStatus, nil
 }
+
+// IsRolledOut tells if all the desired replicas of the application run its last version and are ready
+func (appStatus ApplicationStatus) IsRolledOut() bool {
+	return appStatus.UpdatedReplicas >= appStatus.DesiredReplicas &&
+		appStatus.AvailableReplicas >= appStatus.DesiredReplicas &&
+		appStatus.ReadyReplicas >= appStatus.DesiredReplicas
+}
+
+// rolloutFailureReasons are the reasons of waiting containers that will not become ready without a new deployment
+var rolloutFailureReasons = map[string]bool{
+	"CrashLoopBackOff":           true,
+	"ImagePullBackOff":           true,
+	"ErrImagePull":               true,
+	"CreateContainerConfigError": true,
+	"InvalidImageName":           true,
+}
+
+// RolloutFailure returns why the rollout of the application cannot succeed, or an empty string if it still can
+func (appStatus ApplicationStatus) RolloutFailure() string {
+	for _, condition := range appStatus.DeploymentCondition {
+		if condition.Type == "Progressing" && condition.Reason == "ProgressDeadlineExceeded" {
+			return condition.Message
+		}
+	}
+	for _, pod := range appStatus.PodList.Items {
+		for _, containerStatus := range pod.Status.ContainerStatuses {
+			waiting := containerStatus.State.Waiting
+			if waiting != nil && rolloutFailureReasons[waiting.Reason] {
+				return fmt.Sprintf("container %s of pod %s is waiting: %s", containerStatus.Name, pod.MetaData.Name, waiting.Reason)
+			}
+		}
+	}
+	return ""
+}
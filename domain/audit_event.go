@@ -0,0 +1,227 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	customErrors "cloud-app-hive/controllers/errors"
+)
+
+type AuditAction string
+
+const (
+	AuditNamespaceCreate          AuditAction = "namespace.create"
+	AuditNamespaceUpdate          AuditAction = "namespace.update"
+	AuditNamespaceDelete          AuditAction = "namespace.delete"
+	AuditNamespaceMemberAdd       AuditAction = "namespace.membership.add"
+	AuditNamespaceMemberRemove    AuditAction = "namespace.membership.remove"
+	AuditNamespaceMemberUpdate    AuditAction = "namespace.membership.update"
+	AuditNamespaceTransferRequest AuditAction = "namespace.ownership.transfer"
+	AuditNamespaceTransferAccept  AuditAction = "namespace.ownership.accept"
+	AuditNamespaceTransferCancel  AuditAction = "namespace.ownership.cancel"
+	AuditNamespaceInviteCreate    AuditAction = "namespace.invitation.create"
+	AuditNamespaceInviteAccept    AuditAction = "namespace.invitation.accept"
+	AuditNamespaceInviteDecline   AuditAction = "namespace.invitation.decline"
+	AuditNamespaceInviteRevoke    AuditAction = "namespace.invitation.revoke"
+	AuditNamespaceManifestApply   AuditAction = "namespace.manifest.apply"
+	AuditNetworkRuleCreate        AuditAction = "namespace.network_rule.create"
+	AuditNetworkRuleDelete        AuditAction = "namespace.network_rule.delete"
+	AuditRegistryCredentialCreate AuditAction = "namespace.registry_credential.create"
+	AuditRegistryCredentialUpdate AuditAction = "namespace.registry_credential.update"
+	AuditRegistryCredentialDelete AuditAction = "namespace.registry_credential.delete"
+	AuditImageScanPolicyUpdate    AuditAction = "namespace.image_scan_policy.update"
+	AuditAddonCreate              AuditAction = "namespace.addon.create"
+	AuditAddonDelete              AuditAction = "namespace.addon.delete"
+	AuditAddonBind                AuditAction = "namespace.addon.bind"
+	AuditAddonUnbind              AuditAction = "namespace.addon.unbind"
+	AuditConfigGroupCreate        AuditAction = "namespace.config_group.create"
+	AuditConfigGroupUpdate        AuditAction = "namespace.config_group.update"
+	AuditConfigGroupDelete        AuditAction = "namespace.config_group.delete"
+	AuditWebhookCreate            AuditAction = "namespace.webhook.create"
+	AuditWebhookUpdate            AuditAction = "namespace.webhook.update"
+	AuditWebhookDelete            AuditAction = "namespace.webhook.delete"
+	AuditApplicationCreate        AuditAction = "application.create"
+	AuditApplicationUpdate        AuditAction = "application.update"
+	AuditApplicationDelete        AuditAction = "application.delete"
+	AuditApplicationScale         AuditAction = "application.scale"
+	AuditApplicationDeploy        AuditAction = "application.deploy"
+	AuditApplicationUndeploy      AuditAction = "application.undeploy"
+	AuditApplicationSecretSet     AuditAction = "application.secret.set"
+	AuditApplicationSecretDelete  AuditAction = "application.secret.delete"
+	AuditApplicationSecretRefresh AuditAction = "application.secret.refresh"
+	AuditConfigGroupsSet          AuditAction = "application.config_groups.set"
+)
+
+type AuditTargetType string
+
+const (
+	AuditTargetNamespace          AuditTargetType = "namespace"
+	AuditTargetMembership         AuditTargetType = "membership"
+	AuditTargetInvitation         AuditTargetType = "invitation"
+	AuditTargetNetworkRule        AuditTargetType = "network_rule"
+	AuditTargetRegistryCredential AuditTargetType = "registry_credential"
+	AuditTargetAddon              AuditTargetType = "addon"
+	AuditTargetConfigGroup        AuditTargetType = "config_group"
+	AuditTargetWebhook            AuditTargetType = "webhook"
+	AuditTargetApplication        AuditTargetType = "application"
+)
+
+type AuditOutcome string
+
+const (
+	AuditSuccess AuditOutcome = "success"
+	AuditFailure AuditOutcome = "failure"
+	AuditDenied  AuditOutcome = "denied"
+)
+
+// SchedulerActorID is the actor of the actions performed by the schedulers
+const SchedulerActorID = "scheduler"
+
+// AuditActor is a struct that represents who performed an action and from where
+type AuditActor struct {
+	UserID        string
+	AccessTokenID *string
+	SourceIP      string
+}
+
+// SchedulerAuditActor returns the actor of the actions performed by the schedulers
+func SchedulerAuditActor() AuditActor {
+	return AuditActor{UserID: SchedulerActorID}
+}
+
+// AuditChange is a struct that represents a field changed by an action, nested fields are joined with dots
+type AuditChange struct {
+	Field  string      `json:"field"`
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+// AuditChanges is a slice of AuditChange stored as JSON
+// swagger:model AuditChanges
+type AuditChanges []AuditChange
+
+func (auditChanges *AuditChanges) Scan(value interface{}) error {
+	bytes, ok := value.([]byte)
+	if !ok {
+		return fmt.Errorf("failed to unmarshal JSONB value: %v", value)
+	}
+
+	return json.Unmarshal(bytes, &auditChanges)
+}
+
+func (auditChanges AuditChanges) Value() (driver.Value, error) {
+	return json.Marshal(auditChanges)
+}
+
+// AuditEvent is a struct that represents an action performed on a namespace or one of its applications.
+// Audit events are append-only: they are never updated nor deleted.
+type AuditEvent struct {
+	ID            string          `json:"id" gorm:"primaryKey"`
+	NamespaceID   string          `json:"namespaceId" gorm:"size:100;index:idx_audit_namespace_created_at,priority:1"`
+	ActorID       string          `json:"actorId" gorm:"size:255;index;not null"`
+	AccessTokenID *string         `json:"accessTokenId" gorm:"size:100"`
+	SourceIP      string          `json:"sourceIp" gorm:"size:45"`
+	Action        AuditAction     `json:"action" gorm:"size:50;index;not null"`
+	TargetType    AuditTargetType `json:"targetType" gorm:"size:50;not null"`
+	TargetID      string          `json:"targetId" gorm:"size:100;index"`
+	TargetName    string          `json:"targetName" gorm:"size:255"`
+	Changes       AuditChanges    `json:"changes" gorm:"type:json"`
+	Outcome       AuditOutcome    `json:"outcome" gorm:"size:20;not null"`
+	Error         string          `json:"error,omitempty" gorm:"size:1000"`
+	CreatedAt     time.Time       `json:"createdAt" gorm:"index:idx_audit_namespace_created_at,priority:2;not null"`
+}
+
+// NewAuditEvent returns the event of an action, its outcome is set once the action is done
+func NewAuditEvent(actor AuditActor, action AuditAction, targetType AuditTargetType, targetID string) AuditEvent {
+	return AuditEvent{
+		ActorID:       actor.UserID,
+		AccessTokenID: actor.AccessTokenID,
+		SourceIP:      actor.SourceIP,
+		Action:        action,
+		TargetType:    targetType,
+		TargetID:      targetID,
+		Changes:       AuditChanges{},
+	}
+}
+
+// WithOutcome returns the event with the outcome of the action that returned err
+func (auditEvent AuditEvent) WithOutcome(err error) AuditEvent {
+	switch err.(type) {
+	case nil:
+		auditEvent.Outcome = AuditSuccess
+		return auditEvent
+	case *customErrors.NamespacePermissionDeniedError,
+		*customErrors.UnauthorizedToAccessNamespaceError,
+		*customErrors.UnauthorizedToRemoveAdminFromNamespaceError,
+		*customErrors.NamespaceInvitationEmailMismatchError:
+		auditEvent.Outcome = AuditDenied
+	default:
+		auditEvent.Outcome = AuditFailure
+	}
+	auditEvent.Error = TruncateText(err.Error(), MaxRecordedErrorLength)
+	return auditEvent
+}
+
+// NewAuditChanges returns the fields that differ between two specs.
+// Specs are compared through their JSON representation, so that only what the API exposes is recorded.
+func NewAuditChanges(before interface{}, after interface{}) AuditChanges {
+	beforeFields := map[string]interface{}{}
+	flattenAuditFields("", toJSONValue(before), beforeFields)
+	afterFields := map[string]interface{}{}
+	flattenAuditFields("", toJSONValue(after), afterFields)
+
+	fields := []string{}
+	for field := range beforeFields {
+		fields = append(fields, field)
+	}
+	for field := range afterFields {
+		if _, ok := beforeFields[field]; !ok {
+			fields = append(fields, field)
+		}
+	}
+	sort.Strings(fields)
+
+	changes := AuditChanges{}
+	for _, field := range fields {
+		if !reflect.DeepEqual(beforeFields[field], afterFields[field]) {
+			changes = append(changes, AuditChange{Field: field, Before: beforeFields[field], After: afterFields[field]})
+		}
+	}
+	return changes
+}
+
+func toJSONValue(spec interface{}) interface{} {
+	if spec == nil {
+		return nil
+	}
+	bytes, err := json.Marshal(spec)
+	if err != nil {
+		return nil
+	}
+	var value interface{}
+	if err := json.Unmarshal(bytes, &value); err != nil {
+		return nil
+	}
+	return value
+}
+
+func flattenAuditFields(prefix string, value interface{}, fields map[string]interface{}) {
+	object, ok := value.(map[string]interface{})
+	if !ok {
+		if prefix != "" && value != nil {
+			fields[prefix] = value
+		}
+		return
+	}
+	for key, nestedValue := range object {
+		field := key
+		if prefix != "" {
+			field = prefix + "." + key
+		}
+		flattenAuditFields(field, nestedValue, fields)
+	}
+}
@@ -0,0 +1,56 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	customErrors "cloud-app-hive/controllers/errors"
+)
+
+func TestNewAuditChanges(t *testing.T) {
+	before := ApplicationManifest{Name: "frontend", Image: "nginx:1.24", Port: 80, ScalabilitySpecifications: ApplicationScalabilitySpecifications{Replicas: 1}}
+	after := ApplicationManifest{Name: "frontend", Image: "nginx:1.25", Port: 80, ScalabilitySpecifications: ApplicationScalabilitySpecifications{Replicas: 2}}
+
+	changes := NewAuditChanges(before, after)
+	if len(changes) != 2 {
+		t.Fatalf("expected the image and the replicas to change, got %+v", changes)
+	}
+	if changes[0].Field != "image" || changes[0].Before != "nginx:1.24" || changes[0].After != "nginx:1.25" {
+		t.Errorf("unexpected image change %+v", changes[0])
+	}
+	if !strings.HasSuffix(changes[1].Field, ".replicas") || changes[1].Before != float64(1) || changes[1].After != float64(2) {
+		t.Errorf("unexpected replicas change %+v", changes[1])
+	}
+
+	if len(NewAuditChanges(before, before)) != 0 {
+		t.Error("expected no change between identical specs")
+	}
+	for _, change := range NewAuditChanges(nil, after) {
+		if change.Before != nil {
+			t.Errorf("expected a creation to have no previous value, got %+v", change)
+		}
+	}
+}
+
+func TestAuditEvent_WithOutcome(t *testing.T) {
+	auditEvent := NewAuditEvent(SchedulerAuditActor(), AuditApplicationScale, AuditTargetApplication, "application-id")
+	if auditEvent.WithOutcome(nil).Outcome != AuditSuccess || auditEvent.ActorID != SchedulerActorID {
+		t.Error("expected a scheduler action without error to succeed")
+	}
+
+	denied := auditEvent.WithOutcome(customErrors.NewNamespacePermissionDeniedError("namespace-id", "team", "viewer", "VIEWER", "application:scale"))
+	if denied.Outcome != AuditDenied || denied.Error == "" {
+		t.Errorf("expected a permission error to be denied, got %+v", denied)
+	}
+
+	failed := auditEvent.WithOutcome(fmt.Errorf("%s", strings.Repeat("x", 2000)))
+	if failed.Outcome != AuditFailure || len(failed.Error) != 1000 {
+		t.Errorf("expected a truncated failure, got %s with %d characters", failed.Outcome, len(failed.Error))
+	}
+	multiByteFailure := auditEvent.WithOutcome(fmt.Errorf("x%s", strings.Repeat("€", 1000)))
+	if len(multiByteFailure.Error) > 1000 || !utf8.ValidString(multiByteFailure.Error) {
+		t.Errorf("expected a failure truncated on a rune boundary, got %d bytes", len(multiByteFailure.Error))
+	}
+}
@@ -0,0 +1,67 @@
+package commands
+
+import "cloud-app-hive/domain"
+
+type CreateAddon struct {
+	NamespaceID string
+	Name        string
+	Type        domain.AddonType
+	Size        domain.AddonSize
+	CreatedBy   string
+	Actor       domain.AuditActor
+}
+
+type DeleteAddon struct {
+	NamespaceID string
+	AddonID     string
+	DeletedBy   string
+	Actor       domain.AuditActor
+}
+
+// BindAddon is a command that represents a request to give an application the connection settings of an add-on
+type BindAddon struct {
+	NamespaceID   string
+	AddonID       string
+	ApplicationID string
+	BoundBy       string
+	Actor         domain.AuditActor
+}
+
+type UnbindAddon struct {
+	NamespaceID   string
+	AddonID       string
+	ApplicationID string
+	UnboundBy     string
+	Actor         domain.AuditActor
+}
+
+// ApplyAddon is a command that represents the provisioning of an add-on
+type ApplyAddon struct {
+	Name      string
+	Namespace string
+	Type      domain.AddonType
+	Size      domain.AddonSize
+	Username  string
+	Database  string
+	// Password is the password of the add-on, still encrypted
+	Password domain.ApplicationSecret
+}
+
+// NewApplyAddon builds the provisioning command of a stored add-on
+func NewApplyAddon(addon domain.Addon, namespace string) ApplyAddon {
+	return ApplyAddon{
+		Name:      addon.Name,
+		Namespace: namespace,
+		Type:      addon.Type,
+		Size:      addon.Size,
+		Username:  addon.Username,
+		Database:  addon.Database,
+		Password:  addon.Password(),
+	}
+}
+
+// UnapplyAddon is a command that represents the removal of an add-on and of its data
+type UnapplyAddon struct {
+	Name      string
+	Namespace string
+}
@@ -0,0 +1,28 @@
+package commands
+
+import "cloud-app-hive/domain"
+
+// SetApplicationSecret is a command that represents adding a secret to an application or rotating its value or reference
+type SetApplicationSecret struct {
+	ApplicationID string
+	Name          string
+	Value         string
+	// Reference replaces the value by the location of the secret in a secret provider
+	Reference string
+	// Restart rolls the pods of the application out so that they read the new value,
+	// which they otherwise only do when they are recreated
+	Restart bool
+	UserID  string
+	// Actor is recorded in the audit log
+	Actor domain.AuditActor
+}
+
+// DeleteApplicationSecret is a command that represents the removal of a secret from an application.
+// Removing a secret changes the environment of the pods, so they are always rolled out.
+type DeleteApplicationSecret struct {
+	ApplicationID string
+	Name          string
+	UserID        string
+	// Actor is recorded in the audit log
+	Actor domain.AuditActor
+}
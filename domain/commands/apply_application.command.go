@@ -6,6 +6,8 @@ import "cloud-app-hive/domain"
 type ApplyApplication struct {
 	Name                      string
 	Image                     string
+	ImageDigest               string
+	PinImageDigest            bool
 	Registry                  domain.ImageRegistry
 	Namespace                 string
 	Port                      uint32
@@ -14,4 +16,62 @@ type ApplyApplication struct {
 	Secrets                   domain.ApplicationSecrets
 	ContainerSpecifications   domain.ApplicationContainerSpecifications
 	ScalabilitySpecifications domain.ApplicationScalabilitySpecifications
+	Ingress                   domain.ApplicationIngress
+	Dependencies              domain.ApplicationDependencies
+	// RegistryCredential pulls the image of applications using the custom registry, with its password still encrypted
+	RegistryCredential *domain.RegistryCredential
+	// ConfigGroups are the config groups the application reads, in the order of their precedence
+	ConfigGroups []domain.ConfigGroup
+	// ConfigFiles are mounted in the container, with the contents of the secret files still encrypted
+	ConfigFiles domain.ApplicationConfigFiles
+	// NamespaceID scopes the paths of the secret references, unlike the name of the namespace it is never reused
+	NamespaceID string
+	// StepReporter is told when each step of the deployment starts and finishes, it may be nil
+	StepReporter domain.OperationStepReporter `json:"-"`
+}
+
+// NewApplyApplication builds the deployment command of a stored application
+func NewApplyApplication(application domain.Application, namespace string) ApplyApplication {
+	applyApplication := ApplyApplication{
+		Name:            application.Name,
+		Image:           application.Image,
+		ImageDigest:     application.ImageDigest,
+		PinImageDigest:  application.PinImageDigest,
+		Registry:        application.Registry,
+		Namespace:       namespace,
+		NamespaceID:     application.NamespaceID,
+		Port:            application.Port,
+		ApplicationType: application.ApplicationType,
+	}
+	if application.Registry == domain.CustomRegistry {
+		applyApplication.RegistryCredential = application.RegistryCredential
+	}
+	if application.EnvironmentVariables != nil {
+		applyApplication.EnvironmentVariables = *application.EnvironmentVariables
+	}
+	if application.Secrets != nil {
+		applyApplication.Secrets = *application.Secrets
+	}
+	if len(application.AddonBindings) > 0 {
+		applyApplication.Secrets = append(append(domain.ApplicationSecrets{}, applyApplication.Secrets...), domain.AddonBindingSecrets(application.AddonBindings, applyApplication.Secrets)...)
+	}
+	if len(application.ConfigGroupAttachments) > 0 {
+		applyApplication.ConfigGroups = domain.AttachedConfigGroups(application.ConfigGroupAttachments)
+	}
+	if application.ContainerSpecifications != nil {
+		applyApplication.ContainerSpecifications = application.ContainerSpecifications.Data()
+	}
+	if application.ScalabilitySpecifications != nil {
+		applyApplication.ScalabilitySpecifications = application.ScalabilitySpecifications.Data()
+	}
+	if application.Ingress != nil {
+		applyApplication.Ingress = *application.Ingress
+	}
+	if application.Dependencies != nil {
+		applyApplication.Dependencies = *application.Dependencies
+	}
+	if application.ConfigFiles != nil {
+		applyApplication.ConfigFiles = *application.ConfigFiles
+	}
+	return applyApplication
 }
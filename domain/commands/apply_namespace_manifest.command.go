@@ -0,0 +1,14 @@
+package commands
+
+import "cloud-app-hive/domain"
+
+// ApplyNamespaceManifest is a command that represents the reconciliation of a namespace with a manifest
+type ApplyNamespaceManifest struct {
+	NamespaceID string
+	UserID      string
+	Manifest    domain.NamespaceManifest
+	// Prune deletes the applications of the namespace that are not declared in the manifest
+	Prune bool
+	// Actor is recorded in the audit log
+	Actor domain.AuditActor
+}
@@ -0,0 +1,76 @@
+package commands
+
+import "cloud-app-hive/domain"
+
+type CreateConfigGroup struct {
+	NamespaceID          string
+	Name                 string
+	Description          string
+	EnvironmentVariables domain.ApplicationEnvironmentVariables
+	Secrets              domain.ApplicationSecrets
+	CreatedBy            string
+	Actor                domain.AuditActor
+}
+
+// UpdateConfigGroup is a command that represents the replacement of the values of a config group,
+// a secret without value nor reference keeps its current value
+type UpdateConfigGroup struct {
+	NamespaceID          string
+	ConfigGroupID        string
+	Description          string
+	EnvironmentVariables domain.ApplicationEnvironmentVariables
+	Secrets              domain.ApplicationSecrets
+	UpdatedBy            string
+	Actor                domain.AuditActor
+}
+
+type DeleteConfigGroup struct {
+	NamespaceID   string
+	ConfigGroupID string
+	DeletedBy     string
+	Actor         domain.AuditActor
+}
+
+// SetApplicationConfigGroups is a command that represents the replacement of the config groups an application is attached to,
+// the values of a group take precedence over the values of the groups before it
+type SetApplicationConfigGroups struct {
+	ApplicationID    string
+	ConfigGroupNames []string
+	UserID           string
+	Actor            domain.AuditActor
+}
+
+// ApplyConfigGroup is a command that represents the rendering of a config group in a namespace
+type ApplyConfigGroup struct {
+	Name                 string
+	Namespace            string
+	Version              int
+	EnvironmentVariables domain.ApplicationEnvironmentVariables
+	// Secrets are the secrets of the group, still encrypted
+	Secrets domain.ApplicationSecrets
+	// NamespaceID scopes the paths of the secret references, unlike the name of the namespace it is never reused
+	NamespaceID string
+}
+
+// NewApplyConfigGroup builds the rendering command of a stored config group
+func NewApplyConfigGroup(configGroup domain.ConfigGroup, namespace string) ApplyConfigGroup {
+	applyConfigGroup := ApplyConfigGroup{
+		Name:        configGroup.Name,
+		Namespace:   namespace,
+		NamespaceID: configGroup.NamespaceID,
+		Version:     configGroup.Version,
+	}
+	if configGroup.EnvironmentVariables != nil {
+		applyConfigGroup.EnvironmentVariables = *configGroup.EnvironmentVariables
+	}
+	if configGroup.Secrets != nil {
+		applyConfigGroup.Secrets = *configGroup.Secrets
+	}
+	return applyConfigGroup
+}
+
+// UnapplyConfigGroup is a command that represents the removal of a config group from a namespace
+type UnapplyConfigGroup struct {
+	Name      string
+	Namespace string
+}
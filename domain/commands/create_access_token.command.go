@@ -0,0 +1,19 @@
+package commands
+
+import (
+	"time"
+
+	"cloud-app-hive/domain"
+)
+
+// CreateAccessToken is a command that represents the intent to mint an access token
+type CreateAccessToken struct {
+	Name        string
+	UserID      string
+	Scopes      domain.AccessTokenScopes
+	NamespaceID *string
+	ExpiresAt   *time.Time
+	// SecretHash and SecretStart are computed from the secret, which is never stored
+	SecretHash  string
+	SecretStart string
+}
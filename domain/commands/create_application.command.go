@@ -2,6 +2,8 @@ package commands
 
 import (
 	"cloud-app-hive/domain"
+
+	"gorm.io/datatypes"
 )
 
 // CreateApplication is a command that represents the deployment of an application
@@ -10,7 +12,10 @@ type CreateApplication struct {
 	Name                      string
 	Description               string
 	Image                     string
+	ImageDigest               string
+	PinImageDigest            bool
 	Registry                  domain.ImageRegistry
+	RegistryCredentialID      *string
 	NamespaceID               string
 	Port                      uint32
 	Zone                      string
@@ -19,5 +24,40 @@ type CreateApplication struct {
 	Secrets                   domain.ApplicationSecrets
 	ContainerSpecifications   domain.ApplicationContainerSpecifications
 	ScalabilitySpecifications domain.ApplicationScalabilitySpecifications
+	Ingress                   domain.ApplicationIngress
+	Dependencies              domain.ApplicationDependencies
+	ConfigFiles               domain.ApplicationConfigFiles
 	AdministratorEmail        string
+	// DryRun runs every check of the creation without storing nor deploying the application, the image is not scanned but checked against its stored scan
+	DryRun bool
+	// Actor is recorded in the audit log
+	Actor domain.AuditActor
+}
+
+// Application builds the application described by the command, without ID
+func (createApplication CreateApplication) Application() domain.Application {
+	containerSpecs := datatypes.NewJSONType(createApplication.ContainerSpecifications)
+	scalabilitySpecs := datatypes.NewJSONType(createApplication.ScalabilitySpecifications)
+	return domain.Application{
+		Name:                      createApplication.Name,
+		Description:               createApplication.Description,
+		Image:                     createApplication.Image,
+		ImageDigest:               createApplication.ImageDigest,
+		PinImageDigest:            createApplication.PinImageDigest,
+		Registry:                  createApplication.Registry,
+		RegistryCredentialID:      createApplication.RegistryCredentialID,
+		UserID:                    createApplication.UserID,
+		NamespaceID:               createApplication.NamespaceID,
+		Port:                      createApplication.Port,
+		Zone:                      createApplication.Zone,
+		ApplicationType:           createApplication.ApplicationType,
+		EnvironmentVariables:      &createApplication.EnvironmentVariables,
+		Secrets:                   &createApplication.Secrets,
+		ContainerSpecifications:   &containerSpecs,
+		ScalabilitySpecifications: &scalabilitySpecs,
+		Ingress:                   &createApplication.Ingress,
+		Dependencies:              &createApplication.Dependencies,
+		ConfigFiles:               &createApplication.ConfigFiles,
+		AdministratorEmail:        createApplication.AdministratorEmail,
+	}
 }
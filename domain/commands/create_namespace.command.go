@@ -1,8 +1,12 @@
 package commands
 
+import "cloud-app-hive/domain"
+
 // CreateNamespace is a command that represents the deployment of a namespace
 type CreateNamespace struct {
 	Name        string
 	Description string
 	UserID      string
+	// Actor is recorded in the audit log
+	Actor domain.AuditActor
 }
@@ -6,6 +6,8 @@ import "cloud-app-hive/domain"
 type CreateNamespaceMembership struct {
 	UserID      string      `json:"user_id" binding:"required"`
 	NamespaceID string      `json:"namespace_id" binding:"required"`
-	Role        domain.Role `json:"role" binding:"required,oneof=ADMIN MEMBER"`
+	Role        domain.Role `json:"role" binding:"required,oneof=OWNER ADMIN DEVELOPER MEMBER VIEWER"`
 	AddedBy     string      `json:"added_by" binding:"required"`
+	// Actor is recorded in the audit log
+	Actor domain.AuditActor `json:"-"`
 }
@@ -1,7 +1,11 @@
 package commands
 
+import "cloud-app-hive/domain"
+
 // DeleteApplication is a command that represents a request to get the metrics of an application
 type DeleteApplication struct {
 	ID     string
 	UserID string
+	// Actor is recorded in the audit log
+	Actor domain.AuditActor
 }
@@ -0,0 +1,22 @@
+package commands
+
+import "cloud-app-hive/domain"
+
+// DeployApplication is a command that represents the deployment of a stored application on the cluster
+type DeployApplication struct {
+	Application   domain.Application
+	NamespaceName string
+	// Actor is recorded in the audit log
+	Actor domain.AuditActor
+	// StepReporter is told of the progress of the deployment on the cluster, it is nil when no operation tracks it
+	StepReporter domain.OperationStepReporter
+}
+
+// UndeployApplication is a command that represents the removal of an application from the cluster
+type UndeployApplication struct {
+	Application domain.Application
+	// Actor is recorded in the audit log
+	Actor domain.AuditActor
+	// StepReporter is told of the progress of the removal on the cluster, it is nil when no operation tracks it
+	StepReporter domain.OperationStepReporter
+}
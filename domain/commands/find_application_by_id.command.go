@@ -1,7 +1,11 @@
 package commands
 
+import "cloud-app-hive/domain"
+
 // FindApplicationByID is a command that represents the parameters for finding applications
 type FindApplicationByID struct {
 	ApplicationID string
 	QueryByUserID string
+	// Permission is the action the user wants to perform on the application, reading it by default
+	Permission domain.Permission
 }
@@ -9,6 +9,8 @@ type FindApplications struct {
 	NamespaceID     *string
 	ApplicationType *domain.ApplicationType
 	IsAutoScaled    *bool
-	Page            uint32
-	Limit           uint32
+	// MemberUserID restricts the applications to the namespaces the user is a member of
+	MemberUserID *string
+	Page         uint32
+	Limit        uint32
 }
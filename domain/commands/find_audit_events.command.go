@@ -0,0 +1,21 @@
+package commands
+
+import (
+	"time"
+
+	"cloud-app-hive/domain"
+)
+
+// FindAuditEvents is a command that represents the filters of the audit log of a namespace
+type FindAuditEvents struct {
+	NamespaceID string
+	UserID      string
+	Action      *domain.AuditAction
+	ActorID     *string
+	TargetID    *string
+	Outcome     *domain.AuditOutcome
+	From        *time.Time
+	To          *time.Time
+	Page        uint32
+	Limit       uint32
+}
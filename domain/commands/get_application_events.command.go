@@ -0,0 +1,9 @@
+package commands
+
+// GetApplicationEvents is a command that represents a request to get the Kubernetes events of an application
+type GetApplicationEvents struct {
+	Name      string
+	Namespace string
+	// UserID is the user reading the events, whose concurrent streams are limited
+	UserID string
+}
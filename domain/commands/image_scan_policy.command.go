@@ -0,0 +1,12 @@
+package commands
+
+import "cloud-app-hive/domain"
+
+// UpdateImageScanPolicy is a command that represents the image scan policy of a namespace
+type UpdateImageScanPolicy struct {
+	NamespaceID string
+	Policy      domain.ImageScanPolicy
+	MaxSeverity domain.VulnerabilitySeverity
+	UpdatedBy   string
+	Actor       domain.AuditActor
+}
@@ -0,0 +1,39 @@
+package commands
+
+import (
+	"time"
+
+	"cloud-app-hive/domain"
+)
+
+// CreateNamespaceInvitation is a command that represents the intent to invite someone to a namespace by email
+type CreateNamespaceInvitation struct {
+	NamespaceID string
+	Email       string
+	Role        domain.Role
+	InvitedBy   string
+	// TokenHash and ExpiresAt are computed when the invitation is sent, the token itself is never stored
+	TokenHash string
+	ExpiresAt time.Time
+	// Actor is recorded in the audit log
+	Actor domain.AuditActor
+}
+
+// RespondToNamespaceInvitation is a command that represents the intent of an invitee to accept or decline an invitation
+type RespondToNamespaceInvitation struct {
+	Token  string
+	UserID string
+	// UserEmail is the verified email of the signed in user, it must be the one the invitation was sent to
+	UserEmail string
+	// Actor is recorded in the audit log
+	Actor domain.AuditActor
+}
+
+// RevokeNamespaceInvitation is a command that represents the intent to cancel a pending invitation
+type RevokeNamespaceInvitation struct {
+	NamespaceID  string
+	InvitationID string
+	RevokedBy    string
+	// Actor is recorded in the audit log
+	Actor domain.AuditActor
+}
@@ -0,0 +1,20 @@
+package commands
+
+import "cloud-app-hive/domain"
+
+// TransferNamespaceOwnership is a command that represents the intent of the owner to transfer a namespace to one of its members
+type TransferNamespaceOwnership struct {
+	NamespaceID string
+	NewOwnerID  string
+	RequestedBy string
+	// Actor is recorded in the audit log
+	Actor domain.AuditActor
+}
+
+// RespondToNamespaceOwnershipTransfer is a command that represents the intent to accept, decline or cancel a pending ownership transfer
+type RespondToNamespaceOwnershipTransfer struct {
+	NamespaceID string
+	UserID      string
+	// Actor is recorded in the audit log
+	Actor domain.AuditActor
+}
@@ -0,0 +1,35 @@
+package commands
+
+import "cloud-app-hive/domain"
+
+type CreateNetworkRule struct {
+	NamespaceID       string
+	ApplicationID     *string
+	FromNamespaceID   string
+	FromApplicationID *string
+	Description       string
+	CreatedBy         string
+	Actor             domain.AuditActor
+}
+
+type DeleteNetworkRule struct {
+	NamespaceID   string
+	NetworkRuleID string
+	DeletedBy     string
+	Actor         domain.AuditActor
+}
+
+// ApplyNetworkRules is a command that represents the network rules of a namespace, with the names its objects have on the container manager
+type ApplyNetworkRules struct {
+	Namespace string
+	Rules     []ApplyNetworkRule
+}
+
+// ApplyNetworkRule allows the traffic from the applications of FromNamespace, or only FromApplicationName, to ApplicationName,
+// or to every application of the namespace when it is nil
+type ApplyNetworkRule struct {
+	ID                  string
+	ApplicationName     *string
+	FromNamespace       string
+	FromApplicationName *string
+}
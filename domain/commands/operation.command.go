@@ -0,0 +1,13 @@
+package commands
+
+import "cloud-app-hive/domain"
+
+// StartApplicationOperation is a command that represents a request to run an operation on an application in the background
+type StartApplicationOperation struct {
+	Type          domain.OperationType
+	Application   domain.Application
+	NamespaceName string
+	UserID        string
+	// Run applies the operation on the cluster, it tells the reporter when each step starts and finishes
+	Run func(reporter domain.OperationStepReporter) error
+}
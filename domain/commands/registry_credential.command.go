@@ -0,0 +1,31 @@
+package commands
+
+import "cloud-app-hive/domain"
+
+type CreateRegistryCredential struct {
+	NamespaceID string
+	Name        string
+	Server      string
+	Username    string
+	Password    string
+	CreatedBy   string
+	Actor       domain.AuditActor
+}
+
+// UpdateRegistryCredential is a command that represents the new account of a registry credential, an empty password keeps the stored one
+type UpdateRegistryCredential struct {
+	NamespaceID          string
+	RegistryCredentialID string
+	Server               string
+	Username             string
+	Password             string
+	UpdatedBy            string
+	Actor                domain.AuditActor
+}
+
+type DeleteRegistryCredential struct {
+	NamespaceID          string
+	RegistryCredentialID string
+	DeletedBy            string
+	Actor                domain.AuditActor
+}
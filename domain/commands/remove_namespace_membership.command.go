@@ -1,8 +1,12 @@
 package commands
 
+import "cloud-app-hive/domain"
+
 // RemoveNamespaceMembership is a command that represents the intent to create a namespace membership.
 type RemoveNamespaceMembership struct {
 	UserID      string
 	NamespaceID string
 	RemovedBy   string
+	// Actor is recorded in the audit log
+	Actor domain.AuditActor
 }
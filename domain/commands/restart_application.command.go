@@ -0,0 +1,7 @@
+package commands
+
+// RestartApplication is a command that represents a rolling restart of the pods of an application
+type RestartApplication struct {
+	Name      string
+	Namespace string
+}
@@ -1,7 +1,11 @@
 package commands
 
+import "cloud-app-hive/domain"
+
 // UnapplyApplication is a command that represents a request to get the metrics of an application
 type UnapplyApplication struct {
 	Name      string
 	Namespace string
+	// StepReporter is told when each step of the removal starts and finishes, it may be nil
+	StepReporter domain.OperationStepReporter
 }
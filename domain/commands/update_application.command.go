@@ -1,18 +1,67 @@
 package commands
 
-import "cloud-app-hive/domain"
+import (
+	"cloud-app-hive/domain"
+
+	"gorm.io/datatypes"
+)
 
 // UpdateApplication is a command that represents the deployment of an application
 type UpdateApplication struct {
 	UserID                    string
 	Description               string
 	Image                     string
+	ImageDigest               string
+	PinImageDigest            bool
 	Registry                  domain.ImageRegistry
+	RegistryCredentialID      *string
 	Port                      uint32
 	ApplicationType           domain.ApplicationType
 	EnvironmentVariables      domain.ApplicationEnvironmentVariables
 	Secrets                   domain.ApplicationSecrets
 	ContainerSpecifications   domain.ApplicationContainerSpecifications
 	ScalabilitySpecifications domain.ApplicationScalabilitySpecifications
+	Ingress                   domain.ApplicationIngress
+	Dependencies              domain.ApplicationDependencies
+	ConfigFiles               *domain.ApplicationConfigFiles // Keeps the current config files when nil
 	AdministratorEmail        string
+	// DryRun runs every check of the update without storing nor deploying the application, the image is not scanned but checked against its stored scan
+	DryRun bool
+	// Actor is recorded in the audit log
+	Actor domain.AuditActor
+}
+
+// ApplyTo sets the updated fields on an application
+func (updateApplication UpdateApplication) ApplyTo(application *domain.Application) {
+	application.Description = updateApplication.Description
+	application.Image = updateApplication.Image
+	application.ImageDigest = updateApplication.ImageDigest
+	application.PinImageDigest = updateApplication.PinImageDigest
+	application.Registry = updateApplication.Registry
+	if !equalRegistryCredentialIDs(application.RegistryCredentialID, updateApplication.RegistryCredentialID) {
+		// The credential is loaded again by the repository once the application is stored
+		application.RegistryCredential = nil
+	}
+	application.RegistryCredentialID = updateApplication.RegistryCredentialID
+	application.Port = updateApplication.Port
+	application.ApplicationType = updateApplication.ApplicationType
+	application.EnvironmentVariables = &updateApplication.EnvironmentVariables
+	application.Secrets = &updateApplication.Secrets
+	containerSpecs := datatypes.NewJSONType(updateApplication.ContainerSpecifications)
+	application.ContainerSpecifications = &containerSpecs
+	scalabilitySpecs := datatypes.NewJSONType(updateApplication.ScalabilitySpecifications)
+	application.ScalabilitySpecifications = &scalabilitySpecs
+	application.Ingress = &updateApplication.Ingress
+	application.Dependencies = &updateApplication.Dependencies
+	if updateApplication.ConfigFiles != nil {
+		application.ConfigFiles = updateApplication.ConfigFiles
+	}
+	application.AdministratorEmail = updateApplication.AdministratorEmail
+}
+
+func equalRegistryCredentialIDs(a *string, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
 }
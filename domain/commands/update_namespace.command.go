@@ -1,8 +1,12 @@
 package commands
 
+import "cloud-app-hive/domain"
+
 // UpdateNamespace is a command that represents a user's request to update a namespace
 type UpdateNamespace struct {
 	ID          string
 	Description string
 	UserID      string
+	// Actor is recorded in the audit log
+	Actor domain.AuditActor
 }
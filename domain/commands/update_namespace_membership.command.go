@@ -2,9 +2,12 @@ package commands
 
 import "cloud-app-hive/domain"
 
-// UpdateNamespaceMembership is a command that represents the intent to create a namespace membership.
+// UpdateNamespaceMembership is a command that represents the intent to change the role of a namespace member.
 type UpdateNamespaceMembership struct {
 	UserID      string      `json:"user_id" binding:"required"`
 	NamespaceID string      `json:"namespace_id" binding:"required"`
-	Role        domain.Role `json:"role" binding:"required,oneof=ADMIN MEMBER"`
+	Role        domain.Role `json:"role" binding:"required,oneof=OWNER ADMIN DEVELOPER MEMBER VIEWER"`
+	UpdatedBy   string      `json:"updated_by" binding:"required"`
+	// Actor is recorded in the audit log
+	Actor domain.AuditActor `json:"-"`
 }
@@ -0,0 +1,29 @@
+package commands
+
+import "cloud-app-hive/domain"
+
+type CreateWebhook struct {
+	NamespaceID string
+	URL         string
+	EventTypes  domain.WebhookEventTypes
+	CreatedBy   string
+	Actor       domain.AuditActor
+}
+
+// UpdateWebhook is a command that represents the new endpoint and subscriptions of a webhook, its secret is kept
+type UpdateWebhook struct {
+	NamespaceID string
+	WebhookID   string
+	URL         string
+	EventTypes  domain.WebhookEventTypes
+	Active      bool
+	UpdatedBy   string
+	Actor       domain.AuditActor
+}
+
+type DeleteWebhook struct {
+	NamespaceID string
+	WebhookID   string
+	DeletedBy   string
+	Actor       domain.AuditActor
+}
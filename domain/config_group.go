@@ -0,0 +1,169 @@
+package domain
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ConfigGroup is a struct that represents environment variables and secrets shared by applications of a namespace.
+// A config group is rendered as a ConfigMap and a Secret, and the applications attached to it read both as environment variables.
+type ConfigGroup struct {
+	ID          string `json:"id" gorm:"primaryKey"`
+	NamespaceID string `json:"namespaceId" gorm:"size:255;index:idx_config_group_namespace_id;not null"`
+	Name        string `json:"name" gorm:"size:40;not null"`
+	Description string `json:"description" gorm:"size:255"`
+	// EnvironmentVariables are the plain values of the group, written in its ConfigMap
+	EnvironmentVariables *ApplicationEnvironmentVariables `json:"environmentVariables" gorm:"type:json"`
+	// Secrets are encrypted with the data key of the namespace, their values are never written in API responses
+	Secrets *ApplicationSecrets `json:"secrets" gorm:"type:json"`
+	// Version is incremented each time the group is updated, so that the pods of the attached applications are rolled out
+	Version     int                     `json:"version" gorm:"not null;default:1"`
+	Attachments []ConfigGroupAttachment `json:"attachments" gorm:"foreignKey:ConfigGroupID;references:ID"`
+	CreatedBy   string                  `json:"createdBy" gorm:"size:255;not null"`
+	UpdatedAt   time.Time               `json:"updatedAt" gorm:"autoUpdateTime"`
+	CreatedAt   time.Time               `json:"createdAt" gorm:"autoCreateTime"`
+	DeletedAt   *gorm.DeletedAt         `json:"deletedAt" gorm:"index;default:null"`
+}
+
+// ConfigGroupAttachment is a struct that represents an application reading the values of a config group.
+// When several groups of an application define the same name, the group with the highest position wins.
+type ConfigGroupAttachment struct {
+	ID            string       `json:"id" gorm:"primaryKey"`
+	ConfigGroupID string       `json:"configGroupId" gorm:"size:255;uniqueIndex:idx_config_group_attachment_group_application;not null"`
+	ApplicationID string       `json:"applicationId" gorm:"size:255;uniqueIndex:idx_config_group_attachment_group_application;index:idx_config_group_attachment_application_id;not null"`
+	Position      int          `json:"position" gorm:"not null"`
+	ConfigGroup   *ConfigGroup `json:"configGroup,omitempty" gorm:"foreignKey:ConfigGroupID;references:ID"`
+	CreatedBy     string       `json:"createdBy" gorm:"size:255;not null"`
+	CreatedAt     time.Time    `json:"createdAt" gorm:"autoCreateTime"`
+}
+
+const MaxConfigGroupsByNamespace = 20
+
+const MaxConfigGroupsByApplication = 5
+
+var configGroupNameRegex = regexp.MustCompile(`^[a-z]([-a-z0-9]{0,38}[a-z0-9])?$`)
+
+// NewConfigGroup returns a config group of a namespace once its name and values are valid
+func NewConfigGroup(
+	namespaceID string,
+	name string,
+	description string,
+	environmentVariables ApplicationEnvironmentVariables,
+	secrets ApplicationSecrets,
+	createdBy string,
+) (*ConfigGroup, error) {
+	configGroup := ConfigGroup{
+		NamespaceID: namespaceID,
+		Name:        name,
+		Description: description,
+		CreatedBy:   createdBy,
+	}
+	if err := configGroup.Update(description, environmentVariables, secrets, time.Now()); err != nil {
+		return nil, err
+	}
+	return &configGroup, nil
+}
+
+// Update replaces the values of the config group and increments its version.
+// A secret given without value nor reference keeps its current value.
+func (configGroup *ConfigGroup) Update(description string, environmentVariables ApplicationEnvironmentVariables, secrets ApplicationSecrets, now time.Time) error {
+	if err := ValidateConfigGroup(configGroup.Name, environmentVariables, secrets); err != nil {
+		return err
+	}
+	mergedSecrets, err := secrets.Merge(configGroup.Secrets, now)
+	if err != nil {
+		return err
+	}
+	configGroup.Description = description
+	configGroup.EnvironmentVariables = &environmentVariables
+	configGroup.Secrets = &mergedSecrets
+	configGroup.Version++
+	return nil
+}
+
+// ValidateConfigGroup checks the name of a config group and the names of its values,
+// a name cannot be both an environment variable and a secret of the group
+func ValidateConfigGroup(name string, environmentVariables ApplicationEnvironmentVariables, secrets ApplicationSecrets) error {
+	if !configGroupNameRegex.MatchString(name) {
+		return fmt.Errorf("name %s must be at most 40 lowercase alphanumeric characters or '-', starting with a letter and ending with an alphanumeric character", name)
+	}
+	if err := environmentVariables.Validate(); err != nil {
+		return err
+	}
+	if err := secrets.Validate(); err != nil {
+		return err
+	}
+	names := map[string]bool{}
+	for _, environmentVariable := range environmentVariables {
+		if names[environmentVariable.Name] {
+			return fmt.Errorf("environment variable %s is defined twice", environmentVariable.Name)
+		}
+		names[environmentVariable.Name] = true
+	}
+	for _, secret := range secrets {
+		// Secrets are read as environment variables too
+		if !IsAValidEnvironmentVariableName(secret.Name) {
+			return fmt.Errorf("secret %s must be a valid environment variable name", secret.Name)
+		}
+		if names[secret.Name] {
+			return fmt.Errorf("%s is defined twice", secret.Name)
+		}
+		names[secret.Name] = true
+	}
+	return nil
+}
+
+// ApplicationNames returns the names of the applications attached to the config group, found in the given applications
+func (configGroup ConfigGroup) ApplicationNames(applications []Application) []string {
+	applicationNames := make([]string, 0, len(configGroup.Attachments))
+	for _, attachment := range configGroup.Attachments {
+		for _, application := range applications {
+			if application.ID == attachment.ApplicationID {
+				applicationNames = append(applicationNames, application.Name)
+			}
+		}
+	}
+	return applicationNames
+}
+
+// ConfigGroupConfigMapName returns the name of the ConfigMap holding the environment variables of a config group on the cluster
+func ConfigGroupConfigMapName(configGroupName string) string {
+	return fmt.Sprintf("%s-config-group", configGroupName)
+}
+
+// ConfigGroupSecretName returns the name of the Secret holding the secrets of a config group on the cluster
+func ConfigGroupSecretName(configGroupName string) string {
+	return fmt.Sprintf("%s-config-group-secrets", configGroupName)
+}
+
+// AttachedConfigGroups returns the config groups of the attachments in the order of their positions,
+// the values of a group take precedence over the values of the groups before it
+func AttachedConfigGroups(attachments []ConfigGroupAttachment) []ConfigGroup {
+	sortedAttachments := append([]ConfigGroupAttachment{}, attachments...)
+	sort.SliceStable(sortedAttachments, func(i, j int) bool {
+		return sortedAttachments[i].Position < sortedAttachments[j].Position
+	})
+	configGroups := make([]ConfigGroup, 0, len(sortedAttachments))
+	for _, attachment := range sortedAttachments {
+		if attachment.ConfigGroup == nil {
+			continue
+		}
+		configGroups = append(configGroups, *attachment.ConfigGroup)
+	}
+	return configGroups
+}
+
+// ConfigGroupVersions describes the versions of config groups, e.g. shared:3,database:1.
+// It changes each time one of the groups is updated.
+func ConfigGroupVersions(configGroups []ConfigGroup) string {
+	versions := make([]string, 0, len(configGroups))
+	for _, configGroup := range configGroups {
+		versions = append(versions, fmt.Sprintf("%s:%d", configGroup.Name, configGroup.Version))
+	}
+	return strings.Join(versions, ",")
+}
@@ -0,0 +1,66 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewConfigGroup(t *testing.T) {
+	configGroup, err := NewConfigGroup(
+		"team-id",
+		"shared",
+		"",
+		ApplicationEnvironmentVariables{{Name: "LOG_LEVEL", Val: "debug"}},
+		ApplicationSecrets{{Name: "SENTRY_DSN", Val: "https://key@sentry.example.com/1"}},
+		"admin",
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if configGroup.Version != 1 {
+		t.Errorf("expected the first version, got %d", configGroup.Version)
+	}
+	secret, _ := configGroup.Secrets.Find("SENTRY_DSN")
+	if secret.Version != 1 || secret.Preview == "" {
+		t.Errorf("expected a first version of the secret with a preview, got %+v", secret)
+	}
+
+	for _, invalid := range []struct {
+		name                 string
+		environmentVariables ApplicationEnvironmentVariables
+		secrets              ApplicationSecrets
+	}{
+		{"Shared", nil, nil},
+		{"shared", ApplicationEnvironmentVariables{{Name: "LOG-LEVEL", Val: "debug"}}, nil},
+		{"shared", ApplicationEnvironmentVariables{{Name: "TOKEN", Val: "a"}}, ApplicationSecrets{{Name: "TOKEN", Val: "b"}}},
+		{"shared", nil, ApplicationSecrets{{Name: "TOKEN"}}},
+	} {
+		if _, err := NewConfigGroup("team-id", invalid.name, "", invalid.environmentVariables, invalid.secrets, "admin"); err == nil {
+			t.Errorf("expected %+v to be rejected", invalid)
+		}
+	}
+}
+
+func TestConfigGroup_UpdateKeepsSecretsGivenWithoutValue(t *testing.T) {
+	configGroup, _ := NewConfigGroup("team-id", "shared", "", nil, ApplicationSecrets{{Name: "TOKEN", Val: "first"}}, "admin")
+
+	err := configGroup.Update("shared settings", ApplicationEnvironmentVariables{{Name: "LOG_LEVEL", Val: "info"}}, ApplicationSecrets{{Name: "TOKEN"}}, time.Now())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	secret, _ := configGroup.Secrets.Find("TOKEN")
+	if configGroup.Version != 2 || secret.Val != "first" || secret.Version != 1 {
+		t.Errorf("expected a second version of the group keeping the token, got version %d and %+v", configGroup.Version, secret)
+	}
+}
+
+func TestAttachedConfigGroups_FollowPositions(t *testing.T) {
+	configGroups := AttachedConfigGroups([]ConfigGroupAttachment{
+		{Position: 1, ConfigGroup: &ConfigGroup{Name: "database", Version: 1}},
+		{Position: 0, ConfigGroup: &ConfigGroup{Name: "shared", Version: 3}},
+		{Position: 2},
+	})
+	if versions := ConfigGroupVersions(configGroups); versions != "shared:3,database:1" {
+		t.Errorf("expected shared then database, got %s", versions)
+	}
+}
@@ -0,0 +1,101 @@
+package domain
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ImageReference is an image of a registry, the tag is "latest" when the image has neither tag nor digest
+type ImageReference struct {
+	Repository string
+	Tag        string
+	Digest     string
+}
+
+var imageRepositoryRegex = regexp.MustCompile(`^[a-z0-9]+([._-][a-z0-9]+)*(/[a-z0-9]+([._-][a-z0-9]+)*)*$`)
+
+var imageDigestRegex = regexp.MustCompile(`^[a-z0-9]+:[a-f0-9]{32,}$`)
+
+// ParseImageReference parses "<repository>[:<tag>][@<digest>]", the repository being relative to its registry
+func ParseImageReference(image string) (ImageReference, error) {
+	imageReference := ImageReference{}
+	repository := image
+	if name, digest, found := strings.Cut(image, "@"); found {
+		if !imageDigestRegex.MatchString(digest) {
+			return ImageReference{}, fmt.Errorf("image %s has an invalid digest", image)
+		}
+		repository = name
+		imageReference.Digest = digest
+	}
+	if tagIndex := strings.LastIndex(repository, ":"); tagIndex > strings.LastIndex(repository, "/") {
+		imageReference.Tag = repository[tagIndex+1:]
+		repository = repository[:tagIndex]
+	}
+	if !imageRepositoryRegex.MatchString(repository) {
+		return ImageReference{}, fmt.Errorf("image %s has an invalid repository", image)
+	}
+	imageReference.Repository = repository
+	if imageReference.Tag == "" && imageReference.Digest == "" {
+		imageReference.Tag = "latest"
+	}
+	return imageReference, nil
+}
+
+// ManifestReference returns the tag or digest identifying the manifest of the image in its repository
+func (imageReference ImageReference) ManifestReference() string {
+	if imageReference.Digest != "" {
+		return imageReference.Digest
+	}
+	return imageReference.Tag
+}
+
+// PinnedImage returns the image with its digest, so that every replica runs the same image even if its tag is moved
+func PinnedImage(image string, digest string) string {
+	name, _, _ := strings.Cut(image, "@")
+	return name + "@" + digest
+}
+
+// ImageRegistryEndpoint is the URL of a Docker registry and the account reading its images, anonymous when the username is empty
+type ImageRegistryEndpoint struct {
+	URL      string
+	Username string
+	Password string
+}
+
+// ImagePlatform is an operating system and architecture an image can run on
+type ImagePlatform struct {
+	OS           string `json:"os"`
+	Architecture string `json:"architecture"`
+}
+
+// ImageManifest is the manifest of an image found in its registry
+type ImageManifest struct {
+	Digest string
+	// Platforms is empty when the registry does not tell the platforms of the image
+	Platforms []ImagePlatform
+}
+
+// SupportsOneOf returns true if the image runs on one of the architectures, or if its platforms are unknown
+func (imageManifest ImageManifest) SupportsOneOf(architectures []string) bool {
+	if len(imageManifest.Platforms) == 0 {
+		return true
+	}
+	for _, platform := range imageManifest.Platforms {
+		for _, architecture := range architectures {
+			if platform.Architecture == architecture {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Architectures returns the architectures of the platforms of the image
+func (imageManifest ImageManifest) Architectures() []string {
+	architectures := []string{}
+	for _, platform := range imageManifest.Platforms {
+		architectures = append(architectures, platform.Architecture)
+	}
+	return architectures
+}
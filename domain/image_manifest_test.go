@@ -0,0 +1,47 @@
+package domain
+
+import "testing"
+
+func TestParseImageReference(t *testing.T) {
+	for image, expected := range map[string]ImageReference{
+		"nginx":                                 {Repository: "nginx", Tag: "latest"},
+		"library/nginx:1.25":                    {Repository: "library/nginx", Tag: "1.25"},
+		"team/api@sha256:" + testImageDigest:    {Repository: "team/api", Digest: "sha256:" + testImageDigest},
+		"team/api:v2@sha256:" + testImageDigest: {Repository: "team/api", Tag: "v2", Digest: "sha256:" + testImageDigest},
+	} {
+		imageReference, err := ParseImageReference(image)
+		if err != nil || imageReference != expected {
+			t.Errorf("expected %q to be parsed as %+v, got %+v, %v", image, expected, imageReference, err)
+		}
+	}
+
+	for _, image := range []string{"", "Team/API", "team/api@sha256:short", "team//api"} {
+		if _, err := ParseImageReference(image); err == nil {
+			t.Errorf("expected %q to be rejected", image)
+		}
+	}
+}
+
+func TestPinnedImage(t *testing.T) {
+	if pinnedImage := PinnedImage("team/api:v2", "sha256:"+testImageDigest); pinnedImage != "team/api:v2@sha256:"+testImageDigest {
+		t.Errorf("expected the digest to be appended, got %s", pinnedImage)
+	}
+	if pinnedImage := PinnedImage("team/api:v2@sha256:old", "sha256:"+testImageDigest); pinnedImage != "team/api:v2@sha256:"+testImageDigest {
+		t.Errorf("expected the previous digest to be replaced, got %s", pinnedImage)
+	}
+}
+
+func TestImageManifest_SupportsOneOf(t *testing.T) {
+	imageManifest := ImageManifest{Platforms: []ImagePlatform{{OS: "linux", Architecture: "arm64"}}}
+	if imageManifest.SupportsOneOf([]string{"amd64"}) {
+		t.Error("expected an arm64 image not to run on amd64 nodes")
+	}
+	if !imageManifest.SupportsOneOf([]string{"amd64", "arm64"}) {
+		t.Error("expected an arm64 image to run on a cluster with arm64 nodes")
+	}
+	if !(ImageManifest{}).SupportsOneOf([]string{"amd64"}) {
+		t.Error("expected an image with unknown platforms to be accepted")
+	}
+}
+
+const testImageDigest = "4b2a7c0e5d9f1a3b6c8e0d2f4a6b8c0e1f3a5b7c9d0e2f4a6b8c0d1e3f5a7b9c"
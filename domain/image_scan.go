@@ -0,0 +1,173 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// VulnerabilitySeverity is the severity of a vulnerability, as reported by Trivy
+type VulnerabilitySeverity string
+
+const (
+	SeverityUnknown  VulnerabilitySeverity = "UNKNOWN"
+	SeverityLow      VulnerabilitySeverity = "LOW"
+	SeverityMedium   VulnerabilitySeverity = "MEDIUM"
+	SeverityHigh     VulnerabilitySeverity = "HIGH"
+	SeverityCritical VulnerabilitySeverity = "CRITICAL"
+)
+
+// vulnerabilitySeverities are the severities from the least to the most severe
+var vulnerabilitySeverities = []VulnerabilitySeverity{SeverityUnknown, SeverityLow, SeverityMedium, SeverityHigh, SeverityCritical}
+
+// Rank returns the position of the severity from the least severe, -1 if the severity is not valid
+func (severity VulnerabilitySeverity) Rank() int {
+	for rank, vulnerabilitySeverity := range vulnerabilitySeverities {
+		if vulnerabilitySeverity == severity {
+			return rank
+		}
+	}
+	return -1
+}
+
+// IsValid returns true if the severity is one of the severities reported by Trivy
+func (severity VulnerabilitySeverity) IsValid() bool {
+	return severity.Rank() >= 0
+}
+
+// ImageScanPolicy tells what happens to the deploys of a namespace whose image has vulnerabilities above the severity it allows
+type ImageScanPolicy string
+
+const (
+	// ImageScanPolicyOff does not scan the images
+	ImageScanPolicyOff ImageScanPolicy = "off"
+	// ImageScanPolicyWarn deploys the image and warns about its vulnerabilities
+	ImageScanPolicyWarn ImageScanPolicy = "warn"
+	// ImageScanPolicyBlock rejects the deploy
+	ImageScanPolicyBlock ImageScanPolicy = "block"
+)
+
+// IsValid returns true if the policy is off, warn or block
+func (policy ImageScanPolicy) IsValid() bool {
+	return policy == ImageScanPolicyOff || policy == ImageScanPolicyWarn || policy == ImageScanPolicyBlock
+}
+
+// ImageVulnerability is a vulnerability found in a package of an image
+type ImageVulnerability struct {
+	ID               string                `json:"id"`
+	PackageName      string                `json:"packageName"`
+	InstalledVersion string                `json:"installedVersion"`
+	FixedVersion     string                `json:"fixedVersion,omitempty"`
+	Severity         VulnerabilitySeverity `json:"severity"`
+	Title            string                `json:"title,omitempty"`
+}
+
+// ImageVulnerabilities is a slice of ImageVulnerability stored as JSON
+type ImageVulnerabilities []ImageVulnerability
+
+func (imageVulnerabilities *ImageVulnerabilities) Scan(value interface{}) error {
+	bytes, ok := value.([]byte)
+	if !ok {
+		return fmt.Errorf("failed to unmarshal JSONB value: %v", value)
+	}
+
+	return json.Unmarshal(bytes, &imageVulnerabilities)
+}
+
+func (imageVulnerabilities *ImageVulnerabilities) Value() (driver.Value, error) {
+	return json.Marshal(imageVulnerabilities)
+}
+
+// ImageScan is the result of the scan of an image. Scans are stored per image digest,
+// so an image deployed by several applications or deployed again is scanned once.
+type ImageScan struct {
+	ID              string                `json:"id" gorm:"primaryKey"`
+	ImageDigest     string                `json:"imageDigest" gorm:"size:100;uniqueIndex:idx_image_scan_digest;not null"`
+	Image           string                `json:"image" gorm:"size:1000;not null"` // The image scanned first with this digest
+	Vulnerabilities *ImageVulnerabilities `json:"vulnerabilities" gorm:"type:json"`
+	// ScannedAt is when the vulnerabilities were last looked for, the vulnerability database keeps growing after that
+	ScannedAt time.Time `json:"scannedAt"`
+	CreatedAt time.Time `json:"createdAt" gorm:"autoCreateTime"`
+}
+
+// IsOutdated returns true if the image was scanned more than maxAge ago and must be scanned again
+func (imageScan ImageScan) IsOutdated(maxAge time.Duration, now time.Time) bool {
+	return now.Sub(imageScan.ScannedAt) > maxAge
+}
+
+// VulnerabilitiesAbove returns the vulnerabilities more severe than the severity
+func (imageScan ImageScan) VulnerabilitiesAbove(severity VulnerabilitySeverity) []ImageVulnerability {
+	vulnerabilities := []ImageVulnerability{}
+	if imageScan.Vulnerabilities == nil {
+		return vulnerabilities
+	}
+	for _, vulnerability := range *imageScan.Vulnerabilities {
+		if vulnerability.Severity.Rank() > severity.Rank() {
+			vulnerabilities = append(vulnerabilities, vulnerability)
+		}
+	}
+	return vulnerabilities
+}
+
+// ImageScanReport is the scan of the image of an application, judged against the image scan policy of its namespace
+type ImageScanReport struct {
+	ApplicationID string                `json:"applicationId"`
+	Image         string                `json:"image"`
+	ImageDigest   string                `json:"imageDigest"`
+	Policy        ImageScanPolicy       `json:"policy"`
+	MaxSeverity   VulnerabilitySeverity `json:"maxSeverity"`
+	// ScannedAt is nil when the image has not been scanned
+	ScannedAt       *time.Time           `json:"scannedAt"`
+	Vulnerabilities []ImageVulnerability `json:"vulnerabilities"`
+	// Violations are the vulnerabilities more severe than the namespace allows
+	Violations []ImageVulnerability `json:"violations"`
+}
+
+// NewImageScanReport builds the report of the image of an application, imageScan is nil when the image has not been scanned
+func NewImageScanReport(namespace Namespace, application Application, imageScan *ImageScan) ImageScanReport {
+	imageScanReport := ImageScanReport{
+		ApplicationID:   application.ID,
+		Image:           application.Image,
+		ImageDigest:     application.ImageDigest,
+		Policy:          namespace.ImageScanPolicy,
+		MaxSeverity:     namespace.MaxImageVulnerabilitySeverity,
+		Vulnerabilities: []ImageVulnerability{},
+		Violations:      []ImageVulnerability{},
+	}
+	if imageScan == nil {
+		return imageScanReport
+	}
+	imageScanReport.ScannedAt = &imageScan.CreatedAt
+	if imageScan.Vulnerabilities != nil {
+		imageScanReport.Vulnerabilities = *imageScan.Vulnerabilities
+	}
+	imageScanReport.Violations = namespace.ImageScanViolations(*imageScan)
+	return imageScanReport
+}
+
+// Warning returns the warning to give when the image is deployed despite its violations, or an empty string if it has none
+func (imageScanReport ImageScanReport) Warning() string {
+	if len(imageScanReport.Violations) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("image %s has %d vulnerabilities more severe than %s: %s",
+		imageScanReport.Image, len(imageScanReport.Violations), imageScanReport.MaxSeverity, VulnerabilityIDs(imageScanReport.Violations))
+}
+
+// maxListedVulnerabilities is the number of vulnerabilities listed in warnings and errors, the report lists all of them
+const maxListedVulnerabilities = 10
+
+// VulnerabilityIDs returns the comma separated IDs of the first vulnerabilities
+func VulnerabilityIDs(vulnerabilities []ImageVulnerability) string {
+	ids := []string{}
+	for i, vulnerability := range vulnerabilities {
+		if i == maxListedVulnerabilities {
+			ids = append(ids, fmt.Sprintf("and %d more", len(vulnerabilities)-maxListedVulnerabilities))
+			break
+		}
+		ids = append(ids, vulnerability.ID)
+	}
+	return strings.Join(ids, ", ")
+}
@@ -0,0 +1,44 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNamespace_ImageScanViolations(t *testing.T) {
+	imageScan := ImageScan{Vulnerabilities: &ImageVulnerabilities{
+		{ID: "CVE-1", Severity: SeverityLow},
+		{ID: "CVE-2", Severity: SeverityHigh},
+		{ID: "CVE-3", Severity: SeverityCritical},
+	}}
+
+	namespace := Namespace{ImageScanPolicy: ImageScanPolicyBlock, MaxImageVulnerabilitySeverity: SeverityMedium}
+	if violations := namespace.ImageScanViolations(imageScan); len(violations) != 2 || violations[0].ID != "CVE-2" || violations[1].ID != "CVE-3" {
+		t.Errorf("expected the high and critical vulnerabilities, got %v", violations)
+	}
+	namespace.MaxImageVulnerabilitySeverity = SeverityCritical
+	if violations := namespace.ImageScanViolations(imageScan); len(violations) != 0 {
+		t.Errorf("expected every vulnerability to be allowed, got %v", violations)
+	}
+	namespace = Namespace{ImageScanPolicy: ImageScanPolicyOff, MaxImageVulnerabilitySeverity: SeverityLow}
+	if violations := namespace.ImageScanViolations(imageScan); len(violations) != 0 {
+		t.Errorf("expected no violation when the policy is off, got %v", violations)
+	}
+}
+
+func TestImageScanReport_Warning(t *testing.T) {
+	vulnerabilities := ImageVulnerabilities{}
+	for i := 0; i < 12; i++ {
+		vulnerabilities = append(vulnerabilities, ImageVulnerability{ID: "CVE-" + strings.Repeat("1", i+1), Severity: SeverityCritical})
+	}
+	namespace := Namespace{ImageScanPolicy: ImageScanPolicyWarn, MaxImageVulnerabilitySeverity: SeverityHigh}
+	application := Application{ID: "api-id", Image: "team/api:v2", ImageDigest: "sha256:api"}
+
+	imageScanReport := NewImageScanReport(namespace, application, &ImageScan{Vulnerabilities: &vulnerabilities})
+	if warning := imageScanReport.Warning(); !strings.Contains(warning, "12 vulnerabilities more severe than HIGH") || !strings.HasSuffix(warning, "and 2 more") {
+		t.Errorf("expected the warning to count the violations and list the first ones, got %q", warning)
+	}
+	if warning := NewImageScanReport(namespace, application, nil).Warning(); warning != "" {
+		t.Errorf("expected no warning for an image that was not scanned, got %q", warning)
+	}
+}
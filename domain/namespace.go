@@ -3,8 +3,13 @@ package domain
 import (
 	"gorm.io/gorm"
 	"time"
+
+	customErrors "cloud-app-hive/controllers/errors"
 )
 
+// NamespaceOwnershipTransferValidity is how long the new owner has to accept an ownership transfer
+const NamespaceOwnershipTransferValidity = 7 * 24 * time.Hour
+
 // Namespace is a struct that represents a user's namespace
 type Namespace struct {
 	ID           string                `json:"id" gorm:"primaryKey"`
@@ -13,7 +18,75 @@ type Namespace struct {
 	UserID       string                `json:"userId" gorm:"index:idx_user_id;not null"`
 	Memberships  []NamespaceMembership `json:"memberships" gorm:"foreignKey:NamespaceID;references:ID;not null"`
 	Applications []Application         `json:"applications" gorm:"foreignKey:NamespaceID;references:ID;not null"`
-	UpdatedAt    time.Time             `json:"updatedAt" gorm:"autoUpdateTime"`
-	CreatedAt    time.Time             `json:"createdAt" gorm:"autoCreateTime"`
-	DeletedAt    *gorm.DeletedAt       `json:"deletedAt" gorm:"index;default:null"`
+	// ImageScanPolicy tells whether deploying an image with vulnerabilities more severe than MaxImageVulnerabilitySeverity is blocked or warned about
+	ImageScanPolicy               ImageScanPolicy       `json:"imageScanPolicy" gorm:"size:10;default:'warn'"`
+	MaxImageVulnerabilitySeverity VulnerabilitySeverity `json:"maxImageVulnerabilitySeverity" gorm:"size:10;default:'HIGH'"`
+	// PendingOwnerID is the member the owner transfers the namespace to, until the member accepts it
+	PendingOwnerID             *string         `json:"pendingOwnerId" gorm:"size:255"`
+	OwnershipTransferExpiresAt *time.Time      `json:"ownershipTransferExpiresAt"`
+	UpdatedAt                  time.Time       `json:"updatedAt" gorm:"autoUpdateTime"`
+	CreatedAt                  time.Time       `json:"createdAt" gorm:"autoCreateTime"`
+	DeletedAt                  *gorm.DeletedAt `json:"deletedAt" gorm:"index;default:null"`
+}
+
+// HasPendingOwnershipTransferTo returns true if the namespace is being transferred to the user and the transfer has not expired
+func (namespace Namespace) HasPendingOwnershipTransferTo(userID string, now time.Time) bool {
+	return namespace.PendingOwnerID != nil && *namespace.PendingOwnerID == userID &&
+		namespace.OwnershipTransferExpiresAt != nil && now.Before(*namespace.OwnershipTransferExpiresAt)
+}
+
+// FindApplicationByID returns the application of the namespace with the ID, or nil if the namespace has no such application
+func (namespace Namespace) FindApplicationByID(applicationID string) *Application {
+	for i := range namespace.Applications {
+		if namespace.Applications[i].ID == applicationID {
+			return &namespace.Applications[i]
+		}
+	}
+	return nil
+}
+
+// ApplicationsUsingRegistryCredential returns the applications of the namespace pulling their image with the registry credential
+func (namespace Namespace) ApplicationsUsingRegistryCredential(registryCredentialID string) []Application {
+	applications := []Application{}
+	for _, application := range namespace.Applications {
+		if application.RegistryCredentialID != nil && *application.RegistryCredentialID == registryCredentialID {
+			applications = append(applications, application)
+		}
+	}
+	return applications
+}
+
+// RequiresPinnedImages returns true if the images of the namespace must be deployed by their digest,
+// so that the pods run the image that was scanned rather than what its tag points to when they pull it
+func (namespace Namespace) RequiresPinnedImages() bool {
+	return namespace.ImageScanPolicy == ImageScanPolicyBlock
+}
+
+// ImageScanViolations returns the vulnerabilities of the image more severe than the namespace allows,
+// none when the namespace does not scan its images
+func (namespace Namespace) ImageScanViolations(imageScan ImageScan) []ImageVulnerability {
+	if namespace.ImageScanPolicy != ImageScanPolicyWarn && namespace.ImageScanPolicy != ImageScanPolicyBlock {
+		return []ImageVulnerability{}
+	}
+	return imageScan.VulnerabilitiesAbove(namespace.MaxImageVulnerabilitySeverity)
+}
+
+// ValidateMembershipChange returns an error if giving the role to the member, or removing the member when role is nil,
+// would remove the owner of the namespace or leave the namespace without any owner or admin
+func (namespace Namespace) ValidateMembershipChange(userID string, role *Role) error {
+	if userID == namespace.UserID {
+		return customErrors.NewNamespaceMembershipConflictError(namespace.ID, userID, "the owner of the namespace cannot be removed nor change role, the ownership must be transferred first")
+	}
+	if role != nil && (*role == RoleOwner || *role == RoleAdmin) {
+		return nil
+	}
+	for _, membership := range namespace.Memberships {
+		if membership.UserID == userID {
+			continue
+		}
+		if otherRole, _ := namespace.RoleOf(membership.UserID); otherRole == RoleOwner || otherRole == RoleAdmin {
+			return nil
+		}
+	}
+	return customErrors.NewNamespaceMembershipConflictError(namespace.ID, userID, "the namespace must keep at least one owner or admin")
 }
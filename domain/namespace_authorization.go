@@ -0,0 +1,141 @@
+package domain
+
+import (
+	customErrors "cloud-app-hive/controllers/errors"
+)
+
+// Permission is an action that a member can perform in a namespace
+type Permission string
+
+const (
+	PermissionReadNamespace          Permission = "namespace:read"
+	PermissionUpdateNamespace        Permission = "namespace:update"
+	PermissionDeleteNamespace        Permission = "namespace:delete"
+	PermissionManageNamespaceMembers Permission = "namespace:members:manage"
+	PermissionManageNamespaceAdmins  Permission = "namespace:admins:manage"
+	PermissionTransferNamespace      Permission = "namespace:ownership:transfer"
+	PermissionApplyNamespaceManifest Permission = "namespace:manifest:apply"
+	PermissionReadNamespaceAudit     Permission = "namespace:audit:read"
+	PermissionManageNetworkRules     Permission = "namespace:network:manage"
+	PermissionManageRegistries       Permission = "namespace:registries:manage"
+	PermissionManageImageScanPolicy  Permission = "namespace:image-scan-policy:manage"
+	PermissionManageAddons           Permission = "namespace:addons:manage"
+	PermissionManageConfigGroups     Permission = "namespace:config-groups:manage"
+	PermissionManageWebhooks         Permission = "namespace:webhooks:manage"
+	PermissionReadApplication        Permission = "application:read"
+	PermissionCreateApplication      Permission = "application:create"
+	PermissionUpdateApplication      Permission = "application:update"
+	PermissionDeleteApplication      Permission = "application:delete"
+	PermissionScaleApplication       Permission = "application:scale"
+	PermissionReadApplicationLogs    Permission = "application:logs:read"
+	PermissionReadApplicationMetrics Permission = "application:metrics:read"
+	PermissionReadApplicationStatus  Permission = "application:status:read"
+	PermissionReadApplicationSecrets Permission = "application:secrets:read"
+)
+
+var viewerPermissions = []Permission{
+	PermissionReadNamespace,
+	PermissionReadApplication,
+	PermissionReadApplicationMetrics,
+	PermissionReadApplicationStatus,
+}
+
+// memberPermissions are the permissions members had before roles were introduced
+var memberPermissions = append([]Permission{
+	PermissionCreateApplication,
+	PermissionScaleApplication,
+	PermissionReadApplicationLogs,
+	PermissionReadApplicationSecrets,
+}, viewerPermissions...)
+
+var developerPermissions = append([]Permission{
+	PermissionUpdateApplication,
+	PermissionDeleteApplication,
+}, memberPermissions...)
+
+var adminPermissions = append([]Permission{
+	PermissionUpdateNamespace,
+	PermissionDeleteNamespace,
+	PermissionManageNamespaceMembers,
+	PermissionApplyNamespaceManifest,
+	PermissionReadNamespaceAudit,
+	PermissionManageNetworkRules,
+	PermissionManageRegistries,
+	PermissionManageImageScanPolicy,
+	PermissionManageAddons,
+	PermissionManageConfigGroups,
+	PermissionManageWebhooks,
+}, developerPermissions...)
+
+var ownerPermissions = append([]Permission{
+	PermissionManageNamespaceAdmins,
+	PermissionTransferNamespace,
+}, adminPermissions...)
+
+// rolePermissions is the permission matrix of the namespace roles
+var rolePermissions = map[Role][]Permission{
+	RoleOwner:     ownerPermissions,
+	RoleAdmin:     adminPermissions,
+	RoleDeveloper: developerPermissions,
+	RoleMember:    memberPermissions,
+	RoleViewer:    viewerPermissions,
+}
+
+// Can returns true if the role grants the permission
+func (role Role) Can(permission Permission) bool {
+	for _, rolePermission := range rolePermissions[role] {
+		if rolePermission == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// RoleOf returns the role of a user in the namespace and false if the user is not a member.
+// The creator of the namespace is always its owner, even if the membership was created with the admin role.
+func (namespace Namespace) RoleOf(userID string) (Role, bool) {
+	for _, membership := range namespace.Memberships {
+		if membership.UserID != userID {
+			continue
+		}
+		if namespace.UserID == userID {
+			return RoleOwner, true
+		}
+		return membership.Role, true
+	}
+	return "", false
+}
+
+// Authorize returns an error if the user cannot perform the action in the namespace
+func (namespace Namespace) Authorize(userID string, permission Permission) error {
+	role, isMember := namespace.RoleOf(userID)
+	if !isMember {
+		return customErrors.NewUnauthorizedToAccessNamespaceError(namespace.ID, namespace.Name, userID)
+	}
+	if !role.Can(permission) {
+		return customErrors.NewNamespacePermissionDeniedError(namespace.ID, namespace.Name, userID, string(role), string(permission))
+	}
+	return nil
+}
+
+// AuthorizeOwnershipTransfer returns an error if the user is not the owner of the namespace.
+// Members given the owner role share its permissions, but only the owner can transfer the namespace.
+func (namespace Namespace) AuthorizeOwnershipTransfer(userID string) error {
+	if err := namespace.Authorize(userID, PermissionTransferNamespace); err != nil {
+		return err
+	}
+	if userID != namespace.UserID {
+		return customErrors.NewNamespacePermissionDeniedError(namespace.ID, namespace.Name, userID, string(RoleOwner), string(PermissionTransferNamespace))
+	}
+	return nil
+}
+
+// Authorize returns an error if the user cannot perform the action on the application.
+// Members who created the application can update and delete it as long as they can still create applications.
+func (application Application) Authorize(userID string, permission Permission) error {
+	isUpdateOrDelete := permission == PermissionUpdateApplication || permission == PermissionDeleteApplication
+	if isUpdateOrDelete && application.UserID == userID && application.Namespace.Authorize(userID, PermissionCreateApplication) == nil {
+		return nil
+	}
+	return application.Namespace.Authorize(userID, permission)
+}
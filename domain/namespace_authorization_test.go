@@ -0,0 +1,105 @@
+package domain
+
+import (
+	"testing"
+
+	customErrors "cloud-app-hive/controllers/errors"
+)
+
+func newTestNamespaceWithRoles() Namespace {
+	return Namespace{
+		ID:     "namespace-id",
+		Name:   "team",
+		UserID: "creator",
+		Memberships: []NamespaceMembership{
+			{UserID: "creator", Role: RoleAdmin},
+			{UserID: "admin", Role: RoleAdmin},
+			{UserID: "developer", Role: RoleDeveloper},
+			{UserID: "member", Role: RoleMember},
+			{UserID: "viewer", Role: RoleViewer},
+		},
+	}
+}
+
+func TestNamespace_Authorize(t *testing.T) {
+	namespace := newTestNamespaceWithRoles()
+	testCases := []struct {
+		userID     string
+		permission Permission
+		allowed    bool
+	}{
+		{"creator", PermissionManageNamespaceAdmins, true},
+		{"admin", PermissionManageNamespaceAdmins, false},
+		{"admin", PermissionApplyNamespaceManifest, true},
+		{"developer", PermissionDeleteApplication, true},
+		{"developer", PermissionManageNamespaceMembers, false},
+		{"member", PermissionReadApplicationLogs, true},
+		{"member", PermissionUpdateApplication, false},
+		{"viewer", PermissionReadApplicationStatus, true},
+		{"viewer", PermissionReadApplicationSecrets, false},
+		{"viewer", PermissionScaleApplication, false},
+	}
+	for _, testCase := range testCases {
+		err := namespace.Authorize(testCase.userID, testCase.permission)
+		if testCase.allowed && err != nil {
+			t.Errorf("expected %s to be allowed %s, got %v", testCase.userID, testCase.permission, err)
+		}
+		if !testCase.allowed {
+			if _, ok := err.(*customErrors.NamespacePermissionDeniedError); !ok {
+				t.Errorf("expected %s to be denied %s, got %v", testCase.userID, testCase.permission, err)
+			}
+		}
+	}
+
+	if _, ok := namespace.Authorize("stranger", PermissionReadNamespace).(*customErrors.UnauthorizedToAccessNamespaceError); !ok {
+		t.Error("expected a user outside the namespace to be unauthorized")
+	}
+}
+
+func TestApplication_AuthorizeLetsMembersChangeTheirApplications(t *testing.T) {
+	application := Application{UserID: "member", Namespace: newTestNamespaceWithRoles()}
+	if err := application.Authorize("member", PermissionUpdateApplication); err != nil {
+		t.Errorf("expected the member to update their application, got %v", err)
+	}
+
+	application.UserID = "viewer"
+	if err := application.Authorize("viewer", PermissionDeleteApplication); err == nil {
+		t.Error("expected a viewer not to delete an application, even one they created")
+	}
+}
+
+func TestNamespace_ValidateMembershipChangeKeepsAnOwnerOrAdmin(t *testing.T) {
+	namespace := newTestNamespaceWithRoles()
+	developer := RoleDeveloper
+	if _, ok := namespace.ValidateMembershipChange("creator", nil).(*customErrors.NamespaceMembershipConflictError); !ok {
+		t.Error("expected the owner not to be removable")
+	}
+	if err := namespace.ValidateMembershipChange("admin", &developer); err != nil {
+		t.Errorf("expected the admin to be demoted while the owner stays, got %v", err)
+	}
+
+	namespace.UserID = "former-owner"
+	if _, ok := namespace.ValidateMembershipChange("admin", nil).(*customErrors.NamespaceMembershipConflictError); ok {
+		t.Error("expected the admin to be removable while the creator membership is still an admin")
+	}
+	namespace.Memberships = namespace.Memberships[1:]
+	if _, ok := namespace.ValidateMembershipChange("admin", &developer).(*customErrors.NamespaceMembershipConflictError); !ok {
+		t.Error("expected the last admin not to be demoted")
+	}
+	if err := namespace.ValidateMembershipChange("developer", nil); err != nil {
+		t.Errorf("expected a developer to be removable, got %v", err)
+	}
+}
+
+func TestNamespace_AuthorizeOwnershipTransferIsOwnerOnly(t *testing.T) {
+	namespace := newTestNamespaceWithRoles()
+	namespace.Memberships = append(namespace.Memberships, NamespaceMembership{UserID: "co-owner", Role: RoleOwner})
+	if err := namespace.AuthorizeOwnershipTransfer("creator"); err != nil {
+		t.Errorf("expected the owner to transfer the namespace, got %v", err)
+	}
+	for _, userID := range []string{"co-owner", "admin"} {
+		if _, ok := namespace.AuthorizeOwnershipTransfer(userID).(*customErrors.NamespacePermissionDeniedError); !ok {
+			t.Errorf("expected %s not to transfer the namespace", userID)
+		}
+	}
+}
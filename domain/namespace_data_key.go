@@ -0,0 +1,24 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+// EncryptedSecretValuePrefix starts the value of the secrets encrypted at rest, followed by the data key ID and the ciphertext
+const EncryptedSecretValuePrefix = "enc:v1:"
+
+// NamespaceDataKey is a struct that represents the key encrypting the secrets of the applications of a namespace.
+// It is only stored wrapped by a master key, the master keys never reach the database.
+type NamespaceDataKey struct {
+	ID          string    `json:"id" gorm:"primaryKey"`
+	NamespaceID string    `json:"namespaceId" gorm:"size:100;index;not null"`
+	MasterKeyID string    `json:"masterKeyId" gorm:"size:100;not null"`
+	WrappedKey  []byte    `json:"-" gorm:"not null"`
+	CreatedAt   time.Time `json:"createdAt" gorm:"autoCreateTime;not null"`
+}
+
+// IsEncrypted returns true if the value of the secret is encrypted at rest
+func (applicationSecret ApplicationSecret) IsEncrypted() bool {
+	return strings.HasPrefix(applicationSecret.Val, EncryptedSecretValuePrefix)
+}
@@ -0,0 +1,87 @@
+package domain
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// NamespaceInvitationTokenPrefix starts every invitation token, it tells them apart from access token secrets
+const NamespaceInvitationTokenPrefix = "cahinv_"
+
+// NamespaceInvitationValidity is how long an invitation can be accepted after it was sent
+const NamespaceInvitationValidity = 7 * 24 * time.Hour
+
+type NamespaceInvitationStatus string
+
+const (
+	NamespaceInvitationPending  NamespaceInvitationStatus = "PENDING"
+	NamespaceInvitationAccepted NamespaceInvitationStatus = "ACCEPTED"
+	NamespaceInvitationDeclined NamespaceInvitationStatus = "DECLINED"
+	NamespaceInvitationRevoked  NamespaceInvitationStatus = "REVOKED"
+	// NamespaceInvitationExpired is never stored, it is the status of a pending invitation past its expiry
+	NamespaceInvitationExpired NamespaceInvitationStatus = "EXPIRED"
+)
+
+// NamespaceInvitation is a struct that represents an invitation sent by email to join a namespace with a role.
+// The membership is only created when the invitee accepts it, only the hash of the token sent by email is stored.
+type NamespaceInvitation struct {
+	ID          string                    `json:"id" gorm:"primaryKey"`
+	NamespaceID string                    `json:"namespaceId" gorm:"size:100;index:idx_namespace_invitation_namespace_id;not null"`
+	Email       string                    `json:"email" gorm:"size:255;not null"`
+	Role        Role                      `json:"role" gorm:"enum:OWNER,ADMIN,DEVELOPER,MEMBER,VIEWER;not null"`
+	TokenHash   string                    `json:"-" gorm:"size:64;uniqueIndex;not null"`
+	InvitedBy   string                    `json:"invitedBy" gorm:"not null"`
+	Status      NamespaceInvitationStatus `json:"status" gorm:"size:20;not null"`
+	ExpiresAt   time.Time                 `json:"expiresAt" gorm:"not null"`
+	RespondedBy *string                   `json:"respondedBy"` // The user who accepted or declined the invitation
+	RespondedAt *time.Time                `json:"respondedAt"`
+	UpdatedAt   time.Time                 `json:"updatedAt" gorm:"autoUpdateTime;not null"`
+	CreatedAt   time.Time                 `json:"createdAt" gorm:"autoCreateTime;not null"`
+	DeletedAt   *gorm.DeletedAt           `json:"deletedAt" gorm:"index;default:null"`
+}
+
+// NewNamespaceInvitationToken returns a random invitation token
+func NewNamespaceInvitationToken() (string, error) {
+	randomBytes := make([]byte, 32)
+	if _, err := rand.Read(randomBytes); err != nil {
+		return "", fmt.Errorf("error generating namespace invitation token: %w", err)
+	}
+	return NamespaceInvitationTokenPrefix + base64.RawURLEncoding.EncodeToString(randomBytes), nil
+}
+
+// HashNamespaceInvitationToken returns the hash stored in place of the token
+func HashNamespaceInvitationToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
+
+// NormalizeInvitationEmail returns the email compared between invitations and users
+func NormalizeInvitationEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// CurrentStatus returns the status of the invitation, pending invitations past their expiry are expired
+func (invitation NamespaceInvitation) CurrentStatus(now time.Time) NamespaceInvitationStatus {
+	if invitation.Status == NamespaceInvitationPending && !now.Before(invitation.ExpiresAt) {
+		return NamespaceInvitationExpired
+	}
+	return invitation.Status
+}
+
+// IsPending returns true if the invitation can still be accepted, declined or revoked
+func (invitation NamespaceInvitation) IsPending(now time.Time) bool {
+	return invitation.CurrentStatus(now) == NamespaceInvitationPending
+}
+
+// IsFor returns true if the invitation was sent to the email, an empty email matches no invitation
+func (invitation NamespaceInvitation) IsFor(email string) bool {
+	normalizedEmail := NormalizeInvitationEmail(email)
+	return normalizedEmail != "" && NormalizeInvitationEmail(invitation.Email) == normalizedEmail
+}
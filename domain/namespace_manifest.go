@@ -0,0 +1,245 @@
+package domain
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+
+	"cloud-app-hive/controllers/errors"
+	"cloud-app-hive/validators"
+)
+
+const (
+	// NamespaceManifestAPIVersion is the only manifest format version currently supported
+	NamespaceManifestAPIVersion = "cloud-app-hive/v1"
+	// NamespaceManifestKind is the kind every namespace manifest must declare
+	NamespaceManifestKind = "NamespaceManifest"
+)
+
+// NamespaceManifest is a struct that represents the declarative description of a whole namespace.
+// It is read and written both as YAML and JSON, so only json tags are used.
+// swagger:model NamespaceManifest
+type NamespaceManifest struct {
+	APIVersion   string                    `json:"apiVersion"`
+	Kind         string                    `json:"kind"`
+	Metadata     NamespaceManifestMetadata `json:"metadata"`
+	Applications []ApplicationManifest     `json:"applications"`
+}
+
+// NamespaceManifestMetadata is a struct that identifies the namespace described by a manifest
+type NamespaceManifestMetadata struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+// ApplicationManifest is a struct that represents an application inside a namespace manifest.
+// Applications are identified by their name, which cannot change once created.
+type ApplicationManifest struct {
+	Name        string        `json:"name"`
+	Description string        `json:"description,omitempty"`
+	Image       string        `json:"image"`
+	Registry    ImageRegistry `json:"registry"`
+	// RegistryCredential is the name of the registry credential of the namespace pulling the image from the custom registry
+	RegistryCredential        string                               `json:"registryCredential,omitempty"`
+	PinImageDigest            bool                                 `json:"pinImageDigest,omitempty"`
+	Port                      uint32                               `json:"port"`
+	Zone                      string                               `json:"zone,omitempty"`
+	ApplicationType           ApplicationType                      `json:"applicationType"`
+	EnvironmentVariables      ApplicationEnvironmentVariables      `json:"environmentVariables,omitempty"`
+	Secrets                   []ApplicationManifestSecret          `json:"secrets,omitempty"`
+	ContainerSpecifications   ApplicationContainerSpecifications   `json:"containerSpecifications"`
+	ScalabilitySpecifications ApplicationScalabilitySpecifications `json:"scalabilitySpecifications"`
+	// Ingress is exported without the basic auth password, a basic auth without password keeps the stored one
+	Ingress *ApplicationIngress `json:"ingress,omitempty"`
+	// Dependencies are the names of the applications of the namespace the application calls
+	Dependencies       []string `json:"dependencies,omitempty"`
+	AdministratorEmail string   `json:"administratorEmail"`
+}
+
+// ApplicationManifestSecret is a struct that represents a reference to an application secret.
+// Exported manifests never contain secret values: a secret without value nor reference keeps the value already stored.
+type ApplicationManifestSecret struct {
+	Name      string `json:"name"`
+	Value     string `json:"value,omitempty"`
+	Reference string `json:"reference,omitempty"`
+}
+
+// NamespaceManifestApplyResult is a struct that represents what was done to match a manifest
+type NamespaceManifestApplyResult struct {
+	Created   []string `json:"created"`
+	Updated   []string `json:"updated"`
+	Unchanged []string `json:"unchanged"`
+	Deleted   []string `json:"deleted"`
+	// Operations deploy the created and updated applications and remove the deleted ones in the background
+	Operations []Operation `json:"operations"`
+}
+
+const applicationManifestNameRegex = "^[a-zA-Z0-9_-]{3,50}$"
+
+// NewNamespaceManifest builds the manifest of a namespace from its stored applications
+func NewNamespaceManifest(namespace Namespace, applications []Application) NamespaceManifest {
+	manifest := NamespaceManifest{
+		APIVersion: NamespaceManifestAPIVersion,
+		Kind:       NamespaceManifestKind,
+		Metadata: NamespaceManifestMetadata{
+			Name:        namespace.Name,
+			Description: namespace.Description,
+		},
+		Applications: []ApplicationManifest{},
+	}
+
+	for _, application := range applications {
+		manifest.Applications = append(manifest.Applications, NewApplicationManifest(application))
+	}
+
+	return manifest
+}
+
+// NewApplicationManifest builds the manifest of a stored application, secrets are referenced by name only
+func NewApplicationManifest(application Application) ApplicationManifest {
+	applicationManifest := ApplicationManifest{
+		Name:               application.Name,
+		Description:        application.Description,
+		Image:              application.Image,
+		Registry:           application.Registry,
+		PinImageDigest:     application.PinImageDigest,
+		Port:               application.Port,
+		Zone:               application.Zone,
+		ApplicationType:    application.ApplicationType,
+		AdministratorEmail: application.AdministratorEmail,
+	}
+	if application.RegistryCredential != nil {
+		applicationManifest.RegistryCredential = application.RegistryCredential.Name
+	}
+	if application.EnvironmentVariables != nil {
+		applicationManifest.EnvironmentVariables = *application.EnvironmentVariables
+	}
+	if application.Secrets != nil {
+		for _, secret := range *application.Secrets {
+			applicationManifest.Secrets = append(applicationManifest.Secrets, ApplicationManifestSecret{Name: secret.Name, Reference: secret.Reference})
+		}
+	}
+	if application.ContainerSpecifications != nil {
+		applicationManifest.ContainerSpecifications = application.ContainerSpecifications.Data()
+	}
+	if application.ScalabilitySpecifications != nil {
+		applicationManifest.ScalabilitySpecifications = application.ScalabilitySpecifications.Data()
+	}
+	if application.Ingress != nil && !reflect.DeepEqual(*application.Ingress, ApplicationIngress{}) {
+		ingress := *application.Ingress
+		applicationManifest.Ingress = &ingress
+	}
+	if application.Dependencies != nil && len(*application.Dependencies) > 0 {
+		applicationManifest.Dependencies = application.Dependencies.Names()
+	}
+	return applicationManifest
+}
+
+// Validate checks that a manifest is well-formed before anything is applied
+func (manifest NamespaceManifest) Validate() error {
+	if manifest.APIVersion != NamespaceManifestAPIVersion {
+		return errors.NewInvalidNamespaceManifestError(
+			fmt.Sprintf("apiVersion must be %s - current value: %s", NamespaceManifestAPIVersion, manifest.APIVersion),
+		)
+	}
+	if manifest.Kind != NamespaceManifestKind {
+		return errors.NewInvalidNamespaceManifestError(
+			fmt.Sprintf("kind must be %s - current value: %s", NamespaceManifestKind, manifest.Kind),
+		)
+	}
+	if len(manifest.Applications) > MaxApplicationsByNamespace {
+		return errors.NewInvalidNamespaceManifestError(
+			fmt.Sprintf("a namespace cannot contain more than %d applications", MaxApplicationsByNamespace),
+		)
+	}
+
+	applicationNames := map[string]bool{}
+	for _, application := range manifest.Applications {
+		if err := application.Validate(); err != nil {
+			return err
+		}
+		if applicationNames[application.Name] {
+			return errors.NewInvalidNamespaceManifestError(
+				fmt.Sprintf("application %s is declared more than once", application.Name),
+			)
+		}
+		applicationNames[application.Name] = true
+	}
+
+	return nil
+}
+
+// Validate checks the fields of an application manifest with the same rules as the creation of an application
+func (applicationManifest ApplicationManifest) Validate() error {
+	if match, _ := regexp.MatchString(applicationManifestNameRegex, applicationManifest.Name); !match {
+		return errors.NewInvalidNamespaceManifestError(
+			fmt.Sprintf("application name '%s' must match the following regex: %s", applicationManifest.Name, applicationManifestNameRegex),
+		)
+	}
+	if applicationManifest.Image == "" {
+		return errors.NewInvalidNamespaceManifestError(
+			fmt.Sprintf("application %s: image must not be empty", applicationManifest.Name),
+		)
+	}
+	if applicationManifest.Registry != DockerHubRegistry && applicationManifest.Registry != PrivateRegistry && applicationManifest.Registry != CustomRegistry {
+		return errors.NewInvalidNamespaceManifestError(
+			fmt.Sprintf("application %s: registry must be one of %s, %s, %s", applicationManifest.Name, DockerHubRegistry, PrivateRegistry, CustomRegistry),
+		)
+	}
+	if (applicationManifest.Registry == CustomRegistry) != (applicationManifest.RegistryCredential != "") {
+		return errors.NewInvalidNamespaceManifestError(
+			fmt.Sprintf("application %s: registryCredential must be set if and only if registry is %s", applicationManifest.Name, CustomRegistry),
+		)
+	}
+	if applicationManifest.Port < 1 || applicationManifest.Port > 65535 {
+		return errors.NewInvalidNamespaceManifestError(
+			fmt.Sprintf("application %s: port must be between 1 and 65535", applicationManifest.Name),
+		)
+	}
+	if applicationManifest.ApplicationType != SingleInstance && applicationManifest.ApplicationType != LoadBalanced {
+		return errors.NewInvalidNamespaceManifestError(
+			fmt.Sprintf("application %s: applicationType must be one of %s, %s", applicationManifest.Name, SingleInstance, LoadBalanced),
+		)
+	}
+	if err := validators.ValidateEmail(applicationManifest.AdministratorEmail); err != nil {
+		return errors.NewInvalidNamespaceManifestError(
+			fmt.Sprintf("application %s: administratorEmail is invalid: %s", applicationManifest.Name, err.Error()),
+		)
+	}
+	if applicationManifest.ContainerSpecifications.CPULimit == nil || applicationManifest.ContainerSpecifications.MemoryLimit == nil {
+		return errors.NewInvalidNamespaceManifestError(
+			fmt.Sprintf("application %s: containerSpecifications must define cpuLimit and memoryLimit", applicationManifest.Name),
+		)
+	}
+
+	if err := applicationManifest.EnvironmentVariables.Validate(); err != nil {
+		return err
+	}
+	for _, secret := range applicationManifest.Secrets {
+		if !IsAValidSecretName(secret.Name) {
+			return errors.NewInvalidApplicationSecretsError("Name must not contain special characters, it must match the following regex: " + secretNameRegex)
+		}
+		if err := (ApplicationSecret{Name: secret.Name, Val: secret.Value, Reference: secret.Reference}).validateReference(); err != nil {
+			return err
+		}
+	}
+	if err := applicationManifest.ContainerSpecifications.Validate(); err != nil {
+		return err
+	}
+	if err := applicationManifest.ScalabilitySpecifications.Validate(); err != nil {
+		return err
+	}
+	if applicationManifest.Ingress != nil {
+		if err := applicationManifest.Ingress.Validate(); err != nil {
+			return errors.NewInvalidApplicationIngressError(fmt.Sprintf("application %s: %s", applicationManifest.Name, err.Error()))
+		}
+		if err := applicationManifest.Ingress.ValidatePorts(applicationManifest.Port); err != nil {
+			return errors.NewInvalidApplicationIngressError(fmt.Sprintf("application %s: %s", applicationManifest.Name, err.Error()))
+		}
+	}
+	if err := NewApplicationDependencies(applicationManifest.Dependencies).Validate(applicationManifest.Name); err != nil {
+		return errors.NewInvalidApplicationDependenciesError(fmt.Sprintf("application %s: %s", applicationManifest.Name, err.Error()))
+	}
+
+	return nil
+}
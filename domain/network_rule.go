@@ -0,0 +1,24 @@
+package domain
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// NetworkRule allows traffic to the applications of a namespace from another namespace, or from one of its applications.
+// Namespaces only accept traffic from their own applications and from the ingress controller otherwise.
+type NetworkRule struct {
+	ID          string `json:"id" gorm:"primaryKey"`
+	NamespaceID string `json:"namespaceId" gorm:"size:255;index:idx_network_rule_namespace_id;not null"`
+	// ApplicationID is the application receiving the traffic, every application of the namespace when nil
+	ApplicationID   *string `json:"applicationId" gorm:"size:255"`
+	FromNamespaceID string  `json:"fromNamespaceId" gorm:"size:255;not null"`
+	// FromApplicationID is the application sending the traffic, every application of FromNamespaceID when nil
+	FromApplicationID *string         `json:"fromApplicationId" gorm:"size:255"`
+	Description       string          `json:"description" gorm:"size:1000"`
+	CreatedBy         string          `json:"createdBy" gorm:"size:255;not null"`
+	UpdatedAt         time.Time       `json:"updatedAt" gorm:"autoUpdateTime"`
+	CreatedAt         time.Time       `json:"createdAt" gorm:"autoCreateTime"`
+	DeletedAt         *gorm.DeletedAt `json:"deletedAt" gorm:"index;default:null"`
+}
@@ -0,0 +1,214 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// OperationType is an enum that represents what an operation does to an application
+type OperationType string
+
+const (
+	DeployOperation OperationType = "deploy"
+	UpdateOperation OperationType = "update"
+	ScaleOperation  OperationType = "scale"
+	DeleteOperation OperationType = "delete"
+)
+
+// OperationStatus is an enum that represents the progress of an operation or of one of its steps
+type OperationStatus string
+
+const (
+	OperationPending   OperationStatus = "pending"
+	OperationRunning   OperationStatus = "running"
+	OperationSucceeded OperationStatus = "succeeded"
+	OperationFailed    OperationStatus = "failed"
+)
+
+// OperationStepName is an enum that represents a step of an operation on the cluster
+type OperationStepName string
+
+const (
+	NamespaceOperationStep  OperationStepName = "namespace"
+	SecretsOperationStep    OperationStepName = "secrets"
+	DeploymentOperationStep OperationStepName = "deployment"
+	ServiceOperationStep    OperationStepName = "service"
+	IngressOperationStep    OperationStepName = "ingress"
+	RolloutOperationStep    OperationStepName = "rollout"
+)
+
+// operationTypeSteps are the steps of each type of operation, in the order they run
+var operationTypeSteps = map[OperationType][]OperationStepName{
+	DeployOperation: {NamespaceOperationStep, SecretsOperationStep, DeploymentOperationStep, ServiceOperationStep, IngressOperationStep, RolloutOperationStep},
+	UpdateOperation: {NamespaceOperationStep, SecretsOperationStep, DeploymentOperationStep, ServiceOperationStep, IngressOperationStep, RolloutOperationStep},
+	ScaleOperation:  {NamespaceOperationStep, SecretsOperationStep, DeploymentOperationStep, ServiceOperationStep, IngressOperationStep, RolloutOperationStep},
+	DeleteOperation: {IngressOperationStep, ServiceOperationStep, DeploymentOperationStep, SecretsOperationStep},
+}
+
+// Steps returns the steps of the type of operation, in the order they run
+func (operationType OperationType) Steps() []OperationStepName {
+	return operationTypeSteps[operationType]
+}
+
+// WaitsForRollout tells if the operation only succeeds once the pods of the application are ready
+func (operationType OperationType) WaitsForRollout() bool {
+	for _, step := range operationType.Steps() {
+		if step == RolloutOperationStep {
+			return true
+		}
+	}
+	return false
+}
+
+// OperationStep is a struct that represents the progress of a step of an operation
+type OperationStep struct {
+	Name       OperationStepName `json:"name"`
+	Status     OperationStatus   `json:"status"`
+	StartedAt  *time.Time        `json:"startedAt"`
+	FinishedAt *time.Time        `json:"finishedAt"`
+	Error      string            `json:"error,omitempty"`
+}
+
+// OperationSteps is a slice of OperationStep
+// swagger:model OperationSteps
+type OperationSteps []OperationStep
+
+func (operationSteps *OperationSteps) Scan(value interface{}) error {
+	bytes, ok := value.([]byte)
+	if !ok {
+		return fmt.Errorf("failed to unmarshal JSONB value: %v", value)
+	}
+
+	return json.Unmarshal(bytes, &operationSteps)
+}
+
+func (operationSteps *OperationSteps) Value() (driver.Value, error) {
+	return json.Marshal(operationSteps)
+}
+
+// Operation is a struct that represents a deployment, an update, a scaling or a deletion of an application running on the cluster.
+// The API answers as soon as the operation is recorded, its steps are updated while it runs.
+type Operation struct {
+	ID              string          `json:"id" gorm:"primaryKey"`
+	Type            OperationType   `json:"type" gorm:"size:20;not null"`
+	Status          OperationStatus `json:"status" gorm:"size:20;not null"`
+	ApplicationID   string          `json:"applicationId" gorm:"size:255;index:idx_operation_application_id;not null"`
+	ApplicationName string          `json:"applicationName" gorm:"size:255;not null"`
+	NamespaceID     string          `json:"namespaceId" gorm:"size:255;not null"`
+	Steps           *OperationSteps `json:"steps" gorm:"type:json"`
+	// FailedStep and Error tell which step failed and why when the operation failed
+	FailedStep OperationStepName `json:"failedStep,omitempty" gorm:"size:20"`
+	Error      string            `json:"error,omitempty" gorm:"type:text"`
+	CreatedBy  string            `json:"createdBy" gorm:"size:255;not null"`
+	StartedAt  *time.Time        `json:"startedAt"`
+	FinishedAt *time.Time        `json:"finishedAt"`
+	UpdatedAt  time.Time         `json:"updatedAt" gorm:"autoUpdateTime"`
+	CreatedAt  time.Time         `json:"createdAt" gorm:"autoCreateTime"`
+}
+
+// NewOperation returns a pending operation on an application, with the steps of its type
+func NewOperation(operationType OperationType, application Application, createdBy string) Operation {
+	steps := make(OperationSteps, 0, len(operationType.Steps()))
+	for _, step := range operationType.Steps() {
+		steps = append(steps, OperationStep{Name: step, Status: OperationPending})
+	}
+	return Operation{
+		Type:            operationType,
+		Status:          OperationPending,
+		ApplicationID:   application.ID,
+		ApplicationName: application.Name,
+		NamespaceID:     application.NamespaceID,
+		Steps:           &steps,
+		CreatedBy:       createdBy,
+	}
+}
+
+// IsFinished tells if the operation succeeded or failed
+func (operation Operation) IsFinished() bool {
+	return operation.Status == OperationSucceeded || operation.Status == OperationFailed
+}
+
+// Start marks the operation as running
+func (operation *Operation) Start(now time.Time) {
+	operation.Status = OperationRunning
+	operation.StartedAt = &now
+}
+
+// StartStep marks a step of the operation as running
+func (operation *Operation) StartStep(name OperationStepName, now time.Time) {
+	operation.updateStep(name, func(step *OperationStep) {
+		step.Status = OperationRunning
+		step.StartedAt = &now
+	})
+}
+
+// FinishStep marks a step of the operation as succeeded, or as failed with its error
+func (operation *Operation) FinishStep(name OperationStepName, err error, now time.Time) {
+	operation.updateStep(name, func(step *OperationStep) {
+		if step.StartedAt == nil {
+			step.StartedAt = &now
+		}
+		step.FinishedAt = &now
+		step.Status = OperationSucceeded
+		if err != nil {
+			step.Status = OperationFailed
+			step.Error = err.Error()
+		}
+	})
+}
+
+// Finish marks the operation as succeeded, or as failed with its error.
+// A failed operation records the step that failed, or the step that was running when it failed.
+func (operation *Operation) Finish(err error, now time.Time) {
+	operation.FinishedAt = &now
+	if err == nil {
+		operation.Status = OperationSucceeded
+		return
+	}
+	operation.Status = OperationFailed
+	operation.Error = err.Error()
+	if operation.Steps == nil {
+		return
+	}
+	for index, step := range *operation.Steps {
+		if step.Status == OperationRunning {
+			(*operation.Steps)[index].Status = OperationFailed
+			(*operation.Steps)[index].FinishedAt = &now
+			(*operation.Steps)[index].Error = err.Error()
+		}
+		if (*operation.Steps)[index].Status == OperationFailed && operation.FailedStep == "" {
+			operation.FailedStep = step.Name
+		}
+	}
+}
+
+func (operation *Operation) updateStep(name OperationStepName, update func(step *OperationStep)) {
+	if operation.Steps == nil {
+		return
+	}
+	for index := range *operation.Steps {
+		if (*operation.Steps)[index].Name == name {
+			update(&(*operation.Steps)[index])
+			return
+		}
+	}
+}
+
+// OperationStepReporter is told when the steps of an operation start and finish on the cluster
+type OperationStepReporter interface {
+	StartStep(name OperationStepName)
+	FinishStep(name OperationStepName, err error)
+}
+
+// RunOperationStep runs a step of an operation and reports it, reporter may be nil when no operation tracks the step
+func RunOperationStep(reporter OperationStepReporter, name OperationStepName, run func() error) error {
+	if reporter == nil {
+		return run()
+	}
+	reporter.StartStep(name)
+	err := run()
+	reporter.FinishStep(name, err)
+	return err
+}
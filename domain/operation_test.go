@@ -0,0 +1,70 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestNewOperation_HasTheStepsOfItsType(t *testing.T) {
+	operation := NewOperation(DeleteOperation, Application{ID: "app-id", Name: "api", NamespaceID: "namespace-id"}, "user-id")
+	if operation.Status != OperationPending || operation.ApplicationID != "app-id" || operation.NamespaceID != "namespace-id" {
+		t.Fatalf("unexpected operation: %+v", operation)
+	}
+	expectedSteps := []OperationStepName{IngressOperationStep, ServiceOperationStep, DeploymentOperationStep, SecretsOperationStep}
+	if len(*operation.Steps) != len(expectedSteps) {
+		t.Fatalf("expected %d steps, got %d", len(expectedSteps), len(*operation.Steps))
+	}
+	for index, step := range *operation.Steps {
+		if step.Name != expectedSteps[index] || step.Status != OperationPending {
+			t.Errorf("expected pending step %s, got %+v", expectedSteps[index], step)
+		}
+	}
+	if DeleteOperation.WaitsForRollout() || !DeployOperation.WaitsForRollout() {
+		t.Errorf("expected only the operations applying the application to wait for its rollout")
+	}
+}
+
+func TestOperation_FinishRecordsTheFailedStep(t *testing.T) {
+	now := time.Now()
+	operation := NewOperation(DeployOperation, Application{ID: "app-id", Name: "api"}, "user-id")
+	operation.Start(now)
+	operation.StartStep(NamespaceOperationStep, now)
+	operation.FinishStep(NamespaceOperationStep, nil, now)
+	operation.StartStep(SecretsOperationStep, now)
+	operation.Finish(errors.New("secret api-secrets is forbidden"), now)
+
+	if operation.Status != OperationFailed || !operation.IsFinished() {
+		t.Fatalf("expected a failed operation, got %s", operation.Status)
+	}
+	if operation.FailedStep != SecretsOperationStep || operation.Error != "secret api-secrets is forbidden" {
+		t.Errorf("expected the secrets step to have failed, got %s: %s", operation.FailedStep, operation.Error)
+	}
+	steps := *operation.Steps
+	if steps[0].Status != OperationSucceeded || steps[1].Status != OperationFailed || steps[1].FinishedAt == nil || steps[2].Status != OperationPending {
+		t.Errorf("unexpected steps: %+v", steps)
+	}
+}
+
+func TestApplicationStatus_RolloutFailure(t *testing.T) {
+	status := ApplicationStatus{DesiredReplicas: 2, UpdatedReplicas: 2, AvailableReplicas: 1, ReadyReplicas: 1}
+	if status.IsRolledOut() || status.RolloutFailure() != "" {
+		t.Fatalf("expected a rollout in progress")
+	}
+
+	status.PodList.Items = []Pod{{
+		MetaData: PodMetaData{Name: "api-deployment-1"},
+		Status: PodStatus{ContainerStatuses: []ContainerStatus{{
+			Name:  "api",
+			State: ContainerState{Waiting: &ContainerStateWaiting{Reason: "ImagePullBackOff"}},
+		}}},
+	}}
+	if status.RolloutFailure() == "" {
+		t.Errorf("expected a pod unable to pull its image to fail the rollout")
+	}
+
+	status.AvailableReplicas, status.ReadyReplicas = 2, 2
+	if !status.IsRolledOut() {
+		t.Errorf("expected the rollout to be finished")
+	}
+}
@@ -0,0 +1,114 @@
+package domain
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RateLimitClass is a group of routes sharing the same rate limits
+type RateLimitClass string
+
+const (
+	RateLimitRead   RateLimitClass = "read"
+	RateLimitWrite  RateLimitClass = "write"
+	RateLimitDeploy RateLimitClass = "deploy"
+	RateLimitLogs   RateLimitClass = "logs"
+)
+
+// rateLimitDeployRoutes are the routes that apply or delete objects on the cluster, they are the most expensive ones
+var rateLimitDeployRoutes = map[string]bool{
+	"POST /applications":                                     true,
+	"PUT /applications/:id":                                  true,
+	"DELETE /applications/:id":                               true,
+	"POST /applications/:id/scale":                           true,
+	"PUT /applications/:id/secrets/:name":                    true,
+	"DELETE /applications/:id/secrets/:name":                 true,
+	"DELETE /namespaces/:id":                                 true,
+	"POST /namespaces/:id/manifest/apply":                    true,
+	"PUT /namespaces/:id/registry-credentials/:credentialId": true,
+}
+
+// RateLimitClassOf returns the class of a route, given relative to the API base path
+func RateLimitClassOf(method string, route string) RateLimitClass {
+	if rateLimitDeployRoutes[method+" "+route] {
+		return RateLimitDeploy
+	}
+	// Events are read from the cluster like logs, their stream reads them again for as long as it is open
+	if strings.HasSuffix(route, "/logs") || strings.HasSuffix(route, "/events") || strings.HasSuffix(route, "/events/stream") {
+		return RateLimitLogs
+	}
+	if method == "GET" || method == "HEAD" || method == "OPTIONS" {
+		return RateLimitRead
+	}
+	return RateLimitWrite
+}
+
+// RateLimit is a number of requests allowed in a fixed window, long windows act as quotas
+type RateLimit struct {
+	Requests int64
+	Window   time.Duration
+}
+
+func (rateLimit RateLimit) String() string {
+	return fmt.Sprintf("%d/%s", rateLimit.Requests, rateLimit.Window)
+}
+
+// WindowStart returns the start of the window counting the requests made at a given time
+func (rateLimit RateLimit) WindowStart(now time.Time) time.Time {
+	return now.UTC().Truncate(rateLimit.Window)
+}
+
+// ParseRateLimits parses comma separated "<requests>/<window>" limits such as "30/1m,500/24h".
+// "none" disables the limits.
+func ParseRateLimits(value string) ([]RateLimit, error) {
+	if strings.TrimSpace(value) == "none" {
+		return []RateLimit{}, nil
+	}
+
+	rateLimits := []RateLimit{}
+	for _, rawRateLimit := range strings.Split(value, ",") {
+		rawRequests, rawWindow, found := strings.Cut(strings.TrimSpace(rawRateLimit), "/")
+		if !found {
+			return nil, fmt.Errorf("rate limit %s must be <requests>/<window>", rawRateLimit)
+		}
+		requests, err := strconv.ParseInt(rawRequests, 10, 64)
+		if err != nil || requests <= 0 {
+			return nil, fmt.Errorf("rate limit %s must allow a strictly positive number of requests", rawRateLimit)
+		}
+		window, err := time.ParseDuration(rawWindow)
+		if err != nil || window < time.Second {
+			return nil, fmt.Errorf("rate limit %s must have a window of at least 1s", rawRateLimit)
+		}
+		rateLimits = append(rateLimits, RateLimit{Requests: requests, Window: window})
+	}
+	return rateLimits, nil
+}
+
+// RateLimitPolicy is the rate limits applied to each identity for each class of routes, a class without limits is not limited
+type RateLimitPolicy map[RateLimitClass][]RateLimit
+
+// RateLimitDecision is a struct that represents whether a request is allowed, with the limit closest to be or being exceeded
+type RateLimitDecision struct {
+	Allowed   bool
+	Limit     RateLimit
+	Remaining int64
+	ResetAt   time.Time
+}
+
+// RetryAfter returns how long the client has to wait before its next request can be allowed
+func (rateLimitDecision RateLimitDecision) RetryAfter(now time.Time) time.Duration {
+	if rateLimitDecision.Allowed || !rateLimitDecision.ResetAt.After(now) {
+		return 0
+	}
+	return rateLimitDecision.ResetAt.Sub(now)
+}
+
+// RateLimitCounter is a struct that represents the number of requests of a key in a window, stored by the MySQL rate limit counters.
+// The column is not named "key", which is a reserved MySQL word.
+type RateLimitCounter struct {
+	Key         string    `gorm:"column:counter_key;size:191;primaryKey"`
+	WindowStart time.Time `gorm:"primaryKey"`
+	Count       int64     `gorm:"not null"`
+}
@@ -0,0 +1,54 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseRateLimits(t *testing.T) {
+	rateLimits, err := ParseRateLimits("30/1m, 500/24h")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []RateLimit{{Requests: 30, Window: time.Minute}, {Requests: 500, Window: 24 * time.Hour}}
+	if len(rateLimits) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, rateLimits)
+	}
+	for i := range expected {
+		if rateLimits[i] != expected[i] {
+			t.Errorf("expected %v, got %v", expected[i], rateLimits[i])
+		}
+	}
+
+	rateLimits, err = ParseRateLimits("none")
+	if err != nil || len(rateLimits) != 0 {
+		t.Errorf("expected no limits, got %v, %v", rateLimits, err)
+	}
+
+	for _, value := range []string{"", "30", "0/1m", "-1/1m", "30/abc", "30/500ms"} {
+		if _, err := ParseRateLimits(value); err == nil {
+			t.Errorf("expected %q to be rejected", value)
+		}
+	}
+}
+
+func TestRateLimitClassOf(t *testing.T) {
+	testCases := []struct {
+		method   string
+		route    string
+		expected RateLimitClass
+	}{
+		{"GET", "/applications", RateLimitRead},
+		{"POST", "/applications", RateLimitDeploy},
+		{"POST", "/applications/:id/scale", RateLimitDeploy},
+		{"POST", "/namespaces/:id/manifest/apply", RateLimitDeploy},
+		{"GET", "/applications/:id/logs", RateLimitLogs},
+		{"GET", "/applications/:id/events/stream", RateLimitLogs},
+		{"POST", "/access-tokens", RateLimitWrite},
+	}
+	for _, testCase := range testCases {
+		if class := RateLimitClassOf(testCase.method, testCase.route); class != testCase.expected {
+			t.Errorf("%s %s: expected %s, got %s", testCase.method, testCase.route, testCase.expected, class)
+		}
+	}
+}
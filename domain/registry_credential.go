@@ -0,0 +1,102 @@
+package domain
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// RegistryCredential is a struct that represents the account of a namespace on a Docker registry (GHCR, GitLab, Harbor, ...).
+// Applications using the custom registry pull their image with one of the credentials of their namespace.
+type RegistryCredential struct {
+	ID          string `json:"id" gorm:"primaryKey"`
+	NamespaceID string `json:"namespaceId" gorm:"size:255;index:idx_registry_credential_namespace_id;not null"`
+	Name        string `json:"name" gorm:"size:63;not null"`
+	// Server is the host of the registry, such as ghcr.io or registry.gitlab.com
+	Server   string `json:"server" gorm:"size:255;not null"`
+	Username string `json:"username" gorm:"size:255;not null"`
+	// Password is encrypted with the data key of the namespace, it is never written in API responses
+	Password string `json:"-" gorm:"type:text;not null"`
+	// PasswordPreview is the masked password, computed when the password is set
+	PasswordPreview string          `json:"passwordPreview" gorm:"size:20"`
+	CreatedBy       string          `json:"createdBy" gorm:"size:255;not null"`
+	UpdatedAt       time.Time       `json:"updatedAt" gorm:"autoUpdateTime"`
+	CreatedAt       time.Time       `json:"createdAt" gorm:"autoCreateTime"`
+	DeletedAt       *gorm.DeletedAt `json:"deletedAt" gorm:"index;default:null"`
+}
+
+// registryCredentialPasswordSecretName is the name of the password when it goes through the secrets cipher
+const registryCredentialPasswordSecretName = "password"
+
+// PasswordSecrets returns the password as secrets, so that it is encrypted and decrypted like the application secrets
+func (registryCredential RegistryCredential) PasswordSecrets() ApplicationSecrets {
+	return ApplicationSecrets{{Name: registryCredentialPasswordSecretName, Val: registryCredential.Password}}
+}
+
+// WithPasswordSecrets returns the credential with the password of secrets returned by the secrets cipher
+func (registryCredential RegistryCredential) WithPasswordSecrets(secrets ApplicationSecrets) RegistryCredential {
+	password, _ := secrets.Find(registryCredentialPasswordSecretName)
+	registryCredential.Password = password.Val
+	return registryCredential
+}
+
+var registryCredentialNameRegex = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]{0,61}[a-z0-9])?$`)
+
+var registryServerRegex = regexp.MustCompile(`^[a-zA-Z0-9]([-a-zA-Z0-9.]*[a-zA-Z0-9])?(:[0-9]{1,5})?(/[a-zA-Z0-9._-]+)*$`)
+
+// ValidateRegistryCredentialName checks that a name can be used in manifests to refer to the credential
+func ValidateRegistryCredentialName(name string) error {
+	if !registryCredentialNameRegex.MatchString(name) {
+		return fmt.Errorf("name %s must be at most 63 lowercase alphanumeric characters or '-', starting and ending with an alphanumeric character", name)
+	}
+	return nil
+}
+
+// NormalizeRegistryServer returns the host of a registry without scheme nor trailing slash, as written in Docker configs,
+// or an error if it is not a registry host
+func NormalizeRegistryServer(server string) (string, error) {
+	normalizedServer := strings.TrimSpace(server)
+	normalizedServer = strings.TrimPrefix(normalizedServer, "https://")
+	normalizedServer = strings.TrimPrefix(normalizedServer, "http://")
+	normalizedServer = strings.TrimRight(normalizedServer, "/")
+	if !registryServerRegex.MatchString(normalizedServer) {
+		return "", fmt.Errorf("server %s must be the host of a registry, such as ghcr.io or registry.example.com:5000", server)
+	}
+	return normalizedServer, nil
+}
+
+// dockerConfigAuth is the authentication of a registry in a Docker config
+type dockerConfigAuth struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+	Auth     string `json:"auth"`
+}
+
+// DockerConfigJSON returns the ".dockerconfigjson" content authenticating on the registry with the decrypted password
+func (registryCredential RegistryCredential) DockerConfigJSON() ([]byte, error) {
+	return json.Marshal(map[string]map[string]dockerConfigAuth{
+		"auths": {
+			registryCredential.Server: {
+				Username: registryCredential.Username,
+				Password: registryCredential.Password,
+				Auth:     base64.StdEncoding.EncodeToString([]byte(registryCredential.Username + ":" + registryCredential.Password)),
+			},
+		},
+	})
+}
+
+// ValidateImageRegistry checks that applications use a registry credential if and only if they pull from the custom registry
+func ValidateImageRegistry(registry ImageRegistry, registryCredentialID *string) error {
+	if registry == CustomRegistry && (registryCredentialID == nil || *registryCredentialID == "") {
+		return fmt.Errorf("registry %s requires a registry credential", CustomRegistry)
+	}
+	if registry != CustomRegistry && registryCredentialID != nil && *registryCredentialID != "" {
+		return fmt.Errorf("registry credentials can only be used with the registry %s", CustomRegistry)
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package domain
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestNormalizeRegistryServer(t *testing.T) {
+	for server, expected := range map[string]string{
+		"ghcr.io":                          "ghcr.io",
+		"https://registry.gitlab.com/":     "registry.gitlab.com",
+		" harbor.example.com:5000 ":        "harbor.example.com:5000",
+		"http://registry.local:5000/team/": "registry.local:5000/team",
+	} {
+		normalizedServer, err := NormalizeRegistryServer(server)
+		if err != nil || normalizedServer != expected {
+			t.Errorf("expected %q to be normalized to %q, got %q, %v", server, expected, normalizedServer, err)
+		}
+	}
+
+	for _, server := range []string{"", "ftp://ghcr.io", "ghcr.io:port", "user@ghcr.io", "-ghcr.io"} {
+		if _, err := NormalizeRegistryServer(server); err == nil {
+			t.Errorf("expected %q to be rejected", server)
+		}
+	}
+}
+
+func TestRegistryCredential_DockerConfigJSON(t *testing.T) {
+	registryCredential := RegistryCredential{Server: "ghcr.io", Username: "octocat", Password: "token"}
+	dockerConfigJSON, err := registryCredential.DockerConfigJSON()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var dockerConfig map[string]map[string]dockerConfigAuth
+	if err := json.Unmarshal(dockerConfigJSON, &dockerConfig); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	auth := dockerConfig["auths"]["ghcr.io"]
+	if auth.Username != "octocat" || auth.Password != "token" || auth.Auth != "b2N0b2NhdDp0b2tlbg==" {
+		t.Errorf("expected the auth of octocat on ghcr.io, got %+v", auth)
+	}
+}
+
+func TestRegistryCredential_PasswordIsNotSerialized(t *testing.T) {
+	serialized, err := json.Marshal(RegistryCredential{Name: "ghcr", Password: "token", PasswordPreview: "****"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var fields map[string]interface{}
+	_ = json.Unmarshal(serialized, &fields)
+	if _, ok := fields["password"]; ok {
+		t.Errorf("expected the password not to be serialized, got %s", serialized)
+	}
+}
+
+func TestValidateImageRegistry(t *testing.T) {
+	registryCredentialID := "credential-id"
+	if err := ValidateImageRegistry(CustomRegistry, &registryCredentialID); err != nil {
+		t.Errorf("expected the custom registry with a credential to be valid, got %v", err)
+	}
+	if err := ValidateImageRegistry(DockerHubRegistry, nil); err != nil {
+		t.Errorf("expected Docker Hub without credential to be valid, got %v", err)
+	}
+	if err := ValidateImageRegistry(CustomRegistry, nil); err == nil {
+		t.Error("expected the custom registry without credential to be rejected")
+	}
+	if err := ValidateImageRegistry(PrivateRegistry, &registryCredentialID); err == nil {
+		t.Error("expected a credential with the private registry to be rejected")
+	}
+}
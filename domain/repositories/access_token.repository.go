@@ -0,0 +1,24 @@
+package repositories
+
+import (
+	"time"
+
+	"cloud-app-hive/domain"
+	"cloud-app-hive/domain/commands"
+)
+
+// AccessTokenRepository is an interface that represents a repository of access tokens
+type AccessTokenRepository interface {
+	// Create creates a new access token
+	Create(createAccessToken commands.CreateAccessToken) (*domain.AccessToken, error)
+	// FindByID returns an access token by its ID
+	FindByID(id string) (*domain.AccessToken, error)
+	// FindBySecretHash returns the access token of a secret
+	FindBySecretHash(secretHash string) (*domain.AccessToken, error)
+	// FindByUserID returns the access tokens of a user
+	FindByUserID(userID string) ([]domain.AccessToken, error)
+	// Revoke revokes an access token
+	Revoke(id string, revokedAt time.Time) (*domain.AccessToken, error)
+	// UpdateLastUsedAt records the last use of an access token
+	UpdateLastUsedAt(id string, lastUsedAt time.Time) error
+}
@@ -0,0 +1,26 @@
+package repositories
+
+import (
+	"cloud-app-hive/domain"
+)
+
+// AddonRepository is an interface that represents a repository of add-ons and of their bindings.
+// Credentials are encrypted before they are stored and returned encrypted.
+type AddonRepository interface {
+	// Create creates a new add-on
+	Create(addon domain.Addon) (*domain.Addon, error)
+	// FindByID returns an add-on with its bindings by its ID, or nil if it does not exist
+	FindByID(id string) (*domain.Addon, error)
+	// FindByNamespaceID returns the add-ons of a namespace with their bindings
+	FindByNamespaceID(namespaceID string) ([]domain.Addon, error)
+	// FindAll returns the add-ons of every namespace, without their bindings
+	FindAll() ([]domain.Addon, error)
+	// UpdateCredentials encrypts and stores the credentials of an add-on
+	UpdateCredentials(addonID string, namespaceID string, credentials domain.ApplicationSecrets) error
+	// Delete deletes an add-on
+	Delete(id string) error
+	// CreateBinding binds an add-on to an application
+	CreateBinding(addonBinding domain.AddonBinding) (*domain.AddonBinding, error)
+	// DeleteBinding unbinds an add-on from an application
+	DeleteBinding(id string) error
+}
@@ -28,6 +28,21 @@ type ApplicationRepository interface {
 	// Update updates an application
 	Update(applicationID string, application commands.UpdateApplication) (*domain.Application, error)
 
+	// FindAll returns every application, with its namespace
+	FindAll() ([]domain.Application, error)
+
+	// FindWithSecrets returns every application that has secrets or secret config files
+	FindWithSecrets() ([]domain.Application, error)
+
+	// UpdateSecrets encrypts and stores the secrets of an application, without changing its other fields
+	UpdateSecrets(applicationID string, namespaceID string, secrets domain.ApplicationSecrets) error
+
+	// UpdateConfigFiles encrypts the contents of the secret config files and stores the config files of an application, without changing its other fields
+	UpdateConfigFiles(applicationID string, namespaceID string, configFiles domain.ApplicationConfigFiles) error
+
+	// UpdateDependencies stores the dependencies of an application resolved against its namespace, without changing its other fields
+	UpdateDependencies(applicationID string, dependencies domain.ApplicationDependencies) error
+
 	// Delete deletes an application
 	Delete(id string) (*domain.Application, error)
 
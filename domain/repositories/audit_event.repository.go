@@ -0,0 +1,14 @@
+package repositories
+
+import (
+	"cloud-app-hive/domain"
+	"cloud-app-hive/domain/commands"
+)
+
+// AuditEventRepository is an interface that represents the append-only audit log
+type AuditEventRepository interface {
+	// Create appends an event to the audit log
+	Create(auditEvent domain.AuditEvent) error
+	// Find returns the events of a namespace matching the filters, the most recent first
+	Find(findAuditEvents commands.FindAuditEvents) ([]domain.AuditEvent, error)
+}
@@ -0,0 +1,24 @@
+package repositories
+
+import (
+	"cloud-app-hive/domain"
+)
+
+// ConfigGroupRepository is an interface that represents a repository of config groups and of their attachments.
+// Secrets are encrypted before they are stored and returned encrypted.
+type ConfigGroupRepository interface {
+	// Create creates a new config group
+	Create(configGroup domain.ConfigGroup) (*domain.ConfigGroup, error)
+	// FindByID returns a config group with its attachments by its ID, or nil if it does not exist
+	FindByID(id string) (*domain.ConfigGroup, error)
+	// FindByNamespaceID returns the config groups of a namespace with their attachments
+	FindByNamespaceID(namespaceID string) ([]domain.ConfigGroup, error)
+	// FindAll returns the config groups of every namespace, without their attachments
+	FindAll() ([]domain.ConfigGroup, error)
+	// Update updates the values and the version of a config group
+	Update(configGroup domain.ConfigGroup) (*domain.ConfigGroup, error)
+	// Delete deletes a config group
+	Delete(id string) error
+	// ReplaceAttachments replaces the config groups an application is attached to
+	ReplaceAttachments(applicationID string, attachments []domain.ConfigGroupAttachment) error
+}
@@ -11,12 +11,30 @@ type ContainerManagerRepository interface {
 	GetApplicationMetrics(application commands.GetApplicationMetrics) ([]domain.ApplicationMetrics, error)
 	// ApplyApplication deploys an application on a container manager
 	ApplyApplication(applyApplication commands.ApplyApplication) error
+	// DryRunApplication renders the objects of an application deployment and compares them with the live ones, without applying anything
+	DryRunApplication(applyApplication commands.ApplyApplication) ([]domain.KubernetesObjectDryRun, error)
 	// GetApplicationLogs returns the logs of an application
 	GetApplicationLogs(application commands.GetApplicationLogs) ([]domain.ApplicationLogs, error)
+	// GetApplicationEvents returns the Kubernetes events of the Deployment, ReplicaSets, Pods, Service and Ingress of an application
+	GetApplicationEvents(application commands.GetApplicationEvents) ([]domain.ApplicationEvent, error)
 	// GetApplicationStatus returns the status of an application
 	GetApplicationStatus(application commands.GetApplicationStatus) (*domain.ApplicationStatus, error)
+	// RefreshApplicationSecrets updates the secrets of a deployed application when their values changed, and returns true if they were updated
+	RefreshApplicationSecrets(applyApplication commands.ApplyApplication) (bool, error)
+	// RestartApplication rolls the pods of a deployed application out, so that they read its secrets again
+	RestartApplication(restartApplication commands.RestartApplication) error
+	// ApplyNetworkRules isolates a namespace from the other namespaces and allows the traffic of its network rules only
+	ApplyNetworkRules(applyNetworkRules commands.ApplyNetworkRules) error
 	// UnapplyApplication delete an application on a container manager
 	UnapplyApplication(applyApplication commands.UnapplyApplication) error
+	// ApplyAddon provisions an add-on with its storage in a namespace
+	ApplyAddon(applyAddon commands.ApplyAddon) error
+	// UnapplyAddon deletes an add-on and its data
+	UnapplyAddon(unapplyAddon commands.UnapplyAddon) error
+	// ApplyConfigGroup renders a config group in a namespace, so that the applications attached to it can read it
+	ApplyConfigGroup(applyConfigGroup commands.ApplyConfigGroup) error
+	// UnapplyConfigGroup deletes a config group from a namespace
+	UnapplyConfigGroup(unapplyConfigGroup commands.UnapplyConfigGroup) error
 	// DeleteNamespace deletes a namespace on a container manager
 	DeleteNamespace(namespace string) error
 	// GetKubeClusterState returns the state of the kubernetes cluster
@@ -0,0 +1,7 @@
+package repositories
+
+// EmailSender sends emails to the users, it is implemented by the EmailService
+type EmailSender interface {
+	// Send sends an email with a text and an HTML body
+	Send(to, subject, body, htmlBody string, carbonCopy []string) error
+}
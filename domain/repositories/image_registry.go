@@ -0,0 +1,9 @@
+package repositories
+
+import "cloud-app-hive/domain"
+
+// ImageRegistry reads the manifests of images through the Docker Registry HTTP API v2
+type ImageRegistry interface {
+	// FindImageManifest returns the manifest of the image, or nil if the registry does not have it
+	FindImageManifest(endpoint domain.ImageRegistryEndpoint, imageReference domain.ImageReference) (*domain.ImageManifest, error)
+}
@@ -0,0 +1,15 @@
+package repositories
+
+import (
+	"cloud-app-hive/domain"
+)
+
+// ImageScanRepository is an interface that represents a repository of image scans, one per image digest
+type ImageScanRepository interface {
+	// Create stores the scan of an image digest, the scan already stored is returned if the digest was scanned concurrently
+	Create(imageScan domain.ImageScan) (*domain.ImageScan, error)
+	// FindByImageDigest returns the scan of an image digest, or nil if it has not been scanned
+	FindByImageDigest(imageDigest string) (*domain.ImageScan, error)
+	// Update stores the vulnerabilities found when scanning an image digest again
+	Update(imageScan domain.ImageScan) error
+}
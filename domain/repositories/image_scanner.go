@@ -0,0 +1,9 @@
+package repositories
+
+import "cloud-app-hive/domain"
+
+// ImageScanner scans images for known vulnerabilities
+type ImageScanner interface {
+	// ScanImage returns the vulnerabilities of the image, pulled from the registry endpoint by its digest
+	ScanImage(endpoint domain.ImageRegistryEndpoint, imageReference domain.ImageReference) (domain.ImageVulnerabilities, error)
+}
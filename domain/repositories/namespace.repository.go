@@ -1,6 +1,8 @@
 package repositories
 
 import (
+	"time"
+
 	"cloud-app-hive/domain"
 	"cloud-app-hive/domain/commands"
 )
@@ -21,4 +23,10 @@ type NamespaceRepository interface {
 	Delete(id string, userId string) (*domain.Namespace, error)
 	// Update updates a namespace
 	Update(namespace commands.UpdateNamespace) (*domain.Namespace, error)
+	// SetPendingOwner records the member a namespace is being transferred to, a nil pending owner cancels the transfer
+	SetPendingOwner(namespaceID string, pendingOwnerID *string, expiresAt *time.Time) error
+	// SetImageScanPolicy stores the image scan policy of a namespace and the most severe vulnerabilities it allows
+	SetImageScanPolicy(namespaceID string, policy domain.ImageScanPolicy, maxSeverity domain.VulnerabilitySeverity) error
+	// TransferOwnership makes the member the owner of the namespace and the previous owner an admin
+	TransferOwnership(namespaceID string, newOwnerID string) (*domain.Namespace, error)
 }
@@ -0,0 +1,21 @@
+package repositories
+
+import "cloud-app-hive/domain"
+
+// NamespaceDataKeyRepository is an interface that represents a repository of wrapped namespace data keys
+type NamespaceDataKeyRepository interface {
+	// Create stores a new data key
+	Create(namespaceDataKey domain.NamespaceDataKey) (*domain.NamespaceDataKey, error)
+
+	// FindByID returns a data key by its ID
+	FindByID(id string) (*domain.NamespaceDataKey, error)
+
+	// FindLatestByNamespaceID returns the most recent data key of a namespace, the one used to encrypt new secrets
+	FindLatestByNamespaceID(namespaceID string) (*domain.NamespaceDataKey, error)
+
+	// FindAll returns every data key
+	FindAll() ([]domain.NamespaceDataKey, error)
+
+	// Delete deletes a data key
+	Delete(id string) error
+}
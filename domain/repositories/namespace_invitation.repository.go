@@ -0,0 +1,22 @@
+package repositories
+
+import (
+	"time"
+
+	"cloud-app-hive/domain"
+	"cloud-app-hive/domain/commands"
+)
+
+// NamespaceInvitationRepository is an interface that represents a repository of namespace invitations
+type NamespaceInvitationRepository interface {
+	// Create creates a new pending namespace invitation
+	Create(createNamespaceInvitation commands.CreateNamespaceInvitation) (*domain.NamespaceInvitation, error)
+	// FindByID returns a namespace invitation by its ID
+	FindByID(id string) (*domain.NamespaceInvitation, error)
+	// FindByTokenHash returns the namespace invitation of a token
+	FindByTokenHash(tokenHash string) (*domain.NamespaceInvitation, error)
+	// FindPendingByNamespaceID returns the invitations of a namespace that are still pending at a given time
+	FindPendingByNamespaceID(namespaceID string, now time.Time) ([]domain.NamespaceInvitation, error)
+	// UpdateStatus changes the status of a pending invitation and returns false if the invitation was no longer pending
+	UpdateStatus(id string, status domain.NamespaceInvitationStatus, respondedBy *string, updatedAt time.Time) (bool, error)
+}
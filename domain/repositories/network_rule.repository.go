@@ -0,0 +1,18 @@
+package repositories
+
+import (
+	"cloud-app-hive/domain"
+	"cloud-app-hive/domain/commands"
+)
+
+// NetworkRuleRepository is an interface that represents a repository of network rules
+type NetworkRuleRepository interface {
+	// Create creates a new network rule
+	Create(createNetworkRule commands.CreateNetworkRule) (*domain.NetworkRule, error)
+	// FindByID returns a network rule by its ID, or nil if it does not exist
+	FindByID(id string) (*domain.NetworkRule, error)
+	// FindByNamespaceID returns the network rules of a namespace
+	FindByNamespaceID(namespaceID string) ([]domain.NetworkRule, error)
+	// Delete deletes a network rule
+	Delete(id string) error
+}
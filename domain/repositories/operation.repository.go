@@ -0,0 +1,17 @@
+package repositories
+
+import (
+	"cloud-app-hive/domain"
+)
+
+// OperationRepository is an interface that represents a repository of operations on applications
+type OperationRepository interface {
+	// Create creates a new operation
+	Create(operation domain.Operation) (*domain.Operation, error)
+	// FindByID returns an operation by its ID, or nil if it does not exist
+	FindByID(id string) (*domain.Operation, error)
+	// FindUnfinished returns the operations that are still pending or running
+	FindUnfinished() ([]domain.Operation, error)
+	// Update stores the status and the steps of an operation
+	Update(operation domain.Operation) error
+}
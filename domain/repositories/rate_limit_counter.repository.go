@@ -0,0 +1,9 @@
+package repositories
+
+import "time"
+
+// RateLimitCounterRepository is an interface that represents a repository of rate limit counters
+type RateLimitCounterRepository interface {
+	// Increment counts a request of the key in the window starting at windowStart and returns the number of requests counted in this window
+	Increment(key string, windowStart time.Time, window time.Duration) (int64, error)
+}
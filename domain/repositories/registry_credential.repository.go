@@ -0,0 +1,22 @@
+package repositories
+
+import (
+	"cloud-app-hive/domain"
+)
+
+// RegistryCredentialRepository is an interface that represents a repository of registry credentials.
+// Passwords are encrypted before they are stored and returned encrypted.
+type RegistryCredentialRepository interface {
+	// Create creates a new registry credential
+	Create(registryCredential domain.RegistryCredential) (*domain.RegistryCredential, error)
+	// FindByID returns a registry credential by its ID, or nil if it does not exist
+	FindByID(id string) (*domain.RegistryCredential, error)
+	// FindByNamespaceID returns the registry credentials of a namespace
+	FindByNamespaceID(namespaceID string) ([]domain.RegistryCredential, error)
+	// FindAll returns the registry credentials of every namespace
+	FindAll() ([]domain.RegistryCredential, error)
+	// Update stores the server, username and password of a registry credential
+	Update(registryCredential domain.RegistryCredential) (*domain.RegistryCredential, error)
+	// Delete deletes a registry credential
+	Delete(id string) error
+}
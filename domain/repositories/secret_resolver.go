@@ -0,0 +1,11 @@
+package repositories
+
+import "cloud-app-hive/domain"
+
+// SecretResolver reads the values of the application secrets that reference a secret provider instead of holding a value.
+// Like decryption, resolution only happens to build the Kubernetes Secrets of the applications.
+type SecretResolver interface {
+	// Resolve returns the secrets with the values of their references, secrets without reference are returned as is.
+	// References are read under the path of the namespace in their provider, named by the ID of the namespace since its name can be reused.
+	Resolve(namespaceID string, secrets domain.ApplicationSecrets) (domain.ApplicationSecrets, error)
+}
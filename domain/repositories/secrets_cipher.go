@@ -0,0 +1,20 @@
+package repositories
+
+import "cloud-app-hive/domain"
+
+// SecretsCipher encrypts the values of the application secrets stored in the database.
+// Values are only decrypted to build the Kubernetes Secrets of the applications.
+type SecretsCipher interface {
+	// Encrypt encrypts the values of the secrets with the data key of the namespace, values already encrypted for the namespace are kept
+	Encrypt(namespaceID string, secrets domain.ApplicationSecrets) (domain.ApplicationSecrets, error)
+
+	// Decrypt returns the secrets with their plain values, values stored before encryption at rest are returned as is
+	Decrypt(secrets domain.ApplicationSecrets) (domain.ApplicationSecrets, error)
+
+	// RotateDataKey gives the namespace a new data key, wrapped by the current master key and used by the next encryptions
+	RotateDataKey(namespaceID string) error
+
+	// PruneDataKeys deletes the data keys that are not the latest of their namespace or not wrapped by the current master key.
+	// The data keys the stored secrets are still encrypted with are kept, the stored secrets must be read after every secret is re-encrypted.
+	PruneDataKeys(storedSecrets domain.ApplicationSecrets) error
+}
@@ -0,0 +1,42 @@
+package repositories
+
+import (
+	"time"
+
+	"cloud-app-hive/domain"
+)
+
+// WebhookRepository is an interface that represents a repository of webhooks.
+// Secrets are encrypted before they are stored and returned encrypted.
+type WebhookRepository interface {
+	// Create creates a new webhook
+	Create(webhook domain.Webhook) (*domain.Webhook, error)
+	// FindByID returns a webhook by its ID, or nil if it does not exist
+	FindByID(id string) (*domain.Webhook, error)
+	// FindByNamespaceID returns the webhooks of a namespace
+	FindByNamespaceID(namespaceID string) ([]domain.Webhook, error)
+	// FindAll returns the webhooks of every namespace
+	FindAll() ([]domain.Webhook, error)
+	// Update stores the URL, the events, the state and the secret of a webhook
+	Update(webhook domain.Webhook) (*domain.Webhook, error)
+	// Delete deletes a webhook
+	Delete(id string) error
+}
+
+// WebhookDeliveryRepository is an interface that represents the delivery log of the webhooks
+type WebhookDeliveryRepository interface {
+	// Create appends an attempt to deliver an event to the log
+	Create(webhookDelivery domain.WebhookDelivery) (*domain.WebhookDelivery, error)
+	// FindByWebhookID returns the last attempts to deliver events to a webhook, most recent first
+	FindByWebhookID(webhookID string, limit int) ([]domain.WebhookDelivery, error)
+	// FindDue returns the failed attempts whose next attempt is due at now, oldest first
+	FindDue(now time.Time) ([]domain.WebhookDelivery, error)
+	// ClearNextAttempt marks the next attempt of a failed attempt as sent, it returns false when it was already cleared
+	ClearNextAttempt(webhookDeliveryID string) (bool, error)
+}
+
+// WebhookSender sends the events to the webhooks
+type WebhookSender interface {
+	// Send posts the payload to the URL with the headers and returns the HTTP status answered, an error is returned when the endpoint did not answer
+	Send(url string, headers map[string]string, payload []byte) (int, error)
+}
@@ -0,0 +1,55 @@
+package domain
+
+import (
+	"cloud-app-hive/controllers/errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// secretReferenceRegex matches "provider://path#key", the key being optional
+var secretReferenceRegex = regexp.MustCompile(`^([a-z][a-z0-9-]*)://([^#]+)(?:#(.+))?$`)
+
+// SecretReference is a struct that represents a secret stored by a secret provider instead of the database
+type SecretReference struct {
+	Provider string
+	Path     string
+	Key      string
+}
+
+// ParseSecretReference parses a reference formatted as "provider://path#key"
+func ParseSecretReference(reference string) (SecretReference, error) {
+	matches := secretReferenceRegex.FindStringSubmatch(reference)
+	if matches == nil {
+		return SecretReference{}, errors.NewInvalidApplicationSecretsError(
+			fmt.Sprintf("reference %s must be formatted as provider://path#key", reference),
+		)
+	}
+	return SecretReference{
+		Provider: matches[1],
+		Path:     strings.Trim(matches[2], "/"),
+		Key:      matches[3],
+	}, nil
+}
+
+func (secretReference SecretReference) String() string {
+	if secretReference.Key == "" {
+		return secretReference.Provider + "://" + secretReference.Path
+	}
+	return secretReference.Provider + "://" + secretReference.Path + "#" + secretReference.Key
+}
+
+// IsReference returns true if the value of the secret is stored by a secret provider
+func (applicationSecret ApplicationSecret) IsReference() bool {
+	return applicationSecret.Reference != ""
+}
+
+// HasReferences returns true if one of the secrets is stored by a secret provider
+func (applicationSecrets ApplicationSecrets) HasReferences() bool {
+	for _, secret := range applicationSecrets {
+		if secret.IsReference() {
+			return true
+		}
+	}
+	return false
+}
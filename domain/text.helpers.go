@@ -0,0 +1,18 @@
+package domain
+
+import "unicode/utf8"
+
+// MaxRecordedErrorLength is the size of the columns keeping the error of an audit event or a webhook delivery
+const MaxRecordedErrorLength = 1000
+
+// TruncateText returns at most maxBytes bytes of text, cut before the rune that would not fit so that the result stays valid UTF-8
+func TruncateText(text string, maxBytes int) string {
+	if len(text) <= maxBytes {
+		return text
+	}
+	end := maxBytes
+	for end > 0 && !utf8.RuneStart(text[end]) {
+		end--
+	}
+	return text[:end]
+}
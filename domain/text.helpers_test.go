@@ -0,0 +1,23 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestTruncateText_CutsOnARuneBoundary(t *testing.T) {
+	if truncated := TruncateText("short", 10); truncated != "short" {
+		t.Errorf("Expected a short text to be kept, but got %q", truncated)
+	}
+	if truncated := TruncateText("abcdef", 3); truncated != "abc" {
+		t.Errorf("Expected an ASCII text to be cut at the limit, but got %q", truncated)
+	}
+
+	// "é" is encoded on 2 bytes, the limit falls in the middle of the last one
+	text := "a" + strings.Repeat("é", 5)
+	truncated := TruncateText(text, 4)
+	if truncated != "aé" || !utf8.ValidString(truncated) {
+		t.Errorf("Expected the text to be cut before the split rune, but got %q", truncated)
+	}
+}
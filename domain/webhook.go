@@ -0,0 +1,299 @@
+package domain
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql/driver"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// WebhookEventType is an enum that represents what happened in a namespace
+type WebhookEventType string
+
+const (
+	WebhookApplicationCreated       WebhookEventType = "application.created"
+	WebhookApplicationDeployed      WebhookEventType = "application.deployed"
+	WebhookApplicationFailed        WebhookEventType = "application.failed"
+	WebhookApplicationScaled        WebhookEventType = "application.scaled"
+	WebhookApplicationStatusChanged WebhookEventType = "application.status_changed"
+	WebhookApplicationDeleted       WebhookEventType = "application.deleted"
+	WebhookMembershipAdded          WebhookEventType = "membership.added"
+	WebhookMembershipUpdated        WebhookEventType = "membership.updated"
+	WebhookMembershipRemoved        WebhookEventType = "membership.removed"
+)
+
+// AllWebhookEventTypes are the events a webhook can subscribe to
+var AllWebhookEventTypes = []WebhookEventType{
+	WebhookApplicationCreated,
+	WebhookApplicationDeployed,
+	WebhookApplicationFailed,
+	WebhookApplicationScaled,
+	WebhookApplicationStatusChanged,
+	WebhookApplicationDeleted,
+	WebhookMembershipAdded,
+	WebhookMembershipUpdated,
+	WebhookMembershipRemoved,
+}
+
+// auditActionWebhookEventTypes are the events sent when an action of the audit log succeeds
+var auditActionWebhookEventTypes = map[AuditAction]WebhookEventType{
+	AuditApplicationCreate:     WebhookApplicationCreated,
+	AuditApplicationScale:      WebhookApplicationScaled,
+	AuditApplicationDelete:     WebhookApplicationDeleted,
+	AuditNamespaceMemberAdd:    WebhookMembershipAdded,
+	AuditNamespaceInviteAccept: WebhookMembershipAdded,
+	AuditNamespaceMemberUpdate: WebhookMembershipUpdated,
+	AuditNamespaceMemberRemove: WebhookMembershipRemoved,
+}
+
+// WebhookEventTypes is a slice of WebhookEventType
+// swagger:model WebhookEventTypes
+type WebhookEventTypes []WebhookEventType
+
+func (webhookEventTypes *WebhookEventTypes) Scan(value interface{}) error {
+	bytes, ok := value.([]byte)
+	if !ok {
+		return fmt.Errorf("failed to unmarshal JSONB value: %v", value)
+	}
+
+	return json.Unmarshal(bytes, &webhookEventTypes)
+}
+
+func (webhookEventTypes WebhookEventTypes) Value() (driver.Value, error) {
+	return json.Marshal(webhookEventTypes)
+}
+
+// Validate checks that the webhook subscribes to at least one known event, once each
+func (webhookEventTypes WebhookEventTypes) Validate() error {
+	if len(webhookEventTypes) == 0 {
+		return fmt.Errorf("a webhook must subscribe to at least one event")
+	}
+	subscribed := map[WebhookEventType]bool{}
+	for _, eventType := range webhookEventTypes {
+		if !isWebhookEventType(eventType) {
+			return fmt.Errorf("event %s is not one of %v", eventType, AllWebhookEventTypes)
+		}
+		if subscribed[eventType] {
+			return fmt.Errorf("event %s is subscribed twice", eventType)
+		}
+		subscribed[eventType] = true
+	}
+	return nil
+}
+
+func isWebhookEventType(eventType WebhookEventType) bool {
+	for _, webhookEventType := range AllWebhookEventTypes {
+		if webhookEventType == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// Webhook is a struct that represents an endpoint of a namespace receiving its events.
+// Each event is sent as a JSON body signed with the secret of the webhook, so that the endpoint can check it comes from the platform.
+type Webhook struct {
+	ID          string            `json:"id" gorm:"primaryKey"`
+	NamespaceID string            `json:"namespaceId" gorm:"size:255;index:idx_webhook_namespace_id;not null"`
+	URL         string            `json:"url" gorm:"size:2048;not null"`
+	EventTypes  WebhookEventTypes `json:"eventTypes" gorm:"type:json"`
+	Active      bool              `json:"active" gorm:"not null"`
+	// Secret is the key signing the events, encrypted with the data key of the namespace. It is only returned when the webhook is created.
+	Secret    string          `json:"-" gorm:"type:text;not null"`
+	CreatedBy string          `json:"createdBy" gorm:"size:255;not null"`
+	UpdatedAt time.Time       `json:"updatedAt" gorm:"autoUpdateTime"`
+	CreatedAt time.Time       `json:"createdAt" gorm:"autoCreateTime"`
+	DeletedAt *gorm.DeletedAt `json:"deletedAt" gorm:"index;default:null"`
+}
+
+// webhookSecretName is the name of the secret when it goes through the secrets cipher
+const webhookSecretName = "secret"
+
+// WebhookSecretPrefix starts the secrets of the webhooks, so that they are recognizable in the configuration of the endpoints
+const WebhookSecretPrefix = "whsec_"
+
+// NewWebhookSecret returns a random webhook secret
+func NewWebhookSecret() (string, error) {
+	randomBytes := make([]byte, 32)
+	if _, err := rand.Read(randomBytes); err != nil {
+		return "", fmt.Errorf("error generating webhook secret: %w", err)
+	}
+	return WebhookSecretPrefix + base64.RawURLEncoding.EncodeToString(randomBytes), nil
+}
+
+// SecretSecrets returns the secret as secrets, so that it is encrypted and decrypted like the application secrets
+func (webhook Webhook) SecretSecrets() ApplicationSecrets {
+	return ApplicationSecrets{{Name: webhookSecretName, Val: webhook.Secret}}
+}
+
+// WithSecretSecrets returns the webhook with the secret of secrets returned by the secrets cipher
+func (webhook Webhook) WithSecretSecrets(secrets ApplicationSecrets) Webhook {
+	secret, _ := secrets.Find(webhookSecretName)
+	webhook.Secret = secret.Val
+	return webhook
+}
+
+// Subscribes tells if the webhook receives the events of the type
+func (webhook Webhook) Subscribes(eventType WebhookEventType) bool {
+	if !webhook.Active {
+		return false
+	}
+	for _, subscribedEventType := range webhook.EventTypes {
+		if subscribedEventType == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateWebhookURL checks that the events can be sent to the URL
+func ValidateWebhookURL(webhookURL string) error {
+	parsedURL, err := url.Parse(webhookURL)
+	if err != nil || (parsedURL.Scheme != "https" && parsedURL.Scheme != "http") || parsedURL.Host == "" {
+		return fmt.Errorf("url %s must be an absolute http or https URL", webhookURL)
+	}
+	if parsedURL.User != nil {
+		return fmt.Errorf("url %s must not contain credentials, the events are signed with the secret of the webhook", webhookURL)
+	}
+	return nil
+}
+
+// WebhookEvent is a struct that represents the JSON body sent to the webhooks subscribed to an event
+type WebhookEvent struct {
+	ID          string           `json:"id"`
+	Type        WebhookEventType `json:"type"`
+	NamespaceID string           `json:"namespaceId"`
+	OccurredAt  time.Time        `json:"occurredAt"`
+	Data        interface{}      `json:"data"`
+}
+
+// WebhookAuditEventData is the data of the events sent for the actions of the audit log
+type WebhookAuditEventData struct {
+	TargetType AuditTargetType `json:"targetType"`
+	TargetID   string          `json:"targetId"`
+	TargetName string          `json:"targetName"`
+	ActorID    string          `json:"actorId"`
+	Changes    AuditChanges    `json:"changes"`
+}
+
+// WebhookApplicationStatusData is the data of the events sent when the status of an application changes
+type WebhookApplicationStatusData struct {
+	ApplicationID   string                      `json:"applicationId"`
+	ApplicationName string                      `json:"applicationName"`
+	PreviousStatus  ApplicationDeploymentStatus `json:"previousStatus"`
+	Status          ApplicationDeploymentStatus `json:"status"`
+	HumanizedStatus string                      `json:"humanizedStatus"`
+}
+
+// NewWebhookEventFromAuditEvent returns the event to send for an action of the audit log, if the action is one webhooks can subscribe to and it succeeded
+func NewWebhookEventFromAuditEvent(auditEvent AuditEvent) (WebhookEvent, bool) {
+	eventType, ok := auditActionWebhookEventTypes[auditEvent.Action]
+	if !ok || auditEvent.Outcome != AuditSuccess || auditEvent.NamespaceID == "" {
+		return WebhookEvent{}, false
+	}
+	return WebhookEvent{
+		Type:        eventType,
+		NamespaceID: auditEvent.NamespaceID,
+		Data: WebhookAuditEventData{
+			TargetType: auditEvent.TargetType,
+			TargetID:   auditEvent.TargetID,
+			TargetName: auditEvent.TargetName,
+			ActorID:    auditEvent.ActorID,
+			Changes:    auditEvent.Changes,
+		},
+	}, true
+}
+
+// NewWebhookEventFromOperation returns the event to send when an operation finishes:
+// a deployment or an update that rolled out, or any operation that failed
+func NewWebhookEventFromOperation(operation Operation) (WebhookEvent, bool) {
+	event := WebhookEvent{NamespaceID: operation.NamespaceID, Data: operation}
+	switch {
+	case operation.Status == OperationFailed:
+		event.Type = WebhookApplicationFailed
+	case operation.Status == OperationSucceeded && (operation.Type == DeployOperation || operation.Type == UpdateOperation):
+		event.Type = WebhookApplicationDeployed
+	default:
+		return WebhookEvent{}, false
+	}
+	return event, true
+}
+
+// NewApplicationStatusChangedWebhookEvent returns the event to send when the computed status of an application changes
+func NewApplicationStatusChangedWebhookEvent(application Application, previousStatus ApplicationDeploymentStatus, status ApplicationStatus) WebhookEvent {
+	return WebhookEvent{
+		Type:        WebhookApplicationStatusChanged,
+		NamespaceID: application.NamespaceID,
+		Data: WebhookApplicationStatusData{
+			ApplicationID:   application.ID,
+			ApplicationName: application.Name,
+			PreviousStatus:  previousStatus,
+			Status:          *status.ComputedApplicationStatus,
+			HumanizedStatus: status.HumanizedStatus,
+		},
+	}
+}
+
+// Headers of the requests sent to the webhooks
+const (
+	WebhookEventHeader     = "X-Cloud-App-Hive-Event"
+	WebhookDeliveryHeader  = "X-Cloud-App-Hive-Delivery"
+	WebhookTimestampHeader = "X-Cloud-App-Hive-Timestamp"
+	WebhookSignatureHeader = "X-Cloud-App-Hive-Signature"
+)
+
+// SignWebhookPayload returns the signature of a payload sent at the timestamp: "sha256=" followed by the hex HMAC-SHA256 of "<timestamp>.<payload>".
+// The timestamp is signed so that endpoints can reject replayed events.
+func SignWebhookPayload(secret string, timestamp time.Time, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp.Unix(), 10) + "."))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// WebhookDeliveryHeaders returns the headers identifying and signing an attempt to deliver the payload of an event
+func WebhookDeliveryHeaders(secret string, event WebhookEvent, timestamp time.Time, payload []byte) map[string]string {
+	return map[string]string{
+		"Content-Type":         "application/json",
+		WebhookEventHeader:     string(event.Type),
+		WebhookDeliveryHeader:  event.ID,
+		WebhookTimestampHeader: strconv.FormatInt(timestamp.Unix(), 10),
+		WebhookSignatureHeader: SignWebhookPayload(secret, timestamp, payload),
+	}
+}
+
+// MaxWebhookDeliveryAttempts is the number of times an event is sent to a webhook before giving up
+const MaxWebhookDeliveryAttempts = 5
+
+// WebhookRetryDelay returns how long to wait after the failed attempt before sending the event again, doubling after each attempt
+func WebhookRetryDelay(baseDelay time.Duration, attempt int) time.Duration {
+	return baseDelay * time.Duration(1<<(attempt-1))
+}
+
+// WebhookDelivery is a struct that represents an attempt to send an event to a webhook, the delivery log keeps every attempt
+type WebhookDelivery struct {
+	ID          string           `json:"id" gorm:"primaryKey"`
+	WebhookID   string           `json:"webhookId" gorm:"size:255;index:idx_webhook_delivery_webhook_id_created_at,priority:1;not null"`
+	NamespaceID string           `json:"namespaceId" gorm:"size:255;not null"`
+	EventID     string           `json:"eventId" gorm:"size:255;not null"`
+	EventType   WebhookEventType `json:"eventType" gorm:"size:50;not null"`
+	Payload     string           `json:"payload" gorm:"type:mediumtext"`
+	Attempt     int              `json:"attempt" gorm:"not null"`
+	Succeeded   bool             `json:"succeeded" gorm:"not null"`
+	// StatusCode is the HTTP status answered by the endpoint, 0 when it did not answer
+	StatusCode int    `json:"statusCode"`
+	Error      string `json:"error,omitempty" gorm:"size:1000"`
+	Duration   int64  `json:"durationMs"`
+	// NextAttemptAt is when the event is sent again after this failed attempt, nil when it is not or once it has been sent again
+	NextAttemptAt *time.Time `json:"nextAttemptAt" gorm:"index:idx_webhook_delivery_next_attempt_at"`
+	CreatedAt     time.Time  `json:"createdAt" gorm:"index:idx_webhook_delivery_webhook_id_created_at,priority:2;not null"`
+}
@@ -3,9 +3,12 @@ package main
 import (
 	"cloud-app-hive/repositories"
 	"cloud-app-hive/schedulers"
+	"cloud-app-hive/services"
 	"cloud-app-hive/use_cases"
+	"cloud-app-hive/use_cases/access_tokens"
 	"cloud-app-hive/use_cases/applications"
 	"cloud-app-hive/use_cases/namespaces"
+	"cloud-app-hive/use_cases/operations"
 	"os"
 
 	"cloud-app-hive/config"
@@ -14,11 +17,13 @@ import (
 
 	"cloud-app-hive/docs"
 	"context"
+	"fmt"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
+	"gorm.io/gorm"
 )
 
 var _ = context.Background()
@@ -26,6 +31,12 @@ var _ = context.Background()
 func main() {
 	config.InitEnvironmentFile()
 
+	// "rotate-secret-keys" re-encrypts the application secrets with the current master key instead of serving the API
+	if len(os.Args) > 1 && os.Args[1] == "rotate-secret-keys" {
+		rotateSecretKeys()
+		return
+	}
+
 	router := gin.Default()
 	configApp := cors.DefaultConfig()
 	configApp.AllowAllOrigins = true
@@ -58,19 +69,123 @@ func initDependencies(router *gin.Engine) {
 		panic(err)
 	}
 
-	containerManagerRepository := repositories.KubernetesContainerManagerRepository{}
+	jwtService, err := services.NewJWTService()
+	if err != nil {
+		panic(err)
+	}
+
+	secretEncryptionService, err := newSecretEncryptionService(db)
+	if err != nil {
+		panic(err)
+	}
+
+	containerManagerRepository, err := repositories.NewContainerManagerRepository(secretEncryptionService, services.NewSecretResolverService())
+	if err != nil {
+		panic(err)
+	}
 
 	namespaceRepository := repositories.GORMNamespaceRepository{
 		Database: db,
 	}
 	applicationRepository := repositories.GORMApplicationRepository{
+		Database:      db,
+		SecretsCipher: secretEncryptionService,
+	}
+
+	// Audit dependencies
+	auditEventRepository := repositories.GORMAuditEventRepository{
+		Database: db,
+	}
+	// Webhook dependencies, the events are published from the audit log and the operations
+	webhookRepository := repositories.GORMWebhookRepository{
+		Database:      db,
+		SecretsCipher: secretEncryptionService,
+	}
+	webhookDeliveryRepository := repositories.GORMWebhookDeliveryRepository{
+		Database: db,
+	}
+	publishWebhookEventUseCase := &use_cases.PublishWebhookEventUseCase{
+		WebhookRepository:         webhookRepository,
+		WebhookDeliveryRepository: webhookDeliveryRepository,
+		WebhookSender:             services.NewHTTPWebhookSenderService(),
+		SecretsCipher:             secretEncryptionService,
+	}
+	recordAuditEventUseCase := use_cases.RecordAuditEventUseCase{
+		AuditEventRepository:       auditEventRepository,
+		PublishWebhookEventUseCase: publishWebhookEventUseCase,
+	}
+	networkRuleRepository := repositories.GORMNetworkRuleRepository{
 		Database: db,
 	}
+	registryCredentialRepository := repositories.GORMRegistryCredentialRepository{
+		Database:      db,
+		SecretsCipher: secretEncryptionService,
+	}
+	imageScanRepository := repositories.GORMImageScanRepository{
+		Database: db,
+	}
+	addonRepository := repositories.GORMAddonRepository{
+		Database:      db,
+		SecretsCipher: secretEncryptionService,
+	}
+	configGroupRepository := repositories.GORMConfigGroupRepository{
+		Database:      db,
+		SecretsCipher: secretEncryptionService,
+	}
+	scanApplicationImageUseCase := use_cases.ScanApplicationImageUseCase{
+		ImageScanRepository: imageScanRepository,
+		SecretsCipher:       secretEncryptionService,
+		MaxScanAge:          use_cases.ImageScanMaxAgeFromEnvironment(),
+	}
+	if imageScannerURL := os.Getenv("IMAGE_SCANNER_URL"); imageScannerURL != "" {
+		scanApplicationImageUseCase.ImageScanner = services.NewTrivyImageScannerService(imageScannerURL, os.Getenv("IMAGE_SCANNER_TOKEN"))
+	}
+	verifyApplicationImageUseCase := use_cases.VerifyApplicationImageUseCase{
+		SecretsCipher: secretEncryptionService,
+		Architectures: use_cases.ClusterArchitecturesFromEnvironment(),
+	}
+	if use_cases.ImageVerificationEnabledFromEnvironment() {
+		verifyApplicationImageUseCase.ImageRegistry = services.NewImageRegistryService()
+	}
+	applyNetworkRulesUseCase := use_cases.ApplyNetworkRulesUseCase{
+		NamespaceRepository:        namespaceRepository,
+		NetworkRuleRepository:      networkRuleRepository,
+		ContainerManagerRepository: containerManagerRepository,
+	}
+
+	// Operation dependencies
+	operationRepository := repositories.GORMOperationRepository{
+		Database: db,
+	}
+	startApplicationOperationUseCase := operations.StartApplicationOperationUseCase{
+		OperationRepository:        operationRepository,
+		ContainerManagerRepository: containerManagerRepository,
+		PublishWebhookEventUseCase: publishWebhookEventUseCase,
+	}
+	findOperationByIDUseCase := operations.FindOperationByIDUseCase{
+		OperationRepository: operationRepository,
+		NamespaceRepository: namespaceRepository,
+	}
+	// The operations left unfinished by the previous run of the API are failed before new ones start.
+	// Since the operations of every process are failed, and queued in the process, a single replica of the API is supported.
+	if err = (operations.FailInterruptedOperationsUseCase{OperationRepository: operationRepository}).Execute(); err != nil {
+		panic(err)
+	}
+	redeployApplicationUseCase := applications.RedeployApplicationUseCase{
+		ApplicationRepository:            applicationRepository,
+		ContainerManagerRepository:       containerManagerRepository,
+		StartApplicationOperationUseCase: startApplicationOperationUseCase,
+	}
+	refreshApplicationDependentsUseCase := applications.RefreshApplicationDependentsUseCase{
+		ApplicationRepository:      applicationRepository,
+		RedeployApplicationUseCase: redeployApplicationUseCase,
+	}
 
 	// Namespace dependencies
 
 	createNamespaceUseCase := namespaces.CreateNamespaceUseCase{
-		NamespaceRepository: namespaceRepository,
+		NamespaceRepository:     namespaceRepository,
+		RecordAuditEventUseCase: recordAuditEventUseCase,
 	}
 	findNamespaceByIDUseCase := namespaces.FindNamespaceByIDUseCase{
 		ApplicationRepository: applicationRepository,
@@ -82,9 +197,130 @@ func initDependencies(router *gin.Engine) {
 	deleteNamespaceByIDUseCase := namespaces.DeleteNamespaceByIDUseCase{
 		NamespaceRepository:        namespaceRepository,
 		ContainerManagerRepository: containerManagerRepository,
+		RecordAuditEventUseCase:    recordAuditEventUseCase,
 	}
 	updateNamespaceByIDUseCase := namespaces.UpdateNamespaceByIDUseCase{
+		NamespaceRepository:     namespaceRepository,
+		RecordAuditEventUseCase: recordAuditEventUseCase,
+	}
+	createNetworkRuleUseCase := namespaces.CreateNetworkRuleUseCase{
+		NamespaceRepository:      namespaceRepository,
+		NetworkRuleRepository:    networkRuleRepository,
+		ApplyNetworkRulesUseCase: applyNetworkRulesUseCase,
+		RecordAuditEventUseCase:  recordAuditEventUseCase,
+	}
+	findNetworkRulesUseCase := namespaces.FindNetworkRulesUseCase{
+		NamespaceRepository:   namespaceRepository,
+		NetworkRuleRepository: networkRuleRepository,
+	}
+	deleteNetworkRuleUseCase := namespaces.DeleteNetworkRuleUseCase{
+		NamespaceRepository:      namespaceRepository,
+		NetworkRuleRepository:    networkRuleRepository,
+		ApplyNetworkRulesUseCase: applyNetworkRulesUseCase,
+		RecordAuditEventUseCase:  recordAuditEventUseCase,
+	}
+	createRegistryCredentialUseCase := namespaces.CreateRegistryCredentialUseCase{
+		NamespaceRepository:          namespaceRepository,
+		RegistryCredentialRepository: registryCredentialRepository,
+		RecordAuditEventUseCase:      recordAuditEventUseCase,
+	}
+	findRegistryCredentialsUseCase := namespaces.FindRegistryCredentialsUseCase{
+		NamespaceRepository:          namespaceRepository,
+		RegistryCredentialRepository: registryCredentialRepository,
+	}
+	updateRegistryCredentialUseCase := namespaces.UpdateRegistryCredentialUseCase{
+		NamespaceRepository:          namespaceRepository,
+		RegistryCredentialRepository: registryCredentialRepository,
+		ApplicationRepository:        applicationRepository,
+		ContainerManagerRepository:   containerManagerRepository,
+		RecordAuditEventUseCase:      recordAuditEventUseCase,
+	}
+	updateImageScanPolicyUseCase := namespaces.UpdateImageScanPolicyUseCase{
+		NamespaceRepository:     namespaceRepository,
+		RecordAuditEventUseCase: recordAuditEventUseCase,
+	}
+	deleteRegistryCredentialUseCase := namespaces.DeleteRegistryCredentialUseCase{
+		NamespaceRepository:          namespaceRepository,
+		RegistryCredentialRepository: registryCredentialRepository,
+		RecordAuditEventUseCase:      recordAuditEventUseCase,
+	}
+	findNamespaceServicesUseCase := namespaces.FindNamespaceServicesUseCase{
+		NamespaceRepository:   namespaceRepository,
+		ApplicationRepository: applicationRepository,
+	}
+	createAddonUseCase := namespaces.CreateAddonUseCase{
+		NamespaceRepository:        namespaceRepository,
+		AddonRepository:            addonRepository,
+		ContainerManagerRepository: containerManagerRepository,
+		RecordAuditEventUseCase:    recordAuditEventUseCase,
+	}
+	findAddonsUseCase := namespaces.FindAddonsUseCase{
 		NamespaceRepository: namespaceRepository,
+		AddonRepository:     addonRepository,
+	}
+	deleteAddonUseCase := namespaces.DeleteAddonUseCase{
+		NamespaceRepository:        namespaceRepository,
+		AddonRepository:            addonRepository,
+		ContainerManagerRepository: containerManagerRepository,
+		RecordAuditEventUseCase:    recordAuditEventUseCase,
+	}
+	bindAddonUseCase := namespaces.BindAddonUseCase{
+		NamespaceRepository:        namespaceRepository,
+		AddonRepository:            addonRepository,
+		ApplicationRepository:      applicationRepository,
+		ContainerManagerRepository: containerManagerRepository,
+		RecordAuditEventUseCase:    recordAuditEventUseCase,
+	}
+	unbindAddonUseCase := namespaces.UnbindAddonUseCase{
+		NamespaceRepository:        namespaceRepository,
+		AddonRepository:            addonRepository,
+		ApplicationRepository:      applicationRepository,
+		ContainerManagerRepository: containerManagerRepository,
+		RecordAuditEventUseCase:    recordAuditEventUseCase,
+	}
+	createConfigGroupUseCase := namespaces.CreateConfigGroupUseCase{
+		NamespaceRepository:        namespaceRepository,
+		ConfigGroupRepository:      configGroupRepository,
+		ContainerManagerRepository: containerManagerRepository,
+		RecordAuditEventUseCase:    recordAuditEventUseCase,
+	}
+	findConfigGroupsUseCase := namespaces.FindConfigGroupsUseCase{
+		NamespaceRepository:   namespaceRepository,
+		ConfigGroupRepository: configGroupRepository,
+	}
+	updateConfigGroupUseCase := namespaces.UpdateConfigGroupUseCase{
+		NamespaceRepository:        namespaceRepository,
+		ConfigGroupRepository:      configGroupRepository,
+		ApplicationRepository:      applicationRepository,
+		ContainerManagerRepository: containerManagerRepository,
+		RedeployApplicationUseCase: redeployApplicationUseCase,
+		RecordAuditEventUseCase:    recordAuditEventUseCase,
+	}
+	deleteConfigGroupUseCase := namespaces.DeleteConfigGroupUseCase{
+		NamespaceRepository:        namespaceRepository,
+		ConfigGroupRepository:      configGroupRepository,
+		ContainerManagerRepository: containerManagerRepository,
+		RecordAuditEventUseCase:    recordAuditEventUseCase,
+	}
+	createWebhookUseCase := namespaces.CreateWebhookUseCase{
+		NamespaceRepository:     namespaceRepository,
+		WebhookRepository:       webhookRepository,
+		RecordAuditEventUseCase: recordAuditEventUseCase,
+	}
+	findWebhooksUseCase := namespaces.FindWebhooksUseCase{
+		NamespaceRepository:       namespaceRepository,
+		WebhookRepository:         webhookRepository,
+		WebhookDeliveryRepository: webhookDeliveryRepository,
+	}
+	updateWebhookUseCase := namespaces.UpdateWebhookUseCase{
+		NamespaceRepository:     namespaceRepository,
+		WebhookRepository:       webhookRepository,
+		RecordAuditEventUseCase: recordAuditEventUseCase,
+	}
+	deleteWebhookUseCase := namespaces.DeleteWebhookUseCase{
+		NamespaceRepository:     namespaceRepository,
+		WebhookRepository:       webhookRepository,
+		RecordAuditEventUseCase: recordAuditEventUseCase,
 	}
 
 	// Application dependencies
@@ -95,21 +331,39 @@ func initDependencies(router *gin.Engine) {
 		ApplicationRepository: applicationRepository,
 	}
 	createApplicationUseCase := applications.CreateApplicationUseCase{
-		ApplicationRepository: applicationRepository,
-		NamespaceRepository:   namespaceRepository,
+		ApplicationRepository:         applicationRepository,
+		NamespaceRepository:           namespaceRepository,
+		RegistryCredentialRepository:  registryCredentialRepository,
+		VerifyApplicationImageUseCase: verifyApplicationImageUseCase,
+		ScanApplicationImageUseCase:   scanApplicationImageUseCase,
+		RecordAuditEventUseCase:       recordAuditEventUseCase,
 	}
 	updateApplicationUseCase := applications.UpdateApplicationUseCase{
+		ApplicationRepository:         applicationRepository,
+		NamespaceRepository:           namespaceRepository,
+		RegistryCredentialRepository:  registryCredentialRepository,
+		VerifyApplicationImageUseCase: verifyApplicationImageUseCase,
+		ScanApplicationImageUseCase:   scanApplicationImageUseCase,
+		RecordAuditEventUseCase:       recordAuditEventUseCase,
+	}
+	findApplicationImageScanUseCase := applications.FindApplicationImageScanUseCase{
 		ApplicationRepository: applicationRepository,
-		NamespaceRepository:   namespaceRepository,
+		ImageScanRepository:   imageScanRepository,
 	}
 	deleteApplicationUseCase := applications.DeleteApplicationUseCase{
-		ApplicationRepository: applicationRepository,
+		ApplicationRepository:   applicationRepository,
+		RecordAuditEventUseCase: recordAuditEventUseCase,
 	}
 	deployApplicationUseCase := applications.DeployApplicationUseCase{
-		ContainerManagerRepository: containerManagerRepository,
+		ContainerManagerRepository:          containerManagerRepository,
+		ApplyNetworkRulesUseCase:            applyNetworkRulesUseCase,
+		RefreshApplicationDependentsUseCase: refreshApplicationDependentsUseCase,
+		RecordAuditEventUseCase:             recordAuditEventUseCase,
 	}
 	undeployApplicationUseCase := applications.UndeployApplicationUseCase{
-		ContainerManagerRepository: containerManagerRepository,
+		ContainerManagerRepository:          containerManagerRepository,
+		RefreshApplicationDependentsUseCase: refreshApplicationDependentsUseCase,
+		RecordAuditEventUseCase:             recordAuditEventUseCase,
 	}
 	getApplicationLogsUseCase := applications.GetApplicationLogsUseCase{
 		ContainerManagerRepository: containerManagerRepository,
@@ -120,9 +374,36 @@ func initDependencies(router *gin.Engine) {
 	getApplicationStatusUseCase := applications.GetApplicationStatusUseCase{
 		ContainerManagerRepository: containerManagerRepository,
 	}
+	getApplicationEventsUseCase := applications.GetApplicationEventsUseCase{
+		ContainerManagerRepository: containerManagerRepository,
+	}
 	fillApplicationsStatusUseCase := applications.FillApplicationStatusUseCase{
 		ContainerManagerRepository: containerManagerRepository,
 	}
+	dryRunApplicationUseCase := applications.DryRunApplicationUseCase{
+		ContainerManagerRepository: containerManagerRepository,
+	}
+	scaleApplicationUseCase := applications.ScaleApplicationUseCase{
+		ApplicationRepository:   applicationRepository,
+		ContainerManager:        containerManagerRepository,
+		RecordAuditEventUseCase: recordAuditEventUseCase,
+	}
+	setApplicationSecretUseCase := applications.SetApplicationSecretUseCase{
+		ApplicationRepository:      applicationRepository,
+		ContainerManagerRepository: containerManagerRepository,
+		RecordAuditEventUseCase:    recordAuditEventUseCase,
+	}
+	setApplicationConfigGroupsUseCase := applications.SetApplicationConfigGroupsUseCase{
+		ApplicationRepository:      applicationRepository,
+		ConfigGroupRepository:      configGroupRepository,
+		ContainerManagerRepository: containerManagerRepository,
+		RecordAuditEventUseCase:    recordAuditEventUseCase,
+	}
+	deleteApplicationSecretUseCase := applications.DeleteApplicationSecretUseCase{
+		ApplicationRepository:      applicationRepository,
+		ContainerManagerRepository: containerManagerRepository,
+		RecordAuditEventUseCase:    recordAuditEventUseCase,
+	}
 
 	// Namespace membership dependencies
 	memoryNamespaceMembershipRepository := repositories.GORMNamespaceMembershipRepository{
@@ -130,10 +411,88 @@ func initDependencies(router *gin.Engine) {
 	}
 	createNamespaceMembershipUseCase := namespaces.CreateNamespaceMembershipUseCase{
 		NamespaceMembershipRepository: memoryNamespaceMembershipRepository,
+		NamespaceRepository:           namespaceRepository,
+		RecordAuditEventUseCase:       recordAuditEventUseCase,
 	}
 	removeNamespaceMembershipUseCase := namespaces.RemoveNamespaceMembershipUseCase{
 		NamespaceMembershipRepository: memoryNamespaceMembershipRepository,
 		NamespaceRepository:           namespaceRepository,
+		RecordAuditEventUseCase:       recordAuditEventUseCase,
+	}
+
+	updateNamespaceMembershipUseCase := namespaces.UpdateNamespaceMembershipUseCase{
+		NamespaceMembershipRepository: memoryNamespaceMembershipRepository,
+		NamespaceRepository:           namespaceRepository,
+		RecordAuditEventUseCase:       recordAuditEventUseCase,
+	}
+
+	// Namespace ownership dependencies
+	transferNamespaceOwnershipUseCase := namespaces.TransferNamespaceOwnershipUseCase{
+		NamespaceRepository:     namespaceRepository,
+		RecordAuditEventUseCase: recordAuditEventUseCase,
+	}
+	acceptNamespaceOwnershipTransferUseCase := namespaces.AcceptNamespaceOwnershipTransferUseCase{
+		NamespaceRepository:     namespaceRepository,
+		RecordAuditEventUseCase: recordAuditEventUseCase,
+	}
+	cancelNamespaceOwnershipTransferUseCase := namespaces.CancelNamespaceOwnershipTransferUseCase{
+		NamespaceRepository:     namespaceRepository,
+		RecordAuditEventUseCase: recordAuditEventUseCase,
+	}
+
+	// Namespace invitation dependencies
+	namespaceInvitationRepository := repositories.GORMNamespaceInvitationRepository{
+		Database: db,
+	}
+	createNamespaceInvitationUseCase := namespaces.CreateNamespaceInvitationUseCase{
+		NamespaceRepository:           namespaceRepository,
+		NamespaceInvitationRepository: namespaceInvitationRepository,
+		EmailSender:                   services.NewEmailService(),
+		AcceptURL:                     os.Getenv("NAMESPACE_INVITATION_ACCEPT_URL"),
+		RecordAuditEventUseCase:       recordAuditEventUseCase,
+	}
+	findNamespaceInvitationsUseCase := namespaces.FindNamespaceInvitationsUseCase{
+		NamespaceRepository:           namespaceRepository,
+		NamespaceInvitationRepository: namespaceInvitationRepository,
+	}
+	revokeNamespaceInvitationUseCase := namespaces.RevokeNamespaceInvitationUseCase{
+		NamespaceRepository:           namespaceRepository,
+		NamespaceInvitationRepository: namespaceInvitationRepository,
+		RecordAuditEventUseCase:       recordAuditEventUseCase,
+	}
+	acceptNamespaceInvitationUseCase := namespaces.AcceptNamespaceInvitationUseCase{
+		NamespaceRepository:           namespaceRepository,
+		NamespaceInvitationRepository: namespaceInvitationRepository,
+		NamespaceMembershipRepository: memoryNamespaceMembershipRepository,
+		RecordAuditEventUseCase:       recordAuditEventUseCase,
+	}
+	declineNamespaceInvitationUseCase := namespaces.DeclineNamespaceInvitationUseCase{
+		NamespaceInvitationRepository: namespaceInvitationRepository,
+		RecordAuditEventUseCase:       recordAuditEventUseCase,
+	}
+
+	// Namespace manifest dependencies
+	exportNamespaceManifestUseCase := namespaces.ExportNamespaceManifestUseCase{
+		NamespaceRepository:   namespaceRepository,
+		ApplicationRepository: applicationRepository,
+	}
+	applyNamespaceManifestUseCase := namespaces.ApplyNamespaceManifestUseCase{
+		NamespaceRepository:              namespaceRepository,
+		ApplicationRepository:            applicationRepository,
+		RegistryCredentialRepository:     registryCredentialRepository,
+		CreateApplicationUseCase:         createApplicationUseCase,
+		UpdateApplicationUseCase:         updateApplicationUseCase,
+		DeleteApplicationUseCase:         deleteApplicationUseCase,
+		DeployApplicationUseCase:         deployApplicationUseCase,
+		UndeployApplicationUseCase:       undeployApplicationUseCase,
+		StartApplicationOperationUseCase: startApplicationOperationUseCase,
+		RecordAuditEventUseCase:          recordAuditEventUseCase,
+	}
+
+	// Audit log dependencies
+	findAuditEventsUseCase := namespaces.FindAuditEventsUseCase{
+		NamespaceRepository:  namespaceRepository,
+		AuditEventRepository: auditEventRepository,
 	}
 
 	// Cluster dependencies
@@ -141,8 +500,44 @@ func initDependencies(router *gin.Engine) {
 		ContainerManagerRepository: containerManagerRepository,
 	}
 
+	// Access token dependencies
+	accessTokenRepository := repositories.GORMAccessTokenRepository{
+		Database: db,
+	}
+	authenticateAccessTokenUseCase := access_tokens.AuthenticateAccessTokenUseCase{
+		AccessTokenRepository: accessTokenRepository,
+	}
+	createAccessTokenUseCase := access_tokens.CreateAccessTokenUseCase{
+		AccessTokenRepository: accessTokenRepository,
+		NamespaceRepository:   namespaceRepository,
+	}
+	findAccessTokensUseCase := access_tokens.FindAccessTokensUseCase{
+		AccessTokenRepository: accessTokenRepository,
+	}
+	findAccessTokenByIDUseCase := access_tokens.FindAccessTokenByIDUseCase{
+		AccessTokenRepository: accessTokenRepository,
+	}
+	revokeAccessTokenUseCase := access_tokens.RevokeAccessTokenUseCase{
+		AccessTokenRepository: accessTokenRepository,
+	}
+
+	rateLimitCounterRepository, err := repositories.NewRateLimitCounterRepository(db)
+	if err != nil {
+		panic(err)
+	}
+	rateLimitPolicy, err := use_cases.RateLimitPolicyFromEnvironment()
+	if err != nil {
+		panic(err)
+	}
+	checkRateLimitUseCase := use_cases.CheckRateLimitUseCase{
+		RateLimitCounterRepository: rateLimitCounterRepository,
+		RateLimitPolicy:            rateLimitPolicy,
+	}
+
 	controllers.InitRoutes(
 		router,
+		jwtService,
+		authenticateAccessTokenUseCase,
 		createNamespaceUseCase,
 		findNamespaceByIDUseCase,
 		findNamespacesUseCase,
@@ -162,7 +557,114 @@ func initDependencies(router *gin.Engine) {
 		deleteNamespaceByIDUseCase,
 		updateNamespaceByIDUseCase,
 		getClusterMetricsUseCase,
+		exportNamespaceManifestUseCase,
+		applyNamespaceManifestUseCase,
+		dryRunApplicationUseCase,
+		scaleApplicationUseCase,
+		createAccessTokenUseCase,
+		findAccessTokensUseCase,
+		findAccessTokenByIDUseCase,
+		revokeAccessTokenUseCase,
+		findAuditEventsUseCase,
+		setApplicationSecretUseCase,
+		deleteApplicationSecretUseCase,
+		createNamespaceInvitationUseCase,
+		findNamespaceInvitationsUseCase,
+		revokeNamespaceInvitationUseCase,
+		acceptNamespaceInvitationUseCase,
+		declineNamespaceInvitationUseCase,
+		updateNamespaceMembershipUseCase,
+		transferNamespaceOwnershipUseCase,
+		acceptNamespaceOwnershipTransferUseCase,
+		cancelNamespaceOwnershipTransferUseCase,
+		checkRateLimitUseCase,
+		createNetworkRuleUseCase,
+		findNetworkRulesUseCase,
+		deleteNetworkRuleUseCase,
+		createRegistryCredentialUseCase,
+		findRegistryCredentialsUseCase,
+		updateRegistryCredentialUseCase,
+		deleteRegistryCredentialUseCase,
+		updateImageScanPolicyUseCase,
+		findApplicationImageScanUseCase,
+		findNamespaceServicesUseCase,
+		createAddonUseCase,
+		findAddonsUseCase,
+		deleteAddonUseCase,
+		bindAddonUseCase,
+		unbindAddonUseCase,
+		createConfigGroupUseCase,
+		findConfigGroupsUseCase,
+		updateConfigGroupUseCase,
+		deleteConfigGroupUseCase,
+		setApplicationConfigGroupsUseCase,
+		startApplicationOperationUseCase,
+		findOperationByIDUseCase,
+		createWebhookUseCase,
+		findWebhooksUseCase,
+		updateWebhookUseCase,
+		deleteWebhookUseCase,
+		getApplicationEventsUseCase,
 	)
 
-	schedulers.InitSchedulers()
+	schedulers.InitSchedulers(containerManagerRepository, secretEncryptionService)
+}
+
+func newSecretEncryptionService(db *gorm.DB) (services.SecretEncryptionService, error) {
+	masterKeyProvider, err := services.NewMasterKeyProvider()
+	if err != nil {
+		return services.SecretEncryptionService{}, err
+	}
+	return services.SecretEncryptionService{
+		MasterKeyProvider: masterKeyProvider,
+		NamespaceDataKeyRepository: repositories.GORMNamespaceDataKeyRepository{
+			Database: db,
+		},
+	}, nil
+}
+
+// rotateSecretKeys re-encrypts every application secret, registry credential password and webhook secret with a new data key wrapped by the current master key
+func rotateSecretKeys() {
+	db, err := database.ConnectToDatabase()
+	if err != nil {
+		panic(err)
+	}
+
+	if err = database.MigrateDatabase(db); err != nil {
+		panic(err)
+	}
+
+	secretEncryptionService, err := newSecretEncryptionService(db)
+	if err != nil {
+		panic(err)
+	}
+
+	rotateSecretKeysUseCase := use_cases.RotateSecretKeysUseCase{
+		ApplicationRepository: repositories.GORMApplicationRepository{
+			Database:      db,
+			SecretsCipher: secretEncryptionService,
+		},
+		RegistryCredentialRepository: repositories.GORMRegistryCredentialRepository{
+			Database:      db,
+			SecretsCipher: secretEncryptionService,
+		},
+		WebhookRepository: repositories.GORMWebhookRepository{
+			Database:      db,
+			SecretsCipher: secretEncryptionService,
+		},
+		AddonRepository: repositories.GORMAddonRepository{
+			Database:      db,
+			SecretsCipher: secretEncryptionService,
+		},
+		ConfigGroupRepository: repositories.GORMConfigGroupRepository{
+			Database:      db,
+			SecretsCipher: secretEncryptionService,
+		},
+		SecretsCipher: secretEncryptionService,
+	}
+	rotatedApplications, err := rotateSecretKeysUseCase.Execute()
+	if err != nil {
+		panic(err)
+	}
+	fmt.Printf("Secrets of %d applications re-encrypted with master key %s\n", rotatedApplications, secretEncryptionService.MasterKeyProvider.CurrentKeyID())
 }
@@ -0,0 +1,25 @@
+package repositories
+
+import (
+	domainRepositories "cloud-app-hive/domain/repositories"
+	"fmt"
+	"os"
+)
+
+// NewContainerManagerRepository returns the container manager selected by the CONTAINER_MANAGER environment variable:
+// "kubernetes" (the default) or "memory" to simulate a cluster without any Kubernetes API
+func NewContainerManagerRepository(secretsCipher domainRepositories.SecretsCipher, secretResolver domainRepositories.SecretResolver) (domainRepositories.ContainerManagerRepository, error) {
+	switch containerManager := os.Getenv("CONTAINER_MANAGER"); containerManager {
+	case "", "kubernetes":
+		return KubernetesContainerManagerRepository{SecretsCipher: secretsCipher, SecretResolver: secretResolver}, nil
+	case "memory":
+		config, err := MemoryContainerManagerConfigFromEnvironment()
+		if err != nil {
+			return nil, err
+		}
+		fmt.Println("Using the in-memory container manager, no application is deployed on a cluster")
+		return NewMemoryContainerManagerRepository(config), nil
+	default:
+		return nil, fmt.Errorf("unknown CONTAINER_MANAGER %s, expected kubernetes or memory", containerManager)
+	}
+}
@@ -0,0 +1,93 @@
+package repositories
+
+import (
+	"cloud-app-hive/domain"
+	"cloud-app-hive/domain/commands"
+	"fmt"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"time"
+)
+
+type GORMAccessTokenRepository struct {
+	Database *gorm.DB
+}
+
+// Create creates a new access token
+func (r GORMAccessTokenRepository) Create(createAccessToken commands.CreateAccessToken) (*domain.AccessToken, error) {
+	accessToken := domain.AccessToken{
+		ID:          uuid.New().String(),
+		Name:        createAccessToken.Name,
+		UserID:      createAccessToken.UserID,
+		SecretHash:  createAccessToken.SecretHash,
+		SecretStart: createAccessToken.SecretStart,
+		Scopes:      createAccessToken.Scopes,
+		NamespaceID: createAccessToken.NamespaceID,
+		ExpiresAt:   createAccessToken.ExpiresAt,
+	}
+	result := r.Database.Create(&accessToken)
+	if result.Error != nil {
+		return nil, fmt.Errorf("error creating access token: %w", result.Error)
+	}
+	return &accessToken, nil
+}
+
+// FindByID returns an access token by its ID
+func (r GORMAccessTokenRepository) FindByID(id string) (*domain.AccessToken, error) {
+	accessToken := domain.AccessToken{}
+	result := r.Database.Limit(1).Find(&accessToken, domain.AccessToken{
+		ID: id,
+	})
+	if result.Error != nil {
+		return nil, fmt.Errorf("error finding access token: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+	return &accessToken, nil
+}
+
+// FindBySecretHash returns the access token of a secret
+func (r GORMAccessTokenRepository) FindBySecretHash(secretHash string) (*domain.AccessToken, error) {
+	accessToken := domain.AccessToken{}
+	result := r.Database.Limit(1).Find(&accessToken, domain.AccessToken{
+		SecretHash: secretHash,
+	})
+	if result.Error != nil {
+		return nil, fmt.Errorf("error finding access token: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+	return &accessToken, nil
+}
+
+// FindByUserID returns the access tokens of a user
+func (r GORMAccessTokenRepository) FindByUserID(userID string) ([]domain.AccessToken, error) {
+	accessTokens := []domain.AccessToken{}
+	result := r.Database.Order("created_at desc").Find(&accessTokens, domain.AccessToken{
+		UserID: userID,
+	})
+	if result.Error != nil {
+		return nil, fmt.Errorf("error finding access tokens: %w", result.Error)
+	}
+	return accessTokens, nil
+}
+
+// Revoke revokes an access token
+func (r GORMAccessTokenRepository) Revoke(id string, revokedAt time.Time) (*domain.AccessToken, error) {
+	result := r.Database.Model(&domain.AccessToken{}).Where("id = ?", id).Update("revoked_at", revokedAt)
+	if result.Error != nil {
+		return nil, fmt.Errorf("error revoking access token: %w", result.Error)
+	}
+	return r.FindByID(id)
+}
+
+// UpdateLastUsedAt records the last use of an access token
+func (r GORMAccessTokenRepository) UpdateLastUsedAt(id string, lastUsedAt time.Time) error {
+	result := r.Database.Model(&domain.AccessToken{}).Where("id = ?", id).Update("last_used_at", lastUsedAt)
+	if result.Error != nil {
+		return fmt.Errorf("error updating access token last use: %w", result.Error)
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package repositories
+
+import (
+	"cloud-app-hive/domain"
+	domainRepositories "cloud-app-hive/domain/repositories"
+	"fmt"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type GORMAddonRepository struct {
+	Database *gorm.DB
+	// SecretsCipher encrypts the credentials before they are stored
+	SecretsCipher domainRepositories.SecretsCipher
+}
+
+// Create creates a new add-on
+func (r GORMAddonRepository) Create(addon domain.Addon) (*domain.Addon, error) {
+	addon.ID = uuid.New().String()
+	if addon.Credentials != nil {
+		encryptedCredentials, err := r.SecretsCipher.Encrypt(addon.NamespaceID, *addon.Credentials)
+		if err != nil {
+			return nil, fmt.Errorf("error encrypting credentials of addon %s: %w", addon.Name, err)
+		}
+		addon.Credentials = &encryptedCredentials
+	}
+	result := r.Database.Omit("Bindings").Create(&addon)
+	if result.Error != nil {
+		return nil, fmt.Errorf("error creating addon: %w", result.Error)
+	}
+	return &addon, nil
+}
+
+// FindByID returns an add-on with its bindings by its ID
+func (r GORMAddonRepository) FindByID(id string) (*domain.Addon, error) {
+	addon := domain.Addon{}
+	result := r.Database.Preload("Bindings").Limit(1).Find(&addon, domain.Addon{
+		ID: id,
+	})
+	if result.Error != nil {
+		return nil, fmt.Errorf("error finding addon: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+	return &addon, nil
+}
+
+// FindByNamespaceID returns the add-ons of a namespace with their bindings, sorted by name
+func (r GORMAddonRepository) FindByNamespaceID(namespaceID string) ([]domain.Addon, error) {
+	var addons []domain.Addon
+	result := r.Database.Preload("Bindings").Order("name").Find(&addons, domain.Addon{
+		NamespaceID: namespaceID,
+	})
+	if result.Error != nil {
+		return nil, fmt.Errorf("error finding addons: %w", result.Error)
+	}
+	return addons, nil
+}
+
+// FindAll returns the add-ons of every namespace, without their bindings
+func (r GORMAddonRepository) FindAll() ([]domain.Addon, error) {
+	var addons []domain.Addon
+	result := r.Database.Find(&addons)
+	if result.Error != nil {
+		return nil, fmt.Errorf("error finding addons: %w", result.Error)
+	}
+	return addons, nil
+}
+
+// UpdateCredentials encrypts and stores the credentials of an add-on
+func (r GORMAddonRepository) UpdateCredentials(addonID string, namespaceID string, credentials domain.ApplicationSecrets) error {
+	encryptedCredentials, err := r.SecretsCipher.Encrypt(namespaceID, credentials)
+	if err != nil {
+		return fmt.Errorf("error encrypting credentials of addon %s: %w", addonID, err)
+	}
+	result := r.Database.Model(&domain.Addon{ID: addonID}).Update("credentials", &encryptedCredentials)
+	if result.Error != nil {
+		return fmt.Errorf("error updating credentials of addon %s: %w", addonID, result.Error)
+	}
+	return nil
+}
+
+// Delete deletes an add-on
+func (r GORMAddonRepository) Delete(id string) error {
+	result := r.Database.Delete(&domain.Addon{}, "id = ?", id)
+	if result.Error != nil {
+		return fmt.Errorf("error deleting addon: %w", result.Error)
+	}
+	return nil
+}
+
+// CreateBinding binds an add-on to an application
+func (r GORMAddonRepository) CreateBinding(addonBinding domain.AddonBinding) (*domain.AddonBinding, error) {
+	addonBinding.ID = uuid.New().String()
+	result := r.Database.Omit("Addon").Create(&addonBinding)
+	if result.Error != nil {
+		return nil, fmt.Errorf("error creating addon binding: %w", result.Error)
+	}
+	return &addonBinding, nil
+}
+
+// DeleteBinding unbinds an add-on from an application
+func (r GORMAddonRepository) DeleteBinding(id string) error {
+	result := r.Database.Delete(&domain.AddonBinding{}, "id = ?", id)
+	if result.Error != nil {
+		return fmt.Errorf("error deleting addon binding: %w", result.Error)
+	}
+	return nil
+}
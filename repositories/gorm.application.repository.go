@@ -3,7 +3,7 @@ package repositories
 import (
 	"cloud-app-hive/domain"
 	"cloud-app-hive/domain/commands"
-	"cloud-app-hive/domain/errors"
+	domainRepositories "cloud-app-hive/domain/repositories"
 	"encoding/json"
 	"fmt"
 
@@ -14,6 +14,8 @@ import (
 
 type GORMApplicationRepository struct {
 	Database *gorm.DB
+	// SecretsCipher encrypts the secret values before they are stored
+	SecretsCipher domainRepositories.SecretsCipher
 }
 
 // FindApplications returns a list of applications
@@ -23,6 +25,12 @@ func (r GORMApplicationRepository) FindApplications(findApplications commands.Fi
 	if findApplications.NamespaceID != nil {
 		query = query.Where("namespace_id = ?", findApplications.NamespaceID)
 	}
+	if findApplications.MemberUserID != nil {
+		query = query.Where(
+			"namespace_id IN (?)",
+			r.Database.Model(&domain.NamespaceMembership{}).Select("namespace_id").Where("user_id = ?", findApplications.MemberUserID),
+		)
+	}
 	if findApplications.Name != nil {
 		query = query.Where("name = ?", findApplications.Name)
 	}
@@ -50,7 +58,7 @@ func (r GORMApplicationRepository) FindApplications(findApplications commands.Fi
 // FindByID returns an application by its ID
 func (r GORMApplicationRepository) FindByID(id string) (*domain.Application, error) {
 	app := &domain.Application{}
-	result := r.Database.Preload("Namespace").Preload("Namespace.Memberships").Limit(1).Find(&app, domain.Application{
+	result := r.Database.Preload("Namespace").Preload("Namespace.Memberships").Preload("RegistryCredential").Preload("AddonBindings.Addon").Preload("ConfigGroupAttachments.ConfigGroup").Limit(1).Find(&app, domain.Application{
 		ID: id,
 	})
 
@@ -108,31 +116,18 @@ func (r GORMApplicationRepository) FindByNamespaceIDAndName(namespaceID string,
 
 // Create creates a new application
 func (r GORMApplicationRepository) Create(createApplication commands.CreateApplication) (*domain.Application, error) {
-	containerSpecs := datatypes.NewJSONType(createApplication.ContainerSpecifications)
-	scalabilitySpecs := datatypes.NewJSONType(createApplication.ScalabilitySpecifications)
-	app := domain.Application{
-		ID:                      uuid.New().String(),
-		Name:                    createApplication.Name,
-		Description:             createApplication.Description,
-		Image:                   createApplication.Image,
-		Registry:                createApplication.Registry,
-		UserID:                  createApplication.UserID,
-		NamespaceID:             createApplication.NamespaceID,
-		Port:                    createApplication.Port,
-		Zone:                    createApplication.Zone,
-		ApplicationType:         createApplication.ApplicationType,
-		EnvironmentVariables:    &createApplication.EnvironmentVariables,
-		Secrets:                 &createApplication.Secrets,
-		ContainerSpecifications: &containerSpecs,
-		// ScalabilitySpecifications: &createApplication.ScalabilitySpecifications,
-		// repositories/gorm.application.repository.go:272:34: cannot use scalabilitySpecifications (variable of type *domain.ApplicationScalabilitySpecifications) as *datatypes.JSONType[domain.ApplicationScalabilitySpecifications] value in assignment
-		ScalabilitySpecifications: &scalabilitySpecs,
-		AdministratorEmail:        createApplication.AdministratorEmail,
-	}
-	result := r.Database.Create(&app)
+	app := createApplication.Application()
+	app.ID = uuid.New().String()
+	if err := r.encryptSecrets(&app); err != nil {
+		return nil, err
+	}
+	result := r.Database.Omit("AddonBindings", "ConfigGroupAttachments").Create(&app)
 	if result.Error != nil {
 		return nil, fmt.Errorf("error while creating application: %v", result.Error)
 	}
+	if err := r.loadRegistryCredential(&app); err != nil {
+		return nil, err
+	}
 	return &app, nil
 }
 
@@ -140,7 +135,7 @@ func (r GORMApplicationRepository) Create(createApplication commands.CreateAppli
 func (r GORMApplicationRepository) Update(applicationID string, application commands.UpdateApplication) (*domain.Application, error) {
 	app := domain.Application{}
 	// Also retrieve namespace linked to application
-	queryResult := r.Database.Preload("Namespace").Limit(1).Find(&app, domain.Application{
+	queryResult := r.Database.Preload("Namespace").Preload("AddonBindings.Addon").Preload("ConfigGroupAttachments.ConfigGroup").Limit(1).Find(&app, domain.Application{
 		ID: applicationID,
 	})
 	if queryResult.Error != nil {
@@ -149,26 +144,131 @@ func (r GORMApplicationRepository) Update(applicationID string, application comm
 	if queryResult.RowsAffected == 0 {
 		return nil, fmt.Errorf("application with ID %s not found", applicationID)
 	}
-	app.Description = application.Description
-	app.Image = application.Image
-	app.Registry = application.Registry
-	app.Port = application.Port
-	app.ApplicationType = application.ApplicationType
-	app.EnvironmentVariables = &application.EnvironmentVariables
-	app.Secrets = &application.Secrets
-	containerSpecs := datatypes.NewJSONType(application.ContainerSpecifications)
-	app.ContainerSpecifications = &containerSpecs
-	scalabilitySpecs := datatypes.NewJSONType(application.ScalabilitySpecifications)
-	app.ScalabilitySpecifications = &scalabilitySpecs
-	app.AdministratorEmail = application.AdministratorEmail
+	application.ApplyTo(&app)
+	if err := r.encryptSecrets(&app); err != nil {
+		return nil, err
+	}
 
-	saveResult := r.Database.Save(&app)
+	saveResult := r.Database.Omit("RegistryCredential", "AddonBindings", "ConfigGroupAttachments").Save(&app)
 	if saveResult.Error != nil {
 		return nil, saveResult.Error
 	}
+	if err := r.loadRegistryCredential(&app); err != nil {
+		return nil, err
+	}
 	return &app, nil
 }
 
+// FindAll returns every application, with its namespace
+func (r GORMApplicationRepository) FindAll() ([]domain.Application, error) {
+	var applications []domain.Application
+	result := r.Database.Preload("Namespace").Find(&applications)
+	if result.Error != nil {
+		return nil, fmt.Errorf("error finding applications: %w", result.Error)
+	}
+	return applications, nil
+}
+
+// FindWithSecrets returns every application that has secrets or secret config files, with its namespace
+func (r GORMApplicationRepository) FindWithSecrets() ([]domain.Application, error) {
+	var applications []domain.Application
+	result := r.Database.Preload("Namespace").Preload("AddonBindings.Addon").Preload("ConfigGroupAttachments.ConfigGroup").Where("(secrets IS NOT NULL AND JSON_LENGTH(secrets) > 0) OR (config_files IS NOT NULL AND JSON_LENGTH(config_files) > 0)").Find(&applications)
+	if result.Error != nil {
+		return nil, fmt.Errorf("error finding applications with secrets: %w", result.Error)
+	}
+	// Config files are only kept when one of them is secret
+	applicationsWithSecrets := []domain.Application{}
+	for _, application := range applications {
+		hasSecrets := application.Secrets != nil && len(*application.Secrets) > 0
+		hasSecretConfigFiles := application.ConfigFiles != nil && len(application.ConfigFiles.SecretContents()) > 0
+		if hasSecrets || hasSecretConfigFiles {
+			applicationsWithSecrets = append(applicationsWithSecrets, application)
+		}
+	}
+	return fillApplicationsJSON(applicationsWithSecrets, r)
+}
+
+// UpdateSecrets encrypts and stores the secrets of an application, without changing its other fields
+func (r GORMApplicationRepository) UpdateSecrets(applicationID string, namespaceID string, secrets domain.ApplicationSecrets) error {
+	encryptedSecrets, err := r.SecretsCipher.Encrypt(namespaceID, secrets)
+	if err != nil {
+		return fmt.Errorf("error encrypting secrets of application %s: %w", applicationID, err)
+	}
+	result := r.Database.Model(&domain.Application{ID: applicationID}).Update("secrets", &encryptedSecrets)
+	if result.Error != nil {
+		return fmt.Errorf("error updating secrets of application %s: %w", applicationID, result.Error)
+	}
+	return nil
+}
+
+// UpdateConfigFiles encrypts the contents of the secret config files and stores the config files of an application, without changing its other fields
+func (r GORMApplicationRepository) UpdateConfigFiles(applicationID string, namespaceID string, configFiles domain.ApplicationConfigFiles) error {
+	encryptedContents, err := r.SecretsCipher.Encrypt(namespaceID, configFiles.SecretContents())
+	if err != nil {
+		return fmt.Errorf("error encrypting config files of application %s: %w", applicationID, err)
+	}
+	encryptedConfigFiles := configFiles.WithSecretContents(encryptedContents)
+	result := r.Database.Model(&domain.Application{ID: applicationID}).Update("config_files", &encryptedConfigFiles)
+	if result.Error != nil {
+		return fmt.Errorf("error updating config files of application %s: %w", applicationID, result.Error)
+	}
+	return nil
+}
+
+func (r GORMApplicationRepository) UpdateDependencies(applicationID string, dependencies domain.ApplicationDependencies) error {
+	result := r.Database.Model(&domain.Application{ID: applicationID}).Update("dependencies", &dependencies)
+	if result.Error != nil {
+		return fmt.Errorf("error updating dependencies of application %s: %w", applicationID, result.Error)
+	}
+	return nil
+}
+
+// loadRegistryCredential sets the registry credential of an application once it is stored, so that it can be deployed
+func (r GORMApplicationRepository) loadRegistryCredential(app *domain.Application) error {
+	app.RegistryCredential = nil
+	if app.RegistryCredentialID == nil {
+		return nil
+	}
+	registryCredential := domain.RegistryCredential{}
+	result := r.Database.Limit(1).Find(&registryCredential, domain.RegistryCredential{
+		ID: *app.RegistryCredentialID,
+	})
+	if result.Error != nil {
+		return fmt.Errorf("error finding registry credential of application %s: %w", app.Name, result.Error)
+	}
+	if result.RowsAffected > 0 {
+		app.RegistryCredential = &registryCredential
+	}
+	return nil
+}
+
+// encryptSecrets replaces the secret values and the secret config files of the application with their encryption, before it is stored
+func (r GORMApplicationRepository) encryptSecrets(app *domain.Application) error {
+	if app.Secrets == nil {
+		return r.encryptConfigFiles(app)
+	}
+	encryptedSecrets, err := r.SecretsCipher.Encrypt(app.NamespaceID, *app.Secrets)
+	if err != nil {
+		return fmt.Errorf("error encrypting secrets of application %s: %w", app.Name, err)
+	}
+	app.Secrets = &encryptedSecrets
+	return r.encryptConfigFiles(app)
+}
+
+// encryptConfigFiles replaces the contents of the secret config files of the application with their encryption, before it is stored
+func (r GORMApplicationRepository) encryptConfigFiles(app *domain.Application) error {
+	if app.ConfigFiles == nil {
+		return nil
+	}
+	encryptedContents, err := r.SecretsCipher.Encrypt(app.NamespaceID, app.ConfigFiles.SecretContents())
+	if err != nil {
+		return fmt.Errorf("error encrypting config files of application %s: %w", app.Name, err)
+	}
+	configFiles := app.ConfigFiles.WithSecretContents(encryptedContents)
+	app.ConfigFiles = &configFiles
+	return nil
+}
+
 // Delete deletes an application
 func (r GORMApplicationRepository) Delete(id string) (*domain.Application, error) {
 	app := domain.Application{}
@@ -182,6 +282,15 @@ func (r GORMApplicationRepository) Delete(id string) (*domain.Application, error
 		return nil, fmt.Errorf("application with ID %s not found while deleting", id)
 	}
 
+	// The add-ons bound to the application and its config groups are kept, they are only unbound
+	bindingsResult := r.Database.Delete(&domain.AddonBinding{}, "application_id = ?", id)
+	if bindingsResult.Error != nil {
+		return nil, fmt.Errorf("error deleting addon bindings of application: %w", bindingsResult.Error)
+	}
+	attachmentsResult := r.Database.Delete(&domain.ConfigGroupAttachment{}, "application_id = ?", id)
+	if attachmentsResult.Error != nil {
+		return nil, fmt.Errorf("error deleting config group attachments of application: %w", attachmentsResult.Error)
+	}
 	result := r.Database.Delete(&app)
 	if result.Error != nil {
 		return nil, fmt.Errorf("error deleting application: %w", result.Error)
@@ -194,6 +303,12 @@ func (r GORMApplicationRepository) FindManualScalingApplications() ([]domain.App
 	var applications []domain.Application
 	result := r.Database.Preload(
 		"Namespace",
+	).Preload(
+		"RegistryCredential",
+	).Preload(
+		"AddonBindings.Addon",
+	).Preload(
+		"ConfigGroupAttachments.ConfigGroup",
 	).Where(
 		"JSON_EXTRACT(scalability_specifications, '$.isAutoScaled') = false",
 	).Find(&applications, domain.Application{
@@ -216,6 +331,12 @@ func (r GORMApplicationRepository) FindAutoScalingApplications() ([]domain.Appli
 	var applications []domain.Application
 	result := r.Database.Preload(
 		"Namespace",
+	).Preload(
+		"RegistryCredential",
+	).Preload(
+		"AddonBindings.Addon",
+	).Preload(
+		"ConfigGroupAttachments.ConfigGroup",
 	).Where(
 		"JSON_EXTRACT(scalability_specifications, '$.isAutoScaled') = true",
 	).Find(&applications, domain.Application{
@@ -238,16 +359,22 @@ func fillApplicationJSONFields(app *domain.Application, r GORMApplicationReposit
 	var scalabilitySpecificationsJSON string
 	var environmentVariablesJSON string
 	var secretsJSON string
+	var ingressJSON string
+	var dependenciesJSON string
 
 	r.Database.Table("applications").Where("id = ?", app.ID).Limit(1).Pluck("container_specifications", &containerSpecificationsJSON)
 	r.Database.Table("applications").Where("id = ?", app.ID).Limit(1).Pluck("scalability_specifications", &scalabilitySpecificationsJSON)
 	r.Database.Table("applications").Where("id = ?", app.ID).Limit(1).Pluck("environment_variables", &environmentVariablesJSON)
 	r.Database.Table("applications").Where("id = ?", app.ID).Limit(1).Pluck("secrets", &secretsJSON)
+	r.Database.Table("applications").Where("id = ?", app.ID).Limit(1).Pluck("ingress", &ingressJSON)
+	r.Database.Table("applications").Where("id = ?", app.ID).Limit(1).Pluck("dependencies", &dependenciesJSON)
 
 	var containerSpecifications *domain.ApplicationContainerSpecifications
 	var scalabilitySpecifications *domain.ApplicationScalabilitySpecifications
 	var environmentVariables *domain.ApplicationEnvironmentVariables
 	var secrets *domain.ApplicationSecrets
+	var ingress *domain.ApplicationIngress
+	var dependencies *domain.ApplicationDependencies
 
 	if containerSpecificationsJSON != "" && containerSpecificationsJSON != "null" {
 		err := json.Unmarshal([]byte(containerSpecificationsJSON), &containerSpecifications)
@@ -273,6 +400,18 @@ func fillApplicationJSONFields(app *domain.Application, r GORMApplicationReposit
 			return nil, err
 		}
 	}
+	if ingressJSON != "" && ingressJSON != "null" {
+		err := json.Unmarshal([]byte(ingressJSON), &ingress)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if dependenciesJSON != "" && dependenciesJSON != "null" {
+		err := json.Unmarshal([]byte(dependenciesJSON), &dependencies)
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	containerSpecs := datatypes.NewJSONType(*containerSpecifications)
 	app.ContainerSpecifications = &containerSpecs
@@ -280,6 +419,8 @@ func fillApplicationJSONFields(app *domain.Application, r GORMApplicationReposit
 	app.ScalabilitySpecifications = &scalabilitySpecs
 	app.EnvironmentVariables = environmentVariables
 	app.Secrets = secrets
+	app.Ingress = ingress
+	app.Dependencies = dependencies
 
 	return app, nil
 }
@@ -308,18 +449,11 @@ func (r GORMApplicationRepository) HorizontalScaleUp(applicationID string) (*dom
 		return nil, fmt.Errorf("application not found with ID %s", applicationID)
 	}
 
-	newNumberOfReplicas := app.ScalabilitySpecifications.Data().Replicas + 1
-
-	if newNumberOfReplicas > domain.MaxNumberOfReplicas {
-		return nil, fmt.Errorf("application is already at maximum number of replicas")
+	scaledScalabilitySpecifications, err := app.ScalabilitySpecifications.Data().HorizontallyScaledUp()
+	if err != nil {
+		return nil, err
 	}
-
-	scalabilitySpecs := datatypes.NewJSONType(domain.ApplicationScalabilitySpecifications{
-		Replicas:                       newNumberOfReplicas,
-		IsAutoScaled:                   app.ScalabilitySpecifications.Data().IsAutoScaled,
-		CpuUsagePercentageThreshold:    app.ScalabilitySpecifications.Data().CpuUsagePercentageThreshold,
-		MemoryUsagePercentageThreshold: app.ScalabilitySpecifications.Data().MemoryUsagePercentageThreshold,
-	})
+	scalabilitySpecs := datatypes.NewJSONType(scaledScalabilitySpecifications)
 
 	scalabilitySpecsJSON, err := json.Marshal(scalabilitySpecs)
 	if err != nil {
@@ -330,6 +464,7 @@ func (r GORMApplicationRepository) HorizontalScaleUp(applicationID string) (*dom
 	if result.Error != nil {
 		return nil, fmt.Errorf("error while updating application: %w", result.Error)
 	}
+	app.ScalabilitySpecifications = &scalabilitySpecs
 
 	app, err = fillApplicationJSONFields(app, r)
 
@@ -349,18 +484,11 @@ func (r GORMApplicationRepository) HorizontalScaleDown(applicationID string) (*d
 		return nil, fmt.Errorf("application not found with ID %s", applicationID)
 	}
 
-	newNumberOfReplicas := app.ScalabilitySpecifications.Data().Replicas - 1
-
-	if newNumberOfReplicas <= 0 {
-		return nil, fmt.Errorf("application is already at minimum number of replicas")
+	scaledScalabilitySpecifications, err := app.ScalabilitySpecifications.Data().HorizontallyScaledDown()
+	if err != nil {
+		return nil, err
 	}
-
-	scalabilitySpecs := datatypes.NewJSONType(domain.ApplicationScalabilitySpecifications{
-		Replicas:                       newNumberOfReplicas,
-		IsAutoScaled:                   app.ScalabilitySpecifications.Data().IsAutoScaled,
-		CpuUsagePercentageThreshold:    app.ScalabilitySpecifications.Data().CpuUsagePercentageThreshold,
-		MemoryUsagePercentageThreshold: app.ScalabilitySpecifications.Data().MemoryUsagePercentageThreshold,
-	})
+	scalabilitySpecs := datatypes.NewJSONType(scaledScalabilitySpecifications)
 
 	scalabilitySpecsJSON, err := json.Marshal(scalabilitySpecs)
 	if err != nil {
@@ -371,6 +499,7 @@ func (r GORMApplicationRepository) HorizontalScaleDown(applicationID string) (*d
 	if result.Error != nil {
 		return nil, fmt.Errorf("error while updating application: %w", result.Error)
 	}
+	app.ScalabilitySpecifications = &scalabilitySpecs
 
 	return &app, nil
 }
@@ -388,28 +517,11 @@ func (r GORMApplicationRepository) VerticalScaleUp(applicationID string) (*domai
 		return nil, fmt.Errorf("application not found with ID %s", applicationID)
 	}
 
-	if err := app.ContainerSpecifications.Data().Validate(); err != nil {
-		return nil, fmt.Errorf("error while validating container specifications when scaling up: %w", err)
-	}
-
-	if domain.IsAtMaxCPULimit(*app.ContainerSpecifications.Data().CPULimit) && domain.IsAtMaxMemoryLimit(*app.ContainerSpecifications.Data().MemoryLimit) {
-		return nil, errors.NewInvalidApplicationCannotVerticallyScaleBecauseMaxSpecsError(
-			"application is already at maximum cpu and memory limits",
-		)
+	scaledContainerSpecifications, err := app.ContainerSpecifications.Data().VerticallyScaledUp()
+	if err != nil {
+		return nil, err
 	}
-	newContainerCPULimit := domain.NextCPULimit(*app.ContainerSpecifications.Data().CPULimit)
-	newContainerMemoryLimit := domain.NextMemoryLimit(*app.ContainerSpecifications.Data().MemoryLimit)
-
-	containerSpecs := datatypes.NewJSONType(domain.ApplicationContainerSpecifications{
-		CPULimit: &domain.ContainerCpuLimit{
-			Val:  newContainerCPULimit.Value,
-			Unit: newContainerCPULimit.Unit,
-		},
-		MemoryLimit: &domain.ContainerMemoryLimit{
-			Val:  newContainerMemoryLimit.Value,
-			Unit: newContainerMemoryLimit.Unit,
-		},
-	})
+	containerSpecs := datatypes.NewJSONType(scaledContainerSpecifications)
 	containerSpecsJSON, err := json.Marshal(containerSpecs)
 
 	if err != nil {
@@ -420,6 +532,7 @@ func (r GORMApplicationRepository) VerticalScaleUp(applicationID string) (*domai
 	if result.Error != nil {
 		return nil, fmt.Errorf("error while updating application: %w", result.Error)
 	}
+	app.ContainerSpecifications = &containerSpecs
 
 	return &app, nil
 }
@@ -0,0 +1,59 @@
+package repositories
+
+import (
+	"cloud-app-hive/domain"
+	"cloud-app-hive/domain/commands"
+	"fmt"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"time"
+)
+
+// GORMAuditEventRepository only inserts and reads audit events, it has no way to update nor delete them
+type GORMAuditEventRepository struct {
+	Database *gorm.DB
+}
+
+// Create appends an event to the audit log
+func (r GORMAuditEventRepository) Create(auditEvent domain.AuditEvent) error {
+	auditEvent.ID = uuid.New().String()
+	auditEvent.CreatedAt = time.Now().UTC()
+	result := r.Database.Create(&auditEvent)
+	if result.Error != nil {
+		return fmt.Errorf("error creating audit event: %w", result.Error)
+	}
+	return nil
+}
+
+// Find returns the events of a namespace matching the filters, the most recent first
+func (r GORMAuditEventRepository) Find(findAuditEvents commands.FindAuditEvents) ([]domain.AuditEvent, error) {
+	auditEvents := []domain.AuditEvent{}
+	query := r.Database.Where("namespace_id = ?", findAuditEvents.NamespaceID)
+	if findAuditEvents.Action != nil {
+		query = query.Where("action = ?", *findAuditEvents.Action)
+	}
+	if findAuditEvents.ActorID != nil {
+		query = query.Where("actor_id = ?", *findAuditEvents.ActorID)
+	}
+	if findAuditEvents.TargetID != nil {
+		query = query.Where("target_id = ?", *findAuditEvents.TargetID)
+	}
+	if findAuditEvents.Outcome != nil {
+		query = query.Where("outcome = ?", *findAuditEvents.Outcome)
+	}
+	if findAuditEvents.From != nil {
+		query = query.Where("created_at >= ?", *findAuditEvents.From)
+	}
+	if findAuditEvents.To != nil {
+		query = query.Where("created_at < ?", *findAuditEvents.To)
+	}
+	if findAuditEvents.Limit > 0 {
+		query = query.Limit(int(findAuditEvents.Limit)).Offset(int((findAuditEvents.Page - 1) * findAuditEvents.Limit))
+	}
+
+	result := query.Order("created_at desc").Order("id").Find(&auditEvents)
+	if result.Error != nil {
+		return nil, fmt.Errorf("error finding audit events: %w", result.Error)
+	}
+	return auditEvents, nil
+}
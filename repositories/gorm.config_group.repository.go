@@ -0,0 +1,115 @@
+package repositories
+
+import (
+	"cloud-app-hive/domain"
+	domainRepositories "cloud-app-hive/domain/repositories"
+	"fmt"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type GORMConfigGroupRepository struct {
+	Database *gorm.DB
+	// SecretsCipher encrypts the secrets of the config groups before they are stored
+	SecretsCipher domainRepositories.SecretsCipher
+}
+
+func (r GORMConfigGroupRepository) encryptSecrets(configGroup *domain.ConfigGroup) error {
+	if configGroup.Secrets == nil {
+		return nil
+	}
+	encryptedSecrets, err := r.SecretsCipher.Encrypt(configGroup.NamespaceID, *configGroup.Secrets)
+	if err != nil {
+		return fmt.Errorf("error encrypting secrets of config group %s: %w", configGroup.Name, err)
+	}
+	configGroup.Secrets = &encryptedSecrets
+	return nil
+}
+
+// Create creates a new config group
+func (r GORMConfigGroupRepository) Create(configGroup domain.ConfigGroup) (*domain.ConfigGroup, error) {
+	configGroup.ID = uuid.New().String()
+	if err := r.encryptSecrets(&configGroup); err != nil {
+		return nil, err
+	}
+	result := r.Database.Omit("Attachments").Create(&configGroup)
+	if result.Error != nil {
+		return nil, fmt.Errorf("error creating config group: %w", result.Error)
+	}
+	return &configGroup, nil
+}
+
+// FindByID returns a config group with its attachments by its ID
+func (r GORMConfigGroupRepository) FindByID(id string) (*domain.ConfigGroup, error) {
+	configGroup := domain.ConfigGroup{}
+	result := r.Database.Preload("Attachments").Limit(1).Find(&configGroup, domain.ConfigGroup{
+		ID: id,
+	})
+	if result.Error != nil {
+		return nil, fmt.Errorf("error finding config group: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+	return &configGroup, nil
+}
+
+// FindByNamespaceID returns the config groups of a namespace with their attachments, sorted by name
+func (r GORMConfigGroupRepository) FindByNamespaceID(namespaceID string) ([]domain.ConfigGroup, error) {
+	var configGroups []domain.ConfigGroup
+	result := r.Database.Preload("Attachments").Order("name").Find(&configGroups, domain.ConfigGroup{
+		NamespaceID: namespaceID,
+	})
+	if result.Error != nil {
+		return nil, fmt.Errorf("error finding config groups: %w", result.Error)
+	}
+	return configGroups, nil
+}
+
+// FindAll returns the config groups of every namespace, without their attachments
+func (r GORMConfigGroupRepository) FindAll() ([]domain.ConfigGroup, error) {
+	var configGroups []domain.ConfigGroup
+	result := r.Database.Find(&configGroups)
+	if result.Error != nil {
+		return nil, fmt.Errorf("error finding config groups: %w", result.Error)
+	}
+	return configGroups, nil
+}
+
+// Update updates the values and the version of a config group
+func (r GORMConfigGroupRepository) Update(configGroup domain.ConfigGroup) (*domain.ConfigGroup, error) {
+	if err := r.encryptSecrets(&configGroup); err != nil {
+		return nil, err
+	}
+	result := r.Database.Model(&domain.ConfigGroup{ID: configGroup.ID}).Select("Description", "EnvironmentVariables", "Secrets", "Version").Updates(&configGroup)
+	if result.Error != nil {
+		return nil, fmt.Errorf("error updating config group: %w", result.Error)
+	}
+	return &configGroup, nil
+}
+
+// Delete deletes a config group
+func (r GORMConfigGroupRepository) Delete(id string) error {
+	result := r.Database.Delete(&domain.ConfigGroup{}, "id = ?", id)
+	if result.Error != nil {
+		return fmt.Errorf("error deleting config group: %w", result.Error)
+	}
+	return nil
+}
+
+// ReplaceAttachments replaces the config groups an application is attached to, in a transaction
+func (r GORMConfigGroupRepository) ReplaceAttachments(applicationID string, attachments []domain.ConfigGroupAttachment) error {
+	return r.Database.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&domain.ConfigGroupAttachment{}, "application_id = ?", applicationID).Error; err != nil {
+			return fmt.Errorf("error deleting config group attachments: %w", err)
+		}
+		for _, attachment := range attachments {
+			attachment.ID = uuid.New().String()
+			attachment.ApplicationID = applicationID
+			if err := tx.Omit("ConfigGroup").Create(&attachment).Error; err != nil {
+				return fmt.Errorf("error creating config group attachment: %w", err)
+			}
+		}
+		return nil
+	})
+}
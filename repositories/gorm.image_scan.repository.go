@@ -0,0 +1,50 @@
+package repositories
+
+import (
+	"cloud-app-hive/domain"
+	"fmt"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type GORMImageScanRepository struct {
+	Database *gorm.DB
+}
+
+// Create stores the scan of an image digest, the scan already stored is returned if the digest was scanned concurrently
+func (r GORMImageScanRepository) Create(imageScan domain.ImageScan) (*domain.ImageScan, error) {
+	imageScan.ID = uuid.New().String()
+	result := r.Database.Clauses(clause.OnConflict{DoNothing: true}).Create(&imageScan)
+	if result.Error != nil {
+		return nil, fmt.Errorf("error creating image scan: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return r.FindByImageDigest(imageScan.ImageDigest)
+	}
+	return &imageScan, nil
+}
+
+// Update stores the vulnerabilities found when scanning an image digest again
+func (r GORMImageScanRepository) Update(imageScan domain.ImageScan) error {
+	result := r.Database.Model(&domain.ImageScan{ID: imageScan.ID}).Select("Vulnerabilities", "ScannedAt").Updates(&imageScan)
+	if result.Error != nil {
+		return fmt.Errorf("error updating image scan: %w", result.Error)
+	}
+	return nil
+}
+
+// FindByImageDigest returns the scan of an image digest
+func (r GORMImageScanRepository) FindByImageDigest(imageDigest string) (*domain.ImageScan, error) {
+	imageScan := domain.ImageScan{}
+	result := r.Database.Limit(1).Find(&imageScan, domain.ImageScan{
+		ImageDigest: imageDigest,
+	})
+	if result.Error != nil {
+		return nil, fmt.Errorf("error finding image scan: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+	return &imageScan, nil
+}
@@ -4,6 +4,7 @@ import (
 	"cloud-app-hive/domain"
 	"cloud-app-hive/domain/commands"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
@@ -97,7 +98,7 @@ func (r GORMNamespaceRepository) Create(createNamespace commands.CreateNamespace
 			ID:          uuid.New().String(),
 			UserID:      namespace.UserID,
 			NamespaceID: namespace.ID,
-			Role:        domain.RoleAdmin,
+			Role:        domain.RoleOwner,
 		}
 		result = tx.Create(&membership)
 		if result.Error != nil {
@@ -131,15 +132,8 @@ func (r GORMNamespaceRepository) Delete(id string, userId string) (*domain.Names
 		return nil, fmt.Errorf("namespace with ID %s not found", id)
 	}
 
-	// check if user is admin
-	isAdmin := false
-	for _, membership := range namespace.Memberships {
-		if membership.UserID == userId && membership.Role == domain.RoleAdmin {
-			isAdmin = true
-		}
-	}
-	if !isAdmin {
-		return nil, fmt.Errorf("user is not admin of namespace %s", id)
+	if err := namespace.Authorize(userId, domain.PermissionDeleteNamespace); err != nil {
+		return nil, err
 	}
 
 	result := r.Database.Delete(&namespace)
@@ -195,7 +189,6 @@ func (r GORMNamespaceRepository) Update(updateNamespace commands.UpdateNamespace
 	}
 
 	namespace.Description = updateNamespace.Description
-	namespace.UserID = updateNamespace.UserID
 
 	result := r.Database.Save(&namespace)
 	if result.Error != nil {
@@ -203,3 +196,69 @@ func (r GORMNamespaceRepository) Update(updateNamespace commands.UpdateNamespace
 	}
 	return &namespace, nil
 }
+
+// SetPendingOwner records the member a namespace is being transferred to, a nil pending owner cancels the transfer
+func (r GORMNamespaceRepository) SetPendingOwner(namespaceID string, pendingOwnerID *string, expiresAt *time.Time) error {
+	result := r.Database.Model(&domain.Namespace{}).Where("id = ?", namespaceID).Updates(map[string]interface{}{
+		"pending_owner_id":              pendingOwnerID,
+		"ownership_transfer_expires_at": expiresAt,
+	})
+	if result.Error != nil {
+		return fmt.Errorf("error updating namespace pending owner: %w", result.Error)
+	}
+	return nil
+}
+
+// SetImageScanPolicy stores the image scan policy of a namespace and the most severe vulnerabilities it allows
+func (r GORMNamespaceRepository) SetImageScanPolicy(namespaceID string, policy domain.ImageScanPolicy, maxSeverity domain.VulnerabilitySeverity) error {
+	result := r.Database.Model(&domain.Namespace{}).Where("id = ?", namespaceID).Updates(map[string]interface{}{
+		"image_scan_policy":                policy,
+		"max_image_vulnerability_severity": maxSeverity,
+	})
+	if result.Error != nil {
+		return fmt.Errorf("error updating namespace image scan policy: %w", result.Error)
+	}
+	return nil
+}
+
+// TransferOwnership makes the member the owner of the namespace and the previous owner an admin
+func (r GORMNamespaceRepository) TransferOwnership(namespaceID string, newOwnerID string) (*domain.Namespace, error) {
+	err := r.Database.Transaction(func(tx *gorm.DB) error {
+		namespace := domain.Namespace{}
+		result := tx.Limit(1).Find(&namespace, domain.Namespace{
+			ID: namespaceID,
+		})
+		if result.Error != nil {
+			return fmt.Errorf("error finding namespace: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("namespace with ID %s not found", namespaceID)
+		}
+		previousOwnerID := namespace.UserID
+
+		result = tx.Model(&domain.Namespace{}).Where("id = ?", namespaceID).Updates(map[string]interface{}{
+			"user_id":                       newOwnerID,
+			"pending_owner_id":              nil,
+			"ownership_transfer_expires_at": nil,
+		})
+		if result.Error != nil {
+			return fmt.Errorf("error transferring namespace ownership: %w", result.Error)
+		}
+		result = tx.Model(&domain.NamespaceMembership{}).Where("namespace_id = ? AND user_id = ?", namespaceID, newOwnerID).Update("role", domain.RoleOwner)
+		if result.Error != nil {
+			return fmt.Errorf("error updating new owner membership: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("user %s is not a member of the namespace %s", newOwnerID, namespaceID)
+		}
+		result = tx.Model(&domain.NamespaceMembership{}).Where("namespace_id = ? AND user_id = ?", namespaceID, previousOwnerID).Update("role", domain.RoleAdmin)
+		if result.Error != nil {
+			return fmt.Errorf("error updating previous owner membership: %w", result.Error)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r.FindByID(namespaceID)
+}
@@ -0,0 +1,69 @@
+package repositories
+
+import (
+	"cloud-app-hive/domain"
+	"fmt"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type GORMNamespaceDataKeyRepository struct {
+	Database *gorm.DB
+}
+
+// Create stores a new data key
+func (r GORMNamespaceDataKeyRepository) Create(namespaceDataKey domain.NamespaceDataKey) (*domain.NamespaceDataKey, error) {
+	namespaceDataKey.ID = uuid.New().String()
+	result := r.Database.Create(&namespaceDataKey)
+	if result.Error != nil {
+		return nil, fmt.Errorf("error creating namespace data key: %w", result.Error)
+	}
+	return &namespaceDataKey, nil
+}
+
+// FindByID returns a data key by its ID
+func (r GORMNamespaceDataKeyRepository) FindByID(id string) (*domain.NamespaceDataKey, error) {
+	namespaceDataKey := domain.NamespaceDataKey{}
+	result := r.Database.Limit(1).Find(&namespaceDataKey, domain.NamespaceDataKey{
+		ID: id,
+	})
+	if result.Error != nil {
+		return nil, fmt.Errorf("error finding namespace data key: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+	return &namespaceDataKey, nil
+}
+
+// FindLatestByNamespaceID returns the most recent data key of a namespace
+func (r GORMNamespaceDataKeyRepository) FindLatestByNamespaceID(namespaceID string) (*domain.NamespaceDataKey, error) {
+	namespaceDataKey := domain.NamespaceDataKey{}
+	result := r.Database.Where("namespace_id = ?", namespaceID).Order("created_at desc").Limit(1).Find(&namespaceDataKey)
+	if result.Error != nil {
+		return nil, fmt.Errorf("error finding namespace data key: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+	return &namespaceDataKey, nil
+}
+
+// FindAll returns every data key, the most recent first
+func (r GORMNamespaceDataKeyRepository) FindAll() ([]domain.NamespaceDataKey, error) {
+	namespaceDataKeys := []domain.NamespaceDataKey{}
+	result := r.Database.Order("created_at desc").Find(&namespaceDataKeys)
+	if result.Error != nil {
+		return nil, fmt.Errorf("error finding namespace data keys: %w", result.Error)
+	}
+	return namespaceDataKeys, nil
+}
+
+// Delete deletes a data key
+func (r GORMNamespaceDataKeyRepository) Delete(id string) error {
+	result := r.Database.Delete(&domain.NamespaceDataKey{}, "id = ?", id)
+	if result.Error != nil {
+		return fmt.Errorf("error deleting namespace data key: %w", result.Error)
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package repositories
+
+import (
+	"cloud-app-hive/domain"
+	"cloud-app-hive/domain/commands"
+	"fmt"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"time"
+)
+
+type GORMNamespaceInvitationRepository struct {
+	Database *gorm.DB
+}
+
+// Create creates a new pending namespace invitation
+func (r GORMNamespaceInvitationRepository) Create(createNamespaceInvitation commands.CreateNamespaceInvitation) (*domain.NamespaceInvitation, error) {
+	namespaceInvitation := domain.NamespaceInvitation{
+		ID:          uuid.New().String(),
+		NamespaceID: createNamespaceInvitation.NamespaceID,
+		Email:       createNamespaceInvitation.Email,
+		Role:        createNamespaceInvitation.Role,
+		TokenHash:   createNamespaceInvitation.TokenHash,
+		InvitedBy:   createNamespaceInvitation.InvitedBy,
+		Status:      domain.NamespaceInvitationPending,
+		ExpiresAt:   createNamespaceInvitation.ExpiresAt,
+	}
+	result := r.Database.Create(&namespaceInvitation)
+	if result.Error != nil {
+		return nil, fmt.Errorf("error creating namespace invitation: %w", result.Error)
+	}
+	return &namespaceInvitation, nil
+}
+
+// FindByID returns a namespace invitation by its ID
+func (r GORMNamespaceInvitationRepository) FindByID(id string) (*domain.NamespaceInvitation, error) {
+	namespaceInvitation := domain.NamespaceInvitation{}
+	result := r.Database.Limit(1).Find(&namespaceInvitation, domain.NamespaceInvitation{
+		ID: id,
+	})
+	if result.Error != nil {
+		return nil, fmt.Errorf("error finding namespace invitation: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+	return &namespaceInvitation, nil
+}
+
+// FindByTokenHash returns the namespace invitation of a token
+func (r GORMNamespaceInvitationRepository) FindByTokenHash(tokenHash string) (*domain.NamespaceInvitation, error) {
+	namespaceInvitation := domain.NamespaceInvitation{}
+	result := r.Database.Limit(1).Find(&namespaceInvitation, domain.NamespaceInvitation{
+		TokenHash: tokenHash,
+	})
+	if result.Error != nil {
+		return nil, fmt.Errorf("error finding namespace invitation: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+	return &namespaceInvitation, nil
+}
+
+// FindPendingByNamespaceID returns the invitations of a namespace that are still pending at a given time
+func (r GORMNamespaceInvitationRepository) FindPendingByNamespaceID(namespaceID string, now time.Time) ([]domain.NamespaceInvitation, error) {
+	namespaceInvitations := []domain.NamespaceInvitation{}
+	result := r.Database.Where("expires_at > ?", now).Order("created_at desc").Find(&namespaceInvitations, domain.NamespaceInvitation{
+		NamespaceID: namespaceID,
+		Status:      domain.NamespaceInvitationPending,
+	})
+	if result.Error != nil {
+		return nil, fmt.Errorf("error finding namespace invitations: %w", result.Error)
+	}
+	return namespaceInvitations, nil
+}
+
+// UpdateStatus changes the status of a pending invitation and returns false if the invitation was no longer pending.
+// The status is checked by the update itself, so that an invitation cannot be both accepted and revoked.
+func (r GORMNamespaceInvitationRepository) UpdateStatus(id string, status domain.NamespaceInvitationStatus, respondedBy *string, updatedAt time.Time) (bool, error) {
+	updates := map[string]interface{}{"status": status}
+	if respondedBy != nil {
+		updates["responded_by"] = *respondedBy
+		updates["responded_at"] = updatedAt
+	}
+	result := r.Database.Model(&domain.NamespaceInvitation{}).
+		Where("id = ? AND status = ?", id, domain.NamespaceInvitationPending).
+		Updates(updates)
+	if result.Error != nil {
+		return false, fmt.Errorf("error updating namespace invitation: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
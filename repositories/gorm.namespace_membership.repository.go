@@ -80,15 +80,8 @@ func (r GORMNamespaceMembershipRepository) Create(createNamespaceMembership comm
 		return nil, fmt.Errorf("namespace not found with ID %s", createNamespaceMembership.NamespaceID)
 	}
 
-	isAuthorized := false
-	for _, namespaceMembership := range namespace.Memberships {
-		if namespaceMembership.UserID == createNamespaceMembership.AddedBy && namespaceMembership.Role == domain.RoleAdmin {
-			isAuthorized = true
-		}
-	}
-
-	if !isAuthorized {
-		return nil, fmt.Errorf("user %s is not authorized to add users to namespace %s", createNamespaceMembership.AddedBy, createNamespaceMembership.NamespaceID)
+	if err := namespace.Authorize(createNamespaceMembership.AddedBy, domain.PermissionManageNamespaceMembers); err != nil {
+		return nil, err
 	}
 
 	namespaceMembershipModel := domain.NamespaceMembership{
@@ -143,13 +136,12 @@ func (r GORMNamespaceMembershipRepository) IsAdminInNamespace(userID string, nam
 	if result.RowsAffected == 0 {
 		return false, errors.NewUnauthorizedToAccessNamespaceError(namespaceID, "", userID)
 	}
-	return namespaceMembership.Role == domain.RoleAdmin, nil
+	return namespaceMembership.Role == domain.RoleOwner || namespaceMembership.Role == domain.RoleAdmin, nil
 }
 
 // Update updates a namespace membership
 func (r GORMNamespaceMembershipRepository) Update(namespaceMembership commands.UpdateNamespaceMembership) (*domain.NamespaceMembership, error) {
-	namespaceMembershipModel := domain.NamespaceMembership{}
-	result := r.Database.Model(&namespaceMembershipModel).Where(domain.NamespaceMembership{
+	result := r.Database.Model(&domain.NamespaceMembership{}).Where(domain.NamespaceMembership{
 		UserID:      namespaceMembership.UserID,
 		NamespaceID: namespaceMembership.NamespaceID,
 	}).Updates(domain.NamespaceMembership{
@@ -159,5 +151,5 @@ func (r GORMNamespaceMembershipRepository) Update(namespaceMembership commands.U
 	if result.Error != nil {
 		return nil, fmt.Errorf("error updating namespace membership: %w", result.Error)
 	}
-	return &namespaceMembershipModel, nil
+	return r.FindByUserIDAndNamespaceID(namespaceMembership.UserID, namespaceMembership.NamespaceID)
 }
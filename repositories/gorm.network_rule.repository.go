@@ -0,0 +1,67 @@
+package repositories
+
+import (
+	"cloud-app-hive/domain"
+	"cloud-app-hive/domain/commands"
+	"fmt"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type GORMNetworkRuleRepository struct {
+	Database *gorm.DB
+}
+
+// Create creates a new network rule
+func (r GORMNetworkRuleRepository) Create(createNetworkRule commands.CreateNetworkRule) (*domain.NetworkRule, error) {
+	networkRule := domain.NetworkRule{
+		ID:                uuid.New().String(),
+		NamespaceID:       createNetworkRule.NamespaceID,
+		ApplicationID:     createNetworkRule.ApplicationID,
+		FromNamespaceID:   createNetworkRule.FromNamespaceID,
+		FromApplicationID: createNetworkRule.FromApplicationID,
+		Description:       createNetworkRule.Description,
+		CreatedBy:         createNetworkRule.CreatedBy,
+	}
+	result := r.Database.Create(&networkRule)
+	if result.Error != nil {
+		return nil, fmt.Errorf("error creating network rule: %w", result.Error)
+	}
+	return &networkRule, nil
+}
+
+// FindByID returns a network rule by its ID
+func (r GORMNetworkRuleRepository) FindByID(id string) (*domain.NetworkRule, error) {
+	networkRule := domain.NetworkRule{}
+	result := r.Database.Limit(1).Find(&networkRule, domain.NetworkRule{
+		ID: id,
+	})
+	if result.Error != nil {
+		return nil, fmt.Errorf("error finding network rule: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+	return &networkRule, nil
+}
+
+// FindByNamespaceID returns the network rules of a namespace, oldest first
+func (r GORMNetworkRuleRepository) FindByNamespaceID(namespaceID string) ([]domain.NetworkRule, error) {
+	var networkRules []domain.NetworkRule
+	result := r.Database.Order("created_at").Find(&networkRules, domain.NetworkRule{
+		NamespaceID: namespaceID,
+	})
+	if result.Error != nil {
+		return nil, fmt.Errorf("error finding network rules: %w", result.Error)
+	}
+	return networkRules, nil
+}
+
+// Delete deletes a network rule
+func (r GORMNetworkRuleRepository) Delete(id string) error {
+	result := r.Database.Delete(&domain.NetworkRule{}, "id = ?", id)
+	if result.Error != nil {
+		return fmt.Errorf("error deleting network rule: %w", result.Error)
+	}
+	return nil
+}
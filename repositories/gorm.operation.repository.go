@@ -0,0 +1,56 @@
+package repositories
+
+import (
+	"cloud-app-hive/domain"
+	"fmt"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type GORMOperationRepository struct {
+	Database *gorm.DB
+}
+
+// Create creates a new operation
+func (r GORMOperationRepository) Create(operation domain.Operation) (*domain.Operation, error) {
+	operation.ID = uuid.New().String()
+	result := r.Database.Create(&operation)
+	if result.Error != nil {
+		return nil, fmt.Errorf("error creating operation: %w", result.Error)
+	}
+	return &operation, nil
+}
+
+// FindByID returns an operation by its ID
+func (r GORMOperationRepository) FindByID(id string) (*domain.Operation, error) {
+	operation := domain.Operation{}
+	result := r.Database.Limit(1).Find(&operation, domain.Operation{
+		ID: id,
+	})
+	if result.Error != nil {
+		return nil, fmt.Errorf("error finding operation: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+	return &operation, nil
+}
+
+// FindUnfinished returns the operations that are still pending or running
+func (r GORMOperationRepository) FindUnfinished() ([]domain.Operation, error) {
+	var operations []domain.Operation
+	result := r.Database.Where("status IN ?", []domain.OperationStatus{domain.OperationPending, domain.OperationRunning}).Find(&operations)
+	if result.Error != nil {
+		return nil, fmt.Errorf("error finding unfinished operations: %w", result.Error)
+	}
+	return operations, nil
+}
+
+// Update stores the status and the steps of an operation
+func (r GORMOperationRepository) Update(operation domain.Operation) error {
+	result := r.Database.Model(&domain.Operation{ID: operation.ID}).Select("Status", "Steps", "FailedStep", "Error", "StartedAt", "FinishedAt").Updates(&operation)
+	if result.Error != nil {
+		return fmt.Errorf("error updating operation: %w", result.Error)
+	}
+	return nil
+}
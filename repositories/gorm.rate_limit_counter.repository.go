@@ -0,0 +1,45 @@
+package repositories
+
+import (
+	"cloud-app-hive/domain"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GORMRateLimitCounterRepository counts the requests in MySQL, so that every replica of the API shares the same counters
+type GORMRateLimitCounterRepository struct {
+	Database *gorm.DB
+}
+
+// Increment counts a request of the key in the window starting at windowStart and returns the number of requests counted in this window.
+// Concurrent requests may read a count incremented by each other, which only makes the limit stricter.
+func (r GORMRateLimitCounterRepository) Increment(key string, windowStart time.Time, window time.Duration) (int64, error) {
+	counter := domain.RateLimitCounter{Key: key, WindowStart: windowStart, Count: 1}
+	result := r.Database.Clauses(clause.OnConflict{
+		DoUpdates: clause.Assignments(map[string]interface{}{"count": gorm.Expr("count + 1")}),
+	}).Create(&counter)
+	if result.Error != nil {
+		return 0, fmt.Errorf("error incrementing rate limit counter: %w", result.Error)
+	}
+
+	// MySQL reports 1 affected row for an insert, the first request of a window drops the previous windows of the key
+	if result.RowsAffected == 1 {
+		result = r.Database.Where("counter_key = ? AND window_start < ?", key, windowStart).Delete(&domain.RateLimitCounter{})
+		if result.Error != nil {
+			return 0, fmt.Errorf("error deleting past rate limit counters: %w", result.Error)
+		}
+		return 1, nil
+	}
+
+	var count int64
+	result = r.Database.Model(&domain.RateLimitCounter{}).
+		Where("counter_key = ? AND window_start = ?", key, windowStart).
+		Pluck("count", &count)
+	if result.Error != nil {
+		return 0, fmt.Errorf("error reading rate limit counter: %w", result.Error)
+	}
+	return count, nil
+}
@@ -0,0 +1,101 @@
+package repositories
+
+import (
+	"cloud-app-hive/domain"
+	domainRepositories "cloud-app-hive/domain/repositories"
+	"fmt"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type GORMRegistryCredentialRepository struct {
+	Database *gorm.DB
+	// SecretsCipher encrypts the passwords before they are stored
+	SecretsCipher domainRepositories.SecretsCipher
+}
+
+// Create creates a new registry credential
+func (r GORMRegistryCredentialRepository) Create(registryCredential domain.RegistryCredential) (*domain.RegistryCredential, error) {
+	registryCredential.ID = uuid.New().String()
+	if err := r.encryptPassword(&registryCredential); err != nil {
+		return nil, err
+	}
+	result := r.Database.Create(&registryCredential)
+	if result.Error != nil {
+		return nil, fmt.Errorf("error creating registry credential: %w", result.Error)
+	}
+	return &registryCredential, nil
+}
+
+// FindByID returns a registry credential by its ID
+func (r GORMRegistryCredentialRepository) FindByID(id string) (*domain.RegistryCredential, error) {
+	registryCredential := domain.RegistryCredential{}
+	result := r.Database.Limit(1).Find(&registryCredential, domain.RegistryCredential{
+		ID: id,
+	})
+	if result.Error != nil {
+		return nil, fmt.Errorf("error finding registry credential: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+	return &registryCredential, nil
+}
+
+// FindByNamespaceID returns the registry credentials of a namespace, sorted by name
+func (r GORMRegistryCredentialRepository) FindByNamespaceID(namespaceID string) ([]domain.RegistryCredential, error) {
+	var registryCredentials []domain.RegistryCredential
+	result := r.Database.Order("name").Find(&registryCredentials, domain.RegistryCredential{
+		NamespaceID: namespaceID,
+	})
+	if result.Error != nil {
+		return nil, fmt.Errorf("error finding registry credentials: %w", result.Error)
+	}
+	return registryCredentials, nil
+}
+
+// FindAll returns the registry credentials of every namespace
+func (r GORMRegistryCredentialRepository) FindAll() ([]domain.RegistryCredential, error) {
+	var registryCredentials []domain.RegistryCredential
+	result := r.Database.Find(&registryCredentials)
+	if result.Error != nil {
+		return nil, fmt.Errorf("error finding registry credentials: %w", result.Error)
+	}
+	return registryCredentials, nil
+}
+
+// Update stores the server, username and password of a registry credential, the password is encrypted if it is not already
+func (r GORMRegistryCredentialRepository) Update(registryCredential domain.RegistryCredential) (*domain.RegistryCredential, error) {
+	if err := r.encryptPassword(&registryCredential); err != nil {
+		return nil, err
+	}
+	result := r.Database.Model(&domain.RegistryCredential{ID: registryCredential.ID}).Updates(map[string]interface{}{
+		"server":           registryCredential.Server,
+		"username":         registryCredential.Username,
+		"password":         registryCredential.Password,
+		"password_preview": registryCredential.PasswordPreview,
+	})
+	if result.Error != nil {
+		return nil, fmt.Errorf("error updating registry credential: %w", result.Error)
+	}
+	return &registryCredential, nil
+}
+
+// Delete deletes a registry credential
+func (r GORMRegistryCredentialRepository) Delete(id string) error {
+	result := r.Database.Delete(&domain.RegistryCredential{}, "id = ?", id)
+	if result.Error != nil {
+		return fmt.Errorf("error deleting registry credential: %w", result.Error)
+	}
+	return nil
+}
+
+// encryptPassword replaces the password of the credential with its encryption, before it is stored
+func (r GORMRegistryCredentialRepository) encryptPassword(registryCredential *domain.RegistryCredential) error {
+	encryptedPassword, err := r.SecretsCipher.Encrypt(registryCredential.NamespaceID, registryCredential.PasswordSecrets())
+	if err != nil {
+		return fmt.Errorf("error encrypting password of registry credential %s: %w", registryCredential.Name, err)
+	}
+	*registryCredential = registryCredential.WithPasswordSecrets(encryptedPassword)
+	return nil
+}
@@ -0,0 +1,147 @@
+package repositories
+
+import (
+	"cloud-app-hive/domain"
+	domainRepositories "cloud-app-hive/domain/repositories"
+	"fmt"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"time"
+)
+
+type GORMWebhookRepository struct {
+	Database *gorm.DB
+	// SecretsCipher encrypts the secrets before they are stored
+	SecretsCipher domainRepositories.SecretsCipher
+}
+
+// Create creates a new webhook
+func (r GORMWebhookRepository) Create(webhook domain.Webhook) (*domain.Webhook, error) {
+	webhook.ID = uuid.New().String()
+	if err := r.encryptSecret(&webhook); err != nil {
+		return nil, err
+	}
+	result := r.Database.Create(&webhook)
+	if result.Error != nil {
+		return nil, fmt.Errorf("error creating webhook: %w", result.Error)
+	}
+	return &webhook, nil
+}
+
+// FindByID returns a webhook by its ID
+func (r GORMWebhookRepository) FindByID(id string) (*domain.Webhook, error) {
+	webhook := domain.Webhook{}
+	result := r.Database.Limit(1).Find(&webhook, domain.Webhook{
+		ID: id,
+	})
+	if result.Error != nil {
+		return nil, fmt.Errorf("error finding webhook: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+	return &webhook, nil
+}
+
+// FindByNamespaceID returns the webhooks of a namespace, oldest first
+func (r GORMWebhookRepository) FindByNamespaceID(namespaceID string) ([]domain.Webhook, error) {
+	var webhooks []domain.Webhook
+	result := r.Database.Order("created_at").Find(&webhooks, domain.Webhook{
+		NamespaceID: namespaceID,
+	})
+	if result.Error != nil {
+		return nil, fmt.Errorf("error finding webhooks: %w", result.Error)
+	}
+	return webhooks, nil
+}
+
+// FindAll returns the webhooks of every namespace
+func (r GORMWebhookRepository) FindAll() ([]domain.Webhook, error) {
+	var webhooks []domain.Webhook
+	result := r.Database.Find(&webhooks)
+	if result.Error != nil {
+		return nil, fmt.Errorf("error finding webhooks: %w", result.Error)
+	}
+	return webhooks, nil
+}
+
+// Update stores the URL, the events, the state and the secret of a webhook, the secret is encrypted if it is not already
+func (r GORMWebhookRepository) Update(webhook domain.Webhook) (*domain.Webhook, error) {
+	if err := r.encryptSecret(&webhook); err != nil {
+		return nil, err
+	}
+	result := r.Database.Model(&domain.Webhook{ID: webhook.ID}).Updates(map[string]interface{}{
+		"url":         webhook.URL,
+		"event_types": webhook.EventTypes,
+		"active":      webhook.Active,
+		"secret":      webhook.Secret,
+	})
+	if result.Error != nil {
+		return nil, fmt.Errorf("error updating webhook: %w", result.Error)
+	}
+	return &webhook, nil
+}
+
+// Delete deletes a webhook
+func (r GORMWebhookRepository) Delete(id string) error {
+	result := r.Database.Delete(&domain.Webhook{}, "id = ?", id)
+	if result.Error != nil {
+		return fmt.Errorf("error deleting webhook: %w", result.Error)
+	}
+	return nil
+}
+
+// encryptSecret replaces the secret of the webhook with its encryption, before it is stored
+func (r GORMWebhookRepository) encryptSecret(webhook *domain.Webhook) error {
+	encryptedSecret, err := r.SecretsCipher.Encrypt(webhook.NamespaceID, webhook.SecretSecrets())
+	if err != nil {
+		return fmt.Errorf("error encrypting secret of webhook %s: %w", webhook.ID, err)
+	}
+	*webhook = webhook.WithSecretSecrets(encryptedSecret)
+	return nil
+}
+
+type GORMWebhookDeliveryRepository struct {
+	Database *gorm.DB
+}
+
+// Create appends an attempt to deliver an event to the log
+func (r GORMWebhookDeliveryRepository) Create(webhookDelivery domain.WebhookDelivery) (*domain.WebhookDelivery, error) {
+	webhookDelivery.ID = uuid.New().String()
+	result := r.Database.Create(&webhookDelivery)
+	if result.Error != nil {
+		return nil, fmt.Errorf("error creating webhook delivery: %w", result.Error)
+	}
+	return &webhookDelivery, nil
+}
+
+// FindByWebhookID returns the last attempts to deliver events to a webhook, most recent first
+func (r GORMWebhookDeliveryRepository) FindByWebhookID(webhookID string, limit int) ([]domain.WebhookDelivery, error) {
+	var webhookDeliveries []domain.WebhookDelivery
+	result := r.Database.Order("created_at desc").Limit(limit).Find(&webhookDeliveries, domain.WebhookDelivery{
+		WebhookID: webhookID,
+	})
+	if result.Error != nil {
+		return nil, fmt.Errorf("error finding webhook deliveries: %w", result.Error)
+	}
+	return webhookDeliveries, nil
+}
+
+// FindDue returns the failed attempts whose next attempt is due at now, oldest first
+func (r GORMWebhookDeliveryRepository) FindDue(now time.Time) ([]domain.WebhookDelivery, error) {
+	var webhookDeliveries []domain.WebhookDelivery
+	result := r.Database.Where("next_attempt_at IS NOT NULL AND next_attempt_at <= ?", now).Order("next_attempt_at").Find(&webhookDeliveries)
+	if result.Error != nil {
+		return nil, fmt.Errorf("error finding due webhook deliveries: %w", result.Error)
+	}
+	return webhookDeliveries, nil
+}
+
+// ClearNextAttempt marks the next attempt of a failed attempt as sent, it returns false when it was already cleared
+func (r GORMWebhookDeliveryRepository) ClearNextAttempt(webhookDeliveryID string) (bool, error) {
+	result := r.Database.Model(&domain.WebhookDelivery{}).Where("id = ? AND next_attempt_at IS NOT NULL", webhookDeliveryID).Update("next_attempt_at", nil)
+	if result.Error != nil {
+		return false, fmt.Errorf("error clearing next attempt of webhook delivery: %w", result.Error)
+	}
+	return result.RowsAffected == 1, nil
+}
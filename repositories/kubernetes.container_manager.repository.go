@@ -5,31 +5,46 @@ import (
 	customErrors "cloud-app-hive/controllers/errors"
 	"cloud-app-hive/domain"
 	"cloud-app-hive/domain/commands"
+	domainRepositories "cloud-app-hive/domain/repositories"
+	"crypto/sha256"
 	"encoding/base64"
 	"encoding/json"
 	"sort"
 	"time"
 
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/yaml"
 
 	"context"
 	"fmt"
 	"io"
 
 	"os"
+	"regexp"
 	"strings"
 
 	v12 "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
 	v13 "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/metrics/pkg/client/clientset/versioned"
 )
 
-type KubernetesContainerManagerRepository struct{}
+type KubernetesContainerManagerRepository struct {
+	// SecretsCipher decrypts the secret values stored in the database when the Kubernetes Secrets are built
+	SecretsCipher domainRepositories.SecretsCipher
+	// SecretResolver reads the values of the secrets referencing a secret provider when the Kubernetes Secrets are built
+	SecretResolver domainRepositories.SecretResolver
+}
 
 // connectToKubernetesAPIMetrics Connect to Kubernetes API and return the clientset
 func (containerManager KubernetesContainerManagerRepository) connectToKubernetesAPIMetrics() (*versioned.Clientset, error) {
@@ -175,51 +190,125 @@ func (containerManager KubernetesContainerManagerRepository) ApplyApplication(
 		return err
 	}
 
-	err = containerManager.applyNamespace(clientset, applyApplication)
-	if err != nil {
-		return &customErrors.ContainerManagerError{
-			Message: "While applying namespace - " + err.Error(),
+	reporter := applyApplication.StepReporter
+	err = domain.RunOperationStep(reporter, domain.NamespaceOperationStep, func() error {
+		err := containerManager.applyNamespace(clientset, applyApplication)
+		if err != nil {
+			return &customErrors.ContainerManagerError{
+				Message: "While applying namespace - " + err.Error(),
+			}
 		}
-	}
 
-	secretOriginalKeyWithConvertedK8sKey, err := containerManager.applySecrets(clientset, applyApplication)
-	if err != nil {
-		return &customErrors.ContainerManagerError{
-			Message: "While applying secrets - " + err.Error(),
+		err = containerManager.applyNamespaceNetworkPolicies(clientset, applyApplication.Namespace)
+		if err != nil {
+			return &customErrors.ContainerManagerError{
+				Message: "While applying namespace network policies - " + err.Error(),
+			}
 		}
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 
-	if usesPrivateRegistry(applyApplication.Registry) {
-		err = containerManager.applyPrivateRegistrySecret(clientset, applyApplication)
+	var secretOriginalKeyWithConvertedK8sKey map[string]string
+	err = domain.RunOperationStep(reporter, domain.SecretsOperationStep, func() error {
+		var err error
+		secretOriginalKeyWithConvertedK8sKey, err = containerManager.applySecrets(clientset, applyApplication)
 		if err != nil {
 			return &customErrors.ContainerManagerError{
-				Message: "While applying private registry secret - " + err.Error(),
+				Message: "While applying secrets - " + err.Error(),
 			}
 		}
-	}
 
-	err = containerManager.applyDeployment(clientset, applyApplication, secretOriginalKeyWithConvertedK8sKey)
-	if err != nil {
-		return &customErrors.ContainerManagerError{
-			Message: "While applying deployment - " + err.Error(),
+		if usesPrivateRegistry(applyApplication.Registry) {
+			err = containerManager.applyPrivateRegistrySecret(clientset, applyApplication)
+			if err != nil {
+				return &customErrors.ContainerManagerError{
+					Message: "While applying private registry secret - " + err.Error(),
+				}
+			}
 		}
-	}
 
-	err = containerManager.applyService(clientset, applyApplication)
-	if err != nil {
-		return &customErrors.ContainerManagerError{
-			Message: "While applying service - " + err.Error(),
+		if usesRegistryCredential(applyApplication) {
+			err = containerManager.applyRegistryCredentialSecret(clientset, applyApplication)
+			if err != nil {
+				return &customErrors.ContainerManagerError{
+					Message: "While applying registry credential secret - " + err.Error(),
+				}
+			}
+		}
+
+		err = containerManager.applyConfigFiles(clientset, applyApplication)
+		if err != nil {
+			return &customErrors.ContainerManagerError{
+				Message: "While applying config files - " + err.Error(),
+			}
 		}
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 
-	err = containerManager.applyIngress(clientset, applyApplication)
+	err = domain.RunOperationStep(reporter, domain.DeploymentOperationStep, func() error {
+		err := containerManager.applyDeployment(clientset, applyApplication, secretOriginalKeyWithConvertedK8sKey)
+		if err != nil {
+			return &customErrors.ContainerManagerError{
+				Message: "While applying deployment - " + err.Error(),
+			}
+		}
+		return nil
+	})
 	if err != nil {
-		return &customErrors.ContainerManagerError{
-			Message: "While applying ingress - " + err.Error(),
+		return err
+	}
+
+	err = domain.RunOperationStep(reporter, domain.ServiceOperationStep, func() error {
+		err := containerManager.applyService(clientset, applyApplication)
+		if err != nil {
+			return &customErrors.ContainerManagerError{
+				Message: "While applying service - " + err.Error(),
+			}
 		}
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 
-	return nil
+	return domain.RunOperationStep(reporter, domain.IngressOperationStep, func() error {
+		err := containerManager.applyBasicAuthSecret(clientset, applyApplication)
+		if err != nil {
+			return &customErrors.ContainerManagerError{
+				Message: "While applying basic auth secret - " + err.Error(),
+			}
+		}
+
+		err = containerManager.applyIngress(clientset, applyApplication)
+		if err != nil {
+			return &customErrors.ContainerManagerError{
+				Message: "While applying ingress - " + err.Error(),
+			}
+		}
+
+		err = containerManager.applyLoadBalancerNetworkPolicy(clientset, applyApplication)
+		if err != nil {
+			return &customErrors.ContainerManagerError{
+				Message: "While applying load balancer network policy - " + err.Error(),
+			}
+		}
+		return nil
+	})
+}
+
+func buildNamespace(deployApplication commands.ApplyApplication) *v1.Namespace {
+	return &v1.Namespace{
+		TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Namespace"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: deployApplication.Namespace,
+		},
+	}
 }
 
 func (containerManager KubernetesContainerManagerRepository) applyNamespace(
@@ -228,11 +317,7 @@ func (containerManager KubernetesContainerManagerRepository) applyNamespace(
 	namespace := deployApplication.Namespace
 	_, err := clientset.CoreV1().Namespaces().Get(context.Background(), namespace, metav1.GetOptions{})
 	if err != nil {
-		_, err = clientset.CoreV1().Namespaces().Create(context.Background(), &v1.Namespace{
-			ObjectMeta: metav1.ObjectMeta{
-				Name: namespace,
-			},
-		}, metav1.CreateOptions{})
+		_, err = clientset.CoreV1().Namespaces().Create(context.Background(), buildNamespace(deployApplication), metav1.CreateOptions{})
 		if err != nil {
 			return &customErrors.ContainerManagerApplicationDeploymentError{
 				Message:         fmt.Sprintf("Error while creating namespace : %s", err.Error()),
@@ -247,6 +332,214 @@ func (containerManager KubernetesContainerManagerRepository) applyNamespace(
 	return nil
 }
 
+// networkRuleLabel marks the NetworkPolicies built from the network rules of a namespace, the other ones are never deleted by ApplyNetworkRules
+const networkRuleLabel = "cloud-app-hive/network-rule"
+
+// namespaceNameLabel is set by Kubernetes on every namespace, NetworkPolicies select namespaces with it
+const namespaceNameLabel = "kubernetes.io/metadata.name"
+
+// buildNamespaceNetworkPolicies builds the policies isolating a namespace: its pods only accept traffic
+// from the pods of the namespace and from the ingress controller namespace, "ingress-nginx" unless overridden
+func buildNamespaceNetworkPolicies(namespace string) []*v13.NetworkPolicy {
+	ingressControllerNamespace := os.Getenv("NETWORK_POLICY_INGRESS_CONTROLLER_NAMESPACE")
+	if ingressControllerNamespace == "" {
+		ingressControllerNamespace = "ingress-nginx"
+	}
+
+	return []*v13.NetworkPolicy{
+		{
+			TypeMeta: metav1.TypeMeta{APIVersion: "networking.k8s.io/v1", Kind: "NetworkPolicy"},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "default-deny-ingress",
+				Namespace: namespace,
+			},
+			Spec: v13.NetworkPolicySpec{
+				PodSelector: metav1.LabelSelector{},
+				PolicyTypes: []v13.PolicyType{v13.PolicyTypeIngress},
+			},
+		},
+		{
+			TypeMeta: metav1.TypeMeta{APIVersion: "networking.k8s.io/v1", Kind: "NetworkPolicy"},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "allow-namespace-and-ingress-controller",
+				Namespace: namespace,
+			},
+			Spec: v13.NetworkPolicySpec{
+				PodSelector: metav1.LabelSelector{},
+				PolicyTypes: []v13.PolicyType{v13.PolicyTypeIngress},
+				Ingress: []v13.NetworkPolicyIngressRule{
+					{
+						From: []v13.NetworkPolicyPeer{
+							{PodSelector: &metav1.LabelSelector{}},
+							{NamespaceSelector: &metav1.LabelSelector{
+								MatchLabels: map[string]string{namespaceNameLabel: ingressControllerNamespace},
+							}},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// buildLoadBalancerNetworkPolicy builds the policy letting the load balancer of an application reach its pods from outside the cluster
+func buildLoadBalancerNetworkPolicy(deployApplication commands.ApplyApplication) *v13.NetworkPolicy {
+	applicationPort := intstr.FromInt(int(deployApplication.Port))
+	protocol := v1.ProtocolTCP
+	return &v13.NetworkPolicy{
+		TypeMeta: metav1.TypeMeta{APIVersion: "networking.k8s.io/v1", Kind: "NetworkPolicy"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-allow-load-balancer", deployApplication.Name),
+			Namespace: deployApplication.Namespace,
+		},
+		Spec: v13.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{
+				MatchLabels: map[string]string{"app": fmt.Sprintf("%s-deployment", deployApplication.Name)},
+			},
+			PolicyTypes: []v13.PolicyType{v13.PolicyTypeIngress},
+			Ingress: []v13.NetworkPolicyIngressRule{
+				{
+					Ports: []v13.NetworkPolicyPort{{Protocol: &protocol, Port: &applicationPort}},
+				},
+			},
+		},
+	}
+}
+
+// buildNetworkRulePolicy builds the policy of a network rule, the namespace and pod selectors of the peer must both match
+func buildNetworkRulePolicy(namespace string, networkRule commands.ApplyNetworkRule) *v13.NetworkPolicy {
+	podSelector := metav1.LabelSelector{}
+	if networkRule.ApplicationName != nil {
+		podSelector.MatchLabels = map[string]string{"app": fmt.Sprintf("%s-deployment", *networkRule.ApplicationName)}
+	}
+	peer := v13.NetworkPolicyPeer{
+		NamespaceSelector: &metav1.LabelSelector{
+			MatchLabels: map[string]string{namespaceNameLabel: networkRule.FromNamespace},
+		},
+	}
+	if networkRule.FromApplicationName != nil {
+		peer.PodSelector = &metav1.LabelSelector{
+			MatchLabels: map[string]string{"app": fmt.Sprintf("%s-deployment", *networkRule.FromApplicationName)},
+		}
+	}
+
+	return &v13.NetworkPolicy{
+		TypeMeta: metav1.TypeMeta{APIVersion: "networking.k8s.io/v1", Kind: "NetworkPolicy"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("network-rule-%s", networkRule.ID),
+			Namespace: namespace,
+			Labels:    map[string]string{networkRuleLabel: "true"},
+		},
+		Spec: v13.NetworkPolicySpec{
+			PodSelector: podSelector,
+			PolicyTypes: []v13.PolicyType{v13.PolicyTypeIngress},
+			Ingress:     []v13.NetworkPolicyIngressRule{{From: []v13.NetworkPolicyPeer{peer}}},
+		},
+	}
+}
+
+func (containerManager KubernetesContainerManagerRepository) applyNetworkPolicy(clientset *kubernetes.Clientset, networkPolicy *v13.NetworkPolicy) error {
+	client := clientset.NetworkingV1().NetworkPolicies(networkPolicy.Namespace)
+	_, err := client.Get(context.Background(), networkPolicy.Name, metav1.GetOptions{})
+	if err == nil {
+		_, err = client.Update(context.Background(), networkPolicy, metav1.UpdateOptions{})
+		if err != nil {
+			return fmt.Errorf("error while updating network policy %s : %s", networkPolicy.Name, err.Error())
+		}
+	} else {
+		_, err = client.Create(context.Background(), networkPolicy, metav1.CreateOptions{})
+		if err != nil {
+			return fmt.Errorf("error while creating network policy %s : %s", networkPolicy.Name, err.Error())
+		}
+	}
+
+	fmt.Println("Network policy created successfully : " + networkPolicy.Name + " in namespace " + networkPolicy.Namespace)
+	return nil
+}
+
+func (containerManager KubernetesContainerManagerRepository) deleteNetworkPolicy(clientset *kubernetes.Clientset, namespace string, name string) error {
+	err := clientset.NetworkingV1().NetworkPolicies(namespace).Delete(context.Background(), name, metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("error while deleting network policy %s : %s", name, err.Error())
+	}
+	return nil
+}
+
+func (containerManager KubernetesContainerManagerRepository) applyNamespaceNetworkPolicies(clientset *kubernetes.Clientset, namespace string) error {
+	for _, networkPolicy := range buildNamespaceNetworkPolicies(namespace) {
+		if err := containerManager.applyNetworkPolicy(clientset, networkPolicy); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyLoadBalancerNetworkPolicy opens load balanced applications to the outside of the cluster,
+// and closes the applications that are no longer load balanced
+func (containerManager KubernetesContainerManagerRepository) applyLoadBalancerNetworkPolicy(clientset *kubernetes.Clientset, deployApplication commands.ApplyApplication) error {
+	networkPolicy := buildLoadBalancerNetworkPolicy(deployApplication)
+	if deployApplication.ApplicationType != domain.LoadBalanced {
+		return containerManager.deleteNetworkPolicy(clientset, networkPolicy.Namespace, networkPolicy.Name)
+	}
+	return containerManager.applyNetworkPolicy(clientset, networkPolicy)
+}
+
+// ApplyNetworkRules isolates a namespace and applies one NetworkPolicy per network rule,
+// the policies of the rules that no longer exist are deleted
+func (containerManager KubernetesContainerManagerRepository) ApplyNetworkRules(applyNetworkRules commands.ApplyNetworkRules) error {
+	clientset, err := containerManager.connectToKubernetesAPI()
+	if err != nil {
+		return &customErrors.ContainerManagerError{
+			Message: fmt.Sprintf("Connecting to Kubernetes API while applying network rules failed : %s", err.Error()),
+		}
+	}
+
+	// Namespaces are created by the first deployment of their applications, which applies the rules too
+	namespace := applyNetworkRules.Namespace
+	_, err = clientset.CoreV1().Namespaces().Get(context.Background(), namespace, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return &customErrors.ContainerManagerError{
+			Message: fmt.Sprintf("Error while retrieving namespace %s : %s", namespace, err.Error()),
+		}
+	}
+
+	if err := containerManager.applyNamespaceNetworkPolicies(clientset, namespace); err != nil {
+		return &customErrors.ContainerManagerError{Message: "While applying namespace network policies - " + err.Error()}
+	}
+
+	networkRulePolicyNames := make(map[string]bool, len(applyNetworkRules.Rules))
+	for _, networkRule := range applyNetworkRules.Rules {
+		networkPolicy := buildNetworkRulePolicy(namespace, networkRule)
+		networkRulePolicyNames[networkPolicy.Name] = true
+		if err := containerManager.applyNetworkPolicy(clientset, networkPolicy); err != nil {
+			return &customErrors.ContainerManagerError{Message: "While applying network rules - " + err.Error()}
+		}
+	}
+
+	networkPolicies, err := clientset.NetworkingV1().NetworkPolicies(namespace).List(context.Background(), metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=true", networkRuleLabel),
+	})
+	if err != nil {
+		return &customErrors.ContainerManagerError{
+			Message: fmt.Sprintf("Error while listing network rule policies : %s", err.Error()),
+		}
+	}
+	for _, networkPolicy := range networkPolicies.Items {
+		if networkRulePolicyNames[networkPolicy.Name] {
+			continue
+		}
+		if err := containerManager.deleteNetworkPolicy(clientset, namespace, networkPolicy.Name); err != nil {
+			return &customErrors.ContainerManagerError{Message: "While deleting network rules - " + err.Error()}
+		}
+		fmt.Println("Network policy deleted successfully : " + networkPolicy.Name + " in namespace " + namespace)
+	}
+
+	return nil
+}
+
 // DockerRegistrySecretData represents the data to store in the Docker registry Secret
 type DockerRegistrySecretData struct {
 	Auths map[string]struct {
@@ -260,9 +553,7 @@ func usesPrivateRegistry(registry domain.ImageRegistry) bool {
 	return registry == domain.PrivateRegistry
 }
 
-func (containerManager KubernetesContainerManagerRepository) applyPrivateRegistrySecret(
-	clientset *kubernetes.Clientset, deployApplication commands.ApplyApplication,
-) error {
+func buildPrivateRegistrySecret(deployApplication commands.ApplyApplication) (*v1.Secret, error) {
 	privateRegistryUrl := os.Getenv("PRIVATE_HARBOR_REGISTRY_URL")
 	privateRegistryUsername := os.Getenv("PRIVATE_HARBOR_REGISTRY_USERNAME")
 	privateRegistryPassword := os.Getenv("PRIVATE_HARBOR_REGISTRY_PASSWORD")
@@ -270,16 +561,6 @@ func (containerManager KubernetesContainerManagerRepository) applyPrivateRegistr
 	encodedAuth := base64.URLEncoding.EncodeToString([]byte(
 		fmt.Sprintf("%s:%s", privateRegistryUsername, privateRegistryPassword),
 	))
-	// s.Auth = base64.URLEncoding.EncodeToString([]byte(fmt.Sprintf("%s:%s", s.Username, s.Password)))
-	// data := k8sDockerRegistrySecretData{
-	// 	Auths: map[string]k8sDockerRegistrySecret{
-	// 		registry: s,
-	// 	},
-	// }
-	// authBytes, _ := json.Marshal(data)
-	//     // delete this line
-	// // reStr := base64.URLEncoding.EncodeToString(authBytes)
-	// return authBytes
 
 	// Create the data for the Secret
 	secretData := DockerRegistrySecretData{
@@ -299,20 +580,31 @@ func (containerManager KubernetesContainerManagerRepository) applyPrivateRegistr
 	// Convert the Secret data to JSON
 	secretDataJSON, err := json.Marshal(secretData)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	applicationNamespace := deployApplication.Namespace
-	secret := &v1.Secret{
+	return &v1.Secret{
+		TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Secret"},
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      secretName,
-			Namespace: applicationNamespace,
+			Namespace: deployApplication.Namespace,
 		},
 		Type: v1.SecretTypeDockerConfigJson,
 		Data: map[string][]byte{
 			".dockerconfigjson": []byte(secretDataJSON),
 		},
+	}, nil
+}
+
+func (containerManager KubernetesContainerManagerRepository) applyPrivateRegistrySecret(
+	clientset *kubernetes.Clientset, deployApplication commands.ApplyApplication,
+) error {
+	secret, err := buildPrivateRegistrySecret(deployApplication)
+	if err != nil {
+		return err
 	}
+	secretName := secret.Name
+	applicationNamespace := deployApplication.Namespace
 
 	_, err = clientset.CoreV1().Secrets(applicationNamespace).Get(context.Background(), secretName, metav1.GetOptions{})
 	if err == nil {
@@ -341,7 +633,78 @@ func (containerManager KubernetesContainerManagerRepository) applyPrivateRegistr
 	return nil
 }
 
-func (containerManager KubernetesContainerManagerRepository) applyDeployment(clientset *kubernetes.Clientset, deployApplication commands.ApplyApplication, secretOriginalKeyWithConvertedK8sKey map[string]string) error {
+// usesRegistryCredential returns true if the image of the application is pulled with a registry credential of its namespace
+func usesRegistryCredential(deployApplication commands.ApplyApplication) bool {
+	return deployApplication.Registry == domain.CustomRegistry && deployApplication.RegistryCredential != nil
+}
+
+func registryCredentialSecretName(applicationName string) string {
+	return fmt.Sprintf("%s-registry-credential", applicationName)
+}
+
+// buildRegistryCredentialSecret builds the image pull Secret of an application from its registry credential,
+// with the password as given: decrypted when applied, still encrypted for a dry run
+func buildRegistryCredentialSecret(deployApplication commands.ApplyApplication, registryCredential domain.RegistryCredential) (*v1.Secret, error) {
+	dockerConfigJSON, err := registryCredential.DockerConfigJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	return &v1.Secret{
+		TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Secret"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      registryCredentialSecretName(deployApplication.Name),
+			Namespace: deployApplication.Namespace,
+		},
+		Type: v1.SecretTypeDockerConfigJson,
+		Data: map[string][]byte{
+			v1.DockerConfigJsonKey: dockerConfigJSON,
+		},
+	}, nil
+}
+
+func (containerManager KubernetesContainerManagerRepository) applyRegistryCredentialSecret(
+	clientset *kubernetes.Clientset, deployApplication commands.ApplyApplication,
+) error {
+	registryCredential := *deployApplication.RegistryCredential
+	decryptedPassword, err := containerManager.SecretsCipher.Decrypt(registryCredential.PasswordSecrets())
+	if err != nil {
+		return fmt.Errorf("error while decrypting registry credential %s : %w", registryCredential.Name, err)
+	}
+	secret, err := buildRegistryCredentialSecret(deployApplication, registryCredential.WithPasswordSecrets(decryptedPassword))
+	if err != nil {
+		return err
+	}
+	applicationNamespace := deployApplication.Namespace
+
+	_, err = clientset.CoreV1().Secrets(applicationNamespace).Get(context.Background(), secret.Name, metav1.GetOptions{})
+	if err == nil {
+		_, err = clientset.CoreV1().Secrets(applicationNamespace).Update(context.Background(), secret, metav1.UpdateOptions{})
+		if err != nil {
+			return &customErrors.ContainerManagerApplicationDeploymentError{
+				Message:         fmt.Sprintf("Error while updating registry credential secret : %s", err.Error()),
+				ApplicationName: deployApplication.Name,
+				Namespace:       deployApplication.Namespace,
+				Image:           deployApplication.Image,
+			}
+		}
+	} else {
+		_, err = clientset.CoreV1().Secrets(applicationNamespace).Create(context.Background(), secret, metav1.CreateOptions{})
+		if err != nil {
+			return &customErrors.ContainerManagerApplicationDeploymentError{
+				Message:         fmt.Sprintf("Error while creating registry credential secret : %s", err.Error()),
+				ApplicationName: deployApplication.Name,
+				Namespace:       deployApplication.Namespace,
+				Image:           deployApplication.Image,
+			}
+		}
+	}
+
+	fmt.Println("Registry credential secret applied successfully : " + secret.Name + " in namespace " + applicationNamespace)
+	return nil
+}
+
+func buildDeployment(deployApplication commands.ApplyApplication, secretOriginalKeyWithConvertedK8sKey map[string]string) (*v12.Deployment, error) {
 	applicationNamespace := deployApplication.Namespace
 	applicationName := deployApplication.Name
 	applicationImage := deployApplication.Image
@@ -349,23 +712,46 @@ func (containerManager KubernetesContainerManagerRepository) applyDeployment(cli
 		privateRegistryUrl := os.Getenv("PRIVATE_HARBOR_REGISTRY_URL")
 		applicationImage = fmt.Sprintf("%s/%s", privateRegistryUrl, deployApplication.Image)
 	}
-	// applicationPort := deployApplication.Port
+	// Images of the custom registry may be given without the host of the registry, like the images of the private registry
+	if usesRegistryCredential(deployApplication) && !strings.HasPrefix(applicationImage, deployApplication.RegistryCredential.Server+"/") {
+		applicationImage = fmt.Sprintf("%s/%s", deployApplication.RegistryCredential.Server, applicationImage)
+	}
+	if deployApplication.PinImageDigest && deployApplication.ImageDigest != "" {
+		applicationImage = domain.PinnedImage(applicationImage, deployApplication.ImageDigest)
+	}
 	applicationEnvironmentVariables := make([]v1.EnvVar, 0)
+	definedEnvironmentVariables := map[string]bool{}
 	for _, environmentVariable := range deployApplication.EnvironmentVariables {
 		applicationEnvironmentVariables = append(applicationEnvironmentVariables, v1.EnvVar{
 			Name:  environmentVariable.Name,
 			Value: environmentVariable.Val,
 		})
+		definedEnvironmentVariables[environmentVariable.Name] = true
+	}
+	// The services of the dependencies are described by environment variables, unless the application defines them itself
+	for _, environmentVariable := range deployApplication.Dependencies.EnvironmentVariables(applicationNamespace) {
+		if definedEnvironmentVariables[environmentVariable.Name] {
+			continue
+		}
+		applicationEnvironmentVariables = append(applicationEnvironmentVariables, v1.EnvVar{
+			Name:  environmentVariable.Name,
+			Value: environmentVariable.Val,
+		})
 	}
 
 	secretName := fmt.Sprintf("%s-secrets", applicationName)
-	// Add secret keys to environment variables
-	for secretOriginalKey, convertedK8sKey := range secretOriginalKeyWithConvertedK8sKey {
+	// Add secret keys to environment variables, in a stable order so that the pod template does not change between two applies
+	secretOriginalKeys := make([]string, 0, len(secretOriginalKeyWithConvertedK8sKey))
+	for secretOriginalKey := range secretOriginalKeyWithConvertedK8sKey {
+		secretOriginalKeys = append(secretOriginalKeys, secretOriginalKey)
+	}
+	sort.Strings(secretOriginalKeys)
+	for _, secretOriginalKey := range secretOriginalKeys {
 		applicationEnvironmentVariables = append(applicationEnvironmentVariables, v1.EnvVar{
 			Name: secretOriginalKey,
 			ValueFrom: &v1.EnvVarSource{
 				SecretKeyRef: &v1.SecretKeySelector{
-					Key: convertedK8sKey,
+					Key: secretOriginalKeyWithConvertedK8sKey[secretOriginalKey],
 					LocalObjectReference: v1.LocalObjectReference{
 						Name: secretName,
 					},
@@ -379,7 +765,7 @@ func (containerManager KubernetesContainerManagerRepository) applyDeployment(cli
 		replicas = 1
 	} else {
 		if deployApplication.ScalabilitySpecifications.Replicas > domain.MaxNumberOfReplicas {
-			return &customErrors.ContainerManagerApplicationDeploymentError{
+			return nil, &customErrors.ContainerManagerApplicationDeploymentError{
 				Message:         fmt.Sprintf("Error while creating deployment : %s", "Replicas must be less than or equal to "+fmt.Sprintf("%d", domain.MaxNumberOfReplicas)),
 				ApplicationName: deployApplication.Name,
 				Namespace:       deployApplication.Namespace,
@@ -388,8 +774,6 @@ func (containerManager KubernetesContainerManagerRepository) applyDeployment(cli
 		}
 		replicas = deployApplication.ScalabilitySpecifications.Replicas
 	}
-	// json, _ := json.Marshal(deployApplication)
-	// fmt.Println("Deploying application with replicas : ", string(json))
 	rawCpuLimit := fmt.Sprintf("%d%s", deployApplication.ContainerSpecifications.CPULimit.Val, deployApplication.ContainerSpecifications.CPULimit.Unit)
 	rawMemoryLimit := fmt.Sprintf("%d%s", deployApplication.ContainerSpecifications.MemoryLimit.Val, deployApplication.ContainerSpecifications.MemoryLimit.Unit)
 	cpuLimit := resource.MustParse(domain.ConvertReadableHumanValueAndUnitToK8sResource(rawCpuLimit))
@@ -402,6 +786,7 @@ func (containerManager KubernetesContainerManagerRepository) applyDeployment(cli
 		runtimeClassName = "gvisor"
 	}
 	deployment := &v12.Deployment{
+		TypeMeta: metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"},
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      deploymentName,
 			Namespace: applicationNamespace,
@@ -464,26 +849,267 @@ func (containerManager KubernetesContainerManagerRepository) applyDeployment(cli
 	}
 
 	if usesPrivateRegistry(deployApplication.Registry) {
-		fmt.Println("Using private registry in deployment")
 		deployment.Spec.Template.Spec.ImagePullSecrets = []v1.LocalObjectReference{
 			{
 				Name: fmt.Sprintf("%s-private-registry-secret", deployApplication.Name),
 			},
 		}
 	}
-
-	_, err := clientset.AppsV1().Deployments(applicationNamespace).Get(context.Background(), deploymentName, metav1.GetOptions{})
-	if err == nil {
-		_, err = clientset.AppsV1().Deployments(applicationNamespace).Update(context.Background(), deployment, metav1.UpdateOptions{})
-		if err != nil {
-			return &customErrors.ContainerManagerApplicationDeploymentError{
-				Message:         fmt.Sprintf("Error while updating deployment : %s", err.Error()),
-				ApplicationName: deployApplication.Name,
-				Namespace:       deployApplication.Namespace,
-				Image:           deployApplication.Image,
-			}
+	if usesRegistryCredential(deployApplication) {
+		deployment.Spec.Template.Spec.ImagePullSecrets = []v1.LocalObjectReference{
+			{
+				Name: registryCredentialSecretName(deployApplication.Name),
+			},
 		}
-	} else {
+	}
+	if len(deployApplication.ConfigGroups) > 0 {
+		// The variables of env take precedence over envFrom, and a source of envFrom over the ones before it
+		deployment.Spec.Template.Spec.Containers[0].EnvFrom = buildConfigGroupEnvFrom(deployApplication.ConfigGroups)
+		// The versions of the groups are part of the pod template, so that updating a group rolls the pods out
+		deployment.Spec.Template.ObjectMeta.Annotations = map[string]string{
+			configGroupsAnnotation: domain.ConfigGroupVersions(deployApplication.ConfigGroups),
+		}
+	}
+	if len(deployApplication.ConfigFiles) > 0 {
+		addConfigFileVolumes(deployment, deployApplication)
+	}
+
+	return deployment, nil
+}
+
+// configFilesAnnotationPrefix prefixes the annotations holding the checksums of the config files of an application, on its pod template
+const configFilesAnnotationPrefix = "config-files.cloud-app-hive/"
+
+const (
+	configFilesVolumeName = "config-files"
+	secretFilesVolumeName = "secret-files"
+)
+
+func configFilesConfigMapName(applicationName string) string {
+	return fmt.Sprintf("%s-config-files", applicationName)
+}
+
+func secretFilesSecretName(applicationName string) string {
+	return fmt.Sprintf("%s-secret-files", applicationName)
+}
+
+// addConfigFileVolumes mounts each config file of the application at its path, read-only.
+// The checksums of the files are part of the pod template, so that changing a file rolls the pods out:
+// files mounted with a sub path are not updated in running pods.
+func addConfigFileVolumes(deployment *v12.Deployment, deployApplication commands.ApplyApplication) {
+	podSpec := &deployment.Spec.Template.Spec
+	if deployment.Spec.Template.ObjectMeta.Annotations == nil {
+		deployment.Spec.Template.ObjectMeta.Annotations = map[string]string{}
+	}
+	usesConfigMap, usesSecret := false, false
+	for _, configFile := range deployApplication.ConfigFiles {
+		volumeName := configFilesVolumeName
+		if configFile.Secret {
+			volumeName = secretFilesVolumeName
+			usesSecret = true
+		} else {
+			usesConfigMap = true
+		}
+		podSpec.Containers[0].VolumeMounts = append(podSpec.Containers[0].VolumeMounts, v1.VolumeMount{
+			Name:      volumeName,
+			MountPath: configFile.MountPath,
+			SubPath:   configFile.Name,
+			ReadOnly:  true,
+		})
+		// The checksum of a secret file is the one of its encrypted content
+		deployment.Spec.Template.ObjectMeta.Annotations[configFilesAnnotationPrefix+configFile.Name] = configFile.Checksum()
+	}
+	if usesConfigMap {
+		podSpec.Volumes = append(podSpec.Volumes, v1.Volume{
+			Name: configFilesVolumeName,
+			VolumeSource: v1.VolumeSource{
+				ConfigMap: &v1.ConfigMapVolumeSource{
+					LocalObjectReference: v1.LocalObjectReference{Name: configFilesConfigMapName(deployApplication.Name)},
+				},
+			},
+		})
+	}
+	if usesSecret {
+		podSpec.Volumes = append(podSpec.Volumes, v1.Volume{
+			Name: secretFilesVolumeName,
+			VolumeSource: v1.VolumeSource{
+				Secret: &v1.SecretVolumeSource{SecretName: secretFilesSecretName(deployApplication.Name)},
+			},
+		})
+	}
+}
+
+// buildConfigFileObjects builds the ConfigMap holding the config files of an application and the Secret holding its secret files.
+// The contents of the secret files are used as given: decrypted when applied, still encrypted for a dry run.
+// An object is nil when the application has no file of its kind.
+func buildConfigFileObjects(deployApplication commands.ApplyApplication) (*v1.ConfigMap, *v1.Secret, error) {
+	var configMap *v1.ConfigMap
+	var secret *v1.Secret
+	for _, configFile := range deployApplication.ConfigFiles {
+		if configFile.Secret {
+			if secret == nil {
+				secret = &v1.Secret{
+					TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Secret"},
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      secretFilesSecretName(deployApplication.Name),
+						Namespace: deployApplication.Namespace,
+					},
+					Data:       map[string][]byte{},
+					StringData: map[string]string{},
+					Type:       v1.SecretTypeOpaque,
+				}
+			}
+			if (domain.ApplicationSecret{Val: configFile.Content}).IsEncrypted() {
+				secret.StringData[configFile.Name] = configFile.Content
+				continue
+			}
+			content, err := configFile.Decode()
+			if err != nil {
+				return nil, nil, fmt.Errorf("error decoding config file %s : %w", configFile.Name, err)
+			}
+			secret.Data[configFile.Name] = content
+			continue
+		}
+
+		if configMap == nil {
+			configMap = &v1.ConfigMap{
+				TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      configFilesConfigMapName(deployApplication.Name),
+					Namespace: deployApplication.Namespace,
+				},
+				Data:       map[string]string{},
+				BinaryData: map[string][]byte{},
+			}
+		}
+		if configFile.Encoding == domain.Base64ConfigFile {
+			content, err := configFile.Decode()
+			if err != nil {
+				return nil, nil, fmt.Errorf("error decoding config file %s : %w", configFile.Name, err)
+			}
+			configMap.BinaryData[configFile.Name] = content
+			continue
+		}
+		configMap.Data[configFile.Name] = configFile.Content
+	}
+	return configMap, secret, nil
+}
+
+// applyConfigFiles creates or replaces the ConfigMap and the Secret holding the config files of an application,
+// and deletes them when the application no longer has files of their kind
+func (containerManager KubernetesContainerManagerRepository) applyConfigFiles(clientset *kubernetes.Clientset, deployApplication commands.ApplyApplication) error {
+	decryptedContents, err := containerManager.SecretsCipher.Decrypt(deployApplication.ConfigFiles.SecretContents())
+	if err != nil {
+		return fmt.Errorf("error while decrypting config files : %w", err)
+	}
+	deployApplication.ConfigFiles = deployApplication.ConfigFiles.WithSecretContents(decryptedContents)
+	configMap, secret, err := buildConfigFileObjects(deployApplication)
+	if err != nil {
+		return err
+	}
+
+	configMapsClient := clientset.CoreV1().ConfigMaps(deployApplication.Namespace)
+	if configMap == nil {
+		err = configMapsClient.Delete(context.Background(), configFilesConfigMapName(deployApplication.Name), metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("error while deleting config files config map : %w", err)
+		}
+	} else {
+		if _, err = configMapsClient.Get(context.Background(), configMap.Name, metav1.GetOptions{}); err == nil {
+			_, err = configMapsClient.Update(context.Background(), configMap, metav1.UpdateOptions{})
+		} else {
+			_, err = configMapsClient.Create(context.Background(), configMap, metav1.CreateOptions{})
+		}
+		if err != nil {
+			return fmt.Errorf("error while applying config files config map : %w", err)
+		}
+	}
+
+	secretsClient := clientset.CoreV1().Secrets(deployApplication.Namespace)
+	if secret == nil {
+		err = secretsClient.Delete(context.Background(), secretFilesSecretName(deployApplication.Name), metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("error while deleting secret files secret : %w", err)
+		}
+	} else {
+		if _, err = secretsClient.Get(context.Background(), secret.Name, metav1.GetOptions{}); err == nil {
+			_, err = secretsClient.Update(context.Background(), secret, metav1.UpdateOptions{})
+		} else {
+			_, err = secretsClient.Create(context.Background(), secret, metav1.CreateOptions{})
+		}
+		if err != nil {
+			return fmt.Errorf("error while applying secret files secret : %w", err)
+		}
+	}
+	return nil
+}
+
+func (containerManager KubernetesContainerManagerRepository) deleteConfigFiles(clientset *kubernetes.Clientset, deployApplication commands.UnapplyApplication) error {
+	err := clientset.CoreV1().ConfigMaps(deployApplication.Namespace).Delete(context.Background(), configFilesConfigMapName(deployApplication.Name), metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return &customErrors.ContainerManagerApplicationRemoveError{
+			Message:         fmt.Sprintf("Error deleting config files config map : %s", err.Error()),
+			ApplicationName: deployApplication.Name,
+			Namespace:       deployApplication.Namespace,
+		}
+	}
+	err = clientset.CoreV1().Secrets(deployApplication.Namespace).Delete(context.Background(), secretFilesSecretName(deployApplication.Name), metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return &customErrors.ContainerManagerApplicationRemoveError{
+			Message:         fmt.Sprintf("Error deleting secret files secret : %s", err.Error()),
+			ApplicationName: deployApplication.Name,
+			Namespace:       deployApplication.Namespace,
+		}
+	}
+	return nil
+}
+
+// configGroupsAnnotation holds the versions of the config groups an application reads, on its pod template
+const configGroupsAnnotation = "cloud-app-hive/config-groups"
+
+func buildConfigGroupEnvFrom(configGroups []domain.ConfigGroup) []v1.EnvFromSource {
+	envFrom := make([]v1.EnvFromSource, 0, 2*len(configGroups))
+	for _, configGroup := range configGroups {
+		envFrom = append(envFrom, v1.EnvFromSource{
+			ConfigMapRef: &v1.ConfigMapEnvSource{
+				LocalObjectReference: v1.LocalObjectReference{Name: domain.ConfigGroupConfigMapName(configGroup.Name)},
+			},
+		}, v1.EnvFromSource{
+			SecretRef: &v1.SecretEnvSource{
+				LocalObjectReference: v1.LocalObjectReference{Name: domain.ConfigGroupSecretName(configGroup.Name)},
+			},
+		})
+	}
+	return envFrom
+}
+
+func (containerManager KubernetesContainerManagerRepository) applyDeployment(clientset *kubernetes.Clientset, deployApplication commands.ApplyApplication, secretOriginalKeyWithConvertedK8sKey map[string]string) error {
+	deployment, err := buildDeployment(deployApplication, secretOriginalKeyWithConvertedK8sKey)
+	if err != nil {
+		return err
+	}
+	applicationNamespace := deployApplication.Namespace
+	deploymentName := deployment.Name
+
+	currentDeployment, err := clientset.AppsV1().Deployments(applicationNamespace).Get(context.Background(), deploymentName, metav1.GetOptions{})
+	if err == nil {
+		// Keeping the last restart avoids rolling the pods out again on every update
+		if restartedAt, ok := currentDeployment.Spec.Template.Annotations[restartedAtAnnotation]; ok {
+			if deployment.Spec.Template.Annotations == nil {
+				deployment.Spec.Template.Annotations = map[string]string{}
+			}
+			deployment.Spec.Template.Annotations[restartedAtAnnotation] = restartedAt
+		}
+		_, err = clientset.AppsV1().Deployments(applicationNamespace).Update(context.Background(), deployment, metav1.UpdateOptions{})
+		if err != nil {
+			return &customErrors.ContainerManagerApplicationDeploymentError{
+				Message:         fmt.Sprintf("Error while updating deployment : %s", err.Error()),
+				ApplicationName: deployApplication.Name,
+				Namespace:       deployApplication.Namespace,
+				Image:           deployApplication.Image,
+			}
+		}
+	} else {
 		_, err = clientset.AppsV1().Deployments(applicationNamespace).Create(context.Background(), deployment, metav1.CreateOptions{})
 		if err != nil {
 			return &customErrors.ContainerManagerApplicationDeploymentError{
@@ -499,11 +1125,7 @@ func (containerManager KubernetesContainerManagerRepository) applyDeployment(cli
 	return nil
 }
 
-// Add secrets to the application
-func (containerManager KubernetesContainerManagerRepository) applySecrets(clientset *kubernetes.Clientset, deployApplication commands.ApplyApplication) (map[string]string, error) {
-	applicationNamespace := deployApplication.Namespace
-	applicationSecrets := deployApplication.Secrets
-
+func buildSecrets(deployApplication commands.ApplyApplication) (*v1.Secret, map[string]string) {
 	// While applying secrets -
 	//Error while creating secrets : Secret "A_SECRET_ENVIRONMENT_VARIABLE" is invalid:
 	// => metadata.name: Invalid value: "A_SECRET_ENVIRONMENT_VARIABLE":
@@ -515,7 +1137,7 @@ func (containerManager KubernetesContainerManagerRepository) applySecrets(client
 
 	stringData := make(map[string]string)
 	secretOriginalKeyWithConvertedK8sKey := make(map[string]string)
-	for _, secret := range applicationSecrets {
+	for _, secret := range deployApplication.Secrets {
 		secretKey := strings.ToLower(secret.Name)
 		secretOriginalKeyWithConvertedK8sKey[secret.Name] = secretKey
 		secretVal := secret.Val
@@ -523,15 +1145,49 @@ func (containerManager KubernetesContainerManagerRepository) applySecrets(client
 		stringData[secretKey] = secretVal
 	}
 	secrets := &v1.Secret{
+		TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Secret"},
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      secretName,
-			Namespace: applicationNamespace,
+			Namespace: deployApplication.Namespace,
 		},
 		StringData: stringData,
 		Type:       v1.SecretTypeOpaque,
 	}
 
-	_, err := clientset.CoreV1().Secrets(applicationNamespace).Get(context.Background(), secretName, metav1.GetOptions{})
+	return secrets, secretOriginalKeyWithConvertedK8sKey
+}
+
+// buildResolvedSecrets builds the Kubernetes Secret of the application with the plain values of its secrets.
+// This is the only place where the secret values stored in the database are decrypted and where secret references are resolved.
+func (containerManager KubernetesContainerManagerRepository) buildResolvedSecrets(deployApplication commands.ApplyApplication) (*v1.Secret, map[string]string, error) {
+	decryptedSecrets, err := containerManager.SecretsCipher.Decrypt(deployApplication.Secrets)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error while decrypting secrets : %w", err)
+	}
+	resolvedSecrets, err := containerManager.SecretResolver.Resolve(deployApplication.NamespaceID, decryptedSecrets)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error while resolving secrets : %w", err)
+	}
+	deployApplication.Secrets = resolvedSecrets
+	secrets, secretOriginalKeyWithConvertedK8sKey := buildSecrets(deployApplication)
+	return secrets, secretOriginalKeyWithConvertedK8sKey, nil
+}
+
+// Add secrets to the application
+func (containerManager KubernetesContainerManagerRepository) applySecrets(clientset *kubernetes.Clientset, deployApplication commands.ApplyApplication) (map[string]string, error) {
+	applicationNamespace := deployApplication.Namespace
+	secrets, secretOriginalKeyWithConvertedK8sKey, err := containerManager.buildResolvedSecrets(deployApplication)
+	if err != nil {
+		return nil, &customErrors.ContainerManagerApplicationDeploymentError{
+			Message:         err.Error(),
+			ApplicationName: deployApplication.Name,
+			Namespace:       deployApplication.Namespace,
+			Image:           deployApplication.Image,
+		}
+	}
+	secretName := secrets.Name
+
+	_, err = clientset.CoreV1().Secrets(applicationNamespace).Get(context.Background(), secretName, metav1.GetOptions{})
 	if err == nil {
 		_, err = clientset.CoreV1().Secrets(applicationNamespace).Update(context.Background(), secrets, metav1.UpdateOptions{})
 		if err != nil {
@@ -559,6 +1215,54 @@ func (containerManager KubernetesContainerManagerRepository) applySecrets(client
 	return secretOriginalKeyWithConvertedK8sKey, nil
 }
 
+// RefreshApplicationSecrets updates the Kubernetes Secret of a deployed application when the values of its secrets changed,
+// and returns true if it was updated
+func (containerManager KubernetesContainerManagerRepository) RefreshApplicationSecrets(applyApplication commands.ApplyApplication) (bool, error) {
+	clientset, err := containerManager.connectToKubernetesAPI()
+	if err != nil {
+		return false, &customErrors.ContainerManagerError{
+			Message: fmt.Sprintf("Connecting to Kubernetes API while refreshing secrets failed : %s", err.Error()),
+		}
+	}
+
+	secrets, _, err := containerManager.buildResolvedSecrets(applyApplication)
+	if err != nil {
+		return false, &customErrors.ContainerManagerError{Message: err.Error()}
+	}
+	currentSecrets, err := clientset.CoreV1().Secrets(applyApplication.Namespace).Get(context.Background(), secrets.Name, metav1.GetOptions{})
+	if err != nil {
+		return false, &customErrors.ContainerManagerError{
+			Message: fmt.Sprintf("Error while retrieving secrets %s : %s", secrets.Name, err.Error()),
+		}
+	}
+	if secretDataEqual(currentSecrets.Data, secrets.StringData) {
+		return false, nil
+	}
+
+	_, err = clientset.CoreV1().Secrets(applyApplication.Namespace).Update(context.Background(), secrets, metav1.UpdateOptions{})
+	if err != nil {
+		return false, &customErrors.ContainerManagerError{
+			Message: fmt.Sprintf("Error while updating secrets %s : %s", secrets.Name, err.Error()),
+		}
+	}
+	fmt.Println("Secret refreshed successfully : " + secrets.Name + " in namespace " + applyApplication.Namespace)
+	return true, nil
+}
+
+// secretDataEqual returns true if the data of a live Kubernetes Secret has the given string data
+func secretDataEqual(data map[string][]byte, stringData map[string]string) bool {
+	if len(data) != len(stringData) {
+		return false
+	}
+	for key, value := range stringData {
+		currentValue, ok := data[key]
+		if !ok || string(currentValue) != value {
+			return false
+		}
+	}
+	return true
+}
+
 func FrenchReadableResourceUnitToKubernetesCPUUnit(resourceUnit domain.ContainerMemoryLimitUnit) string {
 	switch resourceUnit {
 	case domain.MB:
@@ -589,12 +1293,11 @@ func FrenchReadableResourceUnitToKubernetesMemoryUnit(resourceUnit domain.Contai
 	}
 }
 
-func (containerManager KubernetesContainerManagerRepository) applyService(clientset *kubernetes.Clientset, deployApplication commands.ApplyApplication) error {
-	applicationNamespace := deployApplication.Namespace
+func buildService(deployApplication commands.ApplyApplication) (*v1.Service, error) {
 	applicationName := deployApplication.Name
 	applicationPort := deployApplication.Port
 	servicePort := 80
-	serviceName := fmt.Sprintf("%s-service", applicationName)
+	serviceName := domain.ApplicationServiceName(applicationName)
 	deploymentName := fmt.Sprintf("%s-deployment", applicationName)
 
 	var serviceType v1.ServiceType
@@ -603,7 +1306,7 @@ func (containerManager KubernetesContainerManagerRepository) applyService(client
 	} else if deployApplication.ApplicationType == domain.LoadBalanced {
 		serviceType = v1.ServiceTypeLoadBalancer
 	} else {
-		return &customErrors.ContainerManagerApplicationDeploymentError{
+		return nil, &customErrors.ContainerManagerApplicationDeploymentError{
 			Message:         fmt.Sprintf("Error while creating service : %s", "Application type not supported"),
 			ApplicationName: deployApplication.Name,
 			Namespace:       deployApplication.Namespace,
@@ -611,27 +1314,68 @@ func (containerManager KubernetesContainerManagerRepository) applyService(client
 		}
 	}
 
-	service := &v1.Service{
+	ports := []v1.ServicePort{
+		{
+			Protocol:   v1.ProtocolTCP,
+			Port:       int32(servicePort),
+			TargetPort: intstr.FromInt(int(applicationPort)),
+		},
+	}
+	// The applications of the namespace reach the application on its own port, as described by the environment variables of their dependencies
+	if applicationPort != uint32(servicePort) {
+		ports = append(ports, v1.ServicePort{
+			Name:       "app",
+			Protocol:   v1.ProtocolTCP,
+			Port:       int32(applicationPort),
+			TargetPort: intstr.FromInt(int(applicationPort)),
+		})
+	}
+	// Routes to other container ports are exposed on other ports of the service
+	for _, route := range deployApplication.Ingress.ResolvedRoutes(applicationName, applicationPort) {
+		routePort := routeServicePort(deployApplication, route.Port)
+		exposed := false
+		for _, port := range ports {
+			exposed = exposed || port.Port == routePort
+		}
+		if !exposed {
+			ports = append(ports, v1.ServicePort{
+				Name:       fmt.Sprintf("port-%d", route.Port),
+				Protocol:   v1.ProtocolTCP,
+				Port:       routePort,
+				TargetPort: intstr.FromInt(int(route.Port)),
+			})
+		}
+	}
+	// The ports of a service exposing several of them must be named
+	if len(ports) > 1 {
+		ports[0].Name = "http"
+	}
+
+	return &v1.Service{
+		TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Service"},
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      serviceName,
-			Namespace: applicationNamespace,
+			Namespace: deployApplication.Namespace,
 		},
 		Spec: v1.ServiceSpec{
 			Selector: map[string]string{
 				"app": deploymentName,
 			},
-			Ports: []v1.ServicePort{
-				{
-					Protocol:   v1.ProtocolTCP,
-					Port:       int32(servicePort),
-					TargetPort: intstr.FromInt(int(applicationPort)),
-				},
-			},
-			Type: serviceType,
+			Ports: ports,
+			Type:  serviceType,
 		},
+	}, nil
+}
+
+func (containerManager KubernetesContainerManagerRepository) applyService(clientset *kubernetes.Clientset, deployApplication commands.ApplyApplication) error {
+	service, err := buildService(deployApplication)
+	if err != nil {
+		return err
 	}
+	applicationNamespace := deployApplication.Namespace
+	serviceName := service.Name
 
-	_, err := clientset.CoreV1().Services(applicationNamespace).Get(context.Background(), serviceName, metav1.GetOptions{})
+	_, err = clientset.CoreV1().Services(applicationNamespace).Get(context.Background(), serviceName, metav1.GetOptions{})
 	if err == nil {
 		_, err = clientset.CoreV1().Services(applicationNamespace).Update(context.Background(), service, metav1.UpdateOptions{})
 		if err != nil {
@@ -658,258 +1402,1123 @@ func (containerManager KubernetesContainerManagerRepository) applyService(client
 	return nil
 }
 
-func (containerManager KubernetesContainerManagerRepository) applyIngress(clientset *kubernetes.Clientset, deployApplication commands.ApplyApplication) error {
+// ingressName is the name of the Ingress of the routes of an application keeping their path prefix
+func ingressName(applicationName string) string {
+	return fmt.Sprintf("%s-ingress", applicationName)
+}
+
+// stripPathPrefixIngressName is the name of the Ingress of the routes of an application stripping their path prefix,
+// nginx rewrites the paths of a whole Ingress so these routes cannot share the Ingress of the others
+func stripPathPrefixIngressName(applicationName string) string {
+	return fmt.Sprintf("%s-ingress-strip-path-prefix", applicationName)
+}
+
+func basicAuthSecretName(applicationName string) string {
+	return fmt.Sprintf("%s-basic-auth", applicationName)
+}
+
+// routeHost returns the host of a route, which is <subdomain>.<namespace>.<domain>
+func routeHost(route domain.ApplicationRoute, namespace string) string {
+	return fmt.Sprintf("%s.%s.%s", route.Subdomain, namespace, os.Getenv("DOMAIN_NAME"))
+}
+
+// routeServicePort returns the port of the service forwarding to the container port of a route:
+// the port of the application is exposed on 80 and the other ones on their own number
+func routeServicePort(deployApplication commands.ApplyApplication, containerPort uint32) int32 {
+	if containerPort == deployApplication.Port {
+		return 80
+	}
+	return int32(containerPort)
+}
+
+// buildIngressAnnotations renders the request policies of an application as nginx ingress annotations
+func buildIngressAnnotations(deployApplication commands.ApplyApplication) map[string]string {
+	ingress := deployApplication.Ingress
+	annotations := map[string]string{
+		"cert-manager.io/cluster-issuer": "letsencrypt",
+	}
+	if ingress.MaxBodySizeMegabytes > 0 {
+		annotations["nginx.ingress.kubernetes.io/proxy-body-size"] = fmt.Sprintf("%dm", ingress.MaxBodySizeMegabytes)
+	}
+	if ingress.ConnectTimeoutSeconds > 0 {
+		annotations["nginx.ingress.kubernetes.io/proxy-connect-timeout"] = fmt.Sprintf("%d", ingress.ConnectTimeoutSeconds)
+	}
+	if ingress.ReadTimeoutSeconds > 0 {
+		annotations["nginx.ingress.kubernetes.io/proxy-read-timeout"] = fmt.Sprintf("%d", ingress.ReadTimeoutSeconds)
+	}
+	if ingress.SendTimeoutSeconds > 0 {
+		annotations["nginx.ingress.kubernetes.io/proxy-send-timeout"] = fmt.Sprintf("%d", ingress.SendTimeoutSeconds)
+	}
+	if len(ingress.ResponseHeaders) > 0 {
+		// Sorted so that the annotation, and therefore the Ingress, does not change between two deploys
+		headerNames := make([]string, 0, len(ingress.ResponseHeaders))
+		for name := range ingress.ResponseHeaders {
+			headerNames = append(headerNames, name)
+		}
+		sort.Strings(headerNames)
+		snippet := ""
+		for _, name := range headerNames {
+			snippet += fmt.Sprintf("more_set_headers \"%s: %s\";\n", name, ingress.ResponseHeaders[name])
+		}
+		annotations["nginx.ingress.kubernetes.io/configuration-snippet"] = snippet
+	}
+	if ingress.BasicAuth != nil {
+		annotations["nginx.ingress.kubernetes.io/auth-type"] = "basic"
+		annotations["nginx.ingress.kubernetes.io/auth-secret"] = basicAuthSecretName(deployApplication.Name)
+		annotations["nginx.ingress.kubernetes.io/auth-realm"] = "Authentication required"
+	}
+	if len(ingress.AllowedSourceRanges) > 0 {
+		annotations["nginx.ingress.kubernetes.io/whitelist-source-range"] = strings.Join(ingress.AllowedSourceRanges, ",")
+	}
+	if len(ingress.DeniedSourceRanges) > 0 {
+		annotations["nginx.ingress.kubernetes.io/denylist-source-range"] = strings.Join(ingress.DeniedSourceRanges, ",")
+	}
+	return annotations
+}
+
+// buildIngress builds the Ingress of routes that all keep or all strip their path prefix
+func buildIngress(deployApplication commands.ApplyApplication, name string, routes []domain.ApplicationRoute, stripPathPrefix bool) *v13.Ingress {
 	applicationNamespace := deployApplication.Namespace
-	applicationName := deployApplication.Name
-	ingressName := fmt.Sprintf("%s-ingress", applicationName)
-	serviceName := fmt.Sprintf("%s-service", applicationName)
-	servicePort := 80
-	domainName := os.Getenv("DOMAIN_NAME")
-	ingress := v13.Ingress{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      ingressName,
-			Namespace: applicationNamespace,
-			Annotations: map[string]string{
-				"nginx.ingress.kubernetes.io/rewrite-target": "/",
-				"cert-manager.io/cluster-issuer":             "letsencrypt",
+	serviceName := fmt.Sprintf("%s-service", deployApplication.Name)
+	annotations := buildIngressAnnotations(deployApplication)
+	pathType := v13.PathTypePrefix
+	if stripPathPrefix {
+		// The path prefix is matched as a regex whose second group is the rest of the path sent to the application
+		annotations["nginx.ingress.kubernetes.io/use-regex"] = "true"
+		annotations["nginx.ingress.kubernetes.io/rewrite-target"] = "/$2"
+		pathType = v13.PathTypeImplementationSpecific
+	}
+
+	hosts := []string{}
+	rules := []v13.IngressRule{}
+	rulesByHost := map[string]int{}
+	for _, route := range routes {
+		host := routeHost(route, applicationNamespace)
+		ruleIndex, exists := rulesByHost[host]
+		if !exists {
+			hosts = append(hosts, host)
+			ruleIndex = len(rules)
+			rulesByHost[host] = ruleIndex
+			rules = append(rules, v13.IngressRule{
+				Host:             host,
+				IngressRuleValue: v13.IngressRuleValue{HTTP: &v13.HTTPIngressRuleValue{}},
+			})
+		}
+
+		path := route.PathPrefix
+		if stripPathPrefix {
+			path = regexp.QuoteMeta(route.PathPrefix) + "(/|$)(.*)"
+		}
+		routePathType := pathType
+		rules[ruleIndex].HTTP.Paths = append(rules[ruleIndex].HTTP.Paths, v13.HTTPIngressPath{
+			Path:     path,
+			PathType: &routePathType,
+			Backend: v13.IngressBackend{
+				Service: &v13.IngressServiceBackend{
+					Name: serviceName,
+					Port: v13.ServiceBackendPort{
+						Number: routeServicePort(deployApplication, route.Port),
+					},
+				},
 			},
+		})
+	}
+
+	return &v13.Ingress{
+		TypeMeta: metav1.TypeMeta{APIVersion: "networking.k8s.io/v1", Kind: "Ingress"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   applicationNamespace,
+			Annotations: annotations,
 		},
 		Spec: v13.IngressSpec{
 			IngressClassName: func() *string { s := "nginx"; return &s }(),
 			TLS: []v13.IngressTLS{
 				{
-					Hosts: []string{
-						fmt.Sprintf("%s.%s.%s", applicationName, applicationNamespace, domainName),
-					},
+					Hosts:      hosts,
 					SecretName: "letsencrypt-account-key",
 				},
 			},
-			Rules: []v13.IngressRule{
-				{
-					Host: fmt.Sprintf("%s.%s.%s", applicationName, applicationNamespace, domainName),
-					IngressRuleValue: v13.IngressRuleValue{
-						HTTP: &v13.HTTPIngressRuleValue{
-							Paths: []v13.HTTPIngressPath{
-								{
-									Path:     "/",
-									PathType: func() *v13.PathType { p := v13.PathTypePrefix; return &p }(),
-									Backend: v13.IngressBackend{
-										Service: &v13.IngressServiceBackend{
-											Name: serviceName,
-											Port: v13.ServiceBackendPort{
-												Number: int32(servicePort),
-											},
-										},
-									},
-								},
-							},
-						},
-					},
-				},
-			},
+			Rules: rules,
+		},
+	}
+}
+
+// buildIngresses builds the Ingresses of the routes of an application, there is no Ingress for a kind of route it does not use
+func buildIngresses(deployApplication commands.ApplyApplication) []*v13.Ingress {
+	routes := []domain.ApplicationRoute{}
+	stripPathPrefixRoutes := []domain.ApplicationRoute{}
+	for _, route := range deployApplication.Ingress.ResolvedRoutes(deployApplication.Name, deployApplication.Port) {
+		if route.StripPathPrefix {
+			stripPathPrefixRoutes = append(stripPathPrefixRoutes, route)
+		} else {
+			routes = append(routes, route)
+		}
+	}
+
+	ingresses := []*v13.Ingress{}
+	if len(routes) > 0 {
+		ingresses = append(ingresses, buildIngress(deployApplication, ingressName(deployApplication.Name), routes, false))
+	}
+	if len(stripPathPrefixRoutes) > 0 {
+		ingresses = append(ingresses, buildIngress(deployApplication, stripPathPrefixIngressName(deployApplication.Name), stripPathPrefixRoutes, true))
+	}
+	return ingresses
+}
+
+// buildBasicAuthSecret builds the htpasswd Secret read by nginx, nil if the application has no basic auth
+func buildBasicAuthSecret(deployApplication commands.ApplyApplication) *v1.Secret {
+	basicAuth := deployApplication.Ingress.BasicAuth
+	if basicAuth == nil {
+		return nil
+	}
+	return &v1.Secret{
+		TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Secret"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      basicAuthSecretName(deployApplication.Name),
+			Namespace: deployApplication.Namespace,
+		},
+		Type: v1.SecretTypeOpaque,
+		Data: map[string][]byte{
+			"auth": []byte(basicAuth.Htpasswd()),
 		},
 	}
+}
+
+func (containerManager KubernetesContainerManagerRepository) applyBasicAuthSecret(clientset *kubernetes.Clientset, deployApplication commands.ApplyApplication) error {
+	applicationNamespace := deployApplication.Namespace
+	secret := buildBasicAuthSecret(deployApplication)
+	if secret == nil {
+		err := clientset.CoreV1().Secrets(applicationNamespace).Delete(context.Background(), basicAuthSecretName(deployApplication.Name), metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("error while deleting basic auth secret : %w", err)
+		}
+		return nil
+	}
 
-	_, err := clientset.NetworkingV1().Ingresses(applicationNamespace).Get(context.Background(), ingressName, metav1.GetOptions{})
+	_, err := clientset.CoreV1().Secrets(applicationNamespace).Get(context.Background(), secret.Name, metav1.GetOptions{})
 	if err == nil {
-		_, err = clientset.NetworkingV1().Ingresses(applicationNamespace).Update(context.Background(), &ingress, metav1.UpdateOptions{})
+		_, err = clientset.CoreV1().Secrets(applicationNamespace).Update(context.Background(), secret, metav1.UpdateOptions{})
+		if err != nil {
+			return &customErrors.ContainerManagerApplicationDeploymentError{
+				Message:         fmt.Sprintf("Error while updating basic auth secret : %s", err.Error()),
+				ApplicationName: deployApplication.Name,
+				Namespace:       deployApplication.Namespace,
+				Image:           deployApplication.Image,
+			}
+		}
+	} else {
+		_, err = clientset.CoreV1().Secrets(applicationNamespace).Create(context.Background(), secret, metav1.CreateOptions{})
 		if err != nil {
 			return &customErrors.ContainerManagerApplicationDeploymentError{
-				Message:         fmt.Sprintf("Error while updating ingress : %s", err.Error()),
+				Message:         fmt.Sprintf("Error while creating basic auth secret : %s", err.Error()),
 				ApplicationName: deployApplication.Name,
 				Namespace:       deployApplication.Namespace,
 				Image:           deployApplication.Image,
 			}
 		}
+	}
+
+	fmt.Println("Basic auth secret applied successfully : " + secret.Name + " in namespace " + applicationNamespace)
+	return nil
+}
+
+func (containerManager KubernetesContainerManagerRepository) applyIngress(clientset *kubernetes.Clientset, deployApplication commands.ApplyApplication) error {
+	applicationNamespace := deployApplication.Namespace
+	ingresses := buildIngresses(deployApplication)
+	appliedIngresses := map[string]bool{}
+
+	for _, ingress := range ingresses {
+		appliedIngresses[ingress.Name] = true
+
+		_, err := clientset.NetworkingV1().Ingresses(applicationNamespace).Get(context.Background(), ingress.Name, metav1.GetOptions{})
+		if err == nil {
+			_, err = clientset.NetworkingV1().Ingresses(applicationNamespace).Update(context.Background(), ingress, metav1.UpdateOptions{})
+			if err != nil {
+				return &customErrors.ContainerManagerApplicationDeploymentError{
+					Message:         fmt.Sprintf("Error while updating ingress : %s", err.Error()),
+					ApplicationName: deployApplication.Name,
+					Namespace:       deployApplication.Namespace,
+					Image:           deployApplication.Image,
+				}
+			}
+		} else {
+			_, err = clientset.NetworkingV1().Ingresses(applicationNamespace).Create(context.Background(), ingress, metav1.CreateOptions{})
+			if err != nil {
+				return &customErrors.ContainerManagerApplicationDeploymentError{
+					Message:         fmt.Sprintf("Error while creating ingress : %s", err.Error()),
+					ApplicationName: deployApplication.Name,
+					Namespace:       deployApplication.Namespace,
+					Image:           deployApplication.Image,
+				}
+			}
+		}
+
+		fmt.Println("Ingress created successfully : " + ingress.Name + " in namespace " + applicationNamespace)
+	}
+
+	// The Ingress of a kind of route the application no longer uses would keep routing its former routes
+	for _, name := range []string{ingressName(deployApplication.Name), stripPathPrefixIngressName(deployApplication.Name)} {
+		if appliedIngresses[name] {
+			continue
+		}
+		err := clientset.NetworkingV1().Ingresses(applicationNamespace).Delete(context.Background(), name, metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return &customErrors.ContainerManagerApplicationDeploymentError{
+				Message:         fmt.Sprintf("Error while deleting ingress : %s", err.Error()),
+				ApplicationName: deployApplication.Name,
+				Namespace:       deployApplication.Namespace,
+				Image:           deployApplication.Image,
+			}
+		}
+	}
+	return nil
+}
+
+func (containerManager KubernetesContainerManagerRepository) GetApplicationLogs(deployApplication commands.GetApplicationLogs) ([]domain.ApplicationLogs, error) {
+	applicationNamespace := deployApplication.Namespace
+	applicationName := deployApplication.Name
+	deploymentName := fmt.Sprintf("%s-deployment", applicationName)
+
+	clientset, err := containerManager.connectToKubernetesAPI()
+	if err != nil {
+		return nil, &customErrors.ContainerManagerError{
+			Message: fmt.Sprintf("Connecting to Kubernetes API while getting application logs failed : %s", err.Error()),
+		}
+	}
+	podList, err := clientset.CoreV1().Pods(applicationNamespace).List(context.Background(), metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("app=%s", deploymentName),
+	})
+	if err != nil {
+		return nil, &customErrors.ContainerManagerError{
+			Message: fmt.Sprintf("Getting pods while getting application logs failed : %s", err.Error()),
+		}
+	}
+	if len(podList.Items) == 0 {
+		return nil, &customErrors.ContainerManagerError{
+			Message: fmt.Sprintf("No pod found for application %s in namespace %s", applicationName, applicationNamespace),
+		}
+	}
+
+	logs := make([]domain.ApplicationLogs, 0)
+	podLogOptions := v1.PodLogOptions{
+		Timestamps: true,
+	}
+
+	for _, pod := range podList.Items {
+		request := clientset.CoreV1().Pods(applicationNamespace).GetLogs(pod.Name, &podLogOptions)
+		podLogs, err := request.Stream(context.Background())
+		if err != nil {
+			return nil, &customErrors.ContainerManagerError{
+				Message: fmt.Sprintf("Opening stream to pod %s while getting application logs failed : %s", pod.Name, err.Error()),
+			}
+		}
+		defer podLogs.Close()
+
+		buf := new(bytes.Buffer)
+		if _, err = io.Copy(buf, podLogs); err != nil {
+			return nil, &customErrors.ContainerManagerError{
+				Message: fmt.Sprintf(
+					"Reading stream from pod %s while getting application logs failed : %s", pod.Name, err.Error(),
+				),
+			}
+		}
+
+		logs = append(logs, domain.ApplicationLogs{
+			PodName: pod.Name,
+			Logs:    buf.String(),
+		})
+	}
+
+	return logs, nil
+}
+
+// GetApplicationEvents returns the events of the namespace about the objects of an application.
+// Pods are matched by the names of the ReplicaSets of the Deployment, so that the events of deleted pods are kept while Kubernetes keeps them.
+func (containerManager KubernetesContainerManagerRepository) GetApplicationEvents(getApplicationEvents commands.GetApplicationEvents) ([]domain.ApplicationEvent, error) {
+	applicationNamespace := getApplicationEvents.Namespace
+	deploymentName := fmt.Sprintf("%s-deployment", getApplicationEvents.Name)
+
+	clientset, err := containerManager.connectToKubernetesAPI()
+	if err != nil {
+		return nil, &customErrors.ContainerManagerError{
+			Message: fmt.Sprintf("Connecting to Kubernetes API while getting application events failed : %s", err.Error()),
+		}
+	}
+	replicaSetList, err := clientset.AppsV1().ReplicaSets(applicationNamespace).List(context.Background(), metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("app=%s", deploymentName),
+	})
+	if err != nil {
+		return nil, &customErrors.ContainerManagerError{
+			Message: fmt.Sprintf("Getting replica sets while getting application events failed : %s", err.Error()),
+		}
+	}
+	podList, err := clientset.CoreV1().Pods(applicationNamespace).List(context.Background(), metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("app=%s", deploymentName),
+	})
+	if err != nil {
+		return nil, &customErrors.ContainerManagerError{
+			Message: fmt.Sprintf("Getting pods while getting application events failed : %s", err.Error()),
+		}
+	}
+
+	replicaSetNames := make([]string, 0, len(replicaSetList.Items))
+	for _, replicaSet := range replicaSetList.Items {
+		replicaSetNames = append(replicaSetNames, replicaSet.Name)
+	}
+	events := []v1.Event{}
+	for _, fieldSelector := range applicationEventFieldSelectors(getApplicationEvents.Name, replicaSetNames) {
+		eventList, err := clientset.CoreV1().Events(applicationNamespace).List(context.Background(), metav1.ListOptions{
+			FieldSelector: fieldSelector,
+		})
+		if err != nil {
+			return nil, &customErrors.ContainerManagerError{
+				Message: fmt.Sprintf("Getting events while getting application events failed : %s", err.Error()),
+			}
+		}
+		events = append(events, eventList.Items...)
+	}
+
+	applicationEvents := buildApplicationEvents(getApplicationEvents.Name, replicaSetNames, events)
+	return append(applicationEvents, buildContainerTerminationEvents(podList.Items)...), nil
+}
+
+// applicationEventFieldSelectors returns the field selectors listing the events about the objects of an application rather than the whole namespace.
+// Pods are named after their ReplicaSet followed by a random suffix, so the events of the pods are selected by kind only.
+func applicationEventFieldSelectors(applicationName string, replicaSetNames []string) []string {
+	involvedObjects := [][2]string{
+		{domain.DeploymentEventObject, fmt.Sprintf("%s-deployment", applicationName)},
+		{domain.ServiceEventObject, fmt.Sprintf("%s-service", applicationName)},
+		{domain.IngressEventObject, ingressName(applicationName)},
+		{domain.IngressEventObject, stripPathPrefixIngressName(applicationName)},
+	}
+	for _, replicaSetName := range replicaSetNames {
+		involvedObjects = append(involvedObjects, [2]string{domain.ReplicaSetEventObject, replicaSetName})
+	}
+
+	fieldSelectors := []string{fields.OneTermEqualSelector("involvedObject.kind", domain.PodEventObject).String()}
+	for _, involvedObject := range involvedObjects {
+		fieldSelectors = append(fieldSelectors, fields.AndSelectors(
+			fields.OneTermEqualSelector("involvedObject.kind", involvedObject[0]),
+			fields.OneTermEqualSelector("involvedObject.name", involvedObject[1]),
+		).String())
+	}
+	return fieldSelectors
+}
+
+// buildContainerTerminationEvents reports the containers killed for running out of memory as events of their pods.
+// Kubernetes records no event when it happens, the reason is only kept in the state of the container until it terminates again.
+func buildContainerTerminationEvents(pods []v1.Pod) []domain.ApplicationEvent {
+	applicationEvents := []domain.ApplicationEvent{}
+	for _, pod := range pods {
+		for _, containerStatus := range pod.Status.ContainerStatuses {
+			for _, containerState := range []v1.ContainerState{containerStatus.LastTerminationState, containerStatus.State} {
+				if containerState.Terminated == nil || containerState.Terminated.Reason != domain.OOMKilledEventReason {
+					continue
+				}
+				applicationEvents = append(applicationEvents, domain.ApplicationEvent{
+					Type:           domain.WarningApplicationEvent,
+					Reason:         domain.OOMKilledEventReason,
+					Message:        fmt.Sprintf("Container %s was killed because it ran out of memory", containerStatus.Name),
+					ObjectKind:     domain.PodEventObject,
+					ObjectName:     pod.Name,
+					Count:          1,
+					FirstTimestamp: containerState.Terminated.FinishedAt.Time,
+					LastTimestamp:  containerState.Terminated.FinishedAt.Time,
+				})
+			}
+		}
+	}
+	return applicationEvents
+}
+
+// buildApplicationEvents keeps the events about the Deployment, the ReplicaSets and their Pods, the Service and the Ingresses of an application
+func buildApplicationEvents(applicationName string, replicaSetNames []string, events []v1.Event) []domain.ApplicationEvent {
+	objectNames := map[string]map[string]bool{
+		domain.DeploymentEventObject: {fmt.Sprintf("%s-deployment", applicationName): true},
+		domain.ReplicaSetEventObject: {},
+		domain.ServiceEventObject:    {fmt.Sprintf("%s-service", applicationName): true},
+		domain.IngressEventObject:    {ingressName(applicationName): true, stripPathPrefixIngressName(applicationName): true},
+	}
+	for _, replicaSetName := range replicaSetNames {
+		objectNames[domain.ReplicaSetEventObject][replicaSetName] = true
+	}
+	isApplicationObject := func(objectReference v1.ObjectReference) bool {
+		if objectReference.Kind != domain.PodEventObject {
+			return objectNames[objectReference.Kind][objectReference.Name]
+		}
+		// Pods are named after their ReplicaSet followed by a random suffix
+		separatorIndex := strings.LastIndex(objectReference.Name, "-")
+		return separatorIndex > 0 && objectNames[domain.ReplicaSetEventObject][objectReference.Name[:separatorIndex]]
+	}
+
+	applicationEvents := []domain.ApplicationEvent{}
+	for _, event := range events {
+		if !isApplicationObject(event.InvolvedObject) {
+			continue
+		}
+		applicationEvent := domain.ApplicationEvent{
+			Type:           domain.ApplicationEventType(event.Type),
+			Reason:         event.Reason,
+			Message:        event.Message,
+			ObjectKind:     event.InvolvedObject.Kind,
+			ObjectName:     event.InvolvedObject.Name,
+			Count:          event.Count,
+			FirstTimestamp: event.FirstTimestamp.Time,
+			LastTimestamp:  event.LastTimestamp.Time,
+		}
+		// Events recorded through the events.k8s.io API only have an event time, and a series when they repeat
+		if applicationEvent.FirstTimestamp.IsZero() {
+			applicationEvent.FirstTimestamp = event.EventTime.Time
+		}
+		if event.Series != nil {
+			applicationEvent.Count = event.Series.Count
+			applicationEvent.LastTimestamp = event.Series.LastObservedTime.Time
+		}
+		applicationEvents = append(applicationEvents, applicationEvent)
+	}
+	return applicationEvents
+}
+
+// restartedAtAnnotation is set on the pod template of a deployment to roll its pods out, like kubectl rollout restart does
+const restartedAtAnnotation = "cloud-app-hive/restartedAt"
+
+func (containerManager KubernetesContainerManagerRepository) RestartApplication(restartApplication commands.RestartApplication) error {
+	clientset, err := containerManager.connectToKubernetesAPI()
+	if err != nil {
+		return &customErrors.ContainerManagerError{
+			Message: fmt.Sprintf("Connecting to Kubernetes API while restarting application failed : %s", err.Error()),
+		}
+	}
+
+	patch, err := json.Marshal(map[string]interface{}{
+		"spec": map[string]interface{}{
+			"template": map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"annotations": map[string]string{
+						restartedAtAnnotation: time.Now().Format(time.RFC3339),
+					},
+				},
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	deploymentName := fmt.Sprintf("%s-deployment", restartApplication.Name)
+	_, err = clientset.AppsV1().Deployments(restartApplication.Namespace).Patch(context.Background(), deploymentName, types.StrategicMergePatchType, patch, metav1.PatchOptions{})
+	if err != nil {
+		return &customErrors.ContainerManagerError{
+			Message: fmt.Sprintf("Restarting deployment %s failed : %s", deploymentName, err.Error()),
+		}
+	}
+
+	fmt.Println("Deployment restarted successfully : " + deploymentName + " in namespace " + restartApplication.Namespace)
+	return nil
+}
+
+func (containerManager KubernetesContainerManagerRepository) UnapplyApplication(
+	unapplyApplication commands.UnapplyApplication,
+) error {
+	applicationNamespace := unapplyApplication.Namespace
+	applicationName := unapplyApplication.Name
+
+	clientset, err := containerManager.connectToKubernetesAPI()
+	if err != nil {
+		return &customErrors.ContainerManagerError{
+			Message: fmt.Sprintf("Connecting to Kubernetes API while unapplying application failed : %s", err.Error()),
+		}
+	}
+
+	reporter := unapplyApplication.StepReporter
+	err = domain.RunOperationStep(reporter, domain.IngressOperationStep, func() error {
+		if err := containerManager.deleteIngress(clientset, unapplyApplication); err != nil {
+			// TODO: Redeploy application if ingress deletion failed ?
+			return &customErrors.ContainerManagerError{
+				Message: fmt.Sprintf("Deleting ingress while unapplying application failed : %s", err.Error()),
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	err = domain.RunOperationStep(reporter, domain.ServiceOperationStep, func() error {
+		if err := containerManager.deleteService(clientset, unapplyApplication); err != nil {
+			return &customErrors.ContainerManagerError{
+				Message: fmt.Sprintf("Deleting service while unapplying application failed : %s", err.Error()),
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	err = domain.RunOperationStep(reporter, domain.DeploymentOperationStep, func() error {
+		if err := containerManager.deleteDeployment(clientset, unapplyApplication); err != nil {
+			return &customErrors.ContainerManagerError{
+				Message: fmt.Sprintf("Deleting deployment while unapplying application failed : %s", err.Error()),
+			}
+		}
+
+		if err := containerManager.deletePods(clientset, unapplyApplication); err != nil {
+			return &customErrors.ContainerManagerError{
+				Message: fmt.Sprintf("Deleting pods while unapplying application failed : %s", err.Error()),
+			}
+		}
+
+		loadBalancerNetworkPolicyName := fmt.Sprintf("%s-allow-load-balancer", applicationName)
+		if err := containerManager.deleteNetworkPolicy(clientset, applicationNamespace, loadBalancerNetworkPolicyName); err != nil {
+			return &customErrors.ContainerManagerError{
+				Message: fmt.Sprintf("Deleting network policy while unapplying application failed : %s", err.Error()),
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	err = domain.RunOperationStep(reporter, domain.SecretsOperationStep, func() error {
+		if err := containerManager.deleteConfigFiles(clientset, unapplyApplication); err != nil {
+			return &customErrors.ContainerManagerError{
+				Message: fmt.Sprintf("Deleting config files while unapplying application failed : %s", err.Error()),
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("Application deleted successfully : " + applicationName + " in namespace " + applicationNamespace)
+	return nil
+}
+
+func (containerManager KubernetesContainerManagerRepository) deleteIngress(clientset *kubernetes.Clientset, deployApplication commands.UnapplyApplication) error {
+	applicationNamespace := deployApplication.Namespace
+	applicationName := deployApplication.Name
+	// An application only has the Ingresses of the kinds of route it uses
+	for _, name := range []string{ingressName(applicationName), stripPathPrefixIngressName(applicationName)} {
+		err := clientset.NetworkingV1().Ingresses(applicationNamespace).Delete(context.Background(), name, metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return &customErrors.ContainerManagerApplicationRemoveError{
+				Message:         fmt.Sprintf("Error deleting ingress : %s", err.Error()),
+				ApplicationName: applicationName,
+				Namespace:       applicationNamespace,
+			}
+		}
+	}
+	err := clientset.CoreV1().Secrets(applicationNamespace).Delete(context.Background(), basicAuthSecretName(applicationName), metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return &customErrors.ContainerManagerApplicationRemoveError{
+			Message:         fmt.Sprintf("Error deleting basic auth secret : %s", err.Error()),
+			ApplicationName: applicationName,
+			Namespace:       applicationNamespace,
+		}
+	}
+	fmt.Println("Ingress deleted successfully : " + applicationName)
+	return nil
+}
+
+func (containerManager KubernetesContainerManagerRepository) deleteService(clientset *kubernetes.Clientset, deployApplication commands.UnapplyApplication) error {
+	applicationNamespace := deployApplication.Namespace
+	applicationName := deployApplication.Name
+	serviceName := fmt.Sprintf("%s-service", applicationName)
+	err := clientset.CoreV1().Services(applicationNamespace).Delete(context.Background(), serviceName, metav1.DeleteOptions{})
+	if err != nil {
+		return &customErrors.ContainerManagerApplicationRemoveError{
+			Message:         fmt.Sprintf("Error deleting service : %s", err.Error()),
+			ApplicationName: applicationName,
+			Namespace:       applicationNamespace,
+		}
+	}
+	fmt.Println("Service deleted successfully : " + applicationName)
+	return nil
+}
+
+func (containerManager KubernetesContainerManagerRepository) deleteDeployment(clientset *kubernetes.Clientset, deployApplication commands.UnapplyApplication) error {
+	applicationNamespace := deployApplication.Namespace
+	applicationName := deployApplication.Name
+	deploymentName := fmt.Sprintf("%s-deployment", applicationName)
+	err := clientset.AppsV1().Deployments(applicationNamespace).Delete(context.Background(), deploymentName, metav1.DeleteOptions{})
+	if err != nil {
+		return &customErrors.ContainerManagerApplicationRemoveError{
+			Message:         fmt.Sprintf("Error deleting deployment : %s", err.Error()),
+			ApplicationName: applicationName,
+			Namespace:       applicationNamespace,
+		}
+	}
+	fmt.Println("Deployment deleted successfully : " + applicationName)
+	return nil
+}
+
+func (containerManager KubernetesContainerManagerRepository) deletePods(clientset *kubernetes.Clientset, deployApplication commands.UnapplyApplication) error {
+	applicationNamespace := deployApplication.Namespace
+	applicationName := deployApplication.Name
+	deploymentName := fmt.Sprintf("%s-deployment", applicationName)
+	podList, err := clientset.CoreV1().Pods(applicationNamespace).List(context.Background(), metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("app=%s", deploymentName),
+	})
+	if err != nil {
+		return &customErrors.ContainerManagerApplicationRemoveError{
+			Message:         fmt.Sprintf("Error getting pods : %s", err.Error()),
+			ApplicationName: applicationName,
+			Namespace:       applicationNamespace,
+		}
+	}
+	for _, pod := range podList.Items {
+		err = clientset.CoreV1().Pods(applicationNamespace).Delete(context.Background(), pod.Name, metav1.DeleteOptions{})
+		if err != nil {
+			return &customErrors.ContainerManagerApplicationRemoveError{
+				Message:         fmt.Sprintf("Error deleting pod : %s", err.Error()),
+				ApplicationName: applicationName,
+				Namespace:       applicationNamespace,
+			}
+		}
+		fmt.Println("Pod deleted successfully : " + pod.Name)
+	}
+	return nil
+}
+
+// addonLabel marks the objects of an add-on, with the name of the add-on as value
+const addonLabel = "cloud-app-hive/addon"
+
+func addonCredentialsSecretName(addonName string) string {
+	return fmt.Sprintf("%s-addon-credentials", addonName)
+}
+
+// addonDataVolumeName is the name of the volume claim template of the add-ons, their claims are named data-<name>-addon-0
+const addonDataVolumeName = "data"
+
+// addonDataPaths are the directories where the add-ons store their data
+var addonDataPaths = map[domain.AddonType]string{
+	domain.MySQLAddon:      "/var/lib/mysql",
+	domain.PostgreSQLAddon: "/var/lib/postgresql/data",
+	domain.RedisAddon:      "/data",
+}
+
+func addonLabels(addonName string) map[string]string {
+	return map[string]string{
+		"app":      domain.AddonServiceName(addonName),
+		addonLabel: addonName,
+	}
+}
+
+// buildAddonSecret builds the Secret holding the credentials of an add-on, with the password as given:
+// decrypted when applied
+func buildAddonSecret(applyAddon commands.ApplyAddon) *v1.Secret {
+	return &v1.Secret{
+		TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Secret"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      addonCredentialsSecretName(applyAddon.Name),
+			Namespace: applyAddon.Namespace,
+			Labels:    addonLabels(applyAddon.Name),
+		},
+		StringData: map[string]string{
+			"username": applyAddon.Username,
+			"password": applyAddon.Password.Val,
+			"database": applyAddon.Database,
+		},
+		Type: v1.SecretTypeOpaque,
+	}
+}
+
+func buildAddonService(applyAddon commands.ApplyAddon) *v1.Service {
+	port := int32(applyAddon.Type.Port())
+	return &v1.Service{
+		TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Service"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      domain.AddonServiceName(applyAddon.Name),
+			Namespace: applyAddon.Namespace,
+			Labels:    addonLabels(applyAddon.Name),
+		},
+		Spec: v1.ServiceSpec{
+			Selector: addonLabels(applyAddon.Name),
+			Ports: []v1.ServicePort{
+				{
+					Name:       string(applyAddon.Type),
+					Protocol:   v1.ProtocolTCP,
+					Port:       port,
+					TargetPort: intstr.FromInt(int(port)),
+				},
+			},
+			Type: v1.ServiceTypeClusterIP,
+		},
+	}
+}
+
+// addonCredentialEnv reads an environment variable of an add-on container from its credentials Secret
+func addonCredentialEnv(applyAddon commands.ApplyAddon, name string, key string) v1.EnvVar {
+	return v1.EnvVar{
+		Name: name,
+		ValueFrom: &v1.EnvVarSource{
+			SecretKeyRef: &v1.SecretKeySelector{
+				LocalObjectReference: v1.LocalObjectReference{Name: addonCredentialsSecretName(applyAddon.Name)},
+				Key:                  key,
+			},
+		},
+	}
+}
+
+// buildAddonContainer builds the container of an add-on, configured by the environment variables of its image
+func buildAddonContainer(applyAddon commands.ApplyAddon) (v1.Container, error) {
+	resources := applyAddon.Size.Resources()
+	quantities := v1.ResourceList{
+		v1.ResourceCPU:    resource.MustParse(resources.CPU),
+		v1.ResourceMemory: resource.MustParse(resources.Memory),
+	}
+	port := int32(applyAddon.Type.Port())
+	container := v1.Container{
+		Name:  domain.AddonServiceName(applyAddon.Name),
+		Image: applyAddon.Type.Image(),
+		Ports: []v1.ContainerPort{
+			{
+				Name:          string(applyAddon.Type),
+				ContainerPort: port,
+				Protocol:      v1.ProtocolTCP,
+			},
+		},
+		Resources: v1.ResourceRequirements{
+			Limits:   quantities,
+			Requests: quantities,
+		},
+		ReadinessProbe: &v1.Probe{
+			ProbeHandler: v1.ProbeHandler{
+				TCPSocket: &v1.TCPSocketAction{Port: intstr.FromInt(int(port))},
+			},
+			InitialDelaySeconds: 5,
+			PeriodSeconds:       10,
+		},
+		VolumeMounts: []v1.VolumeMount{
+			{
+				Name:      addonDataVolumeName,
+				MountPath: addonDataPaths[applyAddon.Type],
+			},
+		},
+	}
+
+	switch applyAddon.Type {
+	case domain.MySQLAddon:
+		container.Env = []v1.EnvVar{
+			addonCredentialEnv(applyAddon, "MYSQL_USER", "username"),
+			addonCredentialEnv(applyAddon, "MYSQL_PASSWORD", "password"),
+			addonCredentialEnv(applyAddon, "MYSQL_DATABASE", "database"),
+			{Name: "MYSQL_RANDOM_ROOT_PASSWORD", Value: "yes"},
+		}
+	case domain.PostgreSQLAddon:
+		container.Env = []v1.EnvVar{
+			addonCredentialEnv(applyAddon, "POSTGRES_USER", "username"),
+			addonCredentialEnv(applyAddon, "POSTGRES_PASSWORD", "password"),
+			addonCredentialEnv(applyAddon, "POSTGRES_DB", "database"),
+			// The root of a volume holds a lost+found directory that PostgreSQL refuses to initialize
+			{Name: "PGDATA", Value: addonDataPaths[domain.PostgreSQLAddon] + "/pgdata"},
+		}
+	case domain.RedisAddon:
+		container.Env = []v1.EnvVar{
+			addonCredentialEnv(applyAddon, "REDIS_PASSWORD", "password"),
+		}
+		container.Command = []string{"sh", "-c", `exec redis-server --requirepass "$REDIS_PASSWORD" --appendonly yes`}
+	default:
+		return v1.Container{}, fmt.Errorf("addon type %s not supported", applyAddon.Type)
+	}
+	return container, nil
+}
+
+// buildAddonStatefulSet builds the StatefulSet of an add-on, its single pod keeps its data on a volume claimed with the storage of the size tier
+func buildAddonStatefulSet(applyAddon commands.ApplyAddon) (*v12.StatefulSet, error) {
+	container, err := buildAddonContainer(applyAddon)
+	if err != nil {
+		return nil, err
+	}
+	replicas := int32(1)
+	labels := addonLabels(applyAddon.Name)
+
+	return &v12.StatefulSet{
+		TypeMeta: metav1.TypeMeta{APIVersion: "apps/v1", Kind: "StatefulSet"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      domain.AddonServiceName(applyAddon.Name),
+			Namespace: applyAddon.Namespace,
+			Labels:    labels,
+		},
+		Spec: v12.StatefulSetSpec{
+			ServiceName: domain.AddonServiceName(applyAddon.Name),
+			Replicas:    &replicas,
+			Selector: &metav1.LabelSelector{
+				MatchLabels: labels,
+			},
+			Template: v1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: labels,
+				},
+				Spec: v1.PodSpec{
+					Containers: []v1.Container{container},
+				},
+			},
+			VolumeClaimTemplates: []v1.PersistentVolumeClaim{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:   addonDataVolumeName,
+						Labels: labels,
+					},
+					Spec: v1.PersistentVolumeClaimSpec{
+						AccessModes: []v1.PersistentVolumeAccessMode{v1.ReadWriteOnce},
+						Resources: v1.ResourceRequirements{
+							Requests: v1.ResourceList{
+								v1.ResourceStorage: resource.MustParse(applyAddon.Size.Resources().Storage),
+							},
+						},
+					},
+				},
+			},
+		},
+	}, nil
+}
+
+// ApplyAddon creates the namespace of an add-on if needed, then its credentials Secret, its Service and its StatefulSet
+func (containerManager KubernetesContainerManagerRepository) ApplyAddon(applyAddon commands.ApplyAddon) error {
+	clientset, err := containerManager.connectToKubernetesAPI()
+	if err != nil {
+		return &customErrors.ContainerManagerError{
+			Message: fmt.Sprintf("Connecting to Kubernetes API while applying addon failed : %s", err.Error()),
+		}
+	}
+
+	// Add-ons can be created before the first application of their namespace is deployed
+	err = containerManager.applyNamespace(clientset, commands.ApplyApplication{Name: applyAddon.Name, Namespace: applyAddon.Namespace})
+	if err != nil {
+		return &customErrors.ContainerManagerError{
+			Message: "While applying namespace - " + err.Error(),
+		}
+	}
+	err = containerManager.applyNamespaceNetworkPolicies(clientset, applyAddon.Namespace)
+	if err != nil {
+		return &customErrors.ContainerManagerError{
+			Message: "While applying namespace network policies - " + err.Error(),
+		}
+	}
+
+	decryptedPassword, err := containerManager.SecretsCipher.Decrypt(domain.ApplicationSecrets{applyAddon.Password})
+	if err != nil {
+		return &customErrors.ContainerManagerError{
+			Message: fmt.Sprintf("While decrypting password of addon %s - %s", applyAddon.Name, err.Error()),
+		}
+	}
+	applyAddon.Password = decryptedPassword[0]
+
+	secret := buildAddonSecret(applyAddon)
+	secretsClient := clientset.CoreV1().Secrets(applyAddon.Namespace)
+	if _, err = secretsClient.Get(context.Background(), secret.Name, metav1.GetOptions{}); err == nil {
+		_, err = secretsClient.Update(context.Background(), secret, metav1.UpdateOptions{})
+	} else {
+		_, err = secretsClient.Create(context.Background(), secret, metav1.CreateOptions{})
+	}
+	if err != nil {
+		return &customErrors.ContainerManagerError{
+			Message: "While applying addon credentials secret - " + err.Error(),
+		}
+	}
+
+	service := buildAddonService(applyAddon)
+	servicesClient := clientset.CoreV1().Services(applyAddon.Namespace)
+	if _, err = servicesClient.Get(context.Background(), service.Name, metav1.GetOptions{}); err == nil {
+		_, err = servicesClient.Update(context.Background(), service, metav1.UpdateOptions{})
+	} else {
+		_, err = servicesClient.Create(context.Background(), service, metav1.CreateOptions{})
+	}
+	if err != nil {
+		return &customErrors.ContainerManagerError{
+			Message: "While applying addon service - " + err.Error(),
+		}
+	}
+
+	statefulSet, err := buildAddonStatefulSet(applyAddon)
+	if err != nil {
+		return &customErrors.ContainerManagerError{
+			Message: "While building addon stateful set - " + err.Error(),
+		}
+	}
+	statefulSetsClient := clientset.AppsV1().StatefulSets(applyAddon.Namespace)
+	if _, err = statefulSetsClient.Get(context.Background(), statefulSet.Name, metav1.GetOptions{}); err == nil {
+		_, err = statefulSetsClient.Update(context.Background(), statefulSet, metav1.UpdateOptions{})
 	} else {
-		_, err = clientset.NetworkingV1().Ingresses(applicationNamespace).Create(context.Background(), &ingress, metav1.CreateOptions{})
-		if err != nil {
-			return &customErrors.ContainerManagerApplicationDeploymentError{
-				Message:         fmt.Sprintf("Error while creating ingress : %s", err.Error()),
-				ApplicationName: deployApplication.Name,
-				Namespace:       deployApplication.Namespace,
-				Image:           deployApplication.Image,
-			}
+		_, err = statefulSetsClient.Create(context.Background(), statefulSet, metav1.CreateOptions{})
+	}
+	if err != nil {
+		return &customErrors.ContainerManagerError{
+			Message: "While applying addon stateful set - " + err.Error(),
 		}
 	}
 
-	fmt.Println("Ingress created successfully : " + ingressName + " in namespace " + applicationNamespace)
+	fmt.Println("Addon applied successfully : " + applyAddon.Name + " in namespace " + applyAddon.Namespace)
 	return nil
 }
 
-func (containerManager KubernetesContainerManagerRepository) GetApplicationLogs(deployApplication commands.GetApplicationLogs) ([]domain.ApplicationLogs, error) {
-	applicationNamespace := deployApplication.Namespace
-	applicationName := deployApplication.Name
-	deploymentName := fmt.Sprintf("%s-deployment", applicationName)
-
+// UnapplyAddon deletes the StatefulSet, Service and credentials Secret of an add-on, then the claims of its data
+func (containerManager KubernetesContainerManagerRepository) UnapplyAddon(unapplyAddon commands.UnapplyAddon) error {
 	clientset, err := containerManager.connectToKubernetesAPI()
 	if err != nil {
-		return nil, &customErrors.ContainerManagerError{
-			Message: fmt.Sprintf("Connecting to Kubernetes API while getting application logs failed : %s", err.Error()),
+		return &customErrors.ContainerManagerError{
+			Message: fmt.Sprintf("Connecting to Kubernetes API while unapplying addon failed : %s", err.Error()),
 		}
 	}
-	podList, err := clientset.CoreV1().Pods(applicationNamespace).List(context.Background(), metav1.ListOptions{
-		LabelSelector: fmt.Sprintf("app=%s", deploymentName),
-	})
-	if err != nil {
-		return nil, &customErrors.ContainerManagerError{
-			Message: fmt.Sprintf("Getting pods while getting application logs failed : %s", err.Error()),
+
+	name := domain.AddonServiceName(unapplyAddon.Name)
+	err = clientset.AppsV1().StatefulSets(unapplyAddon.Namespace).Delete(context.Background(), name, metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return &customErrors.ContainerManagerError{
+			Message: fmt.Sprintf("Deleting stateful set while unapplying addon failed : %s", err.Error()),
 		}
 	}
-	if len(podList.Items) == 0 {
-		return nil, &customErrors.ContainerManagerError{
-			Message: fmt.Sprintf("No pod found for application %s in namespace %s", applicationName, applicationNamespace),
+	err = clientset.CoreV1().Services(unapplyAddon.Namespace).Delete(context.Background(), name, metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return &customErrors.ContainerManagerError{
+			Message: fmt.Sprintf("Deleting service while unapplying addon failed : %s", err.Error()),
 		}
 	}
-
-	logs := make([]domain.ApplicationLogs, 0)
-	podLogOptions := v1.PodLogOptions{
-		Timestamps: true,
-	}
-
-	for _, pod := range podList.Items {
-		request := clientset.CoreV1().Pods(applicationNamespace).GetLogs(pod.Name, &podLogOptions)
-		podLogs, err := request.Stream(context.Background())
-		if err != nil {
-			return nil, &customErrors.ContainerManagerError{
-				Message: fmt.Sprintf("Opening stream to pod %s while getting application logs failed : %s", pod.Name, err.Error()),
-			}
+	err = clientset.CoreV1().Secrets(unapplyAddon.Namespace).Delete(context.Background(), addonCredentialsSecretName(unapplyAddon.Name), metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return &customErrors.ContainerManagerError{
+			Message: fmt.Sprintf("Deleting credentials secret while unapplying addon failed : %s", err.Error()),
 		}
-		defer podLogs.Close()
-
-		buf := new(bytes.Buffer)
-		if _, err = io.Copy(buf, podLogs); err != nil {
-			return nil, &customErrors.ContainerManagerError{
-				Message: fmt.Sprintf(
-					"Reading stream from pod %s while getting application logs failed : %s", pod.Name, err.Error(),
-				),
-			}
+	}
+	// Claims created from the volume claim templates outlive their StatefulSet
+	err = clientset.CoreV1().PersistentVolumeClaims(unapplyAddon.Namespace).DeleteCollection(context.Background(), metav1.DeleteOptions{}, metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", addonLabel, unapplyAddon.Name),
+	})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return &customErrors.ContainerManagerError{
+			Message: fmt.Sprintf("Deleting volume claims while unapplying addon failed : %s", err.Error()),
 		}
-
-		logs = append(logs, domain.ApplicationLogs{
-			PodName: pod.Name,
-			Logs:    buf.String(),
-		})
 	}
 
-	return logs, nil
+	fmt.Println("Addon deleted successfully : " + unapplyAddon.Name + " in namespace " + unapplyAddon.Namespace)
+	return nil
 }
 
-func (containerManager KubernetesContainerManagerRepository) UnapplyApplication(
-	unapplyApplication commands.UnapplyApplication,
-) error {
-	applicationNamespace := unapplyApplication.Namespace
-	applicationName := unapplyApplication.Name
+// configGroupLabel marks the objects of a config group, with the name of the group as value
+const configGroupLabel = "cloud-app-hive/config-group"
+
+// buildConfigGroupObjects builds the ConfigMap and the Secret of a config group, with the secrets as given:
+// decrypted and resolved when applied
+func buildConfigGroupObjects(applyConfigGroup commands.ApplyConfigGroup) (*v1.ConfigMap, *v1.Secret) {
+	labels := map[string]string{configGroupLabel: applyConfigGroup.Name}
+	data := make(map[string]string, len(applyConfigGroup.EnvironmentVariables))
+	for _, environmentVariable := range applyConfigGroup.EnvironmentVariables {
+		data[environmentVariable.Name] = environmentVariable.Val
+	}
+	stringData := make(map[string]string, len(applyConfigGroup.Secrets))
+	for _, secret := range applyConfigGroup.Secrets {
+		stringData[secret.Name] = secret.Val
+	}
+	configMap := &v1.ConfigMap{
+		TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      domain.ConfigGroupConfigMapName(applyConfigGroup.Name),
+			Namespace: applyConfigGroup.Namespace,
+			Labels:    labels,
+		},
+		Data: data,
+	}
+	secret := &v1.Secret{
+		TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Secret"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      domain.ConfigGroupSecretName(applyConfigGroup.Name),
+			Namespace: applyConfigGroup.Namespace,
+			Labels:    labels,
+		},
+		StringData: stringData,
+		Type:       v1.SecretTypeOpaque,
+	}
+	return configMap, secret
+}
 
+// ApplyConfigGroup creates or replaces the ConfigMap and the Secret of a config group
+func (containerManager KubernetesContainerManagerRepository) ApplyConfigGroup(applyConfigGroup commands.ApplyConfigGroup) error {
 	clientset, err := containerManager.connectToKubernetesAPI()
 	if err != nil {
 		return &customErrors.ContainerManagerError{
-			Message: fmt.Sprintf("Connecting to Kubernetes API while unapplying application failed : %s", err.Error()),
+			Message: fmt.Sprintf("Connecting to Kubernetes API while applying config group failed : %s", err.Error()),
 		}
 	}
 
-	if err = containerManager.deleteIngress(clientset, unapplyApplication); err != nil {
-		// TODO: Redeploy application if ingress deletion failed ?
+	// Config groups can be created before the first application of their namespace is deployed
+	err = containerManager.applyNamespace(clientset, commands.ApplyApplication{Name: applyConfigGroup.Name, Namespace: applyConfigGroup.Namespace})
+	if err != nil {
 		return &customErrors.ContainerManagerError{
-			Message: fmt.Sprintf("Deleting ingress while unapplying application failed : %s", err.Error()),
+			Message: "While applying namespace - " + err.Error(),
 		}
 	}
 
-	if err = containerManager.deleteService(clientset, unapplyApplication); err != nil {
+	decryptedSecrets, err := containerManager.SecretsCipher.Decrypt(applyConfigGroup.Secrets)
+	if err != nil {
 		return &customErrors.ContainerManagerError{
-			Message: fmt.Sprintf("Deleting service while unapplying application failed : %s", err.Error()),
+			Message: fmt.Sprintf("While decrypting secrets of config group %s - %s", applyConfigGroup.Name, err.Error()),
 		}
 	}
-
-	if err = containerManager.deleteDeployment(clientset, unapplyApplication); err != nil {
+	resolvedSecrets, err := containerManager.SecretResolver.Resolve(applyConfigGroup.NamespaceID, decryptedSecrets)
+	if err != nil {
 		return &customErrors.ContainerManagerError{
-			Message: fmt.Sprintf("Deleting deployment while unapplying application failed : %s", err.Error()),
+			Message: fmt.Sprintf("While resolving secrets of config group %s - %s", applyConfigGroup.Name, err.Error()),
 		}
 	}
+	applyConfigGroup.Secrets = resolvedSecrets
 
-	if err = containerManager.deletePods(clientset, unapplyApplication); err != nil {
-		return &customErrors.ContainerManagerError{
-			Message: fmt.Sprintf("Deleting pods while unapplying application failed : %s", err.Error()),
-		}
+	configMap, secret := buildConfigGroupObjects(applyConfigGroup)
+	configMapsClient := clientset.CoreV1().ConfigMaps(applyConfigGroup.Namespace)
+	if _, err = configMapsClient.Get(context.Background(), configMap.Name, metav1.GetOptions{}); err == nil {
+		_, err = configMapsClient.Update(context.Background(), configMap, metav1.UpdateOptions{})
+	} else {
+		_, err = configMapsClient.Create(context.Background(), configMap, metav1.CreateOptions{})
 	}
-
-	fmt.Println("Application deleted successfully : " + applicationName + " in namespace " + applicationNamespace)
-	return nil
-}
-
-func (containerManager KubernetesContainerManagerRepository) deleteIngress(clientset *kubernetes.Clientset, deployApplication commands.UnapplyApplication) error {
-	applicationNamespace := deployApplication.Namespace
-	applicationName := deployApplication.Name
-	ingressName := fmt.Sprintf("%s-ingress", applicationName)
-	err := clientset.NetworkingV1().Ingresses(applicationNamespace).Delete(context.Background(), ingressName, metav1.DeleteOptions{})
 	if err != nil {
-		return &customErrors.ContainerManagerApplicationRemoveError{
-			Message:         fmt.Sprintf("Error deleting ingress : %s", err.Error()),
-			ApplicationName: applicationName,
-			Namespace:       applicationNamespace,
+		return &customErrors.ContainerManagerError{
+			Message: "While applying config group config map - " + err.Error(),
 		}
 	}
-	fmt.Println("Ingress deleted successfully : " + applicationName)
-	return nil
-}
 
-func (containerManager KubernetesContainerManagerRepository) deleteService(clientset *kubernetes.Clientset, deployApplication commands.UnapplyApplication) error {
-	applicationNamespace := deployApplication.Namespace
-	applicationName := deployApplication.Name
-	serviceName := fmt.Sprintf("%s-service", applicationName)
-	err := clientset.CoreV1().Services(applicationNamespace).Delete(context.Background(), serviceName, metav1.DeleteOptions{})
+	secretsClient := clientset.CoreV1().Secrets(applyConfigGroup.Namespace)
+	if _, err = secretsClient.Get(context.Background(), secret.Name, metav1.GetOptions{}); err == nil {
+		_, err = secretsClient.Update(context.Background(), secret, metav1.UpdateOptions{})
+	} else {
+		_, err = secretsClient.Create(context.Background(), secret, metav1.CreateOptions{})
+	}
 	if err != nil {
-		return &customErrors.ContainerManagerApplicationRemoveError{
-			Message:         fmt.Sprintf("Error deleting service : %s", err.Error()),
-			ApplicationName: applicationName,
-			Namespace:       applicationNamespace,
+		return &customErrors.ContainerManagerError{
+			Message: "While applying config group secret - " + err.Error(),
 		}
 	}
-	fmt.Println("Service deleted successfully : " + applicationName)
+
+	fmt.Println("Config group applied successfully : " + applyConfigGroup.Name + " in namespace " + applyConfigGroup.Namespace)
 	return nil
 }
 
-func (containerManager KubernetesContainerManagerRepository) deleteDeployment(clientset *kubernetes.Clientset, deployApplication commands.UnapplyApplication) error {
-	applicationNamespace := deployApplication.Namespace
-	applicationName := deployApplication.Name
-	deploymentName := fmt.Sprintf("%s-deployment", applicationName)
-	err := clientset.AppsV1().Deployments(applicationNamespace).Delete(context.Background(), deploymentName, metav1.DeleteOptions{})
+// UnapplyConfigGroup deletes the ConfigMap and the Secret of a config group
+func (containerManager KubernetesContainerManagerRepository) UnapplyConfigGroup(unapplyConfigGroup commands.UnapplyConfigGroup) error {
+	clientset, err := containerManager.connectToKubernetesAPI()
 	if err != nil {
-		return &customErrors.ContainerManagerApplicationRemoveError{
-			Message:         fmt.Sprintf("Error deleting deployment : %s", err.Error()),
-			ApplicationName: applicationName,
-			Namespace:       applicationNamespace,
+		return &customErrors.ContainerManagerError{
+			Message: fmt.Sprintf("Connecting to Kubernetes API while unapplying config group failed : %s", err.Error()),
 		}
 	}
-	fmt.Println("Deployment deleted successfully : " + applicationName)
-	return nil
-}
 
-func (containerManager KubernetesContainerManagerRepository) deletePods(clientset *kubernetes.Clientset, deployApplication commands.UnapplyApplication) error {
-	applicationNamespace := deployApplication.Namespace
-	applicationName := deployApplication.Name
-	deploymentName := fmt.Sprintf("%s-deployment", applicationName)
-	podList, err := clientset.CoreV1().Pods(applicationNamespace).List(context.Background(), metav1.ListOptions{
-		LabelSelector: fmt.Sprintf("app=%s", deploymentName),
-	})
-	if err != nil {
-		return &customErrors.ContainerManagerApplicationRemoveError{
-			Message:         fmt.Sprintf("Error getting pods : %s", err.Error()),
-			ApplicationName: applicationName,
-			Namespace:       applicationNamespace,
+	err = clientset.CoreV1().ConfigMaps(unapplyConfigGroup.Namespace).Delete(context.Background(), domain.ConfigGroupConfigMapName(unapplyConfigGroup.Name), metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return &customErrors.ContainerManagerError{
+			Message: fmt.Sprintf("Deleting config map while unapplying config group failed : %s", err.Error()),
 		}
 	}
-	for _, pod := range podList.Items {
-		err = clientset.CoreV1().Pods(applicationNamespace).Delete(context.Background(), pod.Name, metav1.DeleteOptions{})
-		if err != nil {
-			return &customErrors.ContainerManagerApplicationRemoveError{
-				Message:         fmt.Sprintf("Error deleting pod : %s", err.Error()),
-				ApplicationName: applicationName,
-				Namespace:       applicationNamespace,
-			}
+	err = clientset.CoreV1().Secrets(unapplyConfigGroup.Namespace).Delete(context.Background(), domain.ConfigGroupSecretName(unapplyConfigGroup.Name), metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return &customErrors.ContainerManagerError{
+			Message: fmt.Sprintf("Deleting secret while unapplying config group failed : %s", err.Error()),
 		}
-		fmt.Println("Pod deleted successfully : " + pod.Name)
 	}
+
+	fmt.Println("Config group deleted successfully : " + unapplyConfigGroup.Name + " in namespace " + unapplyConfigGroup.Namespace)
 	return nil
 }
 
@@ -982,7 +2591,6 @@ func (containerManager KubernetesContainerManagerRepository) GetApplicationStatu
 	}
 	podList := domain.ConvertPods(pods)
 	podList.Items = domain.ComputeHumanizedPodStatus(&podList.Items)
-	fmt.Println(podList.Items[0].HumanizedStatus)
 
 	serviceName := fmt.Sprintf("%s-service", applicationName)
 	service, err := clientset.CoreV1().Services(applicationNamespace).Get(context.Background(), serviceName, metav1.GetOptions{})
@@ -995,11 +2603,15 @@ func (containerManager KubernetesContainerManagerRepository) GetApplicationStatu
 		}
 	}
 
-	ingressName := fmt.Sprintf("%s-ingress", applicationName)
-
 	ingress, err := clientset.NetworkingV1().Ingresses(applicationNamespace).Get(
-		context.Background(), ingressName, metav1.GetOptions{},
+		context.Background(), ingressName(applicationName), metav1.GetOptions{},
 	)
+	// An application whose routes all strip their path prefix only has the Ingress of these routes
+	if apierrors.IsNotFound(err) {
+		ingress, err = clientset.NetworkingV1().Ingresses(applicationNamespace).Get(
+			context.Background(), stripPathPrefixIngressName(applicationName), metav1.GetOptions{},
+		)
+	}
 	if err != nil {
 		return nil, &customErrors.ContainerManagerApplicationInformationError{
 			Message:         fmt.Sprintf("Getting ingress failed : %s", err.Error()),
@@ -1165,3 +2777,289 @@ func (containerManager KubernetesContainerManagerRepository) GetClusterMetrics()
 
 	return &clusterMetrics, nil
 }
+
+// DryRunApplication renders the objects ApplyApplication would send to the cluster and compares them with the live ones.
+// When a cluster is reachable the objects go through a server-side dry run, so that they are validated and defaulted
+// by the API server, otherwise they are only rendered locally.
+func (containerManager KubernetesContainerManagerRepository) DryRunApplication(
+	applyApplication commands.ApplyApplication,
+) ([]domain.KubernetesObjectDryRun, error) {
+	objects, err := buildApplicationObjects(applyApplication)
+	if err != nil {
+		return nil, err
+	}
+
+	clientset, err := containerManager.connectToKubernetesAPI()
+	if err != nil {
+		fmt.Println("No cluster available for a server-side dry run, rendering objects locally : ", err.Error())
+		return renderObjectsLocally(objects)
+	}
+
+	return dryRunObjects(clientset, objects)
+}
+
+// buildApplicationObjects builds every object ApplyApplication sends to the cluster, in the order they are applied
+func buildApplicationObjects(applyApplication commands.ApplyApplication) ([]runtime.Object, error) {
+	secrets, secretOriginalKeyWithConvertedK8sKey := buildSecrets(applyApplication)
+	objects := []runtime.Object{buildNamespace(applyApplication)}
+	for _, networkPolicy := range buildNamespaceNetworkPolicies(applyApplication.Namespace) {
+		objects = append(objects, networkPolicy)
+	}
+	objects = append(objects, secrets)
+	if usesPrivateRegistry(applyApplication.Registry) {
+		privateRegistrySecret, err := buildPrivateRegistrySecret(applyApplication)
+		if err != nil {
+			return nil, err
+		}
+		objects = append(objects, privateRegistrySecret)
+	}
+	if usesRegistryCredential(applyApplication) {
+		// Like the application secrets, the password is not decrypted for a dry run
+		registryCredentialSecret, err := buildRegistryCredentialSecret(applyApplication, *applyApplication.RegistryCredential)
+		if err != nil {
+			return nil, err
+		}
+		objects = append(objects, registryCredentialSecret)
+	}
+	configFilesConfigMap, secretFilesSecret, err := buildConfigFileObjects(applyApplication)
+	if err != nil {
+		return nil, err
+	}
+	if configFilesConfigMap != nil {
+		objects = append(objects, configFilesConfigMap)
+	}
+	if secretFilesSecret != nil {
+		objects = append(objects, secretFilesSecret)
+	}
+	deployment, err := buildDeployment(applyApplication, secretOriginalKeyWithConvertedK8sKey)
+	if err != nil {
+		return nil, err
+	}
+	service, err := buildService(applyApplication)
+	if err != nil {
+		return nil, err
+	}
+
+	objects = append(objects, deployment, service)
+	if basicAuthSecret := buildBasicAuthSecret(applyApplication); basicAuthSecret != nil {
+		objects = append(objects, basicAuthSecret)
+	}
+	for _, ingress := range buildIngresses(applyApplication) {
+		objects = append(objects, ingress)
+	}
+	if applyApplication.ApplicationType == domain.LoadBalanced {
+		objects = append(objects, buildLoadBalancerNetworkPolicy(applyApplication))
+	}
+	return objects, nil
+}
+
+func renderObjectsLocally(objects []runtime.Object) ([]domain.KubernetesObjectDryRun, error) {
+	objectsDryRun := make([]domain.KubernetesObjectDryRun, 0, len(objects))
+	for _, object := range objects {
+		metadata, err := meta.Accessor(object)
+		if err != nil {
+			return nil, err
+		}
+		rendered, err := renderKubernetesObject(object, object.GetObjectKind().GroupVersionKind())
+		if err != nil {
+			return nil, err
+		}
+		objectsDryRun = append(objectsDryRun, domain.NewKubernetesObjectDryRun(
+			object.GetObjectKind().GroupVersionKind().Kind, metadata.GetName(), metadata.GetNamespace(), rendered, "", false,
+		))
+	}
+	return objectsDryRun, nil
+}
+
+func dryRunObjects(clientset kubernetes.Interface, objects []runtime.Object) ([]domain.KubernetesObjectDryRun, error) {
+	ctx := context.Background()
+	getOptions := metav1.GetOptions{}
+	createOptions := metav1.CreateOptions{DryRun: []string{metav1.DryRunAll}}
+	updateOptions := metav1.UpdateOptions{DryRun: []string{metav1.DryRunAll}}
+
+	// Namespaced objects cannot be dry run server-side in a namespace that does not exist yet
+	namespaceExists := true
+	objectsDryRun := make([]domain.KubernetesObjectDryRun, 0, len(objects))
+	for _, object := range objects {
+		var objectDryRun domain.KubernetesObjectDryRun
+		var err error
+		switch typedObject := object.(type) {
+		case *v1.Namespace:
+			client := clientset.CoreV1().Namespaces()
+			objectDryRun, err = dryRunObject(typedObject,
+				func() (*v1.Namespace, error) { return client.Get(ctx, typedObject.Name, getOptions) },
+				func() (*v1.Namespace, error) { return client.Create(ctx, typedObject, createOptions) },
+				func() (*v1.Namespace, error) { return client.Update(ctx, typedObject, updateOptions) },
+				true,
+			)
+			namespaceExists = objectDryRun.Action != domain.DryRunCreate
+		case *v1.Secret:
+			client := clientset.CoreV1().Secrets(typedObject.Namespace)
+			objectDryRun, err = dryRunObject(typedObject,
+				func() (*v1.Secret, error) { return client.Get(ctx, typedObject.Name, getOptions) },
+				func() (*v1.Secret, error) { return client.Create(ctx, typedObject, createOptions) },
+				func() (*v1.Secret, error) { return client.Update(ctx, typedObject, updateOptions) },
+				namespaceExists,
+			)
+		case *v1.ConfigMap:
+			client := clientset.CoreV1().ConfigMaps(typedObject.Namespace)
+			objectDryRun, err = dryRunObject(typedObject,
+				func() (*v1.ConfigMap, error) { return client.Get(ctx, typedObject.Name, getOptions) },
+				func() (*v1.ConfigMap, error) { return client.Create(ctx, typedObject, createOptions) },
+				func() (*v1.ConfigMap, error) { return client.Update(ctx, typedObject, updateOptions) },
+				namespaceExists,
+			)
+		case *v12.Deployment:
+			client := clientset.AppsV1().Deployments(typedObject.Namespace)
+			objectDryRun, err = dryRunObject(typedObject,
+				func() (*v12.Deployment, error) { return client.Get(ctx, typedObject.Name, getOptions) },
+				func() (*v12.Deployment, error) { return client.Create(ctx, typedObject, createOptions) },
+				func() (*v12.Deployment, error) { return client.Update(ctx, typedObject, updateOptions) },
+				namespaceExists,
+			)
+		case *v1.Service:
+			client := clientset.CoreV1().Services(typedObject.Namespace)
+			objectDryRun, err = dryRunObject(typedObject,
+				func() (*v1.Service, error) { return client.Get(ctx, typedObject.Name, getOptions) },
+				func() (*v1.Service, error) { return client.Create(ctx, typedObject, createOptions) },
+				func() (*v1.Service, error) { return client.Update(ctx, typedObject, updateOptions) },
+				namespaceExists,
+			)
+		case *v13.Ingress:
+			client := clientset.NetworkingV1().Ingresses(typedObject.Namespace)
+			objectDryRun, err = dryRunObject(typedObject,
+				func() (*v13.Ingress, error) { return client.Get(ctx, typedObject.Name, getOptions) },
+				func() (*v13.Ingress, error) { return client.Create(ctx, typedObject, createOptions) },
+				func() (*v13.Ingress, error) { return client.Update(ctx, typedObject, updateOptions) },
+				namespaceExists,
+			)
+		case *v13.NetworkPolicy:
+			client := clientset.NetworkingV1().NetworkPolicies(typedObject.Namespace)
+			objectDryRun, err = dryRunObject(typedObject,
+				func() (*v13.NetworkPolicy, error) { return client.Get(ctx, typedObject.Name, getOptions) },
+				func() (*v13.NetworkPolicy, error) { return client.Create(ctx, typedObject, createOptions) },
+				func() (*v13.NetworkPolicy, error) { return client.Update(ctx, typedObject, updateOptions) },
+				namespaceExists,
+			)
+		default:
+			err = fmt.Errorf("unsupported object type %T for dry run", object)
+		}
+		if err != nil {
+			return nil, err
+		}
+		objectsDryRun = append(objectsDryRun, objectDryRun)
+	}
+
+	return objectsDryRun, nil
+}
+
+// dryRunObject fetches the live version of an object and, if allowed, sends the desired one to the API server in dry run mode
+func dryRunObject[T runtime.Object](
+	object T,
+	get func() (T, error),
+	create func() (T, error),
+	update func() (T, error),
+	serverSideDryRun bool,
+) (domain.KubernetesObjectDryRun, error) {
+	groupVersionKind := object.GetObjectKind().GroupVersionKind()
+	metadata, err := meta.Accessor(object)
+	if err != nil {
+		return domain.KubernetesObjectDryRun{}, err
+	}
+
+	live := ""
+	liveObject, err := get()
+	if err == nil {
+		live, err = renderKubernetesObject(liveObject, groupVersionKind)
+		if err != nil {
+			return domain.KubernetesObjectDryRun{}, err
+		}
+	} else if !apierrors.IsNotFound(err) {
+		return domain.KubernetesObjectDryRun{}, &customErrors.ContainerManagerError{
+			Message: fmt.Sprintf("Error while retrieving live %s %s : %s", groupVersionKind.Kind, metadata.GetName(), err.Error()),
+		}
+	}
+
+	renderedObject := object
+	if serverSideDryRun {
+		if live == "" {
+			renderedObject, err = create()
+		} else {
+			renderedObject, err = update()
+		}
+		if err != nil {
+			return domain.KubernetesObjectDryRun{}, &customErrors.ContainerManagerError{
+				Message: fmt.Sprintf("The cluster rejected %s %s during dry run : %s", groupVersionKind.Kind, metadata.GetName(), err.Error()),
+			}
+		}
+	}
+
+	rendered, err := renderKubernetesObject(renderedObject, groupVersionKind)
+	if err != nil {
+		return domain.KubernetesObjectDryRun{}, err
+	}
+
+	return domain.NewKubernetesObjectDryRun(
+		groupVersionKind.Kind, metadata.GetName(), metadata.GetNamespace(), rendered, live, serverSideDryRun,
+	), nil
+}
+
+// renderKubernetesObject renders an object as YAML without the fields managed by the cluster, so that live and desired objects can be compared
+func renderKubernetesObject(object runtime.Object, groupVersionKind schema.GroupVersionKind) (string, error) {
+	objectJSON, err := json.Marshal(object)
+	if err != nil {
+		return "", err
+	}
+	var fields map[string]interface{}
+	if err = json.Unmarshal(objectJSON, &fields); err != nil {
+		return "", err
+	}
+
+	fields["apiVersion"] = groupVersionKind.GroupVersion().String()
+	fields["kind"] = groupVersionKind.Kind
+	delete(fields, "status")
+	if metadata, ok := fields["metadata"].(map[string]interface{}); ok {
+		for _, field := range []string{"managedFields", "resourceVersion", "uid", "creationTimestamp", "generation", "selfLink"} {
+			delete(metadata, field)
+		}
+	}
+	if groupVersionKind.Kind == "Secret" {
+		redactSecretFields(fields)
+	}
+
+	objectYAML, err := yaml.Marshal(fields)
+	if err != nil {
+		return "", err
+	}
+	return string(objectYAML), nil
+}
+
+// redactSecretFields replaces secret values by a short hash: a diff still shows which keys change without leaking them
+func redactSecretFields(fields map[string]interface{}) {
+	data, _ := fields["data"].(map[string]interface{})
+	if data == nil {
+		data = map[string]interface{}{}
+	}
+	for key, value := range data {
+		decodedValue, err := base64.StdEncoding.DecodeString(fmt.Sprint(value))
+		if err != nil {
+			decodedValue = []byte(fmt.Sprint(value))
+		}
+		data[key] = redactSecretValue(decodedValue)
+	}
+	// The API server merges stringData into data, do the same so that both sides of the diff look alike
+	if stringData, ok := fields["stringData"].(map[string]interface{}); ok {
+		for key, value := range stringData {
+			data[key] = redactSecretValue([]byte(fmt.Sprint(value)))
+		}
+		delete(fields, "stringData")
+	}
+	if len(data) > 0 {
+		fields["data"] = data
+	}
+}
+
+func redactSecretValue(value []byte) string {
+	hash := sha256.Sum256(value)
+	return fmt.Sprintf("<redacted sha256:%x>", hash[:6])
+}
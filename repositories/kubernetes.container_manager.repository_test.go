@@ -0,0 +1,352 @@
+package repositories
+
+import (
+	"testing"
+	"time"
+
+	"cloud-app-hive/domain"
+	"cloud-app-hive/domain/commands"
+
+	v1 "k8s.io/api/core/v1"
+	v13 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestBuildIngresses_DefaultRoute(t *testing.T) {
+	t.Setenv("DOMAIN_NAME", "apps.example.com")
+	ingresses := buildIngresses(newTestApplyApplication(1))
+
+	if len(ingresses) != 1 || ingresses[0].Name != "api-ingress" {
+		t.Fatalf("expected the single ingress of the application, got %d", len(ingresses))
+	}
+	rule := ingresses[0].Spec.Rules[0]
+	if rule.Host != "api.team.apps.example.com" || rule.HTTP.Paths[0].Path != "/" || rule.HTTP.Paths[0].Backend.Service.Port.Number != 80 {
+		t.Errorf("expected / of the default host to reach the service port 80, got %+v", rule)
+	}
+	if _, exists := ingresses[0].Annotations["nginx.ingress.kubernetes.io/rewrite-target"]; exists {
+		t.Errorf("expected the paths not to be rewritten")
+	}
+}
+
+func TestBuildIngresses_RoutesAndPolicies(t *testing.T) {
+	t.Setenv("DOMAIN_NAME", "apps.example.com")
+	applyApplication := newTestApplyApplication(1)
+	applyApplication.Port = 3000
+	applyApplication.Ingress = domain.ApplicationIngress{
+		Routes: []domain.ApplicationRoute{
+			{Subdomain: "shop"},
+			{Subdomain: "shop", PathPrefix: "/api", Port: 8080, StripPathPrefix: true},
+			{Subdomain: "admin", PathPrefix: "/metrics", Port: 9090},
+		},
+		MaxBodySizeMegabytes: 50,
+		ReadTimeoutSeconds:   120,
+		ResponseHeaders:      map[string]string{"X-Frame-Options": "DENY", "Cache-Control": "no-store"},
+		BasicAuth:            &domain.ApplicationBasicAuth{Username: "admin", PasswordHash: "{SSHA}hash"},
+		AllowedSourceRanges:  []string{"10.0.0.0/8", "192.168.0.0/16"},
+	}
+
+	ingresses := buildIngresses(applyApplication)
+	if len(ingresses) != 2 {
+		t.Fatalf("expected an ingress for the routes keeping their path prefix and one for those stripping it, got %d", len(ingresses))
+	}
+	ingress, stripPathPrefixIngress := ingresses[0], ingresses[1]
+
+	if len(ingress.Spec.Rules) != 2 || ingress.Spec.Rules[0].Host != "shop.team.apps.example.com" || ingress.Spec.Rules[1].Host != "admin.team.apps.example.com" {
+		t.Fatalf("expected a rule per host, got %+v", ingress.Spec.Rules)
+	}
+	if port := ingress.Spec.Rules[1].HTTP.Paths[0].Backend.Service.Port.Number; port != 9090 {
+		t.Errorf("expected the container port 9090 to be exposed on its own number, got %d", port)
+	}
+	if len(ingress.Spec.TLS[0].Hosts) != 2 {
+		t.Errorf("expected a certificate for every host, got %v", ingress.Spec.TLS[0].Hosts)
+	}
+
+	path := stripPathPrefixIngress.Spec.Rules[0].HTTP.Paths[0]
+	if stripPathPrefixIngress.Name != "api-ingress-strip-path-prefix" || path.Path != "/api(/|$)(.*)" || *path.PathType != v13.PathTypeImplementationSpecific || path.Backend.Service.Port.Number != 8080 {
+		t.Errorf("expected the /api prefix to be stripped before reaching port 8080, got %+v", path)
+	}
+	if stripPathPrefixIngress.Annotations["nginx.ingress.kubernetes.io/rewrite-target"] != "/$2" {
+		t.Errorf("expected the stripped paths to be rewritten, got %v", stripPathPrefixIngress.Annotations)
+	}
+
+	for _, builtIngress := range ingresses {
+		annotations := builtIngress.Annotations
+		if annotations["nginx.ingress.kubernetes.io/proxy-body-size"] != "50m" || annotations["nginx.ingress.kubernetes.io/proxy-read-timeout"] != "120" {
+			t.Errorf("expected the body size and timeout annotations, got %v", annotations)
+		}
+		if annotations["nginx.ingress.kubernetes.io/configuration-snippet"] != "more_set_headers \"Cache-Control: no-store\";\nmore_set_headers \"X-Frame-Options: DENY\";\n" {
+			t.Errorf("expected the sorted response headers, got %q", annotations["nginx.ingress.kubernetes.io/configuration-snippet"])
+		}
+		if annotations["nginx.ingress.kubernetes.io/auth-secret"] != "api-basic-auth" || annotations["nginx.ingress.kubernetes.io/whitelist-source-range"] != "10.0.0.0/8,192.168.0.0/16" {
+			t.Errorf("expected the basic auth and source range annotations, got %v", annotations)
+		}
+	}
+
+	basicAuthSecret := buildBasicAuthSecret(applyApplication)
+	if string(basicAuthSecret.Data["auth"]) != "admin:{SSHA}hash\n" {
+		t.Errorf("expected the htpasswd of the basic auth, got %s", basicAuthSecret.Data["auth"])
+	}
+
+	service, err := buildService(applyApplication)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(service.Spec.Ports) != 4 || service.Spec.Ports[0].Name != "http" || service.Spec.Ports[1].Port != 3000 || service.Spec.Ports[2].Port != 8080 || service.Spec.Ports[3].Port != 9090 {
+		t.Errorf("expected the service to expose the port of the application and every routed container port, got %+v", service.Spec.Ports)
+	}
+}
+
+func TestBuildDeployment_DependencyEnvironmentVariables(t *testing.T) {
+	applyApplication := newTestApplyApplication(1)
+	applyApplication.EnvironmentVariables = domain.ApplicationEnvironmentVariables{
+		{Name: "HIVE_SVC_USERS_API_URL", Val: "http://users.example.com"},
+	}
+	applyApplication.Dependencies = domain.ApplicationDependencies{
+		{Name: "users-api", Port: 8080},
+		{Name: "billing", Port: 0},
+	}
+
+	deployment, err := buildDeployment(applyApplication, map[string]string{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	environmentVariables := map[string]string{}
+	for _, environmentVariable := range deployment.Spec.Template.Spec.Containers[0].Env {
+		if _, exists := environmentVariables[environmentVariable.Name]; exists {
+			t.Fatalf("expected %s to be defined once", environmentVariable.Name)
+		}
+		environmentVariables[environmentVariable.Name] = environmentVariable.Value
+	}
+	if environmentVariables["HIVE_SVC_USERS_API_HOST"] != "users-api-service.team.svc.cluster.local" || environmentVariables["HIVE_SVC_USERS_API_PORT"] != "8080" {
+		t.Errorf("expected the service of users-api to be described, got %v", environmentVariables)
+	}
+	if environmentVariables["HIVE_SVC_USERS_API_URL"] != "http://users.example.com" {
+		t.Errorf("expected the environment variable of the application to win, got %s", environmentVariables["HIVE_SVC_USERS_API_URL"])
+	}
+	if _, exists := environmentVariables["HIVE_SVC_BILLING_HOST"]; exists {
+		t.Errorf("expected a dependency missing from the namespace not to be described")
+	}
+}
+
+func TestBuildAddonStatefulSet(t *testing.T) {
+	applyAddon := commands.ApplyAddon{
+		Name:      "main-db",
+		Namespace: "team",
+		Type:      domain.MySQLAddon,
+		Size:      domain.MediumAddon,
+		Username:  "app",
+		Database:  "app",
+		Password:  domain.ApplicationSecret{Name: "password", Val: "secret"},
+	}
+
+	statefulSet, err := buildAddonStatefulSet(applyAddon)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if statefulSet.Name != "main-db-addon" || *statefulSet.Spec.Replicas != 1 {
+		t.Errorf("expected a single main-db-addon replica, got %s with %d replicas", statefulSet.Name, *statefulSet.Spec.Replicas)
+	}
+	container := statefulSet.Spec.Template.Spec.Containers[0]
+	if container.Image != "mysql:8.0" || container.Resources.Limits.Memory().String() != "1Gi" {
+		t.Errorf("expected the medium mysql image and resources, got %s with %s", container.Image, container.Resources.Limits.Memory())
+	}
+	storage := statefulSet.Spec.VolumeClaimTemplates[0].Spec.Resources.Requests[v1.ResourceStorage]
+	if storage.String() != "10Gi" || container.VolumeMounts[0].MountPath != "/var/lib/mysql" {
+		t.Errorf("expected 10Gi of storage mounted on /var/lib/mysql, got %s on %s", storage.String(), container.VolumeMounts[0].MountPath)
+	}
+	for _, environmentVariable := range container.Env {
+		if environmentVariable.Name == "MYSQL_PASSWORD" && (environmentVariable.ValueFrom == nil || environmentVariable.ValueFrom.SecretKeyRef.Name != "main-db-addon-credentials") {
+			t.Errorf("expected the password to be read from the credentials secret, got %+v", environmentVariable)
+		}
+	}
+
+	if secret := buildAddonSecret(applyAddon); secret.StringData["password"] != "secret" {
+		t.Errorf("expected the credentials secret to hold the password, got %v", secret.StringData)
+	}
+	if service := buildAddonService(applyAddon); service.Spec.Ports[0].Port != 3306 {
+		t.Errorf("expected the service to expose 3306, got %d", service.Spec.Ports[0].Port)
+	}
+}
+
+func TestBuildDeployment_ConfigGroups(t *testing.T) {
+	applyApplication := newTestApplyApplication(1)
+	applyApplication.ConfigGroups = []domain.ConfigGroup{
+		{Name: "shared", Version: 3},
+		{Name: "database", Version: 1},
+	}
+
+	deployment, err := buildDeployment(applyApplication, map[string]string{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	envFrom := deployment.Spec.Template.Spec.Containers[0].EnvFrom
+	if len(envFrom) != 4 {
+		t.Fatalf("expected a ConfigMap and a Secret for each group, got %+v", envFrom)
+	}
+	// Kubernetes gives precedence to the last source defining a name, so the groups keep their order
+	if envFrom[0].ConfigMapRef.Name != "shared-config-group" || envFrom[1].SecretRef.Name != "shared-config-group-secrets" ||
+		envFrom[2].ConfigMapRef.Name != "database-config-group" || envFrom[3].SecretRef.Name != "database-config-group-secrets" {
+		t.Errorf("expected the sources of shared then database, got %+v", envFrom)
+	}
+	if annotation := deployment.Spec.Template.Annotations[configGroupsAnnotation]; annotation != "shared:3,database:1" {
+		t.Errorf("expected the versions of the groups on the pod template, got %q", annotation)
+	}
+
+	applyApplication.ConfigGroups = nil
+	deployment, _ = buildDeployment(applyApplication, map[string]string{})
+	if len(deployment.Spec.Template.Spec.Containers[0].EnvFrom) != 0 || deployment.Spec.Template.Annotations != nil {
+		t.Errorf("expected an application without config groups to keep its pod template, got %+v", deployment.Spec.Template)
+	}
+}
+
+func TestBuildConfigGroupObjects(t *testing.T) {
+	configMap, secret := buildConfigGroupObjects(commands.ApplyConfigGroup{
+		Name:                 "shared",
+		Namespace:            "team",
+		EnvironmentVariables: domain.ApplicationEnvironmentVariables{{Name: "LOG_LEVEL", Val: "debug"}},
+		Secrets:              domain.ApplicationSecrets{{Name: "SENTRY_DSN", Val: "https://key@sentry.example.com/1"}},
+	})
+	if configMap.Name != "shared-config-group" || configMap.Data["LOG_LEVEL"] != "debug" {
+		t.Errorf("expected the environment variables in the ConfigMap, got %+v", configMap)
+	}
+	if secret.Name != "shared-config-group-secrets" || secret.StringData["SENTRY_DSN"] != "https://key@sentry.example.com/1" {
+		t.Errorf("expected the secrets in the Secret under their own names, got %+v", secret)
+	}
+}
+
+func TestBuildDeployment_ConfigFiles(t *testing.T) {
+	applyApplication := newTestApplyApplication(1)
+	applyApplication.ConfigFiles = domain.ApplicationConfigFiles{
+		{Name: "nginx.conf", MountPath: "/etc/nginx/nginx.conf", Content: "worker_processes 1;", Encoding: domain.TextConfigFile},
+		{Name: "tls.key", MountPath: "/etc/ssl/private/tls.key", Content: "enc:v1:key-id:c2VhbGVk", Encoding: domain.TextConfigFile, Secret: true},
+	}
+
+	deployment, err := buildDeployment(applyApplication, map[string]string{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	volumeMounts := deployment.Spec.Template.Spec.Containers[0].VolumeMounts
+	if len(volumeMounts) != 2 ||
+		volumeMounts[0].Name != configFilesVolumeName || volumeMounts[0].SubPath != "nginx.conf" || volumeMounts[0].MountPath != "/etc/nginx/nginx.conf" || !volumeMounts[0].ReadOnly ||
+		volumeMounts[1].Name != secretFilesVolumeName || volumeMounts[1].SubPath != "tls.key" {
+		t.Errorf("expected each file mounted read-only at its path, got %+v", volumeMounts)
+	}
+	volumes := deployment.Spec.Template.Spec.Volumes
+	if len(volumes) != 2 || volumes[0].ConfigMap.Name != "api-config-files" || volumes[1].Secret.SecretName != "api-secret-files" {
+		t.Errorf("expected the ConfigMap and the Secret of the files as volumes, got %+v", volumes)
+	}
+	checksum := deployment.Spec.Template.Annotations[configFilesAnnotationPrefix+"nginx.conf"]
+	if checksum == "" || deployment.Spec.Template.Annotations[configFilesAnnotationPrefix+"tls.key"] == "" {
+		t.Fatalf("expected the checksums of the files on the pod template, got %+v", deployment.Spec.Template.Annotations)
+	}
+
+	// Changing a file changes the pod template, so that the pods are rolled out
+	applyApplication.ConfigFiles[0].Content = "worker_processes 2;"
+	deployment, _ = buildDeployment(applyApplication, map[string]string{})
+	if deployment.Spec.Template.Annotations[configFilesAnnotationPrefix+"nginx.conf"] == checksum {
+		t.Errorf("expected the checksum of the file to change with its content")
+	}
+}
+
+func TestBuildConfigFileObjects(t *testing.T) {
+	applyApplication := newTestApplyApplication(1)
+	applyApplication.ConfigFiles = domain.ApplicationConfigFiles{
+		{Name: "nginx.conf", MountPath: "/etc/nginx/nginx.conf", Content: "worker_processes 1;", Encoding: domain.TextConfigFile},
+		{Name: "favicon.ico", MountPath: "/usr/share/nginx/html/favicon.ico", Content: "AAEC", Encoding: domain.Base64ConfigFile},
+		{Name: "tls.key", MountPath: "/etc/ssl/private/tls.key", Content: "AAEC", Encoding: domain.Base64ConfigFile, Secret: true},
+	}
+
+	configMap, secret, err := buildConfigFileObjects(applyApplication)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if configMap.Name != "api-config-files" || configMap.Data["nginx.conf"] != "worker_processes 1;" || string(configMap.BinaryData["favicon.ico"]) != "\x00\x01\x02" {
+		t.Errorf("expected the text and binary files in the ConfigMap, got %+v", configMap)
+	}
+	if secret.Name != "api-secret-files" || string(secret.Data["tls.key"]) != "\x00\x01\x02" {
+		t.Errorf("expected the decoded secret file in the Secret, got %+v", secret)
+	}
+
+	applyApplication.ConfigFiles = applyApplication.ConfigFiles[:1]
+	_, secret, _ = buildConfigFileObjects(applyApplication)
+	if secret != nil {
+		t.Errorf("expected no Secret without secret files, got %+v", secret)
+	}
+}
+
+func TestBuildApplicationEvents(t *testing.T) {
+	lastObservedTime := metav1.NewMicroTime(time.Date(2026, 10, 19, 12, 5, 0, 0, time.UTC))
+	event := func(kind string, name string, reason string) v1.Event {
+		return v1.Event{InvolvedObject: v1.ObjectReference{Kind: kind, Name: name}, Type: "Normal", Reason: reason, Count: 1}
+	}
+	probeFailure := event("Pod", "api-deployment-5d8f9c-x7k2p", "Unhealthy")
+	probeFailure.Type = "Warning"
+	probeFailure.Series = &v1.EventSeries{Count: 4, LastObservedTime: lastObservedTime}
+
+	applicationEvents := buildApplicationEvents("api", []string{"api-deployment-5d8f9c"}, []v1.Event{
+		event("Deployment", "api-deployment", "ScalingReplicaSet"),
+		event("ReplicaSet", "api-deployment-5d8f9c", "SuccessfulCreate"),
+		probeFailure,
+		event("Service", "api-service", "EnsuringLoadBalancer"),
+		event("Ingress", "api-ingress", "Sync"),
+		event("Ingress", "api-ingress-strip-path-prefix", "Sync"),
+		// Objects of other applications, "api-deployment" deploys the Deployment api-deployment-deployment
+		event("Deployment", "api-deployment-deployment", "ScalingReplicaSet"),
+		event("Pod", "api-deployment-deployment-7c4b2a-q9w4z", "Scheduled"),
+		event("Service", "web-service", "EnsuringLoadBalancer"),
+	})
+
+	if len(applicationEvents) != 6 {
+		t.Fatalf("expected the 6 events of the objects of api, got %+v", applicationEvents)
+	}
+	if applicationEvents[2].ObjectKind != domain.PodEventObject || applicationEvents[2].Type != domain.WarningApplicationEvent || applicationEvents[2].Count != 4 || !applicationEvents[2].LastTimestamp.Equal(lastObservedTime.Time) {
+		t.Errorf("expected the probe failure of the pod seen 4 times until its series last observation, got %+v", applicationEvents[2])
+	}
+}
+
+func TestApplicationEventFieldSelectors(t *testing.T) {
+	fieldSelectors := applicationEventFieldSelectors("api", []string{"api-deployment-5d8f9c"})
+
+	expectedFieldSelectors := map[string]bool{
+		"involvedObject.kind=Pod": true,
+		"involvedObject.kind=Deployment,involvedObject.name=api-deployment":             true,
+		"involvedObject.kind=Service,involvedObject.name=api-service":                   true,
+		"involvedObject.kind=Ingress,involvedObject.name=api-ingress":                   true,
+		"involvedObject.kind=Ingress,involvedObject.name=api-ingress-strip-path-prefix": true,
+		"involvedObject.kind=ReplicaSet,involvedObject.name=api-deployment-5d8f9c":      true,
+	}
+	if len(fieldSelectors) != len(expectedFieldSelectors) {
+		t.Fatalf("expected a field selector for each object of api, got %v", fieldSelectors)
+	}
+	for _, fieldSelector := range fieldSelectors {
+		if !expectedFieldSelectors[fieldSelector] {
+			t.Errorf("unexpected field selector %s", fieldSelector)
+		}
+	}
+}
+
+func TestBuildContainerTerminationEvents(t *testing.T) {
+	finishedAt := metav1.NewTime(time.Date(2026, 10, 19, 12, 5, 0, 0, time.UTC))
+	pod := func(name string, lastTerminationReason string) v1.Pod {
+		return v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Status: v1.PodStatus{ContainerStatuses: []v1.ContainerStatus{{
+				Name:                 "api",
+				State:                v1.ContainerState{Running: &v1.ContainerStateRunning{}},
+				LastTerminationState: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{Reason: lastTerminationReason, FinishedAt: finishedAt}},
+			}}},
+		}
+	}
+
+	applicationEvents := buildContainerTerminationEvents([]v1.Pod{
+		pod("api-deployment-5d8f9c-x7k2p", "OOMKilled"),
+		pod("api-deployment-5d8f9c-q9w4z", "Error"),
+	})
+
+	if len(applicationEvents) != 1 {
+		t.Fatalf("expected only the container killed for running out of memory to be reported, got %+v", applicationEvents)
+	}
+	if applicationEvents[0].Reason != domain.OOMKilledEventReason || applicationEvents[0].Type != domain.WarningApplicationEvent || applicationEvents[0].ObjectName != "api-deployment-5d8f9c-x7k2p" || !applicationEvents[0].LastTimestamp.Equal(finishedAt.Time) {
+		t.Errorf("expected a warning about the pod when its container was killed, got %+v", applicationEvents[0])
+	}
+}
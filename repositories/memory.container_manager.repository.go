@@ -0,0 +1,991 @@
+package repositories
+
+import (
+	customErrors "cloud-app-hive/controllers/errors"
+	"cloud-app-hive/domain"
+	"cloud-app-hive/domain/commands"
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// MemoryContainerManagerOperation is an enum that represents an operation of the container manager, used to inject failures
+type MemoryContainerManagerOperation string
+
+const (
+	MemoryApplyApplication      MemoryContainerManagerOperation = "ApplyApplication"
+	MemoryDryRunApplication     MemoryContainerManagerOperation = "DryRunApplication"
+	MemoryUnapplyApplication    MemoryContainerManagerOperation = "UnapplyApplication"
+	MemoryRestartApplication    MemoryContainerManagerOperation = "RestartApplication"
+	MemoryRefreshSecrets        MemoryContainerManagerOperation = "RefreshApplicationSecrets"
+	MemoryGetApplicationMetrics MemoryContainerManagerOperation = "GetApplicationMetrics"
+	MemoryGetApplicationLogs    MemoryContainerManagerOperation = "GetApplicationLogs"
+	MemoryGetApplicationStatus  MemoryContainerManagerOperation = "GetApplicationStatus"
+	MemoryGetApplicationEvents  MemoryContainerManagerOperation = "GetApplicationEvents"
+	MemoryApplyNetworkRules     MemoryContainerManagerOperation = "ApplyNetworkRules"
+	MemoryApplyAddon            MemoryContainerManagerOperation = "ApplyAddon"
+	MemoryUnapplyAddon          MemoryContainerManagerOperation = "UnapplyAddon"
+	MemoryApplyConfigGroup      MemoryContainerManagerOperation = "ApplyConfigGroup"
+	MemoryUnapplyConfigGroup    MemoryContainerManagerOperation = "UnapplyConfigGroup"
+	MemoryDeleteNamespace       MemoryContainerManagerOperation = "DeleteNamespace"
+	MemoryGetClusterMetrics     MemoryContainerManagerOperation = "GetClusterMetrics"
+)
+
+// Waiting reasons that can be given to SetApplicationFailure, they are the ones a real cluster reports most often
+const (
+	MemoryPodCrashLoopBackOff = "CrashLoopBackOff"
+	MemoryPodImagePullBackOff = "ImagePullBackOff"
+)
+
+// MemoryContainerManagerConfig is the configuration of the in-memory container manager
+type MemoryContainerManagerConfig struct {
+	// PodStartupDuration is the time a pod stays in ContainerCreating before running
+	PodStartupDuration time.Duration
+	// FailureRate is the probability, between 0 and 1, that any operation fails
+	FailureRate float64
+	// Nodes is the number of simulated cluster nodes
+	Nodes int
+	// NodeCPUCores and NodeMemoryGB are the capacities of each simulated node
+	NodeCPUCores int
+	NodeMemoryGB int
+}
+
+// MemoryContainerManagerConfigFromEnvironment reads the in-memory container manager configuration from the environment,
+// falling back to a 5 seconds pod startup, no random failures and 3 nodes of 4 cores and 8GB each
+func MemoryContainerManagerConfigFromEnvironment() (MemoryContainerManagerConfig, error) {
+	config := MemoryContainerManagerConfig{
+		PodStartupDuration: 5 * time.Second,
+		Nodes:              3,
+		NodeCPUCores:       4,
+		NodeMemoryGB:       8,
+	}
+
+	if podStartupSecondsStr := os.Getenv("MEMORY_CONTAINER_MANAGER_POD_STARTUP_SECONDS"); podStartupSecondsStr != "" {
+		podStartupSeconds, err := strconv.Atoi(podStartupSecondsStr)
+		if err != nil || podStartupSeconds < 0 {
+			return config, fmt.Errorf("MEMORY_CONTAINER_MANAGER_POD_STARTUP_SECONDS must be a positive integer")
+		}
+		config.PodStartupDuration = time.Duration(podStartupSeconds) * time.Second
+	}
+	if failureRateStr := os.Getenv("MEMORY_CONTAINER_MANAGER_FAILURE_RATE"); failureRateStr != "" {
+		failureRate, err := strconv.ParseFloat(failureRateStr, 64)
+		if err != nil || failureRate < 0 || failureRate > 1 {
+			return config, fmt.Errorf("MEMORY_CONTAINER_MANAGER_FAILURE_RATE must be a number between 0 and 1")
+		}
+		config.FailureRate = failureRate
+	}
+	if nodesStr := os.Getenv("MEMORY_CONTAINER_MANAGER_NODES"); nodesStr != "" {
+		nodes, err := strconv.Atoi(nodesStr)
+		if err != nil || nodes <= 0 {
+			return config, fmt.Errorf("MEMORY_CONTAINER_MANAGER_NODES must be a strictly positive integer")
+		}
+		config.Nodes = nodes
+	}
+
+	return config, nil
+}
+
+type memoryPod struct {
+	name         string
+	createdAt    time.Time
+	node         int
+	restartCount int32
+	logs         []string
+}
+
+type memoryDeployment struct {
+	applyApplication commands.ApplyApplication
+	pods             []*memoryPod
+	failureReason    string
+	// cpuUsagePercentage and memoryUsagePercentage override the synthetic usage of the pods when they are set
+	cpuUsagePercentage    *float64
+	memoryUsagePercentage *float64
+	updatedAt             time.Time
+}
+
+// MemoryContainerManagerRepository is a ContainerManagerRepository that simulates a cluster in memory.
+// Deployments, pod status transitions, logs and CPU/memory metrics are synthetic, and failures can be injected
+// either randomly through FailureRate or explicitly with InjectFailure and SetApplicationFailure.
+type MemoryContainerManagerRepository struct {
+	config      MemoryContainerManagerConfig
+	mutex       sync.Mutex
+	deployments map[string]*memoryDeployment
+	namespaces  map[string]bool
+	// networkRules are the last network rules applied on each namespace
+	networkRules map[string][]commands.ApplyNetworkRule
+	failures     map[MemoryContainerManagerOperation]int
+	random       *rand.Rand
+	podCounter   int
+	// addons are the add-ons provisioned on each namespace, by name
+	addons map[string]map[string]commands.ApplyAddon
+	// configGroups are the config groups rendered on each namespace, by name
+	configGroups map[string]map[string]commands.ApplyConfigGroup
+}
+
+func NewMemoryContainerManagerRepository(config MemoryContainerManagerConfig) *MemoryContainerManagerRepository {
+	if config.Nodes <= 0 {
+		config.Nodes = 1
+	}
+	return &MemoryContainerManagerRepository{
+		config:       config,
+		deployments:  make(map[string]*memoryDeployment),
+		namespaces:   make(map[string]bool),
+		networkRules: make(map[string][]commands.ApplyNetworkRule),
+		addons:       make(map[string]map[string]commands.ApplyAddon),
+		configGroups: make(map[string]map[string]commands.ApplyConfigGroup),
+		failures:     make(map[MemoryContainerManagerOperation]int),
+		random:       rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// InjectFailure makes the next count calls of an operation fail, a negative count makes every call fail until ClearFailures
+func (containerManager *MemoryContainerManagerRepository) InjectFailure(operation MemoryContainerManagerOperation, count int) {
+	containerManager.mutex.Lock()
+	defer containerManager.mutex.Unlock()
+	containerManager.failures[operation] = count
+}
+
+// ClearFailures removes every failure injected with InjectFailure and SetApplicationFailure
+func (containerManager *MemoryContainerManagerRepository) ClearFailures() {
+	containerManager.mutex.Lock()
+	defer containerManager.mutex.Unlock()
+	containerManager.failures = make(map[MemoryContainerManagerOperation]int)
+	for _, deployment := range containerManager.deployments {
+		deployment.failureReason = ""
+	}
+}
+
+// SetApplicationFailure keeps the pods of an application waiting with the given reason (e.g. CrashLoopBackOff), an empty reason heals them
+func (containerManager *MemoryContainerManagerRepository) SetApplicationFailure(namespace string, name string, reason string) error {
+	containerManager.mutex.Lock()
+	defer containerManager.mutex.Unlock()
+	deployment, ok := containerManager.deployments[memoryDeploymentKey(namespace, name)]
+	if !ok {
+		return &customErrors.ContainerManagerError{
+			Message: fmt.Sprintf("No deployment found for application %s in namespace %s", name, namespace),
+		}
+	}
+	deployment.failureReason = reason
+	return nil
+}
+
+// SetApplicationUsage fixes the CPU and memory usage of every pod of an application, as percentages of their limits
+func (containerManager *MemoryContainerManagerRepository) SetApplicationUsage(namespace string, name string, cpuUsagePercentage float64, memoryUsagePercentage float64) error {
+	containerManager.mutex.Lock()
+	defer containerManager.mutex.Unlock()
+	deployment, ok := containerManager.deployments[memoryDeploymentKey(namespace, name)]
+	if !ok {
+		return &customErrors.ContainerManagerError{
+			Message: fmt.Sprintf("No deployment found for application %s in namespace %s", name, namespace),
+		}
+	}
+	deployment.cpuUsagePercentage = &cpuUsagePercentage
+	deployment.memoryUsagePercentage = &memoryUsagePercentage
+	return nil
+}
+
+// DeployedApplications returns the names of the applications deployed in a namespace, sorted by name
+func (containerManager *MemoryContainerManagerRepository) DeployedApplications(namespace string) []string {
+	containerManager.mutex.Lock()
+	defer containerManager.mutex.Unlock()
+	var names []string
+	for _, deployment := range containerManager.deployments {
+		if deployment.applyApplication.Namespace == namespace {
+			names = append(names, deployment.applyApplication.Name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (containerManager *MemoryContainerManagerRepository) ApplyApplication(applyApplication commands.ApplyApplication) error {
+	containerManager.mutex.Lock()
+	defer containerManager.mutex.Unlock()
+	// The steps are reported like the Kubernetes container manager does, the reporter is not part of the deployed application
+	reporter := applyApplication.StepReporter
+	applyApplication.StepReporter = nil
+
+	err := domain.RunOperationStep(reporter, domain.NamespaceOperationStep, func() error {
+		if err := containerManager.injectedFailure(MemoryApplyApplication); err != nil {
+			return err
+		}
+		containerManager.namespaces[applyApplication.Namespace] = true
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if err = domain.RunOperationStep(reporter, domain.SecretsOperationStep, func() error { return nil }); err != nil {
+		return err
+	}
+
+	err = domain.RunOperationStep(reporter, domain.DeploymentOperationStep, func() error {
+		// Building the objects validates the application the same way the Kubernetes container manager does
+		if _, err := buildApplicationObjects(applyApplication); err != nil {
+			return err
+		}
+
+		now := time.Now()
+		key := memoryDeploymentKey(applyApplication.Namespace, applyApplication.Name)
+		deployment, ok := containerManager.deployments[key]
+		if !ok {
+			deployment = &memoryDeployment{}
+			containerManager.deployments[key] = deployment
+		}
+
+		// A change of the pod template rolls every pod out, a change of replicas only adds or removes pods
+		podTemplateChanged := !ok || !memoryPodTemplatesEqual(deployment.applyApplication, applyApplication)
+		deployment.applyApplication = applyApplication
+		deployment.updatedAt = now
+		if podTemplateChanged {
+			deployment.pods = nil
+		}
+		replicas := int(applyApplication.ScalabilitySpecifications.Replicas)
+		if replicas <= 0 {
+			replicas = 1
+		}
+		for len(deployment.pods) < replicas {
+			deployment.pods = append(deployment.pods, containerManager.newPod(applyApplication, now))
+		}
+		deployment.pods = deployment.pods[:replicas]
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if err = domain.RunOperationStep(reporter, domain.ServiceOperationStep, func() error { return nil }); err != nil {
+		return err
+	}
+	if err = domain.RunOperationStep(reporter, domain.IngressOperationStep, func() error { return nil }); err != nil {
+		return err
+	}
+
+	fmt.Println("Application deployed in memory : " + applyApplication.Name + " in namespace " + applyApplication.Namespace)
+	return nil
+}
+
+func (containerManager *MemoryContainerManagerRepository) DryRunApplication(applyApplication commands.ApplyApplication) ([]domain.KubernetesObjectDryRun, error) {
+	containerManager.mutex.Lock()
+	defer containerManager.mutex.Unlock()
+	if err := containerManager.injectedFailure(MemoryDryRunApplication); err != nil {
+		return nil, err
+	}
+
+	objects, err := buildApplicationObjects(applyApplication)
+	if err != nil {
+		return nil, err
+	}
+
+	// The live objects are the ones rendered from the last applied version of the application
+	liveObjectsByKindAndName := make(map[string]string)
+	if deployment, ok := containerManager.deployments[memoryDeploymentKey(applyApplication.Namespace, applyApplication.Name)]; ok {
+		liveObjects, err := buildApplicationObjects(deployment.applyApplication)
+		if err != nil {
+			return nil, err
+		}
+		for _, liveObject := range liveObjects {
+			key, err := memoryObjectKey(liveObject)
+			if err != nil {
+				return nil, err
+			}
+			rendered, err := renderKubernetesObject(liveObject, liveObject.GetObjectKind().GroupVersionKind())
+			if err != nil {
+				return nil, err
+			}
+			liveObjectsByKindAndName[key] = rendered
+		}
+	} else if containerManager.namespaces[applyApplication.Namespace] {
+		namespace := buildNamespace(applyApplication)
+		rendered, err := renderKubernetesObject(namespace, namespace.GroupVersionKind())
+		if err != nil {
+			return nil, err
+		}
+		liveObjectsByKindAndName["Namespace/"+namespace.Name] = rendered
+	}
+
+	objectsDryRun := make([]domain.KubernetesObjectDryRun, 0, len(objects))
+	for _, object := range objects {
+		metadata, err := meta.Accessor(object)
+		if err != nil {
+			return nil, err
+		}
+		key, err := memoryObjectKey(object)
+		if err != nil {
+			return nil, err
+		}
+		rendered, err := renderKubernetesObject(object, object.GetObjectKind().GroupVersionKind())
+		if err != nil {
+			return nil, err
+		}
+		objectsDryRun = append(objectsDryRun, domain.NewKubernetesObjectDryRun(
+			object.GetObjectKind().GroupVersionKind().Kind, metadata.GetName(), metadata.GetNamespace(), rendered, liveObjectsByKindAndName[key], false,
+		))
+	}
+
+	return objectsDryRun, nil
+}
+
+// RefreshApplicationSecrets never updates anything, the in-memory cluster does not read the values of the secrets
+func (containerManager *MemoryContainerManagerRepository) RefreshApplicationSecrets(applyApplication commands.ApplyApplication) (bool, error) {
+	containerManager.mutex.Lock()
+	defer containerManager.mutex.Unlock()
+	if err := containerManager.injectedFailure(MemoryRefreshSecrets); err != nil {
+		return false, err
+	}
+
+	_, err := containerManager.findDeployment(applyApplication.Namespace, applyApplication.Name)
+	return false, err
+}
+
+func (containerManager *MemoryContainerManagerRepository) RestartApplication(restartApplication commands.RestartApplication) error {
+	containerManager.mutex.Lock()
+	defer containerManager.mutex.Unlock()
+	if err := containerManager.injectedFailure(MemoryRestartApplication); err != nil {
+		return err
+	}
+
+	deployment, err := containerManager.findDeployment(restartApplication.Namespace, restartApplication.Name)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	deployment.updatedAt = now
+	for i := range deployment.pods {
+		deployment.pods[i] = containerManager.newPod(deployment.applyApplication, now)
+	}
+
+	fmt.Println("Application restarted in memory : " + restartApplication.Name + " in namespace " + restartApplication.Namespace)
+	return nil
+}
+
+// NetworkRules returns the network rules last applied on a namespace
+func (containerManager *MemoryContainerManagerRepository) NetworkRules(namespace string) []commands.ApplyNetworkRule {
+	containerManager.mutex.Lock()
+	defer containerManager.mutex.Unlock()
+	return append([]commands.ApplyNetworkRule{}, containerManager.networkRules[namespace]...)
+}
+
+// ApplyNetworkRules only records the rules, the in-memory cluster does not simulate the traffic between applications
+func (containerManager *MemoryContainerManagerRepository) ApplyNetworkRules(applyNetworkRules commands.ApplyNetworkRules) error {
+	containerManager.mutex.Lock()
+	defer containerManager.mutex.Unlock()
+	if err := containerManager.injectedFailure(MemoryApplyNetworkRules); err != nil {
+		return err
+	}
+
+	containerManager.networkRules[applyNetworkRules.Namespace] = append([]commands.ApplyNetworkRule{}, applyNetworkRules.Rules...)
+	return nil
+}
+
+// Addons returns the names of the add-ons provisioned on a namespace, sorted
+func (containerManager *MemoryContainerManagerRepository) Addons(namespace string) []string {
+	containerManager.mutex.Lock()
+	defer containerManager.mutex.Unlock()
+	names := []string{}
+	for name := range containerManager.addons[namespace] {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ApplyAddon only records the add-on, the in-memory cluster does not run it
+func (containerManager *MemoryContainerManagerRepository) ApplyAddon(applyAddon commands.ApplyAddon) error {
+	containerManager.mutex.Lock()
+	defer containerManager.mutex.Unlock()
+	if err := containerManager.injectedFailure(MemoryApplyAddon); err != nil {
+		return err
+	}
+
+	containerManager.namespaces[applyAddon.Namespace] = true
+	if containerManager.addons[applyAddon.Namespace] == nil {
+		containerManager.addons[applyAddon.Namespace] = make(map[string]commands.ApplyAddon)
+	}
+	containerManager.addons[applyAddon.Namespace][applyAddon.Name] = applyAddon
+	return nil
+}
+
+func (containerManager *MemoryContainerManagerRepository) UnapplyAddon(unapplyAddon commands.UnapplyAddon) error {
+	containerManager.mutex.Lock()
+	defer containerManager.mutex.Unlock()
+	if err := containerManager.injectedFailure(MemoryUnapplyAddon); err != nil {
+		return err
+	}
+
+	delete(containerManager.addons[unapplyAddon.Namespace], unapplyAddon.Name)
+	return nil
+}
+
+// ConfigGroup returns a config group rendered on a namespace, with its secrets as they were given
+func (containerManager *MemoryContainerManagerRepository) ConfigGroup(namespace string, name string) (commands.ApplyConfigGroup, bool) {
+	containerManager.mutex.Lock()
+	defer containerManager.mutex.Unlock()
+	applyConfigGroup, ok := containerManager.configGroups[namespace][name]
+	return applyConfigGroup, ok
+}
+
+// ApplyConfigGroup only records the config group, the deployments of the in-memory cluster do not read it
+func (containerManager *MemoryContainerManagerRepository) ApplyConfigGroup(applyConfigGroup commands.ApplyConfigGroup) error {
+	containerManager.mutex.Lock()
+	defer containerManager.mutex.Unlock()
+	if err := containerManager.injectedFailure(MemoryApplyConfigGroup); err != nil {
+		return err
+	}
+
+	containerManager.namespaces[applyConfigGroup.Namespace] = true
+	if containerManager.configGroups[applyConfigGroup.Namespace] == nil {
+		containerManager.configGroups[applyConfigGroup.Namespace] = make(map[string]commands.ApplyConfigGroup)
+	}
+	containerManager.configGroups[applyConfigGroup.Namespace][applyConfigGroup.Name] = applyConfigGroup
+	return nil
+}
+
+func (containerManager *MemoryContainerManagerRepository) UnapplyConfigGroup(unapplyConfigGroup commands.UnapplyConfigGroup) error {
+	containerManager.mutex.Lock()
+	defer containerManager.mutex.Unlock()
+	if err := containerManager.injectedFailure(MemoryUnapplyConfigGroup); err != nil {
+		return err
+	}
+
+	delete(containerManager.configGroups[unapplyConfigGroup.Namespace], unapplyConfigGroup.Name)
+	return nil
+}
+
+func (containerManager *MemoryContainerManagerRepository) UnapplyApplication(unapplyApplication commands.UnapplyApplication) error {
+	containerManager.mutex.Lock()
+	defer containerManager.mutex.Unlock()
+	reporter := unapplyApplication.StepReporter
+
+	err := domain.RunOperationStep(reporter, domain.IngressOperationStep, func() error {
+		return containerManager.injectedFailure(MemoryUnapplyApplication)
+	})
+	if err != nil {
+		return err
+	}
+	if err = domain.RunOperationStep(reporter, domain.ServiceOperationStep, func() error { return nil }); err != nil {
+		return err
+	}
+
+	err = domain.RunOperationStep(reporter, domain.DeploymentOperationStep, func() error {
+		key := memoryDeploymentKey(unapplyApplication.Namespace, unapplyApplication.Name)
+		if _, ok := containerManager.deployments[key]; !ok {
+			return &customErrors.ContainerManagerError{
+				Message: fmt.Sprintf("Deleting deployment while unapplying application failed : deployment %s-deployment not found", unapplyApplication.Name),
+			}
+		}
+		delete(containerManager.deployments, key)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if err = domain.RunOperationStep(reporter, domain.SecretsOperationStep, func() error { return nil }); err != nil {
+		return err
+	}
+
+	fmt.Println("Application deleted from memory : " + unapplyApplication.Name + " in namespace " + unapplyApplication.Namespace)
+	return nil
+}
+
+func (containerManager *MemoryContainerManagerRepository) DeleteNamespace(namespace string) error {
+	containerManager.mutex.Lock()
+	defer containerManager.mutex.Unlock()
+	if err := containerManager.injectedFailure(MemoryDeleteNamespace); err != nil {
+		return err
+	}
+
+	for key, deployment := range containerManager.deployments {
+		if deployment.applyApplication.Namespace == namespace {
+			delete(containerManager.deployments, key)
+		}
+	}
+	delete(containerManager.namespaces, namespace)
+	delete(containerManager.networkRules, namespace)
+	delete(containerManager.addons, namespace)
+	delete(containerManager.configGroups, namespace)
+	return nil
+}
+
+func (containerManager *MemoryContainerManagerRepository) GetApplicationMetrics(application commands.GetApplicationMetrics) ([]domain.ApplicationMetrics, error) {
+	containerManager.mutex.Lock()
+	defer containerManager.mutex.Unlock()
+	if err := containerManager.injectedFailure(MemoryGetApplicationMetrics); err != nil {
+		return nil, err
+	}
+
+	deployment, err := containerManager.findDeployment(application.Namespace, application.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	var applicationMetrics []domain.ApplicationMetrics
+	for _, pod := range deployment.pods {
+		// Like metrics-server, pods that are not running do not report metrics
+		if !containerManager.isPodRunning(deployment, pod, now) {
+			continue
+		}
+		cpuLimit, memoryLimit := memoryContainerLimits(deployment.applyApplication)
+		cpuUsage, memoryUsage := containerManager.podUsage(deployment, pod, cpuLimit, memoryLimit)
+		applicationMetrics = append(applicationMetrics, domain.ApplicationMetrics{
+			PodName:        pod.name,
+			Name:           deployment.applyApplication.Name,
+			CPUUsage:       fmt.Sprintf("%dm", cpuUsage),
+			MaxCPUUsage:    fmt.Sprintf("%dm", cpuLimit),
+			MemoryUsage:    fmt.Sprintf("%dKi", memoryUsage),
+			MaxMemoryUsage: fmt.Sprintf("%dKi", memoryLimit),
+			PodsUsage:      "0",
+		})
+	}
+
+	return applicationMetrics, nil
+}
+
+func (containerManager *MemoryContainerManagerRepository) GetApplicationLogs(application commands.GetApplicationLogs) ([]domain.ApplicationLogs, error) {
+	containerManager.mutex.Lock()
+	defer containerManager.mutex.Unlock()
+	if err := containerManager.injectedFailure(MemoryGetApplicationLogs); err != nil {
+		return nil, err
+	}
+
+	deployment, err := containerManager.findDeployment(application.Namespace, application.Name)
+	if err != nil {
+		return nil, err
+	}
+	if len(deployment.pods) == 0 {
+		return nil, &customErrors.ContainerManagerError{
+			Message: fmt.Sprintf("No pod found for application %s in namespace %s", application.Name, application.Namespace),
+		}
+	}
+
+	now := time.Now()
+	logs := make([]domain.ApplicationLogs, 0, len(deployment.pods))
+	for _, pod := range deployment.pods {
+		containerManager.refreshPodLogs(deployment, pod, now)
+		logs = append(logs, domain.ApplicationLogs{
+			PodName: pod.name,
+			Logs:    strings.Join(pod.logs, "\n"),
+		})
+	}
+
+	return logs, nil
+}
+
+// GetApplicationEvents returns the events a cluster reports along the simulated lifecycle of the pods of an application
+func (containerManager *MemoryContainerManagerRepository) GetApplicationEvents(application commands.GetApplicationEvents) ([]domain.ApplicationEvent, error) {
+	containerManager.mutex.Lock()
+	defer containerManager.mutex.Unlock()
+	if err := containerManager.injectedFailure(MemoryGetApplicationEvents); err != nil {
+		return nil, err
+	}
+
+	deployment, err := containerManager.findDeployment(application.Namespace, application.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	replicaSetName := fmt.Sprintf("%s-deployment-%08x", application.Name, memoryHash(application.Namespace, application.Name))
+	event := func(eventType domain.ApplicationEventType, reason string, message string, objectKind string, objectName string, count int32, firstTimestamp time.Time, lastTimestamp time.Time) domain.ApplicationEvent {
+		return domain.ApplicationEvent{
+			Type:           eventType,
+			Reason:         reason,
+			Message:        message,
+			ObjectKind:     objectKind,
+			ObjectName:     objectName,
+			Count:          count,
+			FirstTimestamp: firstTimestamp,
+			LastTimestamp:  lastTimestamp,
+		}
+	}
+	applicationEvents := []domain.ApplicationEvent{
+		event(domain.NormalApplicationEvent, "ScalingReplicaSet", fmt.Sprintf("Scaled up replica set %s to %d", replicaSetName, len(deployment.pods)), domain.DeploymentEventObject, fmt.Sprintf("%s-deployment", application.Name), 1, deployment.updatedAt, deployment.updatedAt),
+	}
+	containerName := deployment.applyApplication.Name
+	image := deployment.applyApplication.Image
+	for _, pod := range deployment.pods {
+		startedAt := pod.createdAt.Add(containerManager.config.PodStartupDuration)
+		applicationEvents = append(applicationEvents,
+			event(domain.NormalApplicationEvent, "SuccessfulCreate", fmt.Sprintf("Created pod: %s", pod.name), domain.ReplicaSetEventObject, replicaSetName, 1, pod.createdAt, pod.createdAt),
+			event(domain.NormalApplicationEvent, "Scheduled", fmt.Sprintf("Successfully assigned %s/%s to memory-node-%d", application.Namespace, pod.name, pod.node+1), domain.PodEventObject, pod.name, 1, pod.createdAt, pod.createdAt),
+			event(domain.NormalApplicationEvent, "Pulling", fmt.Sprintf("Pulling image \"%s\"", image), domain.PodEventObject, pod.name, 1, pod.createdAt, pod.createdAt),
+		)
+		// A failure repeats its events every 10 seconds, like the back-off of the kubelet
+		repetitions := int32(now.Sub(pod.createdAt)/(10*time.Second)) + 1
+		switch {
+		case deployment.failureReason == MemoryPodCrashLoopBackOff:
+			applicationEvents = append(applicationEvents,
+				event(domain.NormalApplicationEvent, "Pulled", fmt.Sprintf("Container image \"%s\" already present on machine", image), domain.PodEventObject, pod.name, repetitions, pod.createdAt, now),
+				event(domain.NormalApplicationEvent, "Started", fmt.Sprintf("Started container %s", containerName), domain.PodEventObject, pod.name, repetitions, pod.createdAt, now),
+				event(domain.WarningApplicationEvent, "BackOff", fmt.Sprintf("Back-off restarting failed container %s in pod %s", containerName, pod.name), domain.PodEventObject, pod.name, repetitions, pod.createdAt, now),
+			)
+		case deployment.failureReason == MemoryPodImagePullBackOff:
+			applicationEvents = append(applicationEvents,
+				event(domain.WarningApplicationEvent, "Failed", fmt.Sprintf("Failed to pull image \"%s\": simulated failure", image), domain.PodEventObject, pod.name, repetitions, pod.createdAt, now),
+				event(domain.NormalApplicationEvent, "BackOff", fmt.Sprintf("Back-off pulling image \"%s\"", image), domain.PodEventObject, pod.name, repetitions, pod.createdAt, now),
+			)
+		case deployment.failureReason != "":
+			applicationEvents = append(applicationEvents,
+				event(domain.WarningApplicationEvent, "Failed", fmt.Sprintf("Error: %s", deployment.failureReason), domain.PodEventObject, pod.name, repetitions, pod.createdAt, now),
+			)
+		case !now.Before(startedAt):
+			applicationEvents = append(applicationEvents,
+				event(domain.NormalApplicationEvent, "Pulled", fmt.Sprintf("Successfully pulled image \"%s\"", image), domain.PodEventObject, pod.name, 1, startedAt, startedAt),
+				event(domain.NormalApplicationEvent, "Created", fmt.Sprintf("Created container %s", containerName), domain.PodEventObject, pod.name, 1, startedAt, startedAt),
+				event(domain.NormalApplicationEvent, "Started", fmt.Sprintf("Started container %s", containerName), domain.PodEventObject, pod.name, 1, startedAt, startedAt),
+			)
+		}
+	}
+	return applicationEvents, nil
+}
+
+func (containerManager *MemoryContainerManagerRepository) GetApplicationStatus(application commands.GetApplicationStatus) (*domain.ApplicationStatus, error) {
+	containerManager.mutex.Lock()
+	defer containerManager.mutex.Unlock()
+	if err := containerManager.injectedFailure(MemoryGetApplicationStatus); err != nil {
+		return nil, err
+	}
+
+	deployment, ok := containerManager.deployments[memoryDeploymentKey(application.Namespace, application.Name)]
+	if !ok {
+		return nil, &customErrors.ContainerManagerApplicationInformationError{
+			Message:         fmt.Sprintf("Getting deployment failed : deployments.apps \"%s-deployment\" not found", application.Name),
+			ApplicationName: application.Name,
+			Namespace:       application.Namespace,
+			Type:            "Deployment",
+		}
+	}
+
+	now := time.Now()
+	podList := domain.PodList{Items: make([]domain.Pod, 0, len(deployment.pods))}
+	var readyReplicas int32
+	for _, pod := range deployment.pods {
+		domainPod := containerManager.podStatus(deployment, pod, now)
+		if containerManager.isPodRunning(deployment, pod, now) {
+			readyReplicas++
+		}
+		podList.Items = append(podList.Items, domainPod)
+	}
+	podList.Items = domain.ComputeHumanizedPodStatus(&podList.Items)
+
+	replicas := int32(len(deployment.pods))
+	deploymentName := fmt.Sprintf("%s-deployment", application.Name)
+	lastUpdateTime := deployment.updatedAt.String()
+	progressingCondition := domain.DeploymentCondition{
+		Type:               "Progressing",
+		Status:             "True",
+		Reason:             "ReplicaSetUpdated",
+		Message:            fmt.Sprintf("ReplicaSet \"%s\" is progressing.", deploymentName),
+		LastUpdateTime:     lastUpdateTime,
+		LastTransitionTime: lastUpdateTime,
+	}
+	var deploymentConditions []domain.DeploymentCondition
+	switch {
+	case readyReplicas == replicas:
+		deploymentConditions = []domain.DeploymentCondition{{
+			Type:               "Available",
+			Status:             "True",
+			Reason:             "MinimumReplicasAvailable",
+			Message:            "Deployment has minimum availability.",
+			LastUpdateTime:     lastUpdateTime,
+			LastTransitionTime: lastUpdateTime,
+		}, progressingCondition}
+	case deployment.failureReason != "":
+		deploymentConditions = []domain.DeploymentCondition{{
+			Type:               "Available",
+			Status:             "False",
+			Reason:             "MinimumReplicasUnavailable",
+			Message:            "Deployment does not have minimum availability.",
+			LastUpdateTime:     lastUpdateTime,
+			LastTransitionTime: lastUpdateTime,
+		}, progressingCondition}
+	default:
+		deploymentConditions = []domain.DeploymentCondition{progressingCondition}
+	}
+
+	applicationStatus := domain.ApplicationStatus{
+		Name:                deploymentName,
+		StatusInString:      fmt.Sprintf("replicas: %d, ready: %d", replicas, readyReplicas),
+		Replicas:            replicas,
+		AvailableReplicas:   readyReplicas,
+		UnavailableReplicas: replicas - readyReplicas,
+		ReadyReplicas:       readyReplicas,
+		DesiredReplicas:     replicas,
+		CurrentReplicas:     replicas,
+		UpdatedReplicas:     replicas,
+		DeploymentCondition: deploymentConditions,
+		PodList:             podList,
+		ServiceStatus: domain.ServiceStatus{
+			Name: fmt.Sprintf("%s-service", application.Name),
+			Type: "ClusterIP",
+			IP:   memoryClusterIP(application.Namespace, application.Name),
+			Port: int32(deployment.applyApplication.Port),
+		},
+		IngressStatus: domain.IngressStatus{
+			Name: fmt.Sprintf("%s-ingress", application.Name),
+		},
+	}
+
+	computedStatus, humanizedStatus, err := applicationStatus.ComputeApplicationStatus()
+	if err != nil {
+		return nil, &customErrors.ContainerManagerApplicationInformationError{
+			Message:         fmt.Sprintf("Computing application status failed : %s", err.Error()),
+			ApplicationName: application.Name,
+			Namespace:       application.Namespace,
+			Type:            "ComputeApplicationStatus",
+		}
+	}
+	applicationStatus.ComputedApplicationStatus = computedStatus
+	applicationStatus.HumanizedStatus = humanizedStatus
+
+	return &applicationStatus, nil
+}
+
+func (containerManager *MemoryContainerManagerRepository) GetClusterMetrics() (*domain.ClusterMetrics, error) {
+	containerManager.mutex.Lock()
+	defer containerManager.mutex.Unlock()
+	if err := containerManager.injectedFailure(MemoryGetClusterMetrics); err != nil {
+		return nil, err
+	}
+
+	cpuUsageByNode := make([]int64, containerManager.config.Nodes)
+	memoryUsageByNode := make([]int64, containerManager.config.Nodes)
+	now := time.Now()
+	for _, deployment := range containerManager.deployments {
+		cpuLimit, memoryLimit := memoryContainerLimits(deployment.applyApplication)
+		for _, pod := range deployment.pods {
+			if !containerManager.isPodRunning(deployment, pod, now) {
+				continue
+			}
+			cpuUsage, memoryUsage := containerManager.podUsage(deployment, pod, cpuLimit, memoryLimit)
+			cpuUsageByNode[pod.node] += cpuUsage
+			memoryUsageByNode[pod.node] += memoryUsage
+		}
+	}
+
+	var nodeMetrics []domain.NodeMetrics
+	var nodeCapacities []domain.NodeCapacities
+	for node := 0; node < containerManager.config.Nodes; node++ {
+		nodeName := fmt.Sprintf("memory-node-%d", node+1)
+		cpuUsage := fmt.Sprintf("%dm", cpuUsageByNode[node])
+		memoryUsage := fmt.Sprintf("%dKi", memoryUsageByNode[node])
+		memoryCapacity := fmt.Sprintf("%dGi", containerManager.config.NodeMemoryGB)
+		nodeMetrics = append(nodeMetrics, domain.NodeMetrics{
+			Name:                          nodeName,
+			CPUUsage:                      cpuUsage,
+			MemoryUsage:                   memoryUsage,
+			StorageUsage:                  "0",
+			EphemeralStorageUsage:         "0",
+			ReadableCPUUsage:              domain.ConvertK8sResourceToReadableHumanValueAndUnit(cpuUsage),
+			ReadableMemoryUsage:           domain.ConvertK8sResourceToReadableHumanValueAndUnit(memoryUsage),
+			ReadableStorageUsage:          "0",
+			ReadableEphemeralStorageUsage: "0",
+		})
+		nodeCapacities = append(nodeCapacities, domain.NodeCapacities{
+			Name:                     nodeName,
+			CPULimit:                 strconv.Itoa(containerManager.config.NodeCPUCores),
+			MemoryLimit:              memoryCapacity,
+			StorageLimit:             "0",
+			EphemeralStorageLimit:    "0",
+			ReadableCPU:              strconv.Itoa(containerManager.config.NodeCPUCores),
+			ReadableMemory:           domain.ConvertK8sResourceToReadableHumanValueAndUnit(memoryCapacity),
+			ReadableStorage:          "0",
+			ReadableEphemeralStorage: "0",
+		})
+	}
+
+	nodesComputedUsages, err := domain.ComputeNodesUsagesFromMetricsAndCapacities(nodeMetrics, nodeCapacities)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.ClusterMetrics{
+		NodesMetrics:        nodeMetrics,
+		NodesCapacities:     nodeCapacities,
+		NodesComputedUsages: nodesComputedUsages,
+	}, nil
+}
+
+// injectedFailure returns an error when a failure was injected for the operation or when the random failure rate hits
+func (containerManager *MemoryContainerManagerRepository) injectedFailure(operation MemoryContainerManagerOperation) error {
+	if remaining, ok := containerManager.failures[operation]; ok && remaining != 0 {
+		if remaining > 0 {
+			containerManager.failures[operation] = remaining - 1
+		}
+		return &customErrors.ContainerManagerError{
+			Message: fmt.Sprintf("Injected failure of %s in memory container manager", operation),
+		}
+	}
+	if containerManager.config.FailureRate > 0 && containerManager.random.Float64() < containerManager.config.FailureRate {
+		return &customErrors.ContainerManagerError{
+			Message: fmt.Sprintf("Random failure of %s in memory container manager", operation),
+		}
+	}
+	return nil
+}
+
+func (containerManager *MemoryContainerManagerRepository) findDeployment(namespace string, name string) (*memoryDeployment, error) {
+	deployment, ok := containerManager.deployments[memoryDeploymentKey(namespace, name)]
+	if !ok {
+		return nil, &customErrors.ContainerManagerError{
+			Message: fmt.Sprintf("Error while retrieving Deployment object : deployments.apps \"%s-deployment\" not found", name),
+		}
+	}
+	return deployment, nil
+}
+
+func (containerManager *MemoryContainerManagerRepository) newPod(applyApplication commands.ApplyApplication, now time.Time) *memoryPod {
+	containerManager.podCounter++
+	return &memoryPod{
+		name:      fmt.Sprintf("%s-deployment-%08x-%05d", applyApplication.Name, memoryHash(applyApplication.Namespace, applyApplication.Name), containerManager.podCounter),
+		createdAt: now,
+		node:      containerManager.podCounter % containerManager.config.Nodes,
+		logs: []string{
+			fmt.Sprintf("%s Pulling image \"%s\"", now.UTC().Format(time.RFC3339), applyApplication.Image),
+		},
+	}
+}
+
+func (containerManager *MemoryContainerManagerRepository) isPodRunning(deployment *memoryDeployment, pod *memoryPod, now time.Time) bool {
+	return deployment.failureReason == "" && now.Sub(pod.createdAt) >= containerManager.config.PodStartupDuration
+}
+
+// podStatus simulates the lifecycle of a pod: ContainerCreating during the startup duration, then Running unless a failure is set
+func (containerManager *MemoryContainerManagerRepository) podStatus(deployment *memoryDeployment, pod *memoryPod, now time.Time) domain.Pod {
+	startedAt := pod.createdAt.Add(containerManager.config.PodStartupDuration)
+	containerStatus := domain.ContainerStatus{
+		Name:         deployment.applyApplication.Name,
+		Image:        deployment.applyApplication.Image,
+		RestartCount: pod.restartCount,
+	}
+	phase := "Pending"
+	switch {
+	case deployment.failureReason == MemoryPodCrashLoopBackOff:
+		phase = "Running"
+		pod.restartCount = int32(now.Sub(pod.createdAt) / (10 * time.Second))
+		containerStatus.RestartCount = pod.restartCount
+		containerStatus.State.Waiting = &domain.ContainerStateWaiting{
+			Reason:  deployment.failureReason,
+			Message: fmt.Sprintf("back-off restarting failed container %s", deployment.applyApplication.Name),
+		}
+	case deployment.failureReason != "":
+		containerStatus.State.Waiting = &domain.ContainerStateWaiting{
+			Reason:  deployment.failureReason,
+			Message: fmt.Sprintf("simulated failure of container %s", deployment.applyApplication.Name),
+		}
+	case now.Before(startedAt):
+		containerStatus.State.Waiting = &domain.ContainerStateWaiting{Reason: "ContainerCreating"}
+	default:
+		phase = "Running"
+		containerStatus.Ready = true
+		containerStatus.Started = true
+		containerStatus.State.Running = &domain.ContainerStateRunning{StartedAt: startedAt}
+	}
+
+	return domain.Pod{
+		MetaData: domain.PodMetaData{Name: pod.name},
+		Status: domain.PodStatus{
+			Phase:             phase,
+			ContainerStatuses: []domain.ContainerStatus{containerStatus},
+		},
+	}
+}
+
+// refreshPodLogs appends the log lines of the pod lifecycle events that happened since the last call
+func (containerManager *MemoryContainerManagerRepository) refreshPodLogs(deployment *memoryDeployment, pod *memoryPod, now time.Time) {
+	startedAt := pod.createdAt.Add(containerManager.config.PodStartupDuration)
+	if deployment.failureReason != "" {
+		line := fmt.Sprintf("%s Container %s is waiting : %s", now.UTC().Format(time.RFC3339), deployment.applyApplication.Name, deployment.failureReason)
+		pod.logs = append(pod.logs, line)
+		return
+	}
+	if now.Before(startedAt) {
+		return
+	}
+	if len(pod.logs) == 1 {
+		pod.logs = append(pod.logs,
+			fmt.Sprintf("%s Started container %s", startedAt.UTC().Format(time.RFC3339), deployment.applyApplication.Name),
+			fmt.Sprintf("%s Listening on port %d", startedAt.UTC().Format(time.RFC3339), deployment.applyApplication.Port),
+		)
+	}
+	pod.logs = append(pod.logs, fmt.Sprintf("%s GET / 200", now.UTC().Format(time.RFC3339)))
+}
+
+// podUsage returns the CPU usage in millicores and the memory usage in KiB of a running pod
+func (containerManager *MemoryContainerManagerRepository) podUsage(deployment *memoryDeployment, pod *memoryPod, cpuLimit int64, memoryLimit int64) (int64, int64) {
+	// Synthetic usage is between 10% and 60% of the limits, stable per pod with a little jitter on every read
+	base := float64(memoryHash(pod.name)%50) + 10
+	cpuUsagePercentage := base + containerManager.random.Float64()*5
+	memoryUsagePercentage := base + containerManager.random.Float64()*5
+	if deployment.cpuUsagePercentage != nil {
+		cpuUsagePercentage = *deployment.cpuUsagePercentage
+	}
+	if deployment.memoryUsagePercentage != nil {
+		memoryUsagePercentage = *deployment.memoryUsagePercentage
+	}
+	return int64(float64(cpuLimit) * cpuUsagePercentage / 100), int64(float64(memoryLimit) * memoryUsagePercentage / 100)
+}
+
+// memoryContainerLimits returns the CPU limit in millicores and the memory limit in KiB of an application
+func memoryContainerLimits(applyApplication commands.ApplyApplication) (int64, int64) {
+	var cpuLimit, memoryLimit int64
+	if applyApplication.ContainerSpecifications.CPULimit != nil {
+		cpuLimit = int64(applyApplication.ContainerSpecifications.CPULimit.Val)
+	}
+	if applyApplication.ContainerSpecifications.MemoryLimit != nil {
+		memoryLimit = int64(applyApplication.ContainerSpecifications.MemoryLimit.Val)
+		switch applyApplication.ContainerSpecifications.MemoryLimit.Unit {
+		case domain.MB:
+			memoryLimit *= 1024
+		case domain.GB:
+			memoryLimit *= 1024 * 1024
+		case domain.TB:
+			memoryLimit *= 1024 * 1024 * 1024
+		}
+	}
+	return cpuLimit, memoryLimit
+}
+
+// memoryPodTemplatesEqual compares the pod templates of two versions of an application.
+// Pods reference secrets by name, so a new secret value does not roll them out until the application is restarted.
+func memoryPodTemplatesEqual(previous commands.ApplyApplication, next commands.ApplyApplication) bool {
+	previous.ScalabilitySpecifications.Replicas = next.ScalabilitySpecifications.Replicas
+	previous.Secrets = *previous.Secrets.NamesOnly()
+	next.Secrets = *next.Secrets.NamesOnly()
+	return reflect.DeepEqual(previous, next)
+}
+
+func memoryObjectKey(object runtime.Object) (string, error) {
+	metadata, err := meta.Accessor(object)
+	if err != nil {
+		return "", err
+	}
+	return object.GetObjectKind().GroupVersionKind().Kind + "/" + metadata.GetName(), nil
+}
+
+func memoryDeploymentKey(namespace string, name string) string {
+	return namespace + "/" + name
+}
+
+func memoryClusterIP(namespace string, name string) string {
+	hash := memoryHash(namespace, name)
+	return fmt.Sprintf("10.96.%d.%d", (hash>>8)%256, hash%254+1)
+}
+
+func memoryHash(values ...string) uint32 {
+	hash := fnv.New32a()
+	for _, value := range values {
+		_, _ = hash.Write([]byte(value))
+	}
+	return hash.Sum32()
+}
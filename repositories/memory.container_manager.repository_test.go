@@ -0,0 +1,204 @@
+package repositories
+
+import (
+	"testing"
+	"time"
+
+	"cloud-app-hive/domain"
+	"cloud-app-hive/domain/commands"
+)
+
+func newTestApplyApplication(replicas int32) commands.ApplyApplication {
+	return commands.ApplyApplication{
+		Name:            "api",
+		Image:           "nginx:latest",
+		Registry:        domain.DockerHubRegistry,
+		Namespace:       "team",
+		Port:            80,
+		ApplicationType: domain.SingleInstance,
+		ContainerSpecifications: domain.ApplicationContainerSpecifications{
+			CPULimit:    &domain.ContainerCpuLimit{Val: 500, Unit: "mCPU"},
+			MemoryLimit: &domain.ContainerMemoryLimit{Val: 256, Unit: domain.MB},
+		},
+		ScalabilitySpecifications: domain.ApplicationScalabilitySpecifications{Replicas: replicas},
+	}
+}
+
+func TestMemoryContainerManager_PodsBecomeAvailableAfterStartup(t *testing.T) {
+	containerManager := NewMemoryContainerManagerRepository(MemoryContainerManagerConfig{PodStartupDuration: time.Hour, Nodes: 1})
+	if err := containerManager.ApplyApplication(newTestApplyApplication(2)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	status, err := containerManager.GetApplicationStatus(commands.GetApplicationStatus{Name: "api", Namespace: "team"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if *status.ComputedApplicationStatus != domain.MISSING_REPLICAS || status.ReadyReplicas != 0 {
+		t.Errorf("expected starting pods to be missing replicas, got %s with %d ready", *status.ComputedApplicationStatus, status.ReadyReplicas)
+	}
+	metrics, _ := containerManager.GetApplicationMetrics(commands.GetApplicationMetrics{Name: "api", Namespace: "team"})
+	if len(metrics) != 0 {
+		t.Errorf("expected no metrics for starting pods, got %d", len(metrics))
+	}
+
+	containerManager.config.PodStartupDuration = 0
+	status, err = containerManager.GetApplicationStatus(commands.GetApplicationStatus{Name: "api", Namespace: "team"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if *status.ComputedApplicationStatus != domain.AVAILABLE || status.ReadyReplicas != 2 {
+		t.Errorf("expected started pods to be available, got %s with %d ready", *status.ComputedApplicationStatus, status.ReadyReplicas)
+	}
+}
+
+func TestMemoryContainerManager_SyntheticMetricsFollowUsage(t *testing.T) {
+	containerManager := NewMemoryContainerManagerRepository(MemoryContainerManagerConfig{Nodes: 1, NodeCPUCores: 4, NodeMemoryGB: 8})
+	if err := containerManager.ApplyApplication(newTestApplyApplication(1)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := containerManager.SetApplicationUsage("team", "api", 90, 50); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	metrics, err := containerManager.GetApplicationMetrics(commands.GetApplicationMetrics{Name: "api", Namespace: "team"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(metrics) != 1 {
+		t.Fatalf("expected metrics of 1 pod, got %d", len(metrics))
+	}
+	readableMetrics := metrics[0].WithRealLifeReadableUnits()
+	exceeds, cpuUsagePercentage := domain.DoesUsageExceedsLimitAndHowMuchActually(readableMetrics.CPUUsage, readableMetrics.MaxCPUUsage, 80)
+	if !exceeds || cpuUsagePercentage != 90 {
+		t.Errorf("expected cpu usage of 90%%, got %f", cpuUsagePercentage)
+	}
+	_, memoryUsagePercentage := domain.DoesUsageExceedsLimitAndHowMuchActually(readableMetrics.MemoryUsage, readableMetrics.MaxMemoryUsage, 80)
+	if memoryUsagePercentage != 50 {
+		t.Errorf("expected memory usage of 50%%, got %f", memoryUsagePercentage)
+	}
+
+	clusterMetrics, err := containerManager.GetClusterMetrics()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(clusterMetrics.NodesComputedUsages) != 1 || clusterMetrics.NodesComputedUsages[0].CPUUsageInPercentage <= 0 {
+		t.Errorf("expected cluster cpu usage on the simulated node, got %+v", clusterMetrics.NodesComputedUsages)
+	}
+}
+
+func TestMemoryContainerManager_InjectedFailures(t *testing.T) {
+	containerManager := NewMemoryContainerManagerRepository(MemoryContainerManagerConfig{Nodes: 1})
+	containerManager.InjectFailure(MemoryApplyApplication, 1)
+	if err := containerManager.ApplyApplication(newTestApplyApplication(1)); err == nil {
+		t.Fatal("expected injected failure")
+	}
+	if err := containerManager.ApplyApplication(newTestApplyApplication(1)); err != nil {
+		t.Fatalf("expected failure to be consumed, got %v", err)
+	}
+
+	if err := containerManager.SetApplicationFailure("team", "api", MemoryPodCrashLoopBackOff); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	status, err := containerManager.GetApplicationStatus(commands.GetApplicationStatus{Name: "api", Namespace: "team"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	waiting := status.PodList.Items[0].Status.ContainerStatuses[0].State.Waiting
+	if waiting == nil || waiting.Reason != MemoryPodCrashLoopBackOff {
+		t.Errorf("expected pod to be in %s, got %+v", MemoryPodCrashLoopBackOff, waiting)
+	}
+
+	containerManager.ClearFailures()
+	status, _ = containerManager.GetApplicationStatus(commands.GetApplicationStatus{Name: "api", Namespace: "team"})
+	if *status.ComputedApplicationStatus != domain.AVAILABLE {
+		t.Errorf("expected application to be available once healed, got %s", *status.ComputedApplicationStatus)
+	}
+}
+
+func TestMemoryContainerManager_DryRunComparesWithDeployedApplication(t *testing.T) {
+	containerManager := NewMemoryContainerManagerRepository(MemoryContainerManagerConfig{Nodes: 1})
+	applyApplication := newTestApplyApplication(1)
+	if err := containerManager.ApplyApplication(applyApplication); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	applyApplication.Image = "nginx:1.25"
+	objects, err := containerManager.DryRunApplication(applyApplication)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, object := range objects {
+		expectedAction := domain.DryRunUnchanged
+		if object.Kind == "Deployment" {
+			expectedAction = domain.DryRunUpdate
+		}
+		if object.Action != expectedAction {
+			t.Errorf("expected %s %s to be %s, got %s", object.Kind, object.Name, expectedAction, object.Action)
+		}
+	}
+	if names := containerManager.DeployedApplications("team"); len(names) != 1 || names[0] != "api" {
+		t.Errorf("expected dry run to leave deployed applications untouched, got %v", names)
+	}
+}
+
+func TestMemoryContainerManager_NewSecretValuesNeedARestart(t *testing.T) {
+	containerManager := NewMemoryContainerManagerRepository(MemoryContainerManagerConfig{Nodes: 1})
+	applyApplication := newTestApplyApplication(1)
+	applyApplication.Secrets = domain.ApplicationSecrets{{Name: "TOKEN", Val: "first"}}
+	if err := containerManager.ApplyApplication(applyApplication); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	podName := containerManager.deployments[memoryDeploymentKey("team", "api")].pods[0].name
+
+	applyApplication.Secrets = domain.ApplicationSecrets{{Name: "TOKEN", Val: "second"}}
+	if err := containerManager.ApplyApplication(applyApplication); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if containerManager.deployments[memoryDeploymentKey("team", "api")].pods[0].name != podName {
+		t.Error("expected a new secret value to keep the running pods")
+	}
+
+	if err := containerManager.RestartApplication(commands.RestartApplication{Name: "api", Namespace: "team"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if containerManager.deployments[memoryDeploymentKey("team", "api")].pods[0].name == podName {
+		t.Error("expected the restart to roll the pods out")
+	}
+	if err := containerManager.RestartApplication(commands.RestartApplication{Name: "unknown", Namespace: "team"}); err == nil {
+		t.Error("expected an error when restarting an application that is not deployed")
+	}
+}
+
+func TestMemoryContainerManager_EventsReportFailures(t *testing.T) {
+	containerManager := NewMemoryContainerManagerRepository(MemoryContainerManagerConfig{Nodes: 1})
+	if err := containerManager.ApplyApplication(newTestApplyApplication(1)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	applicationEvents, err := containerManager.GetApplicationEvents(commands.GetApplicationEvents{Name: "api", Namespace: "team"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, applicationEvent := range applicationEvents {
+		if applicationEvent.Type == domain.WarningApplicationEvent {
+			t.Errorf("expected a started application to have no warning, got %+v", applicationEvent)
+		}
+	}
+
+	if err := containerManager.SetApplicationFailure("team", "api", MemoryPodCrashLoopBackOff); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	applicationEvents, err = containerManager.GetApplicationEvents(commands.GetApplicationEvents{Name: "api", Namespace: "team"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	backOff := applicationEvents[len(applicationEvents)-1]
+	if backOff.Type != domain.WarningApplicationEvent || backOff.Reason != "BackOff" || backOff.ObjectKind != domain.PodEventObject {
+		t.Errorf("expected the pod to report a BackOff warning, got %+v", backOff)
+	}
+
+	if _, err := containerManager.GetApplicationEvents(commands.GetApplicationEvents{Name: "web", Namespace: "team"}); err == nil {
+		t.Error("expected the events of an application that is not deployed to fail")
+	}
+}
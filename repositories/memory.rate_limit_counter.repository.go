@@ -0,0 +1,60 @@
+package repositories
+
+import (
+	"sync"
+	"time"
+)
+
+// memoryRateLimitCountersPurgeInterval is how often the counters of past windows are dropped
+const memoryRateLimitCountersPurgeInterval = time.Minute
+
+type memoryRateLimitCounter struct {
+	windowStart time.Time
+	window      time.Duration
+	count       int64
+}
+
+// MemoryRateLimitCounterRepository counts the requests in memory, the counters are lost on restart and not shared between replicas
+type MemoryRateLimitCounterRepository struct {
+	mutex     *sync.Mutex
+	counters  map[string]*memoryRateLimitCounter
+	lastPurge *time.Time
+}
+
+func NewMemoryRateLimitCounterRepository() MemoryRateLimitCounterRepository {
+	now := time.Now()
+	return MemoryRateLimitCounterRepository{
+		mutex:     &sync.Mutex{},
+		counters:  map[string]*memoryRateLimitCounter{},
+		lastPurge: &now,
+	}
+}
+
+// Increment counts a request of the key in the window starting at windowStart and returns the number of requests counted in this window
+func (r MemoryRateLimitCounterRepository) Increment(key string, windowStart time.Time, window time.Duration) (int64, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.purge(time.Now())
+
+	counter, ok := r.counters[key]
+	if !ok || !counter.windowStart.Equal(windowStart) {
+		counter = &memoryRateLimitCounter{windowStart: windowStart, window: window}
+		r.counters[key] = counter
+	}
+	counter.count++
+	return counter.count, nil
+}
+
+// purge drops the counters of the windows that are over, so that identities that stopped calling the API do not use memory
+func (r MemoryRateLimitCounterRepository) purge(now time.Time) {
+	if now.Sub(*r.lastPurge) < memoryRateLimitCountersPurgeInterval {
+		return
+	}
+	for key, counter := range r.counters {
+		if !now.Before(counter.windowStart.Add(counter.window)) {
+			delete(r.counters, key)
+		}
+	}
+	*r.lastPurge = now
+}
@@ -0,0 +1,26 @@
+package repositories
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMemoryRateLimitCounterRepository_Increment(t *testing.T) {
+	repository := NewMemoryRateLimitCounterRepository()
+	windowStart := time.Now().UTC().Truncate(time.Minute)
+
+	for expected := int64(1); expected <= 3; expected++ {
+		count, err := repository.Increment("user:1", windowStart, time.Minute)
+		if err != nil || count != expected {
+			t.Fatalf("expected count %d, got %d, %v", expected, count, err)
+		}
+	}
+
+	if count, _ := repository.Increment("user:2", windowStart, time.Minute); count != 1 {
+		t.Errorf("expected keys to be counted separately, got %d", count)
+	}
+
+	if count, _ := repository.Increment("user:1", windowStart.Add(time.Minute), time.Minute); count != 1 {
+		t.Errorf("expected the next window to start from 1, got %d", count)
+	}
+}
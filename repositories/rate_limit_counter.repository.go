@@ -0,0 +1,22 @@
+package repositories
+
+import (
+	domainRepositories "cloud-app-hive/domain/repositories"
+	"fmt"
+	"os"
+
+	"gorm.io/gorm"
+)
+
+// NewRateLimitCounterRepository returns the rate limit counters selected by the RATE_LIMIT_STORE environment variable:
+// "memory" (the default) for a single replica of the API or "mysql" to share the counters between replicas
+func NewRateLimitCounterRepository(database *gorm.DB) (domainRepositories.RateLimitCounterRepository, error) {
+	switch rateLimitStore := os.Getenv("RATE_LIMIT_STORE"); rateLimitStore {
+	case "", "memory":
+		return NewMemoryRateLimitCounterRepository(), nil
+	case "mysql":
+		return GORMRateLimitCounterRepository{Database: database}, nil
+	default:
+		return nil, fmt.Errorf("unknown RATE_LIMIT_STORE %s, expected memory or mysql", rateLimitStore)
+	}
+}
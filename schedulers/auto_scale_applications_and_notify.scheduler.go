@@ -6,6 +6,7 @@ import (
 	"cloud-app-hive/domain/errors"
 	"cloud-app-hive/services"
 	"cloud-app-hive/use_cases/applications"
+	"cloud-app-hive/use_cases/operations"
 	"fmt"
 	"os"
 	"strconv"
@@ -17,6 +18,7 @@ type AutoScaleApplicationsAndNotifyScheduler struct {
 	getApplicationMetricsUseCase       applications.GetApplicationMetricsUseCase
 	scalabilityNotificationService     services.ScalabilityNotificationService
 	scaleApplicationUseCase            applications.ScaleApplicationUseCase
+	startApplicationOperationUseCase   operations.StartApplicationOperationUseCase
 }
 
 func (scheduler AutoScaleApplicationsAndNotifyScheduler) Launch() {
@@ -115,7 +117,7 @@ func (scheduler AutoScaleApplicationsAndNotifyScheduler) Launch() {
 								fmt.Println("Auto application", application.Name, "has reached the maximum number of replicas")
 
 								// 3. scale up/down the application if one of the usage exceeds the accepted percentage
-								_, err := scheduler.scaleApplicationUseCase.Execute(application.ID, commands.UpdateApplication{}, applications.VerticalUpScaling)
+								err := scheduler.scale(application, applications.VerticalUpScaling)
 								if err != nil {
 									if _, ok := err.(*errors.InvalidApplicationCannotVerticallyScaleBecauseMaxSpecsError); ok {
 										fmt.Println("Auto application", application.Name, "has reached the maximum cpu/memory specs")
@@ -202,7 +204,7 @@ func (scheduler AutoScaleApplicationsAndNotifyScheduler) Launch() {
 							}
 
 							// 5. scale up/down horizontally the application if one of the usage exceeds the accepted percentage
-							_, err := scheduler.scaleApplicationUseCase.Execute(application.ID, commands.UpdateApplication{}, applications.HorizontalUpScaling)
+							err := scheduler.scale(application, applications.HorizontalUpScaling)
 							if err != nil {
 								fmt.Println("error when try to scale horizontally application during AutoScaleApplicationsAndNotifyScheduler :", err.Error())
 								done <- true
@@ -244,6 +246,38 @@ func (scheduler AutoScaleApplicationsAndNotifyScheduler) Launch() {
 	}()
 }
 
+// scale scales the application in an operation, so that the scaling is tracked like the ones requested by users
+// and runs after the operations already queued on the application. It waits until the application is scaled.
+func (scheduler AutoScaleApplicationsAndNotifyScheduler) scale(application domain.Application, scalingType applications.ScalingType) error {
+	// The scaling is checked first, so that no operation is recorded at each run for an application that cannot scale further
+	if _, err := scheduler.scaleApplicationUseCase.DryRun(application.ID, scalingType); err != nil {
+		return err
+	}
+
+	scaled := make(chan error, 1)
+	_, err := scheduler.startApplicationOperationUseCase.Execute(commands.StartApplicationOperation{
+		Type:          domain.ScaleOperation,
+		Application:   application,
+		NamespaceName: application.Namespace.Name,
+		UserID:        domain.SchedulerActorID,
+		Run: func(reporter domain.OperationStepReporter) (err error) {
+			// The scheduler is told of the result even when the scaling panics
+			defer func() {
+				if recovered := recover(); recovered != nil {
+					err = fmt.Errorf("scaling of application %s panicked: %v", application.Name, recovered)
+				}
+				scaled <- err
+			}()
+			_, err = scheduler.scaleApplicationUseCase.Execute(application.ID, domain.SchedulerAuditActor(), scalingType, reporter)
+			return err
+		},
+	})
+	if err != nil {
+		return err
+	}
+	return <-scaled
+}
+
 func getAutoScaleAppSchedulerRepeatInterval() (int, error) {
 	var repeatInterval int
 	schedulerScaleApplicationAndNotifyInSeconds := os.Getenv("SCHEDULER_SCALE_APPLICATION_AND_NOTIFY_IN_SECONDS")
@@ -0,0 +1,44 @@
+package schedulers
+
+import (
+	"cloud-app-hive/use_cases"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+// PublishApplicationStatusChangesScheduler publishes the changes of status of the applications to the webhooks of their namespace
+type PublishApplicationStatusChangesScheduler struct {
+	publishApplicationStatusChangesUseCase *use_cases.PublishApplicationStatusChangesUseCase
+}
+
+func (scheduler PublishApplicationStatusChangesScheduler) Launch() {
+	fmt.Println("Starting 'PublishApplicationStatusChangesScheduler' scheduler...")
+	go func() {
+		repeatInterval, err := getPublishApplicationStatusChangesRepeatInterval()
+		if err != nil {
+			fmt.Println("Error when try to get publish application status changes scheduler repeat interval :", err.Error())
+			return
+		}
+		ticker := time.NewTicker(time.Duration(repeatInterval) * time.Second)
+
+		for range ticker.C {
+			if _, err := scheduler.publishApplicationStatusChangesUseCase.Execute(); err != nil {
+				fmt.Println("error when try to publish application status changes during PublishApplicationStatusChangesScheduler :", err.Error())
+			}
+		}
+	}()
+}
+
+func getPublishApplicationStatusChangesRepeatInterval() (int, error) {
+	schedulerPublishApplicationStatusChangesInSeconds := os.Getenv("SCHEDULER_PUBLISH_APPLICATION_STATUS_CHANGES_IN_SECONDS")
+	if schedulerPublishApplicationStatusChangesInSeconds == "" {
+		return 0, fmt.Errorf("SCHEDULER_PUBLISH_APPLICATION_STATUS_CHANGES_IN_SECONDS is not set")
+	}
+	repeatInterval, err := strconv.Atoi(schedulerPublishApplicationStatusChangesInSeconds)
+	if err != nil {
+		return 0, fmt.Errorf("error when convert SCHEDULER_PUBLISH_APPLICATION_STATUS_CHANGES_IN_SECONDS to int during PublishApplicationStatusChangesScheduler : %s", err.Error())
+	}
+	return repeatInterval, nil
+}
@@ -0,0 +1,51 @@
+package schedulers
+
+import (
+	"cloud-app-hive/use_cases"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+// RefreshApplicationSecretsScheduler keeps the Kubernetes Secrets of the applications in sync with their secret providers
+type RefreshApplicationSecretsScheduler struct {
+	refreshApplicationSecretsUseCase use_cases.RefreshApplicationSecretsUseCase
+}
+
+func (scheduler RefreshApplicationSecretsScheduler) Launch() {
+	fmt.Println("Starting 'RefreshApplicationSecretsScheduler' scheduler...")
+	go func() {
+		repeatInterval, err := getRefreshApplicationSecretsRepeatInterval()
+		if err != nil {
+			fmt.Println("Error when try to get refresh application secrets scheduler repeat interval :", err.Error())
+			return
+		}
+		// Pods only read the secrets given as environment variables when they start
+		restart := os.Getenv("REFRESH_APPLICATION_SECRETS_RESTARTS_APPLICATIONS") == "true"
+		ticker := time.NewTicker(time.Duration(repeatInterval) * time.Second)
+
+		for range ticker.C {
+			refreshedApplications, err := scheduler.refreshApplicationSecretsUseCase.Execute(restart)
+			if err != nil {
+				fmt.Println("error when try to refresh application secrets during RefreshApplicationSecretsScheduler :", err.Error())
+				continue
+			}
+			if len(refreshedApplications) > 0 {
+				fmt.Printf("Secrets of applications %v refreshed from their secret providers\n", refreshedApplications)
+			}
+		}
+	}()
+}
+
+func getRefreshApplicationSecretsRepeatInterval() (int, error) {
+	schedulerRefreshApplicationSecretsInSeconds := os.Getenv("SCHEDULER_REFRESH_APPLICATION_SECRETS_IN_SECONDS")
+	if schedulerRefreshApplicationSecretsInSeconds == "" {
+		return 0, fmt.Errorf("SCHEDULER_REFRESH_APPLICATION_SECRETS_IN_SECONDS is not set")
+	}
+	repeatInterval, err := strconv.Atoi(schedulerRefreshApplicationSecretsInSeconds)
+	if err != nil {
+		return 0, fmt.Errorf("error when convert SCHEDULER_REFRESH_APPLICATION_SECRETS_IN_SECONDS to int during RefreshApplicationSecretsScheduler : %s", err.Error())
+	}
+	return repeatInterval, nil
+}
@@ -0,0 +1,44 @@
+package schedulers
+
+import (
+	"cloud-app-hive/use_cases"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+// RetryWebhookDeliveriesScheduler sends again the events whose delivery failed once their next attempt is due
+type RetryWebhookDeliveriesScheduler struct {
+	retryWebhookDeliveriesUseCase use_cases.RetryWebhookDeliveriesUseCase
+}
+
+func (scheduler RetryWebhookDeliveriesScheduler) Launch() {
+	fmt.Println("Starting 'RetryWebhookDeliveriesScheduler' scheduler...")
+	go func() {
+		repeatInterval, err := getRetryWebhookDeliveriesRepeatInterval()
+		if err != nil {
+			fmt.Println("Error when try to get retry webhook deliveries scheduler repeat interval :", err.Error())
+			return
+		}
+		ticker := time.NewTicker(time.Duration(repeatInterval) * time.Second)
+
+		for range ticker.C {
+			if _, err := scheduler.retryWebhookDeliveriesUseCase.Execute(); err != nil {
+				fmt.Println("error when try to retry webhook deliveries during RetryWebhookDeliveriesScheduler :", err.Error())
+			}
+		}
+	}()
+}
+
+func getRetryWebhookDeliveriesRepeatInterval() (int, error) {
+	schedulerRetryWebhookDeliveriesInSeconds := os.Getenv("SCHEDULER_RETRY_WEBHOOK_DELIVERIES_IN_SECONDS")
+	if schedulerRetryWebhookDeliveriesInSeconds == "" {
+		return 0, fmt.Errorf("SCHEDULER_RETRY_WEBHOOK_DELIVERIES_IN_SECONDS is not set")
+	}
+	repeatInterval, err := strconv.Atoi(schedulerRetryWebhookDeliveriesInSeconds)
+	if err != nil {
+		return 0, fmt.Errorf("error when convert SCHEDULER_RETRY_WEBHOOK_DELIVERIES_IN_SECONDS to int during RetryWebhookDeliveriesScheduler : %s", err.Error())
+	}
+	return repeatInterval, nil
+}
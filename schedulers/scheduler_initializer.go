@@ -2,13 +2,15 @@ package schedulers
 
 import (
 	"cloud-app-hive/database"
+	domainRepositories "cloud-app-hive/domain/repositories"
 	"cloud-app-hive/repositories"
 	"cloud-app-hive/services"
 	"cloud-app-hive/use_cases"
 	"cloud-app-hive/use_cases/applications"
+	"cloud-app-hive/use_cases/operations"
 )
 
-func InitSchedulers() {
+func InitSchedulers(containerManager domainRepositories.ContainerManagerRepository, secretsCipher domainRepositories.SecretsCipher) {
 	db, err := database.ConnectToDatabase()
 	if err != nil {
 		panic(err)
@@ -19,9 +21,9 @@ func InitSchedulers() {
 	}
 
 	applicationRepository := repositories.GORMApplicationRepository{
-		Database: db,
+		Database:      db,
+		SecretsCipher: secretsCipher,
 	}
-	containerManager := repositories.KubernetesContainerManagerRepository{}
 
 	findManualScalingApplicationsUseCase := applications.FindManualScalingApplicationsUseCase{
 		ApplicationRepository: applicationRepository,
@@ -42,18 +44,76 @@ func InitSchedulers() {
 	}
 	manualScaleScheduler.Launch()
 
+	// Actions of the schedulers are recorded in the audit log and sent to the webhooks like the ones requested by users
+	webhookRepository := repositories.GORMWebhookRepository{
+		Database:      db,
+		SecretsCipher: secretsCipher,
+	}
+	webhookDeliveryRepository := repositories.GORMWebhookDeliveryRepository{
+		Database: db,
+	}
+	publishWebhookEventUseCase := &use_cases.PublishWebhookEventUseCase{
+		WebhookRepository:         webhookRepository,
+		WebhookDeliveryRepository: webhookDeliveryRepository,
+		WebhookSender:             services.NewHTTPWebhookSenderService(),
+		SecretsCipher:             secretsCipher,
+	}
+	recordAuditEventUseCase := use_cases.RecordAuditEventUseCase{
+		AuditEventRepository: repositories.GORMAuditEventRepository{
+			Database: db,
+		},
+		PublishWebhookEventUseCase: publishWebhookEventUseCase,
+	}
 	scaleApplicationUseCase := applications.ScaleApplicationUseCase{
-		ApplicationRepository: applicationRepository,
-		ContainerManager:      containerManager,
+		ApplicationRepository:   applicationRepository,
+		ContainerManager:        containerManager,
+		RecordAuditEventUseCase: recordAuditEventUseCase,
+	}
+	// The schedulers run in the API process, their operations are queued with the ones requested by users
+	startApplicationOperationUseCase := operations.StartApplicationOperationUseCase{
+		OperationRepository: repositories.GORMOperationRepository{
+			Database: db,
+		},
+		ContainerManagerRepository: containerManager,
+		PublishWebhookEventUseCase: publishWebhookEventUseCase,
 	}
 	autoScaleScheduler := AutoScaleApplicationsAndNotifyScheduler{
 		findAutoScalingApplicationsUseCase,
 		getApplicationMetricsUseCase,
 		*scalabilityNotificationService,
 		scaleApplicationUseCase,
+		startApplicationOperationUseCase,
 	}
 	autoScaleScheduler.Launch()
 
+	refreshApplicationSecretsScheduler := RefreshApplicationSecretsScheduler{
+		refreshApplicationSecretsUseCase: use_cases.RefreshApplicationSecretsUseCase{
+			ApplicationRepository:      applicationRepository,
+			ContainerManagerRepository: containerManager,
+			RecordAuditEventUseCase:    recordAuditEventUseCase,
+		},
+	}
+	refreshApplicationSecretsScheduler.Launch()
+
+	publishApplicationStatusChangesScheduler := PublishApplicationStatusChangesScheduler{
+		publishApplicationStatusChangesUseCase: &use_cases.PublishApplicationStatusChangesUseCase{
+			ApplicationRepository:      applicationRepository,
+			ContainerManagerRepository: containerManager,
+			PublishWebhookEventUseCase: publishWebhookEventUseCase,
+		},
+	}
+	publishApplicationStatusChangesScheduler.Launch()
+
+	// The failed deliveries of the events published by the API are retried here as well
+	retryWebhookDeliveriesScheduler := RetryWebhookDeliveriesScheduler{
+		retryWebhookDeliveriesUseCase: use_cases.RetryWebhookDeliveriesUseCase{
+			WebhookRepository:          webhookRepository,
+			WebhookDeliveryRepository:  webhookDeliveryRepository,
+			PublishWebhookEventUseCase: publishWebhookEventUseCase,
+		},
+	}
+	retryWebhookDeliveriesScheduler.Launch()
+
 	getClusterMetricsUseCase := use_cases.GetClusterMetricsUseCase{
 		ContainerManagerRepository: containerManager,
 	}
@@ -0,0 +1,243 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"cloud-app-hive/domain"
+)
+
+// manifestMediaTypes are the manifests accepted from registries, image indexes first so that every platform is known
+var manifestMediaTypes = []string{
+	"application/vnd.oci.image.index.v1+json",
+	"application/vnd.docker.distribution.manifest.list.v2+json",
+	"application/vnd.oci.image.manifest.v1+json",
+	"application/vnd.docker.distribution.manifest.v2+json",
+}
+
+// maxManifestSize is the size from which a manifest or image config is not read, real ones are a few kilobytes
+const maxManifestSize = 4 << 20
+
+// ImageRegistryService reads image manifests through the Docker Registry HTTP API v2,
+// authenticating with the bearer token or basic auth challenges of the registry
+type ImageRegistryService struct {
+	HTTPClient *http.Client
+}
+
+func NewImageRegistryService() ImageRegistryService {
+	return ImageRegistryService{
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// registryManifest is the part of an image manifest or index needed to find the platforms of the image
+type registryManifest struct {
+	Manifests []struct {
+		Platform *domain.ImagePlatform `json:"platform"`
+	} `json:"manifests"`
+	Config *struct {
+		Digest string `json:"digest"`
+	} `json:"config"`
+}
+
+// FindImageManifest returns the digest and platforms of the image, or nil if the registry does not have it
+func (imageRegistryService ImageRegistryService) FindImageManifest(endpoint domain.ImageRegistryEndpoint, imageReference domain.ImageReference) (*domain.ImageManifest, error) {
+	registryURL := strings.TrimRight(endpoint.URL, "/")
+	manifestURL := fmt.Sprintf("%s/v2/%s/manifests/%s", registryURL, imageReference.Repository, imageReference.ManifestReference())
+	response, err := imageRegistryService.get(endpoint, manifestURL, strings.Join(manifestMediaTypes, ", "))
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	if response.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("error reading manifest %s: unexpected status %d", manifestURL, response.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(response.Body, maxManifestSize))
+	if err != nil {
+		return nil, fmt.Errorf("error reading manifest %s: %w", manifestURL, err)
+	}
+	var manifest registryManifest
+	if err := json.Unmarshal(body, &manifest); err != nil {
+		return nil, fmt.Errorf("error decoding manifest %s: %w", manifestURL, err)
+	}
+
+	imageManifest := domain.ImageManifest{
+		Digest:    response.Header.Get("Docker-Content-Digest"),
+		Platforms: []domain.ImagePlatform{},
+	}
+	if imageManifest.Digest == "" {
+		imageManifest.Digest = fmt.Sprintf("sha256:%x", sha256.Sum256(body))
+	}
+
+	for _, platformManifest := range manifest.Manifests {
+		// Attestations are listed with an "unknown" platform
+		if platformManifest.Platform != nil && platformManifest.Platform.Architecture != "unknown" {
+			imageManifest.Platforms = append(imageManifest.Platforms, *platformManifest.Platform)
+		}
+	}
+	if manifest.Config != nil && manifest.Config.Digest != "" {
+		platform, err := imageRegistryService.findConfigPlatform(endpoint, fmt.Sprintf("%s/v2/%s/blobs/%s", registryURL, imageReference.Repository, manifest.Config.Digest))
+		if err != nil {
+			return nil, err
+		}
+		if platform != nil {
+			imageManifest.Platforms = append(imageManifest.Platforms, *platform)
+		}
+	}
+	return &imageManifest, nil
+}
+
+// findConfigPlatform returns the platform written in the config of a single platform image, or nil if it has none
+func (imageRegistryService ImageRegistryService) findConfigPlatform(endpoint domain.ImageRegistryEndpoint, configURL string) (*domain.ImagePlatform, error) {
+	response, err := imageRegistryService.get(endpoint, configURL, "application/json")
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("error reading image config %s: unexpected status %d", configURL, response.StatusCode)
+	}
+
+	var platform domain.ImagePlatform
+	if err := json.NewDecoder(io.LimitReader(response.Body, maxManifestSize)).Decode(&platform); err != nil {
+		return nil, fmt.Errorf("error decoding image config %s: %w", configURL, err)
+	}
+	if platform.Architecture == "" {
+		return nil, nil
+	}
+	return &platform, nil
+}
+
+// get sends a GET request to the registry, answering its authentication challenge when it returns 401
+func (imageRegistryService ImageRegistryService) get(endpoint domain.ImageRegistryEndpoint, requestURL string, accept string) (*http.Response, error) {
+	response, err := imageRegistryService.send(requestURL, accept, "")
+	if err != nil {
+		return nil, err
+	}
+	if response.StatusCode != http.StatusUnauthorized {
+		return response, nil
+	}
+	challenge := response.Header.Get("WWW-Authenticate")
+	response.Body.Close()
+
+	authorization, err := imageRegistryService.authorization(endpoint, challenge)
+	if err != nil {
+		return nil, err
+	}
+	response, err = imageRegistryService.send(requestURL, accept, authorization)
+	if err != nil {
+		return nil, err
+	}
+	if response.StatusCode == http.StatusUnauthorized || response.StatusCode == http.StatusForbidden {
+		response.Body.Close()
+		return nil, fmt.Errorf("error reading %s: the registry rejected the credentials with status %d", requestURL, response.StatusCode)
+	}
+	return response, nil
+}
+
+func (imageRegistryService ImageRegistryService) send(requestURL string, accept string, authorization string) (*http.Response, error) {
+	request, err := http.NewRequest(http.MethodGet, requestURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error building registry request: %w", err)
+	}
+	request.Header.Set("Accept", accept)
+	if authorization != "" {
+		request.Header.Set("Authorization", authorization)
+	}
+	response, err := imageRegistryService.HTTPClient.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s: %w", requestURL, err)
+	}
+	return response, nil
+}
+
+// registryToken is the body returned by the token endpoint of a registry, older registries return access_token only
+type registryToken struct {
+	Token       string `json:"token"`
+	AccessToken string `json:"access_token"`
+}
+
+// authorization returns the Authorization header answering a "Basic" or "Bearer realm=...,service=...,scope=..." challenge
+func (imageRegistryService ImageRegistryService) authorization(endpoint domain.ImageRegistryEndpoint, challenge string) (string, error) {
+	scheme, rawParameters, _ := strings.Cut(challenge, " ")
+	if strings.EqualFold(scheme, "Basic") {
+		if endpoint.Username == "" {
+			return "", fmt.Errorf("the registry requires credentials")
+		}
+		request, _ := http.NewRequest(http.MethodGet, "/", nil)
+		request.SetBasicAuth(endpoint.Username, endpoint.Password)
+		return request.Header.Get("Authorization"), nil
+	}
+	if !strings.EqualFold(scheme, "Bearer") {
+		return "", fmt.Errorf("unsupported registry authentication challenge %q", challenge)
+	}
+
+	parameters := parseChallengeParameters(rawParameters)
+	realm, err := url.Parse(parameters["realm"])
+	if err != nil || realm.Host == "" {
+		return "", fmt.Errorf("invalid registry authentication realm %q", parameters["realm"])
+	}
+	query := realm.Query()
+	for _, name := range []string{"service", "scope"} {
+		if parameters[name] != "" {
+			query.Set(name, parameters[name])
+		}
+	}
+	realm.RawQuery = query.Encode()
+
+	request, err := http.NewRequest(http.MethodGet, realm.String(), nil)
+	if err != nil {
+		return "", fmt.Errorf("error building registry token request: %w", err)
+	}
+	if endpoint.Username != "" {
+		request.SetBasicAuth(endpoint.Username, endpoint.Password)
+	}
+	response, err := imageRegistryService.HTTPClient.Do(request)
+	if err != nil {
+		return "", fmt.Errorf("error requesting registry token: %w", err)
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("error requesting registry token: unexpected status %d", response.StatusCode)
+	}
+
+	var token registryToken
+	if err := json.NewDecoder(io.LimitReader(response.Body, maxManifestSize)).Decode(&token); err != nil {
+		return "", fmt.Errorf("error decoding registry token: %w", err)
+	}
+	if token.Token == "" {
+		token.Token = token.AccessToken
+	}
+	return "Bearer " + token.Token, nil
+}
+
+// parseChallengeParameters parses the comma separated key="value" parameters of a WWW-Authenticate challenge,
+// commas may appear inside quoted values such as scopes with several actions
+func parseChallengeParameters(rawParameters string) map[string]string {
+	parameters := map[string]string{}
+	for rawParameters != "" {
+		key, rest, found := strings.Cut(strings.TrimLeft(rawParameters, ", "), "=")
+		if !found {
+			break
+		}
+		var value string
+		if strings.HasPrefix(rest, `"`) {
+			value, rest, _ = strings.Cut(rest[1:], `"`)
+		} else {
+			value, rest, _ = strings.Cut(rest, ",")
+		}
+		parameters[strings.ToLower(strings.TrimSpace(key))] = value
+		rawParameters = rest
+	}
+	return parameters
+}
@@ -0,0 +1,86 @@
+package services
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"cloud-app-hive/domain"
+)
+
+func TestImageRegistryService_FindsMultiPlatformImagesWithBearerToken(t *testing.T) {
+	var registry *httptest.Server
+	registry = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/token":
+			if username, password, _ := r.BasicAuth(); username != "robot" || password != "s3cr3t" || r.URL.Query().Get("scope") != "repository:team/api:pull" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Write([]byte(`{"token":"registry-token"}`))
+		case "/v2/team/api/manifests/v2":
+			if r.Header.Get("Authorization") != "Bearer registry-token" {
+				w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="registry",scope="repository:team/api:pull"`, registry.URL))
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Header().Set("Docker-Content-Digest", "sha256:index")
+			w.Write([]byte(`{"manifests":[
+				{"platform":{"os":"linux","architecture":"amd64"}},
+				{"platform":{"os":"linux","architecture":"arm64"}},
+				{"platform":{"os":"unknown","architecture":"unknown"}}
+			]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer registry.Close()
+
+	endpoint := domain.ImageRegistryEndpoint{URL: registry.URL, Username: "robot", Password: "s3cr3t"}
+	imageManifest, err := NewImageRegistryService().FindImageManifest(endpoint, domain.ImageReference{Repository: "team/api", Tag: "v2"})
+	if err != nil || imageManifest == nil {
+		t.Fatalf("expected the manifest of the image, got %v, %v", imageManifest, err)
+	}
+	if imageManifest.Digest != "sha256:index" {
+		t.Errorf("expected the digest of the registry, got %s", imageManifest.Digest)
+	}
+	if architectures := imageManifest.Architectures(); len(architectures) != 2 || architectures[0] != "amd64" || architectures[1] != "arm64" {
+		t.Errorf("expected the attestation platform to be skipped, got %v", architectures)
+	}
+
+	imageManifest, err = NewImageRegistryService().FindImageManifest(endpoint, domain.ImageReference{Repository: "team/api", Tag: "v3"})
+	if err != nil || imageManifest != nil {
+		t.Errorf("expected a missing tag to return nil, got %v, %v", imageManifest, err)
+	}
+
+	endpoint.Password = "wrong"
+	if _, err := NewImageRegistryService().FindImageManifest(endpoint, domain.ImageReference{Repository: "team/api", Tag: "v2"}); err == nil {
+		t.Error("expected an error with wrong credentials")
+	}
+}
+
+func TestImageRegistryService_ReadsThePlatformOfSinglePlatformImages(t *testing.T) {
+	registry := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/library/nginx/manifests/latest":
+			w.Write([]byte(`{"config":{"digest":"sha256:config"}}`))
+		case "/v2/library/nginx/blobs/sha256:config":
+			w.Write([]byte(`{"os":"linux","architecture":"arm64","config":{}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer registry.Close()
+
+	imageManifest, err := NewImageRegistryService().FindImageManifest(domain.ImageRegistryEndpoint{URL: registry.URL}, domain.ImageReference{Repository: "library/nginx", Tag: "latest"})
+	if err != nil || imageManifest == nil {
+		t.Fatalf("expected the manifest of the image, got %v, %v", imageManifest, err)
+	}
+	if len(imageManifest.Digest) != len("sha256:")+64 {
+		t.Errorf("expected the digest to be computed from the manifest, got %s", imageManifest.Digest)
+	}
+	if imageManifest.SupportsOneOf([]string{"amd64"}) {
+		t.Errorf("expected the arm64 platform of the config, got %v", imageManifest.Platforms)
+	}
+}
@@ -0,0 +1,113 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"cloud-app-hive/domain"
+)
+
+// maxScanReportSize is the size from which a scan report is not read, images with thousands of vulnerabilities stay well below it
+const maxScanReportSize = 32 << 20
+
+// TrivyImageScannerService scans images through an HTTP scanning service answering with the JSON report of Trivy,
+// such as a service wrapping "trivy image --format json" or a local stand-in returning fixed reports.
+// The image is sent by digest with the credentials of its registry so that the scanner pulls exactly the deployed image.
+type TrivyImageScannerService struct {
+	URL        string
+	Token      string
+	HTTPClient *http.Client
+}
+
+func NewTrivyImageScannerService(url string, token string) TrivyImageScannerService {
+	return TrivyImageScannerService{
+		URL:   strings.TrimRight(url, "/"),
+		Token: token,
+		// Scanning an image that is not cached by the scanner downloads all its layers
+		HTTPClient: &http.Client{Timeout: 5 * time.Minute},
+	}
+}
+
+// trivyScanRequest is the body sent to the scanning service
+type trivyScanRequest struct {
+	Image    string `json:"image"`
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+}
+
+// trivyReport is the part of a Trivy JSON report listing the vulnerabilities of every target of the image
+type trivyReport struct {
+	Results []struct {
+		Target          string `json:"Target"`
+		Vulnerabilities []struct {
+			VulnerabilityID  string `json:"VulnerabilityID"`
+			PkgName          string `json:"PkgName"`
+			InstalledVersion string `json:"InstalledVersion"`
+			FixedVersion     string `json:"FixedVersion"`
+			Severity         string `json:"Severity"`
+			Title            string `json:"Title"`
+		} `json:"Vulnerabilities"`
+	} `json:"Results"`
+}
+
+// ScanImage returns the vulnerabilities of the image, pulled from the registry endpoint by its digest
+func (trivyImageScannerService TrivyImageScannerService) ScanImage(endpoint domain.ImageRegistryEndpoint, imageReference domain.ImageReference) (domain.ImageVulnerabilities, error) {
+	registryHost := endpoint.URL
+	if _, host, found := strings.Cut(registryHost, "://"); found {
+		registryHost = host
+	}
+	body, err := json.Marshal(trivyScanRequest{
+		Image:    fmt.Sprintf("%s/%s@%s", strings.TrimRight(registryHost, "/"), imageReference.Repository, imageReference.ManifestReference()),
+		Username: endpoint.Username,
+		Password: endpoint.Password,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error encoding scan request: %w", err)
+	}
+
+	request, err := http.NewRequest(http.MethodPost, trivyImageScannerService.URL+"/scan", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("error building scan request: %w", err)
+	}
+	request.Header.Set("Content-Type", "application/json")
+	if trivyImageScannerService.Token != "" {
+		request.Header.Set("Trivy-Token", trivyImageScannerService.Token)
+	}
+	response, err := trivyImageScannerService.HTTPClient.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("error scanning image %s: %w", imageReference.Repository, err)
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("error scanning image %s: unexpected status %d", imageReference.Repository, response.StatusCode)
+	}
+
+	var report trivyReport
+	if err := json.NewDecoder(io.LimitReader(response.Body, maxScanReportSize)).Decode(&report); err != nil {
+		return nil, fmt.Errorf("error decoding scan report of image %s: %w", imageReference.Repository, err)
+	}
+
+	vulnerabilities := domain.ImageVulnerabilities{}
+	for _, result := range report.Results {
+		for _, vulnerability := range result.Vulnerabilities {
+			severity := domain.VulnerabilitySeverity(strings.ToUpper(vulnerability.Severity))
+			if !severity.IsValid() {
+				severity = domain.SeverityUnknown
+			}
+			vulnerabilities = append(vulnerabilities, domain.ImageVulnerability{
+				ID:               vulnerability.VulnerabilityID,
+				PackageName:      vulnerability.PkgName,
+				InstalledVersion: vulnerability.InstalledVersion,
+				FixedVersion:     vulnerability.FixedVersion,
+				Severity:         severity,
+				Title:            vulnerability.Title,
+			})
+		}
+	}
+	return vulnerabilities, nil
+}
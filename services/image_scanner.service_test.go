@@ -0,0 +1,54 @@
+package services
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"cloud-app-hive/domain"
+)
+
+func TestTrivyImageScannerService_ScansImagesByDigest(t *testing.T) {
+	var scanRequest trivyScanRequest
+	scanner := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Trivy-Token") != "scanner-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.Method != http.MethodPost || r.URL.Path != "/scan" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		json.NewDecoder(r.Body).Decode(&scanRequest)
+		w.Write([]byte(`{"Results":[
+			{"Target":"team/api (debian 12.4)","Vulnerabilities":[{"VulnerabilityID":"CVE-2024-0001","PkgName":"openssl","InstalledVersion":"3.0.11","FixedVersion":"3.0.13","Severity":"CRITICAL","Title":"openssl: overflow"}]},
+			{"Target":"app/go.mod","Vulnerabilities":[{"VulnerabilityID":"GHSA-xxxx","PkgName":"golang.org/x/net","InstalledVersion":"0.17.0","Severity":"negligible"}]},
+			{"Target":"requirements.txt"}
+		]}`))
+	}))
+	defer scanner.Close()
+
+	endpoint := domain.ImageRegistryEndpoint{URL: "https://harbor.example.com", Username: "robot", Password: "s3cr3t"}
+	imageReference := domain.ImageReference{Repository: "team/api", Tag: "v2", Digest: "sha256:api"}
+	vulnerabilities, err := NewTrivyImageScannerService(scanner.URL+"/", "scanner-token").ScanImage(endpoint, imageReference)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if scanRequest.Image != "harbor.example.com/team/api@sha256:api" || scanRequest.Username != "robot" || scanRequest.Password != "s3cr3t" {
+		t.Errorf("expected the image to be scanned by digest with the registry credentials, got %+v", scanRequest)
+	}
+	if len(vulnerabilities) != 2 {
+		t.Fatalf("expected the vulnerabilities of every target, got %v", vulnerabilities)
+	}
+	if vulnerabilities[0].ID != "CVE-2024-0001" || vulnerabilities[0].Severity != domain.SeverityCritical || vulnerabilities[0].FixedVersion != "3.0.13" {
+		t.Errorf("unexpected vulnerability %+v", vulnerabilities[0])
+	}
+	if vulnerabilities[1].Severity != domain.SeverityUnknown {
+		t.Errorf("expected an unknown severity to be reported as UNKNOWN, got %s", vulnerabilities[1].Severity)
+	}
+
+	if _, err := NewTrivyImageScannerService(scanner.URL, "wrong-token").ScanImage(endpoint, imageReference); err == nil {
+		t.Error("expected an error with a wrong token")
+	}
+}
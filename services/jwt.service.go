@@ -0,0 +1,420 @@
+package services
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// jwtClockSkew is the tolerance applied to the exp, nbf and iat claims
+const jwtClockSkew = time.Minute
+
+// jwksMinimumRefreshInterval prevents tokens signed with unknown key IDs from hammering the JWKS endpoint
+const jwksMinimumRefreshInterval = time.Minute
+
+// jwksMaximumAge is how long keys fetched from a URL are trusted before being fetched again
+const jwksMaximumAge = time.Hour
+
+// JWTClaims holds the verified claims of a token
+type JWTClaims struct {
+	Subject  string
+	Issuer   string
+	Audience []string
+	Email    string
+	// EmailVerified tells if the issuer verified that the user owns the email
+	EmailVerified bool
+	ExpiresAt     time.Time
+	// Claims contains every claim of the token, including the ones above
+	Claims map[string]interface{}
+}
+
+type jwtHeader struct {
+	Algorithm string `json:"alg"`
+	KeyID     string `json:"kid"`
+	Type      string `json:"typ"`
+}
+
+type jsonWebKey struct {
+	KeyType string `json:"kty"`
+	KeyID   string `json:"kid"`
+	Use     string `json:"use"`
+	N       string `json:"n"`
+	E       string `json:"e"`
+	Curve   string `json:"crv"`
+	X       string `json:"x"`
+	Y       string `json:"y"`
+}
+
+type jsonWebKeySet struct {
+	Keys []jsonWebKey `json:"keys"`
+}
+
+// JWTService verifies the JWTs issued by the configured OpenID Connect provider.
+// Keys are read from AUTH_JWKS_FILE, fetched from AUTH_JWKS_URL or discovered from the issuer's openid-configuration.
+type JWTService struct {
+	issuer     string
+	audience   string
+	jwksFile   string
+	jwksURL    string
+	httpClient *http.Client
+
+	mutex       sync.Mutex
+	keys        map[string]crypto.PublicKey
+	lastFetchAt time.Time
+}
+
+func NewJWTService() (*JWTService, error) {
+	jwtService := &JWTService{
+		issuer:     strings.TrimSuffix(os.Getenv("AUTH_ISSUER"), "/"),
+		audience:   os.Getenv("AUTH_AUDIENCE"),
+		jwksFile:   os.Getenv("AUTH_JWKS_FILE"),
+		jwksURL:    os.Getenv("AUTH_JWKS_URL"),
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+	if jwtService.issuer == "" {
+		return nil, fmt.Errorf("AUTH_ISSUER is not set, it is required to authenticate the users, set AUTH_JWKS_FILE with it to run without identity provider")
+	}
+	// Without audience, a token the issuer minted for any of its other clients would be accepted
+	if jwtService.audience == "" {
+		return nil, fmt.Errorf("AUTH_AUDIENCE is not set, it is required to only accept the tokens the issuer minted for this API")
+	}
+	if jwtService.jwksFile != "" && jwtService.jwksURL != "" {
+		return nil, fmt.Errorf("AUTH_JWKS_FILE and AUTH_JWKS_URL cannot both be set")
+	}
+	if err := jwtService.refreshKeys(); err != nil {
+		return nil, err
+	}
+	return jwtService, nil
+}
+
+// NewJWTServiceWithKeys returns a JWTService that trusts the given keys, indexed by key ID, without fetching any JWKS
+func NewJWTServiceWithKeys(issuer string, audience string, keys map[string]crypto.PublicKey) *JWTService {
+	return &JWTService{
+		issuer:      strings.TrimSuffix(issuer, "/"),
+		audience:    audience,
+		keys:        keys,
+		lastFetchAt: time.Now(),
+	}
+}
+
+// Verify checks the signature, issuer, audience and validity period of a token and returns its claims
+func (s *JWTService) Verify(token string) (*JWTClaims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("token is not a JWT")
+	}
+
+	var header jwtHeader
+	if err := decodeJWTSegment(parts[0], &header); err != nil {
+		return nil, fmt.Errorf("invalid token header: %w", err)
+	}
+	hash, ok := jwtAlgorithmHashes[header.Algorithm]
+	if !ok {
+		return nil, fmt.Errorf("unsupported token algorithm %q", header.Algorithm)
+	}
+
+	key, err := s.findKey(header.KeyID)
+	if err != nil {
+		return nil, err
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("invalid token signature encoding: %w", err)
+	}
+	if err := verifyJWTSignature(header.Algorithm, hash, key, parts[0]+"."+parts[1], signature); err != nil {
+		return nil, err
+	}
+
+	var claims map[string]interface{}
+	if err := decodeJWTSegment(parts[1], &claims); err != nil {
+		return nil, fmt.Errorf("invalid token claims: %w", err)
+	}
+	return s.validateClaims(claims)
+}
+
+func (s *JWTService) validateClaims(claims map[string]interface{}) (*JWTClaims, error) {
+	now := time.Now()
+	jwtClaims := &JWTClaims{Claims: claims}
+
+	jwtClaims.Issuer, _ = claims["iss"].(string)
+	if strings.TrimSuffix(jwtClaims.Issuer, "/") != s.issuer {
+		return nil, fmt.Errorf("token issuer %q is not trusted", jwtClaims.Issuer)
+	}
+
+	switch audience := claims["aud"].(type) {
+	case string:
+		jwtClaims.Audience = []string{audience}
+	case []interface{}:
+		for _, value := range audience {
+			if audienceValue, ok := value.(string); ok {
+				jwtClaims.Audience = append(jwtClaims.Audience, audienceValue)
+			}
+		}
+	}
+	if !containsString(jwtClaims.Audience, s.audience) {
+		return nil, fmt.Errorf("token is not intended for audience %q", s.audience)
+	}
+
+	expiresAt, ok := claims["exp"].(float64)
+	if !ok {
+		return nil, fmt.Errorf("token has no expiration")
+	}
+	jwtClaims.ExpiresAt = time.Unix(int64(expiresAt), 0)
+	if now.After(jwtClaims.ExpiresAt.Add(jwtClockSkew)) {
+		return nil, fmt.Errorf("token has expired")
+	}
+	if notBefore, ok := claims["nbf"].(float64); ok && now.Add(jwtClockSkew).Before(time.Unix(int64(notBefore), 0)) {
+		return nil, fmt.Errorf("token is not valid yet")
+	}
+	if issuedAt, ok := claims["iat"].(float64); ok && now.Add(jwtClockSkew).Before(time.Unix(int64(issuedAt), 0)) {
+		return nil, fmt.Errorf("token is issued in the future")
+	}
+
+	jwtClaims.Subject, _ = claims["sub"].(string)
+	if jwtClaims.Subject == "" {
+		return nil, fmt.Errorf("token has no subject")
+	}
+	jwtClaims.Email, _ = claims["email"].(string)
+	// Some issuers send the email_verified claim as a string
+	switch emailVerified := claims["email_verified"].(type) {
+	case bool:
+		jwtClaims.EmailVerified = emailVerified
+	case string:
+		jwtClaims.EmailVerified = emailVerified == "true"
+	}
+
+	return jwtClaims, nil
+}
+
+// findKey returns the key with the given ID, fetching the keys again when it is unknown or when they are too old
+func (s *JWTService) findKey(keyID string) (crypto.PublicKey, error) {
+	s.mutex.Lock()
+	key, found := s.lookupKey(keyID)
+	shouldRefresh := s.jwksFile == "" && s.httpClient != nil &&
+		((!found && time.Since(s.lastFetchAt) > jwksMinimumRefreshInterval) || time.Since(s.lastFetchAt) > jwksMaximumAge)
+	s.mutex.Unlock()
+
+	if shouldRefresh {
+		if err := s.refreshKeys(); err != nil {
+			fmt.Println("Error while refreshing JWKS: ", err)
+		}
+		s.mutex.Lock()
+		key, found = s.lookupKey(keyID)
+		s.mutex.Unlock()
+	}
+	if !found {
+		return nil, fmt.Errorf("no key found to verify a token signed with key ID %q", keyID)
+	}
+	return key, nil
+}
+
+// lookupKey must be called with the mutex held. A token without key ID is accepted when there is a single key.
+func (s *JWTService) lookupKey(keyID string) (crypto.PublicKey, bool) {
+	if keyID == "" && len(s.keys) == 1 {
+		for _, key := range s.keys {
+			return key, true
+		}
+	}
+	key, ok := s.keys[keyID]
+	return key, ok
+}
+
+func (s *JWTService) refreshKeys() error {
+	var jwksContent []byte
+	var err error
+	switch {
+	case s.jwksFile != "":
+		jwksContent, err = os.ReadFile(s.jwksFile)
+		if err != nil {
+			return fmt.Errorf("error while reading AUTH_JWKS_FILE: %w", err)
+		}
+	default:
+		jwksURL := s.jwksURL
+		if jwksURL == "" {
+			jwksURL, err = s.discoverJWKSURL()
+			if err != nil {
+				return err
+			}
+		}
+		jwksContent, err = s.fetch(jwksURL)
+		if err != nil {
+			return fmt.Errorf("error while fetching JWKS: %w", err)
+		}
+	}
+
+	keys, err := parseJSONWebKeySet(jwksContent)
+	if err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.keys = keys
+	s.lastFetchAt = time.Now()
+	return nil
+}
+
+// discoverJWKSURL reads the jwks_uri of the issuer's OpenID Connect discovery document
+func (s *JWTService) discoverJWKSURL() (string, error) {
+	content, err := s.fetch(s.issuer + "/.well-known/openid-configuration")
+	if err != nil {
+		return "", fmt.Errorf("error while fetching OpenID configuration of %s: %w", s.issuer, err)
+	}
+	var configuration struct {
+		JWKSURI string `json:"jwks_uri"`
+	}
+	if err := json.Unmarshal(content, &configuration); err != nil || configuration.JWKSURI == "" {
+		return "", fmt.Errorf("OpenID configuration of %s has no jwks_uri", s.issuer)
+	}
+	return configuration.JWKSURI, nil
+}
+
+func (s *JWTService) fetch(url string) ([]byte, error) {
+	response, err := s.httpClient.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d from %s", response.StatusCode, url)
+	}
+	return io.ReadAll(io.LimitReader(response.Body, 1<<20))
+}
+
+var jwtAlgorithmHashes = map[string]crypto.Hash{
+	"RS256": crypto.SHA256,
+	"RS384": crypto.SHA384,
+	"RS512": crypto.SHA512,
+	"PS256": crypto.SHA256,
+	"PS384": crypto.SHA384,
+	"PS512": crypto.SHA512,
+	"ES256": crypto.SHA256,
+	"ES384": crypto.SHA384,
+	"ES512": crypto.SHA512,
+}
+
+// jwtAlgorithmCurves binds each ECDSA algorithm to the only curve it is defined for
+var jwtAlgorithmCurves = map[string]string{
+	"ES256": "P-256",
+	"ES384": "P-384",
+	"ES512": "P-521",
+}
+
+func verifyJWTSignature(algorithm string, hash crypto.Hash, key crypto.PublicKey, signingInput string, signature []byte) error {
+	hasher := hash.New()
+	hasher.Write([]byte(signingInput))
+	digest := hasher.Sum(nil)
+
+	switch publicKey := key.(type) {
+	case *rsa.PublicKey:
+		var err error
+		switch algorithm[:2] {
+		case "RS":
+			err = rsa.VerifyPKCS1v15(publicKey, hash, digest, signature)
+		case "PS":
+			err = rsa.VerifyPSS(publicKey, hash, digest, signature, nil)
+		default:
+			err = errors.New("algorithm does not match an RSA key")
+		}
+		if err != nil {
+			return fmt.Errorf("invalid token signature: %w", err)
+		}
+		return nil
+	case *ecdsa.PublicKey:
+		if jwtAlgorithmCurves[algorithm] != publicKey.Curve.Params().Name {
+			return fmt.Errorf("algorithm %s does not match the curve %s of the key", algorithm, publicKey.Curve.Params().Name)
+		}
+		keySize := (publicKey.Curve.Params().BitSize + 7) / 8
+		if len(signature) != 2*keySize {
+			return fmt.Errorf("invalid token signature for an EC key")
+		}
+		r := new(big.Int).SetBytes(signature[:keySize])
+		sValue := new(big.Int).SetBytes(signature[keySize:])
+		if !ecdsa.Verify(publicKey, digest, r, sValue) {
+			return fmt.Errorf("invalid token signature")
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported key type %T", key)
+	}
+}
+
+func parseJSONWebKeySet(content []byte) (map[string]crypto.PublicKey, error) {
+	var keySet jsonWebKeySet
+	if err := json.Unmarshal(content, &keySet); err != nil {
+		return nil, fmt.Errorf("invalid JWKS: %w", err)
+	}
+
+	keys := make(map[string]crypto.PublicKey)
+	for _, key := range keySet.Keys {
+		if key.Use != "" && key.Use != "sig" {
+			continue
+		}
+		switch key.KeyType {
+		case "RSA":
+			n, err := base64.RawURLEncoding.DecodeString(key.N)
+			if err != nil {
+				return nil, fmt.Errorf("invalid modulus of key %s: %w", key.KeyID, err)
+			}
+			e, err := base64.RawURLEncoding.DecodeString(key.E)
+			if err != nil {
+				return nil, fmt.Errorf("invalid exponent of key %s: %w", key.KeyID, err)
+			}
+			keys[key.KeyID] = &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+		case "EC":
+			var curve elliptic.Curve
+			switch key.Curve {
+			case "P-256":
+				curve = elliptic.P256()
+			case "P-384":
+				curve = elliptic.P384()
+			case "P-521":
+				curve = elliptic.P521()
+			default:
+				return nil, fmt.Errorf("unsupported curve %s of key %s", key.Curve, key.KeyID)
+			}
+			x, err := base64.RawURLEncoding.DecodeString(key.X)
+			if err != nil {
+				return nil, fmt.Errorf("invalid x coordinate of key %s: %w", key.KeyID, err)
+			}
+			y, err := base64.RawURLEncoding.DecodeString(key.Y)
+			if err != nil {
+				return nil, fmt.Errorf("invalid y coordinate of key %s: %w", key.KeyID, err)
+			}
+			keys[key.KeyID] = &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		}
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("JWKS contains no signing key")
+	}
+	return keys, nil
+}
+
+func decodeJWTSegment(segment string, value interface{}) error {
+	content, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(content, value)
+}
+
+func containsString(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
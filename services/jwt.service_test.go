@@ -0,0 +1,146 @@
+package services
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+const testIssuer = "https://auth.example.com/"
+
+func signTestJWT(t *testing.T, algorithm string, keyID string, key crypto.Signer, claims map[string]interface{}) string {
+	t.Helper()
+	header, _ := json.Marshal(map[string]string{"alg": algorithm, "kid": keyID, "typ": "JWT"})
+	payload, _ := json.Marshal(claims)
+	signingInput := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	digest := sha256.Sum256([]byte(signingInput))
+
+	var signature []byte
+	switch signer := key.(type) {
+	case *rsa.PrivateKey:
+		var err error
+		signature, err = rsa.SignPKCS1v15(rand.Reader, signer, crypto.SHA256, digest[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+	case *ecdsa.PrivateKey:
+		r, s, err := ecdsa.Sign(rand.Reader, signer, digest[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		keySize := (signer.Curve.Params().BitSize + 7) / 8
+		signature = append(r.FillBytes(make([]byte, keySize)), s.FillBytes(make([]byte, keySize))...)
+	}
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func validTestClaims() map[string]interface{} {
+	return map[string]interface{}{
+		"iss":   testIssuer,
+		"aud":   []string{"cloud-app-hive"},
+		"sub":   "user-1",
+		"email": "user@example.com",
+		"exp":   time.Now().Add(time.Hour).Unix(),
+		"iat":   time.Now().Unix(),
+	}
+}
+
+func TestJWTService_Verify(t *testing.T) {
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	p384Key, _ := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	otherKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	jwtService := NewJWTServiceWithKeys(testIssuer, "cloud-app-hive", map[string]crypto.PublicKey{
+		"rsa":  &rsaKey.PublicKey,
+		"ec":   &ecKey.PublicKey,
+		"p384": &p384Key.PublicKey,
+	})
+
+	claims, err := jwtService.Verify(signTestJWT(t, "RS256", "rsa", rsaKey, validTestClaims()))
+	if err != nil {
+		t.Fatalf("expected RS256 token to be valid, got %v", err)
+	}
+	if claims.Subject != "user-1" || claims.Email != "user@example.com" || claims.EmailVerified {
+		t.Errorf("unexpected claims %+v", claims)
+	}
+	for _, emailVerified := range []interface{}{true, "true"} {
+		verifiedClaims := validTestClaims()
+		verifiedClaims["email_verified"] = emailVerified
+		claims, err := jwtService.Verify(signTestJWT(t, "RS256", "rsa", rsaKey, verifiedClaims))
+		if err != nil || !claims.EmailVerified {
+			t.Errorf("expected email_verified %v to verify the email, got %+v, %v", emailVerified, claims, err)
+		}
+	}
+	if _, err := jwtService.Verify(signTestJWT(t, "ES256", "ec", ecKey, validTestClaims())); err != nil {
+		t.Errorf("expected ES256 token to be valid, got %v", err)
+	}
+
+	invalidTokens := map[string]string{
+		"wrong key":      signTestJWT(t, "RS256", "rsa", otherKey, validTestClaims()),
+		"unknown key ID": signTestJWT(t, "RS256", "unknown", rsaKey, validTestClaims()),
+		"alg none":       signTestJWT(t, "none", "rsa", rsaKey, validTestClaims()),
+		"ES256 on P-384": signTestJWT(t, "ES256", "p384", p384Key, validTestClaims()),
+		"not a JWT":      "api-key",
+	}
+	for name, override := range map[string]map[string]interface{}{
+		"expired":         {"exp": time.Now().Add(-time.Hour).Unix()},
+		"wrong issuer":    {"iss": "https://evil.example.com"},
+		"wrong audience":  {"aud": "another-api"},
+		"without subject": {"sub": ""},
+		"not valid yet":   {"nbf": time.Now().Add(time.Hour).Unix()},
+	} {
+		claims := validTestClaims()
+		for claim, value := range override {
+			claims[claim] = value
+		}
+		invalidTokens[name] = signTestJWT(t, "RS256", "rsa", rsaKey, claims)
+	}
+	for name, token := range invalidTokens {
+		if _, err := jwtService.Verify(token); err == nil {
+			t.Errorf("expected token %s to be rejected", name)
+		}
+	}
+}
+
+func TestJWTService_ReadsKeysFromJWKSFile(t *testing.T) {
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	jwks, _ := json.Marshal(map[string]interface{}{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": "file-key",
+			"use": "sig",
+			"n":   base64.RawURLEncoding.EncodeToString(rsaKey.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(rsaKey.E)).Bytes()),
+		}},
+	})
+	jwksFile := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(jwksFile, jwks, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("AUTH_ISSUER", testIssuer)
+	t.Setenv("AUTH_AUDIENCE", "")
+	t.Setenv("AUTH_JWKS_FILE", jwksFile)
+	t.Setenv("AUTH_JWKS_URL", "")
+
+	if _, err := NewJWTService(); err == nil {
+		t.Fatal("expected the service not to start without audience")
+	}
+	t.Setenv("AUTH_AUDIENCE", "cloud-app-hive")
+	jwtService, err := NewJWTService()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := jwtService.Verify(signTestJWT(t, "RS256", "file-key", rsaKey, validTestClaims())); err != nil {
+		t.Errorf("expected token signed with the file key to be valid, got %v", err)
+	}
+}
@@ -0,0 +1,139 @@
+package services
+
+import (
+	"bufio"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// MasterKeyProvider wraps and unwraps data keys with master keys.
+// It mirrors the encrypt and decrypt operations of a KMS, so that a KMS can replace the key file.
+type MasterKeyProvider interface {
+	// CurrentKeyID returns the ID of the master key wrapping new data keys
+	CurrentKeyID() string
+	Encrypt(keyID string, plaintext []byte) ([]byte, error)
+	Decrypt(keyID string, ciphertext []byte) ([]byte, error)
+}
+
+// NewMasterKeyProvider returns the master keys read from MASTER_KEYS_FILE, the current one is MASTER_KEY_ID or the last of the file
+func NewMasterKeyProvider() (MasterKeyProvider, error) {
+	masterKeysFile := os.Getenv("MASTER_KEYS_FILE")
+	if masterKeysFile == "" {
+		return nil, fmt.Errorf("MASTER_KEYS_FILE environment variable is not set, application secrets cannot be encrypted")
+	}
+	return NewFileMasterKeyProvider(masterKeysFile, os.Getenv("MASTER_KEY_ID"))
+}
+
+// FileMasterKeyProvider wraps data keys with AES-256-GCM master keys read from a file
+type FileMasterKeyProvider struct {
+	keys         map[string][]byte
+	currentKeyID string
+}
+
+// NewFileMasterKeyProvider reads a file of "<key id>=<base64 encoded 32 bytes key>" lines, empty lines and lines starting with # are ignored.
+// Keeping the previous keys in the file lets the secrets they protect be decrypted until the keys are rotated.
+func NewFileMasterKeyProvider(path string, currentKeyID string) (*FileMasterKeyProvider, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("error opening master keys file: %w", err)
+	}
+	defer file.Close()
+
+	fileMasterKeyProvider := &FileMasterKeyProvider{keys: map[string][]byte{}}
+	lastKeyID := ""
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		separatorIndex := strings.Index(line, "=")
+		if separatorIndex <= 0 {
+			return nil, fmt.Errorf("master keys file lines must be formatted as <key id>=<base64 key>")
+		}
+		keyID := strings.TrimSpace(line[:separatorIndex])
+		key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(line[separatorIndex+1:]))
+		if err != nil {
+			return nil, fmt.Errorf("error decoding master key %s: %w", keyID, err)
+		}
+		if len(key) != 32 {
+			return nil, fmt.Errorf("master key %s must be 32 bytes long, got %d", keyID, len(key))
+		}
+		fileMasterKeyProvider.keys[keyID] = key
+		lastKeyID = keyID
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading master keys file: %w", err)
+	}
+
+	if currentKeyID == "" {
+		currentKeyID = lastKeyID
+	}
+	if _, ok := fileMasterKeyProvider.keys[currentKeyID]; !ok {
+		return nil, fmt.Errorf("master key %s is not in the master keys file", currentKeyID)
+	}
+	fileMasterKeyProvider.currentKeyID = currentKeyID
+	return fileMasterKeyProvider, nil
+}
+
+func (fileMasterKeyProvider *FileMasterKeyProvider) CurrentKeyID() string {
+	return fileMasterKeyProvider.currentKeyID
+}
+
+func (fileMasterKeyProvider *FileMasterKeyProvider) Encrypt(keyID string, plaintext []byte) ([]byte, error) {
+	key, ok := fileMasterKeyProvider.keys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("unknown master key %s", keyID)
+	}
+	return sealAESGCM(key, plaintext, []byte(keyID))
+}
+
+func (fileMasterKeyProvider *FileMasterKeyProvider) Decrypt(keyID string, ciphertext []byte) ([]byte, error) {
+	key, ok := fileMasterKeyProvider.keys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("unknown master key %s, it may have been removed before rotating the secret keys", keyID)
+	}
+	return openAESGCM(key, ciphertext, []byte(keyID))
+}
+
+// sealAESGCM encrypts plaintext and returns the random nonce followed by the ciphertext
+func sealAESGCM(key []byte, plaintext []byte, additionalData []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("error generating nonce: %w", err)
+	}
+	return gcm.Seal(nonce, nonce, plaintext, additionalData), nil
+}
+
+// openAESGCM decrypts the output of sealAESGCM
+func openAESGCM(key []byte, sealed []byte, additionalData []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < gcm.NonceSize() {
+		return nil, fmt.Errorf("ciphertext is too short")
+	}
+	plaintext, err := gcm.Open(nil, sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():], additionalData)
+	if err != nil {
+		return nil, fmt.Errorf("error decrypting: %w", err)
+	}
+	return plaintext, nil
+}
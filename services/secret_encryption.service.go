@@ -0,0 +1,192 @@
+package services
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"cloud-app-hive/domain"
+	"cloud-app-hive/domain/repositories"
+)
+
+// SecretEncryptionService encrypts application secrets with envelope encryption:
+// each namespace has a random data key encrypting its secrets, and only the data key wrapped by a master key is stored.
+type SecretEncryptionService struct {
+	MasterKeyProvider          MasterKeyProvider
+	NamespaceDataKeyRepository repositories.NamespaceDataKeyRepository
+}
+
+// Encrypt encrypts the values of the secrets with the latest data key of the namespace, creating it on first use.
+// References to a secret provider have no value and are kept as is.
+func (secretEncryptionService SecretEncryptionService) Encrypt(namespaceID string, secrets domain.ApplicationSecrets) (domain.ApplicationSecrets, error) {
+	encryptedSecrets := domain.ApplicationSecrets{}
+	var dataKey *domain.NamespaceDataKey
+	var plaintextDataKey []byte
+	for _, secret := range secrets {
+		// The value of a reference is stored by its secret provider
+		if secret.IsReference() {
+			encryptedSecrets = append(encryptedSecrets, secret)
+			continue
+		}
+		if secret.IsEncrypted() {
+			// A value copied from another namespace must not be decrypted into this one
+			secretDataKey, _, err := secretEncryptionService.findDataKeyOfValue(secret.Val)
+			if err != nil {
+				return nil, err
+			}
+			if secretDataKey.NamespaceID != namespaceID {
+				return nil, fmt.Errorf("secret %s is encrypted for another namespace", secret.Name)
+			}
+			encryptedSecrets = append(encryptedSecrets, secret)
+			continue
+		}
+
+		if dataKey == nil {
+			var err error
+			dataKey, plaintextDataKey, err = secretEncryptionService.latestDataKey(namespaceID)
+			if err != nil {
+				return nil, err
+			}
+		}
+		ciphertext, err := sealAESGCM(plaintextDataKey, []byte(secret.Val), []byte(dataKey.ID))
+		if err != nil {
+			return nil, fmt.Errorf("error encrypting secret %s: %w", secret.Name, err)
+		}
+		secret.Val = domain.EncryptedSecretValuePrefix + dataKey.ID + ":" + base64.StdEncoding.EncodeToString(ciphertext)
+		encryptedSecrets = append(encryptedSecrets, secret)
+	}
+	return encryptedSecrets, nil
+}
+
+// Decrypt returns the secrets with their plain values, values stored before encryption at rest are returned as is
+func (secretEncryptionService SecretEncryptionService) Decrypt(secrets domain.ApplicationSecrets) (domain.ApplicationSecrets, error) {
+	decryptedSecrets := domain.ApplicationSecrets{}
+	plaintextDataKeys := map[string][]byte{}
+	for _, secret := range secrets {
+		if !secret.IsEncrypted() {
+			decryptedSecrets = append(decryptedSecrets, secret)
+			continue
+		}
+
+		dataKey, ciphertext, err := secretEncryptionService.findDataKeyOfValue(secret.Val)
+		if err != nil {
+			return nil, fmt.Errorf("error decrypting secret %s: %w", secret.Name, err)
+		}
+		plaintextDataKey, ok := plaintextDataKeys[dataKey.ID]
+		if !ok {
+			plaintextDataKey, err = secretEncryptionService.MasterKeyProvider.Decrypt(dataKey.MasterKeyID, dataKey.WrappedKey)
+			if err != nil {
+				return nil, fmt.Errorf("error unwrapping data key %s: %w", dataKey.ID, err)
+			}
+			plaintextDataKeys[dataKey.ID] = plaintextDataKey
+		}
+		plaintext, err := openAESGCM(plaintextDataKey, ciphertext, []byte(dataKey.ID))
+		if err != nil {
+			return nil, fmt.Errorf("error decrypting secret %s: %w", secret.Name, err)
+		}
+		secret.Val = string(plaintext)
+		decryptedSecrets = append(decryptedSecrets, secret)
+	}
+	return decryptedSecrets, nil
+}
+
+// RotateDataKey gives the namespace a new data key wrapped by the current master key
+func (secretEncryptionService SecretEncryptionService) RotateDataKey(namespaceID string) error {
+	_, _, err := secretEncryptionService.createDataKey(namespaceID)
+	return err
+}
+
+// PruneDataKeys deletes the data keys that are not the latest of their namespace or not wrapped by the current master key,
+// unless one of the stored secrets is still encrypted with them
+func (secretEncryptionService SecretEncryptionService) PruneDataKeys(storedSecrets domain.ApplicationSecrets) error {
+	referencedDataKeyIDs := map[string]bool{}
+	for _, secret := range storedSecrets {
+		if secret.IsEncrypted() {
+			referencedDataKeyIDs[dataKeyIDOfValue(secret.Val)] = true
+		}
+	}
+	dataKeys, err := secretEncryptionService.NamespaceDataKeyRepository.FindAll()
+	if err != nil {
+		return err
+	}
+	currentMasterKeyID := secretEncryptionService.MasterKeyProvider.CurrentKeyID()
+	latestDataKeyFound := map[string]bool{}
+	// Data keys are sorted from the most recent
+	for _, dataKey := range dataKeys {
+		if !latestDataKeyFound[dataKey.NamespaceID] && dataKey.MasterKeyID == currentMasterKeyID {
+			latestDataKeyFound[dataKey.NamespaceID] = true
+			continue
+		}
+		latestDataKeyFound[dataKey.NamespaceID] = true
+		if referencedDataKeyIDs[dataKey.ID] {
+			continue
+		}
+		if err := secretEncryptionService.NamespaceDataKeyRepository.Delete(dataKey.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// latestDataKey returns the latest data key of the namespace with its plaintext, creating it when the namespace has none
+func (secretEncryptionService SecretEncryptionService) latestDataKey(namespaceID string) (*domain.NamespaceDataKey, []byte, error) {
+	dataKey, err := secretEncryptionService.NamespaceDataKeyRepository.FindLatestByNamespaceID(namespaceID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if dataKey == nil {
+		return secretEncryptionService.createDataKey(namespaceID)
+	}
+	plaintextDataKey, err := secretEncryptionService.MasterKeyProvider.Decrypt(dataKey.MasterKeyID, dataKey.WrappedKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error unwrapping data key %s: %w", dataKey.ID, err)
+	}
+	return dataKey, plaintextDataKey, nil
+}
+
+func (secretEncryptionService SecretEncryptionService) createDataKey(namespaceID string) (*domain.NamespaceDataKey, []byte, error) {
+	plaintextDataKey := make([]byte, 32)
+	if _, err := rand.Read(plaintextDataKey); err != nil {
+		return nil, nil, fmt.Errorf("error generating data key: %w", err)
+	}
+	masterKeyID := secretEncryptionService.MasterKeyProvider.CurrentKeyID()
+	wrappedKey, err := secretEncryptionService.MasterKeyProvider.Encrypt(masterKeyID, plaintextDataKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error wrapping data key: %w", err)
+	}
+	dataKey, err := secretEncryptionService.NamespaceDataKeyRepository.Create(domain.NamespaceDataKey{
+		NamespaceID: namespaceID,
+		MasterKeyID: masterKeyID,
+		WrappedKey:  wrappedKey,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return dataKey, plaintextDataKey, nil
+}
+
+// dataKeyIDOfValue returns the ID of the data key an encrypted value is encrypted with
+func dataKeyIDOfValue(value string) string {
+	return strings.SplitN(strings.TrimPrefix(value, domain.EncryptedSecretValuePrefix), ":", 2)[0]
+}
+
+// findDataKeyOfValue parses an encrypted value and returns its data key and ciphertext
+func (secretEncryptionService SecretEncryptionService) findDataKeyOfValue(value string) (*domain.NamespaceDataKey, []byte, error) {
+	dataKeyIDAndCiphertext := strings.SplitN(strings.TrimPrefix(value, domain.EncryptedSecretValuePrefix), ":", 2)
+	if len(dataKeyIDAndCiphertext) != 2 {
+		return nil, nil, fmt.Errorf("malformed encrypted secret value")
+	}
+	ciphertext, err := base64.StdEncoding.DecodeString(dataKeyIDAndCiphertext[1])
+	if err != nil {
+		return nil, nil, fmt.Errorf("malformed encrypted secret value: %w", err)
+	}
+	dataKey, err := secretEncryptionService.NamespaceDataKeyRepository.FindByID(dataKeyIDAndCiphertext[0])
+	if err != nil {
+		return nil, nil, err
+	}
+	if dataKey == nil {
+		return nil, nil, fmt.Errorf("data key %s not found", dataKeyIDAndCiphertext[0])
+	}
+	return dataKey, ciphertext, nil
+}
@@ -0,0 +1,171 @@
+package services
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"cloud-app-hive/domain"
+)
+
+// memoryNamespaceDataKeyRepository keeps the data keys in memory, the most recent last
+type memoryNamespaceDataKeyRepository struct {
+	dataKeys []domain.NamespaceDataKey
+}
+
+func (m *memoryNamespaceDataKeyRepository) Create(namespaceDataKey domain.NamespaceDataKey) (*domain.NamespaceDataKey, error) {
+	namespaceDataKey.ID = fmt.Sprintf("data-key-%d", len(m.dataKeys)+1)
+	namespaceDataKey.CreatedAt = time.Now()
+	m.dataKeys = append(m.dataKeys, namespaceDataKey)
+	return &namespaceDataKey, nil
+}
+
+func (m *memoryNamespaceDataKeyRepository) FindByID(id string) (*domain.NamespaceDataKey, error) {
+	for _, dataKey := range m.dataKeys {
+		if dataKey.ID == id {
+			return &dataKey, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *memoryNamespaceDataKeyRepository) FindLatestByNamespaceID(namespaceID string) (*domain.NamespaceDataKey, error) {
+	for i := len(m.dataKeys) - 1; i >= 0; i-- {
+		if m.dataKeys[i].NamespaceID == namespaceID {
+			return &m.dataKeys[i], nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *memoryNamespaceDataKeyRepository) FindAll() ([]domain.NamespaceDataKey, error) {
+	dataKeys := []domain.NamespaceDataKey{}
+	for i := len(m.dataKeys) - 1; i >= 0; i-- {
+		dataKeys = append(dataKeys, m.dataKeys[i])
+	}
+	return dataKeys, nil
+}
+
+func (m *memoryNamespaceDataKeyRepository) Delete(id string) error {
+	for i, dataKey := range m.dataKeys {
+		if dataKey.ID == id {
+			m.dataKeys = append(m.dataKeys[:i], m.dataKeys[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+func writeTestMasterKeys(t *testing.T, keyIDs ...string) string {
+	t.Helper()
+	lines := []string{"# test master keys"}
+	for _, keyID := range keyIDs {
+		key := make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			t.Fatal(err)
+		}
+		lines = append(lines, keyID+"="+base64.StdEncoding.EncodeToString(key))
+	}
+	path := filepath.Join(t.TempDir(), "master-keys")
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func newTestSecretEncryptionService(t *testing.T, masterKeysPath string, currentKeyID string, dataKeyRepository *memoryNamespaceDataKeyRepository) SecretEncryptionService {
+	t.Helper()
+	masterKeyProvider, err := NewFileMasterKeyProvider(masterKeysPath, currentKeyID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return SecretEncryptionService{MasterKeyProvider: masterKeyProvider, NamespaceDataKeyRepository: dataKeyRepository}
+}
+
+func TestSecretEncryptionService_EncryptsAndDecryptsSecrets(t *testing.T) {
+	dataKeyRepository := &memoryNamespaceDataKeyRepository{}
+	secretEncryptionService := newTestSecretEncryptionService(t, writeTestMasterKeys(t, "2024-01"), "", dataKeyRepository)
+
+	secrets := domain.ApplicationSecrets{{Name: "DATABASE_PASSWORD", Val: "p4ssw0rd", Preview: "****", Version: 2}}
+	encryptedSecrets, err := secretEncryptionService.Encrypt("namespace-id", secrets)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !encryptedSecrets[0].IsEncrypted() || strings.Contains(encryptedSecrets[0].Val, "p4ssw0rd") {
+		t.Fatalf("expected an encrypted value, got %s", encryptedSecrets[0].Val)
+	}
+	if len(dataKeyRepository.dataKeys) != 1 || strings.Contains(string(dataKeyRepository.dataKeys[0].WrappedKey), "p4ssw0rd") {
+		t.Errorf("expected a single wrapped data key, got %+v", dataKeyRepository.dataKeys)
+	}
+
+	// Encrypting again keeps the values already encrypted for the namespace
+	reencryptedSecrets, err := secretEncryptionService.Encrypt("namespace-id", encryptedSecrets)
+	if err != nil || reencryptedSecrets[0].Val != encryptedSecrets[0].Val {
+		t.Errorf("expected the encrypted value to be kept, got %v and %v", reencryptedSecrets, err)
+	}
+
+	decryptedSecrets, err := secretEncryptionService.Decrypt(append(encryptedSecrets, domain.ApplicationSecret{Name: "LEGACY", Val: "plain"}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if decryptedSecrets[0].Val != "p4ssw0rd" || decryptedSecrets[1].Val != "plain" {
+		t.Errorf("expected the plain values back, got %+v", decryptedSecrets)
+	}
+	if decryptedSecrets[0].Version != 2 || decryptedSecrets[0].Preview != "****" {
+		t.Errorf("expected the secret metadata to be kept, got %+v", decryptedSecrets[0])
+	}
+}
+
+func TestSecretEncryptionService_RejectsValuesOfAnotherNamespace(t *testing.T) {
+	secretEncryptionService := newTestSecretEncryptionService(t, writeTestMasterKeys(t, "2024-01"), "", &memoryNamespaceDataKeyRepository{})
+
+	encryptedSecrets, err := secretEncryptionService.Encrypt("other-namespace-id", domain.ApplicationSecrets{{Name: "TOKEN", Val: "value"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := secretEncryptionService.Encrypt("namespace-id", encryptedSecrets); err == nil {
+		t.Error("expected a value encrypted for another namespace to be rejected")
+	}
+}
+
+func TestSecretEncryptionService_RotatesMasterKeys(t *testing.T) {
+	dataKeyRepository := &memoryNamespaceDataKeyRepository{}
+	masterKeysPath := writeTestMasterKeys(t, "2024-01", "2024-02")
+	previousService := newTestSecretEncryptionService(t, masterKeysPath, "2024-01", dataKeyRepository)
+	encryptedSecrets, err := previousService.Encrypt("namespace-id", domain.ApplicationSecrets{{Name: "TOKEN", Val: "value"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	currentService := newTestSecretEncryptionService(t, masterKeysPath, "2024-02", dataKeyRepository)
+	if err := currentService.RotateDataKey("namespace-id"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	decryptedSecrets, err := currentService.Decrypt(encryptedSecrets)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	rotatedSecrets, err := currentService.Encrypt("namespace-id", decryptedSecrets)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := currentService.PruneDataKeys(rotatedSecrets); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(dataKeyRepository.dataKeys) != 1 || dataKeyRepository.dataKeys[0].MasterKeyID != "2024-02" {
+		t.Fatalf("expected only the data key wrapped by the current master key, got %+v", dataKeyRepository.dataKeys)
+	}
+	if _, err := currentService.Decrypt(encryptedSecrets); err == nil {
+		t.Error("expected the values encrypted with the pruned data key not to be decryptable")
+	}
+	finalSecrets, err := currentService.Decrypt(rotatedSecrets)
+	if err != nil || finalSecrets[0].Val != "value" {
+		t.Errorf("expected the rotated value to be decrypted, got %+v and %v", finalSecrets, err)
+	}
+}
@@ -0,0 +1,108 @@
+package services
+
+import (
+	"bufio"
+	"cloud-app-hive/domain"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// SecretProvider reads secrets stored outside of the database
+type SecretProvider interface {
+	// GetSecret returns the value of key in the secret at path, or the whole secret when key is empty
+	GetSecret(path string, key string) (string, error)
+}
+
+// SecretResolverService resolves secret references with the provider named by their scheme
+type SecretResolverService struct {
+	Providers map[string]SecretProvider
+}
+
+// NewSecretResolverService returns the providers configured by the environment:
+// "file" when SECRET_PROVIDER_FILE_ROOT is set and "vault" when VAULT_ADDR is set
+func NewSecretResolverService() SecretResolverService {
+	providers := map[string]SecretProvider{}
+	if fileRoot := os.Getenv("SECRET_PROVIDER_FILE_ROOT"); fileRoot != "" {
+		providers["file"] = FileSecretProvider{Root: fileRoot}
+	}
+	if vaultAddress := os.Getenv("VAULT_ADDR"); vaultAddress != "" {
+		providers["vault"] = NewVaultSecretProvider(vaultAddress, os.Getenv("VAULT_TOKEN"), os.Getenv("VAULT_KV_MOUNT"))
+	}
+	return SecretResolverService{Providers: providers}
+}
+
+// Resolve returns the secrets with the values of their references, secrets without reference are returned as is.
+// Reference paths are relative to the directory of the namespace in the provider, so that a namespace cannot read the secrets of another.
+// The directory is named by the ID of the namespace, a namespace created with the name of a deleted one does not read its secrets.
+func (secretResolverService SecretResolverService) Resolve(namespaceID string, secrets domain.ApplicationSecrets) (domain.ApplicationSecrets, error) {
+	if namespaceID == "" {
+		return nil, fmt.Errorf("a namespace is required to resolve secret references")
+	}
+	resolvedSecrets := domain.ApplicationSecrets{}
+	for _, secret := range secrets {
+		if !secret.IsReference() {
+			resolvedSecrets = append(resolvedSecrets, secret)
+			continue
+		}
+
+		reference, err := domain.ParseSecretReference(secret.Reference)
+		if err != nil {
+			return nil, err
+		}
+		provider, ok := secretResolverService.Providers[reference.Provider]
+		if !ok {
+			return nil, fmt.Errorf("secret %s references the provider %s, which is not configured", secret.Name, reference.Provider)
+		}
+		value, err := provider.GetSecret(namespaceSecretPath(namespaceID, reference.Path), reference.Key)
+		if err != nil {
+			return nil, fmt.Errorf("error resolving secret %s from %s: %w", secret.Name, reference, err)
+		}
+		secret.Val = value
+		resolvedSecrets = append(resolvedSecrets, secret)
+	}
+	return resolvedSecrets, nil
+}
+
+// namespaceSecretPath returns the path of a referenced secret in the directory of the namespace,
+// cleaning the path from the root first keeps it inside that directory
+func namespaceSecretPath(namespaceID string, secretPath string) string {
+	return path.Join(namespaceID, path.Clean("/"+secretPath))
+}
+
+// FileSecretProvider reads secrets from the files of a directory, typically mounted from a secret store.
+// A file is either the value itself or "<key>=<value>" lines, empty lines and lines starting with # being ignored.
+type FileSecretProvider struct {
+	Root string
+}
+
+// GetSecret returns the content of the file at path under the root directory, or the value of key in it
+func (fileSecretProvider FileSecretProvider) GetSecret(path string, key string) (string, error) {
+	// Cleaning the path from the filesystem root keeps it inside the root directory
+	filePath := filepath.Join(fileSecretProvider.Root, filepath.Clean("/"+path))
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return "", fmt.Errorf("error reading secret file %s: %w", path, err)
+	}
+	if key == "" {
+		return strings.TrimRight(string(content), "\r\n"), nil
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(string(content)))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		lineKey, value, found := strings.Cut(line, "=")
+		if found && strings.TrimSpace(lineKey) == key {
+			return strings.TrimSpace(value), nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", fmt.Errorf("error reading secret file %s: %w", path, err)
+	}
+	return "", fmt.Errorf("key %s not found in secret file %s", key, path)
+}
@@ -0,0 +1,133 @@
+package services
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"cloud-app-hive/domain"
+)
+
+func TestFileSecretProvider_ReadsValuesAndKeys(t *testing.T) {
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "token"), []byte("s3cr3t\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "database.env"), []byte("# database\nUSER=api\nPASSWORD = p4ssw0rd\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	fileSecretProvider := FileSecretProvider{Root: root}
+
+	if value, err := fileSecretProvider.GetSecret("token", ""); err != nil || value != "s3cr3t" {
+		t.Errorf("expected the content of the file, got %q and %v", value, err)
+	}
+	if value, err := fileSecretProvider.GetSecret("database.env", "PASSWORD"); err != nil || value != "p4ssw0rd" {
+		t.Errorf("expected the value of the key, got %q and %v", value, err)
+	}
+	if _, err := fileSecretProvider.GetSecret("database.env", "UNKNOWN"); err == nil {
+		t.Error("expected an error for an unknown key")
+	}
+
+	// Paths cannot leave the root directory
+	outside := filepath.Join(filepath.Dir(root), "outside")
+	if err := os.WriteFile(outside, []byte("outside"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(outside)
+	if value, err := fileSecretProvider.GetSecret("../outside", ""); err == nil {
+		t.Errorf("expected the file outside of the root to be unreachable, got %q", value)
+	}
+}
+
+func TestVaultSecretProvider_ReadsKVv2Secrets(t *testing.T) {
+	vault := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "dev-token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		if r.URL.Path != "/v1/kv/data/team/api" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`{"data":{"data":{"password":"p4ssw0rd","port":5432},"metadata":{"version":3}}}`))
+	}))
+	defer vault.Close()
+
+	vaultSecretProvider := NewVaultSecretProvider(vault.URL+"/", "dev-token", "/kv/")
+	if value, err := vaultSecretProvider.GetSecret("team/api", "password"); err != nil || value != "p4ssw0rd" {
+		t.Errorf("expected the value of the key, got %q and %v", value, err)
+	}
+	if value, err := vaultSecretProvider.GetSecret("team/api", "port"); err != nil || value != "5432" {
+		t.Errorf("expected a non string value as JSON, got %q and %v", value, err)
+	}
+	if _, err := vaultSecretProvider.GetSecret("team/unknown", "password"); err == nil || !strings.Contains(err.Error(), "404") {
+		t.Errorf("expected a not found error, got %v", err)
+	}
+	if _, err := NewVaultSecretProvider(vault.URL, "wrong-token", "kv").GetSecret("team/api", "password"); err == nil {
+		t.Error("expected an error with a wrong token")
+	}
+}
+
+func TestSecretResolverService_ResolvesReferences(t *testing.T) {
+	root := t.TempDir()
+	if err := os.Mkdir(filepath.Join(root, "team-namespace-id"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "team-namespace-id", "token"), []byte("from-file"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	secretResolverService := SecretResolverService{Providers: map[string]SecretProvider{"file": FileSecretProvider{Root: root}}}
+
+	secrets, err := secretResolverService.Resolve("team-namespace-id", domain.ApplicationSecrets{
+		{Name: "TOKEN", Reference: "file://token", Version: 2},
+		{Name: "PLAIN", Val: "plain"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if secrets[0].Val != "from-file" || secrets[0].Version != 2 || secrets[1].Val != "plain" {
+		t.Errorf("unexpected resolved secrets %+v", secrets)
+	}
+
+	if _, err := secretResolverService.Resolve("team-namespace-id", domain.ApplicationSecrets{{Name: "TOKEN", Reference: "vault://team#token"}}); err == nil {
+		t.Error("expected an error for a provider that is not configured")
+	}
+}
+
+func TestSecretResolverService_RejectsReferencesOutsideOfTheNamespace(t *testing.T) {
+	root := t.TempDir()
+	for _, namespace := range []string{"team-namespace-id", "other-team-namespace-id"} {
+		if err := os.Mkdir(filepath.Join(root, namespace), 0700); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(root, namespace, "token"), []byte(namespace+"-token"), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var vaultPaths []string
+	vault := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		vaultPaths = append(vaultPaths, r.URL.Path)
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer vault.Close()
+	secretResolverService := SecretResolverService{Providers: map[string]SecretProvider{
+		"file":  FileSecretProvider{Root: root},
+		"vault": NewVaultSecretProvider(vault.URL, "dev-token", "kv"),
+	}}
+
+	for _, reference := range []string{"file://../other-team-namespace-id/token", "file:///other-team-namespace-id/token", "file://../../other-team-namespace-id/token"} {
+		if secrets, err := secretResolverService.Resolve("team-namespace-id", domain.ApplicationSecrets{{Name: "TOKEN", Reference: reference}}); err == nil {
+			t.Errorf("expected %s to be unreachable from namespace team-namespace-id, got %+v", reference, secrets)
+		}
+	}
+	_, _ = secretResolverService.Resolve("team-namespace-id", domain.ApplicationSecrets{{Name: "TOKEN", Reference: "vault://../other-team-namespace-id/api#password"}})
+	if len(vaultPaths) != 1 || vaultPaths[0] != "/v1/kv/data/team-namespace-id/other-team-namespace-id/api" {
+		t.Errorf("expected the Vault secret to be read under the namespace, got %v", vaultPaths)
+	}
+	if _, err := secretResolverService.Resolve("", domain.ApplicationSecrets{{Name: "TOKEN", Reference: "file://team/token"}}); err == nil {
+		t.Error("expected an error without namespace")
+	}
+}
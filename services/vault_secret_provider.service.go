@@ -0,0 +1,78 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// VaultSecretProvider reads secrets from a Vault KV version 2 secrets engine through its HTTP API
+type VaultSecretProvider struct {
+	Address string
+	Token   string
+	// Mount is the path where the KV secrets engine is enabled, "secret" by default
+	Mount      string
+	HTTPClient *http.Client
+}
+
+func NewVaultSecretProvider(address string, token string, mount string) VaultSecretProvider {
+	if mount == "" {
+		mount = "secret"
+	}
+	return VaultSecretProvider{
+		Address:    strings.TrimRight(address, "/"),
+		Token:      token,
+		Mount:      strings.Trim(mount, "/"),
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// vaultKVResponse is the body returned when reading a KV version 2 secret
+type vaultKVResponse struct {
+	Data struct {
+		Data map[string]interface{} `json:"data"`
+	} `json:"data"`
+}
+
+// GetSecret returns the value of key in the latest version of the secret at path
+func (vaultSecretProvider VaultSecretProvider) GetSecret(path string, key string) (string, error) {
+	if key == "" {
+		return "", fmt.Errorf("a key is required to read the Vault secret %s", path)
+	}
+
+	request, err := http.NewRequest(http.MethodGet, vaultSecretProvider.Address+"/v1/"+vaultSecretProvider.Mount+"/data/"+(&url.URL{Path: path}).EscapedPath(), nil)
+	if err != nil {
+		return "", fmt.Errorf("error building Vault request: %w", err)
+	}
+	request.Header.Set("X-Vault-Token", vaultSecretProvider.Token)
+
+	response, err := vaultSecretProvider.HTTPClient.Do(request)
+	if err != nil {
+		return "", fmt.Errorf("error reading Vault secret %s: %w", path, err)
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("error reading Vault secret %s: unexpected status %d", path, response.StatusCode)
+	}
+
+	var kvResponse vaultKVResponse
+	if err := json.NewDecoder(response.Body).Decode(&kvResponse); err != nil {
+		return "", fmt.Errorf("error decoding Vault secret %s: %w", path, err)
+	}
+	value, ok := kvResponse.Data.Data[key]
+	if !ok {
+		return "", fmt.Errorf("key %s not found in Vault secret %s", key, path)
+	}
+	if stringValue, ok := value.(string); ok {
+		return stringValue, nil
+	}
+	// Non string values are given to the application as JSON
+	jsonValue, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	return string(jsonValue), nil
+}
@@ -0,0 +1,139 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// defaultDeniedWebhookNetworks are the networks of the API host and of the cluster, events are never sent to them
+var defaultDeniedWebhookNetworks = []string{
+	"0.0.0.0/8",      // This network
+	"10.0.0.0/8",     // Private, pods and services of most clusters
+	"100.64.0.0/10",  // Carrier-grade NAT, pods of some clusters
+	"127.0.0.0/8",    // Loopback
+	"169.254.0.0/16", // Link-local, cloud metadata endpoints
+	"172.16.0.0/12",  // Private
+	"192.0.0.0/24",   // IETF protocol assignments
+	"192.168.0.0/16", // Private
+	"198.18.0.0/15",  // Benchmarking
+	"224.0.0.0/4",    // Multicast
+	"240.0.0.0/4",    // Reserved and broadcast
+	"::/128",         // Unspecified
+	"::1/128",        // Loopback
+	"64:ff9b::/96",   // IPv4 translation
+	"fc00::/7",       // Unique local
+	"fe80::/10",      // Link-local
+	"ff00::/8",       // Multicast
+}
+
+// HTTPWebhookSenderService posts the events to the webhooks
+type HTTPWebhookSenderService struct {
+	HTTPClient *http.Client
+}
+
+// NewHTTPWebhookSenderService returns a sender that refuses to connect to the internal networks,
+// the default ones and the comma separated CIDRs of WEBHOOK_DENIED_NETWORKS, such as the pod and service ranges of the cluster
+func NewHTTPWebhookSenderService() HTTPWebhookSenderService {
+	deniedNetworks := append([]string{}, defaultDeniedWebhookNetworks...)
+	for _, deniedNetwork := range strings.Split(os.Getenv("WEBHOOK_DENIED_NETWORKS"), ",") {
+		if deniedNetwork = strings.TrimSpace(deniedNetwork); deniedNetwork != "" {
+			deniedNetworks = append(deniedNetworks, deniedNetwork)
+		}
+	}
+	webhookSender, err := NewHTTPWebhookSenderServiceDenyingNetworks(deniedNetworks)
+	if err != nil {
+		panic(err)
+	}
+	return webhookSender
+}
+
+// NewHTTPWebhookSenderServiceDenyingNetworks returns a sender that refuses to connect to the addresses of the networks.
+// The address is checked once the host is resolved, so a host cannot resolve to a public address when it is validated and to an internal one when it is dialed.
+func NewHTTPWebhookSenderServiceDenyingNetworks(deniedNetworks []string) (HTTPWebhookSenderService, error) {
+	parsedDeniedNetworks, err := parseWebhookNetworks(deniedNetworks)
+	if err != nil {
+		return HTTPWebhookSenderService{}, err
+	}
+
+	dialer := &net.Dialer{
+		Timeout: 5 * time.Second,
+		Control: func(network string, address string, _ syscall.RawConn) error {
+			return checkWebhookAddress(parsedDeniedNetworks, address)
+		},
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	// A proxy would connect to the endpoint in place of the dialer checking its address
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return HTTPWebhookSenderService{
+		HTTPClient: &http.Client{
+			// Endpoints are expected to acknowledge the events quickly and process them afterwards
+			Timeout:   10 * time.Second,
+			Transport: transport,
+			// A redirect answers the attempt, following it would send the event to a URL that was not validated
+			CheckRedirect: func(request *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}, nil
+}
+
+func parseWebhookNetworks(networks []string) ([]*net.IPNet, error) {
+	parsedNetworks := make([]*net.IPNet, 0, len(networks))
+	for _, network := range networks {
+		_, parsedNetwork, err := net.ParseCIDR(network)
+		if err != nil {
+			return nil, fmt.Errorf("invalid denied webhook network %s: %w", network, err)
+		}
+		parsedNetworks = append(parsedNetworks, parsedNetwork)
+	}
+	return parsedNetworks, nil
+}
+
+// checkWebhookAddress returns an error if the resolved address belongs to one of the denied networks
+func checkWebhookAddress(deniedNetworks []*net.IPNet, address string) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return fmt.Errorf("webhook address %s is not an IP address", host)
+	}
+	if ipv4 := ip.To4(); ipv4 != nil {
+		ip = ipv4
+	}
+	for _, deniedNetwork := range deniedNetworks {
+		if deniedNetwork.Contains(ip) {
+			return fmt.Errorf("webhook address %s belongs to the internal network %s", ip, deniedNetwork)
+		}
+	}
+	return nil
+}
+
+// Send posts the payload to the URL with the headers and returns the HTTP status answered by the endpoint
+func (webhookSender HTTPWebhookSenderService) Send(url string, headers map[string]string, payload []byte) (int, error) {
+	request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return 0, err
+	}
+	for name, value := range headers {
+		request.Header.Set(name, value)
+	}
+	response, err := webhookSender.HTTPClient.Do(request)
+	if err != nil {
+		return 0, err
+	}
+	defer response.Body.Close()
+	// The body is read so that the connection can be reused, its content is not used
+	_, _ = io.Copy(io.Discard, io.LimitReader(response.Body, 64<<10))
+	return response.StatusCode, nil
+}
@@ -0,0 +1,64 @@
+package services
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHTTPWebhookSenderService_RejectsInternalAddresses(t *testing.T) {
+	requests := 0
+	endpoint := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+	}))
+	defer endpoint.Close()
+
+	if _, err := NewHTTPWebhookSenderService().Send(endpoint.URL, nil, []byte("{}")); err == nil {
+		t.Error("expected an event to a loopback address to be rejected")
+	}
+	if requests != 0 {
+		t.Errorf("expected the endpoint not to be called, got %d requests", requests)
+	}
+
+	deniedNetworks, err := parseWebhookNetworks(defaultDeniedWebhookNetworks)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for address, denied := range map[string]bool{
+		"127.0.0.1:443":        true,
+		"169.254.169.254:80":   true,
+		"10.96.0.1:443":        true,
+		"[::ffff:10.0.0.1]:80": true,
+		"[fd00::1]:443":        true,
+		"93.184.216.34:443":    false,
+		"[2606:4700::1]:443":   false,
+	} {
+		if err := checkWebhookAddress(deniedNetworks, address); (err != nil) != denied {
+			t.Errorf("expected address %s to be denied: %t, got %v", address, denied, err)
+		}
+	}
+}
+
+func TestHTTPWebhookSenderService_DoesNotFollowRedirects(t *testing.T) {
+	redirected := false
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		redirected = true
+	}))
+	defer target.Close()
+	endpoint := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, target.URL, http.StatusTemporaryRedirect)
+	}))
+	defer endpoint.Close()
+	webhookSender, err := NewHTTPWebhookSenderServiceDenyingNetworks(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	statusCode, err := webhookSender.Send(endpoint.URL, nil, []byte("{}"))
+	if err != nil || statusCode != http.StatusTemporaryRedirect {
+		t.Errorf("expected the redirect to answer the attempt, got %d, %v", statusCode, err)
+	}
+	if redirected {
+		t.Error("expected the redirect not to be followed")
+	}
+}
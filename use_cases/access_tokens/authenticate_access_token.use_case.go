@@ -0,0 +1,34 @@
+package access_tokens
+
+import (
+	"fmt"
+	"time"
+
+	"cloud-app-hive/domain"
+	"cloud-app-hive/domain/repositories"
+)
+
+type AuthenticateAccessTokenUseCase struct {
+	AccessTokenRepository repositories.AccessTokenRepository
+}
+
+// Execute returns the active access token of a secret and records its use
+func (authenticateAccessTokenUseCase AuthenticateAccessTokenUseCase) Execute(secret string) (*domain.AccessToken, error) {
+	accessToken, err := authenticateAccessTokenUseCase.AccessTokenRepository.FindBySecretHash(domain.HashAccessTokenSecret(secret))
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	if accessToken == nil || !accessToken.IsActive(now) {
+		return nil, fmt.Errorf("access token is unknown, revoked or expired")
+	}
+
+	if accessToken.ShouldRecordUse(now) {
+		// Failing to record the use must not reject a valid token
+		if err := authenticateAccessTokenUseCase.AccessTokenRepository.UpdateLastUsedAt(accessToken.ID, now); err != nil {
+			fmt.Println(err)
+		}
+		accessToken.LastUsedAt = &now
+	}
+	return accessToken, nil
+}
@@ -0,0 +1,58 @@
+package access_tokens
+
+import (
+	"cloud-app-hive/controllers/errors"
+	"fmt"
+	"time"
+
+	"cloud-app-hive/domain"
+	"cloud-app-hive/domain/commands"
+	"cloud-app-hive/domain/repositories"
+)
+
+type CreateAccessTokenUseCase struct {
+	AccessTokenRepository repositories.AccessTokenRepository
+	NamespaceRepository   repositories.NamespaceRepository
+}
+
+// Execute mints an access token and returns it with its secret, which cannot be retrieved afterwards
+func (createAccessTokenUseCase CreateAccessTokenUseCase) Execute(createAccessToken commands.CreateAccessToken) (*domain.AccessToken, string, error) {
+	if len(createAccessToken.Scopes) == 0 {
+		return nil, "", errors.NewInvalidAccessTokenError("at least one scope is required")
+	}
+	for _, scope := range createAccessToken.Scopes {
+		if !domain.IsAValidAccessTokenScope(scope) {
+			return nil, "", errors.NewInvalidAccessTokenError(fmt.Sprintf("unknown scope %s", scope))
+		}
+	}
+	if createAccessToken.ExpiresAt != nil && !createAccessToken.ExpiresAt.After(time.Now()) {
+		return nil, "", errors.NewInvalidAccessTokenError("expiry must be in the future")
+	}
+
+	// A token restricted to a namespace must not open a namespace its owner cannot read
+	if createAccessToken.NamespaceID != nil {
+		namespace, err := createAccessTokenUseCase.NamespaceRepository.FindByID(*createAccessToken.NamespaceID)
+		if err != nil {
+			return nil, "", err
+		}
+		if namespace == nil {
+			return nil, "", errors.NewNamespaceNotFoundByIDError(*createAccessToken.NamespaceID)
+		}
+		if err := namespace.Authorize(createAccessToken.UserID, domain.PermissionReadNamespace); err != nil {
+			return nil, "", err
+		}
+	}
+
+	secret, err := domain.NewAccessTokenSecret()
+	if err != nil {
+		return nil, "", err
+	}
+	createAccessToken.SecretHash = domain.HashAccessTokenSecret(secret)
+	createAccessToken.SecretStart = secret[:len(domain.AccessTokenSecretPrefix)+6]
+
+	accessToken, err := createAccessTokenUseCase.AccessTokenRepository.Create(createAccessToken)
+	if err != nil {
+		return nil, "", err
+	}
+	return accessToken, secret, nil
+}
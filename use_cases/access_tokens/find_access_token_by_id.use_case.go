@@ -0,0 +1,24 @@
+package access_tokens
+
+import (
+	"cloud-app-hive/controllers/errors"
+
+	"cloud-app-hive/domain"
+	"cloud-app-hive/domain/repositories"
+)
+
+type FindAccessTokenByIDUseCase struct {
+	AccessTokenRepository repositories.AccessTokenRepository
+}
+
+func (findAccessTokenByIDUseCase FindAccessTokenByIDUseCase) Execute(id string, userID string) (*domain.AccessToken, error) {
+	accessToken, err := findAccessTokenByIDUseCase.AccessTokenRepository.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	// Tokens of other users are reported as missing, so that their IDs cannot be probed
+	if accessToken == nil || accessToken.UserID != userID {
+		return nil, errors.NewAccessTokenNotFoundByIDError(id)
+	}
+	return accessToken, nil
+}
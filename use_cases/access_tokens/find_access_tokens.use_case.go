@@ -0,0 +1,14 @@
+package access_tokens
+
+import (
+	"cloud-app-hive/domain"
+	"cloud-app-hive/domain/repositories"
+)
+
+type FindAccessTokensUseCase struct {
+	AccessTokenRepository repositories.AccessTokenRepository
+}
+
+func (findAccessTokensUseCase FindAccessTokensUseCase) Execute(userID string) ([]domain.AccessToken, error) {
+	return findAccessTokensUseCase.AccessTokenRepository.FindByUserID(userID)
+}
@@ -0,0 +1,23 @@
+package access_tokens
+
+import (
+	"time"
+
+	"cloud-app-hive/domain"
+	"cloud-app-hive/domain/repositories"
+)
+
+type RevokeAccessTokenUseCase struct {
+	AccessTokenRepository repositories.AccessTokenRepository
+}
+
+func (revokeAccessTokenUseCase RevokeAccessTokenUseCase) Execute(id string, userID string) (*domain.AccessToken, error) {
+	accessToken, err := FindAccessTokenByIDUseCase{AccessTokenRepository: revokeAccessTokenUseCase.AccessTokenRepository}.Execute(id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if accessToken.RevokedAt != nil {
+		return accessToken, nil
+	}
+	return revokeAccessTokenUseCase.AccessTokenRepository.Revoke(id, time.Now().UTC())
+}
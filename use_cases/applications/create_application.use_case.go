@@ -2,18 +2,39 @@ package applications
 
 import (
 	"fmt"
+	"time"
 
+	customErrors "cloud-app-hive/controllers/errors"
 	"cloud-app-hive/domain"
 	"cloud-app-hive/domain/commands"
 	"cloud-app-hive/domain/repositories"
+	"cloud-app-hive/use_cases"
 )
 
 type CreateApplicationUseCase struct {
-	NamespaceRepository   repositories.NamespaceRepository
-	ApplicationRepository repositories.ApplicationRepository
+	NamespaceRepository           repositories.NamespaceRepository
+	ApplicationRepository         repositories.ApplicationRepository
+	RegistryCredentialRepository  repositories.RegistryCredentialRepository
+	VerifyApplicationImageUseCase use_cases.VerifyApplicationImageUseCase
+	ScanApplicationImageUseCase   use_cases.ScanApplicationImageUseCase
+	RecordAuditEventUseCase       use_cases.RecordAuditEventUseCase
 }
 
-func (createApplicationUseCase CreateApplicationUseCase) Execute(createApplication commands.CreateApplication) (*domain.Application, *domain.Namespace, error) {
+func (createApplicationUseCase CreateApplicationUseCase) Execute(createApplication commands.CreateApplication) (createdApplication *domain.Application, namespace *domain.Namespace, err error) {
+	auditEvent := domain.NewAuditEvent(createApplication.Actor, domain.AuditApplicationCreate, domain.AuditTargetApplication, "")
+	auditEvent.NamespaceID = createApplication.NamespaceID
+	auditEvent.TargetName = createApplication.Name
+	defer func() {
+		if createApplication.DryRun {
+			return
+		}
+		if createdApplication != nil {
+			auditEvent.TargetID = createdApplication.ID
+			auditEvent.Changes = domain.NewAuditChanges(nil, domain.NewApplicationManifest(*createdApplication))
+		}
+		createApplicationUseCase.RecordAuditEventUseCase.Execute(auditEvent, err)
+	}()
+
 	foundNamespaceByID, err := createApplicationUseCase.NamespaceRepository.FindByID(createApplication.NamespaceID)
 	if err != nil {
 		return nil, nil, fmt.Errorf("error while finding namespace by id: %w", err)
@@ -21,15 +42,8 @@ func (createApplicationUseCase CreateApplicationUseCase) Execute(createApplicati
 	if foundNamespaceByID == nil {
 		return nil, nil, fmt.Errorf("no namespace found for namespace id %s", createApplication.NamespaceID)
 	}
-	isMember := false
-	for _, member := range foundNamespaceByID.Memberships {
-		if member.UserID == createApplication.UserID {
-			isMember = true
-			break
-		}
-	}
-	if !isMember {
-		return nil, nil, fmt.Errorf("user %s is not member of namespace %s, he cannot create application", createApplication.UserID, createApplication.NamespaceID)
+	if err := foundNamespaceByID.Authorize(createApplication.UserID, domain.PermissionCreateApplication); err != nil {
+		return nil, nil, err
 	}
 
 	foundApplicationsByNamespace, err := createApplicationUseCase.ApplicationRepository.FindByNamespaceIDAndUserID(createApplication.NamespaceID)
@@ -46,6 +60,9 @@ func (createApplicationUseCase CreateApplicationUseCase) Execute(createApplicati
 			}
 		}
 	}
+	if err := createApplication.Ingress.ValidateRoutesAgainst(createApplication.Name, foundApplicationsByNamespace); err != nil {
+		return nil, nil, err
+	}
 
 	foundApplicationsByUser, err := createApplicationUseCase.ApplicationRepository.FindByUserID(createApplication.UserID)
 	if err != nil {
@@ -55,11 +72,79 @@ func (createApplicationUseCase CreateApplicationUseCase) Execute(createApplicati
 		return nil, nil, fmt.Errorf("maximum number of applications reached for user %s", createApplication.UserID)
 	}
 
+	registryCredential, err := findApplicationRegistryCredential(createApplicationUseCase.RegistryCredentialRepository, foundNamespaceByID.ID, createApplication.Registry, createApplication.RegistryCredentialID)
+	if err != nil {
+		return nil, nil, err
+	}
+	// The scanned digest is deployed rather than the tag, which could point to another image when the pods pull it
+	if foundNamespaceByID.RequiresPinnedImages() {
+		createApplication.PinImageDigest = true
+	}
+	createApplication.ImageDigest, err = createApplicationUseCase.VerifyApplicationImageUseCase.Execute(createApplication.Registry, createApplication.Image, registryCredential, createApplication.PinImageDigest)
+	if err != nil {
+		return nil, nil, err
+	}
+	// A dry run only reads the registry and the stored scans
+	if createApplication.DryRun {
+		_, err = createApplicationUseCase.ScanApplicationImageUseCase.DryRun(*foundNamespaceByID, createApplication.Image, createApplication.ImageDigest)
+	} else {
+		_, err = createApplicationUseCase.ScanApplicationImageUseCase.Execute(*foundNamespaceByID, createApplication.Registry, createApplication.Image, createApplication.ImageDigest, registryCredential)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	createApplication.Secrets, err = createApplication.Secrets.Merge(nil, time.Now())
+	if err != nil {
+		return nil, nil, err
+	}
+	createApplication.Ingress, err = createApplication.Ingress.Merge(nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	createApplication.ConfigFiles, err = createApplication.ConfigFiles.Merge(nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	createApplication.Dependencies = createApplication.Dependencies.Resolve(foundApplicationsByNamespace)
+
+	// A dry run goes through every check but does not store the application
+	if createApplication.DryRun {
+		application := createApplication.Application()
+		application.Namespace = *foundNamespaceByID
+		application.RegistryCredential = registryCredential
+		return &application, foundNamespaceByID, nil
+	}
+
 	// Create the application
-	createdApplication, err := createApplicationUseCase.ApplicationRepository.Create(createApplication)
+	createdApplication, err = createApplicationUseCase.ApplicationRepository.Create(createApplication)
 	if err != nil {
 		return nil, nil, err
 	}
 
 	return createdApplication, foundNamespaceByID, nil
 }
+
+// findApplicationRegistryCredential returns the registry credential an application pulls its image with,
+// or an error if the registry does not match the credential or if the credential is not one of the namespace
+func findApplicationRegistryCredential(
+	registryCredentialRepository repositories.RegistryCredentialRepository,
+	namespaceID string,
+	registry domain.ImageRegistry,
+	registryCredentialID *string,
+) (*domain.RegistryCredential, error) {
+	if err := domain.ValidateImageRegistry(registry, registryCredentialID); err != nil {
+		return nil, customErrors.NewInvalidRegistryCredentialError(err.Error())
+	}
+	if registryCredentialID == nil {
+		return nil, nil
+	}
+	registryCredential, err := registryCredentialRepository.FindByID(*registryCredentialID)
+	if err != nil {
+		return nil, fmt.Errorf("error while finding registry credential by id: %w", err)
+	}
+	if registryCredential == nil || registryCredential.NamespaceID != namespaceID {
+		return nil, customErrors.NewRegistryCredentialNotFoundError(*registryCredentialID)
+	}
+	return registryCredential, nil
+}
@@ -7,31 +7,37 @@ import (
 	"cloud-app-hive/domain"
 	"cloud-app-hive/domain/commands"
 	"cloud-app-hive/domain/repositories"
+	"cloud-app-hive/use_cases"
 )
 
 type DeleteApplicationUseCase struct {
-	ApplicationRepository repositories.ApplicationRepository
+	ApplicationRepository   repositories.ApplicationRepository
+	RecordAuditEventUseCase use_cases.RecordAuditEventUseCase
 }
 
-func (deleteApplicationUseCase DeleteApplicationUseCase) Execute(deleteApplication commands.DeleteApplication) (*domain.Application, error) {
+func (deleteApplicationUseCase DeleteApplicationUseCase) Execute(deleteApplication commands.DeleteApplication) (deletedApplication *domain.Application, err error) {
+	auditEvent := domain.NewAuditEvent(deleteApplication.Actor, domain.AuditApplicationDelete, domain.AuditTargetApplication, deleteApplication.ID)
+	defer func() {
+		deleteApplicationUseCase.RecordAuditEventUseCase.Execute(auditEvent, err)
+	}()
+
 	application, err := deleteApplicationUseCase.ApplicationRepository.FindByID(deleteApplication.ID)
 	if err != nil {
 		return nil, fmt.Errorf("error getting application by ID: %w", err)
 	}
 
-	isAdmin := false
-	for _, membership := range application.Namespace.Memberships {
-		if membership.UserID == deleteApplication.UserID && membership.Role == domain.RoleAdmin {
-			isAdmin = true
-			break
-		}
+	if application == nil {
+		return nil, errors.NewApplicationNotFoundByIDError(deleteApplication.ID)
 	}
-	isAppOwner := application.UserID == deleteApplication.UserID
-	if !isAdmin && !isAppOwner {
-		return nil, errors.NewUnauthorizedToAccessNamespaceError(application.Namespace.ID, application.Namespace.Name, deleteApplication.UserID)
+	auditEvent.NamespaceID = application.NamespaceID
+	auditEvent.TargetName = application.Name
+	auditEvent.Changes = domain.NewAuditChanges(domain.NewApplicationManifest(*application), nil)
+
+	if err := application.Authorize(deleteApplication.UserID, domain.PermissionDeleteApplication); err != nil {
+		return nil, err
 	}
 
-	deletedApplication, err := deleteApplicationUseCase.ApplicationRepository.Delete(deleteApplication.ID)
+	deletedApplication, err = deleteApplicationUseCase.ApplicationRepository.Delete(deleteApplication.ID)
 	if err != nil {
 		return nil, fmt.Errorf("error when deleting application: %w", err)
 	}
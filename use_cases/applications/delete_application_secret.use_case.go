@@ -0,0 +1,51 @@
+package applications
+
+import (
+	customErrors "cloud-app-hive/controllers/errors"
+
+	"cloud-app-hive/domain"
+	"cloud-app-hive/domain/commands"
+	"cloud-app-hive/domain/repositories"
+	"cloud-app-hive/use_cases"
+)
+
+type DeleteApplicationSecretUseCase struct {
+	ApplicationRepository      repositories.ApplicationRepository
+	ContainerManagerRepository repositories.ContainerManagerRepository
+	RecordAuditEventUseCase    use_cases.RecordAuditEventUseCase
+}
+
+// Execute removes the secret from the application, then applies the application so that its Kubernetes Secret is updated
+func (deleteApplicationSecretUseCase DeleteApplicationSecretUseCase) Execute(deleteApplicationSecret commands.DeleteApplicationSecret) (deletedSecret *domain.ApplicationSecret, err error) {
+	auditEvent := domain.NewAuditEvent(deleteApplicationSecret.Actor, domain.AuditApplicationSecretDelete, domain.AuditTargetApplication, deleteApplicationSecret.ApplicationID)
+	defer func() {
+		if deletedSecret != nil {
+			auditEvent.Changes = domain.NewAuditChanges(map[string]interface{}{"secret": deletedSecret}, map[string]interface{}{"secret": nil})
+		}
+		deleteApplicationSecretUseCase.RecordAuditEventUseCase.Execute(auditEvent, err)
+	}()
+
+	application, err := findApplicationToUpdateSecrets(deleteApplicationSecretUseCase.ApplicationRepository, deleteApplicationSecret.ApplicationID, deleteApplicationSecret.UserID)
+	if err != nil {
+		return nil, err
+	}
+	auditEvent.NamespaceID = application.NamespaceID
+	auditEvent.TargetName = application.Name
+
+	currentSecrets := domain.ApplicationSecrets{}
+	if application.Secrets != nil {
+		currentSecrets = *application.Secrets
+	}
+	secret, exists := currentSecrets.Find(deleteApplicationSecret.Name)
+	if !exists {
+		return nil, customErrors.NewApplicationSecretNotFoundError(application.ID, deleteApplicationSecret.Name)
+	}
+	secrets := currentSecrets.WithoutSecret(deleteApplicationSecret.Name)
+
+	err = applyApplicationSecrets(deleteApplicationSecretUseCase.ApplicationRepository, deleteApplicationSecretUseCase.ContainerManagerRepository, *application, secrets, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return &secret, nil
+}
@@ -3,18 +3,42 @@ package applications
 import (
 	"fmt"
 
+	"cloud-app-hive/domain"
 	"cloud-app-hive/domain/commands"
 	"cloud-app-hive/domain/repositories"
+	"cloud-app-hive/use_cases"
 )
 
 type DeployApplicationUseCase struct {
-	ContainerManagerRepository repositories.ContainerManagerRepository
+	ContainerManagerRepository          repositories.ContainerManagerRepository
+	ApplyNetworkRulesUseCase            use_cases.ApplyNetworkRulesUseCase
+	RefreshApplicationDependentsUseCase RefreshApplicationDependentsUseCase
+	RecordAuditEventUseCase             use_cases.RecordAuditEventUseCase
 }
 
-func (deployApplicationUseCase DeployApplicationUseCase) Execute(applyApplication commands.ApplyApplication) error {
-	err := deployApplicationUseCase.ContainerManagerRepository.ApplyApplication(applyApplication)
+func (deployApplicationUseCase DeployApplicationUseCase) Execute(deployApplication commands.DeployApplication) (err error) {
+	auditEvent := domain.NewAuditEvent(deployApplication.Actor, domain.AuditApplicationDeploy, domain.AuditTargetApplication, deployApplication.Application.ID)
+	auditEvent.NamespaceID = deployApplication.Application.NamespaceID
+	auditEvent.TargetName = deployApplication.Application.Name
+	defer func() {
+		deployApplicationUseCase.RecordAuditEventUseCase.Execute(auditEvent, err)
+	}()
+
+	applyApplication := commands.NewApplyApplication(deployApplication.Application, deployApplication.NamespaceName)
+	applyApplication.StepReporter = deployApplication.StepReporter
+	err = deployApplicationUseCase.ContainerManagerRepository.ApplyApplication(applyApplication)
 	if err != nil {
 		return fmt.Errorf("error while applying application: %w", err)
 	}
+	// The rules are applied again since the applications they reference may have been created or renamed
+	err = deployApplicationUseCase.ApplyNetworkRulesUseCase.Execute(deployApplication.Application.NamespaceID)
+	if err != nil {
+		return err
+	}
+	// The applications depending on this one are deployed again by their own operations if its name or port changed
+	_, err = deployApplicationUseCase.RefreshApplicationDependentsUseCase.Execute(deployApplication.Application.NamespaceID, deployApplication.NamespaceName, deployApplication.Actor.UserID)
+	if err != nil {
+		return err
+	}
 	return nil
 }
@@ -0,0 +1,21 @@
+package applications
+
+import (
+	"fmt"
+
+	"cloud-app-hive/domain"
+	"cloud-app-hive/domain/commands"
+	"cloud-app-hive/domain/repositories"
+)
+
+type DryRunApplicationUseCase struct {
+	ContainerManagerRepository repositories.ContainerManagerRepository
+}
+
+func (dryRunApplicationUseCase DryRunApplicationUseCase) Execute(applyApplication commands.ApplyApplication) ([]domain.KubernetesObjectDryRun, error) {
+	objects, err := dryRunApplicationUseCase.ContainerManagerRepository.DryRunApplication(applyApplication)
+	if err != nil {
+		return nil, fmt.Errorf("error while dry running application: %w", err)
+	}
+	return objects, nil
+}
@@ -22,20 +22,16 @@ func (findApplicationByIDUseCase FindApplicationByIDUseCase) Execute(findApplica
 		return nil, errors.NewApplicationNotFoundByIDError(findApplicationByID.ApplicationID)
 	}
 
-	// Check that user is in namespace memberships
-	isAllowed := false
-	for _, membership := range application.Namespace.Memberships {
-		if membership.UserID == findApplicationByID.QueryByUserID {
-			isAllowed = true
-			break
-		}
+	permission := findApplicationByID.Permission
+	if permission == "" {
+		permission = domain.PermissionReadApplication
 	}
-	if !isAllowed {
-		return nil, errors.NewUnauthorizedToAccessNamespaceError(
-			application.Namespace.ID,
-			application.Namespace.Name,
-			findApplicationByID.QueryByUserID,
-		)
+	if err := application.Authorize(findApplicationByID.QueryByUserID, permission); err != nil {
+		return nil, err
+	}
+	role, _ := application.Namespace.RoleOf(findApplicationByID.QueryByUserID)
+	if !role.Can(domain.PermissionReadApplicationSecrets) {
+		application.Secrets = application.Secrets.NamesOnly()
 	}
 
 	return application, nil
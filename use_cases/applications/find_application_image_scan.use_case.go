@@ -0,0 +1,45 @@
+package applications
+
+import (
+	"cloud-app-hive/controllers/errors"
+	"fmt"
+
+	"cloud-app-hive/domain"
+	"cloud-app-hive/domain/commands"
+	"cloud-app-hive/domain/repositories"
+)
+
+type FindApplicationImageScanUseCase struct {
+	ApplicationRepository repositories.ApplicationRepository
+	ImageScanRepository   repositories.ImageScanRepository
+}
+
+// Execute returns the vulnerabilities found in the image deployed by the application, judged against the policy of its namespace
+func (findApplicationImageScanUseCase FindApplicationImageScanUseCase) Execute(findApplicationByID commands.FindApplicationByID) (*domain.ImageScanReport, error) {
+	application, err := findApplicationImageScanUseCase.ApplicationRepository.FindByID(findApplicationByID.ApplicationID)
+	if err != nil {
+		return nil, fmt.Errorf("error while finding application by id: %w", err)
+	}
+	if application == nil {
+		return nil, errors.NewApplicationNotFoundByIDError(findApplicationByID.ApplicationID)
+	}
+	if err := application.Authorize(findApplicationByID.QueryByUserID, domain.PermissionReadApplication); err != nil {
+		return nil, err
+	}
+
+	return findApplicationImageScanUseCase.Report(application.Namespace, *application)
+}
+
+// Report returns the vulnerabilities found in the image of an application the user is already authorized to read
+func (findApplicationImageScanUseCase FindApplicationImageScanUseCase) Report(namespace domain.Namespace, application domain.Application) (*domain.ImageScanReport, error) {
+	var imageScan *domain.ImageScan
+	if application.ImageDigest != "" {
+		var err error
+		imageScan, err = findApplicationImageScanUseCase.ImageScanRepository.FindByImageDigest(application.ImageDigest)
+		if err != nil {
+			return nil, err
+		}
+	}
+	imageScanReport := domain.NewImageScanReport(namespace, application, imageScan)
+	return &imageScanReport, nil
+}
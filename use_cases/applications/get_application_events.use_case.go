@@ -0,0 +1,106 @@
+package applications
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	customErrors "cloud-app-hive/controllers/errors"
+	"cloud-app-hive/domain"
+	"cloud-app-hive/domain/commands"
+	"cloud-app-hive/domain/repositories"
+)
+
+const (
+	defaultApplicationEventsPollInterval = 2 * time.Second
+	defaultMaxEventStreamsByUser         = 5
+)
+
+// eventStreams counts the streams each user has open, every stream reading the cluster at each poll
+var eventStreams = struct {
+	sync.Mutex
+	byUser map[string]int
+}{byUser: map[string]int{}}
+
+type GetApplicationEventsUseCase struct {
+	ContainerManagerRepository repositories.ContainerManagerRepository
+	// PollInterval is how often the streamed events are read again from the cluster, 2 seconds when zero
+	PollInterval time.Duration
+	// MaxStreamsByUser is how many streams a user can have open at the same time, 5 when zero
+	MaxStreamsByUser int
+}
+
+// Execute returns the Kubernetes events of the objects of an application, de-duplicated and from the oldest to the most recent
+func (getApplicationEventsUseCase GetApplicationEventsUseCase) Execute(application commands.GetApplicationEvents) ([]domain.ApplicationEvent, error) {
+	applicationEvents, err := getApplicationEventsUseCase.ContainerManagerRepository.GetApplicationEvents(application)
+	if err != nil {
+		return nil, fmt.Errorf("error while getting events: %w", err)
+	}
+	return domain.MergeApplicationEvents(applicationEvents), nil
+}
+
+// Stream sends the current events of an application, then the events that happen or repeat afterwards, until ctx is done or send fails.
+// Nothing is sent when no new event happened since the previous poll.
+// It fails without sending anything when the user already has as many streams open as allowed.
+func (getApplicationEventsUseCase GetApplicationEventsUseCase) Stream(ctx context.Context, application commands.GetApplicationEvents, send func([]domain.ApplicationEvent) error) error {
+	maxStreams := getApplicationEventsUseCase.maxStreamsByUser()
+	eventStreams.Lock()
+	if eventStreams.byUser[application.UserID] >= maxStreams {
+		eventStreams.Unlock()
+		return customErrors.NewTooManyEventStreamsError(maxStreams)
+	}
+	eventStreams.byUser[application.UserID]++
+	eventStreams.Unlock()
+	defer func() {
+		eventStreams.Lock()
+		defer eventStreams.Unlock()
+		eventStreams.byUser[application.UserID]--
+		if eventStreams.byUser[application.UserID] == 0 {
+			delete(eventStreams.byUser, application.UserID)
+		}
+	}()
+
+	previousEvents, err := getApplicationEventsUseCase.Execute(application)
+	if err != nil {
+		return err
+	}
+	if err := send(previousEvents); err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(getApplicationEventsUseCase.pollInterval())
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		currentEvents, err := getApplicationEventsUseCase.Execute(application)
+		if err != nil {
+			return err
+		}
+		if newEvents := domain.NewApplicationEvents(previousEvents, currentEvents); len(newEvents) > 0 {
+			if err := send(newEvents); err != nil {
+				return err
+			}
+		}
+		previousEvents = currentEvents
+	}
+}
+
+func (getApplicationEventsUseCase GetApplicationEventsUseCase) pollInterval() time.Duration {
+	if getApplicationEventsUseCase.PollInterval == 0 {
+		return defaultApplicationEventsPollInterval
+	}
+	return getApplicationEventsUseCase.PollInterval
+}
+
+func (getApplicationEventsUseCase GetApplicationEventsUseCase) maxStreamsByUser() int {
+	if getApplicationEventsUseCase.MaxStreamsByUser == 0 {
+		return defaultMaxEventStreamsByUser
+	}
+	return getApplicationEventsUseCase.MaxStreamsByUser
+}
@@ -0,0 +1,53 @@
+package applications
+
+import (
+	"fmt"
+
+	"cloud-app-hive/domain"
+	"cloud-app-hive/domain/commands"
+	"cloud-app-hive/domain/repositories"
+	"cloud-app-hive/use_cases/operations"
+)
+
+type RedeployApplicationUseCase struct {
+	ApplicationRepository            repositories.ApplicationRepository
+	ContainerManagerRepository       repositories.ContainerManagerRepository
+	StartApplicationOperationUseCase operations.StartApplicationOperationUseCase
+}
+
+// Execute starts an operation deploying again an application that is on the cluster, so that it picks up what changed outside of it
+// (its dependencies, its config groups...). It returns nil when the application is not on the cluster, it gets the changes when it is deployed.
+// The application is read again when the operation runs, after the operations queued before it, so that its latest version is deployed.
+func (redeployApplicationUseCase RedeployApplicationUseCase) Execute(application domain.Application, namespaceName string, userID string) (*domain.Operation, error) {
+	if !redeployApplicationUseCase.isOnCluster(application.Name, namespaceName) {
+		return nil, nil
+	}
+
+	return redeployApplicationUseCase.StartApplicationOperationUseCase.Execute(commands.StartApplicationOperation{
+		Type:          domain.UpdateOperation,
+		Application:   application,
+		NamespaceName: namespaceName,
+		UserID:        userID,
+		Run: func(reporter domain.OperationStepReporter) error {
+			currentApplication, err := redeployApplicationUseCase.ApplicationRepository.FindByID(application.ID)
+			if err != nil {
+				return fmt.Errorf("error while finding application by id: %w", err)
+			}
+			// An operation queued before this one may have deleted or undeployed the application
+			if currentApplication == nil || !redeployApplicationUseCase.isOnCluster(currentApplication.Name, namespaceName) {
+				return fmt.Errorf("application %s is not on the cluster anymore", application.Name)
+			}
+			applyApplication := commands.NewApplyApplication(*currentApplication, namespaceName)
+			applyApplication.StepReporter = reporter
+			return redeployApplicationUseCase.ContainerManagerRepository.ApplyApplication(applyApplication)
+		},
+	})
+}
+
+func (redeployApplicationUseCase RedeployApplicationUseCase) isOnCluster(applicationName string, namespaceName string) bool {
+	_, err := redeployApplicationUseCase.ContainerManagerRepository.GetApplicationStatus(commands.GetApplicationStatus{
+		Name:      applicationName,
+		Namespace: namespaceName,
+	})
+	return err == nil
+}
@@ -0,0 +1,64 @@
+package applications
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"cloud-app-hive/domain"
+	"cloud-app-hive/domain/repositories"
+)
+
+type RefreshApplicationDependentsUseCase struct {
+	ApplicationRepository      repositories.ApplicationRepository
+	RedeployApplicationUseCase RedeployApplicationUseCase
+}
+
+// Execute resolves again the dependencies of the applications of a namespace after one of its applications was deployed,
+// undeployed or deleted. The applications whose dependencies changed are stored with them, and an operation deploys them again
+// if they are on the cluster so that the environment variables describing their dependencies are up to date.
+// An application failing to refresh does not stop the others, the failures are returned together. It returns the started operations.
+func (refreshApplicationDependentsUseCase RefreshApplicationDependentsUseCase) Execute(namespaceID string, namespaceName string, userID string) ([]domain.Operation, error) {
+	namespaceApplications, err := refreshApplicationDependentsUseCase.ApplicationRepository.FindByNamespaceIDAndUserID(namespaceID)
+	if err != nil {
+		return nil, fmt.Errorf("error while finding applications by namespace id: %w", err)
+	}
+
+	startedOperations := []domain.Operation{}
+	var failures []string
+	for _, namespaceApplication := range namespaceApplications {
+		operation, err := refreshApplicationDependentsUseCase.refresh(namespaceApplication.ID, namespaceApplications, namespaceName, userID)
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %s", namespaceApplication.Name, err.Error()))
+			continue
+		}
+		if operation != nil {
+			startedOperations = append(startedOperations, *operation)
+		}
+	}
+	if len(failures) > 0 {
+		return startedOperations, fmt.Errorf("error refreshing dependencies of applications %s", strings.Join(failures, ", "))
+	}
+	return startedOperations, nil
+}
+
+func (refreshApplicationDependentsUseCase RefreshApplicationDependentsUseCase) refresh(applicationID string, namespaceApplications []domain.Application, namespaceName string, userID string) (*domain.Operation, error) {
+	// Applications listed by namespace do not carry their JSON fields
+	application, err := refreshApplicationDependentsUseCase.ApplicationRepository.FindByID(applicationID)
+	if err != nil {
+		return nil, err
+	}
+	if application == nil || application.Dependencies == nil || len(*application.Dependencies) == 0 {
+		return nil, nil
+	}
+	dependencies := application.Dependencies.Resolve(namespaceApplications)
+	if reflect.DeepEqual(dependencies, *application.Dependencies) {
+		return nil, nil
+	}
+	if err := refreshApplicationDependentsUseCase.ApplicationRepository.UpdateDependencies(application.ID, dependencies); err != nil {
+		return nil, err
+	}
+	application.Dependencies = &dependencies
+
+	return refreshApplicationDependentsUseCase.RedeployApplicationUseCase.Execute(*application, namespaceName, userID)
+}
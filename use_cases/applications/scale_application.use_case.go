@@ -7,11 +7,15 @@ import (
 	"cloud-app-hive/domain/commands"
 	"cloud-app-hive/domain/errors"
 	"cloud-app-hive/domain/repositories"
+	"cloud-app-hive/use_cases"
+
+	"gorm.io/datatypes"
 )
 
 type ScaleApplicationUseCase struct {
-	ApplicationRepository repositories.ApplicationRepository
-	ContainerManager      repositories.ContainerManagerRepository
+	ApplicationRepository   repositories.ApplicationRepository
+	ContainerManager        repositories.ContainerManagerRepository
+	RecordAuditEventUseCase use_cases.RecordAuditEventUseCase
 }
 
 type ScalingType string
@@ -23,7 +27,18 @@ const (
 	VerticalDownScaling   ScalingType = "VerticalDownScaling"
 )
 
-func (scaleApplicationUseCase ScaleApplicationUseCase) Execute(applicationID string, updateApplication commands.UpdateApplication, scalingType ScalingType) (*domain.Application, error) {
+// Execute scales the application, actor is the user or the scheduler at the origin of the scaling.
+// stepReporter is told of the progress of the scaling on the cluster, it is nil when no operation tracks it.
+func (scaleApplicationUseCase ScaleApplicationUseCase) Execute(applicationID string, actor domain.AuditActor, scalingType ScalingType, stepReporter domain.OperationStepReporter) (updatedApplication *domain.Application, err error) {
+	auditEvent := domain.NewAuditEvent(actor, domain.AuditApplicationScale, domain.AuditTargetApplication, applicationID)
+	var before *domain.ApplicationManifest
+	defer func() {
+		if before != nil && updatedApplication != nil {
+			auditEvent.Changes = domain.NewAuditChanges(before, domain.NewApplicationManifest(*updatedApplication))
+		}
+		scaleApplicationUseCase.RecordAuditEventUseCase.Execute(auditEvent, err)
+	}()
+
 	foundApplicationByID, err := scaleApplicationUseCase.ApplicationRepository.FindByID(applicationID)
 	if err != nil {
 		return nil, fmt.Errorf("error while finding application by id: %w", err)
@@ -31,8 +46,12 @@ func (scaleApplicationUseCase ScaleApplicationUseCase) Execute(applicationID str
 	if foundApplicationByID == nil {
 		return nil, fmt.Errorf("no application found for application id %s", applicationID)
 	}
+	auditEvent.NamespaceID = foundApplicationByID.NamespaceID
+	auditEvent.TargetName = foundApplicationByID.Name
+	foundApplicationManifest := domain.NewApplicationManifest(*foundApplicationByID)
+	before = &foundApplicationManifest
 
-	updatedApplication := &domain.Application{}
+	updatedApplication = &domain.Application{}
 	if scalingType == HorizontalUpScaling {
 		updatedApplication, err = scaleApplicationUseCase.ApplicationRepository.HorizontalScaleUp(applicationID)
 	}
@@ -52,18 +71,11 @@ func (scaleApplicationUseCase ScaleApplicationUseCase) Execute(applicationID str
 		return nil, fmt.Errorf("error while scaling application calling application repository: %w", err)
 	}
 
-	applyApplication := commands.ApplyApplication{
-		Name:                      foundApplicationByID.Name,
-		Image:                     foundApplicationByID.Image,
-		Registry:                  foundApplicationByID.Registry,
-		Namespace:                 foundApplicationByID.Namespace.Name,
-		Port:                      foundApplicationByID.Port,
-		ApplicationType:           foundApplicationByID.ApplicationType,
-		EnvironmentVariables:      *foundApplicationByID.EnvironmentVariables,
-		Secrets:                   *foundApplicationByID.Secrets,
-		ContainerSpecifications:   updatedApplication.ContainerSpecifications.Data(),
-		ScalabilitySpecifications: updatedApplication.ScalabilitySpecifications.Data(),
-	}
+	// The application is applied as stored, with its routes, dependencies, config groups and files, only its specifications change
+	applyApplication := commands.NewApplyApplication(*foundApplicationByID, foundApplicationByID.Namespace.Name)
+	applyApplication.ContainerSpecifications = updatedApplication.ContainerSpecifications.Data()
+	applyApplication.ScalabilitySpecifications = updatedApplication.ScalabilitySpecifications.Data()
+	applyApplication.StepReporter = stepReporter
 	err = scaleApplicationUseCase.ContainerManager.ApplyApplication(applyApplication)
 	if err != nil {
 		return nil, fmt.Errorf("error while scaling application calling container manager: %w", err)
@@ -71,3 +83,37 @@ func (scaleApplicationUseCase ScaleApplicationUseCase) Execute(applicationID str
 
 	return updatedApplication, nil
 }
+
+// DryRun returns the application as it would be after scaling, without storing nor deploying it
+func (scaleApplicationUseCase ScaleApplicationUseCase) DryRun(applicationID string, scalingType ScalingType) (*domain.Application, error) {
+	foundApplicationByID, err := scaleApplicationUseCase.ApplicationRepository.FindByID(applicationID)
+	if err != nil {
+		return nil, fmt.Errorf("error while finding application by id: %w", err)
+	}
+	if foundApplicationByID == nil {
+		return nil, fmt.Errorf("no application found for application id %s", applicationID)
+	}
+
+	scalabilitySpecifications := foundApplicationByID.ScalabilitySpecifications.Data()
+	containerSpecifications := foundApplicationByID.ContainerSpecifications.Data()
+	switch scalingType {
+	case HorizontalUpScaling:
+		scalabilitySpecifications, err = scalabilitySpecifications.HorizontallyScaledUp()
+	case HorizontalDownScaling:
+		scalabilitySpecifications, err = scalabilitySpecifications.HorizontallyScaledDown()
+	case VerticalUpScaling:
+		containerSpecifications, err = containerSpecifications.VerticallyScaledUp()
+	default:
+		return nil, fmt.Errorf("scaling type %s is not supported", scalingType)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	scaledContainerSpecifications := datatypes.NewJSONType(containerSpecifications)
+	foundApplicationByID.ContainerSpecifications = &scaledContainerSpecifications
+	scaledScalabilitySpecifications := datatypes.NewJSONType(scalabilitySpecifications)
+	foundApplicationByID.ScalabilitySpecifications = &scaledScalabilitySpecifications
+
+	return foundApplicationByID, nil
+}
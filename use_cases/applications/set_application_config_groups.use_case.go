@@ -0,0 +1,94 @@
+package applications
+
+import (
+	customErrors "cloud-app-hive/controllers/errors"
+	"fmt"
+
+	"cloud-app-hive/domain"
+	"cloud-app-hive/domain/commands"
+	"cloud-app-hive/domain/repositories"
+	"cloud-app-hive/use_cases"
+)
+
+type SetApplicationConfigGroupsUseCase struct {
+	ApplicationRepository      repositories.ApplicationRepository
+	ConfigGroupRepository      repositories.ConfigGroupRepository
+	ContainerManagerRepository repositories.ContainerManagerRepository
+	RecordAuditEventUseCase    use_cases.RecordAuditEventUseCase
+}
+
+// Execute attaches the application to the config groups of its namespace with the given names, in their order of precedence,
+// and deploys it again if it is on the cluster
+func (setApplicationConfigGroupsUseCase SetApplicationConfigGroupsUseCase) Execute(setApplicationConfigGroups commands.SetApplicationConfigGroups) (attachments []domain.ConfigGroupAttachment, err error) {
+	auditEvent := domain.NewAuditEvent(setApplicationConfigGroups.Actor, domain.AuditConfigGroupsSet, domain.AuditTargetApplication, setApplicationConfigGroups.ApplicationID)
+	var before []string
+	defer func() {
+		if attachments != nil {
+			auditEvent.Changes = domain.NewAuditChanges(map[string]interface{}{"configGroups": before}, map[string]interface{}{"configGroups": setApplicationConfigGroups.ConfigGroupNames})
+		}
+		setApplicationConfigGroupsUseCase.RecordAuditEventUseCase.Execute(auditEvent, err)
+	}()
+
+	if len(setApplicationConfigGroups.ConfigGroupNames) > domain.MaxConfigGroupsByApplication {
+		return nil, customErrors.NewInvalidConfigGroupError(fmt.Sprintf("an application cannot be attached to more than %d config groups", domain.MaxConfigGroupsByApplication))
+	}
+
+	application, err := findApplicationToUpdateSecrets(setApplicationConfigGroupsUseCase.ApplicationRepository, setApplicationConfigGroups.ApplicationID, setApplicationConfigGroups.UserID)
+	if err != nil {
+		return nil, err
+	}
+	auditEvent.NamespaceID = application.NamespaceID
+	auditEvent.TargetName = application.Name
+	before = []string{}
+	for _, configGroup := range domain.AttachedConfigGroups(application.ConfigGroupAttachments) {
+		before = append(before, configGroup.Name)
+	}
+
+	configGroups, err := setApplicationConfigGroupsUseCase.ConfigGroupRepository.FindByNamespaceID(application.NamespaceID)
+	if err != nil {
+		return nil, err
+	}
+	configGroupsByName := make(map[string]domain.ConfigGroup, len(configGroups))
+	for _, configGroup := range configGroups {
+		configGroupsByName[configGroup.Name] = configGroup
+	}
+
+	attachments = make([]domain.ConfigGroupAttachment, 0, len(setApplicationConfigGroups.ConfigGroupNames))
+	attached := map[string]bool{}
+	for position, name := range setApplicationConfigGroups.ConfigGroupNames {
+		configGroup, found := configGroupsByName[name]
+		if !found {
+			return nil, customErrors.NewConfigGroupNotFoundError(name)
+		}
+		if attached[name] {
+			return nil, customErrors.NewInvalidConfigGroupError(fmt.Sprintf("config group %s is given twice", name))
+		}
+		attached[name] = true
+		configGroup.Attachments = nil
+		attachments = append(attachments, domain.ConfigGroupAttachment{
+			ConfigGroupID: configGroup.ID,
+			ApplicationID: application.ID,
+			Position:      position,
+			ConfigGroup:   &configGroup,
+			CreatedBy:     setApplicationConfigGroups.UserID,
+		})
+	}
+
+	if err := setApplicationConfigGroupsUseCase.ConfigGroupRepository.ReplaceAttachments(application.ID, attachments); err != nil {
+		return nil, err
+	}
+	application.ConfigGroupAttachments = attachments
+
+	// An application that is not on the cluster reads its config groups when it is deployed
+	_, err = setApplicationConfigGroupsUseCase.ContainerManagerRepository.GetApplicationStatus(commands.GetApplicationStatus{
+		Name:      application.Name,
+		Namespace: application.Namespace.Name,
+	})
+	if err != nil {
+		return attachments, nil
+	}
+	if err := setApplicationConfigGroupsUseCase.ContainerManagerRepository.ApplyApplication(commands.NewApplyApplication(*application, application.Namespace.Name)); err != nil {
+		return nil, fmt.Errorf("error while applying application %s: %w", application.Name, err)
+	}
+	return attachments, nil
+}
@@ -0,0 +1,100 @@
+package applications
+
+import (
+	customErrors "cloud-app-hive/controllers/errors"
+	"fmt"
+	"time"
+
+	"cloud-app-hive/domain"
+	"cloud-app-hive/domain/commands"
+	"cloud-app-hive/domain/repositories"
+	"cloud-app-hive/use_cases"
+)
+
+type SetApplicationSecretUseCase struct {
+	ApplicationRepository      repositories.ApplicationRepository
+	ContainerManagerRepository repositories.ContainerManagerRepository
+	RecordAuditEventUseCase    use_cases.RecordAuditEventUseCase
+}
+
+// Execute adds the secret to the application or rotates its value or reference, then applies the application so that its Kubernetes Secret is updated
+func (setApplicationSecretUseCase SetApplicationSecretUseCase) Execute(setApplicationSecret commands.SetApplicationSecret) (secret *domain.ApplicationSecret, err error) {
+	auditEvent := domain.NewAuditEvent(setApplicationSecret.Actor, domain.AuditApplicationSecretSet, domain.AuditTargetApplication, setApplicationSecret.ApplicationID)
+	var before *domain.ApplicationSecret
+	defer func() {
+		if secret != nil {
+			auditEvent.Changes = domain.NewAuditChanges(map[string]interface{}{"secret": before}, map[string]interface{}{"secret": secret})
+		}
+		setApplicationSecretUseCase.RecordAuditEventUseCase.Execute(auditEvent, err)
+	}()
+
+	requestedSecret := domain.ApplicationSecret{Name: setApplicationSecret.Name, Val: setApplicationSecret.Value, Reference: setApplicationSecret.Reference}
+	requestedSecrets := domain.ApplicationSecrets{requestedSecret}
+	if err := requestedSecrets.Validate(); err != nil {
+		return nil, err
+	}
+
+	application, err := findApplicationToUpdateSecrets(setApplicationSecretUseCase.ApplicationRepository, setApplicationSecret.ApplicationID, setApplicationSecret.UserID)
+	if err != nil {
+		return nil, err
+	}
+	auditEvent.NamespaceID = application.NamespaceID
+	auditEvent.TargetName = application.Name
+
+	currentSecrets := domain.ApplicationSecrets{}
+	if application.Secrets != nil {
+		currentSecrets = *application.Secrets
+	}
+	if currentSecret, exists := currentSecrets.Find(setApplicationSecret.Name); exists {
+		before = &currentSecret
+	}
+	secrets := currentSecrets.WithSecret(requestedSecret, time.Now())
+
+	err = applyApplicationSecrets(setApplicationSecretUseCase.ApplicationRepository, setApplicationSecretUseCase.ContainerManagerRepository, *application, secrets, setApplicationSecret.Restart)
+	if err != nil {
+		return nil, err
+	}
+
+	updatedSecret, _ := secrets.Find(setApplicationSecret.Name)
+	return &updatedSecret, nil
+}
+
+// findApplicationToUpdateSecrets returns the application if the user can update it
+func findApplicationToUpdateSecrets(applicationRepository repositories.ApplicationRepository, applicationID string, userID string) (*domain.Application, error) {
+	application, err := applicationRepository.FindByID(applicationID)
+	if err != nil {
+		return nil, fmt.Errorf("error while finding application by id: %w", err)
+	}
+	if application == nil {
+		return nil, customErrors.NewApplicationNotFoundByIDError(applicationID)
+	}
+	if err := application.Authorize(userID, domain.PermissionUpdateApplication); err != nil {
+		return nil, err
+	}
+	return application, nil
+}
+
+// applyApplicationSecrets stores the secrets of the application and applies it, restarting its pods if restart is true
+func applyApplicationSecrets(
+	applicationRepository repositories.ApplicationRepository,
+	containerManagerRepository repositories.ContainerManagerRepository,
+	application domain.Application,
+	secrets domain.ApplicationSecrets,
+	restart bool,
+) error {
+	if err := applicationRepository.UpdateSecrets(application.ID, application.NamespaceID, secrets); err != nil {
+		return fmt.Errorf("error while updating application secrets: %w", err)
+	}
+
+	application.Secrets = &secrets
+	if err := containerManagerRepository.ApplyApplication(commands.NewApplyApplication(application, application.Namespace.Name)); err != nil {
+		return fmt.Errorf("error while applying application %s: %w", application.Name, err)
+	}
+	if !restart {
+		return nil
+	}
+	if err := containerManagerRepository.RestartApplication(commands.RestartApplication{Name: application.Name, Namespace: application.Namespace.Name}); err != nil {
+		return fmt.Errorf("error while restarting application %s: %w", application.Name, err)
+	}
+	return nil
+}
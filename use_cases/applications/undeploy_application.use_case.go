@@ -3,18 +3,38 @@ package applications
 import (
 	"fmt"
 
+	"cloud-app-hive/domain"
 	"cloud-app-hive/domain/commands"
 	"cloud-app-hive/domain/repositories"
+	"cloud-app-hive/use_cases"
 )
 
 type UndeployApplicationUseCase struct {
-	ContainerManagerRepository repositories.ContainerManagerRepository
+	ContainerManagerRepository          repositories.ContainerManagerRepository
+	RefreshApplicationDependentsUseCase RefreshApplicationDependentsUseCase
+	RecordAuditEventUseCase             use_cases.RecordAuditEventUseCase
 }
 
-func (undeployApplicationUseCase UndeployApplicationUseCase) Execute(applyApplication commands.UnapplyApplication) error {
-	err := undeployApplicationUseCase.ContainerManagerRepository.UnapplyApplication(applyApplication)
+func (undeployApplicationUseCase UndeployApplicationUseCase) Execute(undeployApplication commands.UndeployApplication) (err error) {
+	auditEvent := domain.NewAuditEvent(undeployApplication.Actor, domain.AuditApplicationUndeploy, domain.AuditTargetApplication, undeployApplication.Application.ID)
+	auditEvent.NamespaceID = undeployApplication.Application.NamespaceID
+	auditEvent.TargetName = undeployApplication.Application.Name
+	defer func() {
+		undeployApplicationUseCase.RecordAuditEventUseCase.Execute(auditEvent, err)
+	}()
+
+	err = undeployApplicationUseCase.ContainerManagerRepository.UnapplyApplication(commands.UnapplyApplication{
+		Name:         undeployApplication.Application.Name,
+		Namespace:    undeployApplication.Application.Namespace.Name,
+		StepReporter: undeployApplication.StepReporter,
+	})
 	if err != nil {
 		return fmt.Errorf("error while applying application: %w", err)
 	}
+	// The applications depending on this one lose the environment variables describing it
+	_, err = undeployApplicationUseCase.RefreshApplicationDependentsUseCase.Execute(undeployApplication.Application.NamespaceID, undeployApplication.Application.Namespace.Name, undeployApplication.Actor.UserID)
+	if err != nil {
+		return err
+	}
 	return nil
 }
@@ -2,19 +2,36 @@ package applications
 
 import (
 	"fmt"
+	"time"
 
-	"cloud-app-hive/controllers/errors"
 	"cloud-app-hive/domain"
 	"cloud-app-hive/domain/commands"
 	"cloud-app-hive/domain/repositories"
+	"cloud-app-hive/use_cases"
 )
 
 type UpdateApplicationUseCase struct {
-	ApplicationRepository repositories.ApplicationRepository
-	NamespaceRepository   repositories.NamespaceRepository
+	ApplicationRepository         repositories.ApplicationRepository
+	NamespaceRepository           repositories.NamespaceRepository
+	RegistryCredentialRepository  repositories.RegistryCredentialRepository
+	VerifyApplicationImageUseCase use_cases.VerifyApplicationImageUseCase
+	ScanApplicationImageUseCase   use_cases.ScanApplicationImageUseCase
+	RecordAuditEventUseCase       use_cases.RecordAuditEventUseCase
 }
 
-func (createApplicationUseCase UpdateApplicationUseCase) Execute(applicationID string, updateApplication commands.UpdateApplication, byUserID string) (*domain.Application, *domain.Namespace, error) {
+func (createApplicationUseCase UpdateApplicationUseCase) Execute(applicationID string, updateApplication commands.UpdateApplication, byUserID string) (updatedApplication *domain.Application, namespace *domain.Namespace, err error) {
+	auditEvent := domain.NewAuditEvent(updateApplication.Actor, domain.AuditApplicationUpdate, domain.AuditTargetApplication, applicationID)
+	var before *domain.ApplicationManifest
+	defer func() {
+		if updateApplication.DryRun {
+			return
+		}
+		if before != nil && updatedApplication != nil {
+			auditEvent.Changes = domain.NewAuditChanges(before, domain.NewApplicationManifest(*updatedApplication))
+		}
+		createApplicationUseCase.RecordAuditEventUseCase.Execute(auditEvent, err)
+	}()
+
 	foundApplicationByID, err := createApplicationUseCase.ApplicationRepository.FindByID(applicationID)
 	if err != nil {
 		return nil, nil, fmt.Errorf("error while finding application by id: %w", err)
@@ -22,20 +39,76 @@ func (createApplicationUseCase UpdateApplicationUseCase) Execute(applicationID s
 	if foundApplicationByID == nil {
 		return nil, nil, fmt.Errorf("no application found for application id %s", applicationID)
 	}
+	auditEvent.NamespaceID = foundApplicationByID.NamespaceID
+	auditEvent.TargetName = foundApplicationByID.Name
+	foundApplicationManifest := domain.NewApplicationManifest(*foundApplicationByID)
+	before = &foundApplicationManifest
+
+	if err := foundApplicationByID.Authorize(byUserID, domain.PermissionUpdateApplication); err != nil {
+		return nil, nil, err
+	}
+
+	namespaceApplications, err := createApplicationUseCase.ApplicationRepository.FindByNamespaceIDAndUserID(foundApplicationByID.NamespaceID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error while finding applications by namespace id: %w", err)
+	}
+	if err := updateApplication.Ingress.ValidateRoutesAgainst(foundApplicationByID.Name, namespaceApplications); err != nil {
+		return nil, nil, err
+	}
+
+	registryCredential, err := findApplicationRegistryCredential(createApplicationUseCase.RegistryCredentialRepository, foundApplicationByID.NamespaceID, updateApplication.Registry, updateApplication.RegistryCredentialID)
+	if err != nil {
+		return nil, nil, err
+	}
+	// The scanned digest is deployed rather than the tag, which could point to another image when the pods pull it
+	if foundApplicationByID.Namespace.RequiresPinnedImages() {
+		updateApplication.PinImageDigest = true
+	}
+	updateApplication.ImageDigest, err = createApplicationUseCase.VerifyApplicationImageUseCase.Execute(updateApplication.Registry, updateApplication.Image, registryCredential, updateApplication.PinImageDigest)
+	if err != nil {
+		return nil, nil, err
+	}
+	// A dry run only reads the registry and the stored scans
+	if updateApplication.DryRun {
+		_, err = createApplicationUseCase.ScanApplicationImageUseCase.DryRun(foundApplicationByID.Namespace, updateApplication.Image, updateApplication.ImageDigest)
+	} else {
+		_, err = createApplicationUseCase.ScanApplicationImageUseCase.Execute(foundApplicationByID.Namespace, updateApplication.Registry, updateApplication.Image, updateApplication.ImageDigest, registryCredential)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
 
-	isAdmin := false
-	for _, member := range foundApplicationByID.Namespace.Memberships {
-		if member.UserID == byUserID && member.Role == domain.RoleAdmin {
-			isAdmin = true
-			break
+	// Secrets given without value keep their stored value and version
+	updateApplication.Secrets, err = updateApplication.Secrets.Merge(foundApplicationByID.Secrets, time.Now())
+	if err != nil {
+		return nil, nil, err
+	}
+	// A basic auth given without password keeps its stored password
+	updateApplication.Ingress, err = updateApplication.Ingress.Merge(foundApplicationByID.Ingress)
+	if err != nil {
+		return nil, nil, err
+	}
+	// A secret config file given without content keeps its stored content
+	if updateApplication.ConfigFiles != nil {
+		configFiles, err := updateApplication.ConfigFiles.Merge(foundApplicationByID.ConfigFiles)
+		if err != nil {
+			return nil, nil, err
 		}
+		updateApplication.ConfigFiles = &configFiles
+	}
+	if err := updateApplication.Dependencies.Validate(foundApplicationByID.Name); err != nil {
+		return nil, nil, err
 	}
-	isAppOwner := foundApplicationByID.UserID == byUserID
-	if !isAdmin && !isAppOwner {
-		return nil, nil, errors.NewUnauthorizedToAccessNamespaceError(foundApplicationByID.Namespace.ID, foundApplicationByID.Namespace.Name, byUserID)
+	updateApplication.Dependencies = updateApplication.Dependencies.Resolve(namespaceApplications)
+
+	// A dry run goes through every check but does not store the application
+	if updateApplication.DryRun {
+		updateApplication.ApplyTo(foundApplicationByID)
+		foundApplicationByID.RegistryCredential = registryCredential
+		return foundApplicationByID, &foundApplicationByID.Namespace, nil
 	}
 
-	updatedApplication, err := createApplicationUseCase.ApplicationRepository.Update(applicationID, updateApplication)
+	updatedApplication, err = createApplicationUseCase.ApplicationRepository.Update(applicationID, updateApplication)
 	if err != nil {
 		return nil, nil, err
 	}
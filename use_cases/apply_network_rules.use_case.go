@@ -0,0 +1,71 @@
+package use_cases
+
+import (
+	"fmt"
+
+	"cloud-app-hive/domain"
+	"cloud-app-hive/domain/commands"
+	"cloud-app-hive/domain/repositories"
+)
+
+type ApplyNetworkRulesUseCase struct {
+	NamespaceRepository        repositories.NamespaceRepository
+	NetworkRuleRepository      repositories.NetworkRuleRepository
+	ContainerManagerRepository repositories.ContainerManagerRepository
+}
+
+// Execute applies the network rules of a namespace on the container manager.
+// The rules whose applications or source namespace were deleted since are skipped, which only closes traffic.
+func (applyNetworkRulesUseCase ApplyNetworkRulesUseCase) Execute(namespaceID string) error {
+	namespace, err := applyNetworkRulesUseCase.NamespaceRepository.FindByID(namespaceID)
+	if err != nil {
+		return err
+	}
+	networkRules, err := applyNetworkRulesUseCase.NetworkRuleRepository.FindByNamespaceID(namespaceID)
+	if err != nil {
+		return err
+	}
+
+	applyNetworkRules := commands.ApplyNetworkRules{Namespace: namespace.Name, Rules: []commands.ApplyNetworkRule{}}
+	sourceNamespaces := map[string]*domain.Namespace{}
+	for _, networkRule := range networkRules {
+		applyNetworkRule := commands.ApplyNetworkRule{ID: networkRule.ID}
+		if networkRule.ApplicationID != nil {
+			application := namespace.FindApplicationByID(*networkRule.ApplicationID)
+			if application == nil {
+				fmt.Printf("Skipping network rule %s: application %s no longer exists\n", networkRule.ID, *networkRule.ApplicationID)
+				continue
+			}
+			applyNetworkRule.ApplicationName = &application.Name
+		}
+
+		sourceNamespace, ok := sourceNamespaces[networkRule.FromNamespaceID]
+		if !ok {
+			sourceNamespace, err = applyNetworkRulesUseCase.NamespaceRepository.FindByID(networkRule.FromNamespaceID)
+			if err != nil {
+				fmt.Printf("Skipping network rule %s: %s\n", networkRule.ID, err.Error())
+				sourceNamespace = nil
+			}
+			sourceNamespaces[networkRule.FromNamespaceID] = sourceNamespace
+		}
+		if sourceNamespace == nil {
+			continue
+		}
+		applyNetworkRule.FromNamespace = sourceNamespace.Name
+		if networkRule.FromApplicationID != nil {
+			application := sourceNamespace.FindApplicationByID(*networkRule.FromApplicationID)
+			if application == nil {
+				fmt.Printf("Skipping network rule %s: application %s no longer exists\n", networkRule.ID, *networkRule.FromApplicationID)
+				continue
+			}
+			applyNetworkRule.FromApplicationName = &application.Name
+		}
+
+		applyNetworkRules.Rules = append(applyNetworkRules.Rules, applyNetworkRule)
+	}
+
+	if err := applyNetworkRulesUseCase.ContainerManagerRepository.ApplyNetworkRules(applyNetworkRules); err != nil {
+		return fmt.Errorf("error while applying network rules: %w", err)
+	}
+	return nil
+}
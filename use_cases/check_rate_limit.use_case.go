@@ -0,0 +1,95 @@
+package use_cases
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"cloud-app-hive/domain"
+	"cloud-app-hive/domain/repositories"
+)
+
+// defaultRateLimits are the limits of each class of routes when its RATE_LIMIT_<CLASS> environment variable is not set
+var defaultRateLimits = map[domain.RateLimitClass]string{
+	domain.RateLimitRead:   "600/1m",
+	domain.RateLimitWrite:  "120/1m",
+	domain.RateLimitDeploy: "20/1m,500/24h",
+	domain.RateLimitLogs:   "60/1m",
+}
+
+// RateLimitPolicyFromEnvironment reads the limits of each class of routes from RATE_LIMIT_READ, RATE_LIMIT_WRITE,
+// RATE_LIMIT_DEPLOY and RATE_LIMIT_LOGS, such as "30/1m,500/24h", or "none" to disable the limits of a class
+func RateLimitPolicyFromEnvironment() (domain.RateLimitPolicy, error) {
+	rateLimitPolicy := domain.RateLimitPolicy{}
+	for rateLimitClass, defaultRateLimit := range defaultRateLimits {
+		variable := fmt.Sprintf("RATE_LIMIT_%s", rateLimitClassVariableSuffix(rateLimitClass))
+		value := os.Getenv(variable)
+		if value == "" {
+			value = defaultRateLimit
+		}
+		rateLimits, err := domain.ParseRateLimits(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", variable, err)
+		}
+		rateLimitPolicy[rateLimitClass] = rateLimits
+	}
+	return rateLimitPolicy, nil
+}
+
+func rateLimitClassVariableSuffix(rateLimitClass domain.RateLimitClass) string {
+	switch rateLimitClass {
+	case domain.RateLimitRead:
+		return "READ"
+	case domain.RateLimitWrite:
+		return "WRITE"
+	case domain.RateLimitDeploy:
+		return "DEPLOY"
+	default:
+		return "LOGS"
+	}
+}
+
+type CheckRateLimitUseCase struct {
+	RateLimitCounterRepository repositories.RateLimitCounterRepository
+	RateLimitPolicy            domain.RateLimitPolicy
+}
+
+// Execute counts a request of the identity on a class of routes and tells whether it is allowed by every limit of the class.
+// The returned limit is the exceeded one that resets last, or the one with the fewest remaining requests when allowed.
+func (checkRateLimitUseCase CheckRateLimitUseCase) Execute(identity string, rateLimitClass domain.RateLimitClass) (domain.RateLimitDecision, error) {
+	now := time.Now().UTC()
+	rateLimitDecision := domain.RateLimitDecision{Allowed: true}
+
+	for _, rateLimit := range checkRateLimitUseCase.RateLimitPolicy[rateLimitClass] {
+		windowStart := rateLimit.WindowStart(now)
+		key := fmt.Sprintf("%s|%s|%s", identity, rateLimitClass, rateLimit)
+		count, err := checkRateLimitUseCase.RateLimitCounterRepository.Increment(key, windowStart, rateLimit.Window)
+		if err != nil {
+			return domain.RateLimitDecision{}, err
+		}
+
+		remaining := rateLimit.Requests - count
+		if remaining < 0 {
+			remaining = 0
+		}
+		limitDecision := domain.RateLimitDecision{
+			Allowed:   count <= rateLimit.Requests,
+			Limit:     rateLimit,
+			Remaining: remaining,
+			ResetAt:   windowStart.Add(rateLimit.Window),
+		}
+
+		switch {
+		case rateLimitDecision.Limit.Requests == 0:
+			rateLimitDecision = limitDecision
+		case !limitDecision.Allowed:
+			if rateLimitDecision.Allowed || limitDecision.ResetAt.After(rateLimitDecision.ResetAt) {
+				rateLimitDecision = limitDecision
+			}
+		case rateLimitDecision.Allowed && limitDecision.Remaining < rateLimitDecision.Remaining:
+			rateLimitDecision = limitDecision
+		}
+	}
+
+	return rateLimitDecision, nil
+}
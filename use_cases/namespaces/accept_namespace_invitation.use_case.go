@@ -0,0 +1,99 @@
+package namespaces
+
+import (
+	"cloud-app-hive/controllers/errors"
+	"fmt"
+	"time"
+
+	"cloud-app-hive/domain"
+	"cloud-app-hive/domain/commands"
+	"cloud-app-hive/domain/repositories"
+	"cloud-app-hive/use_cases"
+)
+
+type AcceptNamespaceInvitationUseCase struct {
+	NamespaceRepository           repositories.NamespaceRepository
+	NamespaceInvitationRepository repositories.NamespaceInvitationRepository
+	NamespaceMembershipRepository repositories.NamespaceMembershipRepository
+	RecordAuditEventUseCase       use_cases.RecordAuditEventUseCase
+}
+
+// Execute makes the invitee a member of the namespace with the role of the invitation.
+// The membership is added on behalf of the user who sent the invitation, who must still be allowed to give the role.
+func (acceptNamespaceInvitationUseCase AcceptNamespaceInvitationUseCase) Execute(respondToNamespaceInvitation commands.RespondToNamespaceInvitation) (createdNamespaceMembership *domain.NamespaceMembership, err error) {
+	auditEvent := domain.NewAuditEvent(respondToNamespaceInvitation.Actor, domain.AuditNamespaceInviteAccept, domain.AuditTargetInvitation, "")
+	defer func() {
+		acceptNamespaceInvitationUseCase.RecordAuditEventUseCase.Execute(auditEvent, err)
+	}()
+
+	now := time.Now().UTC()
+	invitation, err := findNamespaceInvitationToRespond(acceptNamespaceInvitationUseCase.NamespaceInvitationRepository, respondToNamespaceInvitation, now)
+	if invitation != nil {
+		auditEvent.NamespaceID = invitation.NamespaceID
+		auditEvent.TargetID = invitation.ID
+		auditEvent.TargetName = invitation.Email
+	}
+	if err != nil {
+		return nil, err
+	}
+	auditEvent.Changes = domain.NewAuditChanges(nil, map[string]domain.Role{"role": invitation.Role})
+
+	namespace, err := acceptNamespaceInvitationUseCase.NamespaceRepository.FindByID(invitation.NamespaceID)
+	if err != nil {
+		return nil, err
+	}
+	if namespace == nil {
+		return nil, errors.NewNamespaceNotFoundByIDError(invitation.NamespaceID)
+	}
+	if _, isMember := namespace.RoleOf(respondToNamespaceInvitation.UserID); isMember {
+		return nil, errors.NewInvalidNamespaceInvitationError(fmt.Sprintf("user %s is already a member of the namespace %s", respondToNamespaceInvitation.UserID, namespace.Name))
+	}
+	if err := authorizeNamespaceInvitation(*namespace, invitation.InvitedBy, invitation.Role); err != nil {
+		return nil, err
+	}
+
+	// The invitation is marked as accepted first, so that a token can only create one membership
+	updated, err := acceptNamespaceInvitationUseCase.NamespaceInvitationRepository.UpdateStatus(invitation.ID, domain.NamespaceInvitationAccepted, &respondToNamespaceInvitation.UserID, now)
+	if err != nil {
+		return nil, err
+	}
+	if !updated {
+		return nil, errors.NewNamespaceInvitationNotPendingError(invitation.ID, "no longer pending")
+	}
+
+	createdNamespaceMembership, err = acceptNamespaceInvitationUseCase.NamespaceMembershipRepository.Create(commands.CreateNamespaceMembership{
+		UserID:      respondToNamespaceInvitation.UserID,
+		NamespaceID: invitation.NamespaceID,
+		Role:        invitation.Role,
+		AddedBy:     invitation.InvitedBy,
+		Actor:       respondToNamespaceInvitation.Actor,
+	})
+	if err != nil {
+		fmt.Println(fmt.Errorf("error creating namespace membership of accepted invitation %s: %w", invitation.ID, err))
+		return nil, err
+	}
+	if createdNamespaceMembership == nil {
+		return nil, fmt.Errorf("namespace membership of invitation %s could not be created", invitation.ID)
+	}
+
+	return createdNamespaceMembership, nil
+}
+
+// findNamespaceInvitationToRespond returns the invitation of the token, with an error if the user cannot respond to it anymore.
+// Holding the token is not enough, users must be signed in with the verified email the invitation was sent to.
+func findNamespaceInvitationToRespond(namespaceInvitationRepository repositories.NamespaceInvitationRepository, respondToNamespaceInvitation commands.RespondToNamespaceInvitation, now time.Time) (*domain.NamespaceInvitation, error) {
+	invitation, err := namespaceInvitationRepository.FindByTokenHash(domain.HashNamespaceInvitationToken(respondToNamespaceInvitation.Token))
+	if err != nil {
+		return nil, err
+	}
+	if invitation == nil {
+		return nil, errors.NewNamespaceInvitationNotFoundError("")
+	}
+	if !invitation.IsFor(respondToNamespaceInvitation.UserEmail) {
+		return invitation, errors.NewNamespaceInvitationEmailMismatchError(invitation.ID, respondToNamespaceInvitation.UserID)
+	}
+	if !invitation.IsPending(now) {
+		return invitation, errors.NewNamespaceInvitationNotPendingError(invitation.ID, string(invitation.CurrentStatus(now)))
+	}
+	return invitation, nil
+}
@@ -0,0 +1,48 @@
+package namespaces
+
+import (
+	"cloud-app-hive/controllers/errors"
+	"time"
+
+	"cloud-app-hive/domain"
+	"cloud-app-hive/domain/commands"
+	"cloud-app-hive/domain/repositories"
+	"cloud-app-hive/use_cases"
+)
+
+type AcceptNamespaceOwnershipTransferUseCase struct {
+	NamespaceRepository     repositories.NamespaceRepository
+	RecordAuditEventUseCase use_cases.RecordAuditEventUseCase
+}
+
+// Execute makes the user the owner of the namespace it is being transferred to, the previous owner stays as an admin
+func (acceptNamespaceOwnershipTransferUseCase AcceptNamespaceOwnershipTransferUseCase) Execute(respondToNamespaceOwnershipTransfer commands.RespondToNamespaceOwnershipTransfer) (namespace *domain.Namespace, err error) {
+	auditEvent := domain.NewAuditEvent(respondToNamespaceOwnershipTransfer.Actor, domain.AuditNamespaceTransferAccept, domain.AuditTargetNamespace, respondToNamespaceOwnershipTransfer.NamespaceID)
+	auditEvent.NamespaceID = respondToNamespaceOwnershipTransfer.NamespaceID
+	defer func() {
+		acceptNamespaceOwnershipTransferUseCase.RecordAuditEventUseCase.Execute(auditEvent, err)
+	}()
+
+	foundNamespaceByID, err := acceptNamespaceOwnershipTransferUseCase.NamespaceRepository.FindByID(respondToNamespaceOwnershipTransfer.NamespaceID)
+	if err != nil {
+		return nil, err
+	}
+	if foundNamespaceByID == nil {
+		return nil, errors.NewNamespaceNotFoundByIDError(respondToNamespaceOwnershipTransfer.NamespaceID)
+	}
+	auditEvent.TargetName = foundNamespaceByID.Name
+
+	if !foundNamespaceByID.HasPendingOwnershipTransferTo(respondToNamespaceOwnershipTransfer.UserID, time.Now()) {
+		return nil, errors.NewNamespaceOwnershipTransferNotFoundError(foundNamespaceByID.ID, respondToNamespaceOwnershipTransfer.UserID)
+	}
+	// The member may have been removed since the transfer was requested
+	if _, isMember := foundNamespaceByID.RoleOf(respondToNamespaceOwnershipTransfer.UserID); !isMember {
+		return nil, errors.NewUnauthorizedToAccessNamespaceError(foundNamespaceByID.ID, foundNamespaceByID.Name, respondToNamespaceOwnershipTransfer.UserID)
+	}
+	auditEvent.Changes = domain.NewAuditChanges(
+		map[string]string{"userId": foundNamespaceByID.UserID},
+		map[string]string{"userId": respondToNamespaceOwnershipTransfer.UserID},
+	)
+
+	return acceptNamespaceOwnershipTransferUseCase.NamespaceRepository.TransferOwnership(foundNamespaceByID.ID, respondToNamespaceOwnershipTransfer.UserID)
+}
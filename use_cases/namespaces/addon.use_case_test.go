@@ -0,0 +1,203 @@
+package namespaces
+
+import (
+	"fmt"
+	"testing"
+
+	customErrors "cloud-app-hive/controllers/errors"
+	"cloud-app-hive/domain"
+	"cloud-app-hive/domain/commands"
+)
+
+// MockAddonRepository is a mock implementation of the AddonRepository interface keeping the add-ons and their bindings in memory
+type MockAddonRepository struct {
+	Addons   []domain.Addon
+	Bindings []domain.AddonBinding
+}
+
+func (m *MockAddonRepository) Create(addon domain.Addon) (*domain.Addon, error) {
+	addon.ID = fmt.Sprintf("addon-%d", len(m.Addons)+1)
+	m.Addons = append(m.Addons, addon)
+	return &addon, nil
+}
+
+func (m *MockAddonRepository) FindByID(id string) (*domain.Addon, error) {
+	for _, addon := range m.Addons {
+		if addon.ID == id {
+			addon.Bindings = m.addonBindings(id)
+			return &addon, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *MockAddonRepository) FindByNamespaceID(namespaceID string) ([]domain.Addon, error) {
+	var addons []domain.Addon
+	for _, addon := range m.Addons {
+		if addon.NamespaceID == namespaceID {
+			addon.Bindings = m.addonBindings(addon.ID)
+			addons = append(addons, addon)
+		}
+	}
+	return addons, nil
+}
+
+func (m *MockAddonRepository) FindAll() ([]domain.Addon, error) {
+	return m.Addons, nil
+}
+
+func (m *MockAddonRepository) UpdateCredentials(addonID string, namespaceID string, credentials domain.ApplicationSecrets) error {
+	for index, addon := range m.Addons {
+		if addon.ID == addonID {
+			m.Addons[index].Credentials = &credentials
+			return nil
+		}
+	}
+	return nil
+}
+
+func (m *MockAddonRepository) Delete(id string) error {
+	for index, addon := range m.Addons {
+		if addon.ID == id {
+			m.Addons = append(m.Addons[:index], m.Addons[index+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+func (m *MockAddonRepository) CreateBinding(addonBinding domain.AddonBinding) (*domain.AddonBinding, error) {
+	addonBinding.ID = fmt.Sprintf("binding-%d", len(m.Bindings)+1)
+	m.Bindings = append(m.Bindings, addonBinding)
+	return &addonBinding, nil
+}
+
+func (m *MockAddonRepository) DeleteBinding(id string) error {
+	for index, binding := range m.Bindings {
+		if binding.ID == id {
+			m.Bindings = append(m.Bindings[:index], m.Bindings[index+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+func (m *MockAddonRepository) addonBindings(addonID string) []domain.AddonBinding {
+	bindings := []domain.AddonBinding{}
+	for _, binding := range m.Bindings {
+		if binding.AddonID == addonID {
+			bindings = append(bindings, binding)
+		}
+	}
+	return bindings
+}
+
+// newTestAddonUseCases returns the add-on use cases of the namespace "team" of newTestNamespaceRepository,
+// where the application "api" is deployed
+func newTestAddonUseCases() (CreateAddonUseCase, DeleteAddonUseCase, BindAddonUseCase, UnbindAddonUseCase, *MockAddonRepository, *MockContainerManagerRepository) {
+	namespaceRepository := newTestNamespaceRepository()
+	namespace, _ := namespaceRepository.FindByID("team")
+	namespace.Applications = []domain.Application{{ID: "api-id", Name: "api", NamespaceID: namespace.ID}}
+	namespaceRepository.FindByIDFunc = func(id string) (*domain.Namespace, error) {
+		return namespace, nil
+	}
+	applicationRepository := newInMemoryApplicationRepository(map[string]*domain.Application{"api-id": &namespace.Applications[0]})
+	addonRepository := &MockAddonRepository{}
+	containerManager := &MockContainerManagerRepository{}
+	recordAuditEventUseCase := newTestRecordAuditEventUseCase(&MockAuditEventRepository{})
+
+	return CreateAddonUseCase{
+		NamespaceRepository:        namespaceRepository,
+		AddonRepository:            addonRepository,
+		ContainerManagerRepository: containerManager,
+		RecordAuditEventUseCase:    recordAuditEventUseCase,
+	}, DeleteAddonUseCase{
+		NamespaceRepository:        namespaceRepository,
+		AddonRepository:            addonRepository,
+		ContainerManagerRepository: containerManager,
+		RecordAuditEventUseCase:    recordAuditEventUseCase,
+	}, BindAddonUseCase{
+		NamespaceRepository:        namespaceRepository,
+		AddonRepository:            addonRepository,
+		ApplicationRepository:      applicationRepository,
+		ContainerManagerRepository: containerManager,
+		RecordAuditEventUseCase:    recordAuditEventUseCase,
+	}, UnbindAddonUseCase{
+		NamespaceRepository:        namespaceRepository,
+		AddonRepository:            addonRepository,
+		ApplicationRepository:      applicationRepository,
+		ContainerManagerRepository: containerManager,
+		RecordAuditEventUseCase:    recordAuditEventUseCase,
+	}, addonRepository, containerManager
+}
+
+func TestExecute_CreateAddon_GeneratesCredentialsAndProvisionsTheAddon(t *testing.T) {
+	createAddonUseCase, _, _, _, addonRepository, containerManager := newTestAddonUseCases()
+
+	createAddon := commands.CreateAddon{NamespaceID: "team", Name: "main-db", Type: domain.PostgreSQLAddon, Size: domain.MediumAddon, CreatedBy: "admin"}
+	addon, err := createAddonUseCase.Execute(createAddon)
+	if err != nil {
+		t.Fatalf("Expected the addon to be created, but got %v", err)
+	}
+	if addon.Port != 5432 || addon.Password().Val == "" || addon.URL().Val == "" {
+		t.Errorf("Expected a PostgreSQL addon with generated credentials, but got %+v", addon)
+	}
+	if len(containerManager.AppliedAddons) != 1 || containerManager.AppliedAddons[0].Password.Val != addon.Password().Val {
+		t.Errorf("Expected the addon to be applied with its password, but got %+v", containerManager.AppliedAddons)
+	}
+
+	if _, err := createAddonUseCase.Execute(createAddon); err == nil {
+		t.Error("Expected a second addon with the same name to be rejected, but got nil")
+	}
+	createAddon.Name = "cache"
+	createAddon.CreatedBy = "member"
+	if _, err := createAddonUseCase.Execute(createAddon); err == nil {
+		t.Error("Expected a member to be forbidden, but got nil")
+	}
+	createAddon.CreatedBy = "admin"
+	createAddon.Size = "huge"
+	if _, err := createAddonUseCase.Execute(createAddon); err == nil {
+		t.Error("Expected an unknown size to be rejected, but got nil")
+	}
+	if len(addonRepository.Addons) != 1 {
+		t.Errorf("Expected 1 addon to be stored, but got %d", len(addonRepository.Addons))
+	}
+}
+
+func TestExecute_BindAddon_RedeploysTheApplicationAndBlocksDeletion(t *testing.T) {
+	createAddonUseCase, deleteAddonUseCase, bindAddonUseCase, unbindAddonUseCase, addonRepository, containerManager := newTestAddonUseCases()
+	addon, err := createAddonUseCase.Execute(commands.CreateAddon{NamespaceID: "team", Name: "cache", Type: domain.RedisAddon, Size: domain.SmallAddon, CreatedBy: "admin"})
+	if err != nil {
+		t.Fatalf("Expected the addon to be created, but got %v", err)
+	}
+
+	bindAddon := commands.BindAddon{NamespaceID: "team", AddonID: addon.ID, ApplicationID: "api-id", BoundBy: "admin"}
+	if _, err := bindAddonUseCase.Execute(bindAddon); err != nil {
+		t.Fatalf("Expected the addon to be bound, but got %v", err)
+	}
+	if len(containerManager.AppliedApplications) != 1 || containerManager.AppliedApplications[0] != "api" {
+		t.Errorf("Expected api to be deployed again, but got %v", containerManager.AppliedApplications)
+	}
+	if _, err := bindAddonUseCase.Execute(bindAddon); err == nil {
+		t.Error("Expected a second binding to the same application to be rejected, but got nil")
+	}
+
+	_, err = deleteAddonUseCase.Execute(commands.DeleteAddon{NamespaceID: "team", AddonID: addon.ID, DeletedBy: "admin"})
+	inUseErr, ok := err.(*customErrors.AddonInUseError)
+	if !ok || len(inUseErr.ApplicationNames) != 1 || inUseErr.ApplicationNames[0] != "api" {
+		t.Fatalf("Expected the addon bound to api not to be deleted, but got %v", err)
+	}
+
+	if _, err := unbindAddonUseCase.Execute(commands.UnbindAddon{NamespaceID: "team", AddonID: addon.ID, ApplicationID: "api-id", UnboundBy: "admin"}); err != nil {
+		t.Fatalf("Expected the addon to be unbound, but got %v", err)
+	}
+	if _, err := unbindAddonUseCase.Execute(commands.UnbindAddon{NamespaceID: "team", AddonID: addon.ID, ApplicationID: "api-id", UnboundBy: "admin"}); err == nil {
+		t.Error("Expected unbinding an application that is not bound to fail, but got nil")
+	}
+	if _, err := deleteAddonUseCase.Execute(commands.DeleteAddon{NamespaceID: "team", AddonID: addon.ID, DeletedBy: "admin"}); err != nil {
+		t.Fatalf("Expected the unbound addon to be deleted, but got %v", err)
+	}
+	if len(addonRepository.Addons) != 0 || len(containerManager.UnappliedAddons) != 1 {
+		t.Errorf("Expected the addon to be deleted from the database and the cluster, but got %+v and %v", addonRepository.Addons, containerManager.UnappliedAddons)
+	}
+}
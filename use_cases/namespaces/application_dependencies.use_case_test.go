@@ -0,0 +1,93 @@
+package namespaces
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"cloud-app-hive/domain"
+	"cloud-app-hive/domain/commands"
+	"cloud-app-hive/use_cases/applications"
+	"cloud-app-hive/use_cases/operations"
+)
+
+func TestApplyNamespaceManifest_ResolvesDependencies(t *testing.T) {
+	applications := map[string]*domain.Application{}
+	operationRepository := &MockOperationRepository{}
+	useCase := newTestApplyNamespaceManifestUseCase(newInMemoryApplicationRepository(applications), &MockContainerManagerRepository{}, operationRepository, &MockAuditEventRepository{})
+	manifest := newTestManifest("frontend", "backend")
+	manifest.Applications[0].Dependencies = []string{"backend", "cache"}
+	manifest.Applications[1].Port = 8080
+
+	result, err := useCase.Execute(commands.ApplyNamespaceManifest{NamespaceID: "namespace-id", UserID: "admin", Manifest: manifest})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	waitForOperations(t, operationRepository, result.Operations)
+	expectedDependencies := domain.ApplicationDependencies{{Name: "backend", Port: 8080}, {Name: "cache", Port: 0}}
+	if !reflect.DeepEqual(*applications["frontend-id"].Dependencies, expectedDependencies) {
+		t.Errorf("Expected the dependencies to be resolved against the manifest, got %v", *applications["frontend-id"].Dependencies)
+	}
+}
+
+func TestRefreshApplicationDependents(t *testing.T) {
+	frontendDependencies := domain.ApplicationDependencies{{Name: "backend", Port: 8080}}
+	storedApplications := map[string]*domain.Application{
+		"frontend-id": {ID: "frontend-id", Name: "frontend", Port: 80, Dependencies: &frontendDependencies},
+		"backend-id":  {ID: "backend-id", Name: "backend", Port: 9090},
+	}
+	containerManager := &MockContainerManagerRepository{}
+	operationRepository := &MockOperationRepository{}
+	applicationRepository := newInMemoryApplicationRepository(storedApplications)
+	useCase := applications.RefreshApplicationDependentsUseCase{
+		ApplicationRepository: applicationRepository,
+		RedeployApplicationUseCase: applications.RedeployApplicationUseCase{
+			ApplicationRepository:      applicationRepository,
+			ContainerManagerRepository: containerManager,
+			StartApplicationOperationUseCase: operations.StartApplicationOperationUseCase{
+				OperationRepository:        operationRepository,
+				ContainerManagerRepository: containerManager,
+				RolloutPollInterval:        time.Millisecond,
+			},
+		},
+	}
+
+	startedOperations, err := useCase.Execute("namespace-id", "team", "admin")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(startedOperations) != 1 || startedOperations[0].ApplicationName != "frontend" || startedOperations[0].Type != domain.UpdateOperation {
+		t.Fatalf("Expected an operation deploying frontend again, got %+v", startedOperations)
+	}
+	if finishedOperations := waitForOperations(t, operationRepository, startedOperations); finishedOperations[0].Status != domain.OperationSucceeded {
+		t.Errorf("Expected the deployment of frontend to succeed, got %+v", finishedOperations[0])
+	}
+	if !reflect.DeepEqual(containerManager.AppliedApplications, []string{"frontend"}) {
+		t.Fatalf("Expected frontend to be deployed again, got %v", containerManager.AppliedApplications)
+	}
+	if (*storedApplications["frontend-id"].Dependencies)[0].Port != 9090 {
+		t.Errorf("Expected the new port of backend to be stored, got %v", *storedApplications["frontend-id"].Dependencies)
+	}
+
+	// Nothing changed since the last refresh
+	if startedOperations, _ = useCase.Execute("namespace-id", "team", "admin"); len(startedOperations) != 0 {
+		t.Errorf("Expected no application to be deployed again, got %+v", startedOperations)
+	}
+}
+
+func TestFindNamespaceServices(t *testing.T) {
+	useCase := FindNamespaceServicesUseCase{
+		NamespaceRepository: newTestNamespaceRepository(),
+		ApplicationRepository: newInMemoryApplicationRepository(map[string]*domain.Application{
+			"backend-id": {ID: "backend-id", Name: "backend", Port: 8080},
+		}),
+	}
+
+	services, err := useCase.Execute("namespace-id", "admin")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(services) != 1 || services[0].Host != "backend-service.team.svc.cluster.local" || services[0].Port != 8080 {
+		t.Errorf("Expected the service of backend, got %+v", services)
+	}
+}
@@ -0,0 +1,51 @@
+package namespaces
+
+import (
+	"context"
+	stdErrors "errors"
+	"testing"
+	"time"
+
+	"cloud-app-hive/controllers/errors"
+	"cloud-app-hive/domain"
+	"cloud-app-hive/domain/commands"
+	"cloud-app-hive/use_cases/applications"
+)
+
+func TestStream_GetApplicationEvents_LimitsTheStreamsOfAUser(t *testing.T) {
+	getApplicationEventsUseCase := applications.GetApplicationEventsUseCase{
+		ContainerManagerRepository: &MockContainerManagerRepository{},
+		PollInterval:               time.Hour,
+		MaxStreamsByUser:           1,
+	}
+	sendNothing := func([]domain.ApplicationEvent) error { return nil }
+	ctx, cancel := context.WithCancel(context.Background())
+	started := make(chan struct{})
+	stopped := make(chan error)
+	go func() {
+		stopped <- getApplicationEventsUseCase.Stream(ctx, commands.GetApplicationEvents{Name: "api", Namespace: "team", UserID: "user-id"}, func([]domain.ApplicationEvent) error {
+			close(started)
+			return nil
+		})
+	}()
+	<-started
+
+	err := getApplicationEventsUseCase.Stream(context.Background(), commands.GetApplicationEvents{Name: "web", Namespace: "team", UserID: "user-id"}, sendNothing)
+	var tooManyEventStreamsError *errors.TooManyEventStreamsError
+	if !stdErrors.As(err, &tooManyEventStreamsError) {
+		t.Fatalf("Expected a second stream of the user to be refused, got %v", err)
+	}
+	stoppedCtx, stop := context.WithCancel(context.Background())
+	stop()
+	if err := getApplicationEventsUseCase.Stream(stoppedCtx, commands.GetApplicationEvents{Name: "web", Namespace: "team", UserID: "other-user-id"}, sendNothing); err != nil {
+		t.Errorf("Expected another user to open a stream, got %v", err)
+	}
+
+	cancel()
+	if err := <-stopped; err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := getApplicationEventsUseCase.Stream(stoppedCtx, commands.GetApplicationEvents{Name: "web", Namespace: "team", UserID: "user-id"}, sendNothing); err != nil {
+		t.Errorf("Expected the user to open a stream once the previous one is closed, got %v", err)
+	}
+}
@@ -0,0 +1,461 @@
+package namespaces
+
+import (
+	"cloud-app-hive/controllers/errors"
+	"fmt"
+	"reflect"
+
+	"cloud-app-hive/domain"
+	"cloud-app-hive/domain/commands"
+	"cloud-app-hive/domain/repositories"
+	"cloud-app-hive/use_cases"
+	"cloud-app-hive/use_cases/applications"
+	"cloud-app-hive/use_cases/operations"
+)
+
+type ApplyNamespaceManifestUseCase struct {
+	NamespaceRepository          repositories.NamespaceRepository
+	ApplicationRepository        repositories.ApplicationRepository
+	RegistryCredentialRepository repositories.RegistryCredentialRepository
+	// Each application of the manifest goes through the same checks as when it is created, updated or deleted on its own
+	CreateApplicationUseCase         applications.CreateApplicationUseCase
+	UpdateApplicationUseCase         applications.UpdateApplicationUseCase
+	DeleteApplicationUseCase         applications.DeleteApplicationUseCase
+	DeployApplicationUseCase         applications.DeployApplicationUseCase
+	UndeployApplicationUseCase       applications.UndeployApplicationUseCase
+	StartApplicationOperationUseCase operations.StartApplicationOperationUseCase
+	RecordAuditEventUseCase          use_cases.RecordAuditEventUseCase
+}
+
+// Execute creates, updates and optionally prunes the applications of a namespace so that they match the manifest.
+// The changed applications are deployed or removed by operations running in the background, listed in the result.
+// Applying the same manifest twice leaves every application unchanged.
+// A single audit event lists the applications changed by the manifest, even when it is partially applied.
+func (applyNamespaceManifestUseCase ApplyNamespaceManifestUseCase) Execute(applyNamespaceManifest commands.ApplyNamespaceManifest) (applyResult *domain.NamespaceManifestApplyResult, err error) {
+	auditEvent := domain.NewAuditEvent(applyNamespaceManifest.Actor, domain.AuditNamespaceManifestApply, domain.AuditTargetNamespace, applyNamespaceManifest.NamespaceID)
+	auditEvent.NamespaceID = applyNamespaceManifest.NamespaceID
+	defer func() {
+		if applyResult != nil {
+			auditEvent.Changes = domain.NewAuditChanges(nil, map[string][]string{
+				"created": applyResult.Created,
+				"updated": applyResult.Updated,
+				"deleted": applyResult.Deleted,
+			})
+		}
+		applyNamespaceManifestUseCase.RecordAuditEventUseCase.Execute(auditEvent, err)
+	}()
+
+	manifest := applyNamespaceManifest.Manifest
+	if err := manifest.Validate(); err != nil {
+		return nil, err
+	}
+
+	namespace, err := applyNamespaceManifestUseCase.NamespaceRepository.FindByID(applyNamespaceManifest.NamespaceID)
+	if err != nil {
+		return nil, err
+	}
+	if namespace == nil {
+		return nil, errors.NewNamespaceNotFoundByIDError(applyNamespaceManifest.NamespaceID)
+	}
+	auditEvent.TargetName = namespace.Name
+	if manifest.Metadata.Name != "" && manifest.Metadata.Name != namespace.Name {
+		return nil, errors.NewInvalidNamespaceManifestError(
+			fmt.Sprintf("manifest describes namespace %s but is applied to namespace %s", manifest.Metadata.Name, namespace.Name),
+		)
+	}
+
+	// A manifest can update and delete applications of other members, so only admins can apply it
+	if err := namespace.Authorize(applyNamespaceManifest.UserID, domain.PermissionApplyNamespaceManifest); err != nil {
+		return nil, err
+	}
+
+	registryCredentials, err := applyNamespaceManifestUseCase.findRegistryCredentials(namespace.ID, manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	namespaceApplications, err := applyNamespaceManifestUseCase.ApplicationRepository.FindByNamespaceIDAndUserID(namespace.ID)
+	if err != nil {
+		return nil, fmt.Errorf("error while finding applications by namespace id: %w", err)
+	}
+	currentApplications := map[string]domain.Application{}
+	for _, namespaceApplication := range namespaceApplications {
+		application, err := applyNamespaceManifestUseCase.ApplicationRepository.FindByID(namespaceApplication.ID)
+		if err != nil {
+			return nil, fmt.Errorf("error while finding application by id: %w", err)
+		}
+		currentApplications[application.Name] = *application
+	}
+
+	declaredApplications := map[string]bool{}
+	applicationsToCreate := 0
+	for _, applicationManifest := range manifest.Applications {
+		declaredApplications[applicationManifest.Name] = true
+		if _, ok := currentApplications[applicationManifest.Name]; !ok {
+			applicationsToCreate++
+		}
+	}
+
+	var applicationsToDelete []domain.Application
+	for name, application := range currentApplications {
+		if !declaredApplications[name] {
+			applicationsToDelete = append(applicationsToDelete, application)
+		}
+	}
+
+	// Check the limits before touching anything so that a manifest over the limits is rejected as a whole,
+	// a manifest failing later on is partially applied and lists what it changed
+	remainingApplications := len(currentApplications) + applicationsToCreate
+	if applyNamespaceManifest.Prune {
+		remainingApplications -= len(applicationsToDelete)
+	}
+	if remainingApplications > domain.MaxApplicationsByNamespace {
+		return nil, errors.NewNamespaceHasReachedMaxNumberOfApplicationsError(fmt.Sprintf("maximum number of applications reached for namespace %s", namespace.ID))
+	}
+	if applicationsToCreate > 0 {
+		userApplications, err := applyNamespaceManifestUseCase.ApplicationRepository.FindByUserID(applyNamespaceManifest.UserID)
+		if err != nil {
+			return nil, fmt.Errorf("error while finding applications by user id: %w", err)
+		}
+		if len(userApplications)+applicationsToCreate > domain.MaxApplicationsByUser {
+			return nil, errors.NewUserHasReachedMaxNumberOfApplicationsError(fmt.Sprintf("maximum number of applications reached for user %s", applyNamespaceManifest.UserID))
+		}
+	}
+
+	// The routes are checked against the namespace as it is once the manifest is applied,
+	// so that a manifest in which an application serves the routes of another one is rejected before anything changes
+	keptApplications := []domain.Application{}
+	if !applyNamespaceManifest.Prune {
+		keptApplications = applicationsToDelete
+	}
+	if err := validateManifestRoutes(manifest, keptApplications); err != nil {
+		return nil, err
+	}
+
+	result := domain.NamespaceManifestApplyResult{
+		Created:    []string{},
+		Updated:    []string{},
+		Unchanged:  []string{},
+		Deleted:    []string{},
+		Operations: []domain.Operation{},
+	}
+
+	// A pruned application is removed from the cluster as soon as it is deleted,
+	// so that a failure of the rest of the manifest does not leave it running without a row to clean it up
+	if applyNamespaceManifest.Prune {
+		for _, application := range applicationsToDelete {
+			_, err := applyNamespaceManifestUseCase.DeleteApplicationUseCase.Execute(commands.DeleteApplication{
+				ID:     application.ID,
+				UserID: applyNamespaceManifest.UserID,
+				Actor:  applyNamespaceManifest.Actor,
+			})
+			if err != nil {
+				return &result, err
+			}
+			result.Deleted = append(result.Deleted, application.Name)
+
+			operation, err := applyNamespaceManifestUseCase.startUndeployOperation(applyNamespaceManifest, application)
+			if err != nil {
+				return &result, err
+			}
+			result.Operations = append(result.Operations, *operation)
+		}
+	}
+
+	// Every application is stored before any is deployed, so that the applications of the manifest can depend on each other
+	operationTypes := map[string]domain.OperationType{}
+	for _, applicationManifest := range manifest.Applications {
+		registryCredential := registryCredentials[applicationManifest.RegistryCredential]
+		currentApplication, exists := currentApplications[applicationManifest.Name]
+		if !exists {
+			application, err := applyNamespaceManifestUseCase.create(applyNamespaceManifest, applicationManifest, registryCredential)
+			if err != nil {
+				return &result, err
+			}
+			result.Created = append(result.Created, applicationManifest.Name)
+			operationTypes[application.ID] = domain.DeployOperation
+			continue
+		}
+
+		updated, err := applyNamespaceManifestUseCase.update(applyNamespaceManifest, applicationManifest, registryCredential, currentApplication)
+		if err != nil {
+			return &result, err
+		}
+		if !updated {
+			result.Unchanged = append(result.Unchanged, applicationManifest.Name)
+			continue
+		}
+		result.Updated = append(result.Updated, applicationManifest.Name)
+		operationTypes[currentApplication.ID] = domain.UpdateOperation
+	}
+
+	namespaceApplications, err = applyNamespaceManifestUseCase.ApplicationRepository.FindByNamespaceIDAndUserID(namespace.ID)
+	if err != nil {
+		return &result, fmt.Errorf("error while finding applications by namespace id: %w", err)
+	}
+	for _, applicationName := range append(result.Created, result.Updated...) {
+		application := findApplicationByName(namespaceApplications, applicationName)
+		if application == nil {
+			return &result, fmt.Errorf("application %s not found once applied", applicationName)
+		}
+		application, err := applyNamespaceManifestUseCase.resolveDependencies(application.ID, namespaceApplications)
+		if err != nil {
+			return &result, err
+		}
+		operation, err := applyNamespaceManifestUseCase.startDeployOperation(applyNamespaceManifest, operationTypes[application.ID], *application, namespace.Name)
+		if err != nil {
+			return &result, err
+		}
+		result.Operations = append(result.Operations, *operation)
+	}
+
+	return &result, nil
+}
+
+// create creates the application of the manifest without deploying it
+func (applyNamespaceManifestUseCase ApplyNamespaceManifestUseCase) create(applyNamespaceManifest commands.ApplyNamespaceManifest, applicationManifest domain.ApplicationManifest, registryCredential *domain.RegistryCredential) (*domain.Application, error) {
+	application, _, err := applyNamespaceManifestUseCase.CreateApplicationUseCase.Execute(commands.CreateApplication{
+		UserID:                    applyNamespaceManifest.UserID,
+		Name:                      applicationManifest.Name,
+		Description:               applicationManifest.Description,
+		Image:                     applicationManifest.Image,
+		Registry:                  applicationManifest.Registry,
+		RegistryCredentialID:      registryCredentialID(registryCredential),
+		PinImageDigest:            applicationManifest.PinImageDigest,
+		NamespaceID:               applyNamespaceManifest.NamespaceID,
+		Port:                      applicationManifest.Port,
+		Zone:                      applicationManifest.Zone,
+		ApplicationType:           applicationManifest.ApplicationType,
+		EnvironmentVariables:      nonNilEnvironmentVariables(applicationManifest.EnvironmentVariables),
+		Secrets:                   manifestSecrets(applicationManifest),
+		ContainerSpecifications:   applicationManifest.ContainerSpecifications,
+		ScalabilitySpecifications: applicationManifest.ScalabilitySpecifications,
+		Ingress:                   manifestIngress(applicationManifest),
+		Dependencies:              domain.NewApplicationDependencies(applicationManifest.Dependencies),
+		AdministratorEmail:        applicationManifest.AdministratorEmail,
+		Actor:                     applyNamespaceManifest.Actor,
+	})
+	return application, err
+}
+
+// update updates the application of the manifest without deploying it, it returns false when nothing changes
+func (applyNamespaceManifestUseCase ApplyNamespaceManifestUseCase) update(applyNamespaceManifest commands.ApplyNamespaceManifest, applicationManifest domain.ApplicationManifest, registryCredential *domain.RegistryCredential, currentApplication domain.Application) (bool, error) {
+	updateApplication := commands.UpdateApplication{
+		UserID:                    applyNamespaceManifest.UserID,
+		Description:               applicationManifest.Description,
+		Image:                     applicationManifest.Image,
+		Registry:                  applicationManifest.Registry,
+		RegistryCredentialID:      registryCredentialID(registryCredential),
+		PinImageDigest:            applicationManifest.PinImageDigest,
+		Port:                      applicationManifest.Port,
+		ApplicationType:           applicationManifest.ApplicationType,
+		EnvironmentVariables:      nonNilEnvironmentVariables(applicationManifest.EnvironmentVariables),
+		Secrets:                   manifestSecrets(applicationManifest),
+		ContainerSpecifications:   applicationManifest.ContainerSpecifications,
+		ScalabilitySpecifications: applicationManifest.ScalabilitySpecifications,
+		Ingress:                   manifestIngress(applicationManifest),
+		Dependencies:              domain.NewApplicationDependencies(applicationManifest.Dependencies),
+		AdministratorEmail:        applicationManifest.AdministratorEmail,
+		Actor:                     applyNamespaceManifest.Actor,
+	}
+
+	// The dry run resolves the image digest, the secrets and the dependencies the update would store
+	updateApplication.DryRun = true
+	updatedApplication, _, err := applyNamespaceManifestUseCase.UpdateApplicationUseCase.Execute(currentApplication.ID, updateApplication, applyNamespaceManifest.UserID)
+	if err != nil {
+		return false, err
+	}
+	if applicationsMatch(currentApplication, *updatedApplication) {
+		return false, nil
+	}
+
+	updateApplication.DryRun = false
+	if _, _, err := applyNamespaceManifestUseCase.UpdateApplicationUseCase.Execute(currentApplication.ID, updateApplication, applyNamespaceManifest.UserID); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// resolveDependencies stores the ports of the dependencies of an application once every application of the manifest is stored, and returns the application
+func (applyNamespaceManifestUseCase ApplyNamespaceManifestUseCase) resolveDependencies(applicationID string, namespaceApplications []domain.Application) (*domain.Application, error) {
+	application, err := applyNamespaceManifestUseCase.ApplicationRepository.FindByID(applicationID)
+	if err != nil {
+		return nil, fmt.Errorf("error while finding application by id: %w", err)
+	}
+	if application.Dependencies == nil || len(*application.Dependencies) == 0 {
+		return application, nil
+	}
+	dependencies := application.Dependencies.Resolve(namespaceApplications)
+	if reflect.DeepEqual(dependencies, *application.Dependencies) {
+		return application, nil
+	}
+	if err := applyNamespaceManifestUseCase.ApplicationRepository.UpdateDependencies(application.ID, dependencies); err != nil {
+		return nil, err
+	}
+	application.Dependencies = &dependencies
+	return application, nil
+}
+
+// startUndeployOperation removes a deleted application from the cluster in the background
+func (applyNamespaceManifestUseCase ApplyNamespaceManifestUseCase) startUndeployOperation(applyNamespaceManifest commands.ApplyNamespaceManifest, application domain.Application) (*domain.Operation, error) {
+	return applyNamespaceManifestUseCase.StartApplicationOperationUseCase.Execute(commands.StartApplicationOperation{
+		Type:          domain.DeleteOperation,
+		Application:   application,
+		NamespaceName: application.Namespace.Name,
+		UserID:        applyNamespaceManifest.UserID,
+		Run: func(reporter domain.OperationStepReporter) error {
+			return applyNamespaceManifestUseCase.UndeployApplicationUseCase.Execute(commands.UndeployApplication{
+				Application:  application,
+				Actor:        applyNamespaceManifest.Actor,
+				StepReporter: reporter,
+			})
+		},
+	})
+}
+
+// startDeployOperation deploys the stored application in the background, the applications depending on it are refreshed once it is deployed
+func (applyNamespaceManifestUseCase ApplyNamespaceManifestUseCase) startDeployOperation(applyNamespaceManifest commands.ApplyNamespaceManifest, operationType domain.OperationType, application domain.Application, namespaceName string) (*domain.Operation, error) {
+	return applyNamespaceManifestUseCase.StartApplicationOperationUseCase.Execute(commands.StartApplicationOperation{
+		Type:          operationType,
+		Application:   application,
+		NamespaceName: namespaceName,
+		UserID:        applyNamespaceManifest.UserID,
+		Run: func(reporter domain.OperationStepReporter) error {
+			return applyNamespaceManifestUseCase.DeployApplicationUseCase.Execute(commands.DeployApplication{
+				Application:   application,
+				NamespaceName: namespaceName,
+				Actor:         applyNamespaceManifest.Actor,
+				StepReporter:  reporter,
+			})
+		},
+	})
+}
+
+// findRegistryCredentials returns the registry credentials referenced by name in the manifest,
+// or an error if one of them is not a credential of the namespace
+func (applyNamespaceManifestUseCase ApplyNamespaceManifestUseCase) findRegistryCredentials(namespaceID string, manifest domain.NamespaceManifest) (map[string]*domain.RegistryCredential, error) {
+	registryCredentialsByName := map[string]*domain.RegistryCredential{}
+	usesRegistryCredentials := false
+	for _, applicationManifest := range manifest.Applications {
+		usesRegistryCredentials = usesRegistryCredentials || applicationManifest.RegistryCredential != ""
+	}
+	if !usesRegistryCredentials {
+		return registryCredentialsByName, nil
+	}
+
+	registryCredentials, err := applyNamespaceManifestUseCase.RegistryCredentialRepository.FindByNamespaceID(namespaceID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range registryCredentials {
+		registryCredentialsByName[registryCredentials[i].Name] = &registryCredentials[i]
+	}
+	for _, applicationManifest := range manifest.Applications {
+		if applicationManifest.RegistryCredential != "" && registryCredentialsByName[applicationManifest.RegistryCredential] == nil {
+			return nil, errors.NewInvalidNamespaceManifestError(
+				fmt.Sprintf("application %s: namespace %s has no registry credential named %s", applicationManifest.Name, namespaceID, applicationManifest.RegistryCredential),
+			)
+		}
+	}
+	return registryCredentialsByName, nil
+}
+
+// validateManifestRoutes checks that no application of the manifest serves the routes of another application of the manifest or of the kept ones
+func validateManifestRoutes(manifest domain.NamespaceManifest, keptApplications []domain.Application) error {
+	namespaceApplications := append([]domain.Application{}, keptApplications...)
+	for _, applicationManifest := range manifest.Applications {
+		ingress := manifestIngress(applicationManifest)
+		namespaceApplications = append(namespaceApplications, domain.Application{Name: applicationManifest.Name, Port: applicationManifest.Port, Ingress: &ingress})
+	}
+	for _, applicationManifest := range manifest.Applications {
+		if err := manifestIngress(applicationManifest).ValidateRoutesAgainst(applicationManifest.Name, namespaceApplications); err != nil {
+			return errors.NewInvalidApplicationIngressError(fmt.Sprintf("application %s: %s", applicationManifest.Name, err.Error()))
+		}
+	}
+	return nil
+}
+
+func findApplicationByName(applications []domain.Application, name string) *domain.Application {
+	for i := range applications {
+		if applications[i].Name == name {
+			return &applications[i]
+		}
+	}
+	return nil
+}
+
+func registryCredentialID(registryCredential *domain.RegistryCredential) *string {
+	if registryCredential == nil {
+		return nil
+	}
+	return &registryCredential.ID
+}
+
+// applicationsMatch returns true if the updated application has the same fields as the current one.
+// Stored secret values are encrypted, so a manifest giving secret values is always considered as an update,
+// while secrets referenced by name only keep the stored ones and compare equal.
+// The same goes for the basic auth password of the ingress.
+func applicationsMatch(currentApplication domain.Application, updatedApplication domain.Application) bool {
+	return reflect.DeepEqual(comparableApplication(currentApplication), comparableApplication(updatedApplication))
+}
+
+// comparableApplication returns the fields of an application a manifest describes, with empty values instead of nil ones
+func comparableApplication(application domain.Application) commands.UpdateApplication {
+	comparable := commands.UpdateApplication{
+		Description:          application.Description,
+		Image:                application.Image,
+		Registry:             application.Registry,
+		RegistryCredentialID: application.RegistryCredentialID,
+		ImageDigest:          application.ImageDigest,
+		PinImageDigest:       application.PinImageDigest,
+		Port:                 application.Port,
+		ApplicationType:      application.ApplicationType,
+		EnvironmentVariables: domain.ApplicationEnvironmentVariables{},
+		Secrets:              domain.ApplicationSecrets{},
+		Dependencies:         domain.ApplicationDependencies{},
+		AdministratorEmail:   application.AdministratorEmail,
+	}
+	if application.EnvironmentVariables != nil {
+		comparable.EnvironmentVariables = nonNilEnvironmentVariables(*application.EnvironmentVariables)
+	}
+	if application.Secrets != nil && len(*application.Secrets) > 0 {
+		comparable.Secrets = *application.Secrets
+	}
+	if application.ContainerSpecifications != nil {
+		comparable.ContainerSpecifications = application.ContainerSpecifications.Data()
+	}
+	if application.ScalabilitySpecifications != nil {
+		comparable.ScalabilitySpecifications = application.ScalabilitySpecifications.Data()
+	}
+	if application.Ingress != nil {
+		comparable.Ingress = *application.Ingress
+	}
+	if application.Dependencies != nil && len(*application.Dependencies) > 0 {
+		comparable.Dependencies = *application.Dependencies
+	}
+	return comparable
+}
+
+// manifestSecrets returns the secrets of the manifest, the ones without value nor reference keep their stored value
+func manifestSecrets(applicationManifest domain.ApplicationManifest) domain.ApplicationSecrets {
+	secrets := domain.ApplicationSecrets{}
+	for _, secret := range applicationManifest.Secrets {
+		secrets = append(secrets, domain.ApplicationSecret{Name: secret.Name, Val: secret.Value, Reference: secret.Reference})
+	}
+	return secrets
+}
+
+// manifestIngress returns the ingress of the manifest, a basic auth without password keeps the stored one
+func manifestIngress(applicationManifest domain.ApplicationManifest) domain.ApplicationIngress {
+	if applicationManifest.Ingress == nil {
+		return domain.ApplicationIngress{}
+	}
+	return *applicationManifest.Ingress
+}
+
+func nonNilEnvironmentVariables(environmentVariables domain.ApplicationEnvironmentVariables) domain.ApplicationEnvironmentVariables {
+	if environmentVariables == nil {
+		return domain.ApplicationEnvironmentVariables{}
+	}
+	return environmentVariables
+}
@@ -0,0 +1,627 @@
+package namespaces
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	customErrors "cloud-app-hive/controllers/errors"
+	"cloud-app-hive/domain"
+	"cloud-app-hive/domain/commands"
+	"cloud-app-hive/use_cases"
+	"cloud-app-hive/use_cases/applications"
+	"cloud-app-hive/use_cases/operations"
+
+	"gorm.io/datatypes"
+)
+
+// MockApplicationRepository is a mock implementation of the ApplicationRepository interface
+type MockApplicationRepository struct {
+	FindApplicationsFunc              func(findApplications commands.FindApplications) ([]domain.Application, error)
+	FindByIDFunc                      func(id string) (*domain.Application, error)
+	FindByUserIDFunc                  func(userID string) ([]domain.Application, error)
+	FindByNamespaceIDAndUserIDFunc    func(namespaceID string) ([]domain.Application, error)
+	FindByNamespaceIDAndNameFunc      func(namespaceID string, name string) (*domain.Application, error)
+	CreateFunc                        func(application commands.CreateApplication) (*domain.Application, error)
+	UpdateFunc                        func(applicationID string, application commands.UpdateApplication) (*domain.Application, error)
+	FindAllFunc                       func() ([]domain.Application, error)
+	FindWithSecretsFunc               func() ([]domain.Application, error)
+	UpdateSecretsFunc                 func(applicationID string, namespaceID string, secrets domain.ApplicationSecrets) error
+	UpdateConfigFilesFunc             func(applicationID string, namespaceID string, configFiles domain.ApplicationConfigFiles) error
+	UpdateDependenciesFunc            func(applicationID string, dependencies domain.ApplicationDependencies) error
+	DeleteFunc                        func(id string) (*domain.Application, error)
+	FindManualScalingApplicationsFunc func() ([]domain.Application, error)
+	FindAutoScalingApplicationsFunc   func() ([]domain.Application, error)
+	HorizontalScaleUpFunc             func(applicationID string) (*domain.Application, error)
+	HorizontalScaleDownFunc           func(applicationID string) (*domain.Application, error)
+	VerticalScaleUpFunc               func(applicationID string) (*domain.Application, error)
+}
+
+func (m *MockApplicationRepository) FindApplications(findApplications commands.FindApplications) ([]domain.Application, error) {
+	return m.FindApplicationsFunc(findApplications)
+}
+
+func (m *MockApplicationRepository) FindByID(id string) (*domain.Application, error) {
+	return m.FindByIDFunc(id)
+}
+
+func (m *MockApplicationRepository) FindByUserID(userID string) ([]domain.Application, error) {
+	return m.FindByUserIDFunc(userID)
+}
+
+func (m *MockApplicationRepository) FindByNamespaceIDAndUserID(namespaceID string) ([]domain.Application, error) {
+	return m.FindByNamespaceIDAndUserIDFunc(namespaceID)
+}
+
+func (m *MockApplicationRepository) FindByNamespaceIDAndName(namespaceID string, name string) (*domain.Application, error) {
+	return m.FindByNamespaceIDAndNameFunc(namespaceID, name)
+}
+
+func (m *MockApplicationRepository) Create(application commands.CreateApplication) (*domain.Application, error) {
+	return m.CreateFunc(application)
+}
+
+func (m *MockApplicationRepository) Update(applicationID string, application commands.UpdateApplication) (*domain.Application, error) {
+	return m.UpdateFunc(applicationID, application)
+}
+
+func (m *MockApplicationRepository) FindAll() ([]domain.Application, error) {
+	return m.FindAllFunc()
+}
+
+func (m *MockApplicationRepository) FindWithSecrets() ([]domain.Application, error) {
+	return m.FindWithSecretsFunc()
+}
+
+func (m *MockApplicationRepository) UpdateSecrets(applicationID string, namespaceID string, secrets domain.ApplicationSecrets) error {
+	return m.UpdateSecretsFunc(applicationID, namespaceID, secrets)
+}
+
+func (m *MockApplicationRepository) UpdateConfigFiles(applicationID string, namespaceID string, configFiles domain.ApplicationConfigFiles) error {
+	return m.UpdateConfigFilesFunc(applicationID, namespaceID, configFiles)
+}
+
+func (m *MockApplicationRepository) UpdateDependencies(applicationID string, dependencies domain.ApplicationDependencies) error {
+	return m.UpdateDependenciesFunc(applicationID, dependencies)
+}
+
+func (m *MockApplicationRepository) Delete(id string) (*domain.Application, error) {
+	return m.DeleteFunc(id)
+}
+
+func (m *MockApplicationRepository) FindManualScalingApplications() ([]domain.Application, error) {
+	return m.FindManualScalingApplicationsFunc()
+}
+
+func (m *MockApplicationRepository) FindAutoScalingApplications() ([]domain.Application, error) {
+	return m.FindAutoScalingApplicationsFunc()
+}
+
+func (m *MockApplicationRepository) HorizontalScaleUp(applicationID string) (*domain.Application, error) {
+	return m.HorizontalScaleUpFunc(applicationID)
+}
+
+func (m *MockApplicationRepository) HorizontalScaleDown(applicationID string) (*domain.Application, error) {
+	return m.HorizontalScaleDownFunc(applicationID)
+}
+
+func (m *MockApplicationRepository) VerticalScaleUp(applicationID string) (*domain.Application, error) {
+	return m.VerticalScaleUpFunc(applicationID)
+}
+
+// MockContainerManagerRepository is a mock implementation of the ContainerManagerRepository interface,
+// applications can be applied and unapplied by background operations
+type MockContainerManagerRepository struct {
+	mutex                 sync.Mutex
+	AppliedApplications   []string
+	UnappliedApplications []string
+	RestartedApplications []string
+	AppliedNetworkRules   []commands.ApplyNetworkRules
+	AppliedAddons         []commands.ApplyAddon
+	UnappliedAddons       []string
+	AppliedConfigGroups   []commands.ApplyConfigGroup
+	UnappliedConfigGroups []string
+}
+
+func (m *MockContainerManagerRepository) GetApplicationMetrics(application commands.GetApplicationMetrics) ([]domain.ApplicationMetrics, error) {
+	return nil, nil
+}
+
+func (m *MockContainerManagerRepository) ApplyApplication(applyApplication commands.ApplyApplication) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.AppliedApplications = append(m.AppliedApplications, applyApplication.Name)
+	return nil
+}
+
+func (m *MockContainerManagerRepository) DryRunApplication(applyApplication commands.ApplyApplication) ([]domain.KubernetesObjectDryRun, error) {
+	return nil, nil
+}
+
+func (m *MockContainerManagerRepository) GetApplicationLogs(application commands.GetApplicationLogs) ([]domain.ApplicationLogs, error) {
+	return nil, nil
+}
+
+func (m *MockContainerManagerRepository) GetApplicationEvents(application commands.GetApplicationEvents) ([]domain.ApplicationEvent, error) {
+	return nil, nil
+}
+
+func (m *MockContainerManagerRepository) GetApplicationStatus(application commands.GetApplicationStatus) (*domain.ApplicationStatus, error) {
+	// Applications without desired replicas are rolled out
+	return &domain.ApplicationStatus{}, nil
+}
+
+func (m *MockContainerManagerRepository) RefreshApplicationSecrets(applyApplication commands.ApplyApplication) (bool, error) {
+	return false, nil
+}
+
+func (m *MockContainerManagerRepository) RestartApplication(restartApplication commands.RestartApplication) error {
+	m.RestartedApplications = append(m.RestartedApplications, restartApplication.Name)
+	return nil
+}
+
+func (m *MockContainerManagerRepository) ApplyNetworkRules(applyNetworkRules commands.ApplyNetworkRules) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.AppliedNetworkRules = append(m.AppliedNetworkRules, applyNetworkRules)
+	return nil
+}
+
+func (m *MockContainerManagerRepository) UnapplyApplication(unapplyApplication commands.UnapplyApplication) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.UnappliedApplications = append(m.UnappliedApplications, unapplyApplication.Name)
+	return nil
+}
+
+func (m *MockContainerManagerRepository) ApplyAddon(applyAddon commands.ApplyAddon) error {
+	m.AppliedAddons = append(m.AppliedAddons, applyAddon)
+	return nil
+}
+
+func (m *MockContainerManagerRepository) UnapplyAddon(unapplyAddon commands.UnapplyAddon) error {
+	m.UnappliedAddons = append(m.UnappliedAddons, unapplyAddon.Name)
+	return nil
+}
+
+func (m *MockContainerManagerRepository) ApplyConfigGroup(applyConfigGroup commands.ApplyConfigGroup) error {
+	m.AppliedConfigGroups = append(m.AppliedConfigGroups, applyConfigGroup)
+	return nil
+}
+
+func (m *MockContainerManagerRepository) UnapplyConfigGroup(unapplyConfigGroup commands.UnapplyConfigGroup) error {
+	m.UnappliedConfigGroups = append(m.UnappliedConfigGroups, unapplyConfigGroup.Name)
+	return nil
+}
+
+func (m *MockContainerManagerRepository) DeleteNamespace(namespace string) error {
+	return nil
+}
+
+func (m *MockContainerManagerRepository) GetClusterMetrics() (*domain.ClusterMetrics, error) {
+	return nil, nil
+}
+
+// newInMemoryApplicationRepository returns a mock repository that keeps applications in a map
+func newInMemoryApplicationRepository(applications map[string]*domain.Application) *MockApplicationRepository {
+	list := func() []domain.Application {
+		var found []domain.Application
+		for _, application := range applications {
+			found = append(found, *application)
+		}
+		return found
+	}
+	return &MockApplicationRepository{
+		// A copy is returned, as the database would, so that dry runs do not change the stored application
+		FindByIDFunc: func(id string) (*domain.Application, error) {
+			application, ok := applications[id]
+			if !ok {
+				return nil, nil
+			}
+			found := *application
+			return &found, nil
+		},
+		FindByUserIDFunc: func(userID string) ([]domain.Application, error) {
+			return list(), nil
+		},
+		FindByNamespaceIDAndUserIDFunc: func(namespaceID string) ([]domain.Application, error) {
+			return list(), nil
+		},
+		CreateFunc: func(createApplication commands.CreateApplication) (*domain.Application, error) {
+			containerSpecs := datatypes.NewJSONType(createApplication.ContainerSpecifications)
+			scalabilitySpecs := datatypes.NewJSONType(createApplication.ScalabilitySpecifications)
+			application := &domain.Application{
+				ID:                        createApplication.Name + "-id",
+				Name:                      createApplication.Name,
+				UserID:                    createApplication.UserID,
+				NamespaceID:               createApplication.NamespaceID,
+				Namespace:                 newTestNamespace(createApplication.NamespaceID),
+				Description:               createApplication.Description,
+				Image:                     createApplication.Image,
+				ImageDigest:               createApplication.ImageDigest,
+				PinImageDigest:            createApplication.PinImageDigest,
+				Registry:                  createApplication.Registry,
+				Port:                      createApplication.Port,
+				ApplicationType:           createApplication.ApplicationType,
+				EnvironmentVariables:      &createApplication.EnvironmentVariables,
+				Secrets:                   &createApplication.Secrets,
+				ContainerSpecifications:   &containerSpecs,
+				ScalabilitySpecifications: &scalabilitySpecs,
+				Dependencies:              &createApplication.Dependencies,
+				AdministratorEmail:        createApplication.AdministratorEmail,
+			}
+			applications[application.ID] = application
+			return application, nil
+		},
+		UpdateFunc: func(applicationID string, updateApplication commands.UpdateApplication) (*domain.Application, error) {
+			application := applications[applicationID]
+			application.Image = updateApplication.Image
+			application.ImageDigest = updateApplication.ImageDigest
+			application.PinImageDigest = updateApplication.PinImageDigest
+			application.Secrets = &updateApplication.Secrets
+			application.Dependencies = &updateApplication.Dependencies
+			return application, nil
+		},
+		UpdateDependenciesFunc: func(applicationID string, dependencies domain.ApplicationDependencies) error {
+			applications[applicationID].Dependencies = &dependencies
+			return nil
+		},
+		DeleteFunc: func(id string) (*domain.Application, error) {
+			application := applications[id]
+			delete(applications, id)
+			return application, nil
+		},
+	}
+}
+
+func newTestManifest(applicationNames ...string) domain.NamespaceManifest {
+	manifest := domain.NamespaceManifest{
+		APIVersion: domain.NamespaceManifestAPIVersion,
+		Kind:       domain.NamespaceManifestKind,
+		Metadata:   domain.NamespaceManifestMetadata{Name: "team"},
+	}
+	for _, name := range applicationNames {
+		manifest.Applications = append(manifest.Applications, domain.ApplicationManifest{
+			Name:            name,
+			Image:           "nginx:latest",
+			Registry:        domain.DockerHubRegistry,
+			Port:            80,
+			ApplicationType: domain.SingleInstance,
+			Secrets:         []domain.ApplicationManifestSecret{{Name: "TOKEN", Value: "secret"}},
+			ContainerSpecifications: domain.ApplicationContainerSpecifications{
+				CPULimit:    &domain.ContainerCpuLimit{Val: 70, Unit: "mCPU"},
+				MemoryLimit: &domain.ContainerMemoryLimit{Val: 128, Unit: domain.MB},
+			},
+			ScalabilitySpecifications: domain.ApplicationScalabilitySpecifications{Replicas: 1},
+			AdministratorEmail:        "admin@example.com",
+		})
+	}
+	return manifest
+}
+
+func newTestNamespace(id string) domain.Namespace {
+	return domain.Namespace{
+		ID:          id,
+		Name:        "team",
+		Memberships: []domain.NamespaceMembership{{UserID: "admin", Role: domain.RoleAdmin}, {UserID: "member", Role: domain.RoleMember}},
+	}
+}
+
+func newTestNamespaceRepository() *MockNamespaceRepository {
+	return &MockNamespaceRepository{
+		FindByIDFunc: func(id string) (*domain.Namespace, error) {
+			namespace := newTestNamespace(id)
+			return &namespace, nil
+		},
+	}
+}
+
+// MockOperationRepository keeps the operations in memory, they are updated by the background operations
+type MockOperationRepository struct {
+	mutex      sync.Mutex
+	Operations map[string]domain.Operation
+}
+
+func (m *MockOperationRepository) Create(operation domain.Operation) (*domain.Operation, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if m.Operations == nil {
+		m.Operations = map[string]domain.Operation{}
+	}
+	operation.ID = fmt.Sprintf("operation-%d", len(m.Operations)+1)
+	m.Operations[operation.ID] = operation
+	return &operation, nil
+}
+
+func (m *MockOperationRepository) FindByID(id string) (*domain.Operation, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	operation, ok := m.Operations[id]
+	if !ok {
+		return nil, nil
+	}
+	return &operation, nil
+}
+
+func (m *MockOperationRepository) FindUnfinished() ([]domain.Operation, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	operations := []domain.Operation{}
+	for _, operation := range m.Operations {
+		if !operation.IsFinished() {
+			operations = append(operations, operation)
+		}
+	}
+	return operations, nil
+}
+
+func (m *MockOperationRepository) Update(operation domain.Operation) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.Operations[operation.ID] = operation
+	return nil
+}
+
+// waitForOperations waits until the started operations are finished and returns them
+func waitForOperations(t *testing.T, operationRepository *MockOperationRepository, startedOperations []domain.Operation) []domain.Operation {
+	t.Helper()
+	finishedOperations := []domain.Operation{}
+	for _, operation := range startedOperations {
+		deadline := time.Now().Add(5 * time.Second)
+		for {
+			found, _ := operationRepository.FindByID(operation.ID)
+			if found.IsFinished() {
+				finishedOperations = append(finishedOperations, *found)
+				break
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("operation %s did not finish", operation.ID)
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	}
+	return finishedOperations
+}
+
+// newTestApplyNamespaceManifestUseCase builds the use case with the application use cases it goes through,
+// the background operations refresh the dependents of the applications with their own empty repository
+func newTestApplyNamespaceManifestUseCase(applicationRepository *MockApplicationRepository, containerManager *MockContainerManagerRepository, operationRepository *MockOperationRepository, auditEventRepository *MockAuditEventRepository) ApplyNamespaceManifestUseCase {
+	namespaceRepository := newTestNamespaceRepository()
+	recordAuditEventUseCase := newTestRecordAuditEventUseCase(auditEventRepository)
+	refreshApplicationDependentsUseCase := applications.RefreshApplicationDependentsUseCase{
+		ApplicationRepository: &MockApplicationRepository{
+			FindByNamespaceIDAndUserIDFunc: func(namespaceID string) ([]domain.Application, error) {
+				return nil, nil
+			},
+		},
+	}
+	return ApplyNamespaceManifestUseCase{
+		NamespaceRepository:   namespaceRepository,
+		ApplicationRepository: applicationRepository,
+		CreateApplicationUseCase: applications.CreateApplicationUseCase{
+			NamespaceRepository:     namespaceRepository,
+			ApplicationRepository:   applicationRepository,
+			RecordAuditEventUseCase: recordAuditEventUseCase,
+		},
+		UpdateApplicationUseCase: applications.UpdateApplicationUseCase{
+			NamespaceRepository:     namespaceRepository,
+			ApplicationRepository:   applicationRepository,
+			RecordAuditEventUseCase: recordAuditEventUseCase,
+		},
+		DeleteApplicationUseCase: applications.DeleteApplicationUseCase{
+			ApplicationRepository:   applicationRepository,
+			RecordAuditEventUseCase: recordAuditEventUseCase,
+		},
+		DeployApplicationUseCase: applications.DeployApplicationUseCase{
+			ContainerManagerRepository: containerManager,
+			ApplyNetworkRulesUseCase: use_cases.ApplyNetworkRulesUseCase{
+				NamespaceRepository:        namespaceRepository,
+				NetworkRuleRepository:      &MockNetworkRuleRepository{},
+				ContainerManagerRepository: containerManager,
+			},
+			RefreshApplicationDependentsUseCase: refreshApplicationDependentsUseCase,
+			RecordAuditEventUseCase:             recordAuditEventUseCase,
+		},
+		UndeployApplicationUseCase: applications.UndeployApplicationUseCase{
+			ContainerManagerRepository:          containerManager,
+			RefreshApplicationDependentsUseCase: refreshApplicationDependentsUseCase,
+			RecordAuditEventUseCase:             recordAuditEventUseCase,
+		},
+		StartApplicationOperationUseCase: operations.StartApplicationOperationUseCase{
+			OperationRepository:        operationRepository,
+			ContainerManagerRepository: containerManager,
+			RolloutPollInterval:        time.Millisecond,
+		},
+		RecordAuditEventUseCase: recordAuditEventUseCase,
+	}
+}
+
+func TestExecute_ApplyNamespaceManifest_IsIdempotent(t *testing.T) {
+	containerManager := &MockContainerManagerRepository{}
+	operationRepository := &MockOperationRepository{}
+	useCase := newTestApplyNamespaceManifestUseCase(newInMemoryApplicationRepository(map[string]*domain.Application{}), containerManager, operationRepository, &MockAuditEventRepository{})
+	applyNamespaceManifest := commands.ApplyNamespaceManifest{
+		NamespaceID: "namespace-id",
+		UserID:      "admin",
+		Manifest:    newTestManifest("frontend", "backend"),
+	}
+
+	result, err := useCase.Execute(applyNamespaceManifest)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(result.Created) != 2 || len(result.Operations) != 2 {
+		t.Fatalf("Expected 2 created applications deployed by 2 operations, got %+v", result)
+	}
+	for _, operation := range waitForOperations(t, operationRepository, result.Operations) {
+		if operation.Type != domain.DeployOperation || operation.Status != domain.OperationSucceeded {
+			t.Errorf("Expected a succeeded deploy operation, got %+v", operation)
+		}
+	}
+	if len(containerManager.AppliedApplications) != 2 {
+		t.Fatalf("Expected 2 applied applications, got %v", containerManager.AppliedApplications)
+	}
+
+	// Exported manifests carry secret names only, the stored values must be kept
+	applyNamespaceManifest.Manifest.Applications[0].Secrets[0].Value = ""
+	applyNamespaceManifest.Manifest.Applications[1].Secrets[0].Value = ""
+	result, err = useCase.Execute(applyNamespaceManifest)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(result.Unchanged) != 2 || len(result.Created) != 0 || len(result.Updated) != 0 {
+		t.Errorf("Expected 2 unchanged applications, got %+v", result)
+	}
+	if len(result.Operations) != 0 {
+		t.Errorf("Expected no new deployment, got %+v", result.Operations)
+	}
+}
+
+func TestExecute_ApplyNamespaceManifest_UpdatesAndPrunes(t *testing.T) {
+	containerManager := &MockContainerManagerRepository{}
+	operationRepository := &MockOperationRepository{}
+	auditEventRepository := &MockAuditEventRepository{}
+	useCase := newTestApplyNamespaceManifestUseCase(newInMemoryApplicationRepository(map[string]*domain.Application{}), containerManager, operationRepository, auditEventRepository)
+	result, err := useCase.Execute(commands.ApplyNamespaceManifest{NamespaceID: "namespace-id", UserID: "admin", Manifest: newTestManifest("frontend", "backend")})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	waitForOperations(t, operationRepository, result.Operations)
+
+	manifest := newTestManifest("frontend")
+	manifest.Applications[0].Image = "nginx:1.25"
+	result, err = useCase.Execute(commands.ApplyNamespaceManifest{NamespaceID: "namespace-id", UserID: "admin", Manifest: manifest, Prune: true})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(result.Updated) != 1 || result.Updated[0] != "frontend" {
+		t.Errorf("Expected frontend to be updated, got %+v", result)
+	}
+	if len(result.Deleted) != 1 || result.Deleted[0] != "backend" {
+		t.Errorf("Expected backend to be pruned, got %+v", result)
+	}
+	operationTypes := map[domain.OperationType]string{}
+	for _, operation := range waitForOperations(t, operationRepository, result.Operations) {
+		operationTypes[operation.Type] = operation.ApplicationName
+	}
+	if operationTypes[domain.UpdateOperation] != "frontend" || operationTypes[domain.DeleteOperation] != "backend" {
+		t.Errorf("Expected an update operation of frontend and a delete operation of backend, got %v", operationTypes)
+	}
+	if len(containerManager.UnappliedApplications) != 1 {
+		t.Errorf("Expected backend to be unapplied, got %v", containerManager.UnappliedApplications)
+	}
+
+	// Each application change is audited as if it was made on its own
+	auditedActions := map[domain.AuditAction]int{}
+	for _, auditEvent := range auditEventRepository.AuditEvents {
+		auditedActions[auditEvent.Action]++
+	}
+	if auditedActions[domain.AuditApplicationCreate] != 2 || auditedActions[domain.AuditApplicationUpdate] != 1 || auditedActions[domain.AuditApplicationDelete] != 1 {
+		t.Errorf("Expected the created, updated and deleted applications to be audited, got %v", auditedActions)
+	}
+}
+
+func TestExecute_ApplyNamespaceManifest_UndeploysPrunedApplicationsWhenACreationFails(t *testing.T) {
+	containerManager := &MockContainerManagerRepository{}
+	operationRepository := &MockOperationRepository{}
+	applicationRepository := newInMemoryApplicationRepository(map[string]*domain.Application{})
+	useCase := newTestApplyNamespaceManifestUseCase(applicationRepository, containerManager, operationRepository, &MockAuditEventRepository{})
+	result, err := useCase.Execute(commands.ApplyNamespaceManifest{NamespaceID: "namespace-id", UserID: "admin", Manifest: newTestManifest("frontend", "backend")})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	waitForOperations(t, operationRepository, result.Operations)
+
+	createApplication := applicationRepository.CreateFunc
+	applicationRepository.CreateFunc = func(application commands.CreateApplication) (*domain.Application, error) {
+		if application.Name == "worker" {
+			return nil, fmt.Errorf("database unavailable")
+		}
+		return createApplication(application)
+	}
+	result, err = useCase.Execute(commands.ApplyNamespaceManifest{NamespaceID: "namespace-id", UserID: "admin", Manifest: newTestManifest("frontend", "worker"), Prune: true})
+	if err == nil {
+		t.Fatal("Expected the creation of worker to fail, but got nil")
+	}
+	if result == nil || len(result.Deleted) != 1 || result.Deleted[0] != "backend" {
+		t.Fatalf("Expected backend to be pruned before the failure, got %+v", result)
+	}
+	finishedOperations := waitForOperations(t, operationRepository, result.Operations)
+	if len(finishedOperations) != 1 || finishedOperations[0].Type != domain.DeleteOperation || finishedOperations[0].ApplicationName != "backend" {
+		t.Errorf("Expected a delete operation of backend, got %+v", finishedOperations)
+	}
+	if len(containerManager.UnappliedApplications) != 1 {
+		t.Errorf("Expected backend to be removed from the cluster, got %v", containerManager.UnappliedApplications)
+	}
+}
+
+func TestExecute_ApplyNamespaceManifest_RejectsRoutesOfAnotherApplication(t *testing.T) {
+	operationRepository := &MockOperationRepository{}
+	applicationRepository := newInMemoryApplicationRepository(map[string]*domain.Application{})
+	useCase := newTestApplyNamespaceManifestUseCase(applicationRepository, &MockContainerManagerRepository{}, operationRepository, &MockAuditEventRepository{})
+	result, err := useCase.Execute(commands.ApplyNamespaceManifest{NamespaceID: "namespace-id", UserID: "admin", Manifest: newTestManifest("frontend", "backend")})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	waitForOperations(t, operationRepository, result.Operations)
+
+	// worker cannot serve the host of another application, whether that one is declared in the manifest or kept without being declared
+	takingDeclaredRoute := newTestManifest("backend", "worker")
+	takingDeclaredRoute.Applications[1].Ingress = &domain.ApplicationIngress{Routes: []domain.ApplicationRoute{{Subdomain: "backend", PathPrefix: "/"}}}
+	takingKeptRoute := newTestManifest("worker")
+	takingKeptRoute.Applications[0].Ingress = &domain.ApplicationIngress{Routes: []domain.ApplicationRoute{{Subdomain: "frontend"}}}
+	for _, applyNamespaceManifest := range []commands.ApplyNamespaceManifest{
+		{NamespaceID: "namespace-id", UserID: "admin", Manifest: takingDeclaredRoute, Prune: true},
+		{NamespaceID: "namespace-id", UserID: "admin", Manifest: takingKeptRoute},
+	} {
+		if _, err := useCase.Execute(applyNamespaceManifest); err == nil {
+			t.Errorf("Expected the manifest %+v to be rejected", applyNamespaceManifest.Manifest.Applications)
+		} else if _, ok := err.(*customErrors.InvalidApplicationIngressError); !ok {
+			t.Errorf("Expected an InvalidApplicationIngressError, got %v", err)
+		}
+	}
+	_, _, err = useCase.CreateApplicationUseCase.Execute(commands.CreateApplication{
+		UserID:      "admin",
+		NamespaceID: "namespace-id",
+		Name:        "worker",
+		Ingress:     domain.ApplicationIngress{Routes: []domain.ApplicationRoute{{Subdomain: "frontend"}}},
+	})
+	if _, ok := err.(*customErrors.InvalidApplicationIngressError); !ok {
+		t.Errorf("Expected the creation of an application serving the host of frontend to be rejected, got %v", err)
+	}
+
+	applications, _ := applicationRepository.FindByNamespaceIDAndUserID("namespace-id")
+	if len(applications) != 2 {
+		t.Errorf("Expected no application to be created nor pruned, got %d applications", len(applications))
+	}
+}
+
+func TestExecute_ApplyNamespaceManifest_RequiresAdmin(t *testing.T) {
+	auditEventRepository := &MockAuditEventRepository{}
+	useCase := newTestApplyNamespaceManifestUseCase(newInMemoryApplicationRepository(map[string]*domain.Application{}), &MockContainerManagerRepository{}, &MockOperationRepository{}, auditEventRepository)
+
+	_, err := useCase.Execute(commands.ApplyNamespaceManifest{NamespaceID: "namespace-id", UserID: "member", Manifest: newTestManifest("frontend")})
+	if err == nil {
+		t.Error("Expected an error when a member applies a manifest, but got nil")
+	}
+	if len(auditEventRepository.AuditEvents) != 1 || auditEventRepository.AuditEvents[0].Outcome != domain.AuditDenied {
+		t.Errorf("Expected a denied audit event, got %+v", auditEventRepository.AuditEvents)
+	}
+}
+
+func TestExecute_ApplyNamespaceManifest_RejectsTooManyApplications(t *testing.T) {
+	operationRepository := &MockOperationRepository{}
+	useCase := newTestApplyNamespaceManifestUseCase(newInMemoryApplicationRepository(map[string]*domain.Application{}), &MockContainerManagerRepository{}, operationRepository, &MockAuditEventRepository{})
+	result, err := useCase.Execute(commands.ApplyNamespaceManifest{NamespaceID: "namespace-id", UserID: "admin", Manifest: newTestManifest("frontend", "backend")})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	waitForOperations(t, operationRepository, result.Operations)
+
+	// Without pruning, the applications missing from the manifest are kept and count towards the limit
+	_, err = useCase.Execute(commands.ApplyNamespaceManifest{NamespaceID: "namespace-id", UserID: "admin", Manifest: newTestManifest("worker", "scheduler")})
+	if _, ok := err.(*customErrors.NamespaceHasReachedMaxNumberOfApplicationsError); !ok {
+		t.Errorf("Expected a NamespaceHasReachedMaxNumberOfApplicationsError, got %v", err)
+	}
+}
@@ -0,0 +1,113 @@
+package namespaces
+
+import (
+	"cloud-app-hive/controllers/errors"
+	"fmt"
+
+	"cloud-app-hive/domain"
+	"cloud-app-hive/domain/commands"
+	"cloud-app-hive/domain/repositories"
+	"cloud-app-hive/use_cases"
+)
+
+type BindAddonUseCase struct {
+	NamespaceRepository        repositories.NamespaceRepository
+	AddonRepository            repositories.AddonRepository
+	ApplicationRepository      repositories.ApplicationRepository
+	ContainerManagerRepository repositories.ContainerManagerRepository
+	RecordAuditEventUseCase    use_cases.RecordAuditEventUseCase
+}
+
+// Execute gives an application of the namespace the connection settings of an add-on as secrets.
+// The application is deployed again if it is on the cluster.
+func (bindAddonUseCase BindAddonUseCase) Execute(bindAddon commands.BindAddon) (createdBinding *domain.AddonBinding, err error) {
+	auditEvent := domain.NewAuditEvent(bindAddon.Actor, domain.AuditAddonBind, domain.AuditTargetAddon, bindAddon.AddonID)
+	auditEvent.NamespaceID = bindAddon.NamespaceID
+	defer func() {
+		if createdBinding != nil {
+			auditEvent.Changes = domain.NewAuditChanges(nil, createdBinding)
+		}
+		bindAddonUseCase.RecordAuditEventUseCase.Execute(auditEvent, err)
+	}()
+
+	namespace, addon, application, err := findAddonBindingTargets(bindAddonUseCase.NamespaceRepository, bindAddonUseCase.AddonRepository, bindAddon.NamespaceID, bindAddon.AddonID, bindAddon.ApplicationID, bindAddon.BoundBy)
+	if err != nil {
+		return nil, err
+	}
+	auditEvent.TargetName = addon.Name
+
+	for _, binding := range addon.Bindings {
+		if binding.ApplicationID == application.ID {
+			return nil, errors.NewInvalidAddonError(fmt.Sprintf("application %s is already bound to addon %s", application.Name, addon.Name))
+		}
+	}
+
+	createdBinding, err = bindAddonUseCase.AddonRepository.CreateBinding(domain.AddonBinding{
+		AddonID:       addon.ID,
+		ApplicationID: application.ID,
+		CreatedBy:     bindAddon.BoundBy,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := redeployBoundApplication(bindAddonUseCase.ApplicationRepository, bindAddonUseCase.ContainerManagerRepository, application.ID, namespace.Name); err != nil {
+		return nil, err
+	}
+	return createdBinding, nil
+}
+
+// findAddonBindingTargets returns the namespace, the add-on and the application of a binding, once the user is allowed to change the application
+func findAddonBindingTargets(
+	namespaceRepository repositories.NamespaceRepository,
+	addonRepository repositories.AddonRepository,
+	namespaceID string,
+	addonID string,
+	applicationID string,
+	userID string,
+) (*domain.Namespace, *domain.Addon, *domain.Application, error) {
+	namespace, err := namespaceRepository.FindByID(namespaceID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if namespace == nil {
+		return nil, nil, nil, errors.NewNamespaceNotFoundByIDError(namespaceID)
+	}
+	if err := namespace.Authorize(userID, domain.PermissionUpdateApplication); err != nil {
+		return nil, nil, nil, err
+	}
+
+	addon, err := addonRepository.FindByID(addonID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if addon == nil || addon.NamespaceID != namespace.ID {
+		return nil, nil, nil, errors.NewAddonNotFoundError(addonID)
+	}
+	application := namespace.FindApplicationByID(applicationID)
+	if application == nil {
+		return nil, nil, nil, errors.NewApplicationNotFoundByIDError(applicationID)
+	}
+	return namespace, addon, application, nil
+}
+
+// redeployBoundApplication deploys an application again with the secrets of its add-ons and its config groups, if it is on the cluster
+func redeployBoundApplication(
+	applicationRepository repositories.ApplicationRepository,
+	containerManagerRepository repositories.ContainerManagerRepository,
+	applicationID string,
+	namespaceName string,
+) error {
+	application, err := applicationRepository.FindByID(applicationID)
+	if err != nil {
+		return err
+	}
+	// An application that is not on the cluster gets the secrets of its add-ons when it is deployed
+	_, err = containerManagerRepository.GetApplicationStatus(commands.GetApplicationStatus{
+		Name:      application.Name,
+		Namespace: namespaceName,
+	})
+	if err != nil {
+		return nil
+	}
+	return containerManagerRepository.ApplyApplication(commands.NewApplyApplication(*application, namespaceName))
+}
@@ -0,0 +1,51 @@
+package namespaces
+
+import (
+	"cloud-app-hive/controllers/errors"
+
+	"cloud-app-hive/domain"
+	"cloud-app-hive/domain/commands"
+	"cloud-app-hive/domain/repositories"
+	"cloud-app-hive/use_cases"
+)
+
+type CancelNamespaceOwnershipTransferUseCase struct {
+	NamespaceRepository     repositories.NamespaceRepository
+	RecordAuditEventUseCase use_cases.RecordAuditEventUseCase
+}
+
+// Execute cancels the pending ownership transfer of a namespace, either the owner withdraws it or the member declines it
+func (cancelNamespaceOwnershipTransferUseCase CancelNamespaceOwnershipTransferUseCase) Execute(respondToNamespaceOwnershipTransfer commands.RespondToNamespaceOwnershipTransfer) (namespace *domain.Namespace, err error) {
+	auditEvent := domain.NewAuditEvent(respondToNamespaceOwnershipTransfer.Actor, domain.AuditNamespaceTransferCancel, domain.AuditTargetNamespace, respondToNamespaceOwnershipTransfer.NamespaceID)
+	auditEvent.NamespaceID = respondToNamespaceOwnershipTransfer.NamespaceID
+	defer func() {
+		cancelNamespaceOwnershipTransferUseCase.RecordAuditEventUseCase.Execute(auditEvent, err)
+	}()
+
+	namespace, err = cancelNamespaceOwnershipTransferUseCase.NamespaceRepository.FindByID(respondToNamespaceOwnershipTransfer.NamespaceID)
+	if err != nil {
+		return nil, err
+	}
+	if namespace == nil {
+		return nil, errors.NewNamespaceNotFoundByIDError(respondToNamespaceOwnershipTransfer.NamespaceID)
+	}
+	auditEvent.TargetName = namespace.Name
+
+	if namespace.PendingOwnerID == nil {
+		return nil, errors.NewNamespaceOwnershipTransferNotFoundError(namespace.ID, respondToNamespaceOwnershipTransfer.UserID)
+	}
+	if *namespace.PendingOwnerID != respondToNamespaceOwnershipTransfer.UserID {
+		if err := namespace.AuthorizeOwnershipTransfer(respondToNamespaceOwnershipTransfer.UserID); err != nil {
+			return nil, err
+		}
+	}
+	auditEvent.Changes = domain.NewAuditChanges(map[string]*string{"pendingOwnerId": namespace.PendingOwnerID}, map[string]*string{"pendingOwnerId": nil})
+
+	if err := cancelNamespaceOwnershipTransferUseCase.NamespaceRepository.SetPendingOwner(namespace.ID, nil, nil); err != nil {
+		return nil, err
+	}
+
+	namespace.PendingOwnerID = nil
+	namespace.OwnershipTransferExpiresAt = nil
+	return namespace, nil
+}
@@ -0,0 +1,210 @@
+package namespaces
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	customErrors "cloud-app-hive/controllers/errors"
+	"cloud-app-hive/domain"
+	"cloud-app-hive/domain/commands"
+	"cloud-app-hive/use_cases/applications"
+	"cloud-app-hive/use_cases/operations"
+)
+
+// MockConfigGroupRepository is a mock implementation of the ConfigGroupRepository interface keeping the config groups and their attachments in memory
+type MockConfigGroupRepository struct {
+	ConfigGroups []domain.ConfigGroup
+	Attachments  []domain.ConfigGroupAttachment
+}
+
+func (m *MockConfigGroupRepository) Create(configGroup domain.ConfigGroup) (*domain.ConfigGroup, error) {
+	configGroup.ID = fmt.Sprintf("config-group-%d", len(m.ConfigGroups)+1)
+	m.ConfigGroups = append(m.ConfigGroups, configGroup)
+	return &configGroup, nil
+}
+
+func (m *MockConfigGroupRepository) FindByID(id string) (*domain.ConfigGroup, error) {
+	for _, configGroup := range m.ConfigGroups {
+		if configGroup.ID == id {
+			configGroup.Attachments = m.configGroupAttachments(id)
+			return &configGroup, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *MockConfigGroupRepository) FindByNamespaceID(namespaceID string) ([]domain.ConfigGroup, error) {
+	var configGroups []domain.ConfigGroup
+	for _, configGroup := range m.ConfigGroups {
+		if configGroup.NamespaceID == namespaceID {
+			configGroup.Attachments = m.configGroupAttachments(configGroup.ID)
+			configGroups = append(configGroups, configGroup)
+		}
+	}
+	return configGroups, nil
+}
+
+func (m *MockConfigGroupRepository) FindAll() ([]domain.ConfigGroup, error) {
+	return m.ConfigGroups, nil
+}
+
+func (m *MockConfigGroupRepository) Update(configGroup domain.ConfigGroup) (*domain.ConfigGroup, error) {
+	for index := range m.ConfigGroups {
+		if m.ConfigGroups[index].ID == configGroup.ID {
+			m.ConfigGroups[index] = configGroup
+		}
+	}
+	return &configGroup, nil
+}
+
+func (m *MockConfigGroupRepository) Delete(id string) error {
+	for index, configGroup := range m.ConfigGroups {
+		if configGroup.ID == id {
+			m.ConfigGroups = append(m.ConfigGroups[:index], m.ConfigGroups[index+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+func (m *MockConfigGroupRepository) ReplaceAttachments(applicationID string, attachments []domain.ConfigGroupAttachment) error {
+	kept := []domain.ConfigGroupAttachment{}
+	for _, attachment := range m.Attachments {
+		if attachment.ApplicationID != applicationID {
+			kept = append(kept, attachment)
+		}
+	}
+	m.Attachments = append(kept, attachments...)
+	return nil
+}
+
+func (m *MockConfigGroupRepository) configGroupAttachments(configGroupID string) []domain.ConfigGroupAttachment {
+	attachments := []domain.ConfigGroupAttachment{}
+	for _, attachment := range m.Attachments {
+		if attachment.ConfigGroupID == configGroupID {
+			attachments = append(attachments, attachment)
+		}
+	}
+	return attachments
+}
+
+// newTestConfigGroupUseCases returns the config group use cases of the namespace "team" of newTestNamespaceRepository,
+// where the application "api" is deployed
+func newTestConfigGroupUseCases() (CreateConfigGroupUseCase, UpdateConfigGroupUseCase, DeleteConfigGroupUseCase, *MockConfigGroupRepository, *MockContainerManagerRepository, *MockOperationRepository) {
+	namespaceRepository := newTestNamespaceRepository()
+	namespace, _ := namespaceRepository.FindByID("team")
+	namespace.Applications = []domain.Application{{ID: "api-id", Name: "api", NamespaceID: namespace.ID}}
+	namespaceRepository.FindByIDFunc = func(id string) (*domain.Namespace, error) {
+		return namespace, nil
+	}
+	applicationRepository := newInMemoryApplicationRepository(map[string]*domain.Application{"api-id": &namespace.Applications[0]})
+	configGroupRepository := &MockConfigGroupRepository{}
+	containerManager := &MockContainerManagerRepository{}
+	operationRepository := &MockOperationRepository{}
+	recordAuditEventUseCase := newTestRecordAuditEventUseCase(&MockAuditEventRepository{})
+
+	createConfigGroupUseCase := CreateConfigGroupUseCase{
+		NamespaceRepository:        namespaceRepository,
+		ConfigGroupRepository:      configGroupRepository,
+		ContainerManagerRepository: containerManager,
+		RecordAuditEventUseCase:    recordAuditEventUseCase,
+	}
+	updateConfigGroupUseCase := UpdateConfigGroupUseCase{
+		NamespaceRepository:        namespaceRepository,
+		ConfigGroupRepository:      configGroupRepository,
+		ApplicationRepository:      applicationRepository,
+		ContainerManagerRepository: containerManager,
+		RedeployApplicationUseCase: applications.RedeployApplicationUseCase{
+			ApplicationRepository:      applicationRepository,
+			ContainerManagerRepository: containerManager,
+			StartApplicationOperationUseCase: operations.StartApplicationOperationUseCase{
+				OperationRepository:        operationRepository,
+				ContainerManagerRepository: containerManager,
+				RolloutPollInterval:        time.Millisecond,
+			},
+		},
+		RecordAuditEventUseCase: recordAuditEventUseCase,
+	}
+	deleteConfigGroupUseCase := DeleteConfigGroupUseCase{
+		NamespaceRepository:        namespaceRepository,
+		ConfigGroupRepository:      configGroupRepository,
+		ContainerManagerRepository: containerManager,
+		RecordAuditEventUseCase:    recordAuditEventUseCase,
+	}
+	return createConfigGroupUseCase, updateConfigGroupUseCase, deleteConfigGroupUseCase, configGroupRepository, containerManager, operationRepository
+}
+
+func TestExecute_CreateConfigGroup_RendersTheGroup(t *testing.T) {
+	createConfigGroupUseCase, _, _, configGroupRepository, containerManager, _ := newTestConfigGroupUseCases()
+
+	createConfigGroup := commands.CreateConfigGroup{
+		NamespaceID:          "team",
+		Name:                 "shared",
+		EnvironmentVariables: domain.ApplicationEnvironmentVariables{{Name: "LOG_LEVEL", Val: "debug"}},
+		CreatedBy:            "admin",
+	}
+	if _, err := createConfigGroupUseCase.Execute(createConfigGroup); err != nil {
+		t.Fatalf("Expected the config group to be created, but got %v", err)
+	}
+	if len(containerManager.AppliedConfigGroups) != 1 || containerManager.AppliedConfigGroups[0].Namespace != "team" {
+		t.Errorf("Expected the config group to be applied in the namespace, but got %+v", containerManager.AppliedConfigGroups)
+	}
+
+	if _, err := createConfigGroupUseCase.Execute(createConfigGroup); err == nil {
+		t.Error("Expected a second config group with the same name to be rejected, but got nil")
+	}
+	createConfigGroup.Name = "database"
+	createConfigGroup.CreatedBy = "member"
+	if _, err := createConfigGroupUseCase.Execute(createConfigGroup); err == nil {
+		t.Error("Expected a member to be forbidden, but got nil")
+	}
+	if len(configGroupRepository.ConfigGroups) != 1 {
+		t.Errorf("Expected 1 config group to be stored, but got %d", len(configGroupRepository.ConfigGroups))
+	}
+}
+
+func TestExecute_UpdateConfigGroup_RedeploysAttachedApplicationsAndBlocksDeletion(t *testing.T) {
+	createConfigGroupUseCase, updateConfigGroupUseCase, deleteConfigGroupUseCase, configGroupRepository, containerManager, operationRepository := newTestConfigGroupUseCases()
+	configGroup, err := createConfigGroupUseCase.Execute(commands.CreateConfigGroup{NamespaceID: "team", Name: "shared", CreatedBy: "admin"})
+	if err != nil {
+		t.Fatalf("Expected the config group to be created, but got %v", err)
+	}
+	configGroupRepository.Attachments = []domain.ConfigGroupAttachment{{ID: "attachment-1", ConfigGroupID: configGroup.ID, ApplicationID: "api-id"}}
+
+	updatedConfigGroup, startedOperations, err := updateConfigGroupUseCase.Execute(commands.UpdateConfigGroup{
+		NamespaceID:          "team",
+		ConfigGroupID:        configGroup.ID,
+		EnvironmentVariables: domain.ApplicationEnvironmentVariables{{Name: "LOG_LEVEL", Val: "info"}},
+		UpdatedBy:            "admin",
+	})
+	if err != nil {
+		t.Fatalf("Expected the config group to be updated, but got %v", err)
+	}
+	if updatedConfigGroup.Version != 2 || len(containerManager.AppliedConfigGroups) != 2 || containerManager.AppliedConfigGroups[1].Version != 2 {
+		t.Errorf("Expected the second version of the group to be applied, but got %+v", containerManager.AppliedConfigGroups)
+	}
+	if len(startedOperations) != 1 || startedOperations[0].ApplicationID != "api-id" || startedOperations[0].Type != domain.UpdateOperation {
+		t.Fatalf("Expected an update operation to be started for api, but got %+v", startedOperations)
+	}
+	if finishedOperations := waitForOperations(t, operationRepository, startedOperations); finishedOperations[0].Status != domain.OperationSucceeded {
+		t.Errorf("Expected the deployment of api to succeed, but got %+v", finishedOperations[0])
+	}
+	if len(containerManager.AppliedApplications) != 1 || containerManager.AppliedApplications[0] != "api" {
+		t.Errorf("Expected api to be deployed again, but got %v", containerManager.AppliedApplications)
+	}
+
+	_, err = deleteConfigGroupUseCase.Execute(commands.DeleteConfigGroup{NamespaceID: "team", ConfigGroupID: configGroup.ID, DeletedBy: "admin"})
+	inUseErr, ok := err.(*customErrors.ConfigGroupInUseError)
+	if !ok || len(inUseErr.ApplicationNames) != 1 || inUseErr.ApplicationNames[0] != "api" {
+		t.Fatalf("Expected the config group attached to api not to be deleted, but got %v", err)
+	}
+
+	configGroupRepository.Attachments = nil
+	if _, err := deleteConfigGroupUseCase.Execute(commands.DeleteConfigGroup{NamespaceID: "team", ConfigGroupID: configGroup.ID, DeletedBy: "admin"}); err != nil {
+		t.Fatalf("Expected the detached config group to be deleted, but got %v", err)
+	}
+	if len(configGroupRepository.ConfigGroups) != 0 || len(containerManager.UnappliedConfigGroups) != 1 {
+		t.Errorf("Expected the config group to be deleted from the database and the cluster, but got %+v and %v", configGroupRepository.ConfigGroups, containerManager.UnappliedConfigGroups)
+	}
+}
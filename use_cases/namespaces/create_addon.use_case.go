@@ -0,0 +1,74 @@
+package namespaces
+
+import (
+	"cloud-app-hive/controllers/errors"
+	"fmt"
+
+	"cloud-app-hive/domain"
+	"cloud-app-hive/domain/commands"
+	"cloud-app-hive/domain/repositories"
+	"cloud-app-hive/use_cases"
+)
+
+type CreateAddonUseCase struct {
+	NamespaceRepository        repositories.NamespaceRepository
+	AddonRepository            repositories.AddonRepository
+	ContainerManagerRepository repositories.ContainerManagerRepository
+	RecordAuditEventUseCase    use_cases.RecordAuditEventUseCase
+}
+
+// Execute stores an add-on of a namespace with generated credentials and provisions it on the cluster.
+// The add-on is deleted again when it cannot be provisioned.
+func (createAddonUseCase CreateAddonUseCase) Execute(createAddon commands.CreateAddon) (createdAddon *domain.Addon, err error) {
+	auditEvent := domain.NewAuditEvent(createAddon.Actor, domain.AuditAddonCreate, domain.AuditTargetAddon, "")
+	auditEvent.NamespaceID = createAddon.NamespaceID
+	auditEvent.TargetName = createAddon.Name
+	defer func() {
+		if createdAddon != nil {
+			auditEvent.TargetID = createdAddon.ID
+			auditEvent.Changes = domain.NewAuditChanges(nil, createdAddon)
+		}
+		createAddonUseCase.RecordAuditEventUseCase.Execute(auditEvent, err)
+	}()
+
+	namespace, err := createAddonUseCase.NamespaceRepository.FindByID(createAddon.NamespaceID)
+	if err != nil {
+		return nil, err
+	}
+	if namespace == nil {
+		return nil, errors.NewNamespaceNotFoundByIDError(createAddon.NamespaceID)
+	}
+	if err := namespace.Authorize(createAddon.CreatedBy, domain.PermissionManageAddons); err != nil {
+		return nil, err
+	}
+
+	addon, err := domain.NewAddon(namespace.ID, namespace.Name, createAddon.Name, createAddon.Type, createAddon.Size, createAddon.CreatedBy)
+	if err != nil {
+		return nil, errors.NewInvalidAddonError(err.Error())
+	}
+	addons, err := createAddonUseCase.AddonRepository.FindByNamespaceID(namespace.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(addons) >= domain.MaxAddonsByNamespace {
+		return nil, errors.NewInvalidAddonError(fmt.Sprintf("namespace %s cannot have more than %d addons", namespace.ID, domain.MaxAddonsByNamespace))
+	}
+	for _, namespaceAddon := range addons {
+		if namespaceAddon.Name == addon.Name {
+			return nil, errors.NewInvalidAddonError(fmt.Sprintf("namespace %s already has an addon named %s", namespace.ID, addon.Name))
+		}
+	}
+
+	createdAddon, err = createAddonUseCase.AddonRepository.Create(*addon)
+	if err != nil {
+		return nil, err
+	}
+	if err := createAddonUseCase.ContainerManagerRepository.ApplyAddon(commands.NewApplyAddon(*createdAddon, namespace.Name)); err != nil {
+		if deleteErr := createAddonUseCase.AddonRepository.Delete(createdAddon.ID); deleteErr != nil {
+			fmt.Println(fmt.Errorf("error deleting addon %s that failed to be applied: %w", createdAddon.ID, deleteErr))
+		}
+		return nil, err
+	}
+	createdAddon.Bindings = []domain.AddonBinding{}
+	return createdAddon, nil
+}
@@ -0,0 +1,81 @@
+package namespaces
+
+import (
+	"cloud-app-hive/controllers/errors"
+	"fmt"
+
+	"cloud-app-hive/domain"
+	"cloud-app-hive/domain/commands"
+	"cloud-app-hive/domain/repositories"
+	"cloud-app-hive/use_cases"
+)
+
+type CreateConfigGroupUseCase struct {
+	NamespaceRepository        repositories.NamespaceRepository
+	ConfigGroupRepository      repositories.ConfigGroupRepository
+	ContainerManagerRepository repositories.ContainerManagerRepository
+	RecordAuditEventUseCase    use_cases.RecordAuditEventUseCase
+}
+
+// Execute stores a config group of a namespace and renders it on the cluster.
+// The config group is deleted again when it cannot be rendered.
+func (createConfigGroupUseCase CreateConfigGroupUseCase) Execute(createConfigGroup commands.CreateConfigGroup) (createdConfigGroup *domain.ConfigGroup, err error) {
+	auditEvent := domain.NewAuditEvent(createConfigGroup.Actor, domain.AuditConfigGroupCreate, domain.AuditTargetConfigGroup, "")
+	auditEvent.NamespaceID = createConfigGroup.NamespaceID
+	auditEvent.TargetName = createConfigGroup.Name
+	defer func() {
+		if createdConfigGroup != nil {
+			auditEvent.TargetID = createdConfigGroup.ID
+			auditEvent.Changes = domain.NewAuditChanges(nil, createdConfigGroup)
+		}
+		createConfigGroupUseCase.RecordAuditEventUseCase.Execute(auditEvent, err)
+	}()
+
+	namespace, err := createConfigGroupUseCase.NamespaceRepository.FindByID(createConfigGroup.NamespaceID)
+	if err != nil {
+		return nil, err
+	}
+	if namespace == nil {
+		return nil, errors.NewNamespaceNotFoundByIDError(createConfigGroup.NamespaceID)
+	}
+	if err := namespace.Authorize(createConfigGroup.CreatedBy, domain.PermissionManageConfigGroups); err != nil {
+		return nil, err
+	}
+
+	configGroup, err := domain.NewConfigGroup(
+		namespace.ID,
+		createConfigGroup.Name,
+		createConfigGroup.Description,
+		createConfigGroup.EnvironmentVariables,
+		createConfigGroup.Secrets,
+		createConfigGroup.CreatedBy,
+	)
+	if err != nil {
+		return nil, errors.NewInvalidConfigGroupError(err.Error())
+	}
+	configGroups, err := createConfigGroupUseCase.ConfigGroupRepository.FindByNamespaceID(namespace.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(configGroups) >= domain.MaxConfigGroupsByNamespace {
+		return nil, errors.NewInvalidConfigGroupError(fmt.Sprintf("namespace %s cannot have more than %d config groups", namespace.ID, domain.MaxConfigGroupsByNamespace))
+	}
+	for _, namespaceConfigGroup := range configGroups {
+		if namespaceConfigGroup.Name == configGroup.Name {
+			return nil, errors.NewInvalidConfigGroupError(fmt.Sprintf("namespace %s already has a config group named %s", namespace.ID, configGroup.Name))
+		}
+	}
+
+	createdConfigGroup, err = createConfigGroupUseCase.ConfigGroupRepository.Create(*configGroup)
+	if err != nil {
+		return nil, err
+	}
+	if err := createConfigGroupUseCase.ContainerManagerRepository.ApplyConfigGroup(commands.NewApplyConfigGroup(*createdConfigGroup, namespace.Name)); err != nil {
+		if deleteErr := createConfigGroupUseCase.ConfigGroupRepository.Delete(createdConfigGroup.ID); deleteErr != nil {
+			fmt.Println(fmt.Errorf("error deleting config group %s that failed to be applied: %w", createdConfigGroup.ID, deleteErr))
+		}
+		return nil, err
+	}
+	createdConfigGroup.Attachments = []domain.ConfigGroupAttachment{}
+	return createdConfigGroup, nil
+}
@@ -7,13 +7,29 @@ import (
 	"cloud-app-hive/domain"
 	"cloud-app-hive/domain/commands"
 	"cloud-app-hive/domain/repositories"
+	"cloud-app-hive/use_cases"
 )
 
 type CreateNamespaceUseCase struct {
-	NamespaceRepository repositories.NamespaceRepository
+	NamespaceRepository     repositories.NamespaceRepository
+	RecordAuditEventUseCase use_cases.RecordAuditEventUseCase
 }
 
-func (createNamespaceUseCase CreateNamespaceUseCase) Execute(createNamespace commands.CreateNamespace) (*domain.Namespace, error) {
+func (createNamespaceUseCase CreateNamespaceUseCase) Execute(createNamespace commands.CreateNamespace) (createdNamespace *domain.Namespace, err error) {
+	auditEvent := domain.NewAuditEvent(createNamespace.Actor, domain.AuditNamespaceCreate, domain.AuditTargetNamespace, "")
+	auditEvent.TargetName = createNamespace.Name
+	defer func() {
+		if createdNamespace != nil {
+			auditEvent.NamespaceID = createdNamespace.ID
+			auditEvent.TargetID = createdNamespace.ID
+			auditEvent.Changes = domain.NewAuditChanges(nil, map[string]string{
+				"name":        createdNamespace.Name,
+				"description": createdNamespace.Description,
+			})
+		}
+		createNamespaceUseCase.RecordAuditEventUseCase.Execute(auditEvent, err)
+	}()
+
 	foundNamespaceByID, err := createNamespaceUseCase.NamespaceRepository.ExistsByName(createNamespace.Name)
 	if err != nil {
 		return nil, err
@@ -22,7 +38,7 @@ func (createNamespaceUseCase CreateNamespaceUseCase) Execute(createNamespace com
 		return nil, errors.NewNamespaceWithNameAlreadyExistError(createNamespace.Name)
 	}
 
-	createdNamespace, err := createNamespaceUseCase.NamespaceRepository.Create(createNamespace)
+	createdNamespace, err = createNamespaceUseCase.NamespaceRepository.Create(createNamespace)
 	if err != nil {
 		fmt.Println(fmt.Errorf("error creating namespace (%s): %w", createNamespace.Name, err))
 		return nil, err
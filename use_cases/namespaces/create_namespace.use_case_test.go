@@ -2,10 +2,13 @@ package namespaces
 
 import (
 	"fmt"
+	"sync"
 	"testing"
+	"time"
 
 	"cloud-app-hive/domain"
 	"cloud-app-hive/domain/commands"
+	"cloud-app-hive/use_cases"
 )
 
 // MockNamespaceRepository is a mock implementation of the NamespaceRepository interface above
@@ -17,6 +20,11 @@ type MockNamespaceRepository struct {
 	CreateFunc       func(createNamespace commands.CreateNamespace) (*domain.Namespace, error)
 	DeleteFunc       func(id string, userId string) (*domain.Namespace, error)
 	UpdateFunc       func(updateNamespace commands.UpdateNamespace) (*domain.Namespace, error)
+	// SetPendingOwnerFunc and TransferOwnershipFunc are optional, they are only called by the ownership transfer
+	SetPendingOwnerFunc   func(namespaceID string, pendingOwnerID *string, expiresAt *time.Time) error
+	TransferOwnershipFunc func(namespaceID string, newOwnerID string) (*domain.Namespace, error)
+	// SetImageScanPolicyFunc is optional, it is only called when the image scan policy changes
+	SetImageScanPolicyFunc func(namespaceID string, policy domain.ImageScanPolicy, maxSeverity domain.VulnerabilitySeverity) error
 }
 
 func (m *MockNamespaceRepository) FindByID(id string) (*domain.Namespace, error) {
@@ -47,6 +55,39 @@ func (m *MockNamespaceRepository) Update(updateNamespace commands.UpdateNamespac
 	return m.UpdateFunc(updateNamespace)
 }
 
+func (m *MockNamespaceRepository) SetPendingOwner(namespaceID string, pendingOwnerID *string, expiresAt *time.Time) error {
+	return m.SetPendingOwnerFunc(namespaceID, pendingOwnerID, expiresAt)
+}
+
+func (m *MockNamespaceRepository) SetImageScanPolicy(namespaceID string, policy domain.ImageScanPolicy, maxSeverity domain.VulnerabilitySeverity) error {
+	return m.SetImageScanPolicyFunc(namespaceID, policy, maxSeverity)
+}
+
+func (m *MockNamespaceRepository) TransferOwnership(namespaceID string, newOwnerID string) (*domain.Namespace, error) {
+	return m.TransferOwnershipFunc(namespaceID, newOwnerID)
+}
+
+// MockAuditEventRepository keeps the recorded audit events in memory, events can be recorded by background operations
+type MockAuditEventRepository struct {
+	mutex       sync.Mutex
+	AuditEvents []domain.AuditEvent
+}
+
+func (m *MockAuditEventRepository) Create(auditEvent domain.AuditEvent) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.AuditEvents = append(m.AuditEvents, auditEvent)
+	return nil
+}
+
+func (m *MockAuditEventRepository) Find(findAuditEvents commands.FindAuditEvents) ([]domain.AuditEvent, error) {
+	return m.AuditEvents, nil
+}
+
+func newTestRecordAuditEventUseCase(auditEventRepository *MockAuditEventRepository) use_cases.RecordAuditEventUseCase {
+	return use_cases.RecordAuditEventUseCase{AuditEventRepository: auditEventRepository}
+}
+
 func TestExecute_CreateNamespace_Success(t *testing.T) {
 	// Create a mock repository with desired behavior
 	mockRepository := &MockNamespaceRepository{
@@ -62,12 +103,14 @@ func TestExecute_CreateNamespace_Success(t *testing.T) {
 	}
 
 	// Create the use case instance with the mock repository
+	auditEventRepository := &MockAuditEventRepository{}
 	useCase := CreateNamespaceUseCase{
-		NamespaceRepository: mockRepository,
+		NamespaceRepository:     mockRepository,
+		RecordAuditEventUseCase: newTestRecordAuditEventUseCase(auditEventRepository),
 	}
 
 	// Prepare the test case
-	createNamespace := commands.CreateNamespace{Name: "myNamespace"}
+	createNamespace := commands.CreateNamespace{Name: "myNamespace", Actor: domain.AuditActor{UserID: "user", SourceIP: "10.0.0.1"}}
 
 	// Execute the use case
 	createdNamespace, err := useCase.Execute(createNamespace)
@@ -81,6 +124,13 @@ func TestExecute_CreateNamespace_Success(t *testing.T) {
 	if createdNamespace.Name != createNamespace.Name {
 		t.Errorf("Expected created namespace name to be %s, but got %s", createNamespace.Name, createdNamespace.Name)
 	}
+	if len(auditEventRepository.AuditEvents) != 1 {
+		t.Fatalf("Expected 1 audit event, but got %d", len(auditEventRepository.AuditEvents))
+	}
+	auditEvent := auditEventRepository.AuditEvents[0]
+	if auditEvent.Action != domain.AuditNamespaceCreate || auditEvent.Outcome != domain.AuditSuccess || auditEvent.NamespaceID != "123" || auditEvent.ActorID != "user" || auditEvent.SourceIP != "10.0.0.1" {
+		t.Errorf("Unexpected audit event %+v", auditEvent)
+	}
 }
 
 func TestExecute_CreateNamespace_AlreadyExists(t *testing.T) {
@@ -96,7 +146,8 @@ func TestExecute_CreateNamespace_AlreadyExists(t *testing.T) {
 
 	// Create the use case instance with the mock repository
 	useCase := CreateNamespaceUseCase{
-		NamespaceRepository: mockRepository,
+		NamespaceRepository:     mockRepository,
+		RecordAuditEventUseCase: newTestRecordAuditEventUseCase(&MockAuditEventRepository{}),
 	}
 
 	// Prepare the test case
@@ -0,0 +1,129 @@
+package namespaces
+
+import (
+	"cloud-app-hive/controllers/errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"cloud-app-hive/domain"
+	"cloud-app-hive/domain/commands"
+	"cloud-app-hive/domain/repositories"
+	"cloud-app-hive/use_cases"
+	"cloud-app-hive/validators"
+)
+
+type CreateNamespaceInvitationUseCase struct {
+	NamespaceRepository           repositories.NamespaceRepository
+	NamespaceInvitationRepository repositories.NamespaceInvitationRepository
+	EmailSender                   repositories.EmailSender
+	// AcceptURL is the page where invitees accept invitations, the token is added to it as the "token" query parameter
+	AcceptURL               string
+	RecordAuditEventUseCase use_cases.RecordAuditEventUseCase
+}
+
+// Execute stores a pending invitation and emails its token to the invitee, the token cannot be retrieved afterwards
+func (createNamespaceInvitationUseCase CreateNamespaceInvitationUseCase) Execute(createNamespaceInvitation commands.CreateNamespaceInvitation) (createdNamespaceInvitation *domain.NamespaceInvitation, err error) {
+	createNamespaceInvitation.Email = domain.NormalizeInvitationEmail(createNamespaceInvitation.Email)
+	auditEvent := domain.NewAuditEvent(createNamespaceInvitation.Actor, domain.AuditNamespaceInviteCreate, domain.AuditTargetInvitation, "")
+	auditEvent.NamespaceID = createNamespaceInvitation.NamespaceID
+	auditEvent.TargetName = createNamespaceInvitation.Email
+	auditEvent.Changes = domain.NewAuditChanges(nil, map[string]domain.Role{"role": createNamespaceInvitation.Role})
+	defer func() {
+		if createdNamespaceInvitation != nil {
+			auditEvent.TargetID = createdNamespaceInvitation.ID
+		}
+		createNamespaceInvitationUseCase.RecordAuditEventUseCase.Execute(auditEvent, err)
+	}()
+
+	if err := validators.ValidateEmail(createNamespaceInvitation.Email); err != nil {
+		return nil, errors.NewInvalidNamespaceInvitationError(fmt.Sprintf("email is invalid: %s", err.Error()))
+	}
+
+	namespace, err := createNamespaceInvitationUseCase.NamespaceRepository.FindByID(createNamespaceInvitation.NamespaceID)
+	if err != nil {
+		return nil, err
+	}
+	if namespace == nil {
+		return nil, errors.NewNamespaceNotFoundByIDError(createNamespaceInvitation.NamespaceID)
+	}
+	if err := authorizeNamespaceInvitation(*namespace, createNamespaceInvitation.InvitedBy, createNamespaceInvitation.Role); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	pendingInvitations, err := createNamespaceInvitationUseCase.NamespaceInvitationRepository.FindPendingByNamespaceID(namespace.ID, now)
+	if err != nil {
+		return nil, err
+	}
+	for _, pendingInvitation := range pendingInvitations {
+		if pendingInvitation.IsFor(createNamespaceInvitation.Email) {
+			return nil, errors.NewInvalidNamespaceInvitationError(fmt.Sprintf("%s already has a pending invitation %s, revoke it to send another one", createNamespaceInvitation.Email, pendingInvitation.ID))
+		}
+	}
+
+	token, err := domain.NewNamespaceInvitationToken()
+	if err != nil {
+		return nil, err
+	}
+	createNamespaceInvitation.TokenHash = domain.HashNamespaceInvitationToken(token)
+	createNamespaceInvitation.ExpiresAt = now.Add(domain.NamespaceInvitationValidity)
+
+	createdNamespaceInvitation, err = createNamespaceInvitationUseCase.NamespaceInvitationRepository.Create(createNamespaceInvitation)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := createNamespaceInvitationUseCase.sendInvitation(*namespace, *createdNamespaceInvitation, token); err != nil {
+		// An invitation that was never received must not stay pending, so that it can be sent again
+		if _, revokeErr := createNamespaceInvitationUseCase.NamespaceInvitationRepository.UpdateStatus(createdNamespaceInvitation.ID, domain.NamespaceInvitationRevoked, nil, now); revokeErr != nil {
+			fmt.Println(fmt.Errorf("error revoking unsent namespace invitation %s: %w", createdNamespaceInvitation.ID, revokeErr))
+		}
+		return nil, fmt.Errorf("error sending namespace invitation to %s: %w", createNamespaceInvitation.Email, err)
+	}
+
+	return createdNamespaceInvitation, nil
+}
+
+func (createNamespaceInvitationUseCase CreateNamespaceInvitationUseCase) sendInvitation(namespace domain.Namespace, invitation domain.NamespaceInvitation, token string) error {
+	acceptLink, err := namespaceInvitationAcceptLink(createNamespaceInvitationUseCase.AcceptURL, token)
+	if err != nil {
+		return err
+	}
+
+	subject := fmt.Sprintf("You are invited to join the namespace %s", namespace.Name)
+	expiresAt := invitation.ExpiresAt.Format("January 2, 2006 at 15:04 MST")
+	textBody := fmt.Sprintf(
+		"Hello,\n\nYou are invited to join the namespace %s with the role %s.\n\nAccept the invitation: %s\n\nThis invitation expires on %s. If you did not expect it, you can ignore this email or decline it.\n\nInvitation token: %s\n",
+		namespace.Name, invitation.Role, acceptLink, expiresAt, token,
+	)
+	htmlBody := fmt.Sprintf(
+		"<p>Hello,</p><p>You are invited to join the namespace <strong>%s</strong> with the role <strong>%s</strong>.</p><p><a href=\"%s\">Accept the invitation</a></p><p>This invitation expires on %s. If you did not expect it, you can ignore this email or decline it.</p>",
+		namespace.Name, invitation.Role, acceptLink, expiresAt,
+	)
+	return createNamespaceInvitationUseCase.EmailSender.Send(invitation.Email, subject, textBody, htmlBody, []string{})
+}
+
+// namespaceInvitationAcceptLink adds the token to the accept page, keeping the query parameters the page already has
+func namespaceInvitationAcceptLink(acceptURL string, token string) (string, error) {
+	link, err := url.Parse(acceptURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid namespace invitation accept URL %s: %w", acceptURL, err)
+	}
+	query := link.Query()
+	query.Set("token", token)
+	link.RawQuery = query.Encode()
+	return link.String(), nil
+}
+
+// authorizeNamespaceInvitation returns an error if the user cannot invite someone with the role,
+// only the owner can invite with the roles that manage the namespace
+func authorizeNamespaceInvitation(namespace domain.Namespace, userID string, role domain.Role) error {
+	if err := namespace.Authorize(userID, domain.PermissionManageNamespaceMembers); err != nil {
+		return err
+	}
+	if role == domain.RoleOwner || role == domain.RoleAdmin {
+		return namespace.Authorize(userID, domain.PermissionManageNamespaceAdmins)
+	}
+	return nil
+}
@@ -1,18 +1,48 @@
 package namespaces
 
 import (
+	"cloud-app-hive/controllers/errors"
 	"fmt"
 
 	"cloud-app-hive/domain"
 	"cloud-app-hive/domain/commands"
 	"cloud-app-hive/domain/repositories"
+	"cloud-app-hive/use_cases"
 )
 
 type CreateNamespaceMembershipUseCase struct {
+	NamespaceRepository           repositories.NamespaceRepository
 	NamespaceMembershipRepository repositories.NamespaceMembershipRepository
+	RecordAuditEventUseCase       use_cases.RecordAuditEventUseCase
 }
 
-func (createNamespaceMembershipUseCase CreateNamespaceMembershipUseCase) Execute(createNamespaceMembership commands.CreateNamespaceMembership) (*domain.NamespaceMembership, error) {
+func (createNamespaceMembershipUseCase CreateNamespaceMembershipUseCase) Execute(createNamespaceMembership commands.CreateNamespaceMembership) (createdNamespaceMembership *domain.NamespaceMembership, err error) {
+	auditEvent := domain.NewAuditEvent(createNamespaceMembership.Actor, domain.AuditNamespaceMemberAdd, domain.AuditTargetMembership, createNamespaceMembership.UserID)
+	auditEvent.NamespaceID = createNamespaceMembership.NamespaceID
+	auditEvent.TargetName = createNamespaceMembership.UserID
+	auditEvent.Changes = domain.NewAuditChanges(nil, map[string]domain.Role{"role": createNamespaceMembership.Role})
+	defer func() {
+		createNamespaceMembershipUseCase.RecordAuditEventUseCase.Execute(auditEvent, err)
+	}()
+
+	foundNamespaceByID, err := createNamespaceMembershipUseCase.NamespaceRepository.FindByID(createNamespaceMembership.NamespaceID)
+	if err != nil {
+		return nil, err
+	}
+	if foundNamespaceByID == nil {
+		return nil, errors.NewNamespaceNotFoundByIDError(createNamespaceMembership.NamespaceID)
+	}
+
+	if err := foundNamespaceByID.Authorize(createNamespaceMembership.AddedBy, domain.PermissionManageNamespaceMembers); err != nil {
+		return nil, err
+	}
+	// Only the owner can give the roles that manage the namespace
+	if createNamespaceMembership.Role == domain.RoleOwner || createNamespaceMembership.Role == domain.RoleAdmin {
+		if err := foundNamespaceByID.Authorize(createNamespaceMembership.AddedBy, domain.PermissionManageNamespaceAdmins); err != nil {
+			return nil, err
+		}
+	}
+
 	foundNamespaceMembershipByID, err := createNamespaceMembershipUseCase.NamespaceMembershipRepository.ExistsByNamespaceIDAndUserID(createNamespaceMembership.NamespaceID, createNamespaceMembership.UserID)
 	if err != nil {
 		return nil, err
@@ -21,7 +51,7 @@ func (createNamespaceMembershipUseCase CreateNamespaceMembershipUseCase) Execute
 		return nil, fmt.Errorf("namespace membership %s already exists", createNamespaceMembership.NamespaceID)
 	}
 
-	createdNamespaceMembership, err := createNamespaceMembershipUseCase.NamespaceMembershipRepository.Create(createNamespaceMembership)
+	createdNamespaceMembership, err = createNamespaceMembershipUseCase.NamespaceMembershipRepository.Create(createNamespaceMembership)
 	if err != nil {
 		fmt.Println(fmt.Errorf("error creating namespace membership (%s): %w", createNamespaceMembership.NamespaceID, err))
 		return nil, err
@@ -0,0 +1,75 @@
+package namespaces
+
+import (
+	"cloud-app-hive/controllers/errors"
+	"fmt"
+
+	"cloud-app-hive/domain"
+	"cloud-app-hive/domain/commands"
+	"cloud-app-hive/domain/repositories"
+	"cloud-app-hive/use_cases"
+)
+
+type CreateNetworkRuleUseCase struct {
+	NamespaceRepository      repositories.NamespaceRepository
+	NetworkRuleRepository    repositories.NetworkRuleRepository
+	ApplyNetworkRulesUseCase use_cases.ApplyNetworkRulesUseCase
+	RecordAuditEventUseCase  use_cases.RecordAuditEventUseCase
+}
+
+// Execute stores a network rule and applies the rules of the namespace.
+// The user must be able to read the namespace the traffic comes from, so that rules only reference namespaces they know.
+func (createNetworkRuleUseCase CreateNetworkRuleUseCase) Execute(createNetworkRule commands.CreateNetworkRule) (createdNetworkRule *domain.NetworkRule, err error) {
+	auditEvent := domain.NewAuditEvent(createNetworkRule.Actor, domain.AuditNetworkRuleCreate, domain.AuditTargetNetworkRule, "")
+	auditEvent.NamespaceID = createNetworkRule.NamespaceID
+	defer func() {
+		if createdNetworkRule != nil {
+			auditEvent.TargetID = createdNetworkRule.ID
+			auditEvent.Changes = domain.NewAuditChanges(nil, createdNetworkRule)
+		}
+		createNetworkRuleUseCase.RecordAuditEventUseCase.Execute(auditEvent, err)
+	}()
+
+	namespace, err := createNetworkRuleUseCase.NamespaceRepository.FindByID(createNetworkRule.NamespaceID)
+	if err != nil {
+		return nil, err
+	}
+	if namespace == nil {
+		return nil, errors.NewNamespaceNotFoundByIDError(createNetworkRule.NamespaceID)
+	}
+	if err := namespace.Authorize(createNetworkRule.CreatedBy, domain.PermissionManageNetworkRules); err != nil {
+		return nil, err
+	}
+	if createNetworkRule.ApplicationID != nil && namespace.FindApplicationByID(*createNetworkRule.ApplicationID) == nil {
+		return nil, errors.NewInvalidNetworkRuleError(fmt.Sprintf("application %s is not in namespace %s", *createNetworkRule.ApplicationID, namespace.ID))
+	}
+
+	if createNetworkRule.FromNamespaceID == namespace.ID {
+		return nil, errors.NewInvalidNetworkRuleError("the applications of a namespace can always reach each other")
+	}
+	fromNamespace, err := createNetworkRuleUseCase.NamespaceRepository.FindByID(createNetworkRule.FromNamespaceID)
+	if err != nil || fromNamespace == nil {
+		return nil, errors.NewNamespaceNotFoundByIDError(createNetworkRule.FromNamespaceID)
+	}
+	if err := fromNamespace.Authorize(createNetworkRule.CreatedBy, domain.PermissionReadNamespace); err != nil {
+		return nil, err
+	}
+	if createNetworkRule.FromApplicationID != nil && fromNamespace.FindApplicationByID(*createNetworkRule.FromApplicationID) == nil {
+		return nil, errors.NewInvalidNetworkRuleError(fmt.Sprintf("application %s is not in namespace %s", *createNetworkRule.FromApplicationID, fromNamespace.ID))
+	}
+
+	networkRule, err := createNetworkRuleUseCase.NetworkRuleRepository.Create(createNetworkRule)
+	if err != nil {
+		return nil, err
+	}
+
+	// A rule that is not applied is deleted, so that the stored rules match the cluster
+	if err := createNetworkRuleUseCase.ApplyNetworkRulesUseCase.Execute(namespace.ID); err != nil {
+		if deleteErr := createNetworkRuleUseCase.NetworkRuleRepository.Delete(networkRule.ID); deleteErr != nil {
+			fmt.Println(fmt.Errorf("error deleting network rule %s that could not be applied: %w", networkRule.ID, deleteErr))
+		}
+		return nil, err
+	}
+
+	return networkRule, nil
+}
@@ -0,0 +1,69 @@
+package namespaces
+
+import (
+	"cloud-app-hive/controllers/errors"
+	"fmt"
+
+	"cloud-app-hive/domain"
+	"cloud-app-hive/domain/commands"
+	"cloud-app-hive/domain/repositories"
+	"cloud-app-hive/use_cases"
+)
+
+type CreateRegistryCredentialUseCase struct {
+	NamespaceRepository          repositories.NamespaceRepository
+	RegistryCredentialRepository repositories.RegistryCredentialRepository
+	RecordAuditEventUseCase      use_cases.RecordAuditEventUseCase
+}
+
+// Execute stores a registry credential of a namespace, its password is encrypted with the data key of the namespace
+func (createRegistryCredentialUseCase CreateRegistryCredentialUseCase) Execute(createRegistryCredential commands.CreateRegistryCredential) (createdRegistryCredential *domain.RegistryCredential, err error) {
+	auditEvent := domain.NewAuditEvent(createRegistryCredential.Actor, domain.AuditRegistryCredentialCreate, domain.AuditTargetRegistryCredential, "")
+	auditEvent.NamespaceID = createRegistryCredential.NamespaceID
+	auditEvent.TargetName = createRegistryCredential.Name
+	defer func() {
+		if createdRegistryCredential != nil {
+			auditEvent.TargetID = createdRegistryCredential.ID
+			auditEvent.Changes = domain.NewAuditChanges(nil, createdRegistryCredential)
+		}
+		createRegistryCredentialUseCase.RecordAuditEventUseCase.Execute(auditEvent, err)
+	}()
+
+	namespace, err := createRegistryCredentialUseCase.NamespaceRepository.FindByID(createRegistryCredential.NamespaceID)
+	if err != nil {
+		return nil, err
+	}
+	if namespace == nil {
+		return nil, errors.NewNamespaceNotFoundByIDError(createRegistryCredential.NamespaceID)
+	}
+	if err := namespace.Authorize(createRegistryCredential.CreatedBy, domain.PermissionManageRegistries); err != nil {
+		return nil, err
+	}
+
+	if err := domain.ValidateRegistryCredentialName(createRegistryCredential.Name); err != nil {
+		return nil, errors.NewInvalidRegistryCredentialError(err.Error())
+	}
+	server, err := domain.NormalizeRegistryServer(createRegistryCredential.Server)
+	if err != nil {
+		return nil, errors.NewInvalidRegistryCredentialError(err.Error())
+	}
+	registryCredentials, err := createRegistryCredentialUseCase.RegistryCredentialRepository.FindByNamespaceID(namespace.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, registryCredential := range registryCredentials {
+		if registryCredential.Name == createRegistryCredential.Name {
+			return nil, errors.NewInvalidRegistryCredentialError(fmt.Sprintf("namespace %s already has a registry credential named %s", namespace.ID, createRegistryCredential.Name))
+		}
+	}
+
+	return createRegistryCredentialUseCase.RegistryCredentialRepository.Create(domain.RegistryCredential{
+		NamespaceID:     namespace.ID,
+		Name:            createRegistryCredential.Name,
+		Server:          server,
+		Username:        createRegistryCredential.Username,
+		Password:        createRegistryCredential.Password,
+		PasswordPreview: domain.MaskSecretValue(createRegistryCredential.Password),
+		CreatedBy:       createRegistryCredential.CreatedBy,
+	})
+}
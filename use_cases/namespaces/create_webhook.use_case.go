@@ -0,0 +1,66 @@
+package namespaces
+
+import (
+	"cloud-app-hive/controllers/errors"
+
+	"cloud-app-hive/domain"
+	"cloud-app-hive/domain/commands"
+	"cloud-app-hive/domain/repositories"
+	"cloud-app-hive/use_cases"
+)
+
+type CreateWebhookUseCase struct {
+	NamespaceRepository     repositories.NamespaceRepository
+	WebhookRepository       repositories.WebhookRepository
+	RecordAuditEventUseCase use_cases.RecordAuditEventUseCase
+}
+
+// Execute stores a webhook of a namespace with a new random secret.
+// It returns the webhook and its secret, which is stored encrypted and never returned again.
+func (createWebhookUseCase CreateWebhookUseCase) Execute(createWebhook commands.CreateWebhook) (createdWebhook *domain.Webhook, secret string, err error) {
+	auditEvent := domain.NewAuditEvent(createWebhook.Actor, domain.AuditWebhookCreate, domain.AuditTargetWebhook, "")
+	auditEvent.NamespaceID = createWebhook.NamespaceID
+	auditEvent.TargetName = createWebhook.URL
+	defer func() {
+		if createdWebhook != nil {
+			auditEvent.TargetID = createdWebhook.ID
+			auditEvent.Changes = domain.NewAuditChanges(nil, createdWebhook)
+		}
+		createWebhookUseCase.RecordAuditEventUseCase.Execute(auditEvent, err)
+	}()
+
+	namespace, err := createWebhookUseCase.NamespaceRepository.FindByID(createWebhook.NamespaceID)
+	if err != nil {
+		return nil, "", err
+	}
+	if namespace == nil {
+		return nil, "", errors.NewNamespaceNotFoundByIDError(createWebhook.NamespaceID)
+	}
+	if err := namespace.Authorize(createWebhook.CreatedBy, domain.PermissionManageWebhooks); err != nil {
+		return nil, "", err
+	}
+
+	if err := domain.ValidateWebhookURL(createWebhook.URL); err != nil {
+		return nil, "", errors.NewInvalidWebhookError(err.Error())
+	}
+	if err := createWebhook.EventTypes.Validate(); err != nil {
+		return nil, "", errors.NewInvalidWebhookError(err.Error())
+	}
+
+	secret, err = domain.NewWebhookSecret()
+	if err != nil {
+		return nil, "", err
+	}
+	createdWebhook, err = createWebhookUseCase.WebhookRepository.Create(domain.Webhook{
+		NamespaceID: namespace.ID,
+		URL:         createWebhook.URL,
+		EventTypes:  createWebhook.EventTypes,
+		Active:      true,
+		Secret:      secret,
+		CreatedBy:   createWebhook.CreatedBy,
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	return createdWebhook, secret, nil
+}
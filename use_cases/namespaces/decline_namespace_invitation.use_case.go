@@ -0,0 +1,48 @@
+package namespaces
+
+import (
+	"cloud-app-hive/controllers/errors"
+	"time"
+
+	"cloud-app-hive/domain"
+	"cloud-app-hive/domain/commands"
+	"cloud-app-hive/domain/repositories"
+	"cloud-app-hive/use_cases"
+)
+
+type DeclineNamespaceInvitationUseCase struct {
+	NamespaceInvitationRepository repositories.NamespaceInvitationRepository
+	RecordAuditEventUseCase       use_cases.RecordAuditEventUseCase
+}
+
+// Execute declines a pending invitation, its token cannot be used anymore
+func (declineNamespaceInvitationUseCase DeclineNamespaceInvitationUseCase) Execute(respondToNamespaceInvitation commands.RespondToNamespaceInvitation) (declinedNamespaceInvitation *domain.NamespaceInvitation, err error) {
+	auditEvent := domain.NewAuditEvent(respondToNamespaceInvitation.Actor, domain.AuditNamespaceInviteDecline, domain.AuditTargetInvitation, "")
+	defer func() {
+		declineNamespaceInvitationUseCase.RecordAuditEventUseCase.Execute(auditEvent, err)
+	}()
+
+	now := time.Now().UTC()
+	invitation, err := findNamespaceInvitationToRespond(declineNamespaceInvitationUseCase.NamespaceInvitationRepository, respondToNamespaceInvitation, now)
+	if invitation != nil {
+		auditEvent.NamespaceID = invitation.NamespaceID
+		auditEvent.TargetID = invitation.ID
+		auditEvent.TargetName = invitation.Email
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := declineNamespaceInvitationUseCase.NamespaceInvitationRepository.UpdateStatus(invitation.ID, domain.NamespaceInvitationDeclined, &respondToNamespaceInvitation.UserID, now)
+	if err != nil {
+		return nil, err
+	}
+	if !updated {
+		return nil, errors.NewNamespaceInvitationNotPendingError(invitation.ID, "no longer pending")
+	}
+
+	invitation.Status = domain.NamespaceInvitationDeclined
+	invitation.RespondedBy = &respondToNamespaceInvitation.UserID
+	invitation.RespondedAt = &now
+	return invitation, nil
+}
@@ -0,0 +1,69 @@
+package namespaces
+
+import (
+	"cloud-app-hive/controllers/errors"
+
+	"cloud-app-hive/domain"
+	"cloud-app-hive/domain/commands"
+	"cloud-app-hive/domain/repositories"
+	"cloud-app-hive/use_cases"
+)
+
+type DeleteAddonUseCase struct {
+	NamespaceRepository        repositories.NamespaceRepository
+	AddonRepository            repositories.AddonRepository
+	ContainerManagerRepository repositories.ContainerManagerRepository
+	RecordAuditEventUseCase    use_cases.RecordAuditEventUseCase
+}
+
+// Execute deletes an add-on that no application is bound to anymore, with its data
+func (deleteAddonUseCase DeleteAddonUseCase) Execute(deleteAddon commands.DeleteAddon) (deletedAddon *domain.Addon, err error) {
+	auditEvent := domain.NewAuditEvent(deleteAddon.Actor, domain.AuditAddonDelete, domain.AuditTargetAddon, deleteAddon.AddonID)
+	auditEvent.NamespaceID = deleteAddon.NamespaceID
+	defer func() {
+		deleteAddonUseCase.RecordAuditEventUseCase.Execute(auditEvent, err)
+	}()
+
+	namespace, err := deleteAddonUseCase.NamespaceRepository.FindByID(deleteAddon.NamespaceID)
+	if err != nil {
+		return nil, err
+	}
+	if namespace == nil {
+		return nil, errors.NewNamespaceNotFoundByIDError(deleteAddon.NamespaceID)
+	}
+	if err := namespace.Authorize(deleteAddon.DeletedBy, domain.PermissionManageAddons); err != nil {
+		return nil, err
+	}
+
+	addon, err := deleteAddonUseCase.AddonRepository.FindByID(deleteAddon.AddonID)
+	if err != nil {
+		return nil, err
+	}
+	if addon == nil || addon.NamespaceID != namespace.ID {
+		return nil, errors.NewAddonNotFoundError(deleteAddon.AddonID)
+	}
+	auditEvent.TargetName = addon.Name
+	auditEvent.Changes = domain.NewAuditChanges(addon, nil)
+
+	// Bound applications would lose their database while still reading its connection settings
+	if len(addon.Bindings) > 0 {
+		applicationNames := []string{}
+		for _, binding := range addon.Bindings {
+			if application := namespace.FindApplicationByID(binding.ApplicationID); application != nil {
+				applicationNames = append(applicationNames, application.Name)
+			}
+		}
+		return nil, errors.NewAddonInUseError(addon.ID, applicationNames)
+	}
+
+	if err := deleteAddonUseCase.ContainerManagerRepository.UnapplyAddon(commands.UnapplyAddon{
+		Name:      addon.Name,
+		Namespace: namespace.Name,
+	}); err != nil {
+		return nil, err
+	}
+	if err := deleteAddonUseCase.AddonRepository.Delete(addon.ID); err != nil {
+		return nil, err
+	}
+	return addon, nil
+}
@@ -0,0 +1,63 @@
+package namespaces
+
+import (
+	"cloud-app-hive/controllers/errors"
+
+	"cloud-app-hive/domain"
+	"cloud-app-hive/domain/commands"
+	"cloud-app-hive/domain/repositories"
+	"cloud-app-hive/use_cases"
+)
+
+type DeleteConfigGroupUseCase struct {
+	NamespaceRepository        repositories.NamespaceRepository
+	ConfigGroupRepository      repositories.ConfigGroupRepository
+	ContainerManagerRepository repositories.ContainerManagerRepository
+	RecordAuditEventUseCase    use_cases.RecordAuditEventUseCase
+}
+
+// Execute deletes a config group that no application is attached to anymore
+func (deleteConfigGroupUseCase DeleteConfigGroupUseCase) Execute(deleteConfigGroup commands.DeleteConfigGroup) (deletedConfigGroup *domain.ConfigGroup, err error) {
+	auditEvent := domain.NewAuditEvent(deleteConfigGroup.Actor, domain.AuditConfigGroupDelete, domain.AuditTargetConfigGroup, deleteConfigGroup.ConfigGroupID)
+	auditEvent.NamespaceID = deleteConfigGroup.NamespaceID
+	defer func() {
+		deleteConfigGroupUseCase.RecordAuditEventUseCase.Execute(auditEvent, err)
+	}()
+
+	namespace, err := deleteConfigGroupUseCase.NamespaceRepository.FindByID(deleteConfigGroup.NamespaceID)
+	if err != nil {
+		return nil, err
+	}
+	if namespace == nil {
+		return nil, errors.NewNamespaceNotFoundByIDError(deleteConfigGroup.NamespaceID)
+	}
+	if err := namespace.Authorize(deleteConfigGroup.DeletedBy, domain.PermissionManageConfigGroups); err != nil {
+		return nil, err
+	}
+
+	configGroup, err := deleteConfigGroupUseCase.ConfigGroupRepository.FindByID(deleteConfigGroup.ConfigGroupID)
+	if err != nil {
+		return nil, err
+	}
+	if configGroup == nil || configGroup.NamespaceID != namespace.ID {
+		return nil, errors.NewConfigGroupNotFoundError(deleteConfigGroup.ConfigGroupID)
+	}
+	auditEvent.TargetName = configGroup.Name
+	auditEvent.Changes = domain.NewAuditChanges(configGroup, nil)
+
+	// The pods of attached applications would not start anymore without the ConfigMap and the Secret of the group
+	if len(configGroup.Attachments) > 0 {
+		return nil, errors.NewConfigGroupInUseError(configGroup.ID, configGroup.ApplicationNames(namespace.Applications))
+	}
+
+	if err := deleteConfigGroupUseCase.ContainerManagerRepository.UnapplyConfigGroup(commands.UnapplyConfigGroup{
+		Name:      configGroup.Name,
+		Namespace: namespace.Name,
+	}); err != nil {
+		return nil, err
+	}
+	if err := deleteConfigGroupUseCase.ConfigGroupRepository.Delete(configGroup.ID); err != nil {
+		return nil, err
+	}
+	return configGroup, nil
+}
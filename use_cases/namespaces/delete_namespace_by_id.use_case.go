@@ -5,14 +5,22 @@ import (
 
 	"cloud-app-hive/domain"
 	"cloud-app-hive/domain/repositories"
+	"cloud-app-hive/use_cases"
 )
 
 type DeleteNamespaceByIDUseCase struct {
 	NamespaceRepository        repositories.NamespaceRepository
 	ContainerManagerRepository repositories.ContainerManagerRepository
+	RecordAuditEventUseCase    use_cases.RecordAuditEventUseCase
 }
 
-func (deleteNamespaceByIDUseCase DeleteNamespaceByIDUseCase) Execute(id string, userId string) (*domain.Namespace, error) {
+func (deleteNamespaceByIDUseCase DeleteNamespaceByIDUseCase) Execute(id string, userId string, actor domain.AuditActor) (namespace *domain.Namespace, err error) {
+	auditEvent := domain.NewAuditEvent(actor, domain.AuditNamespaceDelete, domain.AuditTargetNamespace, id)
+	auditEvent.NamespaceID = id
+	defer func() {
+		deleteNamespaceByIDUseCase.RecordAuditEventUseCase.Execute(auditEvent, err)
+	}()
+
 	foundNamespace, err := deleteNamespaceByIDUseCase.NamespaceRepository.FindByID(id)
 	if err != nil {
 		return nil, err
@@ -20,16 +28,10 @@ func (deleteNamespaceByIDUseCase DeleteNamespaceByIDUseCase) Execute(id string,
 	if foundNamespace == nil {
 		return nil, fmt.Errorf("namespace not found with ID %s while deleting", id)
 	}
+	auditEvent.TargetName = foundNamespace.Name
 
-	isAdmin := false
-	for _, member := range foundNamespace.Memberships {
-		if member.UserID == userId && member.Role == domain.RoleAdmin {
-			isAdmin = true
-			break
-		}
-	}
-	if !isAdmin {
-		return nil, fmt.Errorf("user %s is not admin of namespace %s, he cannot delete namespace", userId, id)
+	if err := foundNamespace.Authorize(userId, domain.PermissionDeleteNamespace); err != nil {
+		return nil, err
 	}
 
 	err = deleteNamespaceByIDUseCase.ContainerManagerRepository.DeleteNamespace(foundNamespace.Name)
@@ -37,7 +39,7 @@ func (deleteNamespaceByIDUseCase DeleteNamespaceByIDUseCase) Execute(id string,
 		return nil, err
 	}
 
-	namespace, err := deleteNamespaceByIDUseCase.NamespaceRepository.Delete(id, userId)
+	namespace, err = deleteNamespaceByIDUseCase.NamespaceRepository.Delete(id, userId)
 	if err != nil {
 		fmt.Println(err)
 		return nil, err
@@ -0,0 +1,56 @@
+package namespaces
+
+import (
+	"cloud-app-hive/controllers/errors"
+
+	"cloud-app-hive/domain"
+	"cloud-app-hive/domain/commands"
+	"cloud-app-hive/domain/repositories"
+	"cloud-app-hive/use_cases"
+)
+
+type DeleteNetworkRuleUseCase struct {
+	NamespaceRepository      repositories.NamespaceRepository
+	NetworkRuleRepository    repositories.NetworkRuleRepository
+	ApplyNetworkRulesUseCase use_cases.ApplyNetworkRulesUseCase
+	RecordAuditEventUseCase  use_cases.RecordAuditEventUseCase
+}
+
+// Execute deletes a network rule and applies the remaining rules of the namespace.
+// When they cannot be applied, the traffic stays allowed until the next deployment of the namespace.
+func (deleteNetworkRuleUseCase DeleteNetworkRuleUseCase) Execute(deleteNetworkRule commands.DeleteNetworkRule) (deletedNetworkRule *domain.NetworkRule, err error) {
+	auditEvent := domain.NewAuditEvent(deleteNetworkRule.Actor, domain.AuditNetworkRuleDelete, domain.AuditTargetNetworkRule, deleteNetworkRule.NetworkRuleID)
+	auditEvent.NamespaceID = deleteNetworkRule.NamespaceID
+	defer func() {
+		deleteNetworkRuleUseCase.RecordAuditEventUseCase.Execute(auditEvent, err)
+	}()
+
+	namespace, err := deleteNetworkRuleUseCase.NamespaceRepository.FindByID(deleteNetworkRule.NamespaceID)
+	if err != nil {
+		return nil, err
+	}
+	if namespace == nil {
+		return nil, errors.NewNamespaceNotFoundByIDError(deleteNetworkRule.NamespaceID)
+	}
+	if err := namespace.Authorize(deleteNetworkRule.DeletedBy, domain.PermissionManageNetworkRules); err != nil {
+		return nil, err
+	}
+
+	networkRule, err := deleteNetworkRuleUseCase.NetworkRuleRepository.FindByID(deleteNetworkRule.NetworkRuleID)
+	if err != nil {
+		return nil, err
+	}
+	if networkRule == nil || networkRule.NamespaceID != namespace.ID {
+		return nil, errors.NewNetworkRuleNotFoundError(deleteNetworkRule.NetworkRuleID)
+	}
+	auditEvent.Changes = domain.NewAuditChanges(networkRule, nil)
+
+	if err := deleteNetworkRuleUseCase.NetworkRuleRepository.Delete(networkRule.ID); err != nil {
+		return nil, err
+	}
+	if err := deleteNetworkRuleUseCase.ApplyNetworkRulesUseCase.Execute(namespace.ID); err != nil {
+		return nil, err
+	}
+
+	return networkRule, nil
+}
@@ -0,0 +1,60 @@
+package namespaces
+
+import (
+	"cloud-app-hive/controllers/errors"
+
+	"cloud-app-hive/domain"
+	"cloud-app-hive/domain/commands"
+	"cloud-app-hive/domain/repositories"
+	"cloud-app-hive/use_cases"
+)
+
+type DeleteRegistryCredentialUseCase struct {
+	NamespaceRepository          repositories.NamespaceRepository
+	RegistryCredentialRepository repositories.RegistryCredentialRepository
+	RecordAuditEventUseCase      use_cases.RecordAuditEventUseCase
+}
+
+// Execute deletes a registry credential that no application of the namespace uses anymore
+func (deleteRegistryCredentialUseCase DeleteRegistryCredentialUseCase) Execute(deleteRegistryCredential commands.DeleteRegistryCredential) (deletedRegistryCredential *domain.RegistryCredential, err error) {
+	auditEvent := domain.NewAuditEvent(deleteRegistryCredential.Actor, domain.AuditRegistryCredentialDelete, domain.AuditTargetRegistryCredential, deleteRegistryCredential.RegistryCredentialID)
+	auditEvent.NamespaceID = deleteRegistryCredential.NamespaceID
+	defer func() {
+		deleteRegistryCredentialUseCase.RecordAuditEventUseCase.Execute(auditEvent, err)
+	}()
+
+	namespace, err := deleteRegistryCredentialUseCase.NamespaceRepository.FindByID(deleteRegistryCredential.NamespaceID)
+	if err != nil {
+		return nil, err
+	}
+	if namespace == nil {
+		return nil, errors.NewNamespaceNotFoundByIDError(deleteRegistryCredential.NamespaceID)
+	}
+	if err := namespace.Authorize(deleteRegistryCredential.DeletedBy, domain.PermissionManageRegistries); err != nil {
+		return nil, err
+	}
+
+	registryCredential, err := deleteRegistryCredentialUseCase.RegistryCredentialRepository.FindByID(deleteRegistryCredential.RegistryCredentialID)
+	if err != nil {
+		return nil, err
+	}
+	if registryCredential == nil || registryCredential.NamespaceID != namespace.ID {
+		return nil, errors.NewRegistryCredentialNotFoundError(deleteRegistryCredential.RegistryCredentialID)
+	}
+	auditEvent.TargetName = registryCredential.Name
+	auditEvent.Changes = domain.NewAuditChanges(registryCredential, nil)
+
+	// Applications would not be able to pull their image anymore once restarted
+	if applications := namespace.ApplicationsUsingRegistryCredential(registryCredential.ID); len(applications) > 0 {
+		applicationNames := []string{}
+		for _, application := range applications {
+			applicationNames = append(applicationNames, application.Name)
+		}
+		return nil, errors.NewRegistryCredentialInUseError(registryCredential.ID, applicationNames)
+	}
+
+	if err := deleteRegistryCredentialUseCase.RegistryCredentialRepository.Delete(registryCredential.ID); err != nil {
+		return nil, err
+	}
+	return registryCredential, nil
+}
@@ -0,0 +1,51 @@
+package namespaces
+
+import (
+	"cloud-app-hive/controllers/errors"
+
+	"cloud-app-hive/domain"
+	"cloud-app-hive/domain/commands"
+	"cloud-app-hive/domain/repositories"
+	"cloud-app-hive/use_cases"
+)
+
+type DeleteWebhookUseCase struct {
+	NamespaceRepository     repositories.NamespaceRepository
+	WebhookRepository       repositories.WebhookRepository
+	RecordAuditEventUseCase use_cases.RecordAuditEventUseCase
+}
+
+// Execute deletes a webhook, the events being retried are still delivered to it
+func (deleteWebhookUseCase DeleteWebhookUseCase) Execute(deleteWebhook commands.DeleteWebhook) (deletedWebhook *domain.Webhook, err error) {
+	auditEvent := domain.NewAuditEvent(deleteWebhook.Actor, domain.AuditWebhookDelete, domain.AuditTargetWebhook, deleteWebhook.WebhookID)
+	auditEvent.NamespaceID = deleteWebhook.NamespaceID
+	defer func() {
+		deleteWebhookUseCase.RecordAuditEventUseCase.Execute(auditEvent, err)
+	}()
+
+	namespace, err := deleteWebhookUseCase.NamespaceRepository.FindByID(deleteWebhook.NamespaceID)
+	if err != nil {
+		return nil, err
+	}
+	if namespace == nil {
+		return nil, errors.NewNamespaceNotFoundByIDError(deleteWebhook.NamespaceID)
+	}
+	if err := namespace.Authorize(deleteWebhook.DeletedBy, domain.PermissionManageWebhooks); err != nil {
+		return nil, err
+	}
+
+	webhook, err := deleteWebhookUseCase.WebhookRepository.FindByID(deleteWebhook.WebhookID)
+	if err != nil {
+		return nil, err
+	}
+	if webhook == nil || webhook.NamespaceID != namespace.ID {
+		return nil, errors.NewWebhookNotFoundError(deleteWebhook.WebhookID)
+	}
+	auditEvent.TargetName = webhook.URL
+	auditEvent.Changes = domain.NewAuditChanges(webhook, nil)
+
+	if err := deleteWebhookUseCase.WebhookRepository.Delete(webhook.ID); err != nil {
+		return nil, err
+	}
+	return webhook, nil
+}
@@ -0,0 +1,46 @@
+package namespaces
+
+import (
+	"cloud-app-hive/controllers/errors"
+	"fmt"
+
+	"cloud-app-hive/domain"
+	"cloud-app-hive/domain/repositories"
+)
+
+type ExportNamespaceManifestUseCase struct {
+	NamespaceRepository   repositories.NamespaceRepository
+	ApplicationRepository repositories.ApplicationRepository
+}
+
+func (exportNamespaceManifestUseCase ExportNamespaceManifestUseCase) Execute(namespaceID string, userID string) (*domain.NamespaceManifest, error) {
+	namespace, err := exportNamespaceManifestUseCase.NamespaceRepository.FindByID(namespaceID)
+	if err != nil {
+		return nil, err
+	}
+	if namespace == nil {
+		return nil, errors.NewNamespaceNotFoundByIDError(namespaceID)
+	}
+
+	if err := namespace.Authorize(userID, domain.PermissionReadNamespace); err != nil {
+		return nil, err
+	}
+
+	namespaceApplications, err := exportNamespaceManifestUseCase.ApplicationRepository.FindByNamespaceIDAndUserID(namespaceID)
+	if err != nil {
+		return nil, fmt.Errorf("error while finding applications by namespace id: %w", err)
+	}
+
+	// JSON fields are only filled when applications are found one by one
+	applications := make([]domain.Application, 0, len(namespaceApplications))
+	for _, namespaceApplication := range namespaceApplications {
+		application, err := exportNamespaceManifestUseCase.ApplicationRepository.FindByID(namespaceApplication.ID)
+		if err != nil {
+			return nil, fmt.Errorf("error while finding application by id: %w", err)
+		}
+		applications = append(applications, *application)
+	}
+
+	manifest := domain.NewNamespaceManifest(*namespace, applications)
+	return &manifest, nil
+}
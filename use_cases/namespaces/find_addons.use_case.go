@@ -0,0 +1,29 @@
+package namespaces
+
+import (
+	"cloud-app-hive/controllers/errors"
+
+	"cloud-app-hive/domain"
+	"cloud-app-hive/domain/repositories"
+)
+
+type FindAddonsUseCase struct {
+	NamespaceRepository repositories.NamespaceRepository
+	AddonRepository     repositories.AddonRepository
+}
+
+// Execute returns the add-ons of a namespace with their bindings to its members, credentials are never returned
+func (findAddonsUseCase FindAddonsUseCase) Execute(namespaceID string, userID string) ([]domain.Addon, error) {
+	namespace, err := findAddonsUseCase.NamespaceRepository.FindByID(namespaceID)
+	if err != nil {
+		return nil, err
+	}
+	if namespace == nil {
+		return nil, errors.NewNamespaceNotFoundByIDError(namespaceID)
+	}
+	if err := namespace.Authorize(userID, domain.PermissionReadNamespace); err != nil {
+		return nil, err
+	}
+
+	return findAddonsUseCase.AddonRepository.FindByNamespaceID(namespaceID)
+}
@@ -0,0 +1,60 @@
+package namespaces
+
+import (
+	"cloud-app-hive/controllers/errors"
+
+	"cloud-app-hive/domain"
+	"cloud-app-hive/domain/commands"
+	"cloud-app-hive/domain/repositories"
+)
+
+// auditEventsExportPageSize is the number of events read at once while exporting the audit log
+const auditEventsExportPageSize = 500
+
+type FindAuditEventsUseCase struct {
+	NamespaceRepository  repositories.NamespaceRepository
+	AuditEventRepository repositories.AuditEventRepository
+}
+
+// Execute returns a page of the audit log of a namespace
+func (findAuditEventsUseCase FindAuditEventsUseCase) Execute(findAuditEvents commands.FindAuditEvents) ([]domain.AuditEvent, error) {
+	if err := findAuditEventsUseCase.authorize(findAuditEvents); err != nil {
+		return nil, err
+	}
+	return findAuditEventsUseCase.AuditEventRepository.Find(findAuditEvents)
+}
+
+// Export calls write with every event of the audit log matching the filters, the most recent first.
+// Events are read page by page so that exporting a large audit log does not load it in memory.
+func (findAuditEventsUseCase FindAuditEventsUseCase) Export(findAuditEvents commands.FindAuditEvents, write func(auditEvent domain.AuditEvent) error) error {
+	if err := findAuditEventsUseCase.authorize(findAuditEvents); err != nil {
+		return err
+	}
+
+	findAuditEvents.Limit = auditEventsExportPageSize
+	for findAuditEvents.Page = 1; ; findAuditEvents.Page++ {
+		auditEvents, err := findAuditEventsUseCase.AuditEventRepository.Find(findAuditEvents)
+		if err != nil {
+			return err
+		}
+		for _, auditEvent := range auditEvents {
+			if err := write(auditEvent); err != nil {
+				return err
+			}
+		}
+		if len(auditEvents) < auditEventsExportPageSize {
+			return nil
+		}
+	}
+}
+
+func (findAuditEventsUseCase FindAuditEventsUseCase) authorize(findAuditEvents commands.FindAuditEvents) error {
+	namespace, err := findAuditEventsUseCase.NamespaceRepository.FindByID(findAuditEvents.NamespaceID)
+	if err != nil {
+		return err
+	}
+	if namespace == nil {
+		return errors.NewNamespaceNotFoundByIDError(findAuditEvents.NamespaceID)
+	}
+	return namespace.Authorize(findAuditEvents.UserID, domain.PermissionReadNamespaceAudit)
+}
@@ -0,0 +1,29 @@
+package namespaces
+
+import (
+	"cloud-app-hive/controllers/errors"
+
+	"cloud-app-hive/domain"
+	"cloud-app-hive/domain/repositories"
+)
+
+type FindConfigGroupsUseCase struct {
+	NamespaceRepository   repositories.NamespaceRepository
+	ConfigGroupRepository repositories.ConfigGroupRepository
+}
+
+// Execute returns the config groups of a namespace with their attachments to its members, secret values are never returned
+func (findConfigGroupsUseCase FindConfigGroupsUseCase) Execute(namespaceID string, userID string) ([]domain.ConfigGroup, error) {
+	namespace, err := findConfigGroupsUseCase.NamespaceRepository.FindByID(namespaceID)
+	if err != nil {
+		return nil, err
+	}
+	if namespace == nil {
+		return nil, errors.NewNamespaceNotFoundByIDError(namespaceID)
+	}
+	if err := namespace.Authorize(userID, domain.PermissionReadNamespace); err != nil {
+		return nil, err
+	}
+
+	return findConfigGroupsUseCase.ConfigGroupRepository.FindByNamespaceID(namespaceID)
+}
@@ -23,10 +23,8 @@ func (findNamespacesUseCase FindNamespacesUseCase) Execute(findNamespaces comman
 	}
 	// Check that user has access to all namespaces
 	for _, namespace := range namespaces {
-		for _, namespaceMembership := range namespace.Memberships {
-			if namespaceMembership.UserID != findNamespaces.UserID {
-				return nil, errors.NewUnauthorizedToAccessNamespaceError(namespace.ID, namespace.Name, findNamespaces.UserID)
-			}
+		if err := namespace.Authorize(findNamespaces.UserID, domain.PermissionReadNamespace); err != nil {
+			return nil, err
 		}
 	}
 	return namespaces, nil
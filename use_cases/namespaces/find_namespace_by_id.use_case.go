@@ -1,7 +1,6 @@
 package namespaces
 
 import (
-	"cloud-app-hive/controllers/errors"
 	"fmt"
 
 	"cloud-app-hive/domain"
@@ -23,15 +22,14 @@ func (findNamespaceByIDUseCase FindNamespaceByIDUseCase) Execute(id, userId stri
 		return nil, nil, fmt.Errorf("namespace not found with ID %s", id)
 	}
 
-	isMember := false
-	for _, member := range namespace.Memberships {
-		if member.UserID == userId {
-			isMember = true
-			break
-		}
+	if err := namespace.Authorize(userId, domain.PermissionReadNamespace); err != nil {
+		return nil, nil, err
 	}
-	if !isMember {
-		return nil, nil, errors.NewUnauthorizedToAccessNamespaceError(namespace.ID, namespace.Name, userId)
+	role, _ := namespace.RoleOf(userId)
+	if !role.Can(domain.PermissionReadApplicationSecrets) {
+		for i := range namespace.Applications {
+			namespace.Applications[i].Secrets = namespace.Applications[i].Secrets.NamesOnly()
+		}
 	}
 
 	userApplications, err := findNamespaceByIDUseCase.ApplicationRepository.FindByUserID(userId)
@@ -17,14 +17,8 @@ func (findNamespaceByNameUseCase FindNamespaceByNameUseCase) Execute(name string
 		fmt.Println(err)
 		return nil, err
 	}
-	userCanAccessNamespace := false
-	for _, membership := range namespace.Memberships {
-		if membership.UserID == userId {
-			userCanAccessNamespace = true
-		}
-	}
-	if userCanAccessNamespace == false {
-		return nil, fmt.Errorf("user %s does not have access to namespace %s", userId, name)
+	if err := namespace.Authorize(userId, domain.PermissionReadNamespace); err != nil {
+		return nil, err
 	}
 	return namespace, nil
 }
@@ -0,0 +1,30 @@
+package namespaces
+
+import (
+	"cloud-app-hive/controllers/errors"
+	"time"
+
+	"cloud-app-hive/domain"
+	"cloud-app-hive/domain/repositories"
+)
+
+type FindNamespaceInvitationsUseCase struct {
+	NamespaceRepository           repositories.NamespaceRepository
+	NamespaceInvitationRepository repositories.NamespaceInvitationRepository
+}
+
+// Execute returns the pending invitations of a namespace to the users who manage its members
+func (findNamespaceInvitationsUseCase FindNamespaceInvitationsUseCase) Execute(namespaceID string, userID string) ([]domain.NamespaceInvitation, error) {
+	namespace, err := findNamespaceInvitationsUseCase.NamespaceRepository.FindByID(namespaceID)
+	if err != nil {
+		return nil, err
+	}
+	if namespace == nil {
+		return nil, errors.NewNamespaceNotFoundByIDError(namespaceID)
+	}
+	if err := namespace.Authorize(userID, domain.PermissionManageNamespaceMembers); err != nil {
+		return nil, err
+	}
+
+	return findNamespaceInvitationsUseCase.NamespaceInvitationRepository.FindPendingByNamespaceID(namespaceID, time.Now().UTC())
+}
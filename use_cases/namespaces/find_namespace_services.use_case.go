@@ -0,0 +1,45 @@
+package namespaces
+
+import (
+	"fmt"
+
+	"cloud-app-hive/controllers/errors"
+	"cloud-app-hive/domain"
+	"cloud-app-hive/domain/repositories"
+)
+
+type FindNamespaceServicesUseCase struct {
+	NamespaceRepository   repositories.NamespaceRepository
+	ApplicationRepository repositories.ApplicationRepository
+}
+
+// Execute returns how the applications of a namespace reach each other, to its members
+func (findNamespaceServicesUseCase FindNamespaceServicesUseCase) Execute(namespaceID string, userID string) ([]domain.ApplicationService, error) {
+	namespace, err := findNamespaceServicesUseCase.NamespaceRepository.FindByID(namespaceID)
+	if err != nil {
+		return nil, err
+	}
+	if namespace == nil {
+		return nil, errors.NewNamespaceNotFoundByIDError(namespaceID)
+	}
+	if err := namespace.Authorize(userID, domain.PermissionReadNamespace); err != nil {
+		return nil, err
+	}
+
+	namespaceApplications, err := findNamespaceServicesUseCase.ApplicationRepository.FindByNamespaceIDAndUserID(namespaceID)
+	if err != nil {
+		return nil, fmt.Errorf("error while finding applications by namespace id: %w", err)
+	}
+	// Applications listed by namespace do not carry their dependencies
+	applications := []domain.Application{}
+	for _, namespaceApplication := range namespaceApplications {
+		application, err := findNamespaceServicesUseCase.ApplicationRepository.FindByID(namespaceApplication.ID)
+		if err != nil {
+			return nil, fmt.Errorf("error while finding application by id: %w", err)
+		}
+		if application != nil {
+			applications = append(applications, *application)
+		}
+	}
+	return domain.NewApplicationServices(*namespace, applications), nil
+}
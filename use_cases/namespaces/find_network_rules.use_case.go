@@ -0,0 +1,29 @@
+package namespaces
+
+import (
+	"cloud-app-hive/controllers/errors"
+
+	"cloud-app-hive/domain"
+	"cloud-app-hive/domain/repositories"
+)
+
+type FindNetworkRulesUseCase struct {
+	NamespaceRepository   repositories.NamespaceRepository
+	NetworkRuleRepository repositories.NetworkRuleRepository
+}
+
+// Execute returns the network rules of a namespace to its members
+func (findNetworkRulesUseCase FindNetworkRulesUseCase) Execute(namespaceID string, userID string) ([]domain.NetworkRule, error) {
+	namespace, err := findNetworkRulesUseCase.NamespaceRepository.FindByID(namespaceID)
+	if err != nil {
+		return nil, err
+	}
+	if namespace == nil {
+		return nil, errors.NewNamespaceNotFoundByIDError(namespaceID)
+	}
+	if err := namespace.Authorize(userID, domain.PermissionReadNamespace); err != nil {
+		return nil, err
+	}
+
+	return findNetworkRulesUseCase.NetworkRuleRepository.FindByNamespaceID(namespaceID)
+}
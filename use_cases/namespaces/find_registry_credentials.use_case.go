@@ -0,0 +1,29 @@
+package namespaces
+
+import (
+	"cloud-app-hive/controllers/errors"
+
+	"cloud-app-hive/domain"
+	"cloud-app-hive/domain/repositories"
+)
+
+type FindRegistryCredentialsUseCase struct {
+	NamespaceRepository          repositories.NamespaceRepository
+	RegistryCredentialRepository repositories.RegistryCredentialRepository
+}
+
+// Execute returns the registry credentials of a namespace to its members, passwords are never returned
+func (findRegistryCredentialsUseCase FindRegistryCredentialsUseCase) Execute(namespaceID string, userID string) ([]domain.RegistryCredential, error) {
+	namespace, err := findRegistryCredentialsUseCase.NamespaceRepository.FindByID(namespaceID)
+	if err != nil {
+		return nil, err
+	}
+	if namespace == nil {
+		return nil, errors.NewNamespaceNotFoundByIDError(namespaceID)
+	}
+	if err := namespace.Authorize(userID, domain.PermissionReadNamespace); err != nil {
+		return nil, err
+	}
+
+	return findRegistryCredentialsUseCase.RegistryCredentialRepository.FindByNamespaceID(namespaceID)
+}
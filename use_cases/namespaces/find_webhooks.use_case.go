@@ -0,0 +1,56 @@
+package namespaces
+
+import (
+	"cloud-app-hive/controllers/errors"
+
+	"cloud-app-hive/domain"
+	"cloud-app-hive/domain/repositories"
+)
+
+// maxWebhookDeliveries is the number of attempts returned from the delivery log of a webhook
+const maxWebhookDeliveries = 100
+
+type FindWebhooksUseCase struct {
+	NamespaceRepository       repositories.NamespaceRepository
+	WebhookRepository         repositories.WebhookRepository
+	WebhookDeliveryRepository repositories.WebhookDeliveryRepository
+}
+
+// Execute returns the webhooks of a namespace to the users managing them, secrets are never returned
+func (findWebhooksUseCase FindWebhooksUseCase) Execute(namespaceID string, userID string) ([]domain.Webhook, error) {
+	if _, err := findWebhooksUseCase.authorize(namespaceID, userID); err != nil {
+		return nil, err
+	}
+	return findWebhooksUseCase.WebhookRepository.FindByNamespaceID(namespaceID)
+}
+
+// FindDeliveries returns the last attempts to deliver events to a webhook of the namespace, most recent first.
+// Payloads describe the changes made in the namespace, so the log is restricted to the users managing the webhooks.
+func (findWebhooksUseCase FindWebhooksUseCase) FindDeliveries(namespaceID string, webhookID string, userID string) ([]domain.WebhookDelivery, error) {
+	namespace, err := findWebhooksUseCase.authorize(namespaceID, userID)
+	if err != nil {
+		return nil, err
+	}
+	webhook, err := findWebhooksUseCase.WebhookRepository.FindByID(webhookID)
+	if err != nil {
+		return nil, err
+	}
+	if webhook == nil || webhook.NamespaceID != namespace.ID {
+		return nil, errors.NewWebhookNotFoundError(webhookID)
+	}
+	return findWebhooksUseCase.WebhookDeliveryRepository.FindByWebhookID(webhook.ID, maxWebhookDeliveries)
+}
+
+func (findWebhooksUseCase FindWebhooksUseCase) authorize(namespaceID string, userID string) (*domain.Namespace, error) {
+	namespace, err := findWebhooksUseCase.NamespaceRepository.FindByID(namespaceID)
+	if err != nil {
+		return nil, err
+	}
+	if namespace == nil {
+		return nil, errors.NewNamespaceNotFoundByIDError(namespaceID)
+	}
+	if err := namespace.Authorize(userID, domain.PermissionManageWebhooks); err != nil {
+		return nil, err
+	}
+	return namespace, nil
+}
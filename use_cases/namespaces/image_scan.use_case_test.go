@@ -0,0 +1,245 @@
+package namespaces
+
+import (
+	stdErrors "errors"
+	"testing"
+	"time"
+
+	"cloud-app-hive/controllers/errors"
+	"cloud-app-hive/domain"
+	"cloud-app-hive/domain/commands"
+	"cloud-app-hive/use_cases"
+)
+
+// MockImageScanRepository keeps the image scans in memory
+type MockImageScanRepository struct {
+	ImageScans map[string]domain.ImageScan
+}
+
+func (m *MockImageScanRepository) Create(imageScan domain.ImageScan) (*domain.ImageScan, error) {
+	m.ImageScans[imageScan.ImageDigest] = imageScan
+	return &imageScan, nil
+}
+
+func (m *MockImageScanRepository) Update(imageScan domain.ImageScan) error {
+	m.ImageScans[imageScan.ImageDigest] = imageScan
+	return nil
+}
+
+func (m *MockImageScanRepository) FindByImageDigest(imageDigest string) (*domain.ImageScan, error) {
+	imageScan, ok := m.ImageScans[imageDigest]
+	if !ok {
+		return nil, nil
+	}
+	return &imageScan, nil
+}
+
+// MockImageRegistry finds every image with the same digest
+type MockImageRegistry struct{}
+
+func (m MockImageRegistry) FindImageManifest(endpoint domain.ImageRegistryEndpoint, imageReference domain.ImageReference) (*domain.ImageManifest, error) {
+	return &domain.ImageManifest{Digest: "sha256:nginx"}, nil
+}
+
+// MockImageScanner returns the same vulnerabilities for every image and counts the scans
+type MockImageScanner struct {
+	Vulnerabilities domain.ImageVulnerabilities
+	Scans           int
+}
+
+func (m *MockImageScanner) ScanImage(endpoint domain.ImageRegistryEndpoint, imageReference domain.ImageReference) (domain.ImageVulnerabilities, error) {
+	m.Scans++
+	return m.Vulnerabilities, nil
+}
+
+func newTestImageScanUseCase(policy domain.ImageScanPolicy, imageScanner *MockImageScanner) ApplyNamespaceManifestUseCase {
+	namespaceRepository := newTestNamespaceRepository()
+	findByID := namespaceRepository.FindByIDFunc
+	namespaceRepository.FindByIDFunc = func(id string) (*domain.Namespace, error) {
+		namespace, err := findByID(id)
+		namespace.ImageScanPolicy = policy
+		namespace.MaxImageVulnerabilitySeverity = domain.SeverityHigh
+		return namespace, err
+	}
+	verifyApplicationImageUseCase := use_cases.VerifyApplicationImageUseCase{ImageRegistry: MockImageRegistry{}, Architectures: []string{"amd64"}}
+	scanApplicationImageUseCase := use_cases.ScanApplicationImageUseCase{
+		ImageScanner:        imageScanner,
+		ImageScanRepository: &MockImageScanRepository{ImageScans: map[string]domain.ImageScan{}},
+	}
+	applicationRepository := newInMemoryApplicationRepository(map[string]*domain.Application{})
+	findApplicationByID := applicationRepository.FindByIDFunc
+	applicationRepository.FindByIDFunc = func(id string) (*domain.Application, error) {
+		application, err := findApplicationByID(id)
+		if application != nil {
+			application.Namespace.ImageScanPolicy = policy
+			application.Namespace.MaxImageVulnerabilitySeverity = domain.SeverityHigh
+		}
+		return application, err
+	}
+	useCase := newTestApplyNamespaceManifestUseCase(applicationRepository, &MockContainerManagerRepository{}, &MockOperationRepository{}, &MockAuditEventRepository{})
+	useCase.NamespaceRepository = namespaceRepository
+	useCase.CreateApplicationUseCase.NamespaceRepository = namespaceRepository
+	useCase.CreateApplicationUseCase.VerifyApplicationImageUseCase = verifyApplicationImageUseCase
+	useCase.CreateApplicationUseCase.ScanApplicationImageUseCase = scanApplicationImageUseCase
+	useCase.UpdateApplicationUseCase.VerifyApplicationImageUseCase = verifyApplicationImageUseCase
+	useCase.UpdateApplicationUseCase.ScanApplicationImageUseCase = scanApplicationImageUseCase
+	return useCase
+}
+
+func TestExecute_ApplyNamespaceManifest_BlocksVulnerableImages(t *testing.T) {
+	imageScanner := &MockImageScanner{Vulnerabilities: domain.ImageVulnerabilities{
+		{ID: "CVE-2024-0001", PackageName: "openssl", Severity: domain.SeverityHigh},
+		{ID: "CVE-2024-0002", PackageName: "glibc", Severity: domain.SeverityCritical},
+	}}
+	useCase := newTestImageScanUseCase(domain.ImageScanPolicyBlock, imageScanner)
+
+	result, err := useCase.Execute(commands.ApplyNamespaceManifest{NamespaceID: "namespace-id", UserID: "admin", Manifest: newTestManifest("frontend", "backend")})
+	var vulnerableImageError *errors.VulnerableImageError
+	if !stdErrors.As(err, &vulnerableImageError) {
+		t.Fatalf("Expected a vulnerable image error, got %v", err)
+	}
+	if vulnerableImageError.Count != 1 || vulnerableImageError.VulnerabilityIDs != "CVE-2024-0002" {
+		t.Errorf("Expected only the critical vulnerability to be reported, got %+v", vulnerableImageError)
+	}
+	if len(result.Created) != 0 {
+		t.Errorf("Expected no application to be created, got %v", result.Created)
+	}
+
+	// The scan of the digest is stored, applying the manifest again does not scan the image again
+	_, _ = useCase.Execute(commands.ApplyNamespaceManifest{NamespaceID: "namespace-id", UserID: "admin", Manifest: newTestManifest("frontend")})
+	if imageScanner.Scans != 1 {
+		t.Errorf("Expected the image to be scanned once, got %d scans", imageScanner.Scans)
+	}
+}
+
+func TestExecute_ApplyNamespaceManifest_WarnsAboutVulnerableImages(t *testing.T) {
+	imageScanner := &MockImageScanner{Vulnerabilities: domain.ImageVulnerabilities{{ID: "CVE-2024-0002", Severity: domain.SeverityCritical}}}
+	useCase := newTestImageScanUseCase(domain.ImageScanPolicyWarn, imageScanner)
+
+	result, err := useCase.Execute(commands.ApplyNamespaceManifest{NamespaceID: "namespace-id", UserID: "admin", Manifest: newTestManifest("frontend")})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(result.Created) != 1 {
+		t.Errorf("Expected the application to be deployed despite its vulnerabilities, got %+v", result)
+	}
+}
+
+func TestExecute_UpdateImageScanPolicy(t *testing.T) {
+	namespaceRepository := newTestNamespaceRepository()
+	var storedPolicy domain.ImageScanPolicy
+	namespaceRepository.SetImageScanPolicyFunc = func(namespaceID string, policy domain.ImageScanPolicy, maxSeverity domain.VulnerabilitySeverity) error {
+		storedPolicy = policy
+		return nil
+	}
+	auditEventRepository := &MockAuditEventRepository{}
+	useCase := UpdateImageScanPolicyUseCase{
+		NamespaceRepository:     namespaceRepository,
+		RecordAuditEventUseCase: newTestRecordAuditEventUseCase(auditEventRepository),
+	}
+
+	_, err := useCase.Execute(commands.UpdateImageScanPolicy{NamespaceID: "namespace-id", Policy: domain.ImageScanPolicyBlock, MaxSeverity: domain.SeverityHigh, UpdatedBy: "member"})
+	if _, ok := err.(*errors.NamespacePermissionDeniedError); !ok {
+		t.Errorf("Expected members not to change the policy, got %v", err)
+	}
+	_, err = useCase.Execute(commands.UpdateImageScanPolicy{NamespaceID: "namespace-id", Policy: domain.ImageScanPolicyBlock, MaxSeverity: "SEVERE", UpdatedBy: "admin"})
+	if _, ok := err.(*errors.InvalidImageScanPolicyError); !ok {
+		t.Errorf("Expected an unknown severity to be rejected, got %v", err)
+	}
+
+	namespace, err := useCase.Execute(commands.UpdateImageScanPolicy{NamespaceID: "namespace-id", Policy: domain.ImageScanPolicyBlock, MaxSeverity: domain.SeverityMedium, UpdatedBy: "admin"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if storedPolicy != domain.ImageScanPolicyBlock || namespace.MaxImageVulnerabilitySeverity != domain.SeverityMedium {
+		t.Errorf("Expected the policy to be stored, got %s and %s", storedPolicy, namespace.MaxImageVulnerabilitySeverity)
+	}
+	if len(auditEventRepository.AuditEvents) != 3 || auditEventRepository.AuditEvents[2].Action != domain.AuditImageScanPolicyUpdate {
+		t.Errorf("Expected every attempt to be audited, got %+v", auditEventRepository.AuditEvents)
+	}
+}
+
+func TestExecute_ApplyNamespaceManifest_PinsTheScannedDigestOfBlockingNamespaces(t *testing.T) {
+	useCase := newTestImageScanUseCase(domain.ImageScanPolicyBlock, &MockImageScanner{})
+
+	if _, err := useCase.Execute(commands.ApplyNamespaceManifest{NamespaceID: "namespace-id", UserID: "admin", Manifest: newTestManifest("frontend")}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	applications, _ := useCase.ApplicationRepository.FindByNamespaceIDAndUserID("namespace-id")
+	if len(applications) != 1 || !applications[0].PinImageDigest || applications[0].ImageDigest != "sha256:nginx" {
+		t.Fatalf("Expected the application to run the scanned digest, got %+v", applications)
+	}
+
+	result, err := useCase.Execute(commands.ApplyNamespaceManifest{NamespaceID: "namespace-id", UserID: "admin", Manifest: newTestManifest("frontend")})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(result.Updated) != 0 {
+		t.Errorf("Expected the pinned application to be unchanged, got %v", result.Updated)
+	}
+}
+
+func TestExecute_ScanApplicationImage_ScansOutdatedDigestsAgain(t *testing.T) {
+	imageScanner := &MockImageScanner{}
+	imageScanRepository := &MockImageScanRepository{ImageScans: map[string]domain.ImageScan{}}
+	scanApplicationImageUseCase := use_cases.ScanApplicationImageUseCase{
+		ImageScanner:        imageScanner,
+		ImageScanRepository: imageScanRepository,
+		MaxScanAge:          time.Hour,
+	}
+	namespace := newTestNamespace("namespace-id")
+	namespace.ImageScanPolicy = domain.ImageScanPolicyBlock
+	namespace.MaxImageVulnerabilitySeverity = domain.SeverityHigh
+
+	for i := 0; i < 2; i++ {
+		if _, err := scanApplicationImageUseCase.Execute(namespace, domain.DockerHubRegistry, "nginx:latest", "sha256:nginx", nil); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	if imageScanner.Scans != 1 {
+		t.Errorf("Expected a recent scan to be reused, got %d scans", imageScanner.Scans)
+	}
+
+	outdatedImageScan := imageScanRepository.ImageScans["sha256:nginx"]
+	outdatedImageScan.ScannedAt = time.Now().Add(-2 * time.Hour)
+	imageScanRepository.ImageScans["sha256:nginx"] = outdatedImageScan
+	imageScanner.Vulnerabilities = domain.ImageVulnerabilities{{ID: "CVE-2024-0002", Severity: domain.SeverityCritical}}
+
+	_, err := scanApplicationImageUseCase.Execute(namespace, domain.DockerHubRegistry, "nginx:latest", "sha256:nginx", nil)
+	var vulnerableImageError *errors.VulnerableImageError
+	if !stdErrors.As(err, &vulnerableImageError) {
+		t.Errorf("Expected the vulnerability found by the new scan to block the image, got %v", err)
+	}
+	if imageScanner.Scans != 2 || imageScanRepository.ImageScans["sha256:nginx"].ID != outdatedImageScan.ID {
+		t.Errorf("Expected the outdated scan to be replaced, got %d scans and %+v", imageScanner.Scans, imageScanRepository.ImageScans)
+	}
+}
+
+func TestExecute_ScanApplicationImage_DryRunOnlyReadsStoredScans(t *testing.T) {
+	imageScanner := &MockImageScanner{Vulnerabilities: domain.ImageVulnerabilities{{ID: "CVE-2024-0002", Severity: domain.SeverityCritical}}}
+	imageScanRepository := &MockImageScanRepository{ImageScans: map[string]domain.ImageScan{}}
+	scanApplicationImageUseCase := use_cases.ScanApplicationImageUseCase{
+		ImageScanner:        imageScanner,
+		ImageScanRepository: imageScanRepository,
+	}
+	namespace := newTestNamespace("namespace-id")
+	namespace.ImageScanPolicy = domain.ImageScanPolicyBlock
+	namespace.MaxImageVulnerabilitySeverity = domain.SeverityHigh
+
+	if _, err := scanApplicationImageUseCase.DryRun(namespace, "nginx:latest", "sha256:nginx"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if imageScanner.Scans != 0 || len(imageScanRepository.ImageScans) != 0 {
+		t.Fatalf("Expected a dry run neither to scan the image nor to store a scan, got %d scans and %+v", imageScanner.Scans, imageScanRepository.ImageScans)
+	}
+
+	_, _ = scanApplicationImageUseCase.Execute(namespace, domain.DockerHubRegistry, "nginx:latest", "sha256:nginx", nil)
+	_, err := scanApplicationImageUseCase.DryRun(namespace, "nginx:latest", "sha256:nginx")
+	var vulnerableImageError *errors.VulnerableImageError
+	if !stdErrors.As(err, &vulnerableImageError) {
+		t.Errorf("Expected the stored scan to block the image of the dry run, got %v", err)
+	}
+	if imageScanner.Scans != 1 {
+		t.Errorf("Expected the dry run to reuse the stored scan, got %d scans", imageScanner.Scans)
+	}
+}
@@ -0,0 +1,287 @@
+package namespaces
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"testing"
+	"time"
+
+	"cloud-app-hive/controllers/errors"
+	"cloud-app-hive/domain"
+	"cloud-app-hive/domain/commands"
+)
+
+// MockNamespaceInvitationRepository keeps the invitations in memory
+type MockNamespaceInvitationRepository struct {
+	Invitations map[string]*domain.NamespaceInvitation
+}
+
+func (m *MockNamespaceInvitationRepository) Create(createNamespaceInvitation commands.CreateNamespaceInvitation) (*domain.NamespaceInvitation, error) {
+	invitation := domain.NamespaceInvitation{
+		ID:          fmt.Sprintf("invitation-%d", len(m.Invitations)+1),
+		NamespaceID: createNamespaceInvitation.NamespaceID,
+		Email:       createNamespaceInvitation.Email,
+		Role:        createNamespaceInvitation.Role,
+		TokenHash:   createNamespaceInvitation.TokenHash,
+		InvitedBy:   createNamespaceInvitation.InvitedBy,
+		Status:      domain.NamespaceInvitationPending,
+		ExpiresAt:   createNamespaceInvitation.ExpiresAt,
+	}
+	m.Invitations[invitation.ID] = &invitation
+	stored := invitation
+	return &stored, nil
+}
+
+func (m *MockNamespaceInvitationRepository) FindByID(id string) (*domain.NamespaceInvitation, error) {
+	if invitation, ok := m.Invitations[id]; ok {
+		found := *invitation
+		return &found, nil
+	}
+	return nil, nil
+}
+
+func (m *MockNamespaceInvitationRepository) FindByTokenHash(tokenHash string) (*domain.NamespaceInvitation, error) {
+	for _, invitation := range m.Invitations {
+		if invitation.TokenHash == tokenHash {
+			found := *invitation
+			return &found, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *MockNamespaceInvitationRepository) FindPendingByNamespaceID(namespaceID string, now time.Time) ([]domain.NamespaceInvitation, error) {
+	invitations := []domain.NamespaceInvitation{}
+	for _, invitation := range m.Invitations {
+		if invitation.NamespaceID == namespaceID && invitation.IsPending(now) {
+			invitations = append(invitations, *invitation)
+		}
+	}
+	return invitations, nil
+}
+
+func (m *MockNamespaceInvitationRepository) UpdateStatus(id string, status domain.NamespaceInvitationStatus, respondedBy *string, updatedAt time.Time) (bool, error) {
+	invitation, ok := m.Invitations[id]
+	if !ok || invitation.Status != domain.NamespaceInvitationPending {
+		return false, nil
+	}
+	invitation.Status = status
+	invitation.RespondedBy = respondedBy
+	return true, nil
+}
+
+// MockNamespaceMembershipRepository records the created memberships
+type MockNamespaceMembershipRepository struct {
+	CreatedMemberships []commands.CreateNamespaceMembership
+}
+
+func (m *MockNamespaceMembershipRepository) ExistsByNamespaceIDAndUserID(namespaceID string, userID string) (bool, error) {
+	return false, nil
+}
+
+func (m *MockNamespaceMembershipRepository) FindByUserID(userID string) ([]domain.NamespaceMembership, error) {
+	return nil, nil
+}
+
+func (m *MockNamespaceMembershipRepository) FindByNamespaceID(namespaceID string) ([]domain.NamespaceMembership, error) {
+	return nil, nil
+}
+
+func (m *MockNamespaceMembershipRepository) FindByUserIDAndNamespaceID(userID string, namespaceID string) (*domain.NamespaceMembership, error) {
+	return nil, nil
+}
+
+func (m *MockNamespaceMembershipRepository) Create(namespaceMembership commands.CreateNamespaceMembership) (*domain.NamespaceMembership, error) {
+	m.CreatedMemberships = append(m.CreatedMemberships, namespaceMembership)
+	return &domain.NamespaceMembership{
+		UserID:      namespaceMembership.UserID,
+		NamespaceID: namespaceMembership.NamespaceID,
+		Role:        namespaceMembership.Role,
+	}, nil
+}
+
+func (m *MockNamespaceMembershipRepository) Delete(namespaceMembershipID string) (*domain.NamespaceMembership, error) {
+	return nil, nil
+}
+
+func (m *MockNamespaceMembershipRepository) RemoveByNamespaceIDAndUserID(userID string, namespaceID string) (*domain.NamespaceMembership, error) {
+	return nil, nil
+}
+
+func (m *MockNamespaceMembershipRepository) IsAdminInNamespace(namespaceID string, userID string) (bool, error) {
+	return false, nil
+}
+
+func (m *MockNamespaceMembershipRepository) Update(namespaceMembership commands.UpdateNamespaceMembership) (*domain.NamespaceMembership, error) {
+	return nil, nil
+}
+
+// MockEmailSender records the sent emails instead of sending them
+type MockEmailSender struct {
+	SentTo    []string
+	TextBody  string
+	SendError error
+}
+
+func (m *MockEmailSender) Send(to, subject, body, htmlBody string, carbonCopy []string) error {
+	if m.SendError != nil {
+		return m.SendError
+	}
+	m.SentTo = append(m.SentTo, to)
+	m.TextBody = body
+	return nil
+}
+
+func newTestNamespaceInvitationUseCases(emailSender *MockEmailSender) (CreateNamespaceInvitationUseCase, AcceptNamespaceInvitationUseCase, *MockNamespaceInvitationRepository, *MockNamespaceMembershipRepository) {
+	namespaceRepository := &MockNamespaceRepository{
+		FindByIDFunc: func(id string) (*domain.Namespace, error) {
+			return &domain.Namespace{
+				ID:     id,
+				Name:   "team",
+				UserID: "owner",
+				Memberships: []domain.NamespaceMembership{
+					{UserID: "owner", Role: domain.RoleOwner},
+					{UserID: "admin", Role: domain.RoleAdmin},
+					{UserID: "member", Role: domain.RoleMember},
+				},
+			}, nil
+		},
+	}
+	invitationRepository := &MockNamespaceInvitationRepository{Invitations: map[string]*domain.NamespaceInvitation{}}
+	membershipRepository := &MockNamespaceMembershipRepository{}
+	recordAuditEventUseCase := newTestRecordAuditEventUseCase(&MockAuditEventRepository{})
+
+	createUseCase := CreateNamespaceInvitationUseCase{
+		NamespaceRepository:           namespaceRepository,
+		NamespaceInvitationRepository: invitationRepository,
+		EmailSender:                   emailSender,
+		AcceptURL:                     "https://console.example.com/invitations?source=email",
+		RecordAuditEventUseCase:       recordAuditEventUseCase,
+	}
+	acceptUseCase := AcceptNamespaceInvitationUseCase{
+		NamespaceRepository:           namespaceRepository,
+		NamespaceInvitationRepository: invitationRepository,
+		NamespaceMembershipRepository: membershipRepository,
+		RecordAuditEventUseCase:       recordAuditEventUseCase,
+	}
+	return createUseCase, acceptUseCase, invitationRepository, membershipRepository
+}
+
+// sentInvitationToken returns the token of the accept link of the last sent email
+func sentInvitationToken(t *testing.T, emailSender *MockEmailSender) string {
+	link := regexp.MustCompile(`https://\S+`).FindString(emailSender.TextBody)
+	parsedLink, err := url.Parse(link)
+	if err != nil {
+		t.Fatalf("Unexpected accept link %s: %v", link, err)
+	}
+	if parsedLink.Query().Get("source") != "email" {
+		t.Errorf("Expected the accept link to keep its query parameters, got %s", link)
+	}
+	return parsedLink.Query().Get("token")
+}
+
+func TestExecute_NamespaceInvitation_AcceptCreatesMembershipOnce(t *testing.T) {
+	emailSender := &MockEmailSender{}
+	createUseCase, acceptUseCase, invitationRepository, membershipRepository := newTestNamespaceInvitationUseCases(emailSender)
+
+	invitation, err := createUseCase.Execute(commands.CreateNamespaceInvitation{NamespaceID: "namespace-id", Email: " Invitee@Example.com", Role: domain.RoleDeveloper, InvitedBy: "admin"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(emailSender.SentTo) != 1 || emailSender.SentTo[0] != "invitee@example.com" {
+		t.Fatalf("Expected the invitation to be emailed to invitee@example.com, got %v", emailSender.SentTo)
+	}
+	if len(membershipRepository.CreatedMemberships) != 0 {
+		t.Fatalf("Expected no membership before acceptance, got %v", membershipRepository.CreatedMemberships)
+	}
+	token := sentInvitationToken(t, emailSender)
+	if invitationRepository.Invitations[invitation.ID].TokenHash != domain.HashNamespaceInvitationToken(token) {
+		t.Errorf("Expected only the hash of the emailed token to be stored")
+	}
+
+	membership, err := acceptUseCase.Execute(commands.RespondToNamespaceInvitation{Token: token, UserID: "invitee", UserEmail: "INVITEE@example.com"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if membership.UserID != "invitee" || membership.Role != domain.RoleDeveloper {
+		t.Errorf("Expected invitee to become a developer, got %+v", membership)
+	}
+	if len(membershipRepository.CreatedMemberships) != 1 || membershipRepository.CreatedMemberships[0].AddedBy != "admin" {
+		t.Errorf("Expected one membership added by the inviter, got %+v", membershipRepository.CreatedMemberships)
+	}
+
+	_, err = acceptUseCase.Execute(commands.RespondToNamespaceInvitation{Token: token, UserID: "invitee", UserEmail: "invitee@example.com"})
+	if _, ok := err.(*errors.NamespaceInvitationNotPendingError); !ok {
+		t.Errorf("Expected an accepted invitation to be rejected, got %v", err)
+	}
+}
+
+func TestExecute_NamespaceInvitation_RequiresOwnerForAdminRoles(t *testing.T) {
+	emailSender := &MockEmailSender{}
+	createUseCase, _, _, _ := newTestNamespaceInvitationUseCases(emailSender)
+
+	_, err := createUseCase.Execute(commands.CreateNamespaceInvitation{NamespaceID: "namespace-id", Email: "invitee@example.com", Role: domain.RoleViewer, InvitedBy: "member"})
+	if _, ok := err.(*errors.NamespacePermissionDeniedError); !ok {
+		t.Errorf("Expected a member not to be allowed to invite, got %v", err)
+	}
+	_, err = createUseCase.Execute(commands.CreateNamespaceInvitation{NamespaceID: "namespace-id", Email: "invitee@example.com", Role: domain.RoleAdmin, InvitedBy: "admin"})
+	if _, ok := err.(*errors.NamespacePermissionDeniedError); !ok {
+		t.Errorf("Expected an admin not to be allowed to invite an admin, got %v", err)
+	}
+	if _, err := createUseCase.Execute(commands.CreateNamespaceInvitation{NamespaceID: "namespace-id", Email: "invitee@example.com", Role: domain.RoleAdmin, InvitedBy: "owner"}); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if len(emailSender.SentTo) != 1 {
+		t.Errorf("Expected only the allowed invitation to be sent, got %v", emailSender.SentTo)
+	}
+}
+
+func TestExecute_NamespaceInvitation_RejectsExpiredAndOtherEmails(t *testing.T) {
+	emailSender := &MockEmailSender{}
+	createUseCase, acceptUseCase, invitationRepository, membershipRepository := newTestNamespaceInvitationUseCases(emailSender)
+
+	invitation, err := createUseCase.Execute(commands.CreateNamespaceInvitation{NamespaceID: "namespace-id", Email: "invitee@example.com", Role: domain.RoleMember, InvitedBy: "admin"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	token := sentInvitationToken(t, emailSender)
+
+	_, err = acceptUseCase.Execute(commands.RespondToNamespaceInvitation{Token: token, UserID: "intruder", UserEmail: "intruder@example.com"})
+	if _, ok := err.(*errors.NamespaceInvitationEmailMismatchError); !ok {
+		t.Errorf("Expected an invitation sent to another email to be rejected, got %v", err)
+	}
+	_, err = acceptUseCase.Execute(commands.RespondToNamespaceInvitation{Token: token, UserID: "invitee"})
+	if _, ok := err.(*errors.NamespaceInvitationEmailMismatchError); !ok {
+		t.Errorf("Expected an invitation to be rejected without verified email, got %v", err)
+	}
+
+	invitationRepository.Invitations[invitation.ID].ExpiresAt = time.Now().Add(-time.Minute)
+	_, err = acceptUseCase.Execute(commands.RespondToNamespaceInvitation{Token: token, UserID: "invitee", UserEmail: "invitee@example.com"})
+	if notPendingError, ok := err.(*errors.NamespaceInvitationNotPendingError); !ok || notPendingError.Status != string(domain.NamespaceInvitationExpired) {
+		t.Errorf("Expected an expired invitation to be rejected, got %v", err)
+	}
+	if len(membershipRepository.CreatedMemberships) != 0 {
+		t.Errorf("Expected no membership, got %v", membershipRepository.CreatedMemberships)
+	}
+}
+
+func TestExecute_NamespaceInvitation_RevokesUnsentInvitation(t *testing.T) {
+	emailSender := &MockEmailSender{SendError: fmt.Errorf("mail server unavailable")}
+	createUseCase, _, invitationRepository, _ := newTestNamespaceInvitationUseCases(emailSender)
+
+	if _, err := createUseCase.Execute(commands.CreateNamespaceInvitation{NamespaceID: "namespace-id", Email: "invitee@example.com", Role: domain.RoleMember, InvitedBy: "admin"}); err == nil {
+		t.Fatal("Expected an error when the email cannot be sent, but got nil")
+	}
+	for _, invitation := range invitationRepository.Invitations {
+		if invitation.Status != domain.NamespaceInvitationRevoked {
+			t.Errorf("Expected the unsent invitation to be revoked, got %s", invitation.Status)
+		}
+	}
+
+	// The invitation can be sent again once the email service is back
+	emailSender.SendError = nil
+	if _, err := createUseCase.Execute(commands.CreateNamespaceInvitation{NamespaceID: "namespace-id", Email: "invitee@example.com", Role: domain.RoleMember, InvitedBy: "admin"}); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,112 @@
+package namespaces
+
+import (
+	"testing"
+	"time"
+
+	"cloud-app-hive/controllers/errors"
+	"cloud-app-hive/domain"
+	"cloud-app-hive/domain/commands"
+)
+
+// newTestOwnershipNamespaceRepository keeps a single namespace in memory and applies the ownership changes to it
+func newTestOwnershipNamespaceRepository() (*MockNamespaceRepository, *domain.Namespace) {
+	namespace := &domain.Namespace{
+		ID:     "namespace-id",
+		Name:   "team",
+		UserID: "owner",
+		Memberships: []domain.NamespaceMembership{
+			{UserID: "owner", Role: domain.RoleOwner},
+			{UserID: "admin", Role: domain.RoleAdmin},
+			{UserID: "developer", Role: domain.RoleDeveloper},
+		},
+	}
+	return &MockNamespaceRepository{
+		FindByIDFunc: func(id string) (*domain.Namespace, error) {
+			found := *namespace
+			return &found, nil
+		},
+		SetPendingOwnerFunc: func(namespaceID string, pendingOwnerID *string, expiresAt *time.Time) error {
+			namespace.PendingOwnerID = pendingOwnerID
+			namespace.OwnershipTransferExpiresAt = expiresAt
+			return nil
+		},
+		TransferOwnershipFunc: func(namespaceID string, newOwnerID string) (*domain.Namespace, error) {
+			for index, membership := range namespace.Memberships {
+				switch membership.UserID {
+				case newOwnerID:
+					namespace.Memberships[index].Role = domain.RoleOwner
+				case namespace.UserID:
+					namespace.Memberships[index].Role = domain.RoleAdmin
+				}
+			}
+			namespace.UserID = newOwnerID
+			namespace.PendingOwnerID = nil
+			namespace.OwnershipTransferExpiresAt = nil
+			found := *namespace
+			return &found, nil
+		},
+	}, namespace
+}
+
+func TestExecute_NamespaceOwnershipTransfer_RequiresAcceptance(t *testing.T) {
+	namespaceRepository, namespace := newTestOwnershipNamespaceRepository()
+	recordAuditEventUseCase := newTestRecordAuditEventUseCase(&MockAuditEventRepository{})
+	transferUseCase := TransferNamespaceOwnershipUseCase{NamespaceRepository: namespaceRepository, RecordAuditEventUseCase: recordAuditEventUseCase}
+	acceptUseCase := AcceptNamespaceOwnershipTransferUseCase{NamespaceRepository: namespaceRepository, RecordAuditEventUseCase: recordAuditEventUseCase}
+
+	if _, err := transferUseCase.Execute(commands.TransferNamespaceOwnership{NamespaceID: namespace.ID, NewOwnerID: "developer", RequestedBy: "admin"}); err == nil {
+		t.Error("Expected an admin not to transfer the namespace, but got nil")
+	}
+	if _, err := transferUseCase.Execute(commands.TransferNamespaceOwnership{NamespaceID: namespace.ID, NewOwnerID: "stranger", RequestedBy: "owner"}); err == nil {
+		t.Error("Expected a transfer to a user outside the namespace to fail, but got nil")
+	}
+	if _, err := transferUseCase.Execute(commands.TransferNamespaceOwnership{NamespaceID: namespace.ID, NewOwnerID: "developer", RequestedBy: "owner"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if namespace.UserID != "owner" {
+		t.Fatalf("Expected the owner to stay until the transfer is accepted, got %s", namespace.UserID)
+	}
+
+	_, err := acceptUseCase.Execute(commands.RespondToNamespaceOwnershipTransfer{NamespaceID: namespace.ID, UserID: "admin"})
+	if _, ok := err.(*errors.NamespaceOwnershipTransferNotFoundError); !ok {
+		t.Errorf("Expected another member not to accept the transfer, got %v", err)
+	}
+	transferredNamespace, err := acceptUseCase.Execute(commands.RespondToNamespaceOwnershipTransfer{NamespaceID: namespace.ID, UserID: "developer"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if role, _ := transferredNamespace.RoleOf("developer"); role != domain.RoleOwner || transferredNamespace.UserID != "developer" {
+		t.Errorf("Expected developer to own the namespace, got %s with role %s", transferredNamespace.UserID, role)
+	}
+	if role, _ := transferredNamespace.RoleOf("owner"); role != domain.RoleAdmin {
+		t.Errorf("Expected the previous owner to stay as an admin, got %s", role)
+	}
+}
+
+func TestExecute_NamespaceOwnershipTransfer_ExpiresAndCanBeDeclined(t *testing.T) {
+	namespaceRepository, namespace := newTestOwnershipNamespaceRepository()
+	recordAuditEventUseCase := newTestRecordAuditEventUseCase(&MockAuditEventRepository{})
+	transferUseCase := TransferNamespaceOwnershipUseCase{NamespaceRepository: namespaceRepository, RecordAuditEventUseCase: recordAuditEventUseCase}
+	acceptUseCase := AcceptNamespaceOwnershipTransferUseCase{NamespaceRepository: namespaceRepository, RecordAuditEventUseCase: recordAuditEventUseCase}
+	cancelUseCase := CancelNamespaceOwnershipTransferUseCase{NamespaceRepository: namespaceRepository, RecordAuditEventUseCase: recordAuditEventUseCase}
+
+	if _, err := transferUseCase.Execute(commands.TransferNamespaceOwnership{NamespaceID: namespace.ID, NewOwnerID: "admin", RequestedBy: "owner"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expiredAt := time.Now().Add(-time.Minute)
+	namespace.OwnershipTransferExpiresAt = &expiredAt
+	if _, err := acceptUseCase.Execute(commands.RespondToNamespaceOwnershipTransfer{NamespaceID: namespace.ID, UserID: "admin"}); err == nil {
+		t.Error("Expected an expired transfer not to be accepted, but got nil")
+	}
+
+	if _, err := cancelUseCase.Execute(commands.RespondToNamespaceOwnershipTransfer{NamespaceID: namespace.ID, UserID: "developer"}); err == nil {
+		t.Error("Expected a member not to cancel the transfer of another member, but got nil")
+	}
+	if _, err := cancelUseCase.Execute(commands.RespondToNamespaceOwnershipTransfer{NamespaceID: namespace.ID, UserID: "admin"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if namespace.PendingOwnerID != nil || namespace.UserID != "owner" {
+		t.Errorf("Expected the declined transfer to be cleared, got pending owner %v and owner %s", namespace.PendingOwnerID, namespace.UserID)
+	}
+}
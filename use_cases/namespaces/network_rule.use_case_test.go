@@ -0,0 +1,146 @@
+package namespaces
+
+import (
+	"fmt"
+	"testing"
+
+	"cloud-app-hive/domain"
+	"cloud-app-hive/domain/commands"
+	"cloud-app-hive/use_cases"
+)
+
+// MockNetworkRuleRepository is a mock implementation of the NetworkRuleRepository interface keeping the rules in memory
+type MockNetworkRuleRepository struct {
+	NetworkRules []domain.NetworkRule
+}
+
+func (m *MockNetworkRuleRepository) Create(createNetworkRule commands.CreateNetworkRule) (*domain.NetworkRule, error) {
+	networkRule := domain.NetworkRule{
+		ID:                fmt.Sprintf("rule-%d", len(m.NetworkRules)+1),
+		NamespaceID:       createNetworkRule.NamespaceID,
+		ApplicationID:     createNetworkRule.ApplicationID,
+		FromNamespaceID:   createNetworkRule.FromNamespaceID,
+		FromApplicationID: createNetworkRule.FromApplicationID,
+		CreatedBy:         createNetworkRule.CreatedBy,
+	}
+	m.NetworkRules = append(m.NetworkRules, networkRule)
+	return &networkRule, nil
+}
+
+func (m *MockNetworkRuleRepository) FindByID(id string) (*domain.NetworkRule, error) {
+	for _, networkRule := range m.NetworkRules {
+		if networkRule.ID == id {
+			return &networkRule, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *MockNetworkRuleRepository) FindByNamespaceID(namespaceID string) ([]domain.NetworkRule, error) {
+	var networkRules []domain.NetworkRule
+	for _, networkRule := range m.NetworkRules {
+		if networkRule.NamespaceID == namespaceID {
+			networkRules = append(networkRules, networkRule)
+		}
+	}
+	return networkRules, nil
+}
+
+func (m *MockNetworkRuleRepository) Delete(id string) error {
+	for index, networkRule := range m.NetworkRules {
+		if networkRule.ID == id {
+			m.NetworkRules = append(m.NetworkRules[:index], m.NetworkRules[index+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+// newTestNetworkRuleNamespaceRepository returns a backend namespace administrated by "admin" and a frontend namespace
+// where "admin" is a viewer, "stranger" is only a member of the backend namespace
+func newTestNetworkRuleNamespaceRepository() *MockNamespaceRepository {
+	namespaces := map[string]domain.Namespace{
+		"backend-id": {
+			ID:           "backend-id",
+			Name:         "backend",
+			UserID:       "admin",
+			Memberships:  []domain.NamespaceMembership{{UserID: "admin", Role: domain.RoleOwner}, {UserID: "stranger", Role: domain.RoleAdmin}},
+			Applications: []domain.Application{{ID: "api-id", Name: "api"}},
+		},
+		"frontend-id": {
+			ID:           "frontend-id",
+			Name:         "frontend",
+			UserID:       "frontend-owner",
+			Memberships:  []domain.NamespaceMembership{{UserID: "frontend-owner", Role: domain.RoleOwner}, {UserID: "admin", Role: domain.RoleViewer}},
+			Applications: []domain.Application{{ID: "web-id", Name: "web"}},
+		},
+	}
+	return &MockNamespaceRepository{
+		FindByIDFunc: func(id string) (*domain.Namespace, error) {
+			namespace, ok := namespaces[id]
+			if !ok {
+				return nil, fmt.Errorf("namespace not found with ID %s", id)
+			}
+			return &namespace, nil
+		},
+	}
+}
+
+func TestExecute_CreateNetworkRule_AppliesRulesWithNames(t *testing.T) {
+	namespaceRepository := newTestNetworkRuleNamespaceRepository()
+	networkRuleRepository := &MockNetworkRuleRepository{}
+	containerManagerRepository := &MockContainerManagerRepository{}
+	useCase := CreateNetworkRuleUseCase{
+		NamespaceRepository:   namespaceRepository,
+		NetworkRuleRepository: networkRuleRepository,
+		ApplyNetworkRulesUseCase: use_cases.ApplyNetworkRulesUseCase{
+			NamespaceRepository:        namespaceRepository,
+			NetworkRuleRepository:      networkRuleRepository,
+			ContainerManagerRepository: containerManagerRepository,
+		},
+		RecordAuditEventUseCase: newTestRecordAuditEventUseCase(&MockAuditEventRepository{}),
+	}
+
+	applicationID, fromApplicationID := "api-id", "web-id"
+	if _, err := useCase.Execute(commands.CreateNetworkRule{NamespaceID: "backend-id", ApplicationID: &applicationID, FromNamespaceID: "frontend-id", FromApplicationID: &fromApplicationID, CreatedBy: "admin"}); err != nil {
+		t.Fatalf("Expected the rule to be created, but got %v", err)
+	}
+
+	if len(containerManagerRepository.AppliedNetworkRules) != 1 {
+		t.Fatalf("Expected the rules to be applied once, but got %d", len(containerManagerRepository.AppliedNetworkRules))
+	}
+	applied := containerManagerRepository.AppliedNetworkRules[0]
+	if applied.Namespace != "backend" || len(applied.Rules) != 1 {
+		t.Fatalf("Expected 1 rule applied on namespace backend, but got %+v", applied)
+	}
+	rule := applied.Rules[0]
+	if rule.FromNamespace != "frontend" || *rule.ApplicationName != "api" || *rule.FromApplicationName != "web" {
+		t.Errorf("Expected the rule to allow frontend/web to reach api, but got %+v", rule)
+	}
+}
+
+func TestExecute_CreateNetworkRule_RejectsUnreadableOrSameNamespace(t *testing.T) {
+	namespaceRepository := newTestNetworkRuleNamespaceRepository()
+	networkRuleRepository := &MockNetworkRuleRepository{}
+	containerManagerRepository := &MockContainerManagerRepository{}
+	useCase := CreateNetworkRuleUseCase{
+		NamespaceRepository:   namespaceRepository,
+		NetworkRuleRepository: networkRuleRepository,
+		ApplyNetworkRulesUseCase: use_cases.ApplyNetworkRulesUseCase{
+			NamespaceRepository:        namespaceRepository,
+			NetworkRuleRepository:      networkRuleRepository,
+			ContainerManagerRepository: containerManagerRepository,
+		},
+		RecordAuditEventUseCase: newTestRecordAuditEventUseCase(&MockAuditEventRepository{}),
+	}
+
+	if _, err := useCase.Execute(commands.CreateNetworkRule{NamespaceID: "backend-id", FromNamespaceID: "frontend-id", CreatedBy: "stranger"}); err == nil {
+		t.Error("Expected a user who cannot read the source namespace to be forbidden, but got nil")
+	}
+	if _, err := useCase.Execute(commands.CreateNetworkRule{NamespaceID: "backend-id", FromNamespaceID: "backend-id", CreatedBy: "admin"}); err == nil {
+		t.Error("Expected a rule within the namespace to be rejected, but got nil")
+	}
+	if len(networkRuleRepository.NetworkRules) != 0 || len(containerManagerRepository.AppliedNetworkRules) != 0 {
+		t.Errorf("Expected no rule to be stored nor applied, but got %d stored and %d applied", len(networkRuleRepository.NetworkRules), len(containerManagerRepository.AppliedNetworkRules))
+	}
+}
@@ -0,0 +1,140 @@
+package namespaces
+
+import (
+	"fmt"
+	"testing"
+
+	customErrors "cloud-app-hive/controllers/errors"
+	"cloud-app-hive/domain"
+	"cloud-app-hive/domain/commands"
+)
+
+// MockRegistryCredentialRepository is a mock implementation of the RegistryCredentialRepository interface keeping the credentials in memory
+type MockRegistryCredentialRepository struct {
+	RegistryCredentials []domain.RegistryCredential
+}
+
+func (m *MockRegistryCredentialRepository) Create(registryCredential domain.RegistryCredential) (*domain.RegistryCredential, error) {
+	registryCredential.ID = fmt.Sprintf("credential-%d", len(m.RegistryCredentials)+1)
+	registryCredential.Password = "encrypted:" + registryCredential.Password
+	m.RegistryCredentials = append(m.RegistryCredentials, registryCredential)
+	return &registryCredential, nil
+}
+
+func (m *MockRegistryCredentialRepository) FindByID(id string) (*domain.RegistryCredential, error) {
+	for _, registryCredential := range m.RegistryCredentials {
+		if registryCredential.ID == id {
+			return &registryCredential, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *MockRegistryCredentialRepository) FindByNamespaceID(namespaceID string) ([]domain.RegistryCredential, error) {
+	var registryCredentials []domain.RegistryCredential
+	for _, registryCredential := range m.RegistryCredentials {
+		if registryCredential.NamespaceID == namespaceID {
+			registryCredentials = append(registryCredentials, registryCredential)
+		}
+	}
+	return registryCredentials, nil
+}
+
+func (m *MockRegistryCredentialRepository) FindAll() ([]domain.RegistryCredential, error) {
+	return m.RegistryCredentials, nil
+}
+
+func (m *MockRegistryCredentialRepository) Update(registryCredential domain.RegistryCredential) (*domain.RegistryCredential, error) {
+	for index := range m.RegistryCredentials {
+		if m.RegistryCredentials[index].ID == registryCredential.ID {
+			m.RegistryCredentials[index] = registryCredential
+		}
+	}
+	return &registryCredential, nil
+}
+
+func (m *MockRegistryCredentialRepository) Delete(id string) error {
+	for index, registryCredential := range m.RegistryCredentials {
+		if registryCredential.ID == id {
+			m.RegistryCredentials = append(m.RegistryCredentials[:index], m.RegistryCredentials[index+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+// newTestRegistryCredentialNamespaceRepository returns a namespace administrated by "admin" where "developer" is a developer,
+// its application "api" pulls its image with the credential "credential-1"
+func newTestRegistryCredentialNamespaceRepository() *MockNamespaceRepository {
+	registryCredentialID := "credential-1"
+	namespace := domain.Namespace{
+		ID:           "backend-id",
+		Name:         "backend",
+		UserID:       "admin",
+		Memberships:  []domain.NamespaceMembership{{UserID: "admin", Role: domain.RoleOwner}, {UserID: "developer", Role: domain.RoleDeveloper}},
+		Applications: []domain.Application{{ID: "api-id", Name: "api", Registry: domain.CustomRegistry, RegistryCredentialID: &registryCredentialID}},
+	}
+	return &MockNamespaceRepository{
+		FindByIDFunc: func(id string) (*domain.Namespace, error) {
+			if id != namespace.ID {
+				return nil, fmt.Errorf("namespace not found with ID %s", id)
+			}
+			return &namespace, nil
+		},
+	}
+}
+
+func TestExecute_CreateRegistryCredential_StoresNormalizedServerAndMaskedPassword(t *testing.T) {
+	registryCredentialRepository := &MockRegistryCredentialRepository{}
+	useCase := CreateRegistryCredentialUseCase{
+		NamespaceRepository:          newTestRegistryCredentialNamespaceRepository(),
+		RegistryCredentialRepository: registryCredentialRepository,
+		RecordAuditEventUseCase:      newTestRecordAuditEventUseCase(&MockAuditEventRepository{}),
+	}
+
+	createRegistryCredential := commands.CreateRegistryCredential{NamespaceID: "backend-id", Name: "ghcr", Server: "https://ghcr.io/", Username: "octocat", Password: "ghp_0123456789abcdef", CreatedBy: "admin"}
+	registryCredential, err := useCase.Execute(createRegistryCredential)
+	if err != nil {
+		t.Fatalf("Expected the credential to be created, but got %v", err)
+	}
+	if registryCredential.Server != "ghcr.io" || registryCredential.PasswordPreview != "****" {
+		t.Errorf("Expected the server ghcr.io and the preview ****, but got %s and %s", registryCredential.Server, registryCredential.PasswordPreview)
+	}
+
+	if _, err := useCase.Execute(createRegistryCredential); err == nil {
+		t.Error("Expected a second credential with the same name to be rejected, but got nil")
+	}
+	createRegistryCredential.Name = "gitlab"
+	createRegistryCredential.CreatedBy = "developer"
+	if _, err := useCase.Execute(createRegistryCredential); err == nil {
+		t.Error("Expected a developer to be forbidden, but got nil")
+	}
+	if len(registryCredentialRepository.RegistryCredentials) != 1 {
+		t.Errorf("Expected 1 credential to be stored, but got %d", len(registryCredentialRepository.RegistryCredentials))
+	}
+}
+
+func TestExecute_DeleteRegistryCredential_RejectsCredentialInUse(t *testing.T) {
+	registryCredentialRepository := &MockRegistryCredentialRepository{RegistryCredentials: []domain.RegistryCredential{
+		{ID: "credential-1", NamespaceID: "backend-id", Name: "ghcr"},
+		{ID: "credential-2", NamespaceID: "backend-id", Name: "gitlab"},
+	}}
+	useCase := DeleteRegistryCredentialUseCase{
+		NamespaceRepository:          newTestRegistryCredentialNamespaceRepository(),
+		RegistryCredentialRepository: registryCredentialRepository,
+		RecordAuditEventUseCase:      newTestRecordAuditEventUseCase(&MockAuditEventRepository{}),
+	}
+
+	_, err := useCase.Execute(commands.DeleteRegistryCredential{NamespaceID: "backend-id", RegistryCredentialID: "credential-1", DeletedBy: "admin"})
+	inUseErr, ok := err.(*customErrors.RegistryCredentialInUseError)
+	if !ok || len(inUseErr.ApplicationNames) != 1 || inUseErr.ApplicationNames[0] != "api" {
+		t.Fatalf("Expected the credential used by api not to be deleted, but got %v", err)
+	}
+
+	if _, err := useCase.Execute(commands.DeleteRegistryCredential{NamespaceID: "backend-id", RegistryCredentialID: "credential-2", DeletedBy: "admin"}); err != nil {
+		t.Fatalf("Expected the unused credential to be deleted, but got %v", err)
+	}
+	if len(registryCredentialRepository.RegistryCredentials) != 1 || registryCredentialRepository.RegistryCredentials[0].ID != "credential-1" {
+		t.Errorf("Expected only credential-1 to remain, but got %+v", registryCredentialRepository.RegistryCredentials)
+	}
+}
@@ -7,52 +7,58 @@ import (
 	"cloud-app-hive/domain"
 	"cloud-app-hive/domain/commands"
 	"cloud-app-hive/domain/repositories"
+	"cloud-app-hive/use_cases"
 )
 
 type RemoveNamespaceMembershipUseCase struct {
 	NamespaceRepository           repositories.NamespaceRepository
 	NamespaceMembershipRepository repositories.NamespaceMembershipRepository
+	RecordAuditEventUseCase       use_cases.RecordAuditEventUseCase
 }
 
-func (removeNamespaceMembershipUseCase RemoveNamespaceMembershipUseCase) Execute(removeNamespaceMembership commands.RemoveNamespaceMembership) (*domain.NamespaceMembership, error) {
+func (removeNamespaceMembershipUseCase RemoveNamespaceMembershipUseCase) Execute(removeNamespaceMembership commands.RemoveNamespaceMembership) (removedNamespaceMembership *domain.NamespaceMembership, err error) {
+	auditEvent := domain.NewAuditEvent(removeNamespaceMembership.Actor, domain.AuditNamespaceMemberRemove, domain.AuditTargetMembership, removeNamespaceMembership.UserID)
+	auditEvent.NamespaceID = removeNamespaceMembership.NamespaceID
+	auditEvent.TargetName = removeNamespaceMembership.UserID
+	defer func() {
+		removeNamespaceMembershipUseCase.RecordAuditEventUseCase.Execute(auditEvent, err)
+	}()
+
 	foundNamespaceByID, err := removeNamespaceMembershipUseCase.NamespaceRepository.FindByID(removeNamespaceMembership.NamespaceID)
 	if err != nil {
 		return nil, err
 	}
+	if foundNamespaceByID == nil {
+		return nil, errors.NewNamespaceNotFoundByIDError(removeNamespaceMembership.NamespaceID)
+	}
 
-	foundNamespaceMembershipByID, err := removeNamespaceMembershipUseCase.NamespaceMembershipRepository.ExistsByNamespaceIDAndUserID(removeNamespaceMembership.NamespaceID, removeNamespaceMembership.UserID)
-	if err != nil {
+	if err := foundNamespaceByID.Authorize(removeNamespaceMembership.RemovedBy, domain.PermissionManageNamespaceMembers); err != nil {
 		return nil, err
 	}
-	if foundNamespaceMembershipByID == false {
-		return nil, fmt.Errorf("namespace membership of user %s in namespace %s does not exist", removeNamespaceMembership.UserID, removeNamespaceMembership.NamespaceID)
-	}
 
-	isUserThatRemovesNamespaceMembershipAdminInNamespace, err := removeNamespaceMembershipUseCase.NamespaceMembershipRepository.IsAdminInNamespace(removeNamespaceMembership.RemovedBy, removeNamespaceMembership.NamespaceID)
-	if err != nil {
-		return nil, err
+	removedRole, isMember := foundNamespaceByID.RoleOf(removeNamespaceMembership.UserID)
+	if !isMember {
+		return nil, fmt.Errorf("namespace membership of user %s in namespace %s does not exist", removeNamespaceMembership.UserID, removeNamespaceMembership.NamespaceID)
 	}
-	if isUserThatRemovesNamespaceMembershipAdminInNamespace == false {
-		return nil, errors.NewNotAdminInNamespaceError(removeNamespaceMembership.NamespaceID, removeNamespaceMembership.RemovedBy)
+	auditEvent.Changes = domain.NewAuditChanges(map[string]domain.Role{"role": removedRole}, nil)
+	// If the user that is being removed is an admin in the namespace, the user that removes the user must be the owner of the namespace
+	if removedRole == domain.RoleOwner || removedRole == domain.RoleAdmin {
+		if err := foundNamespaceByID.Authorize(removeNamespaceMembership.RemovedBy, domain.PermissionManageNamespaceAdmins); err != nil {
+			return nil, errors.NewUnauthorizedToRemoveAdminFromNamespaceError(removeNamespaceMembership.NamespaceID, removeNamespaceMembership.UserID, removeNamespaceMembership.RemovedBy)
+		}
 	}
-
-	isAdminInNamespace, err := removeNamespaceMembershipUseCase.NamespaceMembershipRepository.IsAdminInNamespace(removeNamespaceMembership.UserID, removeNamespaceMembership.NamespaceID)
-	if err != nil {
+	if err := foundNamespaceByID.ValidateMembershipChange(removeNamespaceMembership.UserID, nil); err != nil {
 		return nil, err
 	}
-	// If the user that is being removed is an admin in the namespace, the user that removes the user must be the creator of the namespace
-	if isAdminInNamespace == true && foundNamespaceByID.UserID != removeNamespaceMembership.RemovedBy {
-		return nil, errors.NewUnauthorizedToRemoveAdminFromNamespaceError(removeNamespaceMembership.NamespaceID, removeNamespaceMembership.UserID, removeNamespaceMembership.RemovedBy)
-	}
 
-	removeByNamespaceIDAndUserID, err := removeNamespaceMembershipUseCase.NamespaceMembershipRepository.RemoveByNamespaceIDAndUserID(removeNamespaceMembership.NamespaceID, removeNamespaceMembership.UserID)
+	removedNamespaceMembership, err = removeNamespaceMembershipUseCase.NamespaceMembershipRepository.RemoveByNamespaceIDAndUserID(removeNamespaceMembership.NamespaceID, removeNamespaceMembership.UserID)
 	if err != nil {
 		fmt.Println(fmt.Errorf("error removing namespace membership (%s): %w", removeNamespaceMembership.NamespaceID, err))
 		return nil, err
 	}
-	if removeByNamespaceIDAndUserID == nil {
+	if removedNamespaceMembership == nil {
 		return nil, fmt.Errorf("namespace membership %s could not be removed", removeNamespaceMembership.NamespaceID)
 	}
 
-	return removeByNamespaceIDAndUserID, nil
+	return removedNamespaceMembership, nil
 }
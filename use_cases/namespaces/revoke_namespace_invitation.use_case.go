@@ -0,0 +1,64 @@
+package namespaces
+
+import (
+	"cloud-app-hive/controllers/errors"
+	"time"
+
+	"cloud-app-hive/domain"
+	"cloud-app-hive/domain/commands"
+	"cloud-app-hive/domain/repositories"
+	"cloud-app-hive/use_cases"
+)
+
+type RevokeNamespaceInvitationUseCase struct {
+	NamespaceRepository           repositories.NamespaceRepository
+	NamespaceInvitationRepository repositories.NamespaceInvitationRepository
+	RecordAuditEventUseCase       use_cases.RecordAuditEventUseCase
+}
+
+// Execute cancels a pending invitation, only the owner can revoke the invitations giving the roles that manage the namespace
+func (revokeNamespaceInvitationUseCase RevokeNamespaceInvitationUseCase) Execute(revokeNamespaceInvitation commands.RevokeNamespaceInvitation) (revokedNamespaceInvitation *domain.NamespaceInvitation, err error) {
+	auditEvent := domain.NewAuditEvent(revokeNamespaceInvitation.Actor, domain.AuditNamespaceInviteRevoke, domain.AuditTargetInvitation, revokeNamespaceInvitation.InvitationID)
+	auditEvent.NamespaceID = revokeNamespaceInvitation.NamespaceID
+	defer func() {
+		revokeNamespaceInvitationUseCase.RecordAuditEventUseCase.Execute(auditEvent, err)
+	}()
+
+	namespace, err := revokeNamespaceInvitationUseCase.NamespaceRepository.FindByID(revokeNamespaceInvitation.NamespaceID)
+	if err != nil {
+		return nil, err
+	}
+	if namespace == nil {
+		return nil, errors.NewNamespaceNotFoundByIDError(revokeNamespaceInvitation.NamespaceID)
+	}
+	if err := namespace.Authorize(revokeNamespaceInvitation.RevokedBy, domain.PermissionManageNamespaceMembers); err != nil {
+		return nil, err
+	}
+
+	invitation, err := revokeNamespaceInvitationUseCase.NamespaceInvitationRepository.FindByID(revokeNamespaceInvitation.InvitationID)
+	if err != nil {
+		return nil, err
+	}
+	if invitation == nil || invitation.NamespaceID != namespace.ID {
+		return nil, errors.NewNamespaceInvitationNotFoundError(revokeNamespaceInvitation.InvitationID)
+	}
+	auditEvent.TargetName = invitation.Email
+	if err := authorizeNamespaceInvitation(*namespace, revokeNamespaceInvitation.RevokedBy, invitation.Role); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	if !invitation.IsPending(now) {
+		return nil, errors.NewNamespaceInvitationNotPendingError(invitation.ID, string(invitation.CurrentStatus(now)))
+	}
+	updated, err := revokeNamespaceInvitationUseCase.NamespaceInvitationRepository.UpdateStatus(invitation.ID, domain.NamespaceInvitationRevoked, nil, now)
+	if err != nil {
+		return nil, err
+	}
+	if !updated {
+		return nil, errors.NewNamespaceInvitationNotPendingError(invitation.ID, "no longer pending")
+	}
+
+	invitation.Status = domain.NamespaceInvitationRevoked
+	return invitation, nil
+}
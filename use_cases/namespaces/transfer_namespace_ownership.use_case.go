@@ -0,0 +1,59 @@
+package namespaces
+
+import (
+	"cloud-app-hive/controllers/errors"
+	"fmt"
+	"time"
+
+	"cloud-app-hive/domain"
+	"cloud-app-hive/domain/commands"
+	"cloud-app-hive/domain/repositories"
+	"cloud-app-hive/use_cases"
+)
+
+type TransferNamespaceOwnershipUseCase struct {
+	NamespaceRepository     repositories.NamespaceRepository
+	RecordAuditEventUseCase use_cases.RecordAuditEventUseCase
+}
+
+// Execute offers the namespace to one of its members, the namespace changes owner once the member accepts.
+// A new transfer replaces the pending one.
+func (transferNamespaceOwnershipUseCase TransferNamespaceOwnershipUseCase) Execute(transferNamespaceOwnership commands.TransferNamespaceOwnership) (namespace *domain.Namespace, err error) {
+	auditEvent := domain.NewAuditEvent(transferNamespaceOwnership.Actor, domain.AuditNamespaceTransferRequest, domain.AuditTargetNamespace, transferNamespaceOwnership.NamespaceID)
+	auditEvent.NamespaceID = transferNamespaceOwnership.NamespaceID
+	defer func() {
+		transferNamespaceOwnershipUseCase.RecordAuditEventUseCase.Execute(auditEvent, err)
+	}()
+
+	namespace, err = transferNamespaceOwnershipUseCase.NamespaceRepository.FindByID(transferNamespaceOwnership.NamespaceID)
+	if err != nil {
+		return nil, err
+	}
+	if namespace == nil {
+		return nil, errors.NewNamespaceNotFoundByIDError(transferNamespaceOwnership.NamespaceID)
+	}
+	auditEvent.TargetName = namespace.Name
+
+	if err := namespace.AuthorizeOwnershipTransfer(transferNamespaceOwnership.RequestedBy); err != nil {
+		return nil, err
+	}
+	if transferNamespaceOwnership.NewOwnerID == namespace.UserID {
+		return nil, errors.NewInvalidNamespaceOwnershipTransferError(fmt.Sprintf("user %s already owns the namespace %s", transferNamespaceOwnership.NewOwnerID, namespace.Name))
+	}
+	if _, isMember := namespace.RoleOf(transferNamespaceOwnership.NewOwnerID); !isMember {
+		return nil, errors.NewInvalidNamespaceOwnershipTransferError(fmt.Sprintf("user %s must be a member of the namespace %s to own it", transferNamespaceOwnership.NewOwnerID, namespace.Name))
+	}
+	auditEvent.Changes = domain.NewAuditChanges(
+		map[string]*string{"pendingOwnerId": namespace.PendingOwnerID},
+		map[string]*string{"pendingOwnerId": &transferNamespaceOwnership.NewOwnerID},
+	)
+
+	expiresAt := time.Now().UTC().Add(domain.NamespaceOwnershipTransferValidity)
+	if err := transferNamespaceOwnershipUseCase.NamespaceRepository.SetPendingOwner(namespace.ID, &transferNamespaceOwnership.NewOwnerID, &expiresAt); err != nil {
+		return nil, err
+	}
+
+	namespace.PendingOwnerID = &transferNamespaceOwnership.NewOwnerID
+	namespace.OwnershipTransferExpiresAt = &expiresAt
+	return namespace, nil
+}
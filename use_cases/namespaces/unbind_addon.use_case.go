@@ -0,0 +1,55 @@
+package namespaces
+
+import (
+	"cloud-app-hive/controllers/errors"
+
+	"cloud-app-hive/domain"
+	"cloud-app-hive/domain/commands"
+	"cloud-app-hive/domain/repositories"
+	"cloud-app-hive/use_cases"
+)
+
+type UnbindAddonUseCase struct {
+	NamespaceRepository        repositories.NamespaceRepository
+	AddonRepository            repositories.AddonRepository
+	ApplicationRepository      repositories.ApplicationRepository
+	ContainerManagerRepository repositories.ContainerManagerRepository
+	RecordAuditEventUseCase    use_cases.RecordAuditEventUseCase
+}
+
+// Execute removes the connection settings of an add-on from the secrets of an application.
+// The application is deployed again if it is on the cluster, the data of the add-on is kept.
+func (unbindAddonUseCase UnbindAddonUseCase) Execute(unbindAddon commands.UnbindAddon) (deletedBinding *domain.AddonBinding, err error) {
+	auditEvent := domain.NewAuditEvent(unbindAddon.Actor, domain.AuditAddonUnbind, domain.AuditTargetAddon, unbindAddon.AddonID)
+	auditEvent.NamespaceID = unbindAddon.NamespaceID
+	defer func() {
+		if deletedBinding != nil {
+			auditEvent.Changes = domain.NewAuditChanges(deletedBinding, nil)
+		}
+		unbindAddonUseCase.RecordAuditEventUseCase.Execute(auditEvent, err)
+	}()
+
+	namespace, addon, application, err := findAddonBindingTargets(unbindAddonUseCase.NamespaceRepository, unbindAddonUseCase.AddonRepository, unbindAddon.NamespaceID, unbindAddon.AddonID, unbindAddon.ApplicationID, unbindAddon.UnboundBy)
+	if err != nil {
+		return nil, err
+	}
+	auditEvent.TargetName = addon.Name
+
+	var binding *domain.AddonBinding
+	for i := range addon.Bindings {
+		if addon.Bindings[i].ApplicationID == application.ID {
+			binding = &addon.Bindings[i]
+		}
+	}
+	if binding == nil {
+		return nil, errors.NewAddonBindingNotFoundError(addon.ID, application.ID)
+	}
+
+	if err := unbindAddonUseCase.AddonRepository.DeleteBinding(binding.ID); err != nil {
+		return nil, err
+	}
+	if err := redeployBoundApplication(unbindAddonUseCase.ApplicationRepository, unbindAddonUseCase.ContainerManagerRepository, application.ID, namespace.Name); err != nil {
+		return nil, err
+	}
+	return binding, nil
+}
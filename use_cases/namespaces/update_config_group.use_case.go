@@ -0,0 +1,93 @@
+package namespaces
+
+import (
+	"cloud-app-hive/controllers/errors"
+	"fmt"
+	"time"
+
+	"cloud-app-hive/domain"
+	"cloud-app-hive/domain/commands"
+	"cloud-app-hive/domain/repositories"
+	"cloud-app-hive/use_cases"
+	"cloud-app-hive/use_cases/applications"
+)
+
+type UpdateConfigGroupUseCase struct {
+	NamespaceRepository        repositories.NamespaceRepository
+	ConfigGroupRepository      repositories.ConfigGroupRepository
+	ApplicationRepository      repositories.ApplicationRepository
+	ContainerManagerRepository repositories.ContainerManagerRepository
+	RedeployApplicationUseCase applications.RedeployApplicationUseCase
+	RecordAuditEventUseCase    use_cases.RecordAuditEventUseCase
+}
+
+// Execute replaces the values of a config group, renders it again on the cluster
+// and starts an operation deploying again each attached application that is on the cluster, so that their pods are rolled out with the new values.
+// It returns the started operations, the failure of one of them does not stop the others.
+func (updateConfigGroupUseCase UpdateConfigGroupUseCase) Execute(updateConfigGroup commands.UpdateConfigGroup) (updatedConfigGroup *domain.ConfigGroup, startedOperations []domain.Operation, err error) {
+	auditEvent := domain.NewAuditEvent(updateConfigGroup.Actor, domain.AuditConfigGroupUpdate, domain.AuditTargetConfigGroup, updateConfigGroup.ConfigGroupID)
+	auditEvent.NamespaceID = updateConfigGroup.NamespaceID
+	var before domain.ConfigGroup
+	defer func() {
+		if updatedConfigGroup != nil {
+			auditEvent.Changes = domain.NewAuditChanges(before, updatedConfigGroup)
+		}
+		updateConfigGroupUseCase.RecordAuditEventUseCase.Execute(auditEvent, err)
+	}()
+
+	namespace, err := updateConfigGroupUseCase.NamespaceRepository.FindByID(updateConfigGroup.NamespaceID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if namespace == nil {
+		return nil, nil, errors.NewNamespaceNotFoundByIDError(updateConfigGroup.NamespaceID)
+	}
+	if err := namespace.Authorize(updateConfigGroup.UpdatedBy, domain.PermissionManageConfigGroups); err != nil {
+		return nil, nil, err
+	}
+
+	configGroup, err := updateConfigGroupUseCase.ConfigGroupRepository.FindByID(updateConfigGroup.ConfigGroupID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if configGroup == nil || configGroup.NamespaceID != namespace.ID {
+		return nil, nil, errors.NewConfigGroupNotFoundError(updateConfigGroup.ConfigGroupID)
+	}
+	auditEvent.TargetName = configGroup.Name
+	before = *configGroup
+
+	if err := configGroup.Update(updateConfigGroup.Description, updateConfigGroup.EnvironmentVariables, updateConfigGroup.Secrets, time.Now()); err != nil {
+		return nil, nil, errors.NewInvalidConfigGroupError(err.Error())
+	}
+	updatedConfigGroup, err = updateConfigGroupUseCase.ConfigGroupRepository.Update(*configGroup)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := updateConfigGroupUseCase.ContainerManagerRepository.ApplyConfigGroup(commands.NewApplyConfigGroup(*updatedConfigGroup, namespace.Name)); err != nil {
+		return nil, nil, err
+	}
+	startedOperations = []domain.Operation{}
+	for _, attachment := range updatedConfigGroup.Attachments {
+		operation, err := updateConfigGroupUseCase.startRedeployOperation(attachment.ApplicationID, namespace.Name, updateConfigGroup.UpdatedBy)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error while deploying application %s attached to config group %s: %w", attachment.ApplicationID, updatedConfigGroup.Name, err)
+		}
+		if operation != nil {
+			startedOperations = append(startedOperations, *operation)
+		}
+	}
+	return updatedConfigGroup, startedOperations, nil
+}
+
+// startRedeployOperation deploys again in the background an application attached to the group, if it is on the cluster.
+// An application that is not on the cluster gets the values of its config groups when it is deployed, no operation is started for it.
+func (updateConfigGroupUseCase UpdateConfigGroupUseCase) startRedeployOperation(applicationID string, namespaceName string, userID string) (*domain.Operation, error) {
+	application, err := updateConfigGroupUseCase.ApplicationRepository.FindByID(applicationID)
+	if err != nil {
+		return nil, err
+	}
+	if application == nil {
+		return nil, nil
+	}
+	return updateConfigGroupUseCase.RedeployApplicationUseCase.Execute(*application, namespaceName, userID)
+}
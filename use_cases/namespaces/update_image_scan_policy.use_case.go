@@ -0,0 +1,58 @@
+package namespaces
+
+import (
+	"cloud-app-hive/controllers/errors"
+	"fmt"
+
+	"cloud-app-hive/domain"
+	"cloud-app-hive/domain/commands"
+	"cloud-app-hive/domain/repositories"
+	"cloud-app-hive/use_cases"
+)
+
+type UpdateImageScanPolicyUseCase struct {
+	NamespaceRepository     repositories.NamespaceRepository
+	RecordAuditEventUseCase use_cases.RecordAuditEventUseCase
+}
+
+// Execute changes whether the deploys of the namespace are blocked or warned about when their image has vulnerabilities
+// more severe than the namespace allows. Applications already deployed are not affected.
+func (updateImageScanPolicyUseCase UpdateImageScanPolicyUseCase) Execute(updateImageScanPolicy commands.UpdateImageScanPolicy) (updatedNamespace *domain.Namespace, err error) {
+	auditEvent := domain.NewAuditEvent(updateImageScanPolicy.Actor, domain.AuditImageScanPolicyUpdate, domain.AuditTargetNamespace, updateImageScanPolicy.NamespaceID)
+	auditEvent.NamespaceID = updateImageScanPolicy.NamespaceID
+	defer func() {
+		updateImageScanPolicyUseCase.RecordAuditEventUseCase.Execute(auditEvent, err)
+	}()
+
+	if !updateImageScanPolicy.Policy.IsValid() {
+		return nil, errors.NewInvalidImageScanPolicyError(fmt.Sprintf("policy must be %s, %s or %s", domain.ImageScanPolicyOff, domain.ImageScanPolicyWarn, domain.ImageScanPolicyBlock))
+	}
+	if !updateImageScanPolicy.MaxSeverity.IsValid() {
+		return nil, errors.NewInvalidImageScanPolicyError(fmt.Sprintf("max severity %s is not one of the severities reported by Trivy", updateImageScanPolicy.MaxSeverity))
+	}
+
+	namespace, err := updateImageScanPolicyUseCase.NamespaceRepository.FindByID(updateImageScanPolicy.NamespaceID)
+	if err != nil {
+		return nil, err
+	}
+	if namespace == nil {
+		return nil, errors.NewNamespaceNotFoundByIDError(updateImageScanPolicy.NamespaceID)
+	}
+	auditEvent.TargetName = namespace.Name
+	if err := namespace.Authorize(updateImageScanPolicy.UpdatedBy, domain.PermissionManageImageScanPolicy); err != nil {
+		return nil, err
+	}
+
+	err = updateImageScanPolicyUseCase.NamespaceRepository.SetImageScanPolicy(namespace.ID, updateImageScanPolicy.Policy, updateImageScanPolicy.MaxSeverity)
+	if err != nil {
+		return nil, err
+	}
+	auditEvent.Changes = domain.NewAuditChanges(
+		map[string]string{"policy": string(namespace.ImageScanPolicy), "maxSeverity": string(namespace.MaxImageVulnerabilitySeverity)},
+		map[string]string{"policy": string(updateImageScanPolicy.Policy), "maxSeverity": string(updateImageScanPolicy.MaxSeverity)},
+	)
+
+	namespace.ImageScanPolicy = updateImageScanPolicy.Policy
+	namespace.MaxImageVulnerabilitySeverity = updateImageScanPolicy.MaxSeverity
+	return namespace, nil
+}
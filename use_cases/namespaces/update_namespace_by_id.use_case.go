@@ -7,13 +7,21 @@ import (
 	"cloud-app-hive/domain"
 	"cloud-app-hive/domain/commands"
 	"cloud-app-hive/domain/repositories"
+	"cloud-app-hive/use_cases"
 )
 
 type UpdateNamespaceByIDUseCase struct {
-	NamespaceRepository repositories.NamespaceRepository
+	NamespaceRepository     repositories.NamespaceRepository
+	RecordAuditEventUseCase use_cases.RecordAuditEventUseCase
 }
 
-func (UpdateNamespaceUseCase UpdateNamespaceByIDUseCase) Execute(updateNamespace commands.UpdateNamespace, userID string) (*domain.Namespace, error) {
+func (UpdateNamespaceUseCase UpdateNamespaceByIDUseCase) Execute(updateNamespace commands.UpdateNamespace, userID string) (updatedNamespace *domain.Namespace, err error) {
+	auditEvent := domain.NewAuditEvent(updateNamespace.Actor, domain.AuditNamespaceUpdate, domain.AuditTargetNamespace, updateNamespace.ID)
+	auditEvent.NamespaceID = updateNamespace.ID
+	defer func() {
+		UpdateNamespaceUseCase.RecordAuditEventUseCase.Execute(auditEvent, err)
+	}()
+
 	foundNamespaceByID, err := UpdateNamespaceUseCase.NamespaceRepository.FindByID(updateNamespace.ID)
 	if err != nil {
 		return nil, err
@@ -21,19 +29,13 @@ func (UpdateNamespaceUseCase UpdateNamespaceByIDUseCase) Execute(updateNamespace
 	if foundNamespaceByID == nil {
 		return nil, errors.NewNamespaceNotFoundByIDError(updateNamespace.ID)
 	}
+	auditEvent.TargetName = foundNamespaceByID.Name
 
-	isAdmin := false
-	for _, member := range foundNamespaceByID.Memberships {
-		if member.UserID == userID && member.Role == domain.RoleAdmin {
-			isAdmin = true
-			break
-		}
-	}
-	if !isAdmin {
-		return nil, errors.NewUnauthorizedToAccessNamespaceError(foundNamespaceByID.ID, foundNamespaceByID.Name, userID)
+	if err := foundNamespaceByID.Authorize(userID, domain.PermissionUpdateNamespace); err != nil {
+		return nil, err
 	}
 
-	updatedNamespace, err := UpdateNamespaceUseCase.NamespaceRepository.Update(updateNamespace)
+	updatedNamespace, err = UpdateNamespaceUseCase.NamespaceRepository.Update(updateNamespace)
 	if err != nil {
 		fmt.Println(fmt.Errorf("error updating namespace (%s): %w", updateNamespace.ID, err))
 		return nil, err
@@ -41,6 +43,10 @@ func (UpdateNamespaceUseCase UpdateNamespaceByIDUseCase) Execute(updateNamespace
 	if updatedNamespace == nil {
 		return nil, fmt.Errorf("namespace %s could not be updated", updateNamespace.ID)
 	}
+	auditEvent.Changes = domain.NewAuditChanges(
+		map[string]string{"description": foundNamespaceByID.Description},
+		map[string]string{"description": updatedNamespace.Description},
+	)
 
 	return updatedNamespace, nil
 }
@@ -0,0 +1,63 @@
+package namespaces
+
+import (
+	"cloud-app-hive/controllers/errors"
+	"fmt"
+
+	"cloud-app-hive/domain"
+	"cloud-app-hive/domain/commands"
+	"cloud-app-hive/domain/repositories"
+	"cloud-app-hive/use_cases"
+)
+
+type UpdateNamespaceMembershipUseCase struct {
+	NamespaceRepository           repositories.NamespaceRepository
+	NamespaceMembershipRepository repositories.NamespaceMembershipRepository
+	RecordAuditEventUseCase       use_cases.RecordAuditEventUseCase
+}
+
+// Execute changes the role of a member, only the owner can give or take the roles that manage the namespace
+func (updateNamespaceMembershipUseCase UpdateNamespaceMembershipUseCase) Execute(updateNamespaceMembership commands.UpdateNamespaceMembership) (updatedNamespaceMembership *domain.NamespaceMembership, err error) {
+	auditEvent := domain.NewAuditEvent(updateNamespaceMembership.Actor, domain.AuditNamespaceMemberUpdate, domain.AuditTargetMembership, updateNamespaceMembership.UserID)
+	auditEvent.NamespaceID = updateNamespaceMembership.NamespaceID
+	auditEvent.TargetName = updateNamespaceMembership.UserID
+	defer func() {
+		updateNamespaceMembershipUseCase.RecordAuditEventUseCase.Execute(auditEvent, err)
+	}()
+
+	foundNamespaceByID, err := updateNamespaceMembershipUseCase.NamespaceRepository.FindByID(updateNamespaceMembership.NamespaceID)
+	if err != nil {
+		return nil, err
+	}
+	if foundNamespaceByID == nil {
+		return nil, errors.NewNamespaceNotFoundByIDError(updateNamespaceMembership.NamespaceID)
+	}
+
+	if err := foundNamespaceByID.Authorize(updateNamespaceMembership.UpdatedBy, domain.PermissionManageNamespaceMembers); err != nil {
+		return nil, err
+	}
+
+	currentRole, isMember := foundNamespaceByID.RoleOf(updateNamespaceMembership.UserID)
+	if !isMember {
+		return nil, fmt.Errorf("namespace membership of user %s in namespace %s does not exist", updateNamespaceMembership.UserID, updateNamespaceMembership.NamespaceID)
+	}
+	auditEvent.Changes = domain.NewAuditChanges(map[string]domain.Role{"role": currentRole}, map[string]domain.Role{"role": updateNamespaceMembership.Role})
+	for _, role := range []domain.Role{currentRole, updateNamespaceMembership.Role} {
+		if role == domain.RoleOwner || role == domain.RoleAdmin {
+			if err := foundNamespaceByID.Authorize(updateNamespaceMembership.UpdatedBy, domain.PermissionManageNamespaceAdmins); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err := foundNamespaceByID.ValidateMembershipChange(updateNamespaceMembership.UserID, &updateNamespaceMembership.Role); err != nil {
+		return nil, err
+	}
+
+	updatedNamespaceMembership, err = updateNamespaceMembershipUseCase.NamespaceMembershipRepository.Update(updateNamespaceMembership)
+	if err != nil {
+		fmt.Println(fmt.Errorf("error updating namespace membership (%s): %w", updateNamespaceMembership.NamespaceID, err))
+		return nil, err
+	}
+
+	return updatedNamespaceMembership, nil
+}
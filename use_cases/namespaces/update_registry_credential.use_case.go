@@ -0,0 +1,82 @@
+package namespaces
+
+import (
+	"cloud-app-hive/controllers/errors"
+	"fmt"
+
+	"cloud-app-hive/domain"
+	"cloud-app-hive/domain/commands"
+	"cloud-app-hive/domain/repositories"
+	"cloud-app-hive/use_cases"
+)
+
+type UpdateRegistryCredentialUseCase struct {
+	NamespaceRepository          repositories.NamespaceRepository
+	RegistryCredentialRepository repositories.RegistryCredentialRepository
+	ApplicationRepository        repositories.ApplicationRepository
+	ContainerManagerRepository   repositories.ContainerManagerRepository
+	RecordAuditEventUseCase      use_cases.RecordAuditEventUseCase
+}
+
+// Execute updates a registry credential and applies the applications using it, so that their image pull Secrets use the new account
+func (updateRegistryCredentialUseCase UpdateRegistryCredentialUseCase) Execute(updateRegistryCredential commands.UpdateRegistryCredential) (updatedRegistryCredential *domain.RegistryCredential, err error) {
+	auditEvent := domain.NewAuditEvent(updateRegistryCredential.Actor, domain.AuditRegistryCredentialUpdate, domain.AuditTargetRegistryCredential, updateRegistryCredential.RegistryCredentialID)
+	auditEvent.NamespaceID = updateRegistryCredential.NamespaceID
+	var before *domain.RegistryCredential
+	defer func() {
+		if before != nil && updatedRegistryCredential != nil {
+			auditEvent.Changes = domain.NewAuditChanges(before, updatedRegistryCredential)
+		}
+		updateRegistryCredentialUseCase.RecordAuditEventUseCase.Execute(auditEvent, err)
+	}()
+
+	namespace, err := updateRegistryCredentialUseCase.NamespaceRepository.FindByID(updateRegistryCredential.NamespaceID)
+	if err != nil {
+		return nil, err
+	}
+	if namespace == nil {
+		return nil, errors.NewNamespaceNotFoundByIDError(updateRegistryCredential.NamespaceID)
+	}
+	if err := namespace.Authorize(updateRegistryCredential.UpdatedBy, domain.PermissionManageRegistries); err != nil {
+		return nil, err
+	}
+
+	registryCredential, err := updateRegistryCredentialUseCase.RegistryCredentialRepository.FindByID(updateRegistryCredential.RegistryCredentialID)
+	if err != nil {
+		return nil, err
+	}
+	if registryCredential == nil || registryCredential.NamespaceID != namespace.ID {
+		return nil, errors.NewRegistryCredentialNotFoundError(updateRegistryCredential.RegistryCredentialID)
+	}
+	auditEvent.TargetName = registryCredential.Name
+	before = registryCredential
+
+	server, err := domain.NormalizeRegistryServer(updateRegistryCredential.Server)
+	if err != nil {
+		return nil, errors.NewInvalidRegistryCredentialError(err.Error())
+	}
+	credential := *registryCredential
+	credential.Server = server
+	credential.Username = updateRegistryCredential.Username
+	// The stored password, still encrypted, is kept when no new password is given
+	if updateRegistryCredential.Password != "" {
+		credential.Password = updateRegistryCredential.Password
+		credential.PasswordPreview = domain.MaskSecretValue(updateRegistryCredential.Password)
+	}
+	updatedRegistryCredential, err = updateRegistryCredentialUseCase.RegistryCredentialRepository.Update(credential)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, applicationUsingRegistryCredential := range namespace.ApplicationsUsingRegistryCredential(registryCredential.ID) {
+		application, err := updateRegistryCredentialUseCase.ApplicationRepository.FindByID(applicationUsingRegistryCredential.ID)
+		if err != nil {
+			return nil, err
+		}
+		if err := updateRegistryCredentialUseCase.ContainerManagerRepository.ApplyApplication(commands.NewApplyApplication(*application, namespace.Name)); err != nil {
+			return nil, fmt.Errorf("error while applying application %s with the updated registry credential: %w", application.Name, err)
+		}
+	}
+
+	return updatedRegistryCredential, nil
+}
@@ -0,0 +1,62 @@
+package namespaces
+
+import (
+	"cloud-app-hive/controllers/errors"
+
+	"cloud-app-hive/domain"
+	"cloud-app-hive/domain/commands"
+	"cloud-app-hive/domain/repositories"
+	"cloud-app-hive/use_cases"
+)
+
+type UpdateWebhookUseCase struct {
+	NamespaceRepository     repositories.NamespaceRepository
+	WebhookRepository       repositories.WebhookRepository
+	RecordAuditEventUseCase use_cases.RecordAuditEventUseCase
+}
+
+// Execute changes the URL, the events and the state of a webhook, its secret is kept
+func (updateWebhookUseCase UpdateWebhookUseCase) Execute(updateWebhook commands.UpdateWebhook) (updatedWebhook *domain.Webhook, err error) {
+	auditEvent := domain.NewAuditEvent(updateWebhook.Actor, domain.AuditWebhookUpdate, domain.AuditTargetWebhook, updateWebhook.WebhookID)
+	auditEvent.NamespaceID = updateWebhook.NamespaceID
+	var before *domain.Webhook
+	defer func() {
+		if before != nil && updatedWebhook != nil {
+			auditEvent.Changes = domain.NewAuditChanges(before, updatedWebhook)
+		}
+		updateWebhookUseCase.RecordAuditEventUseCase.Execute(auditEvent, err)
+	}()
+
+	namespace, err := updateWebhookUseCase.NamespaceRepository.FindByID(updateWebhook.NamespaceID)
+	if err != nil {
+		return nil, err
+	}
+	if namespace == nil {
+		return nil, errors.NewNamespaceNotFoundByIDError(updateWebhook.NamespaceID)
+	}
+	if err := namespace.Authorize(updateWebhook.UpdatedBy, domain.PermissionManageWebhooks); err != nil {
+		return nil, err
+	}
+
+	webhook, err := updateWebhookUseCase.WebhookRepository.FindByID(updateWebhook.WebhookID)
+	if err != nil {
+		return nil, err
+	}
+	if webhook == nil || webhook.NamespaceID != namespace.ID {
+		return nil, errors.NewWebhookNotFoundError(updateWebhook.WebhookID)
+	}
+	auditEvent.TargetName = webhook.URL
+	before = webhook
+
+	if err := domain.ValidateWebhookURL(updateWebhook.URL); err != nil {
+		return nil, errors.NewInvalidWebhookError(err.Error())
+	}
+	if err := updateWebhook.EventTypes.Validate(); err != nil {
+		return nil, errors.NewInvalidWebhookError(err.Error())
+	}
+	updated := *webhook
+	updated.URL = updateWebhook.URL
+	updated.EventTypes = updateWebhook.EventTypes
+	updated.Active = updateWebhook.Active
+	return updateWebhookUseCase.WebhookRepository.Update(updated)
+}
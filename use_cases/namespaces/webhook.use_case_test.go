@@ -0,0 +1,289 @@
+package namespaces
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	customErrors "cloud-app-hive/controllers/errors"
+	"cloud-app-hive/domain"
+	"cloud-app-hive/domain/commands"
+	"cloud-app-hive/services"
+	"cloud-app-hive/use_cases"
+)
+
+// MockWebhookRepository is a mock implementation of the WebhookRepository interface keeping the webhooks in memory
+type MockWebhookRepository struct {
+	Webhooks []domain.Webhook
+}
+
+func (m *MockWebhookRepository) Create(webhook domain.Webhook) (*domain.Webhook, error) {
+	webhook.ID = fmt.Sprintf("webhook-%d", len(m.Webhooks)+1)
+	stored := webhook
+	stored.Secret = "encrypted:" + webhook.Secret
+	m.Webhooks = append(m.Webhooks, stored)
+	return &webhook, nil
+}
+
+func (m *MockWebhookRepository) FindByID(id string) (*domain.Webhook, error) {
+	for _, webhook := range m.Webhooks {
+		if webhook.ID == id {
+			return &webhook, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *MockWebhookRepository) FindByNamespaceID(namespaceID string) ([]domain.Webhook, error) {
+	var webhooks []domain.Webhook
+	for _, webhook := range m.Webhooks {
+		if webhook.NamespaceID == namespaceID {
+			webhooks = append(webhooks, webhook)
+		}
+	}
+	return webhooks, nil
+}
+
+func (m *MockWebhookRepository) FindAll() ([]domain.Webhook, error) {
+	return m.Webhooks, nil
+}
+
+func (m *MockWebhookRepository) Update(webhook domain.Webhook) (*domain.Webhook, error) {
+	for index := range m.Webhooks {
+		if m.Webhooks[index].ID == webhook.ID {
+			m.Webhooks[index] = webhook
+		}
+	}
+	return &webhook, nil
+}
+
+func (m *MockWebhookRepository) Delete(id string) error {
+	for index, webhook := range m.Webhooks {
+		if webhook.ID == id {
+			m.Webhooks = append(m.Webhooks[:index], m.Webhooks[index+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+// MockWebhookDeliveryRepository is a mock implementation of the WebhookDeliveryRepository interface, deliveries are recorded from the delivering goroutines
+type MockWebhookDeliveryRepository struct {
+	mutex      sync.Mutex
+	Deliveries []domain.WebhookDelivery
+}
+
+func (m *MockWebhookDeliveryRepository) Create(delivery domain.WebhookDelivery) (*domain.WebhookDelivery, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	delivery.ID = fmt.Sprintf("delivery-%d", len(m.Deliveries)+1)
+	m.Deliveries = append(m.Deliveries, delivery)
+	return &delivery, nil
+}
+
+func (m *MockWebhookDeliveryRepository) FindByWebhookID(webhookID string, limit int) ([]domain.WebhookDelivery, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	var deliveries []domain.WebhookDelivery
+	for index := len(m.Deliveries) - 1; index >= 0 && len(deliveries) < limit; index-- {
+		if m.Deliveries[index].WebhookID == webhookID {
+			deliveries = append(deliveries, m.Deliveries[index])
+		}
+	}
+	return deliveries, nil
+}
+
+func (m *MockWebhookDeliveryRepository) FindDue(now time.Time) ([]domain.WebhookDelivery, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	var deliveries []domain.WebhookDelivery
+	for _, delivery := range m.Deliveries {
+		if delivery.NextAttemptAt != nil && !delivery.NextAttemptAt.After(now) {
+			deliveries = append(deliveries, delivery)
+		}
+	}
+	return deliveries, nil
+}
+
+func (m *MockWebhookDeliveryRepository) ClearNextAttempt(webhookDeliveryID string) (bool, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	for index := range m.Deliveries {
+		if m.Deliveries[index].ID == webhookDeliveryID && m.Deliveries[index].NextAttemptAt != nil {
+			m.Deliveries[index].NextAttemptAt = nil
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// MockSecretsCipher is a mock implementation of the SecretsCipher interface removing the "encrypted:" prefix of the values
+type MockSecretsCipher struct{}
+
+func (m MockSecretsCipher) Encrypt(namespaceID string, secrets domain.ApplicationSecrets) (domain.ApplicationSecrets, error) {
+	return secrets, nil
+}
+
+func (m MockSecretsCipher) Decrypt(secrets domain.ApplicationSecrets) (domain.ApplicationSecrets, error) {
+	decrypted := domain.ApplicationSecrets{}
+	for _, secret := range secrets {
+		secret.Val = strings.TrimPrefix(secret.Val, "encrypted:")
+		decrypted = append(decrypted, secret)
+	}
+	return decrypted, nil
+}
+
+func (m MockSecretsCipher) RotateDataKey(namespaceID string) error {
+	return nil
+}
+
+func (m MockSecretsCipher) PruneDataKeys(storedSecrets domain.ApplicationSecrets) error {
+	return nil
+}
+
+func TestExecute_CreateWebhook_ReturnsSecretOnce(t *testing.T) {
+	webhookRepository := &MockWebhookRepository{}
+	useCase := CreateWebhookUseCase{
+		NamespaceRepository:     newTestRegistryCredentialNamespaceRepository(),
+		WebhookRepository:       webhookRepository,
+		RecordAuditEventUseCase: newTestRecordAuditEventUseCase(&MockAuditEventRepository{}),
+	}
+
+	createWebhook := commands.CreateWebhook{NamespaceID: "backend-id", URL: "https://hooks.example.com", EventTypes: domain.WebhookEventTypes{domain.WebhookApplicationDeployed}, CreatedBy: "admin"}
+	webhook, secret, err := useCase.Execute(createWebhook)
+	if err != nil {
+		t.Fatalf("Expected the webhook to be created, but got %v", err)
+	}
+	if !webhook.Active || !strings.HasPrefix(secret, domain.WebhookSecretPrefix) {
+		t.Errorf("Expected an active webhook and a secret starting with %s, but got %+v and %s", domain.WebhookSecretPrefix, webhook, secret)
+	}
+	if webhookRepository.Webhooks[0].Secret != "encrypted:"+secret {
+		t.Errorf("Expected the secret to be stored encrypted, but got %s", webhookRepository.Webhooks[0].Secret)
+	}
+
+	createWebhook.URL = "ftp://hooks.example.com"
+	if _, _, err := useCase.Execute(createWebhook); err == nil {
+		t.Error("Expected an ftp URL to be rejected, but got nil")
+	} else if _, ok := err.(*customErrors.InvalidWebhookError); !ok {
+		t.Errorf("Expected an InvalidWebhookError, but got %v", err)
+	}
+	createWebhook.URL = "https://hooks.example.com"
+	createWebhook.CreatedBy = "developer"
+	if _, _, err := useCase.Execute(createWebhook); err == nil {
+		t.Error("Expected a developer to be forbidden, but got nil")
+	}
+	if len(webhookRepository.Webhooks) != 1 {
+		t.Errorf("Expected 1 webhook to be stored, but got %d", len(webhookRepository.Webhooks))
+	}
+}
+
+func TestExecute_PublishWebhookEvent_SignsAndRetriesFailedDeliveries(t *testing.T) {
+	var mutex sync.Mutex
+	requests := 0
+	var signatureErr error
+	var secret string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		requests++
+		payload, _ := io.ReadAll(r.Body)
+		timestamp, _ := strconv.ParseInt(r.Header.Get(domain.WebhookTimestampHeader), 10, 64)
+		if expected := domain.SignWebhookPayload(secret, time.Unix(timestamp, 0), payload); r.Header.Get(domain.WebhookSignatureHeader) != expected {
+			signatureErr = fmt.Errorf("expected the signature %s, got %s", expected, r.Header.Get(domain.WebhookSignatureHeader))
+		}
+		// The endpoint is unavailable the first time
+		if requests == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	// The test endpoint listens on the loopback address the webhooks are denied by default
+	webhookSender, err := services.NewHTTPWebhookSenderServiceDenyingNetworks(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	webhookRepository := &MockWebhookRepository{}
+	webhookDeliveryRepository := &MockWebhookDeliveryRepository{}
+	recordAuditEventUseCase := newTestRecordAuditEventUseCase(&MockAuditEventRepository{})
+	recordAuditEventUseCase.PublishWebhookEventUseCase = &use_cases.PublishWebhookEventUseCase{
+		WebhookRepository:         webhookRepository,
+		WebhookDeliveryRepository: webhookDeliveryRepository,
+		WebhookSender:             webhookSender,
+		SecretsCipher:             MockSecretsCipher{},
+		RetryDelay:                10 * time.Millisecond,
+	}
+	retryWebhookDeliveriesUseCase := use_cases.RetryWebhookDeliveriesUseCase{
+		WebhookRepository:          webhookRepository,
+		WebhookDeliveryRepository:  webhookDeliveryRepository,
+		PublishWebhookEventUseCase: recordAuditEventUseCase.PublishWebhookEventUseCase,
+	}
+
+	createWebhookUseCase := CreateWebhookUseCase{
+		NamespaceRepository:     newTestRegistryCredentialNamespaceRepository(),
+		WebhookRepository:       webhookRepository,
+		RecordAuditEventUseCase: recordAuditEventUseCase,
+	}
+	webhook, createdSecret, err := createWebhookUseCase.Execute(commands.CreateWebhook{NamespaceID: "backend-id", URL: server.URL, EventTypes: domain.WebhookEventTypes{domain.WebhookMembershipRemoved}, CreatedBy: "admin"})
+	if err != nil {
+		t.Fatalf("Expected the webhook to be created, but got %v", err)
+	}
+	mutex.Lock()
+	secret = createdSecret
+	mutex.Unlock()
+
+	auditEvent := domain.NewAuditEvent(domain.AuditActor{UserID: "admin"}, domain.AuditNamespaceMemberRemove, domain.AuditTargetMembership, "developer")
+	auditEvent.NamespaceID = "backend-id"
+	recordAuditEventUseCase.Execute(auditEvent, nil)
+
+	findWebhooksUseCase := FindWebhooksUseCase{
+		NamespaceRepository:       newTestRegistryCredentialNamespaceRepository(),
+		WebhookRepository:         webhookRepository,
+		WebhookDeliveryRepository: webhookDeliveryRepository,
+	}
+	var deliveries []domain.WebhookDelivery
+	retries := 0
+	for start := time.Now(); time.Since(start) < 5*time.Second && len(deliveries) < 2; time.Sleep(10 * time.Millisecond) {
+		// The failed attempt is sent again by the scheduler once it is due
+		attempts, err := retryWebhookDeliveriesUseCase.Execute()
+		if err != nil {
+			t.Fatalf("Expected the deliveries to be retried, but got %v", err)
+		}
+		retries += attempts
+		deliveries, err = findWebhooksUseCase.FindDeliveries("backend-id", webhook.ID, "admin")
+		if err != nil {
+			t.Fatalf("Expected the deliveries to be found, but got %v", err)
+		}
+	}
+
+	if len(deliveries) != 2 {
+		t.Fatalf("Expected 2 delivery attempts, but got %+v", deliveries)
+	}
+	// Most recent first
+	if !deliveries[0].Succeeded || deliveries[0].Attempt != 2 || deliveries[0].StatusCode != http.StatusNoContent {
+		t.Errorf("Expected the second attempt to succeed, but got %+v", deliveries[0])
+	}
+	if deliveries[1].Succeeded || deliveries[1].StatusCode != http.StatusInternalServerError || deliveries[1].NextAttemptAt != nil || retries != 1 {
+		t.Errorf("Expected the first attempt to fail and be retried once, but got %+v after %d retries", deliveries[1], retries)
+	}
+	if deliveries[0].EventType != domain.WebhookMembershipRemoved || deliveries[0].EventID != deliveries[1].EventID {
+		t.Errorf("Expected both attempts to deliver the same membership.removed event, but got %+v", deliveries)
+	}
+	mutex.Lock()
+	defer mutex.Unlock()
+	if signatureErr != nil {
+		t.Error(signatureErr)
+	}
+
+	if _, err := findWebhooksUseCase.FindDeliveries("backend-id", webhook.ID, "developer"); err == nil {
+		t.Error("Expected a developer not to read the delivery log, but got nil")
+	}
+}
@@ -0,0 +1,34 @@
+package operations
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"cloud-app-hive/domain/repositories"
+)
+
+// errOperationInterrupted is the error of the operations that were running when the API stopped
+var errOperationInterrupted = errors.New("operation interrupted by a restart of the API")
+
+type FailInterruptedOperationsUseCase struct {
+	OperationRepository repositories.OperationRepository
+}
+
+// Execute marks the operations still pending or running as failed.
+// Operations run in the API process, so the ones left unfinished at boot will never finish and must not block their application.
+// It fails the unfinished operations of every process, which is why a single replica of the API is supported.
+func (failInterruptedOperationsUseCase FailInterruptedOperationsUseCase) Execute() error {
+	unfinishedOperations, err := failInterruptedOperationsUseCase.OperationRepository.FindUnfinished()
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	for _, operation := range unfinishedOperations {
+		operation.Finish(errOperationInterrupted, now)
+		if err := failInterruptedOperationsUseCase.OperationRepository.Update(operation); err != nil {
+			return fmt.Errorf("error while failing interrupted operation %s: %w", operation.ID, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+package operations
+
+import (
+	"cloud-app-hive/controllers/errors"
+
+	"cloud-app-hive/domain"
+	"cloud-app-hive/domain/repositories"
+)
+
+type FindOperationByIDUseCase struct {
+	OperationRepository repositories.OperationRepository
+	NamespaceRepository repositories.NamespaceRepository
+}
+
+// Execute returns the operation if the user can read the status of the applications of its namespace
+func (findOperationByIDUseCase FindOperationByIDUseCase) Execute(id string, userID string) (*domain.Operation, error) {
+	operation, err := findOperationByIDUseCase.OperationRepository.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if operation == nil {
+		return nil, errors.NewOperationNotFoundByIDError(id)
+	}
+
+	namespace, err := findOperationByIDUseCase.NamespaceRepository.FindByID(operation.NamespaceID)
+	if err != nil {
+		return nil, err
+	}
+	if namespace == nil {
+		return nil, errors.NewOperationNotFoundByIDError(id)
+	}
+	if err := namespace.Authorize(userID, domain.PermissionReadApplicationStatus); err != nil {
+		return nil, err
+	}
+	return operation, nil
+}
@@ -0,0 +1,214 @@
+package operations
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"cloud-app-hive/domain"
+	"cloud-app-hive/domain/commands"
+	"cloud-app-hive/domain/repositories"
+	"cloud-app-hive/use_cases"
+)
+
+const defaultRolloutTimeout = 5 * time.Minute
+const defaultRolloutPollInterval = 5 * time.Second
+
+type StartApplicationOperationUseCase struct {
+	OperationRepository        repositories.OperationRepository
+	ContainerManagerRepository repositories.ContainerManagerRepository
+	// RolloutTimeout is how long the rollout step waits for the pods to be ready, 5 minutes when zero
+	RolloutTimeout time.Duration
+	// RolloutPollInterval is how often the rollout step checks the status of the application, 5 seconds when zero
+	RolloutPollInterval time.Duration
+	// PublishWebhookEventUseCase sends the finished operations webhooks subscribe to, no event is sent when it is nil
+	PublishWebhookEventUseCase *use_cases.PublishWebhookEventUseCase
+}
+
+// applicationOperationQueues runs the operations of the same application one after the other.
+// The queues are kept in the API process, the operations of another replica would not wait for them.
+var applicationOperationQueues = newOperationQueues()
+
+// Execute records a pending operation and runs it in the background, the operation is saved each time one of its steps progresses.
+// The operation stays pending until the previous operations on the same application are finished, so that they do not apply concurrently.
+// It returns the recorded operation as soon as it is stored.
+func (startApplicationOperationUseCase StartApplicationOperationUseCase) Execute(startApplicationOperation commands.StartApplicationOperation) (*domain.Operation, error) {
+	operation := domain.NewOperation(startApplicationOperation.Type, startApplicationOperation.Application, startApplicationOperation.UserID)
+	createdOperation, err := startApplicationOperationUseCase.OperationRepository.Create(operation)
+	if err != nil {
+		return nil, fmt.Errorf("error while creating operation: %w", err)
+	}
+
+	recorder := &operationStepRecorder{
+		operationRepository: startApplicationOperationUseCase.OperationRepository,
+		operation:           copyOperation(*createdOperation),
+	}
+	previous, done := applicationOperationQueues.enqueue(createdOperation.ApplicationID)
+	go func() {
+		defer applicationOperationQueues.dequeue(createdOperation.ApplicationID, done)
+		<-previous
+		startApplicationOperationUseCase.run(recorder, startApplicationOperation)
+	}()
+
+	return createdOperation, nil
+}
+
+func (startApplicationOperationUseCase StartApplicationOperationUseCase) run(recorder *operationStepRecorder, startApplicationOperation commands.StartApplicationOperation) {
+	recorder.start()
+	err := startApplicationOperationUseCase.runSteps(recorder, startApplicationOperation)
+	finishedOperation := recorder.finish(err)
+
+	if startApplicationOperationUseCase.PublishWebhookEventUseCase == nil {
+		return
+	}
+	if event, ok := domain.NewWebhookEventFromOperation(finishedOperation); ok {
+		startApplicationOperationUseCase.PublishWebhookEventUseCase.Execute(event)
+	}
+}
+
+// runSteps runs the steps of the operation, a panic fails the step that was running instead of stopping the API
+func (startApplicationOperationUseCase StartApplicationOperationUseCase) runSteps(recorder *operationStepRecorder, startApplicationOperation commands.StartApplicationOperation) (err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("operation %s panicked: %v", recorder.operation.ID, recovered)
+		}
+	}()
+
+	err = startApplicationOperation.Run(recorder)
+	if err == nil && startApplicationOperation.Type.WaitsForRollout() {
+		err = domain.RunOperationStep(recorder, domain.RolloutOperationStep, func() error {
+			return startApplicationOperationUseCase.waitForRollout(startApplicationOperation.Application.Name, startApplicationOperation.NamespaceName)
+		})
+	}
+	return err
+}
+
+// waitForRollout waits until all the replicas of the application run its last version and are ready.
+// It fails as soon as a pod cannot start, or when the rollout timeout is reached.
+func (startApplicationOperationUseCase StartApplicationOperationUseCase) waitForRollout(applicationName string, namespaceName string) error {
+	timeout := startApplicationOperationUseCase.RolloutTimeout
+	if timeout == 0 {
+		timeout = defaultRolloutTimeout
+	}
+	pollInterval := startApplicationOperationUseCase.RolloutPollInterval
+	if pollInterval == 0 {
+		pollInterval = defaultRolloutPollInterval
+	}
+
+	deadline := time.Now().Add(timeout)
+	for {
+		// The status is checked after an interval so that the deployment controller notices the new version first
+		time.Sleep(pollInterval)
+		applicationStatus, err := startApplicationOperationUseCase.ContainerManagerRepository.GetApplicationStatus(commands.GetApplicationStatus{
+			Name:      applicationName,
+			Namespace: namespaceName,
+		})
+		if err == nil && applicationStatus != nil {
+			if applicationStatus.IsRolledOut() {
+				return nil
+			}
+			if failure := applicationStatus.RolloutFailure(); failure != "" {
+				return fmt.Errorf("rollout of application %s failed: %s", applicationName, failure)
+			}
+		}
+		if time.Now().After(deadline) {
+			if err != nil {
+				return fmt.Errorf("rollout of application %s did not finish within %s: %w", applicationName, timeout, err)
+			}
+			if applicationStatus == nil {
+				return fmt.Errorf("rollout of application %s did not finish within %s: application status not found", applicationName, timeout)
+			}
+			return fmt.Errorf("rollout of application %s did not finish within %s: %s", applicationName, timeout, applicationStatus.StatusInString)
+		}
+	}
+}
+
+// operationStepRecorder saves the progress of an operation each time one of its steps starts or finishes
+type operationStepRecorder struct {
+	operationRepository repositories.OperationRepository
+	mutex               sync.Mutex
+	operation           domain.Operation
+}
+
+func (recorder *operationStepRecorder) start() {
+	recorder.update(func(operation *domain.Operation, now time.Time) {
+		operation.Start(now)
+	})
+}
+
+// finish marks the operation as finished and returns it
+func (recorder *operationStepRecorder) finish(err error) domain.Operation {
+	recorder.update(func(operation *domain.Operation, now time.Time) {
+		operation.Finish(err, now)
+	})
+	recorder.mutex.Lock()
+	defer recorder.mutex.Unlock()
+	return copyOperation(recorder.operation)
+}
+
+func (recorder *operationStepRecorder) StartStep(name domain.OperationStepName) {
+	recorder.update(func(operation *domain.Operation, now time.Time) {
+		operation.StartStep(name, now)
+	})
+}
+
+func (recorder *operationStepRecorder) FinishStep(name domain.OperationStepName, err error) {
+	recorder.update(func(operation *domain.Operation, now time.Time) {
+		operation.FinishStep(name, err, now)
+	})
+}
+
+func (recorder *operationStepRecorder) update(update func(operation *domain.Operation, now time.Time)) {
+	recorder.mutex.Lock()
+	defer recorder.mutex.Unlock()
+	update(&recorder.operation, time.Now())
+	// The operation keeps running when its progress cannot be saved, its result is saved with the next step
+	if err := recorder.operationRepository.Update(copyOperation(recorder.operation)); err != nil {
+		fmt.Println(fmt.Errorf("error while saving operation %s: %w", recorder.operation.ID, err))
+	}
+}
+
+// copyOperation returns a copy of the operation that does not share its steps
+func copyOperation(operation domain.Operation) domain.Operation {
+	if operation.Steps != nil {
+		steps := make(domain.OperationSteps, len(*operation.Steps))
+		copy(steps, *operation.Steps)
+		operation.Steps = &steps
+	}
+	return operation
+}
+
+// operationQueues keeps, for each application, the channel closed when its last started operation finishes
+type operationQueues struct {
+	mutex sync.Mutex
+	last  map[string]chan struct{}
+}
+
+func newOperationQueues() *operationQueues {
+	return &operationQueues{last: map[string]chan struct{}{}}
+}
+
+// enqueue returns the channel closed when the previous operation on the application finishes,
+// and the channel to close when the new one finishes
+func (queues *operationQueues) enqueue(applicationID string) (<-chan struct{}, chan struct{}) {
+	queues.mutex.Lock()
+	defer queues.mutex.Unlock()
+	previous, ok := queues.last[applicationID]
+	if !ok {
+		previous = make(chan struct{})
+		close(previous)
+	}
+	done := make(chan struct{})
+	queues.last[applicationID] = done
+	return previous, done
+}
+
+// dequeue lets the next operation on the application run, and forgets the application when no other operation waits
+func (queues *operationQueues) dequeue(applicationID string, done chan struct{}) {
+	queues.mutex.Lock()
+	defer queues.mutex.Unlock()
+	close(done)
+	if queues.last[applicationID] == done {
+		delete(queues.last, applicationID)
+	}
+}
@@ -0,0 +1,256 @@
+package operations
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"cloud-app-hive/domain"
+	"cloud-app-hive/domain/commands"
+	"cloud-app-hive/repositories"
+)
+
+type memoryOperationRepository struct {
+	mutex      sync.Mutex
+	operations map[string]domain.Operation
+}
+
+func newMemoryOperationRepository() *memoryOperationRepository {
+	return &memoryOperationRepository{operations: map[string]domain.Operation{}}
+}
+
+func (repository *memoryOperationRepository) Create(operation domain.Operation) (*domain.Operation, error) {
+	repository.mutex.Lock()
+	defer repository.mutex.Unlock()
+	operation.ID = fmt.Sprintf("operation-%d", len(repository.operations)+1)
+	repository.operations[operation.ID] = copyOperation(operation)
+	return &operation, nil
+}
+
+func (repository *memoryOperationRepository) FindByID(id string) (*domain.Operation, error) {
+	repository.mutex.Lock()
+	defer repository.mutex.Unlock()
+	operation, ok := repository.operations[id]
+	if !ok {
+		return nil, nil
+	}
+	operation = copyOperation(operation)
+	return &operation, nil
+}
+
+func (repository *memoryOperationRepository) FindUnfinished() ([]domain.Operation, error) {
+	repository.mutex.Lock()
+	defer repository.mutex.Unlock()
+	operations := []domain.Operation{}
+	for _, operation := range repository.operations {
+		if !operation.IsFinished() {
+			operations = append(operations, copyOperation(operation))
+		}
+	}
+	return operations, nil
+}
+
+func (repository *memoryOperationRepository) Update(operation domain.Operation) error {
+	repository.mutex.Lock()
+	defer repository.mutex.Unlock()
+	repository.operations[operation.ID] = copyOperation(operation)
+	return nil
+}
+
+func waitForOperation(t *testing.T, repository *memoryOperationRepository, id string) domain.Operation {
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		operation, _ := repository.FindByID(id)
+		if operation.IsFinished() {
+			return *operation
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("operation %s did not finish", id)
+	return domain.Operation{}
+}
+
+func newTestApplyApplication() commands.ApplyApplication {
+	return commands.ApplyApplication{
+		Name:            "api",
+		Image:           "nginx:latest",
+		Registry:        domain.DockerHubRegistry,
+		Namespace:       "team",
+		Port:            80,
+		ApplicationType: domain.SingleInstance,
+		ContainerSpecifications: domain.ApplicationContainerSpecifications{
+			CPULimit:    &domain.ContainerCpuLimit{Val: 500, Unit: "mCPU"},
+			MemoryLimit: &domain.ContainerMemoryLimit{Val: 256, Unit: domain.MB},
+		},
+		ScalabilitySpecifications: domain.ApplicationScalabilitySpecifications{Replicas: 2},
+	}
+}
+
+func startTestDeployOperation(t *testing.T, containerManager *repositories.MemoryContainerManagerRepository, operationRepository *memoryOperationRepository) domain.Operation {
+	startApplicationOperationUseCase := StartApplicationOperationUseCase{
+		OperationRepository:        operationRepository,
+		ContainerManagerRepository: containerManager,
+		RolloutTimeout:             time.Second,
+		RolloutPollInterval:        10 * time.Millisecond,
+	}
+	operation, err := startApplicationOperationUseCase.Execute(commands.StartApplicationOperation{
+		Type:          domain.DeployOperation,
+		Application:   domain.Application{ID: "app-id", Name: "api", NamespaceID: "namespace-id"},
+		NamespaceName: "team",
+		UserID:        "user-id",
+		Run: func(reporter domain.OperationStepReporter) error {
+			applyApplication := newTestApplyApplication()
+			applyApplication.StepReporter = reporter
+			return containerManager.ApplyApplication(applyApplication)
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if operation.Status != domain.OperationPending {
+		t.Errorf("expected the started operation to be pending, got %s", operation.Status)
+	}
+	return waitForOperation(t, operationRepository, operation.ID)
+}
+
+func TestStartApplicationOperation_TracksTheStepsUntilTheRolloutFinishes(t *testing.T) {
+	containerManager := repositories.NewMemoryContainerManagerRepository(repositories.MemoryContainerManagerConfig{PodStartupDuration: 50 * time.Millisecond, Nodes: 1})
+	operationRepository := newMemoryOperationRepository()
+
+	operation := startTestDeployOperation(t, containerManager, operationRepository)
+	if operation.Status != domain.OperationSucceeded {
+		t.Fatalf("expected the operation to succeed, got %s: %s", operation.Status, operation.Error)
+	}
+	for _, step := range *operation.Steps {
+		if step.Status != domain.OperationSucceeded || step.StartedAt == nil || step.FinishedAt == nil {
+			t.Errorf("expected step %s to have succeeded, got %+v", step.Name, step)
+		}
+	}
+}
+
+func TestStartApplicationOperation_RecordsTheFailedStep(t *testing.T) {
+	containerManager := repositories.NewMemoryContainerManagerRepository(repositories.MemoryContainerManagerConfig{Nodes: 1})
+	containerManager.InjectFailure(repositories.MemoryApplyApplication, 1)
+	operationRepository := newMemoryOperationRepository()
+
+	operation := startTestDeployOperation(t, containerManager, operationRepository)
+	if operation.Status != domain.OperationFailed || operation.FailedStep != domain.NamespaceOperationStep || operation.Error == "" {
+		t.Fatalf("expected the namespace step to fail, got %s at step %s", operation.Status, operation.FailedStep)
+	}
+	if (*operation.Steps)[1].Status != domain.OperationPending {
+		t.Errorf("expected the steps after the failed one not to run, got %+v", (*operation.Steps)[1])
+	}
+}
+
+func TestStartApplicationOperation_FailsTheRolloutOfCrashingPods(t *testing.T) {
+	containerManager := repositories.NewMemoryContainerManagerRepository(repositories.MemoryContainerManagerConfig{PodStartupDuration: time.Hour, Nodes: 1})
+	operationRepository := newMemoryOperationRepository()
+	if err := containerManager.ApplyApplication(newTestApplyApplication()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := containerManager.SetApplicationFailure("team", "api", repositories.MemoryPodCrashLoopBackOff); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	operation := startTestDeployOperation(t, containerManager, operationRepository)
+	if operation.Status != domain.OperationFailed || operation.FailedStep != domain.RolloutOperationStep {
+		t.Fatalf("expected the rollout step to fail, got %s at step %s: %s", operation.Status, operation.FailedStep, operation.Error)
+	}
+}
+
+func TestStartApplicationOperation_FailsTheStepThatPanicked(t *testing.T) {
+	operationRepository := newMemoryOperationRepository()
+	startApplicationOperationUseCase := StartApplicationOperationUseCase{OperationRepository: operationRepository}
+
+	operation, err := startApplicationOperationUseCase.Execute(commands.StartApplicationOperation{
+		Type:        domain.DeleteOperation,
+		Application: domain.Application{ID: "app-id", Name: "api", NamespaceID: "namespace-id"},
+		Run: func(reporter domain.OperationStepReporter) error {
+			return domain.RunOperationStep(reporter, domain.IngressOperationStep, func() error {
+				var status *domain.ApplicationStatus
+				_ = status.StatusInString
+				return nil
+			})
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	finishedOperation := waitForOperation(t, operationRepository, operation.ID)
+	if finishedOperation.Status != domain.OperationFailed || finishedOperation.FailedStep != domain.IngressOperationStep || finishedOperation.Error == "" {
+		t.Fatalf("expected the ingress step to fail, got %s at step %s: %s", finishedOperation.Status, finishedOperation.FailedStep, finishedOperation.Error)
+	}
+}
+
+func TestStartApplicationOperation_RunsTheOperationsOfAnApplicationOneAfterTheOther(t *testing.T) {
+	operationRepository := newMemoryOperationRepository()
+	startApplicationOperationUseCase := StartApplicationOperationUseCase{OperationRepository: operationRepository}
+	application := domain.Application{ID: "app-id", Name: "api", NamespaceID: "namespace-id"}
+	release := make(chan struct{})
+	var mutex sync.Mutex
+	var runs []string
+	startOperation := func(name string, wait bool) domain.Operation {
+		operation, err := startApplicationOperationUseCase.Execute(commands.StartApplicationOperation{
+			Type:        domain.DeleteOperation,
+			Application: application,
+			Run: func(reporter domain.OperationStepReporter) error {
+				if wait {
+					<-release
+				}
+				mutex.Lock()
+				defer mutex.Unlock()
+				runs = append(runs, name)
+				return nil
+			},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return *operation
+	}
+
+	first := startOperation("first", true)
+	second := startOperation("second", false)
+	time.Sleep(20 * time.Millisecond)
+	if operation, _ := operationRepository.FindByID(second.ID); operation.Status != domain.OperationPending {
+		t.Errorf("expected the second operation to wait for the first one, got %s", operation.Status)
+	}
+
+	close(release)
+	waitForOperation(t, operationRepository, first.ID)
+	waitForOperation(t, operationRepository, second.ID)
+	mutex.Lock()
+	defer mutex.Unlock()
+	if len(runs) != 2 || runs[0] != "first" || runs[1] != "second" {
+		t.Errorf("expected the operations to run in the order they started, got %v", runs)
+	}
+}
+
+func TestFailInterruptedOperations_FailsTheUnfinishedOperations(t *testing.T) {
+	operationRepository := newMemoryOperationRepository()
+	application := domain.Application{ID: "app-id", Name: "api", NamespaceID: "namespace-id"}
+	running, _ := operationRepository.Create(domain.NewOperation(domain.DeployOperation, application, "user-id"))
+	running.Start(time.Now())
+	running.StartStep(domain.DeploymentOperationStep, time.Now())
+	_ = operationRepository.Update(*running)
+	pending, _ := operationRepository.Create(domain.NewOperation(domain.ScaleOperation, application, "user-id"))
+	succeeded, _ := operationRepository.Create(domain.NewOperation(domain.DeleteOperation, application, "user-id"))
+	succeeded.Finish(nil, time.Now())
+	_ = operationRepository.Update(*succeeded)
+
+	if err := (FailInterruptedOperationsUseCase{OperationRepository: operationRepository}).Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if operation, _ := operationRepository.FindByID(running.ID); operation.Status != domain.OperationFailed || operation.FailedStep != domain.DeploymentOperationStep {
+		t.Errorf("expected the running operation to fail at its running step, got %s at step %s", operation.Status, operation.FailedStep)
+	}
+	if operation, _ := operationRepository.FindByID(pending.ID); operation.Status != domain.OperationFailed || operation.FinishedAt == nil {
+		t.Errorf("expected the pending operation to fail, got %s", operation.Status)
+	}
+	if operation, _ := operationRepository.FindByID(succeeded.ID); operation.Status != domain.OperationSucceeded {
+		t.Errorf("expected the succeeded operation to be kept, got %s", operation.Status)
+	}
+}
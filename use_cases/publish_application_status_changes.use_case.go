@@ -0,0 +1,67 @@
+package use_cases
+
+import (
+	"fmt"
+	"sync"
+
+	"cloud-app-hive/domain"
+	"cloud-app-hive/domain/commands"
+	"cloud-app-hive/domain/repositories"
+)
+
+type PublishApplicationStatusChangesUseCase struct {
+	ApplicationRepository      repositories.ApplicationRepository
+	ContainerManagerRepository repositories.ContainerManagerRepository
+	PublishWebhookEventUseCase *PublishWebhookEventUseCase
+
+	mutex sync.Mutex
+	// lastStatuses are the statuses observed during the previous execution, by application ID
+	lastStatuses map[string]domain.ApplicationDeploymentStatus
+}
+
+// Execute compares the status of every application with the one observed during the previous execution
+// and publishes an application.status_changed event for each application whose status changed.
+// Applications observed for the first time are only remembered, so that restarting the API does not publish every status again.
+// It returns the number of published events.
+func (publishApplicationStatusChangesUseCase *PublishApplicationStatusChangesUseCase) Execute() (int, error) {
+	applications, err := publishApplicationStatusChangesUseCase.ApplicationRepository.FindAll()
+	if err != nil {
+		return 0, err
+	}
+
+	publishApplicationStatusChangesUseCase.mutex.Lock()
+	defer publishApplicationStatusChangesUseCase.mutex.Unlock()
+
+	statuses := map[string]domain.ApplicationDeploymentStatus{}
+	published := 0
+	for _, application := range applications {
+		status, err := publishApplicationStatusChangesUseCase.ContainerManagerRepository.GetApplicationStatus(commands.GetApplicationStatus{
+			Name:      application.Name,
+			Namespace: application.Namespace.Name,
+		})
+		if err != nil {
+			fmt.Println(fmt.Errorf("error getting status of application %s: %w", application.ID, err))
+			// The previous status is kept so that a transient error does not publish a change afterwards
+			if previousStatus, ok := publishApplicationStatusChangesUseCase.lastStatuses[application.ID]; ok {
+				statuses[application.ID] = previousStatus
+			}
+			continue
+		}
+		if status == nil || status.ComputedApplicationStatus == nil {
+			continue
+		}
+		statuses[application.ID] = *status.ComputedApplicationStatus
+
+		previousStatus, ok := publishApplicationStatusChangesUseCase.lastStatuses[application.ID]
+		if !ok || previousStatus == *status.ComputedApplicationStatus {
+			continue
+		}
+		if publishApplicationStatusChangesUseCase.PublishWebhookEventUseCase != nil {
+			publishApplicationStatusChangesUseCase.PublishWebhookEventUseCase.Execute(domain.NewApplicationStatusChangedWebhookEvent(application, previousStatus, *status))
+		}
+		published++
+	}
+	// Deleted applications are forgotten
+	publishApplicationStatusChangesUseCase.lastStatuses = statuses
+	return published, nil
+}
@@ -0,0 +1,111 @@
+package use_cases
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"cloud-app-hive/domain"
+	"cloud-app-hive/domain/repositories"
+
+	"github.com/google/uuid"
+)
+
+const defaultWebhookRetryDelay = 10 * time.Second
+
+type PublishWebhookEventUseCase struct {
+	WebhookRepository         repositories.WebhookRepository
+	WebhookDeliveryRepository repositories.WebhookDeliveryRepository
+	WebhookSender             repositories.WebhookSender
+	// SecretsCipher decrypts the secrets signing the events
+	SecretsCipher repositories.SecretsCipher
+	// RetryDelay is the delay before the second attempt to deliver an event, it doubles after each attempt. 10 seconds when zero.
+	RetryDelay time.Duration
+}
+
+// Execute sends the event to the active webhooks of its namespace subscribed to it.
+// Events are delivered in the background and every attempt is recorded in the delivery log,
+// failed attempts are sent again by RetryWebhookDeliveriesUseCase when their next attempt is due, up to MaxWebhookDeliveryAttempts attempts.
+// Like the audit log, the event is about something that already happened, so failing to publish it is printed instead of being returned.
+func (publishWebhookEventUseCase PublishWebhookEventUseCase) Execute(event domain.WebhookEvent) {
+	webhooks, err := publishWebhookEventUseCase.WebhookRepository.FindByNamespaceID(event.NamespaceID)
+	if err != nil {
+		fmt.Println(fmt.Errorf("error finding webhooks of namespace %s to publish event %s: %w", event.NamespaceID, event.Type, err))
+		return
+	}
+
+	event.ID = uuid.New().String()
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = time.Now().UTC()
+	}
+	payload, err := json.Marshal(event)
+	if err != nil {
+		fmt.Println(fmt.Errorf("error encoding event %s: %w", event.Type, err))
+		return
+	}
+
+	for _, webhook := range webhooks {
+		if webhook.Subscribes(event.Type) {
+			go publishWebhookEventUseCase.deliver(webhook, event, payload)
+		}
+	}
+}
+
+// deliver makes the first attempt to send the event to the webhook
+func (publishWebhookEventUseCase PublishWebhookEventUseCase) deliver(webhook domain.Webhook, event domain.WebhookEvent, payload []byte) {
+	signingSecret, err := publishWebhookEventUseCase.signingSecret(webhook)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	publishWebhookEventUseCase.attempt(webhook, signingSecret, event, payload, 1)
+}
+
+// signingSecret returns the decrypted secret signing the events sent to the webhook
+func (publishWebhookEventUseCase PublishWebhookEventUseCase) signingSecret(webhook domain.Webhook) (string, error) {
+	secret, err := publishWebhookEventUseCase.SecretsCipher.Decrypt(webhook.SecretSecrets())
+	if err != nil {
+		return "", fmt.Errorf("error decrypting secret of webhook %s: %w", webhook.ID, err)
+	}
+	return webhook.WithSecretSecrets(secret).Secret, nil
+}
+
+// attempt sends the event once and records the attempt in the delivery log
+func (publishWebhookEventUseCase PublishWebhookEventUseCase) attempt(webhook domain.Webhook, secret string, event domain.WebhookEvent, payload []byte, attempt int) domain.WebhookDelivery {
+	now := time.Now().UTC()
+	statusCode, err := publishWebhookEventUseCase.WebhookSender.Send(webhook.URL, domain.WebhookDeliveryHeaders(secret, event, now, payload), payload)
+	delivery := domain.WebhookDelivery{
+		WebhookID:   webhook.ID,
+		NamespaceID: webhook.NamespaceID,
+		EventID:     event.ID,
+		EventType:   event.Type,
+		Payload:     string(payload),
+		Attempt:     attempt,
+		StatusCode:  statusCode,
+		Succeeded:   err == nil && statusCode >= 200 && statusCode < 300,
+		Duration:    time.Since(now).Milliseconds(),
+		CreatedAt:   now,
+	}
+	switch {
+	case err != nil:
+		delivery.Error = domain.TruncateText(err.Error(), domain.MaxRecordedErrorLength)
+	case !delivery.Succeeded:
+		delivery.Error = fmt.Sprintf("endpoint answered with status %d", statusCode)
+	}
+	if !delivery.Succeeded && attempt < domain.MaxWebhookDeliveryAttempts {
+		nextAttemptAt := now.Add(domain.WebhookRetryDelay(publishWebhookEventUseCase.retryDelay(), attempt))
+		delivery.NextAttemptAt = &nextAttemptAt
+	}
+
+	if _, err := publishWebhookEventUseCase.WebhookDeliveryRepository.Create(delivery); err != nil {
+		fmt.Println(fmt.Errorf("error recording delivery of event %s to webhook %s: %w", event.ID, webhook.ID, err))
+	}
+	return delivery
+}
+
+func (publishWebhookEventUseCase PublishWebhookEventUseCase) retryDelay() time.Duration {
+	if publishWebhookEventUseCase.RetryDelay == 0 {
+		return defaultWebhookRetryDelay
+	}
+	return publishWebhookEventUseCase.RetryDelay
+}
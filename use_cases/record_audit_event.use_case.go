@@ -0,0 +1,29 @@
+package use_cases
+
+import (
+	"fmt"
+
+	"cloud-app-hive/domain"
+	"cloud-app-hive/domain/repositories"
+)
+
+type RecordAuditEventUseCase struct {
+	AuditEventRepository repositories.AuditEventRepository
+	// PublishWebhookEventUseCase sends the actions webhooks subscribe to, no event is sent when it is nil
+	PublishWebhookEventUseCase *PublishWebhookEventUseCase
+}
+
+// Execute appends the outcome of an action to the audit log.
+// The action already happened when it is recorded, so failing to record it is printed instead of being returned.
+func (recordAuditEventUseCase RecordAuditEventUseCase) Execute(auditEvent domain.AuditEvent, err error) {
+	auditEvent = auditEvent.WithOutcome(err)
+	if recordErr := recordAuditEventUseCase.AuditEventRepository.Create(auditEvent); recordErr != nil {
+		fmt.Println(fmt.Errorf("error recording audit event %s on %s: %w", auditEvent.Action, auditEvent.TargetID, recordErr))
+	}
+	if recordAuditEventUseCase.PublishWebhookEventUseCase == nil {
+		return
+	}
+	if event, ok := domain.NewWebhookEventFromAuditEvent(auditEvent); ok {
+		recordAuditEventUseCase.PublishWebhookEventUseCase.Execute(event)
+	}
+}
@@ -0,0 +1,63 @@
+package use_cases
+
+import (
+	"fmt"
+
+	"cloud-app-hive/domain"
+	"cloud-app-hive/domain/commands"
+	"cloud-app-hive/domain/repositories"
+)
+
+type RefreshApplicationSecretsUseCase struct {
+	ApplicationRepository      repositories.ApplicationRepository
+	ContainerManagerRepository repositories.ContainerManagerRepository
+	RecordAuditEventUseCase    RecordAuditEventUseCase
+}
+
+// Execute updates the Kubernetes Secrets of the applications whose referenced secrets changed in their provider,
+// and restarts these applications if restart is true. An application failing to refresh does not stop the others.
+// It returns the names of the refreshed applications.
+func (refreshApplicationSecretsUseCase RefreshApplicationSecretsUseCase) Execute(restart bool) ([]string, error) {
+	applications, err := refreshApplicationSecretsUseCase.ApplicationRepository.FindWithSecrets()
+	if err != nil {
+		return nil, err
+	}
+
+	refreshedApplications := []string{}
+	for _, application := range applications {
+		if application.Secrets == nil || !application.Secrets.HasReferences() {
+			continue
+		}
+		refreshed, err := refreshApplicationSecretsUseCase.refresh(application, restart)
+		if err != nil {
+			fmt.Println(fmt.Errorf("error refreshing secrets of application %s: %w", application.ID, err))
+			continue
+		}
+		if refreshed {
+			refreshedApplications = append(refreshedApplications, application.Name)
+		}
+	}
+	return refreshedApplications, nil
+}
+
+// refresh updates the secrets of the application and records it in the audit log when they changed
+func (refreshApplicationSecretsUseCase RefreshApplicationSecretsUseCase) refresh(application domain.Application, restart bool) (refreshed bool, err error) {
+	auditEvent := domain.NewAuditEvent(domain.SchedulerAuditActor(), domain.AuditApplicationSecretRefresh, domain.AuditTargetApplication, application.ID)
+	auditEvent.NamespaceID = application.NamespaceID
+	auditEvent.TargetName = application.Name
+	defer func() {
+		if refreshed || err != nil {
+			refreshApplicationSecretsUseCase.RecordAuditEventUseCase.Execute(auditEvent, err)
+		}
+	}()
+
+	refreshed, err = refreshApplicationSecretsUseCase.ContainerManagerRepository.RefreshApplicationSecrets(commands.NewApplyApplication(application, application.Namespace.Name))
+	if err != nil || !refreshed || !restart {
+		return refreshed, err
+	}
+	err = refreshApplicationSecretsUseCase.ContainerManagerRepository.RestartApplication(commands.RestartApplication{
+		Name:      application.Name,
+		Namespace: application.Namespace.Name,
+	})
+	return refreshed, err
+}
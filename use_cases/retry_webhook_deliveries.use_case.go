@@ -0,0 +1,53 @@
+package use_cases
+
+import (
+	"fmt"
+	"time"
+
+	"cloud-app-hive/domain"
+	"cloud-app-hive/domain/repositories"
+)
+
+type RetryWebhookDeliveriesUseCase struct {
+	WebhookRepository          repositories.WebhookRepository
+	WebhookDeliveryRepository  repositories.WebhookDeliveryRepository
+	PublishWebhookEventUseCase *PublishWebhookEventUseCase
+}
+
+// Execute sends again the events of the failed attempts whose next attempt is due, and returns the number of attempts made.
+// Each failed attempt is claimed before it is sent again, so that it is retried once even when several schedulers run.
+func (retryWebhookDeliveriesUseCase RetryWebhookDeliveriesUseCase) Execute() (int, error) {
+	dueDeliveries, err := retryWebhookDeliveriesUseCase.WebhookDeliveryRepository.FindDue(time.Now().UTC())
+	if err != nil {
+		return 0, err
+	}
+
+	attempts := 0
+	for _, dueDelivery := range dueDeliveries {
+		claimed, err := retryWebhookDeliveriesUseCase.WebhookDeliveryRepository.ClearNextAttempt(dueDelivery.ID)
+		if err != nil {
+			return attempts, err
+		}
+		if !claimed {
+			continue
+		}
+		// The events of deleted or disabled webhooks are not sent anymore
+		webhook, err := retryWebhookDeliveriesUseCase.WebhookRepository.FindByID(dueDelivery.WebhookID)
+		if err != nil {
+			return attempts, err
+		}
+		if webhook == nil || !webhook.Active {
+			continue
+		}
+		signingSecret, err := retryWebhookDeliveriesUseCase.PublishWebhookEventUseCase.signingSecret(*webhook)
+		if err != nil {
+			fmt.Println(err)
+			continue
+		}
+
+		event := domain.WebhookEvent{ID: dueDelivery.EventID, Type: dueDelivery.EventType, NamespaceID: dueDelivery.NamespaceID}
+		retryWebhookDeliveriesUseCase.PublishWebhookEventUseCase.attempt(*webhook, signingSecret, event, []byte(dueDelivery.Payload), dueDelivery.Attempt+1)
+		attempts++
+	}
+	return attempts, nil
+}
@@ -0,0 +1,211 @@
+package use_cases
+
+import (
+	"fmt"
+
+	"cloud-app-hive/domain"
+	"cloud-app-hive/domain/repositories"
+)
+
+type RotateSecretKeysUseCase struct {
+	ApplicationRepository        repositories.ApplicationRepository
+	RegistryCredentialRepository repositories.RegistryCredentialRepository
+	WebhookRepository            repositories.WebhookRepository
+	AddonRepository              repositories.AddonRepository
+	ConfigGroupRepository        repositories.ConfigGroupRepository
+	SecretsCipher                repositories.SecretsCipher
+}
+
+// Execute gives every namespace with secrets a new data key wrapped by the current master key and re-encrypts every secret with it.
+// Secrets stored before encryption at rest are encrypted too, as well as the contents of the secret config files, the passwords of the registry credentials, the secrets of the webhooks, the credentials of the add-ons and the secrets of the config groups. Once it succeeds, the previous master keys can be removed, unless the API wrote a secret with a previous data key during the rotation: that data key is kept and the rotation must run again.
+// It returns the number of re-encrypted applications.
+func (rotateSecretKeysUseCase RotateSecretKeysUseCase) Execute() (int, error) {
+	applications, err := rotateSecretKeysUseCase.ApplicationRepository.FindWithSecrets()
+	if err != nil {
+		return 0, err
+	}
+
+	registryCredentials, err := rotateSecretKeysUseCase.RegistryCredentialRepository.FindAll()
+	if err != nil {
+		return 0, err
+	}
+
+	webhooks, err := rotateSecretKeysUseCase.WebhookRepository.FindAll()
+	if err != nil {
+		return 0, err
+	}
+
+	addons, err := rotateSecretKeysUseCase.AddonRepository.FindAll()
+	if err != nil {
+		return 0, err
+	}
+
+	configGroups, err := rotateSecretKeysUseCase.ConfigGroupRepository.FindAll()
+	if err != nil {
+		return 0, err
+	}
+
+	namespaceIDs := []string{}
+	for _, application := range applications {
+		namespaceIDs = append(namespaceIDs, application.NamespaceID)
+	}
+	for _, registryCredential := range registryCredentials {
+		namespaceIDs = append(namespaceIDs, registryCredential.NamespaceID)
+	}
+	for _, webhook := range webhooks {
+		namespaceIDs = append(namespaceIDs, webhook.NamespaceID)
+	}
+	for _, addon := range addons {
+		if addon.Credentials != nil {
+			namespaceIDs = append(namespaceIDs, addon.NamespaceID)
+		}
+	}
+	for _, configGroup := range configGroups {
+		if configGroup.Secrets != nil {
+			namespaceIDs = append(namespaceIDs, configGroup.NamespaceID)
+		}
+	}
+	rotatedNamespaces := map[string]bool{}
+	for _, namespaceID := range namespaceIDs {
+		if rotatedNamespaces[namespaceID] {
+			continue
+		}
+		if err := rotateSecretKeysUseCase.SecretsCipher.RotateDataKey(namespaceID); err != nil {
+			return 0, fmt.Errorf("error rotating data key of namespace %s: %w", namespaceID, err)
+		}
+		rotatedNamespaces[namespaceID] = true
+	}
+
+	for _, application := range applications {
+		if application.Secrets != nil {
+			secrets, err := rotateSecretKeysUseCase.SecretsCipher.Decrypt(*application.Secrets)
+			if err != nil {
+				return 0, fmt.Errorf("error decrypting secrets of application %s: %w", application.ID, err)
+			}
+			if err := rotateSecretKeysUseCase.ApplicationRepository.UpdateSecrets(application.ID, application.NamespaceID, secrets); err != nil {
+				return 0, err
+			}
+		}
+		if application.ConfigFiles != nil {
+			contents, err := rotateSecretKeysUseCase.SecretsCipher.Decrypt(application.ConfigFiles.SecretContents())
+			if err != nil {
+				return 0, fmt.Errorf("error decrypting config files of application %s: %w", application.ID, err)
+			}
+			if err := rotateSecretKeysUseCase.ApplicationRepository.UpdateConfigFiles(application.ID, application.NamespaceID, application.ConfigFiles.WithSecretContents(contents)); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	for _, registryCredential := range registryCredentials {
+		password, err := rotateSecretKeysUseCase.SecretsCipher.Decrypt(registryCredential.PasswordSecrets())
+		if err != nil {
+			return 0, fmt.Errorf("error decrypting password of registry credential %s: %w", registryCredential.ID, err)
+		}
+		if _, err := rotateSecretKeysUseCase.RegistryCredentialRepository.Update(registryCredential.WithPasswordSecrets(password)); err != nil {
+			return 0, err
+		}
+	}
+
+	for _, webhook := range webhooks {
+		secret, err := rotateSecretKeysUseCase.SecretsCipher.Decrypt(webhook.SecretSecrets())
+		if err != nil {
+			return 0, fmt.Errorf("error decrypting secret of webhook %s: %w", webhook.ID, err)
+		}
+		if _, err := rotateSecretKeysUseCase.WebhookRepository.Update(webhook.WithSecretSecrets(secret)); err != nil {
+			return 0, err
+		}
+	}
+
+	for _, addon := range addons {
+		if addon.Credentials == nil {
+			continue
+		}
+		credentials, err := rotateSecretKeysUseCase.SecretsCipher.Decrypt(*addon.Credentials)
+		if err != nil {
+			return 0, fmt.Errorf("error decrypting credentials of addon %s: %w", addon.ID, err)
+		}
+		if err := rotateSecretKeysUseCase.AddonRepository.UpdateCredentials(addon.ID, addon.NamespaceID, credentials); err != nil {
+			return 0, err
+		}
+	}
+
+	for _, configGroup := range configGroups {
+		if configGroup.Secrets == nil {
+			continue
+		}
+		secrets, err := rotateSecretKeysUseCase.SecretsCipher.Decrypt(*configGroup.Secrets)
+		if err != nil {
+			return 0, fmt.Errorf("error decrypting secrets of config group %s: %w", configGroup.ID, err)
+		}
+		configGroup.Secrets = &secrets
+		if _, err := rotateSecretKeysUseCase.ConfigGroupRepository.Update(configGroup); err != nil {
+			return 0, err
+		}
+	}
+
+	// The API keeps serving during the rotation, a secret it wrote with a previous data key keeps that key until the next rotation
+	storedSecrets, err := rotateSecretKeysUseCase.findStoredSecrets()
+	if err != nil {
+		return 0, err
+	}
+	if err := rotateSecretKeysUseCase.SecretsCipher.PruneDataKeys(storedSecrets); err != nil {
+		return 0, fmt.Errorf("error pruning data keys: %w", err)
+	}
+	return len(applications), nil
+}
+
+// findStoredSecrets reads again every encrypted value stored in the database
+func (rotateSecretKeysUseCase RotateSecretKeysUseCase) findStoredSecrets() (domain.ApplicationSecrets, error) {
+	storedSecrets := domain.ApplicationSecrets{}
+
+	applications, err := rotateSecretKeysUseCase.ApplicationRepository.FindWithSecrets()
+	if err != nil {
+		return nil, err
+	}
+	for _, application := range applications {
+		if application.Secrets != nil {
+			storedSecrets = append(storedSecrets, *application.Secrets...)
+		}
+		if application.ConfigFiles != nil {
+			storedSecrets = append(storedSecrets, application.ConfigFiles.SecretContents()...)
+		}
+	}
+
+	registryCredentials, err := rotateSecretKeysUseCase.RegistryCredentialRepository.FindAll()
+	if err != nil {
+		return nil, err
+	}
+	for _, registryCredential := range registryCredentials {
+		storedSecrets = append(storedSecrets, registryCredential.PasswordSecrets()...)
+	}
+
+	webhooks, err := rotateSecretKeysUseCase.WebhookRepository.FindAll()
+	if err != nil {
+		return nil, err
+	}
+	for _, webhook := range webhooks {
+		storedSecrets = append(storedSecrets, webhook.SecretSecrets()...)
+	}
+
+	addons, err := rotateSecretKeysUseCase.AddonRepository.FindAll()
+	if err != nil {
+		return nil, err
+	}
+	for _, addon := range addons {
+		if addon.Credentials != nil {
+			storedSecrets = append(storedSecrets, *addon.Credentials...)
+		}
+	}
+
+	configGroups, err := rotateSecretKeysUseCase.ConfigGroupRepository.FindAll()
+	if err != nil {
+		return nil, err
+	}
+	for _, configGroup := range configGroups {
+		if configGroup.Secrets != nil {
+			storedSecrets = append(storedSecrets, *configGroup.Secrets...)
+		}
+	}
+	return storedSecrets, nil
+}
@@ -0,0 +1,358 @@
+package use_cases
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"cloud-app-hive/domain"
+	"cloud-app-hive/domain/repositories"
+	"cloud-app-hive/services"
+)
+
+// memoryNamespaceDataKeyRepository keeps the data keys in memory, the most recent last
+type memoryNamespaceDataKeyRepository struct {
+	dataKeys []domain.NamespaceDataKey
+}
+
+func (m *memoryNamespaceDataKeyRepository) Create(namespaceDataKey domain.NamespaceDataKey) (*domain.NamespaceDataKey, error) {
+	namespaceDataKey.ID = fmt.Sprintf("data-key-%d", len(m.dataKeys)+1)
+	namespaceDataKey.CreatedAt = time.Now()
+	m.dataKeys = append(m.dataKeys, namespaceDataKey)
+	return &namespaceDataKey, nil
+}
+
+func (m *memoryNamespaceDataKeyRepository) FindByID(id string) (*domain.NamespaceDataKey, error) {
+	for _, dataKey := range m.dataKeys {
+		if dataKey.ID == id {
+			return &dataKey, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *memoryNamespaceDataKeyRepository) FindLatestByNamespaceID(namespaceID string) (*domain.NamespaceDataKey, error) {
+	for i := len(m.dataKeys) - 1; i >= 0; i-- {
+		if m.dataKeys[i].NamespaceID == namespaceID {
+			return &m.dataKeys[i], nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *memoryNamespaceDataKeyRepository) FindAll() ([]domain.NamespaceDataKey, error) {
+	dataKeys := []domain.NamespaceDataKey{}
+	for i := len(m.dataKeys) - 1; i >= 0; i-- {
+		dataKeys = append(dataKeys, m.dataKeys[i])
+	}
+	return dataKeys, nil
+}
+
+func (m *memoryNamespaceDataKeyRepository) Delete(id string) error {
+	for i, dataKey := range m.dataKeys {
+		if dataKey.ID == id {
+			m.dataKeys = append(m.dataKeys[:i], m.dataKeys[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+// memoryApplicationRepository only implements the methods used by the rotation, and encrypts what it stores like the GORM repository
+type memoryApplicationRepository struct {
+	repositories.ApplicationRepository
+	secretsCipher repositories.SecretsCipher
+	applications  []domain.Application
+}
+
+func (m *memoryApplicationRepository) FindWithSecrets() ([]domain.Application, error) {
+	return m.applications, nil
+}
+
+func (m *memoryApplicationRepository) UpdateSecrets(applicationID string, namespaceID string, secrets domain.ApplicationSecrets) error {
+	encryptedSecrets, err := m.secretsCipher.Encrypt(namespaceID, secrets)
+	if err != nil {
+		return err
+	}
+	for index, application := range m.applications {
+		if application.ID == applicationID {
+			m.applications[index].Secrets = &encryptedSecrets
+		}
+	}
+	return nil
+}
+
+func (m *memoryApplicationRepository) UpdateConfigFiles(applicationID string, namespaceID string, configFiles domain.ApplicationConfigFiles) error {
+	encryptedContents, err := m.secretsCipher.Encrypt(namespaceID, configFiles.SecretContents())
+	if err != nil {
+		return err
+	}
+	encryptedConfigFiles := configFiles.WithSecretContents(encryptedContents)
+	for index, application := range m.applications {
+		if application.ID == applicationID {
+			m.applications[index].ConfigFiles = &encryptedConfigFiles
+		}
+	}
+	return nil
+}
+
+// memoryRegistryCredentialRepository only implements the methods used by the rotation
+type memoryRegistryCredentialRepository struct {
+	repositories.RegistryCredentialRepository
+}
+
+func (m *memoryRegistryCredentialRepository) FindAll() ([]domain.RegistryCredential, error) {
+	return nil, nil
+}
+
+// memoryWebhookRepository only implements the methods used by the rotation
+type memoryWebhookRepository struct {
+	repositories.WebhookRepository
+}
+
+func (m *memoryWebhookRepository) FindAll() ([]domain.Webhook, error) {
+	return nil, nil
+}
+
+// memoryAddonRepository encrypts the credentials it stores, like the GORM repository
+type memoryAddonRepository struct {
+	repositories.AddonRepository
+	secretsCipher repositories.SecretsCipher
+	addons        []domain.Addon
+}
+
+func (m *memoryAddonRepository) FindAll() ([]domain.Addon, error) {
+	return m.addons, nil
+}
+
+func (m *memoryAddonRepository) UpdateCredentials(addonID string, namespaceID string, credentials domain.ApplicationSecrets) error {
+	encryptedCredentials, err := m.secretsCipher.Encrypt(namespaceID, credentials)
+	if err != nil {
+		return err
+	}
+	for index, addon := range m.addons {
+		if addon.ID == addonID {
+			m.addons[index].Credentials = &encryptedCredentials
+		}
+	}
+	return nil
+}
+
+// memoryConfigGroupRepository encrypts the secrets it stores, like the GORM repository
+type memoryConfigGroupRepository struct {
+	repositories.ConfigGroupRepository
+	secretsCipher repositories.SecretsCipher
+	configGroups  []domain.ConfigGroup
+}
+
+func (m *memoryConfigGroupRepository) FindAll() ([]domain.ConfigGroup, error) {
+	return m.configGroups, nil
+}
+
+func (m *memoryConfigGroupRepository) Update(configGroup domain.ConfigGroup) (*domain.ConfigGroup, error) {
+	encryptedSecrets, err := m.secretsCipher.Encrypt(configGroup.NamespaceID, *configGroup.Secrets)
+	if err != nil {
+		return nil, err
+	}
+	configGroup.Secrets = &encryptedSecrets
+	for index := range m.configGroups {
+		if m.configGroups[index].ID == configGroup.ID {
+			m.configGroups[index] = configGroup
+		}
+	}
+	return &configGroup, nil
+}
+
+func newTestSecretEncryptionService(t *testing.T) services.SecretEncryptionService {
+	t.Helper()
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "master-keys")
+	if err := os.WriteFile(path, []byte("2024-01="+base64.StdEncoding.EncodeToString(key)), 0600); err != nil {
+		t.Fatal(err)
+	}
+	masterKeyProvider, err := services.NewFileMasterKeyProvider(path, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return services.SecretEncryptionService{MasterKeyProvider: masterKeyProvider, NamespaceDataKeyRepository: &memoryNamespaceDataKeyRepository{}}
+}
+
+func encryptTestSecrets(t *testing.T, secretsCipher repositories.SecretsCipher, namespaceID string, secrets domain.ApplicationSecrets) *domain.ApplicationSecrets {
+	t.Helper()
+	encryptedSecrets, err := secretsCipher.Encrypt(namespaceID, secrets)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &encryptedSecrets
+}
+
+func assertDecryptsTo(t *testing.T, secretsCipher repositories.SecretsCipher, secrets domain.ApplicationSecrets, name string, value string) {
+	t.Helper()
+	decryptedSecrets, err := secretsCipher.Decrypt(secrets)
+	if err != nil {
+		t.Fatalf("expected %s to be decrypted after the rotation, got %v", name, err)
+	}
+	secret, found := decryptedSecrets.Find(name)
+	if !found || secret.Val != value {
+		t.Errorf("expected %s to be %q after the rotation, got %+v", name, value, decryptedSecrets)
+	}
+}
+
+func TestRotateSecretKeysUseCase_ReEncryptsAddonCredentials(t *testing.T) {
+	secretEncryptionService := newTestSecretEncryptionService(t)
+	addonRepository := &memoryAddonRepository{
+		secretsCipher: secretEncryptionService,
+		addons: []domain.Addon{{
+			ID:          "addon-id",
+			NamespaceID: "addons-only-namespace-id",
+			Credentials: encryptTestSecrets(t, secretEncryptionService, "addons-only-namespace-id", domain.ApplicationSecrets{{Name: "PASSWORD", Val: "p4ssw0rd"}}),
+		}},
+	}
+	rotateSecretKeysUseCase := RotateSecretKeysUseCase{
+		ApplicationRepository:        &memoryApplicationRepository{},
+		RegistryCredentialRepository: &memoryRegistryCredentialRepository{},
+		WebhookRepository:            &memoryWebhookRepository{},
+		AddonRepository:              addonRepository,
+		ConfigGroupRepository:        &memoryConfigGroupRepository{},
+		SecretsCipher:                secretEncryptionService,
+	}
+
+	if _, err := rotateSecretKeysUseCase.Execute(); err != nil {
+		t.Fatal(err)
+	}
+
+	assertDecryptsTo(t, secretEncryptionService, *addonRepository.addons[0].Credentials, "PASSWORD", "p4ssw0rd")
+	dataKeys, _ := secretEncryptionService.NamespaceDataKeyRepository.FindAll()
+	if len(dataKeys) != 1 || dataKeys[0].ID != "data-key-2" {
+		t.Errorf("expected only the rotated data key to be kept, got %+v", dataKeys)
+	}
+}
+
+func TestRotateSecretKeysUseCase_ReEncryptsConfigGroupSecrets(t *testing.T) {
+	secretEncryptionService := newTestSecretEncryptionService(t)
+	configGroupRepository := &memoryConfigGroupRepository{
+		secretsCipher: secretEncryptionService,
+		configGroups: []domain.ConfigGroup{{
+			ID:          "config-group-id",
+			NamespaceID: "config-groups-only-namespace-id",
+			Secrets:     encryptTestSecrets(t, secretEncryptionService, "config-groups-only-namespace-id", domain.ApplicationSecrets{{Name: "API_KEY", Val: "s3cr3t"}}),
+		}},
+	}
+	rotateSecretKeysUseCase := RotateSecretKeysUseCase{
+		ApplicationRepository:        &memoryApplicationRepository{},
+		RegistryCredentialRepository: &memoryRegistryCredentialRepository{},
+		WebhookRepository:            &memoryWebhookRepository{},
+		AddonRepository:              &memoryAddonRepository{},
+		ConfigGroupRepository:        configGroupRepository,
+		SecretsCipher:                secretEncryptionService,
+	}
+
+	if _, err := rotateSecretKeysUseCase.Execute(); err != nil {
+		t.Fatal(err)
+	}
+
+	assertDecryptsTo(t, secretEncryptionService, *configGroupRepository.configGroups[0].Secrets, "API_KEY", "s3cr3t")
+	dataKeys, _ := secretEncryptionService.NamespaceDataKeyRepository.FindAll()
+	if len(dataKeys) != 1 || dataKeys[0].ID != "data-key-2" {
+		t.Errorf("expected only the rotated data key to be kept, got %+v", dataKeys)
+	}
+}
+
+func TestRotateSecretKeysUseCase_ReEncryptsSecretConfigFiles(t *testing.T) {
+	secretEncryptionService := newTestSecretEncryptionService(t)
+	encryptedContents := encryptTestSecrets(t, secretEncryptionService, "config-files-only-namespace-id", domain.ApplicationSecrets{{Name: "credentials.json", Val: "{}"}})
+	configFiles := domain.ApplicationConfigFiles{
+		{Name: "credentials.json", MountPath: "/etc/app/credentials.json", Content: "{}", Secret: true},
+		{Name: "app.conf", MountPath: "/etc/app/app.conf", Content: "debug=false"},
+	}.WithSecretContents(*encryptedContents)
+	applicationRepository := &memoryApplicationRepository{
+		secretsCipher: secretEncryptionService,
+		applications: []domain.Application{{
+			ID:          "application-id",
+			NamespaceID: "config-files-only-namespace-id",
+			ConfigFiles: &configFiles,
+		}},
+	}
+	rotateSecretKeysUseCase := RotateSecretKeysUseCase{
+		ApplicationRepository:        applicationRepository,
+		RegistryCredentialRepository: &memoryRegistryCredentialRepository{},
+		WebhookRepository:            &memoryWebhookRepository{},
+		AddonRepository:              &memoryAddonRepository{},
+		ConfigGroupRepository:        &memoryConfigGroupRepository{},
+		SecretsCipher:                secretEncryptionService,
+	}
+
+	if _, err := rotateSecretKeysUseCase.Execute(); err != nil {
+		t.Fatal(err)
+	}
+
+	rotatedConfigFiles := *applicationRepository.applications[0].ConfigFiles
+	assertDecryptsTo(t, secretEncryptionService, rotatedConfigFiles.SecretContents(), "credentials.json", "{}")
+	if configFile, _ := rotatedConfigFiles.Find("app.conf"); configFile.Content != "debug=false" {
+		t.Errorf("expected the content of a non secret config file to be kept, got %q", configFile.Content)
+	}
+	dataKeys, _ := secretEncryptionService.NamespaceDataKeyRepository.FindAll()
+	if len(dataKeys) != 1 || dataKeys[0].ID != "data-key-2" {
+		t.Errorf("expected only the rotated data key to be kept, got %+v", dataKeys)
+	}
+}
+
+// writingAddonRepository stores secrets of an application while the add-on credentials are re-encrypted, like the API serving during the rotation
+type writingAddonRepository struct {
+	*memoryAddonRepository
+	onUpdateCredentials func()
+}
+
+func (m writingAddonRepository) UpdateCredentials(addonID string, namespaceID string, credentials domain.ApplicationSecrets) error {
+	m.onUpdateCredentials()
+	return m.memoryAddonRepository.UpdateCredentials(addonID, namespaceID, credentials)
+}
+
+func TestRotateSecretKeysUseCase_KeepsDataKeysOfSecretsWrittenDuringTheRotation(t *testing.T) {
+	secretEncryptionService := newTestSecretEncryptionService(t)
+	// Encrypted with the data key of the namespace before the rotation, by a request still in flight
+	staleSecrets := encryptTestSecrets(t, secretEncryptionService, "namespace-id", domain.ApplicationSecrets{{Name: "TOKEN", Val: "t0k3n"}})
+	applicationRepository := &memoryApplicationRepository{
+		secretsCipher: secretEncryptionService,
+		applications:  []domain.Application{{ID: "application-id", NamespaceID: "namespace-id"}},
+	}
+	addonRepository := writingAddonRepository{
+		memoryAddonRepository: &memoryAddonRepository{
+			secretsCipher: secretEncryptionService,
+			addons: []domain.Addon{{
+				ID:          "addon-id",
+				NamespaceID: "namespace-id",
+				Credentials: encryptTestSecrets(t, secretEncryptionService, "namespace-id", domain.ApplicationSecrets{{Name: "PASSWORD", Val: "p4ssw0rd"}}),
+			}},
+		},
+		onUpdateCredentials: func() {
+			applicationRepository.applications[0].Secrets = staleSecrets
+		},
+	}
+	rotateSecretKeysUseCase := RotateSecretKeysUseCase{
+		ApplicationRepository:        applicationRepository,
+		RegistryCredentialRepository: &memoryRegistryCredentialRepository{},
+		WebhookRepository:            &memoryWebhookRepository{},
+		AddonRepository:              addonRepository,
+		ConfigGroupRepository:        &memoryConfigGroupRepository{},
+		SecretsCipher:                secretEncryptionService,
+	}
+
+	if _, err := rotateSecretKeysUseCase.Execute(); err != nil {
+		t.Fatal(err)
+	}
+
+	assertDecryptsTo(t, secretEncryptionService, *applicationRepository.applications[0].Secrets, "TOKEN", "t0k3n")
+	assertDecryptsTo(t, secretEncryptionService, *addonRepository.addons[0].Credentials, "PASSWORD", "p4ssw0rd")
+	dataKeys, _ := secretEncryptionService.NamespaceDataKeyRepository.FindAll()
+	if len(dataKeys) != 2 {
+		t.Errorf("expected the data key of the secret written during the rotation to be kept, got %+v", dataKeys)
+	}
+}
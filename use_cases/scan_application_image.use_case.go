@@ -0,0 +1,129 @@
+package use_cases
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	customErrors "cloud-app-hive/controllers/errors"
+	"cloud-app-hive/domain"
+	"cloud-app-hive/domain/repositories"
+)
+
+const defaultImageScanMaxAge = 24 * time.Hour
+
+// ImageScanMaxAgeFromEnvironment returns the hours of IMAGE_SCAN_MAX_AGE_IN_HOURS, zero to use the default when it is not a positive number
+func ImageScanMaxAgeFromEnvironment() time.Duration {
+	maxScanAgeInHours, err := strconv.Atoi(os.Getenv("IMAGE_SCAN_MAX_AGE_IN_HOURS"))
+	if err != nil || maxScanAgeInHours <= 0 {
+		return 0
+	}
+	return time.Duration(maxScanAgeInHours) * time.Hour
+}
+
+type ScanApplicationImageUseCase struct {
+	// ImageScanner scans the images, images are not scanned when it is nil
+	ImageScanner        repositories.ImageScanner
+	ImageScanRepository repositories.ImageScanRepository
+	// SecretsCipher decrypts the passwords of the registry credentials
+	SecretsCipher repositories.SecretsCipher
+	// MaxScanAge is how long the scan of a digest is trusted before the digest is scanned again, 24 hours when zero
+	MaxScanAge time.Duration
+}
+
+// Execute scans the image of an application about to be deployed in the namespace, unless its digest was scanned recently,
+// and rejects it if the namespace blocks images with vulnerabilities more severe than the image has.
+// It returns nil when the image is not scanned, because scanning is disabled or the namespace policy is off.
+func (scanApplicationImageUseCase ScanApplicationImageUseCase) Execute(namespace domain.Namespace, registry domain.ImageRegistry, image string, imageDigest string, registryCredential *domain.RegistryCredential) (*domain.ImageScan, error) {
+	return scanApplicationImageUseCase.check(namespace, registry, image, imageDigest, registryCredential, true)
+}
+
+// DryRun checks the image against the namespace policy like Execute, but only with the stored scan of its digest:
+// it neither calls the scanner nor stores a scan, so an image without a recent scan is only checked when it is deployed.
+func (scanApplicationImageUseCase ScanApplicationImageUseCase) DryRun(namespace domain.Namespace, image string, imageDigest string) (*domain.ImageScan, error) {
+	return scanApplicationImageUseCase.check(namespace, "", image, imageDigest, nil, false)
+}
+
+// check checks the image against the namespace policy, scanning it when its digest has no recent scan and scanning is allowed
+func (scanApplicationImageUseCase ScanApplicationImageUseCase) check(namespace domain.Namespace, registry domain.ImageRegistry, image string, imageDigest string, registryCredential *domain.RegistryCredential, allowScan bool) (*domain.ImageScan, error) {
+	if namespace.ImageScanPolicy != domain.ImageScanPolicyWarn && namespace.ImageScanPolicy != domain.ImageScanPolicyBlock {
+		return nil, nil
+	}
+	blocking := namespace.ImageScanPolicy == domain.ImageScanPolicyBlock
+	if scanApplicationImageUseCase.ImageScanner == nil {
+		if blocking {
+			return nil, customErrors.NewImageScanUnavailableError(image, "image scanning is disabled")
+		}
+		return nil, nil
+	}
+	// Scans are stored per digest, which is only known when the image is verified
+	if imageDigest == "" {
+		if blocking {
+			return nil, customErrors.NewImageScanUnavailableError(image, "the digest of the image is unknown, image verification is disabled")
+		}
+		return nil, nil
+	}
+
+	imageScan, err := scanApplicationImageUseCase.ImageScanRepository.FindByImageDigest(imageDigest)
+	if err != nil {
+		return nil, err
+	}
+	maxScanAge := scanApplicationImageUseCase.MaxScanAge
+	if maxScanAge == 0 {
+		maxScanAge = defaultImageScanMaxAge
+	}
+	// Vulnerabilities are found in images after they are scanned, outdated scans are done again
+	if imageScan == nil || imageScan.IsOutdated(maxScanAge, time.Now()) {
+		if !allowScan {
+			return nil, nil
+		}
+		imageScan, err = scanApplicationImageUseCase.scan(registry, image, imageDigest, registryCredential, imageScan)
+		if err != nil {
+			if blocking {
+				return nil, customErrors.NewImageScanUnavailableError(image, err.Error())
+			}
+			fmt.Println(fmt.Errorf("error scanning image %s, deploying it anyway as namespace %s only warns about vulnerabilities: %w", image, namespace.Name, err))
+			return nil, nil
+		}
+	}
+
+	violations := namespace.ImageScanViolations(*imageScan)
+	if blocking && len(violations) > 0 {
+		return imageScan, customErrors.NewVulnerableImageError(image, imageDigest, string(namespace.MaxImageVulnerabilitySeverity), domain.VulnerabilityIDs(violations), len(violations))
+	}
+	return imageScan, nil
+}
+
+// scan scans the image by its digest and stores its vulnerabilities, in place of the outdated scan when there is one
+func (scanApplicationImageUseCase ScanApplicationImageUseCase) scan(registry domain.ImageRegistry, image string, imageDigest string, registryCredential *domain.RegistryCredential, outdatedImageScan *domain.ImageScan) (*domain.ImageScan, error) {
+	endpoint, repositoryImage, err := imageRegistryEndpoint(scanApplicationImageUseCase.SecretsCipher, registry, image, registryCredential)
+	if err != nil {
+		return nil, err
+	}
+	imageReference, err := domain.ParseImageReference(repositoryImage)
+	if err != nil {
+		return nil, err
+	}
+	imageReference.Digest = imageDigest
+
+	vulnerabilities, err := scanApplicationImageUseCase.ImageScanner.ScanImage(endpoint, imageReference)
+	if err != nil {
+		return nil, err
+	}
+	if outdatedImageScan != nil {
+		imageScan := *outdatedImageScan
+		imageScan.Vulnerabilities = &vulnerabilities
+		imageScan.ScannedAt = time.Now()
+		if err := scanApplicationImageUseCase.ImageScanRepository.Update(imageScan); err != nil {
+			return nil, err
+		}
+		return &imageScan, nil
+	}
+	return scanApplicationImageUseCase.ImageScanRepository.Create(domain.ImageScan{
+		ImageDigest:     imageDigest,
+		Image:           image,
+		Vulnerabilities: &vulnerabilities,
+		ScannedAt:       time.Now(),
+	})
+}
@@ -0,0 +1,132 @@
+package use_cases
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	customErrors "cloud-app-hive/controllers/errors"
+	"cloud-app-hive/domain"
+	"cloud-app-hive/domain/repositories"
+)
+
+// defaultDockerHubRegistryURL is the registry serving the Docker Hub images when DOCKERHUB_REGISTRY_URL is not set
+const defaultDockerHubRegistryURL = "https://registry-1.docker.io"
+
+// ImageVerificationEnabledFromEnvironment returns false when IMAGE_VERIFICATION is "none",
+// for clusters that cannot reach the registries of their images
+func ImageVerificationEnabledFromEnvironment() bool {
+	return os.Getenv("IMAGE_VERIFICATION") != "none"
+}
+
+// ClusterArchitecturesFromEnvironment returns the comma separated architectures of the cluster nodes of CLUSTER_NODE_ARCHITECTURES, amd64 by default
+func ClusterArchitecturesFromEnvironment() []string {
+	architectures := []string{}
+	for _, architecture := range strings.Split(os.Getenv("CLUSTER_NODE_ARCHITECTURES"), ",") {
+		if architecture = strings.TrimSpace(architecture); architecture != "" {
+			architectures = append(architectures, architecture)
+		}
+	}
+	if len(architectures) == 0 {
+		return []string{"amd64"}
+	}
+	return architectures
+}
+
+type VerifyApplicationImageUseCase struct {
+	// ImageRegistry reads the manifests of the images, images are not verified when it is nil
+	ImageRegistry repositories.ImageRegistry
+	// SecretsCipher decrypts the passwords of the registry credentials
+	SecretsCipher repositories.SecretsCipher
+	// Architectures are the architectures of the cluster nodes, images must run on one of them
+	Architectures []string
+}
+
+// Execute checks that the registry has the image and that it runs on the cluster nodes before it is deployed, and returns its digest.
+// The digest is empty when image verification is disabled, in which case it cannot be pinned.
+func (verifyApplicationImageUseCase VerifyApplicationImageUseCase) Execute(registry domain.ImageRegistry, image string, registryCredential *domain.RegistryCredential, pinImageDigest bool) (string, error) {
+	if verifyApplicationImageUseCase.ImageRegistry == nil {
+		if pinImageDigest {
+			return "", customErrors.NewImageVerificationUnavailableError(image, "image verification is disabled, so the digest to pin is unknown")
+		}
+		return "", nil
+	}
+
+	endpoint, repositoryImage, err := imageRegistryEndpoint(verifyApplicationImageUseCase.SecretsCipher, registry, image, registryCredential)
+	if err != nil {
+		return "", customErrors.NewImageVerificationUnavailableError(image, err.Error())
+	}
+	imageReference, err := domain.ParseImageReference(repositoryImage)
+	if err != nil {
+		return "", customErrors.NewImageNotFoundError(image, err.Error())
+	}
+
+	imageManifest, err := verifyApplicationImageUseCase.ImageRegistry.FindImageManifest(endpoint, imageReference)
+	if err != nil {
+		return "", customErrors.NewImageVerificationUnavailableError(image, err.Error())
+	}
+	if imageManifest == nil {
+		return "", customErrors.NewImageNotFoundError(image, fmt.Sprintf("the registry has no %s for %s", imageReference.ManifestReference(), imageReference.Repository))
+	}
+	if !imageManifest.SupportsOneOf(verifyApplicationImageUseCase.Architectures) {
+		return "", customErrors.NewIncompatibleImageArchitectureError(image, imageManifest.Architectures(), verifyApplicationImageUseCase.Architectures)
+	}
+	return imageManifest.Digest, nil
+}
+
+// imageRegistryEndpoint returns the registry of the image with the account pulling it, and the image relative to this registry
+func imageRegistryEndpoint(secretsCipher repositories.SecretsCipher, registry domain.ImageRegistry, image string, registryCredential *domain.RegistryCredential) (domain.ImageRegistryEndpoint, string, error) {
+	switch registry {
+	case domain.PrivateRegistry:
+		registryURL, repositoryPrefix := splitRegistryURL(os.Getenv("PRIVATE_HARBOR_REGISTRY_URL"))
+		return domain.ImageRegistryEndpoint{
+			URL:      registryURL,
+			Username: os.Getenv("PRIVATE_HARBOR_REGISTRY_USERNAME"),
+			Password: os.Getenv("PRIVATE_HARBOR_REGISTRY_PASSWORD"),
+		}, joinRepository(repositoryPrefix, image), nil
+	case domain.CustomRegistry:
+		if registryCredential == nil {
+			return domain.ImageRegistryEndpoint{}, "", fmt.Errorf("registry %s requires a registry credential", domain.CustomRegistry)
+		}
+		password, err := secretsCipher.Decrypt(registryCredential.PasswordSecrets())
+		if err != nil {
+			return domain.ImageRegistryEndpoint{}, "", fmt.Errorf("error decrypting registry credential %s: %w", registryCredential.Name, err)
+		}
+		registryURL, repositoryPrefix := splitRegistryURL(registryCredential.Server)
+		return domain.ImageRegistryEndpoint{
+			URL:      registryURL,
+			Username: registryCredential.Username,
+			Password: registryCredential.WithPasswordSecrets(password).Password,
+		}, joinRepository(repositoryPrefix, strings.TrimPrefix(image, registryCredential.Server+"/")), nil
+	default:
+		registryURL := os.Getenv("DOCKERHUB_REGISTRY_URL")
+		if registryURL == "" {
+			registryURL = defaultDockerHubRegistryURL
+		}
+		repositoryImage := strings.TrimPrefix(strings.TrimPrefix(image, "docker.io/"), "index.docker.io/")
+		// Official images are in the "library" namespace of Docker Hub
+		if !strings.Contains(repositoryImage, "/") {
+			repositoryImage = "library/" + repositoryImage
+		}
+		return domain.ImageRegistryEndpoint{URL: strings.TrimRight(registryURL, "/")}, repositoryImage, nil
+	}
+}
+
+// splitRegistryURL splits a registry such as "harbor.example.com/project" into its URL, https when no scheme is given,
+// and the path prefixing its repositories
+func splitRegistryURL(registry string) (string, string) {
+	scheme := "https://"
+	if schemeEnd := strings.Index(registry, "://"); schemeEnd >= 0 {
+		scheme = registry[:schemeEnd+3]
+		registry = registry[schemeEnd+3:]
+	}
+	host, repositoryPrefix, _ := strings.Cut(strings.Trim(registry, "/"), "/")
+	return scheme + host, repositoryPrefix
+}
+
+func joinRepository(repositoryPrefix string, image string) string {
+	if repositoryPrefix == "" {
+		return image
+	}
+	return repositoryPrefix + "/" + image
+}